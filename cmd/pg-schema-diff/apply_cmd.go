@@ -136,26 +136,43 @@ func runPlan(ctx context.Context, cmd *cobra.Command, connConfig *pgx.ConnConfig
 	}
 	defer conn.Close()
 
-	// Due to the way *sql.Db works, when a statement_timeout is set for the session, it will NOT reset
-	// by default when it's returned to the pool.
-	//
-	// We can't set the timeout at the TRANSACTION-level (for each transaction) because `ADD INDEX CONCURRENTLY`
-	// must be executed within its own transaction block. Postgres will error if you try to set a TRANSACTION-level
-	// timeout for it. SESSION-level statement_timeouts are respected by `ADD INDEX CONCURRENTLY`
-	for i, stmt := range plan.Statements {
-		cmd.Println(header(fmt.Sprintf("Executing statement %d", getDisplayableStmtIdx(i))))
-		cmd.Printf("%s\n\n", statementToPrettyS(stmt))
-		start := time.Now()
-		if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET SESSION statement_timeout = %d", stmt.Timeout.Milliseconds())); err != nil {
-			return fmt.Errorf("setting statement timeout: %w", err)
-		}
-		if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET SESSION lock_timeout = %d", stmt.Timeout.Milliseconds())); err != nil {
-			return fmt.Errorf("setting lock timeout: %w", err)
-		}
-		if _, err := conn.ExecContext(ctx, stmt.ToSQL()); err != nil {
-			return fmt.Errorf("executing migration statement. the database maybe be in a dirty state: %s: %w", stmt, err)
+	// Hooks run around the execution of the plan and each of its statements, and progress is reported before each
+	// statement. None are configurable via the CLI, but plans generated with them will run them
+	if err := plan.RunWithMigrationHooks(ctx, func(ctx context.Context, plan diff.Plan) error {
+		// Due to the way *sql.Db works, when a statement_timeout is set for the session, it will NOT reset
+		// by default when it's returned to the pool.
+		//
+		// We can't set the timeout at the TRANSACTION-level (for each transaction) because `ADD INDEX CONCURRENTLY`
+		// must be executed within its own transaction block. Postgres will error if you try to set a TRANSACTION-level
+		// timeout for it. SESSION-level statement_timeouts are respected by `ADD INDEX CONCURRENTLY`
+		for i, stmt := range plan.Statements {
+			cmd.Println(header(fmt.Sprintf("Executing statement %d", getDisplayableStmtIdx(i))))
+			cmd.Printf("%s\n\n", statementToPrettyS(stmt))
+			plan.ReportProgress(ctx, i)
+			if stmt.IsAdvisory {
+				cmd.Println("Skipping advisory statement. Consider running it after the migration completes.")
+				continue
+			}
+			start := time.Now()
+			if err := plan.RunStatementWithHooks(ctx, i, func(ctx context.Context, stmt diff.Statement) error {
+				if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET SESSION statement_timeout = %d", stmt.Timeout.Milliseconds())); err != nil {
+					return fmt.Errorf("setting statement timeout: %w", err)
+				}
+				if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET SESSION lock_timeout = %d", stmt.Timeout.Milliseconds())); err != nil {
+					return fmt.Errorf("setting lock timeout: %w", err)
+				}
+				if _, err := conn.ExecContext(ctx, stmt.ToSQL()); err != nil {
+					return fmt.Errorf("executing migration statement. the database maybe be in a dirty state: %s: %w", stmt.ToSQL(), err)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			cmd.Printf("Finished executing statement. Duration: %s\n", time.Since(start))
 		}
-		cmd.Printf("Finished executing statement. Duration: %s\n", time.Since(start))
+		return nil
+	}); err != nil {
+		return err
 	}
 	cmd.Println(header("Complete"))
 
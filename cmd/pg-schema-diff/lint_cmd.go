@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/spf13/cobra"
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+	"github.com/stripe/pg-schema-diff/pkg/log"
+	"github.com/stripe/pg-schema-diff/pkg/tempdb"
+)
+
+func buildLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Report potential problems with a schema, e.g., tables without a primary key",
+	}
+
+	schemaFlags := createSchemaSourceFlags(cmd, "")
+	tempDbConnFlags := createConnectionFlags(cmd, "temp-db-", "The temporary database to use for schema extraction. This is optional if linting a Postgres instance")
+	var includeSchemas, excludeSchemas []string
+	cmd.Flags().StringArrayVar(&includeSchemas, "include-schema", nil, "Include the specified schema in the lint")
+	cmd.Flags().StringArrayVar(&excludeSchemas, "exclude-schema", nil, "Exclude the specified schema from the lint")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		logger := log.SimpleLogger()
+
+		schemaSource, err := parseSchemaSource(*schemaFlags)
+		if err != nil {
+			return err
+		}
+
+		if !tempDbConnFlags.IsSet() {
+			if !schemaFlags.connFlags.IsSet() {
+				return fmt.Errorf("at least one Postgres server must be provided to lint a schema. either --%s or --%s must be set. Without a temporary Postgres database, pg-schema-diff cannot extract the schema from DDL", tempDbConnFlags.dsnFlagName, schemaFlags.connFlags.dsnFlagName)
+			}
+			tempDbConnFlags = schemaFlags.connFlags
+		}
+		tempDbConnConfig, err := parseConnectionFlags(tempDbConnFlags)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		issues, err := lintSchema(cmd.Context(), lintSchemaParameters{
+			schema:           schemaSource,
+			tempDbConnConfig: tempDbConnConfig,
+			opts: []diff.PlanOpt{
+				diff.WithIncludeSchemas(includeSchemas...),
+				diff.WithExcludeSchemas(excludeSchemas...),
+			},
+			logger: logger,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(issues) == 0 {
+			cmd.Println("No issues found.")
+			return nil
+		}
+		for _, issue := range issues {
+			cmd.Printf("%s: %s\n", issue.Severity, issue.Message)
+		}
+		return nil
+	}
+
+	return cmd
+}
+
+type lintSchemaParameters struct {
+	schema           schemaSourceFactory
+	tempDbConnConfig *pgx.ConnConfig
+	opts             []diff.PlanOpt
+	logger           log.Logger
+}
+
+func lintSchema(ctx context.Context, params lintSchemaParameters) ([]diff.LintIssue, error) {
+	tempDbFactory, err := tempdb.NewOnInstanceFactory(ctx, func(ctx context.Context, dbName string) (*sql.DB, error) {
+		cfg := params.tempDbConnConfig.Copy()
+		cfg.Database = dbName
+		return openDbWithPgxConfig(cfg)
+	}, tempdb.WithRootDatabase(params.tempDbConnConfig.Database))
+	if err != nil {
+		return nil, fmt.Errorf("creating temp db factory: %w", err)
+	}
+	defer func() {
+		err := tempDbFactory.Close()
+		if err != nil {
+			params.logger.Errorf("error shutting down temp db factory: %v", err)
+		}
+	}()
+
+	schemaSource, schemaSourceCloser, err := params.schema()
+	if err != nil {
+		return nil, fmt.Errorf("creating schema source: %w", err)
+	}
+	defer schemaSourceCloser.Close()
+
+	issues, err := diff.Lint(ctx, schemaSource, append(params.opts, diff.WithTempDbFactory(tempDbFactory))...)
+	if err != nil {
+		return nil, fmt.Errorf("linting schema: %w", err)
+	}
+	return issues, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+func (suite *cmdTestSuite) TestLintCmd() {
+	type testCase struct {
+		name        string
+		args        []string
+		dynamicArgs []dArgGenerator
+
+		outputContains    []string
+		expectErrContains []string
+	}
+	for _, tc := range []testCase{
+		{
+			name: "dsn",
+			dynamicArgs: []dArgGenerator{
+				tempDsnDArg(suite.pgEngine, "dsn", []string{"CREATE TABLE foobar(id INT)"}),
+			},
+			outputContains: []string{`WARNING: table "public"."foobar" has no primary key`},
+		},
+		{
+			name: "dir",
+			dynamicArgs: []dArgGenerator{
+				tempSchemaDirDArg("dir", []string{"CREATE TABLE foobar(id INT PRIMARY KEY)"}),
+				tempDsnDArg(suite.pgEngine, "temp-db-dsn", []string{""}),
+			},
+			outputContains: []string{"No issues found."},
+		},
+		{
+			name:              "dir without temp db",
+			args:              []string{"--dir", "some-dir"},
+			expectErrContains: []string{"at least one Postgres server must be provided"},
+		},
+	} {
+		suite.Run(tc.name, func() {
+			suite.runCmdWithAssertions(runCmdWithAssertionsParams{
+				args:              append([]string{"lint"}, tc.args...),
+				dynamicArgs:       tc.dynamicArgs,
+				outputContains:    tc.outputContains,
+				expectErrContains: tc.expectErrContains,
+			})
+		})
+	}
+}
@@ -111,6 +111,8 @@ type (
 	planOptionsFlags struct {
 		includeSchemas []string
 		excludeSchemas []string
+		includeObjects []string
+		excludeObjects []string
 
 		dataPackNewTables     bool
 		disablePlanValidation bool
@@ -212,6 +214,8 @@ func createPlanOptionsFlags(cmd *cobra.Command) *planOptionsFlags {
 
 	cmd.Flags().StringArrayVar(&flags.includeSchemas, "include-schema", nil, "Include the specified schema in the plan")
 	cmd.Flags().StringArrayVar(&flags.excludeSchemas, "exclude-schema", nil, "Exclude the specified schema in the plan")
+	cmd.Flags().StringArrayVar(&flags.includeObjects, "include-object", nil, "Include the objects matching the glob pattern, e.g., 'public.orders_*', in the plan")
+	cmd.Flags().StringArrayVar(&flags.excludeObjects, "exclude-object", nil, "Exclude the objects matching the glob pattern, e.g., 'public.orders_*', from the plan")
 
 	cmd.Flags().BoolVar(&flags.dataPackNewTables, "data-pack-new-tables", true, "If set, will data pack new tables in the plan to minimize table size (re-arranges columns).")
 	cmd.Flags().BoolVar(&flags.disablePlanValidation, "disable-plan-validation", false, "If set, will disable plan validation. Plan validation runs the migration against a temporary"+
@@ -307,6 +311,8 @@ func parsePlanOptions(p planOptionsFlags) (planOptions, error) {
 	opts := []diff.PlanOpt{
 		diff.WithIncludeSchemas(p.includeSchemas...),
 		diff.WithExcludeSchemas(p.excludeSchemas...),
+		diff.WithIncludeObjects(p.includeObjects...),
+		diff.WithExcludeObjects(p.excludeObjects...),
 	}
 
 	if p.dataPackNewTables {
@@ -535,6 +541,7 @@ func planToPrettyS(plan diff.Plan) string {
 
 	if len(plan.Statements) == 0 {
 		sb.WriteString("Schema matches expected. No plan generated")
+		sb.WriteString(planHazardsToPrettyS(plan))
 		return sb.String()
 	}
 
@@ -556,13 +563,27 @@ func planToPrettyS(plan diff.Plan) string {
 		stmtStrs = append(stmtStrs, stmtStr)
 	}
 	sb.WriteString(strings.Join(stmtStrs, "\n\n"))
+	sb.WriteString(planHazardsToPrettyS(plan))
 
 	return sb.String()
 }
 
+// planHazardsToPrettyS renders the hazards of the plan that do not belong to any statement, e.g., ignored changes
+// to column order
+func planHazardsToPrettyS(plan diff.Plan) string {
+	sb := strings.Builder{}
+	for _, hazard := range plan.Hazards {
+		sb.WriteString(fmt.Sprintf("\n-- Plan Hazard %s", hazardToPrettyS(hazard)))
+	}
+	return sb.String()
+}
+
 func statementToPrettyS(stmt diff.Statement) string {
 	sb := strings.Builder{}
 	sb.WriteString(fmt.Sprintf("%s;", stmt.DDL))
+	if stmt.IsAdvisory {
+		sb.WriteString("\n\t-- Advisory: This statement is not executed as part of the migration")
+	}
 	sb.WriteString(fmt.Sprintf("\n\t-- Statement Timeout: %s", stmt.Timeout))
 	if stmt.LockTimeout > 0 && stmt.LockTimeout < stmt.Timeout {
 		// If LockTimeout is 0, it's effectively not set. If it's >= to Timeout, it's redundant to print
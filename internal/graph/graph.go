@@ -12,6 +12,20 @@ type Vertex interface {
 
 type AdjacencyMatrix map[string]map[string]bool
 
+// CycleError is returned when a graph cannot be topologically sorted because it contains a cycle
+type CycleError struct {
+	// Cycle contains the ids of the vertices in the cycle. Each vertex has an edge to the next vertex, and the last
+	// vertex has an edge to the first vertex
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	if len(e.Cycle) == 0 {
+		return "cycle detected"
+	}
+	return fmt.Sprintf("cycle detected: %s -> %s", strings.Join(e.Cycle, " -> "), e.Cycle[0])
+}
+
 // Graph is a directed graph
 type Graph[V Vertex] struct {
 	verticesById map[string]V
@@ -85,6 +99,19 @@ func (g *Graph[V]) HasVertexWithId(id string) bool {
 	return hasVertex
 }
 
+// GetAdjacentVertexIds returns the ids of the vertices the vertex has an edge to. The ids are sorted, such that the
+// output is deterministic
+func (g *Graph[V]) GetAdjacentVertexIds(id string) []string {
+	var adjacentIds []string
+	for target, isAdjacent := range g.edges[id] {
+		if isAdjacent {
+			adjacentIds = append(adjacentIds, target)
+		}
+	}
+	sort.Strings(adjacentIds)
+	return adjacentIds
+}
+
 // Reverse reverses the edges of the map. The sources become the sinks and vice versa.
 func (g *Graph[V]) Reverse() {
 	reversedEdges := make(AdjacencyMatrix)
@@ -191,12 +218,8 @@ func (g *Graph[V]) TopologicallySortWithPriority(isLowerPriority func(V, V) bool
 			}
 		}
 		if indexOfSourceWithHighestPri == -1 {
-			dotSB := strings.Builder{}
-			if err := EncodeDOT(g, &dotSB, true); err != nil {
-				dotSB.Reset()
-				dotSB.WriteString(fmt.Sprintf("failed to encode graph to DOT: %v", err))
-			}
-			return nil, fmt.Errorf("cycle detected: %+v, %+v\n%s", graph, incomingEdgeCountByVertex, dotSB.String())
+			// Every remaining vertex has an incoming edge from another remaining vertex, so there must be a cycle
+			return nil, &CycleError{Cycle: graph.findCycle()}
 		}
 		sourceWithHighestPriority := sources[indexOfSourceWithHighestPri]
 
@@ -219,3 +242,60 @@ func (g *Graph[V]) TopologicallySortWithPriority(isLowerPriority func(V, V) bool
 
 	return output, nil
 }
+
+// findCycle finds a cycle in a graph where every vertex has an incoming edge, e.g., the vertices left over after removing
+// all the sources during a topological sort. The output is deterministic.
+func (g *Graph[V]) findCycle() []string {
+	predecessorIdsById := make(map[string][]string)
+	for source, adjacentEdgesMap := range g.edges {
+		for target, isAdjacent := range adjacentEdgesMap {
+			if isAdjacent {
+				predecessorIdsById[target] = append(predecessorIdsById[target], source)
+			}
+		}
+	}
+	var vertexIds []string
+	for id := range g.verticesById {
+		vertexIds = append(vertexIds, id)
+		sort.Strings(predecessorIdsById[id])
+	}
+	if len(vertexIds) == 0 {
+		return nil
+	}
+	sort.Strings(vertexIds)
+
+	// Walk backwards from any vertex. Since every vertex has a predecessor, the walk must eventually revisit a vertex
+	var path []string
+	pathIdxById := make(map[string]int)
+	currId := vertexIds[0]
+	for {
+		if idx, ok := pathIdxById[currId]; ok {
+			path = path[idx:]
+			break
+		}
+		pathIdxById[currId] = len(path)
+		path = append(path, currId)
+		predecessorIds := predecessorIdsById[currId]
+		if len(predecessorIds) == 0 {
+			// This should never happen if every vertex has an incoming edge
+			return nil
+		}
+		currId = predecessorIds[0]
+	}
+
+	// The path was walked backwards. Reverse it such that each vertex has an edge to the next vertex, and rotate it
+	// such that it starts with the lowest id
+	cycle := make([]string, len(path))
+	lowestIdx := 0
+	for i, id := range path {
+		cycle[len(path)-1-i] = id
+	}
+	for i, id := range cycle {
+		if id < cycle[lowestIdx] {
+			lowestIdx = i
+		}
+	}
+	var rotatedCycle []string
+	rotatedCycle = append(rotatedCycle, cycle[lowestIdx:]...)
+	return append(rotatedCycle, cycle[:lowestIdx]...)
+}
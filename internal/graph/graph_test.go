@@ -78,6 +78,19 @@ func TestAddGetHasOperations(t *testing.T) {
 	}, g.edges)
 }
 
+func TestGetAdjacentVertexIds(t *testing.T) {
+	g := NewGraph[vertex]()
+	for _, id := range []string{"v_1", "v_2", "v_3"} {
+		g.AddVertex(NewV(id))
+	}
+	assert.NoError(t, g.AddEdge("v_1", "v_3"))
+	assert.NoError(t, g.AddEdge("v_1", "v_2"))
+
+	assert.Equal(t, []string{"v_2", "v_3"}, g.GetAdjacentVertexIds("v_1"))
+	assert.Empty(t, g.GetAdjacentVertexIds("v_2"))
+	assert.Empty(t, g.GetAdjacentVertexIds("missing_vertex"))
+}
+
 func TestReverse(t *testing.T) {
 	g := NewGraph[vertex]()
 	g.AddVertex(NewV("v_1"))
@@ -314,7 +327,57 @@ func TestTopologicallySort(t *testing.T) {
 	// Cycle should error
 	assert.NoError(t, g.AddEdge("10", "07"))
 	_, err = g.TopologicallySort()
-	assert.Error(t, err)
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	assert.Equal(t, []string{"07", "11", "10"}, cycleErr.Cycle)
+	assert.EqualError(t, err, "cycle detected: 07 -> 11 -> 10 -> 07")
+}
+
+func TestTopologicallySortReturnsCycle(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		adjList       map[string][]string
+		expectedCycle []string
+	}{
+		{
+			name: "self loop",
+			adjList: map[string][]string{
+				"v_0": {"v_1"},
+				"v_1": {"v_1"},
+			},
+			expectedCycle: []string{"v_1"},
+		},
+		{
+			name: "cycle with vertices before and after it",
+			adjList: map[string][]string{
+				"v_0": {"v_3"},
+				"v_1": {"v_4"},
+				"v_2": {"v_5"},
+				"v_3": {"v_2"},
+				"v_4": {"v_3"},
+				"v_5": {"v_4", "v_6"},
+				"v_6": {},
+			},
+			expectedCycle: []string{"v_2", "v_5", "v_4", "v_3"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGraph[vertex]()
+			for id := range tc.adjList {
+				g.AddVertex(NewV(id))
+			}
+			for id, neighbors := range tc.adjList {
+				for _, neighborId := range neighbors {
+					require.NoError(t, g.AddEdge(id, neighborId))
+				}
+			}
+
+			_, err := g.TopologicallySort()
+			var cycleErr *CycleError
+			require.ErrorAs(t, err, &cycleErr)
+			assert.Equal(t, tc.expectedCycle, cycleErr.Cycle)
+		})
+	}
 }
 
 func TestTopologicallySortWithPriority(t *testing.T) {
@@ -0,0 +1,44 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+type Severity string
+
+const (
+	SeverityWarning Severity = "WARNING"
+)
+
+// Issue is a potential problem with a schema. Issues do not prevent a migration from being planned or executed.
+type Issue struct {
+	Severity Severity
+	// ObjectName is the name of the schema object the issue applies to
+	ObjectName string
+	Message    string
+}
+
+// LintSchema returns the lint issues found in the schema. The issues are deterministically ordered.
+func LintSchema(s schema.Schema) []Issue {
+	s = s.Normalize()
+
+	var issues []Issue
+	for _, table := range s.Tables {
+		// Partitioned tables cannot always have a primary key, since the primary key must include the partition key
+		// columns
+		if table.IsPartitioned() || table.IsPartition() {
+			continue
+		}
+		if !table.HasPrimaryKey() {
+			issues = append(issues, Issue{
+				Severity:   SeverityWarning,
+				ObjectName: table.GetName(),
+				Message: fmt.Sprintf("table %s has no primary key. Tables without primary keys can cause problems "+
+					"for logical replication, ORMs, and some backup tools.", table.GetName()),
+			})
+		}
+	}
+	return issues
+}
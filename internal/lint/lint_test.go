@@ -0,0 +1,95 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stripe/pg-schema-diff/internal/lint"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestLintSchema(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		schema         schema.Schema
+		expectedIssues []lint.Issue
+	}{
+		{
+			name: "table with primary key",
+			schema: schema.Schema{
+				Tables: []schema.Table{
+					{
+						SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+						Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+					},
+				},
+				Indexes: []schema.Index{
+					{
+						Name:        "foo_pkey",
+						OwningTable: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+						Columns:     []string{"id"},
+						Constraint:  &schema.IndexConstraint{Type: schema.PkIndexConstraintType, EscapedConstraintName: "\"foo_pkey\""},
+					},
+				},
+			},
+		},
+		{
+			name: "table with a primary key column",
+			schema: schema.Schema{
+				Tables: []schema.Table{
+					{
+						SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+						Columns:             []schema.Column{{Name: "id", Type: "integer", IsPrimaryKey: true}},
+					},
+				},
+			},
+		},
+		{
+			name: "table with only a unique index",
+			schema: schema.Schema{
+				Tables: []schema.Table{
+					{SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}},
+				},
+				Indexes: []schema.Index{
+					{
+						Name:        "foo_unique",
+						OwningTable: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+						IsUnique:    true,
+					},
+					{
+						Name:        "bar_pkey",
+						OwningTable: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"bar\""},
+						Constraint:  &schema.IndexConstraint{Type: schema.PkIndexConstraintType, EscapedConstraintName: "\"bar_pkey\""},
+					},
+				},
+			},
+			expectedIssues: []lint.Issue{
+				{
+					Severity:   lint.SeverityWarning,
+					ObjectName: "\"public\".\"foo\"",
+					Message:    "table \"public\".\"foo\" has no primary key. Tables without primary keys can cause problems for logical replication, ORMs, and some backup tools.",
+				},
+			},
+		},
+		{
+			name: "partitioned tables are ignored",
+			schema: schema.Schema{
+				Tables: []schema.Table{
+					{
+						SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+						PartitionKeyDef:     "LIST (author)",
+					},
+					{
+						SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_1\""},
+						ParentTable:         &schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+						ForValues:           "FOR VALUES IN ('some author')",
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedIssues, lint.LintSchema(tt.schema))
+		})
+	}
+}
@@ -33,7 +33,9 @@ type (
 		planOpts []diff.PlanOpt
 
 		// roles is a list of roles that should be created before the DDL is applied
-		roles        []string
+		roles []string
+		// tablespaces is a list of tablespaces that should be created before the DDL is applied
+		tablespaces  []string
 		oldSchemaDDL []string
 		newSchemaDDL []string
 
@@ -114,8 +116,13 @@ func (suite *acceptanceTestSuite) runTest(tc acceptanceTestCase) {
 		_, err := rootDb.Exec(fmt.Sprintf("CREATE ROLE %s", r))
 		suite.Require().NoError(err)
 	}
+	// Create tablespaces since they are also global. Each tablespace needs its own empty directory
+	for _, ts := range tc.tablespaces {
+		_, err := rootDb.Exec(fmt.Sprintf("CREATE TABLESPACE %s LOCATION '%s'", ts, suite.T().TempDir()))
+		suite.Require().NoError(err)
+	}
 	defer func() {
-		// This will drop the roles (and attempt to reset other cluster-level state)
+		// This will drop the roles and tablespaces (and attempt to reset other cluster-level state)
 		suite.Require().NoError(pgengine.ResetInstance(context.Background(), rootDb))
 	}()
 
@@ -158,7 +165,7 @@ func (suite *acceptanceTestSuite) runTest(tc acceptanceTestCase) {
 		// It shouldn't be necessary, but we'll run all checks below this point just in case rather than exiting early
 		suite.Empty(plan.Statements)
 	}
-	suite.ElementsMatch(tc.expectedHazardTypes, getUniqueHazardTypesFromStatements(plan.Statements), prettySprintPlan(plan))
+	suite.ElementsMatch(tc.expectedHazardTypes, getUniqueHazardTypes(plan), prettySprintPlan(plan))
 
 	// Apply the plan
 	suite.Require().NoError(applyPlan(oldDb, plan), prettySprintPlan(plan))
@@ -227,20 +234,26 @@ func applyDDL(db *pgengine.DB, ddl []string) error {
 func applyPlan(db *pgengine.DB, plan diff.Plan) error {
 	var ddl []string
 	for _, stmt := range plan.Statements {
+		if stmt.IsAdvisory {
+			continue
+		}
 		ddl = append(ddl, stmt.ToSQL())
 	}
 	return applyDDL(db, ddl)
 }
 
-func getUniqueHazardTypesFromStatements(statements []diff.Statement) []diff.MigrationHazardType {
+// getUniqueHazardTypes returns the hazard types of the plan's statements and of the plan itself
+func getUniqueHazardTypes(plan diff.Plan) []diff.MigrationHazardType {
 	var seenHazardTypes = make(map[diff.MigrationHazardType]bool)
 	var hazardTypes []diff.MigrationHazardType
-	for _, stmt := range statements {
-		for _, hazard := range stmt.Hazards {
-			if _, hasHazard := seenHazardTypes[hazard.Type]; !hasHazard {
-				seenHazardTypes[hazard.Type] = true
-				hazardTypes = append(hazardTypes, hazard.Type)
-			}
+	hazards := append([]diff.MigrationHazard(nil), plan.Hazards...)
+	for _, stmt := range plan.Statements {
+		hazards = append(hazards, stmt.Hazards...)
+	}
+	for _, hazard := range hazards {
+		if _, hasHazard := seenHazardTypes[hazard.Type]; !hasHazard {
+			seenHazardTypes[hazard.Type] = true
+			hazardTypes = append(hazardTypes, hazard.Type)
 		}
 	}
 	return hazardTypes
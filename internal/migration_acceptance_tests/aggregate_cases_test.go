@@ -0,0 +1,138 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var aggregateAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "No-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION add_state(state NUMERIC, val NUMERIC) RETURNS NUMERIC AS $$
+                SELECT state + val
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE AGGREGATE sum_custom(NUMERIC) (SFUNC = add_state, STYPE = NUMERIC, INITCOND = '0');
+            CREATE AGGREGATE count_custom(*) (SFUNC = int8inc, STYPE = BIGINT, INITCOND = '0');
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION add_state(state NUMERIC, val NUMERIC) RETURNS NUMERIC AS $$
+                SELECT state + val
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE AGGREGATE sum_custom(NUMERIC) (SFUNC = add_state, STYPE = NUMERIC, INITCOND = '0');
+            CREATE AGGREGATE count_custom(*) (SFUNC = int8inc, STYPE = BIGINT, INITCOND = '0');
+			`,
+		},
+
+		expectEmptyPlan: true,
+	},
+	{
+		name: "Create aggregate and the functions it depends on",
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE FUNCTION schema_1.add_state(state NUMERIC[], val NUMERIC) RETURNS NUMERIC[] AS $$
+                SELECT ARRAY[state[1] + val, state[2] + 1]
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION schema_1.final_avg(state NUMERIC[]) RETURNS NUMERIC AS $$
+                SELECT CASE WHEN state[2] = 0 THEN NULL ELSE state[1] / state[2] END
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION schema_1.combine_state(a NUMERIC[], b NUMERIC[]) RETURNS NUMERIC[] AS $$
+                SELECT ARRAY[a[1] + b[1], a[2] + b[2]]
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE AGGREGATE schema_1.avg_custom(NUMERIC) (
+                SFUNC = schema_1.add_state,
+                STYPE = NUMERIC[],
+                FINALFUNC = schema_1.final_avg,
+                COMBINEFUNC = schema_1.combine_state,
+                INITCOND = '{0, 0}',
+                PARALLEL = SAFE
+            );
+			`,
+		},
+	},
+	{
+		name: "Create aggregate without arguments",
+		newSchemaDDL: []string{
+			`
+            CREATE AGGREGATE count_custom(*) (SFUNC = int8inc, STYPE = BIGINT, INITCOND = '0');
+			`,
+		},
+	},
+	{
+		name: "Alter aggregate",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION add_state(state NUMERIC, val NUMERIC) RETURNS NUMERIC AS $$
+                SELECT state + val
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE AGGREGATE sum_custom(NUMERIC) (SFUNC = add_state, STYPE = NUMERIC, INITCOND = '0');
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION add_state(state NUMERIC, val NUMERIC) RETURNS NUMERIC AS $$
+                SELECT state + val
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE AGGREGATE sum_custom(NUMERIC) (SFUNC = add_state, STYPE = NUMERIC, INITCOND = '100');
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"DROP AGGREGATE \"public\".\"sum_custom\"(numeric) CASCADE",
+			"CREATE AGGREGATE \"public\".\"sum_custom\"(numeric) (\n\tSFUNC = \"public\".\"add_state\",\n\tSTYPE = numeric,\n\tINITCOND = '100'\n)",
+		},
+	},
+	{
+		name: "Switch aggregate to a new state function",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION add_state(state NUMERIC, val NUMERIC) RETURNS NUMERIC AS $$
+                SELECT state + val
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE AGGREGATE sum_custom(NUMERIC) (SFUNC = add_state, STYPE = NUMERIC);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION add_abs_state(state NUMERIC, val NUMERIC) RETURNS NUMERIC AS $$
+                SELECT COALESCE(state, 0) + abs(val)
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE AGGREGATE sum_custom(NUMERIC) (SFUNC = add_abs_state, STYPE = NUMERIC);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "Drop aggregate and the function it depends on",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION add_state(state NUMERIC, val NUMERIC) RETURNS NUMERIC AS $$
+                SELECT state + val
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE AGGREGATE sum_custom(NUMERIC) (SFUNC = add_state, STYPE = NUMERIC, INITCOND = '0');
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestAggregateTestCases() {
+	suite.runTestCases(aggregateAcceptanceTestCases)
+}
@@ -85,6 +85,7 @@ var backCompatAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 
 		expectedDBSchemaDDL: []string{`
@@ -27,6 +27,26 @@ var checkConstraintCases = []acceptanceTestCase{
 		},
 		expectEmptyPlan: true,
 	},
+	{
+		name: "No-op (expression written differently than how Postgres normalizes it)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                email TEXT CHECK (email != '')
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                email TEXT CHECK ((email <> ''::text))
+            );
+			`,
+		},
+		expectEmptyPlan: true,
+	},
 	{
 		name: "Add check constraint (validate constraint added online)",
 		oldSchemaDDL: []string{
@@ -47,6 +67,9 @@ var checkConstraintCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"foobar_check\" CHECK((bar > id)) NOT VALID",
 			"ALTER TABLE \"public\".\"foobar\" VALIDATE CONSTRAINT \"foobar_check\"",
@@ -101,6 +124,9 @@ var checkConstraintCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add multiple check constraints",
@@ -123,6 +149,9 @@ var checkConstraintCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add check constraints to new column",
@@ -143,6 +172,9 @@ var checkConstraintCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add check constraint and change data type",
@@ -165,6 +197,7 @@ var checkConstraintCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -188,6 +221,9 @@ var checkConstraintCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT "BAR_CHECK" CHECK ( "Bar" < "ID" );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add no inherit check constraint",
@@ -210,6 +246,9 @@ var checkConstraintCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT bar_check CHECK ( bar > id ) NO INHERIT;
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add No-Inherit, Not-Valid check constraint",
@@ -395,6 +434,9 @@ var checkConstraintCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT bar_check CHECK ( bar > id );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"foobar\" VALIDATE CONSTRAINT \"bar_check\"",
 		},
@@ -488,6 +530,9 @@ var checkConstraintCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Alter check constraint with UDF dependency should error",
@@ -548,6 +593,93 @@ var checkConstraintCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT some_constraint CHECK ( to_timestamp(id) <= CURRENT_TIMESTAMP );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
+	},
+	{
+		name: "Add check constraint on table under the NOT VALID row threshold (added in one statement)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                bar BIGINT
+            );
+            INSERT INTO foobar SELECT i, i + 1 FROM generate_series(1, 10) AS i;
+            ANALYZE foobar;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                bar BIGINT CHECK ( bar > id )
+            );
+			`,
+		},
+		planOpts: []diff.PlanOpt{diff.WithAddConstraintsNotValidRowThreshold(1000)},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"foobar_check\" CHECK((bar > id))",
+		},
+	},
+	{
+		name: "Add check constraint on table over the NOT VALID row threshold (validation is long running)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                bar BIGINT
+            );
+            INSERT INTO foobar SELECT i, i + 1 FROM generate_series(1, 2000) AS i;
+            ANALYZE foobar;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                bar BIGINT CHECK ( bar > id )
+            );
+			`,
+		},
+		planOpts: []diff.PlanOpt{diff.WithAddConstraintsNotValidRowThreshold(1000)},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeLongRunning,
+			diff.MigrationHazardTypeValidateConstraint,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"foobar_check\" CHECK((bar > id)) NOT VALID",
+			"ALTER TABLE \"public\".\"foobar\" VALIDATE CONSTRAINT \"foobar_check\"",
+		},
+	},
+	{
+		name: "Add check constraint with NOT VALID disabled",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                bar BIGINT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                bar BIGINT CHECK ( bar > id )
+            );
+			`,
+		},
+		planOpts: []diff.PlanOpt{diff.WithAddConstraintsNotValid(false)},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"foobar_check\" CHECK((bar > id))",
+		},
 	},
 }
 
@@ -0,0 +1,104 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var collationAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "no-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE COLLATION case_insensitive (provider = icu, locale = 'und-u-ks-level2', deterministic = false);
+            CREATE COLLATION c_collation (provider = libc, locale = 'C');
+            CREATE TABLE foo(
+                name TEXT COLLATE case_insensitive
+            );
+            CREATE INDEX foo_name_idx ON foo(name COLLATE c_collation);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE COLLATION case_insensitive (provider = icu, locale = 'und-u-ks-level2', deterministic = false);
+            CREATE COLLATION c_collation (provider = libc, locale = 'C');
+            CREATE TABLE foo(
+                name TEXT COLLATE case_insensitive
+            );
+            CREATE INDEX foo_name_idx ON foo(name COLLATE c_collation);
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name: "create collations used by a domain, table, and index",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE bar();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE COLLATION schema_1.case_insensitive (provider = icu, locale = 'und-u-ks-level2', deterministic = false);
+            CREATE COLLATION schema_1.c_collation (provider = libc, lc_collate = 'C', lc_ctype = 'C');
+            CREATE DOMAIN schema_1.ci_text AS TEXT COLLATE schema_1.case_insensitive;
+            CREATE TABLE foo(
+                name TEXT COLLATE schema_1.case_insensitive,
+                email schema_1.ci_text
+            );
+            CREATE INDEX foo_name_idx ON foo(name COLLATE schema_1.c_collation);
+            CREATE TABLE bar();
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "drop collations used by a table and index",
+		oldSchemaDDL: []string{
+			`
+            CREATE COLLATION case_insensitive (provider = icu, locale = 'und-u-ks-level2', deterministic = false);
+            CREATE COLLATION c_collation (provider = libc, locale = 'C');
+            CREATE TABLE foo(
+                name TEXT COLLATE case_insensitive
+            );
+            CREATE INDEX foo_name_idx ON foo(name COLLATE c_collation);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                name TEXT
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+	{
+		name: "re-create an unused collation",
+		oldSchemaDDL: []string{
+			`
+            CREATE COLLATION case_insensitive (provider = icu, locale = 'und-u-ks-level2', deterministic = false);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE COLLATION case_insensitive (provider = icu, locale = 'und-u-ks-level1', deterministic = false);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"DROP COLLATION \"public\".\"case_insensitive\"",
+			"CREATE COLLATION \"public\".\"case_insensitive\" (PROVIDER = icu, LOCALE = 'und-u-ks-level1', DETERMINISTIC = false)",
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestCollationTestCases() {
+	suite.runTestCases(collationAcceptanceTestCases)
+}
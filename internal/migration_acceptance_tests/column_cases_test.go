@@ -101,6 +101,79 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+	},
+	{
+		name: "Add column with volatile default",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                some_random DOUBLE PRECISION DEFAULT random()
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" ADD COLUMN \"some_random\" double precision DEFAULT random()",
+			"VACUUM FREEZE \"public\".\"foobar\"",
+			"ANALYZE \"public\".\"foobar\"",
+		},
+	},
+	{
+		name: "Add columns with stable and constant defaults",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                created_at TIMESTAMPTZ DEFAULT NOW(),
+                updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+                version INT NOT NULL DEFAULT 1
+            );
+			`,
+		},
+	},
+	{
+		name: "Set volatile default on existing column",
+		oldSchemaDDL: []string{
+			`
+            CREATE SEQUENCE foobar_seq;
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                counter BIGINT DEFAULT 0
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SEQUENCE foobar_seq;
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                counter BIGINT DEFAULT nextval('foobar_seq')
+            );
+			`,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"counter\" SET DEFAULT nextval('foobar_seq'::regclass)",
+		},
 	},
 	{
 		name: "Add one column with all options",
@@ -145,6 +218,65 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
                     )
 				`},
 	},
+	{
+		name: "Add one column and swap the order of existing columns",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                foo TEXT,
+                id INT PRIMARY KEY,
+                bar INT
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeColumnOrderChange,
+		},
+		expectedDBSchemaDDL: []string{`
+                    CREATE TABLE foobar(
+                        id INT PRIMARY KEY,
+                        foo TEXT,
+                        bar INT
+                    )
+				`},
+	},
+	{
+		name: "Swap the order of existing columns",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                foo TEXT,
+                id INT PRIMARY KEY
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeColumnOrderChange,
+		},
+		expectedDBSchemaDDL: []string{`
+                    CREATE TABLE foobar(
+                        id INT PRIMARY KEY,
+                        foo TEXT
+                    )
+				`},
+		expectEmptyPlan: true,
+	},
 	{
 		name: "Add identity column - always no cycle",
 		oldSchemaDDL: []string{
@@ -199,6 +331,7 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexDropped,
 		},
 	},
@@ -220,6 +353,7 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexDropped,
 		},
 	},
@@ -241,6 +375,7 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexDropped,
 		},
 	},
@@ -327,6 +462,7 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"Foobar\" ALTER COLUMN \"some_time_col\" SET DATA TYPE timestamp without time zone using to_timestamp(\"some_time_col\" / 1000)",
 			"ANALYZE \"public\".\"Foobar\" (\"some_time_col\")",
+			"ANALYZE \"public\".\"Foobar\"",
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
@@ -423,6 +559,7 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"foobar\" SET DATA TYPE character varying(255) COLLATE \"pg_catalog\".\"POSIX\" using \"foobar\"::character varying(255)",
 			"ANALYZE \"public\".\"foobar\" (\"foobar\")",
+			"ANALYZE \"public\".\"foobar\"",
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
@@ -527,6 +664,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 		expectedPlanDDL: []string{"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"pgschemadiff_tmpnn_EBESExQVRheYGRobHB0eHw\" CHECK(\"foobar\" IS NOT NULL) NOT VALID", "ALTER TABLE \"public\".\"foobar\" VALIDATE CONSTRAINT \"pgschemadiff_tmpnn_EBESExQVRheYGRobHB0eHw\"", "ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"foobar\" SET NOT NULL", "ALTER TABLE \"public\".\"foobar\" DROP CONSTRAINT \"pgschemadiff_tmpnn_EBESExQVRheYGRobHB0eHw\""},
 	},
 	{
@@ -548,6 +688,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT foobar CHECK (foobar IS NOT NULL) NOT VALID;
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"pgschemadiff_tmpnn_EBESExQVRheYGRobHB0eHw\" CHECK(\"foobar\" IS NOT NULL) NOT VALID",
 			"ALTER TABLE \"public\".\"foobar\" VALIDATE CONSTRAINT \"pgschemadiff_tmpnn_EBESExQVRheYGRobHB0eHw\"",
@@ -577,6 +720,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT foobar CHECK (foobar IS NOT NULL) NOT VALID;
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"pgschemadiff_tmpnn_EBESExQVRheYGRobHB0eHw\" CHECK(\"foobar\" IS NOT NULL) NOT VALID",
 			"ALTER TABLE \"public\".\"foobar\" VALIDATE CONSTRAINT \"pgschemadiff_tmpnn_EBESExQVRheYGRobHB0eHw\"",
@@ -604,6 +750,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT foobar CHECK (foobar IS NOT NULL);
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"foobar\" VALIDATE CONSTRAINT \"foobar\"",
 			"ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"foobar\" SET NOT NULL",
@@ -628,6 +777,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT foobar CHECK (foobar IS NOT NULL);
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"foobar\" CHECK((foobar IS NOT NULL)) NOT VALID",
 			"ALTER TABLE \"public\".\"foobar\" VALIDATE CONSTRAINT \"foobar\"",
@@ -702,6 +854,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT foobar CHECK (LENGTH(foobar) > 0);
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"foobar\" SET NOT NULL",
 			"ALTER TABLE \"public\".\"foobar\" DROP CONSTRAINT \"foobar\"",
@@ -730,6 +885,7 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"pgschemadiff_tmpnn_EBESExQVRheYGRobHB0eHw\" CHECK(\"foobar\" IS NOT NULL) NOT VALID",
@@ -740,6 +896,7 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
 			"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"foobar_foobar_check\" CHECK((foobar > 0)) NOT VALID",
 			"ALTER TABLE \"public\".\"foobar\" VALIDATE CONSTRAINT \"foobar_foobar_check\"",
 			"ALTER TABLE \"public\".\"foobar\" DROP CONSTRAINT \"pgschemadiff_tmpnn_EBESExQVRheYGRobHB0eHw\"",
+			"ANALYZE \"public\".\"foobar\"",
 		},
 	},
 	{
@@ -849,6 +1006,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Change from NOT NULL to no NULL default",
@@ -913,6 +1073,7 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -936,6 +1097,7 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -959,6 +1121,7 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -977,6 +1140,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
 	},
 	{
 		name: "Add identity to column",
@@ -994,6 +1160,10 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeCorrectness,
+		},
 	},
 	{
 		name: "Add identity to column with existing default",
@@ -1011,6 +1181,10 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeCorrectness,
+		},
 	},
 	{
 		name: "Alter identity type - to by default",
@@ -1028,6 +1202,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
 	},
 	{
 		name: "Alter identity type - to always",
@@ -1045,6 +1222,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
 	},
 	{
 		name: "Alter identity minvalue",
@@ -1062,6 +1242,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
 	},
 	{
 		name: "Alter identity maxvalue",
@@ -1079,6 +1262,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
 	},
 	{
 		name: "Alter identity start",
@@ -1096,6 +1282,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
 	},
 	{
 		name: "Alter identity increment",
@@ -1113,6 +1302,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
 	},
 	{
 		name: "Alter identity cache",
@@ -1130,6 +1322,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
 	},
 	{
 		name: "Alter identity cycle - to cycle",
@@ -1147,6 +1342,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
 	},
 	{
 		name: "Alter identity cycle - to no cycle",
@@ -1164,6 +1362,9 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
 	},
 	{
 		name: "Alter all identity properties (from always to default, from no cycle to cycle)",
@@ -1181,6 +1382,255 @@ var columnAcceptanceTestCases = []acceptanceTestCase{
             );
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+	},
+	{
+		name: "Add column with storage",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            );
+            ALTER TABLE foobar ALTER COLUMN content SET STORAGE EXTERNAL;
+			`,
+		},
+	},
+	{
+		name: "Alter column storage to disable compression",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            );
+            ALTER TABLE foobar ALTER COLUMN content SET STORAGE EXTERNAL;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"content\" SET STORAGE EXTERNAL",
+		},
+	},
+	{
+		name: "Alter column storage back to default",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            );
+            ALTER TABLE foobar ALTER COLUMN content SET STORAGE EXTERNAL;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            );
+			`,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"content\" SET STORAGE EXTENDED",
+		},
+	},
+	{
+		name: "Alter column type with storage",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content VARCHAR(255)
+            );
+            ALTER TABLE foobar ALTER COLUMN content SET STORAGE MAIN;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            );
+            ALTER TABLE foobar ALTER COLUMN content SET STORAGE MAIN;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "Create table with generated column",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL,
+                price_with_tax NUMERIC GENERATED ALWAYS AS (price * 1.1) STORED
+            );
+			`,
+		},
+	},
+	{
+		name: "Add generated column",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL,
+                price_with_tax NUMERIC NOT NULL GENERATED ALWAYS AS (price * 1.1) STORED
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"products\" ADD COLUMN \"price_with_tax\" numeric NOT NULL GENERATED ALWAYS AS ((price * 1.1)) STORED",
+			"VACUUM FREEZE \"public\".\"products\"",
+			"ANALYZE \"public\".\"products\"",
+		},
+	},
+	{
+		name: "Drop generated column",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL,
+                price_with_tax NUMERIC GENERATED ALWAYS AS (price * 1.1) STORED
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+	{
+		name: "Change generation expression",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL,
+                price_with_tax NUMERIC GENERATED ALWAYS AS (price * 1.1) STORED
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL,
+                price_with_tax NUMERIC GENERATED ALWAYS AS (price * 1.2) STORED
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"products\" DROP COLUMN \"price_with_tax\"",
+			"ALTER TABLE \"public\".\"products\" ADD COLUMN \"price_with_tax\" numeric GENERATED ALWAYS AS ((price * 1.2)) STORED",
+			"VACUUM FREEZE \"public\".\"products\"",
+			"ANALYZE \"public\".\"products\"",
+		},
+	},
+	{
+		name: "Change regular column to generated column",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL,
+                price_with_tax NUMERIC
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL,
+                price_with_tax NUMERIC GENERATED ALWAYS AS (price * 1.1) STORED
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+	},
+	{
+		name: "Change generated column to regular column",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL,
+                price_with_tax NUMERIC GENERATED ALWAYS AS (price * 1.1) STORED
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE products(
+                id INT PRIMARY KEY,
+                price NUMERIC NOT NULL,
+                price_with_tax NUMERIC
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
 	},
 }
 
@@ -0,0 +1,143 @@
+package migration_acceptance_tests
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+	"github.com/stripe/pg-schema-diff/pkg/sqldb"
+	"github.com/stripe/pg-schema-diff/pkg/tempdb"
+)
+
+// confirmAllColumnRenamesPlan generates a plan with column rename detection and confirms every column rename candidate
+func confirmAllColumnRenamesPlan(ctx context.Context, connPool sqldb.Queryable, tempDbFactory tempdb.Factory, newSchemaDDL []string, opts ...diff.PlanOpt) (diff.Plan, error) {
+	plan, err := diff.Generate(ctx, diff.DBSchemaSource(connPool), diff.DDLSchemaSource(newSchemaDDL),
+		append(opts, diff.WithTempDbFactory(tempDbFactory), diff.WithDetectColumnRenames())...)
+	if err != nil {
+		return diff.Plan{}, err
+	}
+	for len(plan.ColumnRenameCandidates) > 0 {
+		candidate := plan.ColumnRenameCandidates[0]
+		plan, err = plan.ConfirmColumnRename(candidate.Table, candidate.Old, candidate.New)
+		if err != nil {
+			return diff.Plan{}, fmt.Errorf("confirming column rename: %w", err)
+		}
+	}
+	return plan, nil
+}
+
+var columnRenameAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "Rename column with index, check constraint, and foreign key",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                val TEXT,
+                CONSTRAINT foobar_pkey PRIMARY KEY (id),
+                CONSTRAINT val_not_empty CHECK (val <> '')
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val) WHERE val IS NOT NULL;
+
+            CREATE TABLE fizz(
+                foobar_id INT REFERENCES foobar(id)
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                "Renamed Id" INT,
+                val TEXT,
+                CONSTRAINT foobar_pkey PRIMARY KEY ("Renamed Id"),
+                CONSTRAINT val_not_empty CHECK (val <> '')
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val) WHERE val IS NOT NULL;
+
+            CREATE TABLE fizz(
+                foobar_id INT,
+                CONSTRAINT fizz_foobar_id_fkey FOREIGN KEY (foobar_id) REFERENCES foobar("Renamed Id")
+            );
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithRenamedColumn("public", "foobar", "id", "Renamed Id"),
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" RENAME COLUMN \"id\" TO \"Renamed Id\"",
+		},
+	},
+	{
+		name: "Rename detected column with dependent view",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val);
+            CREATE VIEW foobar_view AS SELECT id, val FROM foobar;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                renamed_val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON foobar(renamed_val);
+            CREATE VIEW foobar_view AS SELECT id, renamed_val FROM foobar;
+			`,
+		},
+		planFactory: confirmAllColumnRenamesPlan,
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+	{
+		name: "Rename detected column referenced in function body",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                val INT
+            );
+
+            CREATE FUNCTION sum_foobar() RETURNS BIGINT
+                LANGUAGE plpgsql
+                AS $$ BEGIN RETURN (SELECT SUM(val) FROM foobar); END; $$;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                renamed_val INT
+            );
+
+            CREATE FUNCTION sum_foobar() RETURNS BIGINT
+                LANGUAGE plpgsql
+                AS $$ BEGIN RETURN (SELECT SUM(val) FROM foobar); END; $$;
+			`,
+		},
+		planFactory: confirmAllColumnRenamesPlan,
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+			diff.MigrationHazardTypeCorrectness,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" RENAME COLUMN \"val\" TO \"renamed_val\"",
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestColumnRenameTestCases() {
+	suite.runTestCases(columnRenameAcceptanceTestCases)
+}
@@ -0,0 +1,185 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var commentAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "No-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val);
+            CREATE VIEW foobar_view AS SELECT id, val FROM foobar;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+
+            COMMENT ON TABLE foobar IS 'The foobar''s table';
+            COMMENT ON COLUMN foobar.val IS 'A multi-line
+comment with ''single quotes''';
+            COMMENT ON INDEX foobar_val_idx IS 'The foobar''s index';
+            COMMENT ON VIEW foobar_view IS 'The foobar''s view';
+            COMMENT ON FUNCTION add(integer, integer) IS 'Adds two integers';
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val);
+            CREATE VIEW foobar_view AS SELECT id, val FROM foobar;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+
+            COMMENT ON TABLE foobar IS 'The foobar''s table';
+            COMMENT ON COLUMN foobar.val IS 'A multi-line
+comment with ''single quotes''';
+            COMMENT ON INDEX foobar_val_idx IS 'The foobar''s index';
+            COMMENT ON VIEW foobar_view IS 'The foobar''s view';
+            COMMENT ON FUNCTION add(integer, integer) IS 'Adds two integers';
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name: "Create objects with comments",
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TABLE schema_1.foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON schema_1.foobar(val);
+            CREATE VIEW schema_1.foobar_view AS SELECT id, val FROM schema_1.foobar;
+            CREATE FUNCTION schema_1.add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+
+            COMMENT ON TABLE schema_1.foobar IS 'The foobar''s table';
+            COMMENT ON COLUMN schema_1.foobar.val IS 'A multi-line
+comment with ''single quotes''';
+            COMMENT ON INDEX schema_1.foobar_val_idx IS 'The foobar''s index';
+            COMMENT ON VIEW schema_1.foobar_view IS 'The foobar''s view';
+            COMMENT ON FUNCTION schema_1.add(integer, integer) IS 'Adds two integers';
+			`,
+		},
+	},
+	{
+		name: "Add, change, and remove comments",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val);
+            CREATE VIEW foobar_view AS SELECT id, val FROM foobar;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+
+            COMMENT ON TABLE foobar IS 'The old table comment';
+            COMMENT ON COLUMN foobar.id IS 'The old column comment';
+            COMMENT ON VIEW foobar_view IS 'The old view comment';
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val);
+            CREATE VIEW foobar_view AS SELECT id, val FROM foobar;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+
+            COMMENT ON TABLE foobar IS 'The new table''s
+multi-line comment';
+            COMMENT ON COLUMN foobar.val IS 'The new column comment';
+            COMMENT ON INDEX foobar_val_idx IS 'The new index comment';
+            COMMENT ON FUNCTION add(integer, integer) IS 'The new function comment';
+			`,
+		},
+	},
+	{
+		name: "Keep comments when objects are re-created",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val);
+            CREATE VIEW foobar_view AS SELECT id, val FROM foobar;
+
+            COMMENT ON INDEX foobar_val_idx IS 'The foobar''s index';
+            COMMENT ON VIEW foobar_view IS 'The foobar''s view';
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val, id);
+            CREATE VIEW foobar_view AS SELECT val FROM foobar;
+
+            COMMENT ON INDEX foobar_val_idx IS 'The foobar''s index';
+            COMMENT ON VIEW foobar_view IS 'The foobar''s view';
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+	{
+		name: "Drop objects with comments",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val);
+            CREATE VIEW foobar_view AS SELECT id, val FROM foobar;
+
+            COMMENT ON TABLE foobar IS 'The foobar''s table';
+            COMMENT ON COLUMN foobar.val IS 'The foobar''s column';
+            COMMENT ON INDEX foobar_val_idx IS 'The foobar''s index';
+            COMMENT ON VIEW foobar_view IS 'The foobar''s view';
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestCommentTestCases() {
+	suite.runTestCases(commentAcceptanceTestCases)
+}
@@ -0,0 +1,182 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var compositeTypeAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "no-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT COLLATE "C", zip INT);
+            CREATE TABLE foo(
+                addr address
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT COLLATE "C", zip INT);
+            CREATE TABLE foo(
+                addr address
+            );
+			`,
+		},
+
+		expectEmptyPlan: true,
+	},
+	{
+		name: "create composite type",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TYPE schema_1.color AS ENUM ('red', 'green', 'blue');
+            CREATE TYPE schema_1.address AS (street TEXT COLLATE "C", zip INT, color schema_1.color);
+            CREATE TABLE foo(
+                addr schema_1.address
+            );
+            CREATE FUNCTION get_address() RETURNS schema_1.address
+                LANGUAGE SQL
+                RETURN ROW('main', 12345, 'red')::schema_1.address;
+			`,
+		},
+	},
+	{
+		name: "drop composite type",
+		oldSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TYPE schema_1.color AS ENUM ('red', 'green', 'blue');
+            CREATE TYPE schema_1.address AS (street TEXT, zip INT, color schema_1.color);
+            CREATE TABLE foo(
+                addr schema_1.address
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TABLE foo(
+                addr TEXT
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "add attribute",
+		oldSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT);
+            CREATE TABLE foo(
+                addr address
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT, zip INT);
+            CREATE TABLE foo(
+                addr address
+            );
+			`,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TYPE \"public\".\"address\" ADD ATTRIBUTE \"zip\" integer CASCADE",
+		},
+	},
+	{
+		name: "drop attribute",
+		oldSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT, zip INT);
+            CREATE TABLE foo(
+                addr address
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT);
+            CREATE TABLE foo(
+                addr address
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TYPE \"public\".\"address\" DROP ATTRIBUTE \"zip\" CASCADE",
+		},
+	},
+	{
+		name: "alter attribute type and collation (type not used)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT, zip INT);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT COLLATE "C", zip BIGINT);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "reorder attributes (type not used)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT, zip INT);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (zip INT, street TEXT);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"DROP TYPE \"public\".\"address\"",
+			"CREATE TYPE \"public\".\"address\" AS (\"zip\" integer, \"street\" text COLLATE \"pg_catalog\".\"default\")",
+		},
+	},
+	{
+		name: "alter attribute type (type used)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT, zip INT);
+            CREATE TABLE foo(
+                addr address
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TYPE address AS (street TEXT, zip BIGINT);
+            CREATE TABLE foo(
+                addr address
+            );
+			`,
+		},
+
+		// Postgres cannot alter the type of an attribute of a composite type that is used by a table column.
+		expectedPlanErrorContains: errValidatingPlan.Error(),
+	},
+}
+
+func (suite *acceptanceTestSuite) TestCompositeTypeTestCases() {
+	suite.runTestCases(compositeTypeAcceptanceTestCases)
+}
@@ -0,0 +1,202 @@
+package migration_acceptance_tests
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	pg_query "github.com/pganalyze/pg_query_go/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+// allAcceptanceTestCases is every list of acceptance test cases run by the acceptance test suite
+var allAcceptanceTestCases = [][]acceptanceTestCase{
+	aggregateAcceptanceTestCases,
+	backCompatAcceptanceTestCases,
+	checkConstraintCases,
+	collationAcceptanceTestCases,
+	columnAcceptanceTestCases,
+	columnRenameAcceptanceTestCases,
+	commentAcceptanceTestCases,
+	compositeTypeAcceptanceTestCases,
+	dataPackingCases,
+	databaseSchemaSourceTestCases,
+	defaultPrivilegeAcceptanceTestCases,
+	domainAcceptanceTestCases,
+	enumAcceptanceTestCases,
+	eventTriggerAcceptanceTestCases,
+	extensionAcceptanceTestCases,
+	foreignDataAcceptanceTestCases,
+	foreignKeyConstraintCases,
+	functionAcceptanceTestCases,
+	indexAcceptanceTestCases,
+	localPartitionIndexAcceptanceTestCases,
+	materializedViewAcceptanceTestCases,
+	namedSchemaAcceptanceTestCases,
+	onlineColumnTypeChangeAcceptanceTestCases,
+	operatorAcceptanceTestCases,
+	ownerAcceptanceTestCases,
+	partitionedIndexAcceptanceTestCases,
+	partitionedTableAcceptanceTestCases,
+	policyAcceptanceTestCases,
+	privilegeAcceptanceTestCases,
+	procedureAcceptanceTestCases,
+	publicationAcceptanceTestCases,
+	reassignOwnedAcceptanceTestCases,
+	schemaAcceptanceTests,
+	sequenceAcceptanceTests,
+	statisticsObjectAcceptanceTestCases,
+	tableAcceptanceTestCases,
+	tableInheritanceAcceptanceTestCases,
+	tableRenameAcceptanceTestCases,
+	tablespaceAcceptanceTestCases,
+	textSearchAcceptanceTestCases,
+	triggerAcceptanceTestCases,
+	viewAcceptanceTestCases,
+}
+
+// TestAcceptanceTestCoverage ensures the acceptance test cases exercise every object type that is diffed, i.e., every
+// field of schema.Schema that is not excluded from the hash. pkg/diff registers a SQL generator for each of these object
+// types. It does not require a database, so it can be run on its own, i.e., `go test -run TestAcceptanceTestCoverage`.
+func TestAcceptanceTestCoverage(t *testing.T) {
+	exercisedObjectTypes := make(map[string]bool)
+	for _, testCases := range allAcceptanceTestCases {
+		for _, tc := range testCases {
+			for _, ddl := range append(append([]string(nil), tc.oldSchemaDDL...), tc.newSchemaDDL...) {
+				objectTypes, err := getCreatedObjectTypes(ddl)
+				if err != nil && (tc.expectedPlanErrorIs != nil || tc.expectedPlanErrorContains != "") {
+					// The DDL of test cases that expect an error might be deliberately invalid
+					continue
+				}
+				require.NoError(t, err, "test case %q", tc.name)
+				for _, objectType := range objectTypes {
+					exercisedObjectTypes[objectType] = true
+				}
+			}
+		}
+	}
+
+	var unexercisedObjectTypes []string
+	schemaType := reflect.TypeOf(schema.Schema{})
+	for i := 0; i < schemaType.NumField(); i++ {
+		field := schemaType.Field(i)
+		if field.Tag.Get("hash") == "ignore" {
+			// The field is not diffed, so it has no SQL generator
+			continue
+		}
+		if !exercisedObjectTypes[field.Name] {
+			unexercisedObjectTypes = append(unexercisedObjectTypes, field.Name)
+		}
+	}
+	assert.Empty(t, unexercisedObjectTypes, "no acceptance test case creates these object types. Add test cases "+
+		"that create them and, if the object type is new, teach getCreatedObjectTypes to recognize the statements "+
+		"that create it")
+}
+
+// getCreatedObjectTypes returns the object types created by the DDL, named after the schema.Schema field the objects
+// are stored in
+func getCreatedObjectTypes(ddl string) ([]string, error) {
+	parseResult, err := pg_query.Parse(ddl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing DDL: %w", err)
+	}
+	var objectTypes []string
+	for _, rawStmt := range parseResult.Stmts {
+		switch stmt := rawStmt.Stmt.Node.(type) {
+		case *pg_query.Node_CreateSchemaStmt:
+			objectTypes = append(objectTypes, "NamedSchemas")
+		case *pg_query.Node_CreateExtensionStmt:
+			objectTypes = append(objectTypes, "Extensions")
+		case *pg_query.Node_CreateFdwStmt:
+			objectTypes = append(objectTypes, "ForeignDataWrappers")
+		case *pg_query.Node_CreateForeignServerStmt:
+			objectTypes = append(objectTypes, "ForeignServers")
+		case *pg_query.Node_CreateEnumStmt:
+			objectTypes = append(objectTypes, "Enums")
+		case *pg_query.Node_CreateDomainStmt:
+			objectTypes = append(objectTypes, "Domains")
+		case *pg_query.Node_CompositeTypeStmt:
+			objectTypes = append(objectTypes, "CompositeTypes")
+		case *pg_query.Node_CreateStmt:
+			objectTypes = append(objectTypes, "Tables")
+			if hasForeignKey(stmt.CreateStmt) {
+				objectTypes = append(objectTypes, "ForeignKeyConstraints")
+			}
+		case *pg_query.Node_AlterTableStmt:
+			for _, cmd := range stmt.AlterTableStmt.Cmds {
+				alterTableCmd := cmd.GetAlterTableCmd()
+				if alterTableCmd.GetSubtype() == pg_query.AlterTableType_AT_AddConstraint &&
+					alterTableCmd.GetDef().GetConstraint().GetContype() == pg_query.ConstrType_CONSTR_FOREIGN {
+					objectTypes = append(objectTypes, "ForeignKeyConstraints")
+				}
+			}
+		case *pg_query.Node_CreateForeignTableStmt:
+			objectTypes = append(objectTypes, "ForeignTables")
+		case *pg_query.Node_ViewStmt:
+			objectTypes = append(objectTypes, "Views")
+		case *pg_query.Node_CreateTableAsStmt:
+			if stmt.CreateTableAsStmt.Objtype == pg_query.ObjectType_OBJECT_MATVIEW {
+				objectTypes = append(objectTypes, "MaterializedViews")
+			}
+		case *pg_query.Node_IndexStmt:
+			objectTypes = append(objectTypes, "Indexes")
+		case *pg_query.Node_CreateStatsStmt:
+			objectTypes = append(objectTypes, "StatisticsObjects")
+		case *pg_query.Node_CreateSeqStmt:
+			objectTypes = append(objectTypes, "Sequences")
+		case *pg_query.Node_CreateFunctionStmt:
+			if stmt.CreateFunctionStmt.IsProcedure {
+				objectTypes = append(objectTypes, "Procedures")
+			} else {
+				objectTypes = append(objectTypes, "Functions")
+			}
+		case *pg_query.Node_DefineStmt:
+			switch stmt.DefineStmt.Kind {
+			case pg_query.ObjectType_OBJECT_COLLATION:
+				objectTypes = append(objectTypes, "Collations")
+			case pg_query.ObjectType_OBJECT_TSDICTIONARY:
+				objectTypes = append(objectTypes, "TextSearchDictionaries")
+			case pg_query.ObjectType_OBJECT_TSCONFIGURATION:
+				objectTypes = append(objectTypes, "TextSearchConfigs")
+			case pg_query.ObjectType_OBJECT_AGGREGATE:
+				objectTypes = append(objectTypes, "Aggregates")
+			case pg_query.ObjectType_OBJECT_OPERATOR:
+				objectTypes = append(objectTypes, "Operators")
+			}
+		case *pg_query.Node_CreateTrigStmt:
+			objectTypes = append(objectTypes, "Triggers")
+		case *pg_query.Node_CreateEventTrigStmt:
+			objectTypes = append(objectTypes, "EventTriggers")
+		case *pg_query.Node_CreateOpClassStmt:
+			objectTypes = append(objectTypes, "OperatorClasses")
+		case *pg_query.Node_CreatePublicationStmt:
+			objectTypes = append(objectTypes, "Publications")
+		case *pg_query.Node_GrantStmt:
+			if stmt.GrantStmt.IsGrant {
+				objectTypes = append(objectTypes, "Privileges")
+			}
+		case *pg_query.Node_AlterDefaultPrivilegesStmt:
+			if stmt.AlterDefaultPrivilegesStmt.GetAction().GetIsGrant() {
+				objectTypes = append(objectTypes, "DefaultPrivileges")
+			}
+		}
+	}
+	return objectTypes, nil
+}
+
+func hasForeignKey(stmt *pg_query.CreateStmt) bool {
+	for _, elt := range stmt.TableElts {
+		constraints := []*pg_query.Node{elt}
+		if columnDef := elt.GetColumnDef(); columnDef != nil {
+			constraints = columnDef.Constraints
+		}
+		for _, constraint := range constraints {
+			if constraint.GetConstraint().GetContype() == pg_query.ConstrType_CONSTR_FOREIGN {
+				return true
+			}
+		}
+	}
+	return false
+}
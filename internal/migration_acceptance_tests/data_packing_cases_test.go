@@ -32,6 +32,9 @@ var dataPackingCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT foobar_fk FOREIGN KEY (foo, bar) REFERENCES schema_1.foobar_fk(foo, bar);
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 
 		expectedDBSchemaDDL: []string{`
             CREATE TABLE foobar(
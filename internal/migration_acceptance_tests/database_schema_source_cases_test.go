@@ -141,6 +141,7 @@ var databaseSchemaSourceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
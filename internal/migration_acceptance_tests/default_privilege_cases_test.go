@@ -0,0 +1,118 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var defaultPrivilegeAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name:  "no-op",
+		roles: []string{"role_1", "role_2"},
+		planOpts: []diff.PlanOpt{
+			diff.WithPrivileges(),
+		},
+		oldSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT SELECT ON TABLES TO role_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT USAGE ON SEQUENCES TO role_1 WITH GRANT OPTION;
+            ALTER DEFAULT PRIVILEGES GRANT EXECUTE ON FUNCTIONS TO role_2;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT SELECT ON TABLES TO role_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT USAGE ON SEQUENCES TO role_1 WITH GRANT OPTION;
+            ALTER DEFAULT PRIVILEGES GRANT EXECUTE ON FUNCTIONS TO role_2;
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name:  "default privileges are not diffed without WithPrivileges",
+		roles: []string{"role_1"},
+		oldSchemaDDL: []string{
+			`
+            ALTER DEFAULT PRIVILEGES GRANT SELECT ON TABLES TO role_1;
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name:  "grant default privileges in a new schema",
+		roles: []string{"role_1"},
+		planOpts: []diff.PlanOpt{
+			diff.WithPrivileges(),
+		},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT SELECT, INSERT ON TABLES TO role_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT USAGE ON SEQUENCES TO role_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT EXECUTE ON FUNCTIONS TO role_1;
+            CREATE TABLE foo();
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAuthzUpdate,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:  "grant, alter, and revoke default privileges",
+		roles: []string{"role_1", "role_2"},
+		planOpts: []diff.PlanOpt{
+			diff.WithPrivileges(),
+		},
+		oldSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT SELECT, INSERT ON TABLES TO role_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT USAGE ON SEQUENCES TO role_1 WITH GRANT OPTION;
+            ALTER DEFAULT PRIVILEGES GRANT EXECUTE ON FUNCTIONS TO role_2;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT SELECT, DELETE ON TABLES TO role_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT USAGE ON SEQUENCES TO role_1;
+            ALTER DEFAULT PRIVILEGES GRANT EXECUTE ON FUNCTIONS TO role_1;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAuthzUpdate,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:  "drop a schema with default privileges",
+		roles: []string{"role_1"},
+		planOpts: []diff.PlanOpt{
+			diff.WithPrivileges(),
+		},
+		oldSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            ALTER DEFAULT PRIVILEGES IN SCHEMA schema_1 GRANT SELECT ON TABLES TO role_1;
+            CREATE TABLE foo();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAuthzUpdate,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestDefaultPrivilegeTestCases() {
+	suite.runTestCases(defaultPrivilegeAcceptanceTestCases)
+}
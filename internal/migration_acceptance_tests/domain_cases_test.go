@@ -0,0 +1,183 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var domainAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "no-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE DOMAIN positive_int AS INT DEFAULT 1 NOT NULL CONSTRAINT positive_int_check CHECK (VALUE > 0);
+            CREATE TABLE foo(
+                val positive_int
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE DOMAIN positive_int AS INT DEFAULT 1 NOT NULL CONSTRAINT positive_int_check CHECK (VALUE > 0);
+            CREATE TABLE foo(
+                val positive_int
+            );
+			`,
+		},
+
+		expectEmptyPlan: true,
+	},
+	{
+		name: "create domain",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TYPE schema_1.color AS ENUM ('red', 'green', 'blue');
+            CREATE DOMAIN schema_1.not_blue AS schema_1.color CHECK (VALUE <> 'blue');
+            CREATE DOMAIN schema_1.short_text AS TEXT COLLATE "C" DEFAULT 'foo' NOT NULL
+                CONSTRAINT not_empty CHECK (VALUE <> '')
+                CONSTRAINT short CHECK (LENGTH(VALUE) < 10);
+            CREATE TYPE schema_1.address AS (street schema_1.short_text, color schema_1.not_blue);
+            CREATE TABLE foo(
+                val schema_1.short_text,
+                color schema_1.not_blue,
+                addr schema_1.address
+            );
+			`,
+		},
+	},
+	{
+		name: "drop domain",
+		oldSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TYPE schema_1.color AS ENUM ('red', 'green', 'blue');
+            CREATE DOMAIN schema_1.not_blue AS schema_1.color CHECK (VALUE <> 'blue');
+            CREATE TABLE foo(
+                color schema_1.not_blue
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TABLE foo(
+                color TEXT
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "drop domain and table using it",
+		oldSchemaDDL: []string{
+			`
+            CREATE DOMAIN positive_int AS INT CHECK (VALUE > 0);
+            CREATE TABLE foo(
+                val positive_int
+            );
+			`,
+		},
+		newSchemaDDL: nil,
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+	{
+		name: "add and drop constraints, default, and not null",
+		oldSchemaDDL: []string{
+			`
+            CREATE DOMAIN some_int AS INT DEFAULT 1 CONSTRAINT positive CHECK (VALUE > 0);
+            CREATE TABLE foo(
+                val some_int
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE DOMAIN some_int AS INT NOT NULL CONSTRAINT small CHECK (VALUE < 100);
+            CREATE TABLE foo(
+                val some_int
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresShareLock,
+		},
+		expectedPlanDDL: []string{
+			"ALTER DOMAIN \"public\".\"some_int\" DROP CONSTRAINT \"positive\"",
+			"ALTER DOMAIN \"public\".\"some_int\" DROP DEFAULT",
+			"ALTER DOMAIN \"public\".\"some_int\" SET NOT NULL",
+			"ALTER DOMAIN \"public\".\"some_int\" ADD CONSTRAINT \"small\" CHECK ((VALUE < 100))",
+		},
+	},
+	{
+		name: "change constraint",
+		oldSchemaDDL: []string{
+			`
+            CREATE DOMAIN some_int AS INT CONSTRAINT bounded CHECK (VALUE > 0);
+            CREATE TABLE foo(
+                val some_int
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE DOMAIN some_int AS INT CONSTRAINT bounded CHECK (VALUE > 0 AND VALUE < 100);
+            CREATE TABLE foo(
+                val some_int
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresShareLock,
+		},
+	},
+	{
+		name: "change base type (domain not used)",
+		oldSchemaDDL: []string{
+			`
+            CREATE DOMAIN some_int AS INT CHECK (VALUE > 0);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE DOMAIN some_int AS BIGINT CHECK (VALUE > 0);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "change base type (domain used)",
+		oldSchemaDDL: []string{
+			`
+            CREATE DOMAIN some_int AS INT;
+            CREATE TABLE foo(
+                val some_int
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE DOMAIN some_int AS BIGINT;
+            CREATE TABLE foo(
+                val some_int
+            );
+			`,
+		},
+
+		// A domain in-use cannot be dropped. pg-schema-diff will currently identify this as a validation error.
+		expectedPlanErrorContains: errValidatingPlan.Error(),
+	},
+}
+
+func (suite *acceptanceTestSuite) TestDomainTestCases() {
+	suite.runTestCases(domainAcceptanceTestCases)
+}
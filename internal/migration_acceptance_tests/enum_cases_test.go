@@ -92,6 +92,27 @@ var enumAcceptanceTestCases = []acceptanceTestCase{
 			`
             CREATE TYPE some_enum_1 AS ENUM ('0', '1', '3');
 		`},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "reorder values (enum not used)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TYPE some_enum_1 AS ENUM ('1', '2', '3');
+		`},
+		newSchemaDDL: []string{
+			`
+            CREATE TYPE some_enum_1 AS ENUM ('3', '2', '1');
+		`},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"DROP TYPE \"public\".\"some_enum_1\"",
+			"CREATE TYPE \"public\".\"some_enum_1\" AS ENUM ('3', '2', '1')",
+		},
 	},
 	{
 		name: "delete value and add value (enum used)",
@@ -78,6 +78,18 @@ var eventTriggerAcceptanceTestCases = []acceptanceTestCase{
 			EXECUTE FUNCTION log_table_ddl();`,
 		},
 	},
+	{
+		name: "Create deferrable event trigger (not supported)",
+		newSchemaDDL: []string{
+			`CREATE FUNCTION log_ddl_command() RETURNS event_trigger AS $$
+			BEGIN
+				RAISE NOTICE 'DDL command executed';
+			END;
+			$$ LANGUAGE plpgsql;`,
+			`CREATE EVENT TRIGGER log_ddl ON ddl_command_end DEFERRABLE INITIALLY DEFERRED EXECUTE FUNCTION log_ddl_command();`,
+		},
+		expectedPlanErrorContains: "event triggers do not support DEFERRABLE; found in trigger 'log_ddl'",
+	},
 }
 
 func (suite *acceptanceTestSuite) TestEventTriggerTestCases() {
@@ -0,0 +1,161 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var foreignDataAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "no-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE EXTENSION postgres_fdw;
+            CREATE SERVER remote FOREIGN DATA WRAPPER postgres_fdw OPTIONS (host 'localhost', dbname 'remote_db');
+            CREATE FOREIGN TABLE remote_users(
+                id INT NOT NULL,
+                name TEXT
+            ) SERVER remote OPTIONS (table_name 'users');
+            CREATE VIEW remote_user_names AS SELECT name FROM remote_users;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE EXTENSION postgres_fdw;
+            CREATE SERVER remote FOREIGN DATA WRAPPER postgres_fdw OPTIONS (host 'localhost', dbname 'remote_db');
+            CREATE FOREIGN TABLE remote_users(
+                id INT NOT NULL,
+                name TEXT
+            ) SERVER remote OPTIONS (table_name 'users');
+            CREATE VIEW remote_user_names AS SELECT name FROM remote_users;
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name: "create foreign data wrapper, server, and foreign table used by a view",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE EXTENSION postgres_fdw;
+            CREATE SERVER remote FOREIGN DATA WRAPPER postgres_fdw OPTIONS (host 'localhost', dbname 'remote_db');
+            CREATE FOREIGN DATA WRAPPER dummy OPTIONS (debug 'true');
+            CREATE SERVER dummy_server TYPE 'test' VERSION '1.0' FOREIGN DATA WRAPPER dummy;
+            CREATE FOREIGN TABLE schema_1.remote_users(
+                id INT NOT NULL,
+                name TEXT COLLATE "C" DEFAULT 'unknown'
+            ) SERVER remote OPTIONS (schema_name 'public', table_name 'users');
+            CREATE VIEW schema_1.remote_user_names AS SELECT name FROM schema_1.remote_users;
+            CREATE TABLE foo();
+			`,
+		},
+	},
+	{
+		name: "drop foreign data wrapper, server, and foreign table used by a view",
+		oldSchemaDDL: []string{
+			`
+            CREATE EXTENSION postgres_fdw;
+            CREATE SERVER remote FOREIGN DATA WRAPPER postgres_fdw OPTIONS (host 'localhost', dbname 'remote_db');
+            CREATE FOREIGN DATA WRAPPER dummy;
+            CREATE SERVER dummy_server FOREIGN DATA WRAPPER dummy;
+            CREATE FOREIGN TABLE remote_users(
+                id INT NOT NULL,
+                name TEXT
+            ) SERVER remote OPTIONS (table_name 'users');
+            CREATE VIEW remote_user_names AS SELECT name FROM remote_users;
+            CREATE TABLE foo();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "alter foreign data wrapper, server, and foreign table options",
+		oldSchemaDDL: []string{
+			`
+            CREATE FOREIGN DATA WRAPPER dummy OPTIONS (debug 'true');
+            CREATE SERVER dummy_server VERSION '1.0' FOREIGN DATA WRAPPER dummy OPTIONS (host 'localhost', port '5432');
+            CREATE FOREIGN TABLE remote_users(
+                id INT NOT NULL
+            ) SERVER dummy_server OPTIONS (table_name 'users');
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FOREIGN DATA WRAPPER dummy OPTIONS (debug 'false', batch_size '100');
+            CREATE SERVER dummy_server VERSION '2.0' FOREIGN DATA WRAPPER dummy OPTIONS (host 'db.example.com');
+            CREATE FOREIGN TABLE remote_users(
+                id INT NOT NULL
+            ) SERVER dummy_server OPTIONS (table_name 'users_v2');
+			`,
+		},
+		expectedPlanDDL: []string{
+			"ALTER FOREIGN DATA WRAPPER \"dummy\" OPTIONS (ADD batch_size '100', SET debug 'false')",
+			"ALTER SERVER \"dummy_server\" VERSION '2.0' OPTIONS (SET host 'db.example.com', DROP port)",
+			"ALTER FOREIGN TABLE \"public\".\"remote_users\" OPTIONS (SET table_name 'users_v2')",
+		},
+	},
+	{
+		name: "alter foreign table columns used by a view",
+		oldSchemaDDL: []string{
+			`
+            CREATE FOREIGN DATA WRAPPER dummy;
+            CREATE SERVER dummy_server FOREIGN DATA WRAPPER dummy;
+            CREATE FOREIGN TABLE remote_users(
+                id INT NOT NULL,
+                name TEXT,
+                legacy TEXT
+            ) SERVER dummy_server;
+            CREATE VIEW remote_user_ids AS SELECT id FROM remote_users;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FOREIGN DATA WRAPPER dummy;
+            CREATE SERVER dummy_server FOREIGN DATA WRAPPER dummy;
+            CREATE FOREIGN TABLE remote_users(
+                id BIGINT NOT NULL,
+                name TEXT NOT NULL DEFAULT 'unknown',
+                email TEXT
+            ) SERVER dummy_server;
+            CREATE VIEW remote_user_ids AS SELECT id FROM remote_users WHERE email IS NOT NULL;
+			`,
+		},
+	},
+	{
+		name: "re-create foreign table when its server changes",
+		oldSchemaDDL: []string{
+			`
+            CREATE FOREIGN DATA WRAPPER dummy;
+            CREATE SERVER server_1 FOREIGN DATA WRAPPER dummy;
+            CREATE SERVER server_2 FOREIGN DATA WRAPPER dummy;
+            CREATE FOREIGN TABLE remote_users(
+                id INT NOT NULL
+            ) SERVER server_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FOREIGN DATA WRAPPER dummy;
+            CREATE SERVER server_1 FOREIGN DATA WRAPPER dummy;
+            CREATE SERVER server_2 FOREIGN DATA WRAPPER dummy;
+            CREATE FOREIGN TABLE remote_users(
+                id INT NOT NULL
+            ) SERVER server_2;
+			`,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestForeignDataTestCases() {
+	suite.runTestCases(foreignDataAcceptanceTestCases)
+}
@@ -72,6 +72,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
             );
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add FK on partitioned",
@@ -136,6 +139,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
       ALTER TABLE foobar_fk_1 ADD CONSTRAINT some_fk FOREIGN KEY (fk_id) REFERENCES "foo bar"(id);
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add FK referencing partitioned",
@@ -167,6 +173,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
             );
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add FK referencing partition",
@@ -200,6 +209,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
             );
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add FK (only referenced table is new)",
@@ -224,6 +236,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
             );
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add FK (owning table is not new)",
@@ -250,6 +265,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
             );
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add FK (tables new)",
@@ -269,6 +287,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
             );
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Add not-valid FK (neither table is new)",
@@ -403,6 +424,7 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -502,6 +524,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
                 FOREIGN KEY (fk_id) REFERENCES foobar(id);
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 		expectedPlanDDL: []string{
 			"ALTER TABLE \"public\".\"foobar fk\" VALIDATE CONSTRAINT \"some_fk\"",
 		},
@@ -575,6 +600,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
                 FOREIGN KEY (fk_foo) REFERENCES foobar(foo);
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Alter FK (on update)",
@@ -605,6 +633,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
             );
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Alter FK (on delete)",
@@ -635,6 +666,198 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
             );
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
+	},
+	{
+		name: "Alter FK (on delete cascade)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                PRIMARY KEY (id)
+            );
+
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                FOREIGN KEY (fk_id) REFERENCES foobar(id)
+            );
+      `,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                PRIMARY KEY (id)
+            );
+
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                FOREIGN KEY (fk_id) REFERENCES foobar(id)
+                    ON DELETE CASCADE
+            );
+      `,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
+	},
+	{
+		name: "Alter FK (deferrability)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                PRIMARY KEY (id)
+            );
+
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                FOREIGN KEY (fk_id) REFERENCES foobar(id)
+                    NOT DEFERRABLE
+            );
+      `,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                PRIMARY KEY (id)
+            );
+
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                FOREIGN KEY (fk_id) REFERENCES foobar(id)
+                    DEFERRABLE INITIALLY DEFERRED
+            );
+      `,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar fk\" ALTER CONSTRAINT \"foobar fk_fk_id_fkey\" DEFERRABLE INITIALLY DEFERRED",
+		},
+	},
+	{
+		name: "Alter FK (initially immediate to initially deferred)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                PRIMARY KEY (id)
+            );
+
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                FOREIGN KEY (fk_id) REFERENCES foobar(id)
+                    ON DELETE CASCADE
+                    DEFERRABLE INITIALLY IMMEDIATE
+            );
+      `,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                PRIMARY KEY (id)
+            );
+
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                FOREIGN KEY (fk_id) REFERENCES foobar(id)
+                    ON DELETE CASCADE
+                    DEFERRABLE INITIALLY DEFERRED
+            );
+      `,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar fk\" ALTER CONSTRAINT \"foobar fk_fk_id_fkey\" DEFERRABLE INITIALLY DEFERRED",
+		},
+	},
+	{
+		name: "Alter FK (deferrable to not deferrable)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                PRIMARY KEY (id)
+            );
+
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                FOREIGN KEY (fk_id) REFERENCES foobar(id)
+                    DEFERRABLE INITIALLY DEFERRED
+            );
+      `,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                PRIMARY KEY (id)
+            );
+
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                FOREIGN KEY (fk_id) REFERENCES foobar(id)
+            );
+      `,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar fk\" ALTER CONSTRAINT \"foobar fk_fk_id_fkey\" NOT DEFERRABLE",
+		},
+	},
+	{
+		name: "Add self-referential FK",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                parent_id INT
+            );
+      `,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                parent_id INT
+            );
+            ALTER TABLE foobar ADD CONSTRAINT foobar_parent_fk
+                FOREIGN KEY (parent_id) REFERENCES foobar(id)
+                    ON DELETE CASCADE;
+      `,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
+	},
+	{
+		name: "Add self-referential FK (table new)",
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                parent_id INT REFERENCES foobar(id)
+            );
+      `,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
+	},
+	{
+		name: "Drop table with self-referential FK",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                parent_id INT REFERENCES foobar(id)
+            );
+      `,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
 	},
 	{
 		name: "Alter castable type change",
@@ -789,6 +1012,9 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
                 FOREIGN KEY (fk_id) REFERENCES foobar(id);
       `,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Switch FK owning table (analog tables in different schemas stay same)",
@@ -864,6 +1090,7 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeAcquiresShareRowExclusiveLock,
 			diff.MigrationHazardTypeIndexBuild,
 			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -919,6 +1146,110 @@ var foreignKeyConstraintCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
+		},
+	},
+	{
+		name: "Add FK on table under the NOT VALID row threshold (added in one statement)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+            CREATE TABLE "foobar fk"(
+                fk_id INT
+            );
+            INSERT INTO foobar SELECT i FROM generate_series(1, 10) AS i;
+            INSERT INTO "foobar fk" SELECT i FROM generate_series(1, 10) AS i;
+            ANALYZE foobar;
+            ANALYZE "foobar fk";
+      `,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                CONSTRAINT some_fk FOREIGN KEY (fk_id) REFERENCES foobar(id)
+            );
+      `,
+		},
+		planOpts: []diff.PlanOpt{diff.WithAddConstraintsNotValidRowThreshold(1000)},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresShareRowExclusiveLock,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar fk\" ADD CONSTRAINT \"some_fk\" FOREIGN KEY (fk_id) REFERENCES foobar(id)",
+		},
+	},
+	{
+		name: "Add FK on table over the NOT VALID row threshold (validation is long running)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+            CREATE TABLE "foobar fk"(
+                fk_id INT
+            );
+            INSERT INTO foobar SELECT i FROM generate_series(1, 2000) AS i;
+            INSERT INTO "foobar fk" SELECT i FROM generate_series(1, 2000) AS i;
+            ANALYZE foobar;
+            ANALYZE "foobar fk";
+      `,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                CONSTRAINT some_fk FOREIGN KEY (fk_id) REFERENCES foobar(id)
+            );
+      `,
+		},
+		planOpts: []diff.PlanOpt{diff.WithAddConstraintsNotValidRowThreshold(1000)},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeLongRunning,
+			diff.MigrationHazardTypeValidateConstraint,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar fk\" ADD CONSTRAINT \"some_fk\" FOREIGN KEY (fk_id) REFERENCES foobar(id) NOT VALID",
+			"ALTER TABLE \"public\".\"foobar fk\" VALIDATE CONSTRAINT \"some_fk\"",
+		},
+	},
+	{
+		name: "Add FK with NOT VALID disabled",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+            CREATE TABLE "foobar fk"(
+                fk_id INT
+            );
+      `,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+            CREATE TABLE "foobar fk"(
+                fk_id INT,
+                CONSTRAINT some_fk FOREIGN KEY (fk_id) REFERENCES foobar(id)
+            );
+      `,
+		},
+		planOpts: []diff.PlanOpt{diff.WithAddConstraintsNotValid(false)},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresShareRowExclusiveLock,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar fk\" ADD CONSTRAINT \"some_fk\" FOREIGN KEY (fk_id) REFERENCES foobar(id)",
 		},
 	},
 }
@@ -88,6 +88,22 @@ var functionAcceptanceTestCases = []acceptanceTestCase{
 			`,
 		},
 	},
+	{
+		name:         "Create functions with OUT parameters (with conflicting names)",
+		oldSchemaDDL: nil,
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION split_value(a integer, OUT lower_half integer, OUT upper_half integer)
+                LANGUAGE SQL
+                IMMUTABLE
+                AS $$ SELECT a / 2, a - a / 2 $$;
+            CREATE FUNCTION split_value(a text, OUT lower_half text, OUT upper_half text)
+                LANGUAGE SQL
+                IMMUTABLE
+                AS $$ SELECT LEFT(a, LENGTH(a) / 2), RIGHT(a, LENGTH(a) - LENGTH(a) / 2) $$;
+			`,
+		},
+	},
 	{
 		name:         "Create non-sql function",
 		oldSchemaDDL: nil,
@@ -142,6 +158,27 @@ var functionAcceptanceTestCases = []acceptanceTestCase{
 		`},
 		expectedHazardTypes: []diff.MigrationHazardType{diff.MigrationHazardTypeHasUntrackableDependencies},
 	},
+	{
+		name:         "Create function that depends on a table in another schema",
+		oldSchemaDDL: nil,
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA accounting;
+            CREATE TABLE accounting.transactions(
+                id BIGINT,
+                amount BIGINT
+            );
+
+            CREATE SCHEMA reporting;
+            -- SQL-standard function bodies are validated on creation, so the table must be created first
+            CREATE FUNCTION reporting.get_summary() RETURNS BIGINT
+                LANGUAGE SQL
+                BEGIN ATOMIC
+                    SELECT SUM(amount) FROM accounting.transactions;
+                END;
+			`,
+		},
+	},
 	{
 		name: "Create function with an extension that also creates functions installed",
 		oldSchemaDDL: []string{
@@ -330,6 +367,25 @@ var functionAcceptanceTestCases = []acceptanceTestCase{
 			`,
 		},
 	},
+	{
+		name: "Alter function OUT parameter types",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION split_value(a integer, OUT lower_half integer, OUT upper_half integer)
+                LANGUAGE SQL
+                IMMUTABLE
+                AS $$ SELECT a / 2, a - a / 2 $$;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION split_value(a integer, OUT lower_half bigint, OUT upper_half bigint)
+                LANGUAGE SQL
+                IMMUTABLE
+                AS $$ SELECT a / 2, a - a / 2 $$;
+			`,
+		},
+	},
 	{
 		name: "Alter functions with quoted names (with conflicting names)",
 		oldSchemaDDL: []string{
@@ -571,6 +627,153 @@ var functionAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{diff.MigrationHazardTypeHasUntrackableDependencies},
 	},
+	{
+		name: "Ignore formatting differences",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer AS $$
+                -- Add the two numbers
+                SELECT a + b
+            $$ LANGUAGE SQL IMMUTABLE;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            create function add(a integer, b integer) returns integer as $$ select a + b $$ language sql immutable;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithIgnoreFormattingDifferences(),
+		},
+		expectEmptyPlan: true,
+		expectedDBSchemaDDL: []string{
+			`
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer AS $$
+                -- Add the two numbers
+                SELECT a + b
+            $$ LANGUAGE SQL IMMUTABLE;
+			`,
+		},
+	},
+	{
+		name: "Ignore formatting differences still detects changes",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer AS $$
+                -- Add the two numbers
+                SELECT a + b
+            $$ LANGUAGE SQL IMMUTABLE;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            create function add(a integer, b integer) returns integer as $$ select a + b + 1 $$ language sql immutable;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithIgnoreFormattingDifferences(),
+		},
+	},
+	{
+		name: "Function bodies that only differ in indentation",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION increment(i integer) RETURNS integer AS $$
+            BEGIN
+                RETURN i + 1;
+            END;
+            $$ LANGUAGE plpgsql;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION increment(i integer) RETURNS integer AS $$
+                BEGIN
+                        -- Increment the number
+                        RETURN i + 1;
+                END;
+            $$ LANGUAGE plpgsql;
+			`,
+		},
+		expectEmptyPlan: true,
+		expectedDBSchemaDDL: []string{
+			`
+            CREATE FUNCTION increment(i integer) RETURNS integer AS $$
+            BEGIN
+                RETURN i + 1;
+            END;
+            $$ LANGUAGE plpgsql;
+			`,
+		},
+	},
+	{
+		name: "Create window function",
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION my_row_number() RETURNS bigint
+                LANGUAGE internal
+                WINDOW
+                IMMUTABLE
+                AS 'window_row_number';
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{diff.MigrationHazardTypeHasUntrackableDependencies},
+	},
+	{
+		name: "Add WINDOW attribute to function",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION my_row_number() RETURNS bigint
+                LANGUAGE internal
+                IMMUTABLE
+                AS 'window_row_number';
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION my_row_number() RETURNS bigint
+                LANGUAGE internal
+                WINDOW
+                IMMUTABLE
+                AS 'window_row_number';
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{diff.MigrationHazardTypeHasUntrackableDependencies},
+	},
+	{
+		name: "Remove WINDOW attribute from function",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION my_row_number() RETURNS bigint
+                LANGUAGE internal
+                WINDOW
+                IMMUTABLE
+                AS 'window_row_number';
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION my_row_number() RETURNS bigint
+                LANGUAGE internal
+                IMMUTABLE
+                AS 'window_row_number';
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{diff.MigrationHazardTypeHasUntrackableDependencies},
+	},
+	{
+		name: "Drop window function",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION my_row_number() RETURNS bigint
+                LANGUAGE internal
+                WINDOW
+                IMMUTABLE
+                AS 'window_row_number';
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{diff.MigrationHazardTypeHasUntrackableDependencies},
+	},
 }
 
 func (suite *acceptanceTestSuite) TestFunctionTestCases() {
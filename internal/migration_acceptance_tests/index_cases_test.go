@@ -173,6 +173,7 @@ var indexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -195,6 +196,70 @@ var indexAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeIndexBuild,
 		},
 	},
+	{
+		name: "Add a deferrable unique constraint",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT UNIQUE DEFERRABLE INITIALLY DEFERRED
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Alter unique constraint (not deferrable to deferrable)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT UNIQUE
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT UNIQUE DEFERRABLE
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+	{
+		name: "Alter unique constraint (deferrable to not deferrable)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT UNIQUE DEFERRABLE INITIALLY DEFERRED
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT UNIQUE
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
 	{
 		name: "Add a primary key on NOT NULL column",
 		oldSchemaDDL: []string{
@@ -403,6 +468,7 @@ var indexAcceptanceTestCases = []acceptanceTestCase{
 		expectedPlanDDL: []string{
 			"DROP INDEX CONCURRENTLY \"public\".\"some_idx\"",
 			"ALTER TABLE \"public\".\"foobar\" DROP COLUMN \"foo\"",
+			"ANALYZE \"public\".\"foobar\"",
 		},
 	},
 	{
@@ -426,6 +492,7 @@ var indexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -469,6 +536,7 @@ var indexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexDropped,
 		},
 	},
@@ -493,6 +561,30 @@ var indexAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeIndexDropped,
 		},
 	},
+	{
+		name: "Convert a unique constraint to a standalone unique index",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                CONSTRAINT foobar_id_key UNIQUE (id)
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT
+            );
+            CREATE UNIQUE INDEX foobar_id_key ON foobar(id);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
 	{
 		name: "Add and delete a normal index (conflicting schemas)",
 		oldSchemaDDL: []string{
@@ -556,6 +648,7 @@ var indexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
 		},
@@ -600,6 +693,7 @@ var indexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
 		},
@@ -628,198 +722,846 @@ var indexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
 		},
 	},
 	{
-		name: "Change an index column ordering",
+		name: "Change an index method",
 		oldSchemaDDL: []string{
 			`
             CREATE TABLE foobar(
                 id INT PRIMARY KEY,
-                foo TEXT NOT NULL,
-                bar BIGINT NOT NULL
+                foo TEXT NOT NULL
             );
-            CREATE INDEX some_idx ON foobar (foo, bar)
+            CREATE INDEX some_idx ON foobar USING btree (foo);
 			`,
 		},
 		newSchemaDDL: []string{
 			`
             CREATE TABLE foobar(
-                id INT,
-                foo TEXT NOT NULL,
-                bar BIGINT NOT NULL
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL
             );
-            CREATE INDEX some_idx ON foobar (foo DESC, bar)
+            CREATE INDEX some_idx ON foobar USING hash (foo);
 			`,
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
-			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
 		},
 	},
 	{
-		name: "Delete columns and associated index",
+		name: "Add a GIN index on a JSONB column",
 		oldSchemaDDL: []string{
 			`
-            CREATE TABLE foobar(
+            CREATE TABLE events(
                 id INT PRIMARY KEY,
-                foo TEXT NOT NULL,
-                bar BIGINT NOT NULL
+                payload JSONB NOT NULL
             );
-            CREATE UNIQUE INDEX some_idx ON foobar(foo, bar);
 			`,
 		},
 		newSchemaDDL: []string{
 			`
-            CREATE TABLE foobar(
-                id INT
+            CREATE TABLE events(
+                id INT PRIMARY KEY,
+                payload JSONB NOT NULL
             );
+            CREATE INDEX events_payload_idx ON events USING gin (payload jsonb_path_ops);
 			`,
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
-			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
-			diff.MigrationHazardTypeDeletesData,
-			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
 		},
 	},
 	{
-		name: "Switch primary key and make old key nullable",
+		name: "Disable fastupdate on a GIN index",
 		oldSchemaDDL: []string{
 			`
-            CREATE TABLE foobar(
-                id INT NOT NULL PRIMARY KEY,
-                foo TEXT NOT NULL
+            CREATE TABLE events(
+                id INT PRIMARY KEY,
+                payload JSONB NOT NULL
             );
-            CREATE UNIQUE INDEX some_idx ON foobar(foo);
+            CREATE INDEX events_payload_idx ON events USING gin (payload jsonb_path_ops);
 			`,
 		},
 		newSchemaDDL: []string{
 			`
-            CREATE TABLE foobar(
-                id INT,
-                foo INT NOT NULL PRIMARY KEY
+            CREATE TABLE events(
+                id INT PRIMARY KEY,
+                payload JSONB NOT NULL
             );
+            CREATE INDEX events_payload_idx ON events USING gin (payload jsonb_path_ops) WITH (fastupdate = off, gin_pending_list_limit = 128);
 			`,
 		},
+		expectedPlanDDL: []string{
+			`ALTER INDEX "public"."events_payload_idx" SET (fastupdate=off, gin_pending_list_limit=128)`,
+		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
-			diff.MigrationHazardTypeIndexDropped,
-			diff.MigrationHazardTypeIndexBuild,
 			diff.MigrationHazardTypeImpactsDatabasePerformance,
 		},
 	},
 	{
-		name: "Switch primary key with quoted name",
+		name: "Reset the storage parameters of a GIN index",
 		oldSchemaDDL: []string{
 			`
-            CREATE TABLE "Foobar"(
-                "Id" INT NOT NULL PRIMARY KEY,
-                foo TEXT NOT NULL
+            CREATE TABLE events(
+                id INT PRIMARY KEY,
+                payload JSONB NOT NULL
             );
+            CREATE INDEX events_payload_idx ON events USING gin (payload jsonb_path_ops) WITH (fastupdate = off);
 			`,
 		},
 		newSchemaDDL: []string{
 			`
-            CREATE TABLE "Foobar"(
-                "Id" INT,
-                foo INT NOT NULL PRIMARY KEY
+            CREATE TABLE events(
+                id INT PRIMARY KEY,
+                payload JSONB NOT NULL
             );
+            CREATE INDEX events_payload_idx ON events USING gin (payload jsonb_path_ops);
 			`,
 		},
+		expectedPlanDDL: []string{
+			`ALTER INDEX "public"."events_payload_idx" RESET (fastupdate)`,
+		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
-			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+	{
+		name: "Add a BRIN index on a time-series table",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE measurements(
+                recorded_at TIMESTAMPTZ NOT NULL,
+                value DOUBLE PRECISION NOT NULL
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE measurements(
+                recorded_at TIMESTAMPTZ NOT NULL,
+                value DOUBLE PRECISION NOT NULL
+            );
+            CREATE INDEX measurements_recorded_at_idx ON measurements USING brin (recorded_at) WITH (pages_per_range = 32);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeIndexBuild,
-			diff.MigrationHazardTypeImpactsDatabasePerformance,
 		},
 	},
 	{
-		name: "Switch primary key when the original primary key constraint has a non-default name",
+		name: "Change the pages_per_range of a BRIN index",
 		oldSchemaDDL: []string{
 			`
-            CREATE TABLE foobar(
-                id INT NOT NULL,
-                foo TEXT NOT NULL
+            CREATE TABLE measurements(
+                recorded_at TIMESTAMPTZ NOT NULL,
+                value DOUBLE PRECISION NOT NULL
             );
-            CREATE UNIQUE INDEX unique_idx ON foobar(id);
-            ALTER TABLE foobar ADD CONSTRAINT non_default_primary_key PRIMARY KEY USING INDEX unique_idx;
+            CREATE INDEX measurements_recorded_at_idx ON measurements USING brin (recorded_at) WITH (pages_per_range = 32);
 			`,
 		},
 		newSchemaDDL: []string{
 			`
-            CREATE TABLE foobar(
-                id INT,
-                foo INT NOT NULL PRIMARY KEY
+            CREATE TABLE measurements(
+                recorded_at TIMESTAMPTZ NOT NULL,
+                value DOUBLE PRECISION NOT NULL
             );
+            CREATE INDEX measurements_recorded_at_idx ON measurements USING brin (recorded_at) WITH (pages_per_range = 64);
 			`,
 		},
+		expectedPlanDDL: []string{
+			`ALTER INDEX "public"."measurements_recorded_at_idx" SET (pages_per_range = 64)`,
+		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
-			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "Change the autosummarize of a BRIN index",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE measurements(
+                recorded_at TIMESTAMPTZ NOT NULL,
+                value DOUBLE PRECISION NOT NULL
+            );
+            CREATE INDEX measurements_recorded_at_idx ON measurements USING brin (recorded_at) WITH (pages_per_range = 32, autosummarize = on);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE measurements(
+                recorded_at TIMESTAMPTZ NOT NULL,
+                value DOUBLE PRECISION NOT NULL
+            );
+            CREATE INDEX measurements_recorded_at_idx ON measurements USING brin (recorded_at) WITH (pages_per_range = 32);
+			`,
+		},
+		expectedPlanDDL: []string{
+			`ALTER INDEX "public"."measurements_recorded_at_idx" RESET (autosummarize)`,
+		},
+	},
+	{
+		name: "Change a BRIN index to a btree index",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE measurements(
+                recorded_at TIMESTAMPTZ NOT NULL,
+                value DOUBLE PRECISION NOT NULL
+            );
+            CREATE INDEX measurements_recorded_at_idx ON measurements USING brin (recorded_at) WITH (pages_per_range = 32);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE measurements(
+                recorded_at TIMESTAMPTZ NOT NULL,
+                value DOUBLE PRECISION NOT NULL
+            );
+            CREATE INDEX measurements_recorded_at_idx ON measurements USING btree (recorded_at);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
 		},
 	},
 	{
-		name: "Alter primary key columns (name stays same)",
+		name: "Add an SP-GiST index on a geometric column",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE places(
+                id INT PRIMARY KEY,
+                location POINT NOT NULL
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE places(
+                id INT PRIMARY KEY,
+                location POINT NOT NULL
+            );
+            CREATE INDEX places_location_idx ON places USING spgist (location);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Change an index column ordering",
 		oldSchemaDDL: []string{
 			`
             CREATE TABLE foobar(
-                id INT NOT NULL,
-                foo TEXT NOT NULL
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
             );
-            CREATE UNIQUE INDEX unique_idx ON foobar(id);
-            ALTER TABLE foobar ADD CONSTRAINT non_default_primary_key PRIMARY KEY USING INDEX unique_idx;
+            CREATE INDEX some_idx ON foobar (foo, bar)
 			`,
 		},
 		newSchemaDDL: []string{
 			`
             CREATE TABLE foobar(
                 id INT,
-                foo INT NOT NULL
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
             );
-            CREATE UNIQUE INDEX unique_idx ON foobar(id, foo);
-            ALTER TABLE foobar ADD CONSTRAINT non_default_primary_key PRIMARY KEY USING INDEX unique_idx;
+            CREATE INDEX some_idx ON foobar (foo DESC, bar)
 			`,
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
-			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
 		},
 	},
 	{
-		name: "Alter index columns (index replacement and prioritized builds)",
-		oldSchemaDDL: []string{`
+		name: "Change an index without concurrent index operations",
+		oldSchemaDDL: []string{
+			`
             CREATE TABLE foobar(
-                foo TEXT,
-                bar INT
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
             );
-            CREATE INDEX some_idx_with_a_very_long_name ON foobar(foo);
-            CREATE INDEX old_idx ON foobar(bar);
-		`},
-		newSchemaDDL: []string{`
+            CREATE INDEX some_idx ON foobar (foo);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
             CREATE TABLE foobar(
-                foo TEXT,
-                bar INT
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
             );
-            CREATE INDEX some_idx_with_a_very_long_name ON foobar(foo, bar);
-            CREATE INDEX new_idx ON foobar(bar);
-		`},
-		expectedPlanDDL: []string{
-			"ALTER INDEX \"public\".\"some_idx_with_a_very_long_name\" RENAME TO \"pgschemadiff_tmpidx_some_idx_with_a_very_EBESExQVRheYGRobHB0eHw\"",
-			"CREATE INDEX CONCURRENTLY new_idx ON public.foobar USING btree (bar)",
-			"CREATE INDEX CONCURRENTLY some_idx_with_a_very_long_name ON public.foobar USING btree (foo, bar)",
-			"DROP INDEX CONCURRENTLY \"public\".\"old_idx\"",
-			"DROP INDEX CONCURRENTLY \"public\".\"pgschemadiff_tmpidx_some_idx_with_a_very_EBESExQVRheYGRobHB0eHw\"",
+            CREATE INDEX some_idx ON foobar (foo, bar);
+			`,
 		},
+		planOpts: []diff.PlanOpt{diff.WithDoNotUseConcurrentIndexOperations()},
 		expectedHazardTypes: []diff.MigrationHazardType{
-			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeAcquiresShareLock,
 			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Drop indexes without CONCURRENTLY",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL,
+                CONSTRAINT foo_key UNIQUE (foo)
+            );
+            CREATE UNIQUE INDEX bar_idx ON foobar (bar);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+			`,
+		},
+		planOpts: []diff.PlanOpt{diff.WithDropIndexesConcurrently(false)},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+	{
+		name: "Add an INCLUDE column to an index",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE INDEX some_idx ON foobar (foo);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE INDEX some_idx ON foobar (foo) INCLUDE (bar);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Remove an INCLUDE column from a unique index",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL,
+                fizz BOOLEAN
+            );
+            CREATE UNIQUE INDEX some_idx ON foobar (foo) INCLUDE (bar, fizz);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL,
+                fizz BOOLEAN
+            );
+            CREATE UNIQUE INDEX some_idx ON foobar (foo) INCLUDE (bar);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Delete an INCLUDE column and its index (index dropped concurrently first)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE INDEX some_idx ON foobar (foo) INCLUDE (bar);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+		expectedPlanDDL: []string{
+			"DROP INDEX CONCURRENTLY \"public\".\"some_idx\"",
+			"ALTER TABLE \"public\".\"foobar\" DROP COLUMN \"bar\"",
+			"ANALYZE \"public\".\"foobar\"",
+		},
+	},
+	{
+		name: "Change an index expression",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE INDEX some_idx ON foobar (LOWER(foo), bar);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE INDEX some_idx ON foobar (UPPER(foo), bar);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Index expression whitespace differences are ignored",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL
+            );
+            CREATE INDEX some_idx ON foobar (LOWER(foo));
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL
+            );
+            CREATE INDEX some_idx ON foobar (
+                lower(   foo   )
+            );
+			`,
+		},
+
+		expectEmptyPlan: true,
+	},
+	{
+		name: "Add a partial unique index",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                deleted_at TIMESTAMP
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                deleted_at TIMESTAMP
+            );
+            CREATE UNIQUE INDEX some_partial_idx ON foobar(foo) WHERE deleted_at IS NULL;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Change a partial index predicate",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE UNIQUE INDEX some_partial_idx ON foobar(foo) WHERE bar > 0;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE UNIQUE INDEX some_partial_idx ON foobar(foo) WHERE bar > 10;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Remove a partial index predicate",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE INDEX some_idx ON foobar(foo) WHERE bar > 0;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE INDEX some_idx ON foobar(foo);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Add a partial unique index on a column with a unique constraint",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL UNIQUE,
+                bar BIGINT NOT NULL
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL UNIQUE,
+                bar BIGINT NOT NULL
+            );
+            CREATE UNIQUE INDEX some_partial_idx ON foobar(foo) WHERE bar > 0;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Replace a partial unique index with a unique constraint of the same name",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE UNIQUE INDEX foobar_foo_key ON foobar(foo) WHERE bar > 0;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL,
+                CONSTRAINT foobar_foo_key UNIQUE (foo)
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Delete columns and associated index",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                foo TEXT NOT NULL,
+                bar BIGINT NOT NULL
+            );
+            CREATE UNIQUE INDEX some_idx ON foobar(foo, bar);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+	{
+		name: "Switch primary key and make old key nullable",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT NOT NULL PRIMARY KEY,
+                foo TEXT NOT NULL
+            );
+            CREATE UNIQUE INDEX some_idx ON foobar(foo);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                foo INT NOT NULL PRIMARY KEY
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "Switch primary key with quoted name",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE "Foobar"(
+                "Id" INT NOT NULL PRIMARY KEY,
+                foo TEXT NOT NULL
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE "Foobar"(
+                "Id" INT,
+                foo INT NOT NULL PRIMARY KEY
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "Switch primary key when the original primary key constraint has a non-default name",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT NOT NULL,
+                foo TEXT NOT NULL
+            );
+            CREATE UNIQUE INDEX unique_idx ON foobar(id);
+            ALTER TABLE foobar ADD CONSTRAINT non_default_primary_key PRIMARY KEY USING INDEX unique_idx;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                foo INT NOT NULL PRIMARY KEY
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Alter primary key columns (name stays same)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT NOT NULL,
+                foo TEXT NOT NULL
+            );
+            CREATE UNIQUE INDEX unique_idx ON foobar(id);
+            ALTER TABLE foobar ADD CONSTRAINT non_default_primary_key PRIMARY KEY USING INDEX unique_idx;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                foo INT NOT NULL
+            );
+            CREATE UNIQUE INDEX unique_idx ON foobar(id, foo);
+            ALTER TABLE foobar ADD CONSTRAINT non_default_primary_key PRIMARY KEY USING INDEX unique_idx;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Alter index columns (index replacement and prioritized builds)",
+		oldSchemaDDL: []string{`
+            CREATE TABLE foobar(
+                foo TEXT,
+                bar INT
+            );
+            CREATE INDEX some_idx_with_a_very_long_name ON foobar(foo);
+            CREATE INDEX old_idx ON foobar(bar);
+		`},
+		newSchemaDDL: []string{`
+            CREATE TABLE foobar(
+                foo TEXT,
+                bar INT
+            );
+            CREATE INDEX some_idx_with_a_very_long_name ON foobar(foo, bar);
+            CREATE INDEX new_idx ON foobar(bar);
+		`},
+		expectedPlanDDL: []string{
+			"ALTER INDEX \"public\".\"some_idx_with_a_very_long_name\" RENAME TO \"pgschemadiff_tmpidx_some_idx_with_a_very_EBESExQVRheYGRobHB0eHw\"",
+			"CREATE INDEX CONCURRENTLY new_idx ON public.foobar USING btree (bar)",
+			"CREATE INDEX CONCURRENTLY some_idx_with_a_very_long_name ON public.foobar USING btree (foo, bar)",
+			"DROP INDEX CONCURRENTLY \"public\".\"old_idx\"",
+			"DROP INDEX CONCURRENTLY \"public\".\"pgschemadiff_tmpidx_some_idx_with_a_very_EBESExQVRheYGRobHB0eHw\"",
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+	{
+		name: "Add a GiST exclusion constraint",
+		oldSchemaDDL: []string{`
+            CREATE TABLE reservations(
+                room INT,
+                during TSRANGE
+            );
+		`},
+		newSchemaDDL: []string{`
+            CREATE TABLE reservations(
+                room INT,
+                during TSRANGE,
+                CONSTRAINT reservations_during_excl EXCLUDE USING gist (during WITH &&)
+            );
+		`},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+	},
+	{
+		name: "Add an SP-GiST exclusion constraint with a predicate",
+		oldSchemaDDL: []string{`
+            CREATE TABLE reservations(
+                room INT,
+                during TSRANGE
+            );
+		`},
+		newSchemaDDL: []string{`
+            CREATE TABLE reservations(
+                room INT,
+                during TSRANGE,
+                CONSTRAINT reservations_during_excl EXCLUDE USING spgist (during WITH &&) WHERE (room > 0)
+            );
+		`},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+	},
+	{
+		name: "Create a table with exclusion constraints",
+		newSchemaDDL: []string{`
+            CREATE TABLE reservations(
+                room INT,
+                during TSRANGE,
+                CONSTRAINT reservations_during_excl EXCLUDE USING gist (during WITH &&),
+                CONSTRAINT reservations_during_spgist_excl EXCLUDE USING spgist (during WITH &&)
+            );
+		`},
+	},
+	{
+		name: "Change an exclusion constraint operator",
+		oldSchemaDDL: []string{`
+            CREATE TABLE reservations(
+                room INT,
+                during TSRANGE,
+                CONSTRAINT reservations_during_excl EXCLUDE USING gist (during WITH &&)
+            );
+		`},
+		newSchemaDDL: []string{`
+            CREATE TABLE reservations(
+                room INT,
+                during TSRANGE,
+                CONSTRAINT reservations_during_excl EXCLUDE USING gist (during WITH =)
+            );
+		`},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+	{
+		name: "Delete an exclusion constraint",
+		oldSchemaDDL: []string{`
+            CREATE TABLE reservations(
+                room INT,
+                during TSRANGE,
+                CONSTRAINT reservations_during_excl EXCLUDE USING spgist (during WITH &&)
+            );
+		`},
+		newSchemaDDL: []string{`
+            CREATE TABLE reservations(
+                room INT,
+                during TSRANGE
+            );
+		`},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"reservations\" DROP CONSTRAINT \"reservations_during_excl\"",
 		},
 	},
 }
@@ -321,6 +321,7 @@ var localPartitionIndexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexDropped,
 		},
 	},
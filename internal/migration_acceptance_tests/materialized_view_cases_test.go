@@ -0,0 +1,203 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var materializedViewAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "No-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE MATERIALIZED VIEW foobar_mv WITH (fillfactor = 70) AS SELECT id, val FROM foobar;
+            CREATE UNIQUE INDEX foobar_mv_id_idx ON foobar_mv(id);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE MATERIALIZED VIEW foobar_mv WITH (fillfactor = 70) AS SELECT id, val FROM foobar;
+            CREATE UNIQUE INDEX foobar_mv_id_idx ON foobar_mv(id);
+			`,
+		},
+
+		expectEmptyPlan: true,
+	},
+	{
+		name: "Create materialized view",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE MATERIALIZED VIEW foobar_mv AS SELECT id, val FROM foobar;
+            CREATE UNIQUE INDEX foobar_mv_id_idx ON foobar_mv(id);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name:         "Create materialized view with its tables and dependent materialized views",
+		oldSchemaDDL: nil,
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TABLE schema_1.foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE VIEW schema_1.foobar_view AS SELECT id, val FROM schema_1.foobar;
+            CREATE MATERIALIZED VIEW schema_1.foobar_mv AS SELECT id, val FROM schema_1.foobar_view;
+            CREATE MATERIALIZED VIEW schema_1.foobar_mv_count AS SELECT COUNT(*) AS cnt FROM schema_1.foobar_mv;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "Drop materialized view",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE MATERIALIZED VIEW foobar_mv AS SELECT id, val FROM foobar;
+            CREATE UNIQUE INDEX foobar_mv_id_idx ON foobar_mv(id);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+	{
+		name: "Drop materialized view and the table it depends on",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE MATERIALIZED VIEW foobar_mv AS SELECT id, val FROM foobar;
+            CREATE MATERIALIZED VIEW foobar_mv_count AS SELECT COUNT(*) AS cnt FROM foobar_mv;
+			`,
+		},
+		newSchemaDDL: nil,
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+	{
+		name: "Alter definition (re-created and refreshed)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE MATERIALIZED VIEW foobar_mv AS SELECT id, val FROM foobar;
+            CREATE UNIQUE INDEX foobar_mv_id_idx ON foobar_mv(id);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE MATERIALIZED VIEW foobar_mv AS SELECT id, val FROM foobar WHERE val IS NOT NULL;
+            CREATE UNIQUE INDEX foobar_mv_id_idx ON foobar_mv(id);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "Alter definition and drop the column it used",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE MATERIALIZED VIEW foobar_mv AS SELECT id, val FROM foobar;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+            CREATE MATERIALIZED VIEW foobar_mv AS SELECT id FROM foobar;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "Alter storage parameters and indexes",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE MATERIALIZED VIEW foobar_mv WITH (fillfactor = 70, autovacuum_enabled = false) AS SELECT id, val FROM foobar;
+            CREATE UNIQUE INDEX foobar_mv_id_idx ON foobar_mv(id);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE MATERIALIZED VIEW foobar_mv WITH (fillfactor = 80) AS SELECT id, val FROM foobar;
+            CREATE INDEX foobar_mv_val_idx ON foobar_mv(val);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+		expectedPlanDDL: []string{
+			"ALTER MATERIALIZED VIEW \"public\".\"foobar_mv\" SET (fillfactor=80)",
+			"ALTER MATERIALIZED VIEW \"public\".\"foobar_mv\" RESET (autovacuum_enabled)",
+			"DROP INDEX CONCURRENTLY \"public\".\"foobar_mv_id_idx\"",
+			"CREATE INDEX CONCURRENTLY foobar_mv_val_idx ON public.foobar_mv USING btree (val)",
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestMaterializedViewTestCases() {
+	suite.runTestCases(materializedViewAcceptanceTestCases)
+}
@@ -1,5 +1,9 @@
 package migration_acceptance_tests
 
+import (
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+)
+
 var namedSchemaAcceptanceTestCases = []acceptanceTestCase{
 	{
 		name: "no op",
@@ -33,6 +37,50 @@ var namedSchemaAcceptanceTestCases = []acceptanceTestCase{
             CREATE SCHEMA "schema 1";    
 		`},
 	},
+	{
+		name: "Drop non-empty schema",
+		oldSchemaDDL: []string{`
+            CREATE SCHEMA "schema 1";
+            CREATE TABLE "schema 1".foobar(id INT PRIMARY KEY);
+		`},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:  "Create schema owned by a role",
+		roles: []string{"role_1"},
+		newSchemaDDL: []string{`
+            CREATE SCHEMA "schema 1" AUTHORIZATION role_1;
+            CREATE TABLE "schema 1".foobar(id INT PRIMARY KEY);
+            ALTER TABLE "schema 1".foobar OWNER TO role_1;
+		`},
+		planOpts: []diff.PlanOpt{
+			diff.WithOwners(),
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:  "Transfer schema ownership",
+		roles: []string{"role_1", "role_2"},
+		oldSchemaDDL: []string{`
+            CREATE SCHEMA "schema 1" AUTHORIZATION role_1;
+            CREATE SCHEMA "schema 2" AUTHORIZATION role_2;
+		`},
+		newSchemaDDL: []string{`
+            CREATE SCHEMA "schema 1" AUTHORIZATION role_2;
+            CREATE SCHEMA "schema 2" AUTHORIZATION role_2;
+		`},
+		planOpts: []diff.PlanOpt{
+			diff.WithOwners(),
+		},
+		expectedPlanDDL: []string{
+			"ALTER SCHEMA \"schema 1\" OWNER TO \"role_2\"",
+		},
+	},
 }
 
 func (suite *acceptanceTestSuite) TestNamedSchemaTestCases() {
@@ -0,0 +1,98 @@
+package migration_acceptance_tests
+
+import (
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+)
+
+var onlineColumnTypeChangeAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "Change column type via shadow column",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val VARCHAR(50) NOT NULL DEFAULT 'some default',
+                other_val INT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT NOT NULL DEFAULT 'some default',
+                other_val BIGINT
+            );
+			`,
+		},
+		planOpts: []diff.PlanOpt{diff.WithOnlineColumnTypeChange()},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeColumnOrderChange,
+			diff.MigrationHazardTypeCorrectness,
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+	{
+		name: "Change column type with mutable cast",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                created_at TIMESTAMP
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                created_at TIMESTAMPTZ
+            );
+			`,
+		},
+		planOpts: []diff.PlanOpt{diff.WithOnlineColumnTypeChange()},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeImpossibleWithoutDowntime,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"created_at\" SET DATA TYPE timestamp with time zone using \"created_at\"::timestamp with time zone",
+			"ANALYZE \"public\".\"foobar\" (\"created_at\")",
+			"ANALYZE \"public\".\"foobar\"",
+		},
+	},
+	{
+		name: "Change type of indexed column",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val VARCHAR(50)
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val);
+			`,
+		},
+		planOpts: []diff.PlanOpt{diff.WithOnlineColumnTypeChange()},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeImpossibleWithoutDowntime,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestOnlineColumnTypeChangeTestCases() {
+	suite.runTestCases(onlineColumnTypeChangeAcceptanceTestCases)
+}
@@ -0,0 +1,322 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var operatorAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "No-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION abs_eq(a INT, b INT) RETURNS BOOLEAN AS $$
+                SELECT abs(a) = abs(b)
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION abs_hash(a INT) RETURNS INT AS $$
+                SELECT hashint4(abs(a))
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR |=| (LEFTARG = INT, RIGHTARG = INT, FUNCTION = abs_eq, COMMUTATOR = |=|);
+
+            CREATE OPERATOR CLASS abs_int_ops FOR TYPE INT USING hash AS
+                OPERATOR 1 |=|,
+                FUNCTION 1 abs_hash(INT);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION abs_eq(a INT, b INT) RETURNS BOOLEAN AS $$
+                SELECT abs(a) = abs(b)
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION abs_hash(a INT) RETURNS INT AS $$
+                SELECT hashint4(abs(a))
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR |=| (LEFTARG = INT, RIGHTARG = INT, FUNCTION = abs_eq, COMMUTATOR = |=|);
+
+            CREATE OPERATOR CLASS abs_int_ops FOR TYPE INT USING hash AS
+                OPERATOR 1 |=|,
+                FUNCTION 1 abs_hash(INT);
+			`,
+		},
+
+		expectEmptyPlan: true,
+	},
+	{
+		name: "Create operator and the function it depends on",
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE FUNCTION schema_1.text_concat(a TEXT, b TEXT) RETURNS TEXT AS $$
+                SELECT a || b
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR schema_1.// (LEFTARG = TEXT, RIGHTARG = TEXT, FUNCTION = schema_1.text_concat);
+			`,
+		},
+	},
+	{
+		name: "Create prefix operator",
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION negate_int(a INT) RETURNS INT AS $$
+                SELECT -a
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR ~~~ (RIGHTARG = INT, FUNCTION = negate_int);
+			`,
+		},
+	},
+	{
+		name: "Create overloaded operators",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION text_concat(a TEXT, b TEXT) RETURNS TEXT AS $$
+                SELECT a || b
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR // (LEFTARG = TEXT, RIGHTARG = TEXT, FUNCTION = text_concat);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION text_concat(a TEXT, b TEXT) RETURNS TEXT AS $$
+                SELECT a || b
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION int_concat(a INT, b INT) RETURNS TEXT AS $$
+                SELECT a::TEXT || b::TEXT
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR // (LEFTARG = TEXT, RIGHTARG = TEXT, FUNCTION = text_concat);
+            CREATE OPERATOR // (LEFTARG = INT, RIGHTARG = INT, FUNCTION = int_concat);
+			`,
+		},
+	},
+	{
+		name: "Change operator function (re-creates operator)",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION text_concat(a TEXT, b TEXT) RETURNS TEXT AS $$
+                SELECT a || b
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR // (LEFTARG = TEXT, RIGHTARG = TEXT, FUNCTION = text_concat);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION text_concat_with_space(a TEXT, b TEXT) RETURNS TEXT AS $$
+                SELECT a || ' ' || b
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR // (LEFTARG = TEXT, RIGHTARG = TEXT, FUNCTION = text_concat_with_space);
+			`,
+		},
+	},
+	{
+		name: "Drop operator and the function it depends on",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION text_concat(a TEXT, b TEXT) RETURNS TEXT AS $$
+                SELECT a || b
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR // (LEFTARG = TEXT, RIGHTARG = TEXT, FUNCTION = text_concat);
+			`,
+		},
+		newSchemaDDL: nil,
+	},
+	{
+		name: "Create operator class and the operators it depends on",
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION abs_eq(a INT, b INT) RETURNS BOOLEAN AS $$
+                SELECT abs(a) = abs(b)
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION abs_hash(a INT) RETURNS INT AS $$
+                SELECT hashint4(abs(a))
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR |=| (LEFTARG = INT, RIGHTARG = INT, FUNCTION = abs_eq, COMMUTATOR = |=|);
+
+            CREATE OPERATOR CLASS abs_int_ops FOR TYPE INT USING hash AS
+                OPERATOR 1 |=|,
+                FUNCTION 1 abs_hash(INT);
+			`,
+		},
+	},
+	{
+		name: "Re-create operator that an operator class depends on",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION abs_eq(a INT, b INT) RETURNS BOOLEAN AS $$
+                SELECT abs(a) = abs(b)
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION abs_hash(a INT) RETURNS INT AS $$
+                SELECT hashint4(abs(a))
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR |=| (LEFTARG = INT, RIGHTARG = INT, FUNCTION = abs_eq);
+
+            CREATE OPERATOR CLASS abs_int_ops FOR TYPE INT USING hash AS
+                OPERATOR 1 |=|,
+                FUNCTION 1 abs_hash(INT);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION abs_eq(a INT, b INT) RETURNS BOOLEAN AS $$
+                SELECT abs(a) = abs(b)
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION abs_hash(a INT) RETURNS INT AS $$
+                SELECT hashint4(abs(a))
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR |=| (LEFTARG = INT, RIGHTARG = INT, FUNCTION = abs_eq, COMMUTATOR = |=|);
+
+            CREATE OPERATOR CLASS abs_int_ops FOR TYPE INT USING hash AS
+                OPERATOR 1 |=|,
+                FUNCTION 1 abs_hash(INT);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "Drop operator class and the operators it depends on",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION abs_eq(a INT, b INT) RETURNS BOOLEAN AS $$
+                SELECT abs(a) = abs(b)
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION abs_hash(a INT) RETURNS INT AS $$
+                SELECT hashint4(abs(a))
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR |=| (LEFTARG = INT, RIGHTARG = INT, FUNCTION = abs_eq, COMMUTATOR = |=|);
+
+            CREATE OPERATOR CLASS abs_int_ops FOR TYPE INT USING hash AS
+                OPERATOR 1 |=|,
+                FUNCTION 1 abs_hash(INT);
+			`,
+		},
+		newSchemaDDL: nil,
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "Create operator class and the indexes that use it",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                val INT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION abs_eq(a INT, b INT) RETURNS BOOLEAN AS $$
+                SELECT abs(a) = abs(b)
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION abs_hash(a INT) RETURNS INT AS $$
+                SELECT hashint4(abs(a))
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR |=| (LEFTARG = INT, RIGHTARG = INT, FUNCTION = abs_eq, COMMUTATOR = |=|);
+
+            CREATE OPERATOR CLASS abs_int_ops FOR TYPE INT USING hash AS
+                OPERATOR 1 |=|,
+                FUNCTION 1 abs_hash(INT);
+
+            CREATE TABLE foo(
+                val INT
+            );
+            CREATE INDEX foo_val_idx ON foo USING hash (val abs_int_ops);
+
+            CREATE TABLE bar(
+                val INT
+            );
+            CREATE INDEX bar_val_idx ON bar USING hash (val abs_int_ops);
+
+            CREATE MATERIALIZED VIEW foo_mv AS SELECT val FROM foo;
+            CREATE INDEX foo_mv_val_idx ON foo_mv USING hash (val abs_int_ops);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Re-create operator class used by indexes",
+		oldSchemaDDL: []string{
+			`
+            CREATE FUNCTION abs_eq(a INT, b INT) RETURNS BOOLEAN AS $$
+                SELECT abs(a) = abs(b)
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION abs_hash(a INT) RETURNS INT AS $$
+                SELECT hashint4(abs(a))
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR |=| (LEFTARG = INT, RIGHTARG = INT, FUNCTION = abs_eq);
+
+            CREATE OPERATOR CLASS abs_int_ops FOR TYPE INT USING hash AS
+                OPERATOR 1 |=|,
+                FUNCTION 1 abs_hash(INT);
+
+            CREATE TABLE foo(
+                val INT
+            );
+            CREATE INDEX foo_val_idx ON foo USING hash (val abs_int_ops);
+
+            CREATE MATERIALIZED VIEW foo_mv AS SELECT val FROM foo;
+            CREATE INDEX foo_mv_val_idx ON foo_mv USING hash (val abs_int_ops);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE FUNCTION abs_eq(a INT, b INT) RETURNS BOOLEAN AS $$
+                SELECT abs(a) = abs(b)
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE FUNCTION abs_hash(a INT) RETURNS INT AS $$
+                SELECT hashint4(abs(a))
+            $$ LANGUAGE SQL IMMUTABLE;
+
+            CREATE OPERATOR |=| (LEFTARG = INT, RIGHTARG = INT, FUNCTION = abs_eq, COMMUTATOR = |=|);
+
+            CREATE OPERATOR CLASS abs_int_ops FOR TYPE INT USING hash AS
+                OPERATOR 1 |=|,
+                FUNCTION 1 abs_hash(INT);
+
+            CREATE TABLE foo(
+                val INT
+            );
+            CREATE INDEX foo_val_idx ON foo USING hash (val abs_int_ops);
+
+            CREATE MATERIALIZED VIEW foo_mv AS SELECT val FROM foo;
+            CREATE INDEX foo_mv_val_idx ON foo_mv USING hash (val abs_int_ops);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeIndexDropped,
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeImpactsDatabasePerformance,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestOperatorTestCases() {
+	suite.runTestCases(operatorAcceptanceTestCases)
+}
@@ -0,0 +1,180 @@
+package migration_acceptance_tests
+
+import (
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+)
+
+var ownerAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name:  "No-op",
+		roles: []string{"role_1"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE VIEW foobar_view AS SELECT id FROM foobar;
+            CREATE SEQUENCE foobar_seq;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+
+            ALTER TABLE foobar OWNER TO role_1;
+            ALTER VIEW foobar_view OWNER TO role_1;
+            ALTER SEQUENCE foobar_seq OWNER TO role_1;
+            ALTER FUNCTION add(integer, integer) OWNER TO role_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE VIEW foobar_view AS SELECT id FROM foobar;
+            CREATE SEQUENCE foobar_seq;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+
+            ALTER TABLE foobar OWNER TO role_1;
+            ALTER VIEW foobar_view OWNER TO role_1;
+            ALTER SEQUENCE foobar_seq OWNER TO role_1;
+            ALTER FUNCTION add(integer, integer) OWNER TO role_1;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithOwners(),
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name:  "Transfer ownership to a role that owns other objects",
+		roles: []string{"role_1", "role_2"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE VIEW foobar_view AS SELECT id FROM foobar;
+            CREATE SEQUENCE foobar_seq;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+            CREATE TABLE other(id INT PRIMARY KEY);
+
+            ALTER TABLE foobar OWNER TO role_1;
+            ALTER VIEW foobar_view OWNER TO role_1;
+            ALTER SEQUENCE foobar_seq OWNER TO role_1;
+            ALTER FUNCTION add(integer, integer) OWNER TO role_1;
+            ALTER TABLE other OWNER TO role_2;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE VIEW foobar_view AS SELECT id FROM foobar;
+            CREATE SEQUENCE foobar_seq;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+            CREATE TABLE other(id INT PRIMARY KEY);
+
+            ALTER TABLE foobar OWNER TO role_2;
+            ALTER VIEW foobar_view OWNER TO role_2;
+            ALTER SEQUENCE foobar_seq OWNER TO role_2;
+            ALTER FUNCTION add(integer, integer) OWNER TO role_2;
+            ALTER TABLE other OWNER TO role_2;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithOwners(),
+		},
+	},
+	{
+		name:  "Transfer ownership to a role that owns no objects",
+		roles: []string{"role_1", "role_2"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE VIEW foobar_view AS SELECT id FROM foobar;
+            CREATE SEQUENCE foobar_seq;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+
+            ALTER TABLE foobar OWNER TO role_1;
+            ALTER VIEW foobar_view OWNER TO role_1;
+            ALTER SEQUENCE foobar_seq OWNER TO role_1;
+            ALTER FUNCTION add(integer, integer) OWNER TO role_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE VIEW foobar_view AS SELECT id FROM foobar;
+            CREATE SEQUENCE foobar_seq;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+
+            ALTER TABLE foobar OWNER TO role_2;
+            ALTER VIEW foobar_view OWNER TO role_2;
+            ALTER SEQUENCE foobar_seq OWNER TO role_2;
+            ALTER FUNCTION add(integer, integer) OWNER TO role_2;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithOwners(),
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:  "Create objects owned by a role",
+		roles: []string{"role_1"},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id SERIAL PRIMARY KEY);
+            CREATE VIEW foobar_view AS SELECT id FROM foobar;
+            CREATE SEQUENCE foobar_seq;
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                IMMUTABLE
+                RETURN a + b;
+
+            ALTER TABLE foobar OWNER TO role_1;
+            ALTER VIEW foobar_view OWNER TO role_1;
+            ALTER SEQUENCE foobar_seq OWNER TO role_1;
+            ALTER FUNCTION add(integer, integer) OWNER TO role_1;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithOwners(),
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:  "Ownership is not diffed without the option",
+		roles: []string{"role_1", "role_2"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            ALTER TABLE foobar OWNER TO role_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            ALTER TABLE foobar OWNER TO role_2;
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+}
+
+func (suite *acceptanceTestSuite) TestOwnerTestCases() {
+	suite.runTestCases(ownerAcceptanceTestCases)
+}
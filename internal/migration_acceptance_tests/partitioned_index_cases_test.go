@@ -227,6 +227,7 @@ var partitionedIndexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -328,6 +329,7 @@ var partitionedIndexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -374,6 +376,7 @@ var partitionedIndexAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -466,6 +469,7 @@ var partitionedIndexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -563,6 +567,7 @@ var partitionedIndexAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexDropped,
 		},
 	},
@@ -631,6 +636,7 @@ var partitionedIndexAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 		},
 		expectedPlanDDL: []string{
 			"DROP INDEX CONCURRENTLY \"public\".\"foobar_1_some_local_idx\"",
@@ -638,6 +644,7 @@ var partitionedIndexAcceptanceTestCases = []acceptanceTestCase{
 			"ALTER TABLE \"public\".\"foobar\" DROP COLUMN \"id\"",
 			"DROP TABLE \"schema_1\".\"foobar\"",
 			"DROP SCHEMA \"schema_1\"",
+			"ANALYZE \"public\".\"foobar\"",
 		},
 	},
 	{
@@ -676,6 +683,7 @@ var partitionedIndexAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 		expectedPlanDDL: []string{
 			"ALTER INDEX \"public\".\"some_idx\" RENAME TO \"pgschemadiff_tmpidx_some_idx_MDEyMzQ1Rje4OTo7PD0$Pw\"",
@@ -768,6 +776,7 @@ var partitionedIndexAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -903,6 +912,7 @@ var partitionedIndexAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
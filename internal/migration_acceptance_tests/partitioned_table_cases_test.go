@@ -197,6 +197,9 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
             ALTER TABLE "FOOBAR_1" ADD CONSTRAINT foobar_1_fk FOREIGN KEY (foo) REFERENCES foobar_fk_1(foo);
 		`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Drop table",
@@ -249,6 +252,7 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 		},
 		newSchemaDDL: nil,
 	},
@@ -277,6 +281,7 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 		},
 	},
 	{
@@ -483,6 +488,7 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeImpactsDatabasePerformance,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -601,6 +607,7 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 		},
 	},
 	{
@@ -719,6 +726,7 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 		},
 	},
 	{
@@ -807,6 +815,7 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 		},
 	},
 	{
@@ -921,6 +930,8 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -994,6 +1005,9 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
             ALTER TABLE schema_2.foobar_1 ADD CONSTRAINT foobar_1_fk FOREIGN KEY (foo, id) REFERENCES foobar_fk_1(foo, id);
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name: "Adding a partition with local primary key that can back the unique index",
@@ -1096,7 +1110,7 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
 		},
 	},
 	{
-		name: "Deleting a partitioning errors",
+		name: "Deleting a partition",
 		oldSchemaDDL: []string{
 			`
             CREATE TABLE foobar(
@@ -1121,11 +1135,16 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
             ) PARTITION BY LIST (foo);
 			`,
 		},
-
-		expectedPlanErrorIs: diff.ErrNotImplemented,
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" DETACH PARTITION \"public\".\"foobar_1\" CONCURRENTLY",
+			"DROP TABLE \"public\".\"foobar_1\"",
+		},
 	},
 	{
-		name: "Altering a partition's 'FOR VALUES' errors",
+		name: "Altering a partition's 'FOR VALUES'",
 		oldSchemaDDL: []string{
 			`
             CREATE TABLE foobar(
@@ -1150,8 +1169,281 @@ var partitionedTableAcceptanceTestCases = []acceptanceTestCase{
             CREATE TABLE foobar_1 PARTITION OF foobar FOR VALUES IN ('foo_2');
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" DETACH PARTITION \"public\".\"foobar_1\"",
+			"ALTER TABLE \"public\".\"foobar\" ATTACH PARTITION \"public\".\"foobar_1\" FOR VALUES IN ('foo_2')",
+		},
+	},
+	{
+		name:         "Create range partitioned table",
+		oldSchemaDDL: nil,
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE events(
+                id INT,
+                created_at TIMESTAMPTZ NOT NULL,
+                payload TEXT,
+                PRIMARY KEY (id, created_at)
+            ) PARTITION BY RANGE (created_at);
+
+            CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+            CREATE TABLE events_2025 PARTITION OF events FOR VALUES FROM ('2025-01-01') TO ('2026-01-01');
+            CREATE TABLE events_default PARTITION OF events DEFAULT;
+            CREATE INDEX events_payload_idx ON events(payload);
+			`,
+		},
+	},
+	{
+		name: "Add range partition",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE events(
+                id INT,
+                created_at TIMESTAMPTZ NOT NULL,
+                payload TEXT,
+                PRIMARY KEY (id, created_at)
+            ) PARTITION BY RANGE (created_at);
 
-		expectedPlanErrorIs: diff.ErrNotImplemented,
+            CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+            CREATE INDEX events_payload_idx ON events(payload);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE events(
+                id INT,
+                created_at TIMESTAMPTZ NOT NULL,
+                payload TEXT,
+                PRIMARY KEY (id, created_at)
+            ) PARTITION BY RANGE (created_at);
+
+            CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+            CREATE TABLE events_2025 PARTITION OF events FOR VALUES FROM ('2025-01-01') TO ('2026-01-01');
+            CREATE INDEX events_payload_idx ON events(payload);
+			`,
+		},
+	},
+	{
+		name: "Split range partition",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE events(
+                id INT,
+                created_at TIMESTAMPTZ NOT NULL,
+                payload TEXT,
+                PRIMARY KEY (id, created_at)
+            ) PARTITION BY RANGE (created_at);
+
+            CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2026-01-01');
+            CREATE INDEX events_payload_idx ON events(payload);
+            CREATE INDEX events_2024_local_idx ON events_2024(id);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE events(
+                id INT,
+                created_at TIMESTAMPTZ NOT NULL,
+                payload TEXT,
+                PRIMARY KEY (id, created_at)
+            ) PARTITION BY RANGE (created_at);
+
+            CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+            CREATE TABLE events_2025 PARTITION OF events FOR VALUES FROM ('2025-01-01') TO ('2026-01-01');
+            CREATE INDEX events_payload_idx ON events(payload);
+            CREATE INDEX events_2024_local_idx ON events_2024(id);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+	},
+	{
+		name: "Merge range partitions",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE events(
+                id INT,
+                created_at TIMESTAMPTZ NOT NULL,
+                payload TEXT,
+                PRIMARY KEY (id, created_at)
+            ) PARTITION BY RANGE (created_at);
+
+            CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+            CREATE TABLE events_2025 PARTITION OF events FOR VALUES FROM ('2025-01-01') TO ('2026-01-01');
+            CREATE INDEX events_payload_idx ON events(payload);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE events(
+                id INT,
+                created_at TIMESTAMPTZ NOT NULL,
+                payload TEXT,
+                PRIMARY KEY (id, created_at)
+            ) PARTITION BY RANGE (created_at);
+
+            CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2026-01-01');
+            CREATE INDEX events_payload_idx ON events(payload);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+	{
+		name: "Delete range partition with a default partition",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE events(
+                id INT,
+                created_at TIMESTAMPTZ NOT NULL,
+                payload TEXT
+            ) PARTITION BY RANGE (created_at);
+
+            CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+            CREATE TABLE events_default PARTITION OF events DEFAULT;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE events(
+                id INT,
+                created_at TIMESTAMPTZ NOT NULL,
+                payload TEXT
+            ) PARTITION BY RANGE (created_at);
+
+            CREATE TABLE events_default PARTITION OF events DEFAULT;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeDeletesData,
+		},
+		expectedPlanDDL: []string{
+			"DROP TABLE \"public\".\"events_2024\"",
+		},
+	},
+	{
+		name:         "Create list and hash partitioned tables",
+		oldSchemaDDL: nil,
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE orders(
+                id INT,
+                region TEXT,
+                PRIMARY KEY (id, region)
+            ) PARTITION BY LIST (region);
+            CREATE TABLE orders_us PARTITION OF orders FOR VALUES IN ('us-east', 'us-west');
+            CREATE TABLE orders_eu PARTITION OF orders FOR VALUES IN ('eu-west');
+            CREATE TABLE orders_default PARTITION OF orders DEFAULT;
+
+            CREATE TABLE customers(
+                id INT,
+                region TEXT
+            ) PARTITION BY LIST (region);
+            CREATE TABLE customers_unknown PARTITION OF customers FOR VALUES IN (NULL, 'unknown');
+            CREATE TABLE customers_known PARTITION OF customers FOR VALUES IN ('us', 'eu');
+
+            CREATE TABLE sessions(
+                id INT PRIMARY KEY,
+                payload TEXT
+            ) PARTITION BY HASH (id);
+            CREATE TABLE sessions_0 PARTITION OF sessions FOR VALUES WITH (MODULUS 2, REMAINDER 0);
+            CREATE TABLE sessions_1 PARTITION OF sessions FOR VALUES WITH (MODULUS 2, REMAINDER 1);
+			`,
+		},
+	},
+	{
+		name: "Add value to list partition with a default partition",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE orders(
+                id INT,
+                region TEXT,
+                PRIMARY KEY (id, region)
+            ) PARTITION BY LIST (region);
+            CREATE TABLE orders_us PARTITION OF orders FOR VALUES IN ('us-east');
+            CREATE TABLE orders_default PARTITION OF orders DEFAULT;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE orders(
+                id INT,
+                region TEXT,
+                PRIMARY KEY (id, region)
+            ) PARTITION BY LIST (region);
+            CREATE TABLE orders_us PARTITION OF orders FOR VALUES IN ('us-east', 'us-west');
+            CREATE TABLE orders_default PARTITION OF orders DEFAULT;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"orders\" DETACH PARTITION \"public\".\"orders_us\"",
+			"ALTER TABLE \"public\".\"orders\" ATTACH PARTITION \"public\".\"orders_us\" FOR VALUES IN ('us-east', 'us-west')",
+		},
+	},
+	{
+		name: "Add NULL-accepting list partition with a default partition",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE customers(
+                id INT,
+                region TEXT
+            ) PARTITION BY LIST (region);
+            CREATE TABLE customers_known PARTITION OF customers FOR VALUES IN ('us', 'eu');
+            CREATE TABLE customers_default PARTITION OF customers DEFAULT;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE customers(
+                id INT,
+                region TEXT
+            ) PARTITION BY LIST (region);
+            CREATE TABLE customers_known PARTITION OF customers FOR VALUES IN ('us', 'eu');
+            CREATE TABLE customers_unknown PARTITION OF customers FOR VALUES IN (NULL);
+            CREATE TABLE customers_default PARTITION OF customers DEFAULT;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+		},
+	},
+	{
+		name: "Change hash partition modulus",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE sessions(
+                id INT PRIMARY KEY,
+                payload TEXT
+            ) PARTITION BY HASH (id);
+            CREATE TABLE sessions_0 PARTITION OF sessions FOR VALUES WITH (MODULUS 2, REMAINDER 0);
+            CREATE TABLE sessions_1 PARTITION OF sessions FOR VALUES WITH (MODULUS 2, REMAINDER 1);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE sessions(
+                id INT PRIMARY KEY,
+                payload TEXT
+            ) PARTITION BY HASH (id);
+            CREATE TABLE sessions_0 PARTITION OF sessions FOR VALUES WITH (MODULUS 4, REMAINDER 0);
+            CREATE TABLE sessions_1 PARTITION OF sessions FOR VALUES WITH (MODULUS 2, REMAINDER 1);
+            CREATE TABLE sessions_2 PARTITION OF sessions FOR VALUES WITH (MODULUS 4, REMAINDER 2);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeCorrectness,
+		},
 	},
 	{
 		name: "Re-creating base table causes partitions to be re-created",
@@ -533,6 +533,7 @@ var policyAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAuthzUpdate,
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -570,6 +571,7 @@ var policyAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAuthzUpdate,
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
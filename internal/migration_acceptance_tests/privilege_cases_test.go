@@ -0,0 +1,197 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var privilegeAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name:  "no-op",
+		roles: []string{"role_1", "role_2"},
+		planOpts: []diff.PlanOpt{
+			diff.WithPrivileges(),
+		},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                id INT PRIMARY KEY
+            );
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                RETURN a + b;
+            GRANT SELECT, INSERT ON foo TO role_1;
+            GRANT SELECT ON foo TO role_2 WITH GRANT OPTION;
+            REVOKE EXECUTE ON FUNCTION add(integer, integer) FROM PUBLIC;
+            GRANT EXECUTE ON FUNCTION add(integer, integer) TO role_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                id INT PRIMARY KEY
+            );
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                RETURN a + b;
+            GRANT SELECT, INSERT ON foo TO role_1;
+            GRANT SELECT ON foo TO role_2 WITH GRANT OPTION;
+            REVOKE EXECUTE ON FUNCTION add(integer, integer) FROM PUBLIC;
+            GRANT EXECUTE ON FUNCTION add(integer, integer) TO role_1;
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name:  "privileges are not diffed without WithPrivileges",
+		roles: []string{"role_1"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                id INT PRIMARY KEY
+            );
+            GRANT SELECT, INSERT ON foo TO role_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                id INT PRIMARY KEY
+            );
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name:  "grant privileges on new objects",
+		roles: []string{"role_1"},
+		planOpts: []diff.PlanOpt{
+			diff.WithPrivileges(),
+		},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE bar();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TABLE schema_1.foo(
+                id SERIAL PRIMARY KEY
+            );
+            CREATE VIEW schema_1.foo_view AS SELECT id FROM schema_1.foo;
+            CREATE FUNCTION schema_1.add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                RETURN a + b;
+            GRANT SELECT, UPDATE ON schema_1.foo TO role_1;
+            GRANT USAGE ON SEQUENCE schema_1.foo_id_seq TO role_1;
+            GRANT SELECT ON schema_1.foo_view TO PUBLIC;
+            REVOKE EXECUTE ON FUNCTION schema_1.add(integer, integer) FROM PUBLIC;
+            GRANT EXECUTE ON FUNCTION schema_1.add(integer, integer) TO role_1;
+            CREATE TABLE bar();
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAuthzUpdate,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:  "grant, alter, and revoke privileges on existing objects",
+		roles: []string{"role_1", "role_2"},
+		planOpts: []diff.PlanOpt{
+			diff.WithPrivileges(),
+		},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                id INT PRIMARY KEY
+            );
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                RETURN a + b;
+            GRANT SELECT, INSERT ON foo TO role_1;
+            GRANT SELECT ON foo TO role_2 WITH GRANT OPTION;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                id INT PRIMARY KEY
+            );
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                RETURN a + b;
+            GRANT SELECT, DELETE ON foo TO role_1;
+            GRANT SELECT ON foo TO role_2;
+            REVOKE EXECUTE ON FUNCTION add(integer, integer) FROM PUBLIC;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAuthzUpdate,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:  "re-grant privileges on re-created objects",
+		roles: []string{"role_1"},
+		planOpts: []diff.PlanOpt{
+			diff.WithPrivileges(),
+		},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                id INT
+            );
+            CREATE VIEW foo_view AS SELECT id FROM foo;
+            GRANT SELECT ON foo TO role_1;
+            GRANT SELECT ON foo_view TO role_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                id INT
+            ) PARTITION BY LIST (id);
+            CREATE VIEW foo_view AS SELECT id FROM foo WHERE id > 0;
+            GRANT SELECT ON foo TO role_1;
+            GRANT SELECT ON foo_view TO role_1;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAuthzUpdate,
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:  "drop objects with privileges",
+		roles: []string{"role_1"},
+		planOpts: []diff.PlanOpt{
+			diff.WithPrivileges(),
+		},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                id INT
+            );
+            CREATE FUNCTION add(a integer, b integer) RETURNS integer
+                LANGUAGE SQL
+                RETURN a + b;
+            GRANT SELECT ON foo TO role_1;
+            GRANT EXECUTE ON FUNCTION add(integer, integer) TO role_1;
+            CREATE TABLE bar();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE bar();
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestPrivilegeTestCases() {
+	suite.runTestCases(privilegeAcceptanceTestCases)
+}
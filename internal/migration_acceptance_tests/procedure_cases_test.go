@@ -61,7 +61,6 @@ var procedureAcceptanceTestCases = []acceptanceTestCase{
             $$;
 			`,
 		},
-		expectedHazardTypes: []diff.MigrationHazardType{diff.MigrationHazardTypeHasUntrackableDependencies},
 	},
 	{
 		name: "Alter a procedure to have dependencies that must be created",
@@ -91,7 +90,6 @@ var procedureAcceptanceTestCases = []acceptanceTestCase{
             $$;
 			`,
 		},
-		expectedHazardTypes: []diff.MigrationHazardType{diff.MigrationHazardTypeHasUntrackableDependencies},
 	},
 	{
 		name: "Drop procedure and its dependencies",
@@ -119,9 +117,127 @@ var procedureAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
-			diff.MigrationHazardTypeHasUntrackableDependencies,
 		},
 	},
+	{
+		name: "Create procedures that call other procedures and functions",
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TABLE schema_1.audit_log (
+                id SERIAL PRIMARY KEY,
+                message TEXT NOT NULL
+            );
+
+            CREATE FUNCTION schema_1.format_message(msg TEXT) RETURNS TEXT AS $$
+                SELECT 'audit: ' || msg
+            $$ LANGUAGE SQL;
+
+            CREATE PROCEDURE schema_1.log_message(msg TEXT)
+            LANGUAGE SQL
+            BEGIN ATOMIC
+                INSERT INTO schema_1.audit_log (message) VALUES (schema_1.format_message(msg));
+            END;
+
+            CREATE PROCEDURE schema_1.log_twice(msg TEXT)
+            LANGUAGE SQL
+            BEGIN ATOMIC
+                CALL schema_1.log_message(msg);
+                CALL schema_1.log_message(msg);
+            END;
+			`,
+		},
+	},
+	{
+		name: "Replace a procedure that is called by another procedure",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE audit_log (
+                id SERIAL PRIMARY KEY,
+                message TEXT NOT NULL
+            );
+
+            CREATE PROCEDURE log_message(msg TEXT)
+            LANGUAGE SQL
+            BEGIN ATOMIC
+                INSERT INTO audit_log (message) VALUES (msg);
+            END;
+
+            CREATE PROCEDURE log_twice(msg TEXT)
+            LANGUAGE SQL
+            BEGIN ATOMIC
+                CALL log_message(msg);
+                CALL log_message(msg);
+            END;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE audit_log (
+                id SERIAL PRIMARY KEY,
+                message TEXT NOT NULL
+            );
+
+            CREATE PROCEDURE log_message(msg TEXT)
+            LANGUAGE SQL
+            BEGIN ATOMIC
+                INSERT INTO audit_log (message) VALUES ('audit: ' || msg);
+            END;
+
+            CREATE PROCEDURE log_twice(msg TEXT)
+            LANGUAGE SQL
+            BEGIN ATOMIC
+                CALL log_message(msg);
+                CALL log_message(msg);
+            END;
+			`,
+		},
+	},
+	{
+		name: "Drop procedures that call other procedures",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE audit_log (
+                id SERIAL PRIMARY KEY,
+                message TEXT NOT NULL
+            );
+
+            CREATE PROCEDURE log_message(msg TEXT)
+            LANGUAGE SQL
+            BEGIN ATOMIC
+                INSERT INTO audit_log (message) VALUES (msg);
+            END;
+
+            CREATE PROCEDURE log_twice(msg TEXT)
+            LANGUAGE SQL
+            BEGIN ATOMIC
+                CALL log_message(msg);
+                CALL log_message(msg);
+            END;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE audit_log (
+                id SERIAL PRIMARY KEY,
+                message TEXT NOT NULL
+            );
+			`,
+		},
+	},
+	{
+		name: "Drop a plpgsql procedure",
+		oldSchemaDDL: []string{
+			`
+            CREATE OR REPLACE PROCEDURE some_procedure(i integer) AS $$
+                    BEGIN
+                            RAISE NOTICE 'foobar';
+                    END;
+            $$ LANGUAGE plpgsql;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{diff.MigrationHazardTypeHasUntrackableDependencies},
+	},
 	{
 		// This reveals Postgres does not actually track dependencies of procedures outside of creation time.
 		name: "Drop a procedure's dependencies but not the procedure",
@@ -0,0 +1,174 @@
+package migration_acceptance_tests
+
+import (
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+)
+
+var publicationAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "No-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE TABLE fizzbuzz(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar, fizzbuzz;
+            CREATE PUBLICATION all_tables_pub FOR ALL TABLES WITH (publish = 'insert, update');
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE TABLE fizzbuzz(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar, fizzbuzz;
+            CREATE PUBLICATION all_tables_pub FOR ALL TABLES WITH (publish = 'insert, update');
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name: "Create publications",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE TABLE fizzbuzz(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar, fizzbuzz;
+            CREATE PUBLICATION all_tables_pub FOR ALL TABLES;
+            CREATE PUBLICATION empty_pub WITH (publish = 'insert');
+			`,
+		},
+	},
+	{
+		name: "Drop publications",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar;
+            CREATE PUBLICATION all_tables_pub FOR ALL TABLES;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "Alter publication tables and published operations",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE TABLE fizzbuzz(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar, fizzbuzz;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE TABLE fizzbuzz(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar WITH (publish = 'insert, delete');
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"ALTER PUBLICATION \"foobar_pub\" DROP TABLE \"public\".\"fizzbuzz\"",
+			"ALTER PUBLICATION \"foobar_pub\" SET (publish = 'insert, delete')",
+		},
+	},
+	{
+		name: "Add new table to publication",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE TABLE new_table(id INT);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar, new_table;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "Drop table in publication",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE TABLE fizzbuzz(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar, fizzbuzz;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "Change publication from table list to all tables (re-creates publication)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR ALL TABLES;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"DROP PUBLICATION \"foobar_pub\"",
+			"CREATE PUBLICATION \"foobar_pub\" FOR ALL TABLES WITH (publish = 'insert, update, delete, truncate')",
+		},
+	},
+	{
+		name: "Change publication from all tables to table list (re-creates publication)",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR ALL TABLES;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            CREATE PUBLICATION foobar_pub FOR TABLE foobar;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"DROP PUBLICATION \"foobar_pub\"",
+			"CREATE PUBLICATION \"foobar_pub\" FOR TABLE \"public\".\"foobar\" WITH (publish = 'insert, update, delete, truncate')",
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestPublicationTestCases() {
+	suite.runTestCases(publicationAcceptanceTestCases)
+}
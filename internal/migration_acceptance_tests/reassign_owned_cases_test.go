@@ -0,0 +1,82 @@
+package migration_acceptance_tests
+
+import (
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+)
+
+var reassignOwnedAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name:  "Reassign objects owned by role",
+		roles: []string{"role_1", "role_2"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            ALTER TABLE foobar OWNER TO role_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            ALTER TABLE foobar OWNER TO role_2;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithReassignOwned("role_1", "role_2"),
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAuthzUpdate,
+		},
+		expectedPlanDDL: []string{
+			`REASSIGN OWNED BY "role_1" TO "role_2"`,
+		},
+	},
+	{
+		name:  "Reassign is prepended to the rest of the plan",
+		roles: []string{"role_1", "role_2"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+            ALTER TABLE foobar OWNER TO role_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY, val INT);
+            ALTER TABLE foobar OWNER TO role_2;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithReassignOwned("role_1", "role_2"),
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAuthzUpdate,
+		},
+		expectedPlanDDL: []string{
+			`REASSIGN OWNED BY "role_1" TO "role_2"`,
+			`ALTER TABLE "public"."foobar" ADD COLUMN "val" integer`,
+			`ANALYZE "public"."foobar"`,
+		},
+	},
+	{
+		name:  "No objects owned by role",
+		roles: []string{"role_1", "role_2"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(id INT PRIMARY KEY);
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithReassignOwned("role_1", "role_2"),
+		},
+		expectEmptyPlan: true,
+	},
+}
+
+func (suite *acceptanceTestSuite) TestReassignOwnedTestCases() {
+	suite.runTestCases(reassignOwnedAcceptanceTestCases)
+}
@@ -0,0 +1,174 @@
+package migration_acceptance_tests
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/stripe/pg-schema-diff/internal/pgdump"
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+	"github.com/stripe/pg-schema-diff/pkg/tempdb"
+)
+
+// rollbackAcceptanceTestCase migrates a database from the old schema to the new schema and then applies the rollback of
+// the migration plan. The database should be back to the old schema, unless the rollback is expected to contain steps
+// that are impossible to roll back.
+type rollbackAcceptanceTestCase struct {
+	name         string
+	oldSchemaDDL []string
+	newSchemaDDL []string
+
+	// expectedRollbackHazardTypes should contain all the unique migration hazard types that are expected to be within
+	// the rollback plan
+	expectedRollbackHazardTypes []diff.MigrationHazardType
+	// expectedDBSchemaDDL is the DDL required to reconstruct the state of the database after the rollback. If not
+	// specified, the oldSchemaDDL will be used
+	expectedDBSchemaDDL []string
+}
+
+var rollbackAcceptanceTestCases = []rollbackAcceptanceTestCase{
+	{
+		name: "Create table, index, and schema",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE bar();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TABLE bar();
+            CREATE TABLE schema_1.foo (
+                id INT PRIMARY KEY,
+                val TEXT NOT NULL CHECK (val != '')
+            );
+            CREATE INDEX foo_val_idx ON schema_1.foo(val);
+			`,
+		},
+		expectedRollbackHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+	{
+		name: "Add column, index, and constraints",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY,
+                val TEXT CHECK (val != '')
+            );
+            CREATE INDEX foo_val_idx ON foo(val);
+            ALTER TABLE foo ENABLE ROW LEVEL SECURITY;
+			`,
+		},
+		expectedRollbackHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAuthzUpdate,
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+	{
+		name: "Create types, sequences, and views",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TYPE color AS ENUM ('red', 'blue');
+            CREATE DOMAIN positive_int AS INT CHECK (VALUE > 0);
+            CREATE SEQUENCE foo_seq;
+            CREATE TABLE foo (
+                id INT
+            );
+            CREATE VIEW foo_view AS SELECT id FROM foo;
+            CREATE MATERIALIZED VIEW foo_mv AS SELECT id FROM foo;
+			`,
+		},
+	},
+	{
+		name: "Drop column",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT,
+                val TEXT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT
+            );
+			`,
+		},
+		expectedRollbackHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeImpossibleToRollback,
+		},
+		// The dropped column cannot be restored
+		expectedDBSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT
+            );
+			`,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestRollbackTestCases() {
+	for _, tc := range rollbackAcceptanceTestCases {
+		suite.Run(tc.name, func() {
+			suite.runRollbackTest(tc)
+		})
+	}
+}
+
+func (suite *acceptanceTestSuite) runRollbackTest(tc rollbackAcceptanceTestCase) {
+	if tc.expectedDBSchemaDDL == nil {
+		tc.expectedDBSchemaDDL = tc.oldSchemaDDL
+	}
+
+	oldDb, err := suite.pgEngine.CreateDatabase()
+	suite.Require().NoError(err)
+	defer oldDb.DropDB()
+	suite.Require().NoError(applyDDL(oldDb, tc.oldSchemaDDL))
+
+	oldDBConnPool, err := sql.Open("pgx", oldDb.GetDSN())
+	suite.Require().NoError(err)
+	defer oldDBConnPool.Close()
+
+	tempDbFactory, err := tempdb.NewOnInstanceFactory(context.Background(), func(ctx context.Context, dbName string) (*sql.DB, error) {
+		return sql.Open("pgx", suite.pgEngine.GetPostgresDatabaseConnOpts().With("dbname", dbName).ToDSN())
+	})
+	suite.Require().NoError(err)
+	defer func(tempDbFactory tempdb.Factory) {
+		suite.Require().NoError(tempDbFactory.Close())
+	}(tempDbFactory)
+
+	plan, err := diff.Generate(context.Background(), diff.DBSchemaSource(oldDBConnPool), diff.DDLSchemaSource(tc.newSchemaDDL),
+		diff.WithTempDbFactory(tempDbFactory),
+	)
+	suite.Require().NoError(err)
+	suite.Require().NoError(applyPlan(oldDb, plan), prettySprintPlan(plan))
+
+	rollbackPlan, err := plan.GenerateRollback()
+	suite.Require().NoError(err)
+	suite.assertValidPlan(rollbackPlan)
+	suite.ElementsMatch(tc.expectedRollbackHazardTypes, getUniqueHazardTypes(rollbackPlan), prettySprintPlan(rollbackPlan))
+	suite.Require().NoError(applyPlan(oldDb, rollbackPlan), prettySprintPlan(rollbackPlan))
+
+	oldDbDump, err := pgdump.GetDump(oldDb, pgdump.WithSchemaOnly())
+	suite.Require().NoError(err)
+	suite.Equal(suite.directlyRunDDLAndGetDump(tc.expectedDBSchemaDDL), oldDbDump, prettySprintPlan(rollbackPlan))
+}
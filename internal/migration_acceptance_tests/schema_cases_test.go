@@ -365,6 +365,7 @@ var schemaAcceptanceTests = []acceptanceTestCase{
 			diff.MigrationHazardTypeDeletesData,
 			diff.MigrationHazardTypeHasUntrackableDependencies,
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -446,8 +447,112 @@ var schemaAcceptanceTests = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
+	{
+		name: "Rename schema",
+		oldSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TYPE schema_1.color AS ENUM ('red', 'green', 'blue');
+            CREATE TABLE schema_1.foobar(
+                id SERIAL PRIMARY KEY,
+                color schema_1.color DEFAULT 'green'
+            );
+            CREATE INDEX foobar_color_idx ON schema_1.foobar(color);
+
+            CREATE TABLE bar(
+                id INT PRIMARY KEY,
+                foobar_id INT REFERENCES schema_1.foobar(id)
+            );
+
+            CREATE FUNCTION schema_1.count_foobar() RETURNS BIGINT
+                LANGUAGE SQL
+                BEGIN ATOMIC
+                    SELECT COUNT(*) FROM schema_1.foobar;
+                END;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_2;
+            CREATE TYPE schema_2.color AS ENUM ('red', 'green', 'blue');
+            CREATE TABLE schema_2.foobar(
+                id SERIAL PRIMARY KEY,
+                color schema_2.color DEFAULT 'green'
+            );
+            CREATE INDEX foobar_color_idx ON schema_2.foobar(color);
+
+            CREATE TABLE bar(
+                id INT PRIMARY KEY,
+                foobar_id INT REFERENCES schema_2.foobar(id)
+            );
+
+            CREATE FUNCTION schema_2.count_foobar() RETURNS BIGINT
+                LANGUAGE SQL
+                BEGIN ATOMIC
+                    SELECT COUNT(*) FROM schema_2.foobar;
+                END;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithRenamedSchema("schema_1", "schema_2"),
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"ALTER SCHEMA \"schema_1\" RENAME TO \"schema_2\"",
+		},
+	},
+	{
+		name: "Rename schema and alter its objects",
+		oldSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TABLE schema_1.foobar(
+                id INT PRIMARY KEY
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA "Schema 2";
+            CREATE TABLE "Schema 2".foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            );
+            CREATE INDEX foobar_content_idx ON "Schema 2".foobar(content);
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithRenamedSchema("schema_1", "Schema 2"),
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "Rename schema that does not exist",
+		oldSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_2;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithRenamedSchema("schema_3", "schema_2"),
+		},
+		expectedPlanErrorContains: "cannot rename schema \"schema_3\" because it does not exist",
+	},
 }
 
 func (suite *acceptanceTestSuite) TestSchemaTestCases() {
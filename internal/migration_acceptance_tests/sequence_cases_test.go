@@ -273,6 +273,9 @@ var sequenceAcceptanceTests = []acceptanceTestCase{
                         OWNED BY NONE;
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
 	},
 	{
 		name: "Alter min value",
@@ -434,6 +437,9 @@ var sequenceAcceptanceTests = []acceptanceTestCase{
                         OWNED BY NONE;
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeCorrectness,
+		},
 	},
 	{
 		name: "Alter cache",
@@ -457,6 +463,9 @@ var sequenceAcceptanceTests = []acceptanceTestCase{
                         OWNED BY NONE;
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
 	},
 	{
 		name: "Alter cycle",
@@ -723,6 +732,10 @@ var sequenceAcceptanceTests = []acceptanceTestCase{
             ALTER SEQUENCE "foobar sequence" OWNED BY "some other foobar"."some id";
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeCorrectness,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
 	},
 	{
 		name: "Alter ownership (from table to table) and sequence properties (old type is not compatible with new table)",
@@ -757,6 +770,10 @@ var sequenceAcceptanceTests = []acceptanceTestCase{
             ALTER SEQUENCE "foobar sequence" OWNED BY "some other foobar"."some id";
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeCorrectness,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
 	},
 }
 
@@ -0,0 +1,151 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var statisticsObjectAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "no-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_city_zip (dependencies, ndistinct) ON city, zip FROM foo;
+            ALTER STATISTICS foo_city_zip SET STATISTICS 500;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_city_zip (dependencies, ndistinct) ON city, zip FROM foo;
+            ALTER STATISTICS foo_city_zip SET STATISTICS 500;
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name: "add statistics objects",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_city_zip ON city, zip FROM foo;
+            CREATE STATISTICS schema_1.foo_id_city (mcv) ON id, city FROM foo;
+            CREATE STATISTICS foo_expr ON lower(city), zip FROM foo;
+            ALTER STATISTICS foo_expr SET STATISTICS 200;
+			`,
+		},
+	},
+	{
+		name: "add statistics objects on a new table",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE bar();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE bar();
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_city_zip (dependencies) ON city, zip FROM foo;
+			`,
+		},
+	},
+	{
+		name: "alter statistics target",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_city_zip ON city, zip FROM foo;
+            CREATE STATISTICS foo_id_city ON id, city FROM foo;
+            ALTER STATISTICS foo_id_city SET STATISTICS 100;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_city_zip ON city, zip FROM foo;
+            ALTER STATISTICS foo_city_zip SET STATISTICS 100;
+            CREATE STATISTICS foo_id_city ON id, city FROM foo;
+			`,
+		},
+	},
+	{
+		name: "change statistics kinds and columns",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_stats (ndistinct) ON city, zip FROM foo;
+            CREATE STATISTICS foo_other_stats ON id, city FROM foo;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_stats (ndistinct, mcv) ON city, zip FROM foo;
+            CREATE STATISTICS foo_other_stats ON id, zip FROM foo;
+			`,
+		},
+	},
+	{
+		name: "drop statistics objects and the columns they use",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_city_zip ON city, zip FROM foo;
+            CREATE STATISTICS foo_id_city ON id, city FROM foo;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT);
+            CREATE STATISTICS foo_id_city ON id, city FROM foo;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+	{
+		name: "re-create table with statistics objects",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_city_zip ON city, zip FROM foo;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT) PARTITION BY LIST (city);
+            CREATE STATISTICS foo_city_zip ON city, zip FROM foo;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+	{
+		name: "drop table with statistics objects",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(id INT, city TEXT, zip TEXT);
+            CREATE STATISTICS foo_city_zip ON city, zip FROM foo;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE bar();
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestStatisticsObjectTestCases() {
+	suite.runTestCases(statisticsObjectAcceptanceTestCases)
+}
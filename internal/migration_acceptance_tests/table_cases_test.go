@@ -95,6 +95,9 @@ var tableAcceptanceTestCases = []acceptanceTestCase{
             ALTER TABLE foobar ADD CONSTRAINT foobar_fk FOREIGN KEY (foo, bar) REFERENCES schema_1.foobar_fk(foo, bar);
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeValidateConstraint,
+		},
 	},
 	{
 		name:         "Create table with RLS enabled",
@@ -173,6 +176,7 @@ var tableAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 		},
 		newSchemaDDL: nil,
 	},
@@ -215,6 +219,7 @@ var tableAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 		},
 	},
 	{
@@ -351,6 +356,32 @@ var tableAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeAuthzUpdate,
 		},
 	},
+	{
+		name: "Unforce RLS while keeping RLS enabled",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+            ALTER TABLE foobar ENABLE ROW LEVEL SECURITY;
+            ALTER TABLE foobar FORCE ROW LEVEL SECURITY;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY
+            );
+            ALTER TABLE foobar ENABLE ROW LEVEL SECURITY;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAuthzUpdate,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" NO FORCE ROW LEVEL SECURITY",
+		},
+	},
 	{
 		name: "Alter table: New primary key, drop unique constraint, new unique constraint, change column types, delete unique index, delete FK's, new index, validate check constraint",
 		oldSchemaDDL: []string{
@@ -402,6 +433,7 @@ var tableAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
 			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -473,6 +505,7 @@ var tableAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeDeletesData,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -540,6 +573,7 @@ var tableAcceptanceTestCases = []acceptanceTestCase{
 			diff.MigrationHazardTypeDeletesData,
 			diff.MigrationHazardTypeIndexDropped,
 			diff.MigrationHazardTypeIndexBuild,
+			diff.MigrationHazardTypeValidateConstraint,
 		},
 	},
 	{
@@ -565,6 +599,71 @@ var tableAcceptanceTestCases = []acceptanceTestCase{
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
 			diff.MigrationHazardTypeImpactsDatabasePerformance,
+			diff.MigrationHazardTypeValidateConstraint,
+		},
+	},
+	{
+		name: "Create table with storage parameters",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+            CREATE TABLE foobar(
+                id INT,
+                content TEXT
+            ) WITH (fillfactor = 70, toast_tuple_target = 256, toast.autovacuum_enabled = false);
+            ALTER TABLE foobar ALTER COLUMN content SET STORAGE EXTERNAL;
+			`,
+		},
+	},
+	{
+		name: "Alter table storage parameters",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            ) WITH (fillfactor = 70, autovacuum_enabled = false);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            ) WITH (fillfactor = 80, toast.autovacuum_enabled = false);
+			`,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" SET (fillfactor=80, toast.autovacuum_enabled=false)",
+			"ALTER TABLE \"public\".\"foobar\" RESET (autovacuum_enabled)",
+		},
+	},
+	{
+		name: "Alter autovacuum and reset toast storage parameters",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            ) WITH (autovacuum_vacuum_scale_factor = 0.2, toast.autovacuum_enabled = false, toast_tuple_target = 256);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT PRIMARY KEY,
+                content TEXT
+            ) WITH (autovacuum_vacuum_scale_factor = 0.05);
+			`,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" SET (autovacuum_vacuum_scale_factor=0.05)",
+			"ALTER TABLE \"public\".\"foobar\" RESET (toast.autovacuum_enabled, toast_tuple_target)",
 		},
 	},
 }
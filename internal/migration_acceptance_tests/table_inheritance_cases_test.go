@@ -0,0 +1,138 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var tableInheritanceAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "no-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT NOT NULL, val TEXT DEFAULT 'parent');
+            CREATE TABLE other_parent(other_id INT);
+            CREATE TABLE child(extra INT) INHERITS (parent, other_parent);
+            ALTER TABLE child ALTER COLUMN val SET DEFAULT 'child';
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT NOT NULL, val TEXT DEFAULT 'parent');
+            CREATE TABLE other_parent(other_id INT);
+            CREATE TABLE child(extra INT) INHERITS (parent, other_parent);
+            ALTER TABLE child ALTER COLUMN val SET DEFAULT 'child';
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name: "create parents and children",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+            CREATE TABLE parent(id INT NOT NULL, val TEXT DEFAULT 'parent');
+            CREATE TABLE other_parent(other_id INT);
+            CREATE TABLE child(extra INT) INHERITS (parent, other_parent);
+            ALTER TABLE child ALTER COLUMN val SET DEFAULT 'child';
+            CREATE TABLE grandchild(id INT NOT NULL, val TEXT DEFAULT 'parent', another INT) INHERITS (child);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeCorrectness,
+		},
+	},
+	{
+		name: "add inheritance to an existing table",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT NOT NULL, val TEXT);
+            CREATE TABLE child(id INT NOT NULL, extra INT);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT NOT NULL, val TEXT);
+            CREATE TABLE child(id INT NOT NULL, extra INT, val TEXT) INHERITS (parent);
+			`,
+		},
+	},
+	{
+		name: "remove inheritance",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT, val TEXT);
+            CREATE TABLE child(extra INT) INHERITS (parent);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT, val TEXT);
+            CREATE TABLE child(id INT, extra INT);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+	{
+		name: "change parents",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT);
+            CREATE TABLE other_parent(id INT, val TEXT);
+            CREATE TABLE child(id INT, extra INT) INHERITS (parent);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT);
+            CREATE TABLE other_parent(id INT, val TEXT);
+            CREATE TABLE child(id INT, extra INT, val TEXT) INHERITS (other_parent);
+			`,
+		},
+	},
+	{
+		name: "alter columns of a parent",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT, old_val TEXT, val TEXT);
+            CREATE TABLE child(extra INT) INHERITS (parent);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT NOT NULL, val TEXT DEFAULT 'parent', new_val TEXT);
+            CREATE TABLE child(extra INT) INHERITS (parent);
+            ALTER TABLE child ALTER COLUMN new_val SET DEFAULT 'child';
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeValidateConstraint,
+		},
+	},
+	{
+		name: "drop parent and child",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE parent(id INT);
+            CREATE TABLE child(extra INT) INHERITS (parent);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo();
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeDeletesData,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestTableInheritanceTestCases() {
+	suite.runTestCases(tableInheritanceAcceptanceTestCases)
+}
@@ -0,0 +1,158 @@
+package migration_acceptance_tests
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+	"github.com/stripe/pg-schema-diff/pkg/sqldb"
+	"github.com/stripe/pg-schema-diff/pkg/tempdb"
+)
+
+// confirmAllRenamesPlan generates a plan with rename detection and confirms every rename candidate
+func confirmAllRenamesPlan(ctx context.Context, connPool sqldb.Queryable, tempDbFactory tempdb.Factory, newSchemaDDL []string, opts ...diff.PlanOpt) (diff.Plan, error) {
+	plan, err := diff.Generate(ctx, diff.DBSchemaSource(connPool), diff.DDLSchemaSource(newSchemaDDL),
+		append(opts, diff.WithTempDbFactory(tempDbFactory), diff.WithDetectRenames())...)
+	if err != nil {
+		return diff.Plan{}, err
+	}
+	for len(plan.RenameCandidates) > 0 {
+		candidate := plan.RenameCandidates[0]
+		plan, err = plan.ConfirmRename(candidate.Old, candidate.New)
+		if err != nil {
+			return diff.Plan{}, fmt.Errorf("confirming rename: %w", err)
+		}
+	}
+	return plan, nil
+}
+
+var tableRenameAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "Rename table with dependent view, function, and foreign key",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                val TEXT,
+                CONSTRAINT foobar_pkey PRIMARY KEY (id)
+            );
+            CREATE INDEX foobar_val_idx ON foobar(val);
+
+            CREATE TABLE fizz(
+                foobar_id INT REFERENCES foobar(id)
+            );
+
+            CREATE VIEW foobar_view AS SELECT id, val FROM foobar;
+
+            CREATE FUNCTION count_foobar() RETURNS BIGINT
+                LANGUAGE SQL
+                BEGIN ATOMIC
+                    SELECT COUNT(*) FROM foobar;
+                END;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE "Foo Bar"(
+                id INT,
+                val TEXT,
+                CONSTRAINT foobar_pkey PRIMARY KEY (id)
+            );
+            CREATE INDEX foobar_val_idx ON "Foo Bar"(val);
+
+            CREATE TABLE fizz(
+                foobar_id INT,
+                CONSTRAINT fizz_foobar_id_fkey FOREIGN KEY (foobar_id) REFERENCES "Foo Bar"(id)
+            );
+
+            CREATE VIEW foobar_view AS SELECT id, val FROM "Foo Bar";
+
+            CREATE FUNCTION count_foobar() RETURNS BIGINT
+                LANGUAGE SQL
+                BEGIN ATOMIC
+                    SELECT COUNT(*) FROM "Foo Bar";
+                END;
+			`,
+		},
+		planOpts: []diff.PlanOpt{
+			diff.WithRenamedTable("public", "foobar", "Foo Bar"),
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" RENAME TO \"Foo Bar\"",
+		},
+	},
+	{
+		name: "Rename table with function that references the table by name",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT
+            );
+
+            CREATE FUNCTION count_foobar() RETURNS BIGINT
+                LANGUAGE SQL
+                AS 'SELECT COUNT(*) FROM foobar';
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar_renamed(
+                id INT
+            );
+
+            CREATE FUNCTION count_foobar() RETURNS BIGINT
+                LANGUAGE SQL
+                AS 'SELECT COUNT(*) FROM foobar_renamed';
+			`,
+		},
+		planFactory: confirmAllRenamesPlan,
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "Rename detected table with dependent view and foreign key",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foobar(
+                id INT,
+                CONSTRAINT foobar_pkey PRIMARY KEY (id)
+            );
+            CREATE TABLE fizz(
+                foobar_id INT REFERENCES foobar(id)
+            );
+            CREATE VIEW foobar_view AS SELECT id FROM foobar;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foobar_renamed(
+                id INT,
+                CONSTRAINT foobar_pkey PRIMARY KEY (id)
+            );
+            CREATE TABLE fizz(
+                foobar_id INT,
+                CONSTRAINT fizz_foobar_id_fkey FOREIGN KEY (foobar_id) REFERENCES foobar_renamed(id)
+            );
+            CREATE VIEW foobar_view AS SELECT id FROM foobar_renamed;
+			`,
+		},
+		planFactory: confirmAllRenamesPlan,
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TABLE \"public\".\"foobar\" RENAME TO \"foobar_renamed\"",
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestTableRenameTestCases() {
+	suite.runTestCases(tableRenameAcceptanceTestCases)
+}
@@ -0,0 +1,177 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var tablespaceAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name:        "no-op",
+		tablespaces: []string{"tablespace_1"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY USING INDEX TABLESPACE tablespace_1,
+                val TEXT
+            ) TABLESPACE tablespace_1;
+            CREATE INDEX foo_val_idx ON foo(val) TABLESPACE tablespace_1 WHERE val IS NOT NULL;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY USING INDEX TABLESPACE tablespace_1,
+                val TEXT
+            ) TABLESPACE tablespace_1;
+            CREATE INDEX foo_val_idx ON foo(val) TABLESPACE tablespace_1 WHERE val IS NOT NULL;
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name:        "Create table and indexes in tablespace",
+		tablespaces: []string{"tablespace_1"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE bar();
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE bar();
+            CREATE TABLE foo (
+                id INT PRIMARY KEY USING INDEX TABLESPACE tablespace_1,
+                val TEXT
+            ) TABLESPACE tablespace_1;
+            CREATE INDEX foo_val_idx ON foo(val) TABLESPACE tablespace_1 WHERE val IS NOT NULL;
+			`,
+		},
+	},
+	{
+		name:        "Add index in tablespace",
+		tablespaces: []string{"tablespace_1"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foo_val_idx ON foo(val) TABLESPACE tablespace_1 WHERE val IS NOT NULL;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name:        "Move index to tablespace",
+		tablespaces: []string{"tablespace_1"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foo_val_idx ON foo(val);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY USING INDEX TABLESPACE tablespace_1,
+                val TEXT
+            );
+            CREATE INDEX foo_val_idx ON foo(val) TABLESPACE tablespace_1;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:        "Move index back to pg_default",
+		tablespaces: []string{"tablespace_1"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY USING INDEX TABLESPACE tablespace_1,
+                val TEXT
+            );
+            CREATE INDEX foo_val_idx ON foo(val) TABLESPACE tablespace_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY,
+                val TEXT
+            );
+            CREATE INDEX foo_val_idx ON foo(val);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:        "Move table between tablespaces",
+		tablespaces: []string{"tablespace_1", "tablespace_2"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY,
+                val TEXT
+            ) TABLESPACE tablespace_1;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT PRIMARY KEY,
+                val TEXT
+            ) TABLESPACE tablespace_2;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeAcquiresAccessExclusiveLock,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name:        "Move partitioned table to tablespace",
+		tablespaces: []string{"tablespace_1"},
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT,
+                val TEXT
+            ) PARTITION BY LIST (val);
+            CREATE TABLE foo_1 PARTITION OF foo FOR VALUES IN ('1');
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo (
+                id INT,
+                val TEXT
+            ) PARTITION BY LIST (val) TABLESPACE tablespace_1;
+            CREATE TABLE foo_1 PARTITION OF foo FOR VALUES IN ('1') TABLESPACE pg_default;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestTablespaceTestCases() {
+	suite.runTestCases(tablespaceAcceptanceTestCases)
+}
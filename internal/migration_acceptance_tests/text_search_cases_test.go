@@ -0,0 +1,172 @@
+package migration_acceptance_tests
+
+import "github.com/stripe/pg-schema-diff/pkg/diff"
+
+var textSearchAcceptanceTestCases = []acceptanceTestCase{
+	{
+		name: "no-op",
+		oldSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH DICTIONARY english_stem_nostop (TEMPLATE = snowball, language = english);
+            CREATE TEXT SEARCH CONFIGURATION my_english (PARSER = default);
+            ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR asciiword, word WITH english_stem_nostop, simple;
+            CREATE TABLE foo(
+                content TEXT
+            );
+            CREATE INDEX foo_content_idx ON foo USING GIN (to_tsvector('my_english', content));
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH DICTIONARY english_stem_nostop (TEMPLATE = snowball, language = english);
+            CREATE TEXT SEARCH CONFIGURATION my_english (PARSER = default);
+            ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR asciiword, word WITH english_stem_nostop, simple;
+            CREATE TABLE foo(
+                content TEXT
+            );
+            CREATE INDEX foo_content_idx ON foo USING GIN (to_tsvector('my_english', content));
+			`,
+		},
+		expectEmptyPlan: true,
+	},
+	{
+		name: "create dictionary and configuration used by an index",
+		oldSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                content TEXT
+            );
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE SCHEMA schema_1;
+            CREATE TEXT SEARCH DICTIONARY schema_1.english_stem_nostop (TEMPLATE = snowball, language = english);
+            CREATE TEXT SEARCH CONFIGURATION schema_1.my_english (PARSER = default);
+            ALTER TEXT SEARCH CONFIGURATION schema_1.my_english ADD MAPPING FOR asciiword, word WITH schema_1.english_stem_nostop, simple;
+            ALTER TEXT SEARCH CONFIGURATION schema_1.my_english ADD MAPPING FOR email WITH simple;
+            CREATE TABLE foo(
+                content TEXT
+            );
+            CREATE INDEX foo_content_idx ON foo USING GIN (to_tsvector('schema_1.my_english', content));
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeIndexBuild,
+		},
+	},
+	{
+		name: "drop dictionary and configuration",
+		oldSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH DICTIONARY english_stem_nostop (TEMPLATE = snowball, language = english);
+            CREATE TEXT SEARCH CONFIGURATION my_english (PARSER = default);
+            ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR asciiword, word WITH english_stem_nostop, simple;
+            CREATE TABLE foo(
+                content TEXT
+            );
+            CREATE INDEX foo_content_idx ON foo USING GIN (to_tsvector('my_english', content));
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TABLE foo(
+                content TEXT
+            );
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+			diff.MigrationHazardTypeIndexDropped,
+		},
+	},
+	{
+		name: "alter dictionary options",
+		oldSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH DICTIONARY english_stem_nostop (TEMPLATE = snowball, language = english, stopwords = english);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH DICTIONARY english_stem_nostop (TEMPLATE = snowball, language = russian);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeCorrectness,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TEXT SEARCH DICTIONARY \"public\".\"english_stem_nostop\" (language = 'russian', stopwords)",
+		},
+	},
+	{
+		name: "change dictionary template",
+		oldSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH DICTIONARY my_dict (TEMPLATE = snowball, language = english);
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH DICTIONARY my_dict (TEMPLATE = simple);
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
+	},
+	{
+		name: "add, alter, and drop configuration mappings with a new dictionary",
+		oldSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH CONFIGURATION my_english (PARSER = default);
+            ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR asciiword, word WITH simple;
+            ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR email WITH simple;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH DICTIONARY english_stem_nostop (TEMPLATE = snowball, language = english);
+            CREATE TEXT SEARCH CONFIGURATION my_english (PARSER = default);
+            ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR asciiword, word WITH english_stem_nostop, simple;
+            ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR url WITH simple;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeCorrectness,
+		},
+		expectedPlanDDL: []string{
+			"CREATE TEXT SEARCH DICTIONARY \"public\".\"english_stem_nostop\" (TEMPLATE = \"pg_catalog\".\"snowball\", language = 'english')",
+			"ALTER TEXT SEARCH CONFIGURATION \"public\".\"my_english\" DROP MAPPING FOR email",
+			"ALTER TEXT SEARCH CONFIGURATION \"public\".\"my_english\" ALTER MAPPING FOR asciiword, word WITH \"public\".\"english_stem_nostop\", \"pg_catalog\".\"simple\"",
+			"ALTER TEXT SEARCH CONFIGURATION \"public\".\"my_english\" ADD MAPPING FOR url WITH \"pg_catalog\".\"simple\"",
+		},
+	},
+	{
+		name: "drop dictionary no longer used by a configuration",
+		oldSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH DICTIONARY english_stem_nostop (TEMPLATE = snowball, language = english);
+            CREATE TEXT SEARCH CONFIGURATION my_english (PARSER = default);
+            ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR asciiword WITH english_stem_nostop, simple;
+			`,
+		},
+		newSchemaDDL: []string{
+			`
+            CREATE TEXT SEARCH CONFIGURATION my_english (PARSER = default);
+            ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR asciiword WITH simple;
+			`,
+		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeCorrectness,
+		},
+		expectedPlanDDL: []string{
+			"ALTER TEXT SEARCH CONFIGURATION \"public\".\"my_english\" ALTER MAPPING FOR asciiword WITH \"pg_catalog\".\"simple\"",
+			"DROP TEXT SEARCH DICTIONARY \"public\".\"english_stem_nostop\"",
+		},
+	},
+}
+
+func (suite *acceptanceTestSuite) TestTextSearchTestCases() {
+	suite.runTestCases(textSearchAcceptanceTestCases)
+}
@@ -505,6 +505,9 @@ var triggerAcceptanceTestCases = []acceptanceTestCase{
                 EXECUTE PROCEDURE schema_4."increment version"();
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
 	},
 	{
 		name: "Alter trigger when clause",
@@ -554,6 +557,9 @@ var triggerAcceptanceTestCases = []acceptanceTestCase{
                 EXECUTE PROCEDURE "increment version"();
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
 	},
 	{
 		name: "Alter trigger table",
@@ -615,6 +621,9 @@ var triggerAcceptanceTestCases = []acceptanceTestCase{
                 EXECUTE PROCEDURE "increment version"();
 			`,
 		},
+		expectedHazardTypes: []diff.MigrationHazardType{
+			diff.MigrationHazardTypeHasUntrackableDependencies,
+		},
 	},
 	{
 		name: "Change trigger function and keep old function",
@@ -786,6 +795,7 @@ var triggerAcceptanceTestCases = []acceptanceTestCase{
 		},
 		expectedHazardTypes: []diff.MigrationHazardType{
 			diff.MigrationHazardTypeDeletesData,
+			diff.MigrationHazardTypeHasUntrackableDependencies,
 		},
 	},
 }
@@ -7,13 +7,17 @@ import (
 )
 
 // ResetInstance attempts to reset the cluster to a clean state.
-// It deletes all cluster level objects, i.e., roles, which are not deleted
+// It deletes all cluster level objects, i.e., roles and tablespaces, which are not deleted
 // by dropping database(s). This can be useful for re-using a cluster for multiple tests.
 func ResetInstance(ctx context.Context, db *sql.DB) error {
 	// Drop all roles except the current user and postgres internal roles
 	if err := dropRoles(ctx, db); err != nil {
 		return fmt.Errorf("dropping roles: %w", err)
 	}
+	// Drop all tablespaces except the built-in ones. The databases using them must already be dropped
+	if err := dropTablespaces(ctx, db); err != nil {
+		return fmt.Errorf("dropping tablespaces: %w", err)
+	}
 
 	return nil
 }
@@ -48,3 +52,33 @@ func dropRoles(ctx context.Context, db *sql.DB) error {
 
 	return nil
 }
+
+// dropTablespaces drops all tablespaces except the built-in ones, i.e., pg_default and pg_global
+func dropTablespaces(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT spcname
+		FROM pg_catalog.pg_tablespace
+		WHERE spcname NOT IN ('pg_default', 'pg_global');
+	`,
+	)
+	if err != nil {
+		return fmt.Errorf("querying tablespaces: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tablespaceName string
+		if err := rows.Scan(&tablespaceName); err != nil {
+			return fmt.Errorf("scanning tablespace: %w", err)
+		}
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("DROP TABLESPACE %s", tablespaceName)); err != nil {
+			return fmt.Errorf("dropping tablespace %q: %w", tablespaceName, err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating over rows: %w", err)
+	}
+
+	return nil
+}
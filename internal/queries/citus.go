@@ -0,0 +1,64 @@
+package queries
+
+import (
+	"context"
+)
+
+// The queries in this file are not generated by sqlc because they reference the catalog of the Citus extension, which
+// only exists in databases where Citus is installed.
+
+const getCitusTablesExist = `
+SELECT pg_catalog.to_regclass('citus_tables') IS NOT NULL AS citus_tables_exist
+`
+
+const getCitusDistributedTables = `
+SELECT
+    c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name,
+    ct.distribution_column::TEXT AS distribution_column
+FROM citus_tables AS ct
+INNER JOIN pg_catalog.pg_class AS c ON ct.table_name::OID = c.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+WHERE ct.citus_table_type = 'distributed'
+`
+
+type GetCitusDistributedTablesRow struct {
+	TableName          string
+	TableSchemaName    string
+	DistributionColumn string
+}
+
+// GetCitusDistributedTables gets the tables distributed by Citus and their distribution columns. If Citus is not
+// installed, no tables are returned.
+func (q *Queries) GetCitusDistributedTables(ctx context.Context) ([]GetCitusDistributedTablesRow, error) {
+	var citusTablesExist bool
+	if err := q.db.QueryRowContext(ctx, getCitusTablesExist).Scan(&citusTablesExist); err != nil {
+		return nil, err
+	}
+	if !citusTablesExist {
+		return nil, nil
+	}
+
+	rows, err := q.db.QueryContext(ctx, getCitusDistributedTables)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCitusDistributedTablesRow
+	for rows.Next() {
+		var i GetCitusDistributedTablesRow
+		if err := rows.Scan(&i.TableName, &i.TableSchemaName, &i.DistributionColumn); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
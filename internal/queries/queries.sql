@@ -1,5 +1,7 @@
 -- name: GetSchemas :many
-SELECT nspname::TEXT AS schema_name
+SELECT
+    nspname::TEXT AS schema_name,
+    pg_catalog.pg_get_userbyid(nspowner)::TEXT AS owner_name
 FROM pg_catalog.pg_namespace
 WHERE
     nspname NOT IN ('pg_catalog', 'information_schema')
@@ -33,20 +35,64 @@ SELECT
     (CASE
         WHEN c.relispartition THEN pg_catalog.pg_get_expr(c.relpartbound, c.oid)
         ELSE ''
-    END)::TEXT AS partition_for_values
+    END)::TEXT AS partition_for_values,
+    COALESCE(c.reloptions, '{}')::TEXT [] AS storage_parameters,
+    COALESCE(toast_c.reloptions, '{}')::TEXT [] AS toast_storage_parameters,
+    -- The parents of tables using (non-partition) inheritance, in the order they are inherited from
+    COALESCE(inheritance.parent_names, '{}')::TEXT [] AS inherits_from_names,
+    COALESCE(
+        inheritance.parent_schema_names, '{}'
+    )::TEXT [] AS inherits_from_schema_names,
+    -- The tablespace is empty if the table is in the database's default tablespace
+    COALESCE(tablespace.spcname, '')::TEXT AS tablespace_name,
+    pg_catalog.pg_get_userbyid(c.relowner)::TEXT AS owner_name,
+    COALESCE(description.description, '')::TEXT AS comment
 FROM pg_catalog.pg_class AS c
 INNER JOIN
     pg_catalog.pg_namespace AS table_namespace
     ON c.relnamespace = table_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_class AS toast_c
+    ON c.reltoastrelid = toast_c.oid
+LEFT JOIN
+    pg_catalog.pg_tablespace AS tablespace
+    ON c.reltablespace = tablespace.oid
 LEFT JOIN
     pg_catalog.pg_inherits AS table_inherits
-    ON c.oid = table_inherits.inhrelid
+    ON c.relispartition AND c.oid = table_inherits.inhrelid
 LEFT JOIN
     pg_catalog.pg_class AS parent_c
     ON table_inherits.inhparent = parent_c.oid
 LEFT JOIN
     pg_catalog.pg_namespace AS parent_namespace
     ON parent_c.relnamespace = parent_namespace.oid
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(
+            inheritance_parent_c.relname::TEXT
+            ORDER BY inheritance_inherits.inhseqno
+        ) AS parent_names,
+        ARRAY_AGG(
+            inheritance_parent_namespace.nspname::TEXT
+            ORDER BY inheritance_inherits.inhseqno
+        ) AS parent_schema_names
+    FROM pg_catalog.pg_inherits AS inheritance_inherits
+    INNER JOIN
+        pg_catalog.pg_class AS inheritance_parent_c
+        ON inheritance_inherits.inhparent = inheritance_parent_c.oid
+    INNER JOIN
+        pg_catalog.pg_namespace AS inheritance_parent_namespace
+        ON inheritance_parent_c.relnamespace = inheritance_parent_namespace.oid
+    WHERE
+        inheritance_inherits.inhrelid = c.oid
+        AND NOT c.relispartition
+) AS inheritance ON true
+LEFT JOIN
+    pg_catalog.pg_description AS description
+    ON
+        c.oid = description.objoid
+        AND description.classoid = 'pg_class'::REGCLASS
+        AND description.objsubid = 0
 WHERE
     table_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
     AND table_namespace.nspname !~ '^pg_toast'
@@ -62,6 +108,57 @@ WHERE
             AND depend.deptype = 'e'
     );
 
+-- name: GetTablesByComment :many
+SELECT
+    c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+WHERE
+    c.relkind = 'r'
+    AND pg_catalog.obj_description(c.oid, 'pg_class') = sqlc.arg(comment)::TEXT;
+
+-- name: GetTableRowEstimates :many
+SELECT
+    c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name,
+    c.reltuples::BIGINT AS estimated_rows
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+WHERE
+    table_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND table_namespace.nspname !~ '^pg_toast'
+    AND table_namespace.nspname !~ '^pg_temp'
+    AND (c.relkind = 'r' OR c.relkind = 'p');
+
+-- name: GetTableStatistics :one
+SELECT
+    c.relpages::BIGINT AS pages,
+    c.reltuples::BIGINT AS estimated_rows,
+    COALESCE((
+        SELECT SUM(s.avg_width)
+        FROM pg_catalog.pg_stats AS s
+        WHERE
+            s.schemaname = table_namespace.nspname
+            AND s.tablename = c.relname
+    ), 0)::BIGINT AS row_width,
+    (
+        COALESCE(stat.last_analyze, stat.last_autoanalyze) IS NOT NULL
+    )::BOOLEAN AS has_been_analyzed,
+    COALESCE(stat.n_mod_since_analyze, 0)::BIGINT AS rows_modified_since_analyze
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_stat_all_tables AS stat
+    ON c.oid = stat.relid
+WHERE c.oid = pg_catalog.to_regclass(sqlc.arg(table_name)::TEXT);
+
 -- name: GetColumnsForTable :many
 WITH identity_col_seq AS (
     SELECT
@@ -103,8 +200,26 @@ SELECT
     identity_col_seq.seqmin AS min_value,
     identity_col_seq.seqcache AS cache_size,
     identity_col_seq.seqcycle AS is_cycle,
-    pg_catalog.format_type(a.atttypid, a.atttypmod) AS column_type
+    pg_catalog.format_type(a.atttypid, a.atttypmod) AS column_type,
+    a.attstorage::TEXT AS storage_type,
+    column_type.typstorage::TEXT AS type_storage_type,
+    (a.attgenerated = 's') AS is_generated,
+    (NOT a.attislocal) AS is_inherited,
+    -- Dependencies on built-in functions are not recorded in pg_depend, so find the functions called by the default
+    -- via the function ids in its expression tree
+    COALESCE((
+        SELECT BOOL_OR(default_func.provolatile = 'v')
+        FROM
+            REGEXP_MATCHES(
+                d.adbin::TEXT, ':(?:op)?funcid (\d+)', 'g'
+            ) AS func_ref (func_id)
+        INNER JOIN
+            pg_catalog.pg_proc AS default_func
+            ON func_ref.func_id[1]::OID = default_func.oid
+    ), false) AS is_default_volatile,
+    COALESCE(description.description, '')::TEXT AS comment
 FROM pg_catalog.pg_attribute AS a
+INNER JOIN pg_catalog.pg_type AS column_type ON a.atttypid = column_type.oid
 LEFT JOIN
     pg_catalog.pg_attrdef AS d
     ON (a.attrelid = d.adrelid AND a.attnum = d.adnum)
@@ -117,6 +232,12 @@ LEFT JOIN
     ON
         a.attrelid = identity_col_seq.owner_relid
         AND a.attnum = identity_col_seq.owner_attnum
+LEFT JOIN
+    pg_catalog.pg_description AS description
+    ON
+        a.attrelid = description.objoid
+        AND description.classoid = 'pg_class'::REGCLASS
+        AND a.attnum = description.objsubid
 WHERE
     a.attrelid = $1
     AND a.attnum > 0
@@ -150,16 +271,54 @@ SELECT
         INNER JOIN
             pg_catalog.pg_attribute AS att
             ON att.attrelid = table_c.oid AND indkey_ord.attnum = att.attnum
+        WHERE indkey_ord.ord <= i.indnkeyatts
     )::TEXT [] AS column_names,
-    COALESCE(con.conislocal, false) AS constraint_is_local
+    (
+        SELECT
+            ARRAY_AGG(
+                att.attname
+                ORDER BY indkey_ord.ord
+            )
+        FROM UNNEST(i.indkey) WITH ORDINALITY AS indkey_ord (attnum, ord)
+        INNER JOIN
+            pg_catalog.pg_attribute AS att
+            ON att.attrelid = table_c.oid AND indkey_ord.attnum = att.attnum
+        -- The columns after the key columns are the INCLUDE columns
+        WHERE indkey_ord.ord > i.indnkeyatts
+    )::TEXT [] AS included_column_names,
+    (
+        SELECT
+            ARRAY_AGG(
+                pg_catalog.pg_get_indexdef(c.oid, indkey_ord.ord::INT, true)
+                ORDER BY indkey_ord.ord
+            )
+        FROM UNNEST(i.indkey) WITH ORDINALITY AS indkey_ord (attnum, ord)
+        -- A zero entry in indkey indicates the column is an expression
+        WHERE indkey_ord.attnum = 0
+    )::TEXT [] AS expressions,
+    COALESCE(con.conislocal, false) AS constraint_is_local,
+    COALESCE(con.condeferrable, false) AS constraint_is_deferrable,
+    COALESCE(con.condeferred, false) AS constraint_is_initially_deferred,
+    COALESCE(
+        pg_catalog.pg_get_expr(i.indpred, i.indrelid), ''
+    )::TEXT AS predicate,
+    -- The tablespace is empty if the index is in the database's default tablespace
+    COALESCE(tablespace.spcname, '')::TEXT AS tablespace_name,
+    index_am.amname::TEXT AS index_method,
+    COALESCE(description.description, '')::TEXT AS comment,
+    COALESCE(c.reloptions, '{}')::TEXT [] AS storage_parameters
 FROM pg_catalog.pg_class AS c
 INNER JOIN pg_catalog.pg_index AS i ON (c.oid = i.indexrelid)
+INNER JOIN pg_catalog.pg_am AS index_am ON (c.relam = index_am.oid)
 INNER JOIN pg_catalog.pg_class AS table_c ON (i.indrelid = table_c.oid)
 INNER JOIN pg_catalog.pg_namespace AS table_namespace
     ON table_c.relnamespace = table_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_tablespace AS tablespace
+    ON c.reltablespace = tablespace.oid
 LEFT JOIN
     pg_catalog.pg_constraint AS con
-    ON (c.oid = con.conindid AND con.contype IN ('p', 'u', null))
+    ON (c.oid = con.conindid AND con.contype IN ('p', 'u', 'x', null))
 LEFT JOIN
     pg_catalog.pg_inherits AS idx_inherits
     ON (c.oid = idx_inherits.inhrelid)
@@ -169,6 +328,12 @@ LEFT JOIN
 LEFT JOIN
     pg_catalog.pg_namespace AS parent_namespace
     ON parent_c.relnamespace = parent_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_description AS description
+    ON
+        c.oid = description.objoid
+        AND description.classoid = 'pg_class'::REGCLASS
+        AND description.objsubid = 0
 WHERE
     table_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
     AND table_namespace.nspname !~ '^pg_toast'
@@ -184,6 +349,39 @@ WHERE
             AND depend.deptype = 'e'
     );
 
+-- name: GetIndexOperatorClasses :many
+SELECT DISTINCT
+    c.relname::TEXT AS index_name,
+    c_namespace.nspname::TEXT AS index_schema_name,
+    opc.opcname::TEXT AS operator_class_name,
+    opc_namespace.nspname::TEXT AS operator_class_schema_name,
+    am.amname::TEXT AS index_method
+FROM pg_catalog.pg_index AS i
+INNER JOIN pg_catalog.pg_class AS c ON i.indexrelid = c.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS c_namespace
+    ON c.relnamespace = c_namespace.oid
+CROSS JOIN LATERAL UNNEST(i.indclass::OID []) AS indclass (opc_oid)
+INNER JOIN pg_catalog.pg_opclass AS opc ON indclass.opc_oid = opc.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS opc_namespace
+    ON opc.opcnamespace = opc_namespace.oid
+INNER JOIN pg_catalog.pg_am AS am ON opc.opcmethod = am.oid
+WHERE
+    c_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND c_namespace.nspname !~ '^pg_toast'
+    AND c_namespace.nspname !~ '^pg_temp'
+    -- Only operator classes that are tracked, i.e., not built-in and not belonging to extensions, are relevant
+    AND opc_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_opclass'::REGCLASS
+            AND depend.objid = opc.oid
+            AND depend.deptype = 'e'
+    );
+
 -- name: GetCheckConstraints :many
 SELECT
     pg_constraint.oid,
@@ -224,6 +422,8 @@ SELECT
     foreign_table_c.relname::TEXT AS foreign_table_name,
     foreign_table_namespace.nspname::TEXT AS foreign_table_schema_name,
     pg_constraint.convalidated AS is_valid,
+    pg_constraint.condeferrable AS is_deferrable,
+    pg_constraint.condeferred AS is_initially_deferred,
     pg_catalog.pg_get_constraintdef(pg_constraint.oid) AS constraint_def
 FROM pg_catalog.pg_constraint
 INNER JOIN
@@ -253,7 +453,10 @@ SELECT
     pg_catalog.pg_get_function_identity_arguments(
         pg_proc.oid
     ) AS func_identity_arguments,
-    pg_catalog.pg_get_functiondef(pg_proc.oid) AS func_def
+    pg_catalog.pg_get_functiondef(pg_proc.oid) AS func_def,
+    (pg_proc.prokind = 'w') AS is_window,
+    pg_catalog.pg_get_userbyid(pg_proc.proowner)::TEXT AS owner_name,
+    COALESCE(description.description, '')::TEXT AS comment
 FROM pg_catalog.pg_proc
 INNER JOIN
     pg_catalog.pg_namespace AS proc_namespace
@@ -261,6 +464,12 @@ INNER JOIN
 INNER JOIN
     pg_catalog.pg_language AS proc_lang
     ON pg_proc.prolang = proc_lang.oid
+LEFT JOIN
+    pg_catalog.pg_description AS description
+    ON
+        pg_proc.oid = description.objoid
+        AND description.classoid = 'pg_proc'::REGCLASS
+        AND description.objsubid = 0
 WHERE
     proc_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
     AND proc_namespace.nspname !~ '^pg_toast'
@@ -276,6 +485,143 @@ WHERE
             AND depend.deptype = 'e'
     );
 
+-- name: GetAggregates :many
+SELECT
+    p.proname::TEXT AS aggregate_name,
+    p_namespace.nspname::TEXT AS aggregate_schema_name,
+    pg_catalog.pg_get_function_identity_arguments(
+        p.oid
+    )::TEXT AS aggregate_identity_arguments,
+    pg_catalog.format_type(agg.aggtranstype, NULL)::TEXT AS state_type,
+    state_fn.proname::TEXT AS state_func_name,
+    state_fn_namespace.nspname::TEXT AS state_func_schema_name,
+    pg_catalog.pg_get_function_identity_arguments(
+        state_fn.oid
+    )::TEXT AS state_func_identity_arguments,
+    COALESCE(final_fn.proname, '')::TEXT AS final_func_name,
+    COALESCE(final_fn_namespace.nspname, '')::TEXT AS final_func_schema_name,
+    COALESCE(
+        pg_catalog.pg_get_function_identity_arguments(final_fn.oid), ''
+    )::TEXT AS final_func_identity_arguments,
+    agg.aggfinalextra AS final_func_extra,
+    COALESCE(combine_fn.proname, '')::TEXT AS combine_func_name,
+    COALESCE(combine_fn_namespace.nspname, '')::TEXT AS combine_func_schema_name,
+    COALESCE(
+        pg_catalog.pg_get_function_identity_arguments(combine_fn.oid), ''
+    )::TEXT AS combine_func_identity_arguments,
+    COALESCE(serial_fn.proname, '')::TEXT AS serial_func_name,
+    COALESCE(serial_fn_namespace.nspname, '')::TEXT AS serial_func_schema_name,
+    COALESCE(
+        pg_catalog.pg_get_function_identity_arguments(serial_fn.oid), ''
+    )::TEXT AS serial_func_identity_arguments,
+    COALESCE(deserial_fn.proname, '')::TEXT AS deserial_func_name,
+    COALESCE(
+        deserial_fn_namespace.nspname, ''
+    )::TEXT AS deserial_func_schema_name,
+    COALESCE(
+        pg_catalog.pg_get_function_identity_arguments(deserial_fn.oid), ''
+    )::TEXT AS deserial_func_identity_arguments,
+    -- An empty initial condition is distinct from no initial condition
+    agg.agginitval IS NOT NULL AS has_initial_condition,
+    COALESCE(agg.agginitval, '')::TEXT AS initial_condition,
+    (
+        CASE p.proparallel
+            WHEN 's' THEN 'SAFE'
+            WHEN 'r' THEN 'RESTRICTED'
+            ELSE 'UNSAFE'
+        END
+    )::TEXT AS parallel
+FROM pg_catalog.pg_aggregate AS agg
+INNER JOIN pg_catalog.pg_proc AS p ON agg.aggfnoid = p.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS p_namespace
+    ON p.pronamespace = p_namespace.oid
+INNER JOIN pg_catalog.pg_proc AS state_fn ON agg.aggtransfn = state_fn.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS state_fn_namespace
+    ON state_fn.pronamespace = state_fn_namespace.oid
+-- The optional support functions are zero if they are not set
+LEFT JOIN pg_catalog.pg_proc AS final_fn ON agg.aggfinalfn = final_fn.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS final_fn_namespace
+    ON final_fn.pronamespace = final_fn_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_proc AS combine_fn
+    ON agg.aggcombinefn = combine_fn.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS combine_fn_namespace
+    ON combine_fn.pronamespace = combine_fn_namespace.oid
+LEFT JOIN pg_catalog.pg_proc AS serial_fn ON agg.aggserialfn = serial_fn.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS serial_fn_namespace
+    ON serial_fn.pronamespace = serial_fn_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_proc AS deserial_fn
+    ON agg.aggdeserialfn = deserial_fn.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS deserial_fn_namespace
+    ON deserial_fn.pronamespace = deserial_fn_namespace.oid
+WHERE
+    p_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND p_namespace.nspname !~ '^pg_toast'
+    AND p_namespace.nspname !~ '^pg_temp'
+    -- Exclude aggregates belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_proc'::REGCLASS
+            AND depend.objid = p.oid
+            AND depend.deptype = 'e'
+    );
+
+-- name: GetDefaultPrivileges :many
+SELECT
+    grantor.rolname::TEXT AS grantor,
+    COALESCE(default_acl_namespace.nspname, '')::TEXT AS schema_name,
+    (
+        CASE default_acl.defaclobjtype
+            WHEN 'r' THEN 'TABLES'
+            WHEN 'S' THEN 'SEQUENCES'
+            WHEN 'f' THEN 'FUNCTIONS'
+            WHEN 'T' THEN 'TYPES'
+            WHEN 'n' THEN 'SCHEMAS'
+        END
+    )::TEXT AS object_type,
+    COALESCE(grantee.rolname, 'PUBLIC')::TEXT AS grantee,
+    acl.privilege_type::TEXT AS privilege_type,
+    acl.is_grantable AS is_grantable
+FROM pg_catalog.pg_default_acl AS default_acl
+INNER JOIN pg_catalog.pg_roles AS grantor ON default_acl.defaclrole = grantor.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS default_acl_namespace
+    ON default_acl.defaclnamespace = default_acl_namespace.oid
+CROSS JOIN LATERAL pg_catalog.ACLEXPLODE(default_acl.defaclacl) AS acl
+LEFT JOIN pg_catalog.pg_roles AS grantee ON acl.grantee = grantee.oid
+WHERE
+    -- Default privileges that apply to all schemas store the complete set of privileges, including the built-in
+    -- defaults, e.g., EXECUTE on functions for PUBLIC. Exclude the built-in defaults, since they are implicit
+    default_acl.defaclnamespace != 0
+    OR NOT EXISTS (
+        SELECT builtin_acl.grantee
+        FROM
+            pg_catalog.ACLEXPLODE(
+                pg_catalog.ACLDEFAULT(
+                    (
+                        CASE
+                            WHEN default_acl.defaclobjtype = 'S' THEN 's'
+                            ELSE default_acl.defaclobjtype
+                        END
+                    )::"char",
+                    default_acl.defaclrole
+                )
+            ) AS builtin_acl
+        WHERE
+            builtin_acl.grantee = acl.grantee
+            AND builtin_acl.privilege_type = acl.privilege_type
+            AND builtin_acl.is_grantable = acl.is_grantable
+    );
+
 -- name: GetDependsOnFunctions :many
 SELECT
     pg_proc.proname::TEXT AS func_name,
@@ -344,11 +690,19 @@ WHERE
 SELECT
     c.relname::TEXT AS view_name,
     view_namespace.nspname::TEXT AS view_schema_name,
-    pg_catalog.pg_get_viewdef(c.oid, true) AS view_definition
+    pg_catalog.pg_get_viewdef(c.oid, true) AS view_definition,
+    pg_catalog.pg_get_userbyid(c.relowner)::TEXT AS owner_name,
+    COALESCE(description.description, '')::TEXT AS comment
 FROM pg_catalog.pg_class AS c
 INNER JOIN
     pg_catalog.pg_namespace AS view_namespace
     ON c.relnamespace = view_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_description AS description
+    ON
+        c.oid = description.objoid
+        AND description.classoid = 'pg_class'::REGCLASS
+        AND description.objsubid = 0
 WHERE
     view_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
     AND view_namespace.nspname !~ '^pg_toast'
@@ -387,9 +741,39 @@ WHERE
     AND depend.classid = 'pg_rewrite'::REGCLASS
     AND depend.refclassid = 'pg_class'::REGCLASS
     AND depend.deptype = 'n'
-    AND depends_on_c.relkind IN ('r', 'v') -- 'r' for table, 'v' for view
+    -- 'r' for table, 'v' for view, 'm' for materialized view, 'f' for foreign table
+    AND depends_on_c.relkind IN ('r', 'v', 'm', 'f')
     AND depends_on_ns.nspname NOT IN ('pg_catalog', 'information_schema');
 
+-- name: GetMaterializedViews :many
+SELECT
+    c.relname::TEXT AS materialized_view_name,
+    view_namespace.nspname::TEXT AS materialized_view_schema_name,
+    pg_catalog.pg_get_viewdef(c.oid, true) AS materialized_view_definition,
+    COALESCE(c.reloptions, '{}')::TEXT [] AS storage_parameters,
+    COALESCE(toast_c.reloptions, '{}')::TEXT [] AS toast_storage_parameters
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS view_namespace
+    ON c.relnamespace = view_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_class AS toast_c
+    ON c.reltoastrelid = toast_c.oid
+WHERE
+    view_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND view_namespace.nspname !~ '^pg_toast'
+    AND view_namespace.nspname !~ '^pg_temp'
+    AND c.relkind = 'm'
+    -- Exclude materialized views belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_class'::REGCLASS
+            AND depend.objid = c.oid
+            AND depend.deptype = 'e'
+    );
+
 -- name: GetSequences :many
 SELECT
     seq_c.relname::TEXT AS sequence_name,
@@ -403,7 +787,8 @@ SELECT
     pg_seq.seqmin AS min_value,
     pg_seq.seqcache AS cache_size,
     pg_seq.seqcycle AS is_cycle,
-    FORMAT_TYPE(pg_seq.seqtypid, null) AS data_type
+    FORMAT_TYPE(pg_seq.seqtypid, null) AS data_type,
+    pg_catalog.pg_get_userbyid(seq_c.relowner)::TEXT AS owner_name
 FROM pg_catalog.pg_sequence AS pg_seq
 INNER JOIN pg_catalog.pg_class AS seq_c ON pg_seq.seqrelid = seq_c.oid
 INNER JOIN pg_catalog.pg_namespace AS seq_ns ON seq_c.relnamespace = seq_ns.oid
@@ -454,6 +839,324 @@ WHERE
     AND extension_namespace.nspname !~ '^pg_temp';
 
 
+-- name: GetCollations :many
+SELECT
+    coll.collname::TEXT AS collation_name,
+    coll_namespace.nspname::TEXT AS collation_schema_name,
+    (
+        CASE coll.collprovider
+            WHEN 'i' THEN 'icu'
+            WHEN 'b' THEN 'builtin'
+            ELSE 'libc'
+        END
+    )::TEXT AS provider,
+    -- The column holding the ICU and builtin locales was renamed across versions, so it is read via to_jsonb
+    (
+        CASE
+            WHEN coll.collprovider = 'c' THEN ''
+            ELSE
+                COALESCE(
+                    TO_JSONB(coll) ->> 'colllocale',
+                    TO_JSONB(coll) ->> 'colliculocale',
+                    coll.collcollate,
+                    ''
+                )
+        END
+    )::TEXT AS locale,
+    (
+        CASE WHEN coll.collprovider = 'c' THEN coll.collcollate ELSE '' END
+    )::TEXT AS lc_collate,
+    (
+        CASE WHEN coll.collprovider = 'c' THEN coll.collctype ELSE '' END
+    )::TEXT AS lc_ctype,
+    coll.collisdeterministic AS is_deterministic
+FROM pg_catalog.pg_collation AS coll
+INNER JOIN
+    pg_catalog.pg_namespace AS coll_namespace
+    ON coll.collnamespace = coll_namespace.oid
+WHERE
+    coll_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND coll_namespace.nspname !~ '^pg_toast'
+    AND coll_namespace.nspname !~ '^pg_temp'
+    -- Exclude collations belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_collation'::REGCLASS
+            AND ext_depend.objid = coll.oid
+            AND ext_depend.deptype = 'e'
+    );
+
+-- name: GetCompositeTypes :many
+SELECT
+    pg_type.typname::TEXT AS type_name,
+    type_namespace.nspname::TEXT AS type_schema_name,
+    COALESCE(attrs.attribute_names, '{}')::TEXT [] AS attribute_names,
+    COALESCE(attrs.attribute_types, '{}')::TEXT [] AS attribute_types,
+    COALESCE(
+        attrs.attribute_collation_names, '{}'
+    )::TEXT [] AS attribute_collation_names,
+    COALESCE(
+        attrs.attribute_collation_schema_names, '{}'
+    )::TEXT [] AS attribute_collation_schema_names
+FROM pg_catalog.pg_type AS pg_type
+INNER JOIN
+    pg_catalog.pg_namespace AS type_namespace
+    ON pg_type.typnamespace = type_namespace.oid
+INNER JOIN
+    pg_catalog.pg_class AS type_class
+    ON pg_type.typrelid = type_class.oid
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(a.attname ORDER BY a.attnum) AS attribute_names,
+        ARRAY_AGG(
+            pg_catalog.format_type(a.atttypid, a.atttypmod)
+            ORDER BY a.attnum
+        ) AS attribute_types,
+        ARRAY_AGG(
+            COALESCE(coll.collname, '') ORDER BY a.attnum
+        ) AS attribute_collation_names,
+        ARRAY_AGG(
+            COALESCE(collation_namespace.nspname, '') ORDER BY a.attnum
+        ) AS attribute_collation_schema_names
+    FROM pg_catalog.pg_attribute AS a
+    LEFT JOIN pg_catalog.pg_collation AS coll ON a.attcollation = coll.oid
+    LEFT JOIN
+        pg_catalog.pg_namespace AS collation_namespace
+        ON coll.collnamespace = collation_namespace.oid
+    WHERE
+        a.attrelid = type_class.oid
+        AND a.attnum > 0
+        AND NOT a.attisdropped
+) AS attrs ON true
+WHERE
+    pg_type.typtype = 'c'
+    -- Exclude the row types of tables, views, etc.
+    AND type_class.relkind = 'c'
+    AND type_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND type_namespace.nspname !~ '^pg_toast'
+    AND type_namespace.nspname !~ '^pg_temp'
+    -- Exclude composite types belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_type'::REGCLASS
+            AND ext_depend.objid = pg_type.oid
+            AND ext_depend.deptype = 'e'
+    );
+
+-- name: GetTextSearchDictionaries :many
+SELECT
+    dict.dictname::TEXT AS dictionary_name,
+    dict_namespace.nspname::TEXT AS dictionary_schema_name,
+    tmpl.tmplname::TEXT AS template_name,
+    tmpl_namespace.nspname::TEXT AS template_schema_name,
+    COALESCE(dict.dictinitoption, '')::TEXT AS options
+FROM pg_catalog.pg_ts_dict AS dict
+INNER JOIN
+    pg_catalog.pg_namespace AS dict_namespace
+    ON dict.dictnamespace = dict_namespace.oid
+INNER JOIN pg_catalog.pg_ts_template AS tmpl ON dict.dicttemplate = tmpl.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS tmpl_namespace
+    ON tmpl.tmplnamespace = tmpl_namespace.oid
+WHERE
+    dict_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND dict_namespace.nspname !~ '^pg_toast'
+    AND dict_namespace.nspname !~ '^pg_temp'
+    -- Exclude dictionaries belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_ts_dict'::REGCLASS
+            AND ext_depend.objid = dict.oid
+            AND ext_depend.deptype = 'e'
+    );
+
+-- name: GetTextSearchConfigs :many
+SELECT
+    cfg.cfgname::TEXT AS config_name,
+    cfg_namespace.nspname::TEXT AS config_schema_name,
+    prs.prsname::TEXT AS parser_name,
+    prs_namespace.nspname::TEXT AS parser_schema_name,
+    COALESCE(mappings.token_types, '{}')::TEXT [] AS mapping_token_types,
+    COALESCE(
+        mappings.dictionary_names, '{}'
+    )::TEXT [] AS mapping_dictionary_names,
+    COALESCE(
+        mappings.dictionary_schema_names, '{}'
+    )::TEXT [] AS mapping_dictionary_schema_names
+FROM pg_catalog.pg_ts_config AS cfg
+INNER JOIN
+    pg_catalog.pg_namespace AS cfg_namespace
+    ON cfg.cfgnamespace = cfg_namespace.oid
+INNER JOIN pg_catalog.pg_ts_parser AS prs ON cfg.cfgparser = prs.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS prs_namespace
+    ON prs.prsnamespace = prs_namespace.oid
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(
+            token_type.alias ORDER BY map.maptokentype, map.mapseqno
+        ) AS token_types,
+        ARRAY_AGG(
+            dict.dictname ORDER BY map.maptokentype, map.mapseqno
+        ) AS dictionary_names,
+        ARRAY_AGG(
+            dict_namespace.nspname ORDER BY map.maptokentype, map.mapseqno
+        ) AS dictionary_schema_names
+    FROM pg_catalog.pg_ts_config_map AS map
+    INNER JOIN
+        pg_catalog.ts_token_type(cfg.cfgparser) AS token_type
+        ON map.maptokentype = token_type.tokid
+    INNER JOIN pg_catalog.pg_ts_dict AS dict ON map.mapdict = dict.oid
+    INNER JOIN
+        pg_catalog.pg_namespace AS dict_namespace
+        ON dict.dictnamespace = dict_namespace.oid
+    WHERE map.mapcfg = cfg.oid
+) AS mappings ON true
+WHERE
+    cfg_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND cfg_namespace.nspname !~ '^pg_toast'
+    AND cfg_namespace.nspname !~ '^pg_temp'
+    -- Exclude configurations belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_ts_config'::REGCLASS
+            AND ext_depend.objid = cfg.oid
+            AND ext_depend.deptype = 'e'
+    );
+
+-- name: GetForeignDataWrappers :many
+SELECT
+    fdw.fdwname::TEXT AS wrapper_name,
+    COALESCE(handler_proc.proname, '')::TEXT AS handler_name,
+    COALESCE(handler_namespace.nspname, '')::TEXT AS handler_schema_name,
+    COALESCE(validator_proc.proname, '')::TEXT AS validator_name,
+    COALESCE(validator_namespace.nspname, '')::TEXT AS validator_schema_name,
+    COALESCE(fdw.fdwoptions, '{}')::TEXT [] AS options
+FROM pg_catalog.pg_foreign_data_wrapper AS fdw
+LEFT JOIN pg_catalog.pg_proc AS handler_proc ON fdw.fdwhandler = handler_proc.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS handler_namespace
+    ON handler_proc.pronamespace = handler_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_proc AS validator_proc
+    ON fdw.fdwvalidator = validator_proc.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS validator_namespace
+    ON validator_proc.pronamespace = validator_namespace.oid
+-- Exclude foreign-data wrappers belonging to extensions, e.g., postgres_fdw
+WHERE NOT EXISTS (
+    SELECT ext_depend.objid
+    FROM pg_catalog.pg_depend AS ext_depend
+    WHERE
+        ext_depend.classid = 'pg_foreign_data_wrapper'::REGCLASS
+        AND ext_depend.objid = fdw.oid
+        AND ext_depend.deptype = 'e'
+);
+
+-- name: GetForeignServers :many
+SELECT
+    srv.srvname::TEXT AS server_name,
+    fdw.fdwname::TEXT AS wrapper_name,
+    COALESCE(srv.srvtype, '')::TEXT AS server_type,
+    COALESCE(srv.srvversion, '')::TEXT AS server_version,
+    COALESCE(srv.srvoptions, '{}')::TEXT [] AS options
+FROM pg_catalog.pg_foreign_server AS srv
+INNER JOIN
+    pg_catalog.pg_foreign_data_wrapper AS fdw
+    ON srv.srvfdw = fdw.oid
+-- Exclude foreign servers belonging to extensions
+WHERE NOT EXISTS (
+    SELECT ext_depend.objid
+    FROM pg_catalog.pg_depend AS ext_depend
+    WHERE
+        ext_depend.classid = 'pg_foreign_server'::REGCLASS
+        AND ext_depend.objid = srv.oid
+        AND ext_depend.deptype = 'e'
+);
+
+-- name: GetForeignTables :many
+SELECT
+    c.oid,
+    c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name,
+    srv.srvname::TEXT AS server_name,
+    COALESCE(ft.ftoptions, '{}')::TEXT [] AS options
+FROM pg_catalog.pg_foreign_table AS ft
+INNER JOIN pg_catalog.pg_class AS c ON ft.ftrelid = c.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+INNER JOIN pg_catalog.pg_foreign_server AS srv ON ft.ftserver = srv.oid
+WHERE
+    table_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND table_namespace.nspname !~ '^pg_toast'
+    AND table_namespace.nspname !~ '^pg_temp'
+    -- Exclude foreign tables belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_class'::REGCLASS
+            AND ext_depend.objid = c.oid
+            AND ext_depend.deptype = 'e'
+    );
+
+-- name: GetDomains :many
+SELECT
+    pg_type.typname::TEXT AS domain_name,
+    type_namespace.nspname::TEXT AS domain_schema_name,
+    pg_catalog.format_type(
+        pg_type.typbasetype, pg_type.typtypmod
+    )::TEXT AS base_type,
+    COALESCE(coll.collname, '')::TEXT AS collation_name,
+    COALESCE(collation_namespace.nspname, '')::TEXT AS collation_schema_name,
+    COALESCE(pg_type.typdefault, '')::TEXT AS default_value,
+    pg_type.typnotnull AS is_not_null,
+    COALESCE(cons.constraint_names, '{}')::TEXT [] AS constraint_names,
+    COALESCE(cons.constraint_defs, '{}')::TEXT [] AS constraint_defs
+FROM pg_catalog.pg_type AS pg_type
+INNER JOIN
+    pg_catalog.pg_namespace AS type_namespace
+    ON pg_type.typnamespace = type_namespace.oid
+LEFT JOIN pg_catalog.pg_collation AS coll ON pg_type.typcollation = coll.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS collation_namespace
+    ON coll.collnamespace = collation_namespace.oid
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(con.conname ORDER BY con.conname) AS constraint_names,
+        ARRAY_AGG(
+            pg_catalog.pg_get_constraintdef(con.oid) ORDER BY con.conname
+        ) AS constraint_defs
+    FROM pg_catalog.pg_constraint AS con
+    WHERE
+        con.contypid = pg_type.oid
+        -- NOT NULL is tracked via pg_type.typnotnull
+        AND con.contype = 'c'
+) AS cons ON true
+WHERE
+    pg_type.typtype = 'd'
+    AND type_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND type_namespace.nspname !~ '^pg_toast'
+    AND type_namespace.nspname !~ '^pg_temp'
+    -- Exclude domains belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_type'::REGCLASS
+            AND ext_depend.objid = pg_type.oid
+            AND ext_depend.deptype = 'e'
+    );
+
 -- name: GetEnums :many
 SELECT
     pg_type.typname::TEXT AS enum_name,
@@ -549,3 +1252,348 @@ SELECT
     COALESCE(evttags, '{}')::TEXT[] AS tags
 FROM pg_catalog.pg_event_trigger
 ORDER BY evtname;
+
+-- name: GetOperators :many
+SELECT
+    op.oid,
+    op.oprname::TEXT AS operator_name,
+    op_namespace.nspname::TEXT AS operator_schema_name,
+    COALESCE(
+        pg_catalog.format_type(NULLIF(op.oprleft, 0), NULL), ''
+    )::TEXT AS left_type,
+    COALESCE(
+        pg_catalog.format_type(NULLIF(op.oprright, 0), NULL), ''
+    )::TEXT AS right_type,
+    pg_proc.proname::TEXT AS func_name,
+    proc_namespace.nspname::TEXT AS func_schema_name,
+    pg_catalog.pg_get_function_identity_arguments(
+        pg_proc.oid
+    ) AS func_identity_arguments,
+    COALESCE(com.oprname, '')::TEXT AS commutator_name,
+    COALESCE(com_namespace.nspname, '')::TEXT AS commutator_schema_name,
+    COALESCE(neg.oprname, '')::TEXT AS negator_name,
+    COALESCE(neg_namespace.nspname, '')::TEXT AS negator_schema_name,
+    COALESCE(NULLIF(op.oprrest::TEXT, '-'), '')::TEXT AS restrict_func,
+    COALESCE(NULLIF(op.oprjoin::TEXT, '-'), '')::TEXT AS join_func,
+    op.oprcanhash AS can_hash,
+    op.oprcanmerge AS can_merge
+FROM pg_catalog.pg_operator AS op
+INNER JOIN
+    pg_catalog.pg_namespace AS op_namespace
+    ON op.oprnamespace = op_namespace.oid
+-- Shell operators, i.e., operators only referenced as a commutator or negator, have no function
+INNER JOIN pg_catalog.pg_proc AS pg_proc ON op.oprcode = pg_proc.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS proc_namespace
+    ON pg_proc.pronamespace = proc_namespace.oid
+LEFT JOIN pg_catalog.pg_operator AS com ON op.oprcom = com.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS com_namespace
+    ON com.oprnamespace = com_namespace.oid
+LEFT JOIN pg_catalog.pg_operator AS neg ON op.oprnegate = neg.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS neg_namespace
+    ON neg.oprnamespace = neg_namespace.oid
+WHERE
+    op_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND op_namespace.nspname !~ '^pg_toast'
+    AND op_namespace.nspname !~ '^pg_temp'
+    -- Exclude operators belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_operator'::REGCLASS
+            AND depend.objid = op.oid
+            AND depend.deptype = 'e'
+    );
+
+-- name: GetOperatorClasses :many
+SELECT
+    opc.oid,
+    opc.opcname::TEXT AS operator_class_name,
+    opc_namespace.nspname::TEXT AS operator_class_schema_name,
+    am.amname::TEXT AS index_method,
+    opc.opcdefault AS is_default,
+    pg_catalog.format_type(opc.opcintype, NULL)::TEXT AS input_type,
+    COALESCE(
+        pg_catalog.format_type(NULLIF(opc.opckeytype, 0), NULL), ''
+    )::TEXT AS storage_type
+FROM pg_catalog.pg_opclass AS opc
+INNER JOIN
+    pg_catalog.pg_namespace AS opc_namespace
+    ON opc.opcnamespace = opc_namespace.oid
+INNER JOIN pg_catalog.pg_am AS am ON opc.opcmethod = am.oid
+WHERE
+    opc_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND opc_namespace.nspname !~ '^pg_toast'
+    AND opc_namespace.nspname !~ '^pg_temp'
+    -- Exclude operator classes belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_opclass'::REGCLASS
+            AND depend.objid = opc.oid
+            AND depend.deptype = 'e'
+    );
+
+-- name: GetOperatorClassOperators :many
+SELECT
+    amop.amopstrategy::INT AS strategy_number,
+    op.oprname::TEXT AS operator_name,
+    op_namespace.nspname::TEXT AS operator_schema_name,
+    COALESCE(
+        pg_catalog.format_type(NULLIF(op.oprleft, 0), NULL), ''
+    )::TEXT AS left_type,
+    COALESCE(
+        pg_catalog.format_type(NULLIF(op.oprright, 0), NULL), ''
+    )::TEXT AS right_type
+FROM pg_catalog.pg_amop AS amop
+-- Members of an operator class have an internal dependency on the operator class
+INNER JOIN pg_catalog.pg_depend AS depend
+    ON
+        depend.classid = 'pg_amop'::REGCLASS
+        AND depend.objid = amop.oid
+INNER JOIN pg_catalog.pg_operator AS op ON amop.amopopr = op.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS op_namespace
+    ON op.oprnamespace = op_namespace.oid
+WHERE
+    depend.refclassid = 'pg_opclass'::REGCLASS
+    AND depend.refobjid = sqlc.arg(operator_class_oid)::OID
+ORDER BY amop.amopstrategy;
+
+-- name: GetOperatorClassFunctions :many
+SELECT
+    amproc.amprocnum::INT AS support_number,
+    pg_proc.proname::TEXT AS func_name,
+    proc_namespace.nspname::TEXT AS func_schema_name,
+    pg_catalog.pg_get_function_identity_arguments(
+        pg_proc.oid
+    ) AS func_identity_arguments
+FROM pg_catalog.pg_amproc AS amproc
+-- Members of an operator class have an internal dependency on the operator class
+INNER JOIN pg_catalog.pg_depend AS depend
+    ON
+        depend.classid = 'pg_amproc'::REGCLASS
+        AND depend.objid = amproc.oid
+INNER JOIN pg_catalog.pg_proc AS pg_proc ON amproc.amproc = pg_proc.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS proc_namespace
+    ON pg_proc.pronamespace = proc_namespace.oid
+WHERE
+    depend.refclassid = 'pg_opclass'::REGCLASS
+    AND depend.refobjid = sqlc.arg(operator_class_oid)::OID
+ORDER BY amproc.amprocnum;
+
+-- name: GetObjectOwners :many
+SELECT
+    c.relname::TEXT AS object_name,
+    c_namespace.nspname::TEXT AS object_schema_name,
+    pg_catalog.pg_get_userbyid(c.relowner)::TEXT AS owner_name
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS c_namespace
+    ON c.relnamespace = c_namespace.oid
+WHERE
+    c.relkind IN ('r', 'p', 'v', 'm', 'S', 'f')
+    AND c_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND c_namespace.nspname !~ '^pg_toast'
+    AND c_namespace.nspname !~ '^pg_temp'
+    -- Exclude relations belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_class'::REGCLASS
+            AND depend.objid = c.oid
+            AND depend.deptype = 'e'
+    )
+UNION ALL
+SELECT
+    pg_proc.proname::TEXT AS object_name,
+    proc_namespace.nspname::TEXT AS object_schema_name,
+    pg_catalog.pg_get_userbyid(pg_proc.proowner)::TEXT AS owner_name
+FROM pg_catalog.pg_proc AS pg_proc
+INNER JOIN
+    pg_catalog.pg_namespace AS proc_namespace
+    ON pg_proc.pronamespace = proc_namespace.oid
+WHERE
+    proc_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND proc_namespace.nspname !~ '^pg_toast'
+    AND proc_namespace.nspname !~ '^pg_temp'
+    -- Exclude functions belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_proc'::REGCLASS
+            AND depend.objid = pg_proc.oid
+            AND depend.deptype = 'e'
+    )
+UNION ALL
+SELECT
+    pg_namespace.nspname::TEXT AS object_name,
+    pg_namespace.nspname::TEXT AS object_schema_name,
+    pg_catalog.pg_get_userbyid(pg_namespace.nspowner)::TEXT AS owner_name
+FROM pg_catalog.pg_namespace AS pg_namespace
+WHERE
+    pg_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND pg_namespace.nspname !~ '^pg_toast'
+    AND pg_namespace.nspname !~ '^pg_temp'
+    -- Exclude schemas owned by extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_namespace'::REGCLASS
+            AND depend.objid = pg_namespace.oid
+            AND depend.deptype = 'e'
+    );
+
+-- name: GetPublications :many
+SELECT
+    pub.oid,
+    pub.pubname::TEXT AS publication_name,
+    pub.puballtables AS all_tables,
+    pub.pubinsert AS publish_insert,
+    pub.pubupdate AS publish_update,
+    pub.pubdelete AS publish_delete,
+    pub.pubtruncate AS publish_truncate
+FROM pg_catalog.pg_publication AS pub;
+
+-- name: GetPublicationTables :many
+SELECT
+    c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name
+FROM pg_catalog.pg_publication_rel AS pub_rel
+INNER JOIN pg_catalog.pg_class AS c ON pub_rel.prrelid = c.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+WHERE pub_rel.prpubid = sqlc.arg(publication_oid)::OID
+ORDER BY table_namespace.nspname, c.relname;
+
+-- name: GetPrivileges :many
+SELECT
+    (
+        CASE WHEN c.relkind = 'S' THEN 'SEQUENCE' ELSE 'TABLE' END
+    )::TEXT AS object_type,
+    c.relname::TEXT AS object_name,
+    ''::TEXT AS object_identity_arguments,
+    c_namespace.nspname::TEXT AS object_schema_name,
+    COALESCE(grantee.rolname, 'PUBLIC')::TEXT AS grantee,
+    acl.privilege_type::TEXT AS privilege_type,
+    acl.is_grantable AS is_grantable
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS c_namespace
+    ON c.relnamespace = c_namespace.oid
+CROSS JOIN LATERAL pg_catalog.ACLEXPLODE(
+    COALESCE(
+        c.relacl,
+        pg_catalog.ACLDEFAULT(
+            (CASE WHEN c.relkind = 'S' THEN 's' ELSE 'r' END)::"char",
+            c.relowner
+        )
+    )
+) AS acl
+LEFT JOIN pg_catalog.pg_roles AS grantee ON acl.grantee = grantee.oid
+WHERE
+    c_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND c_namespace.nspname !~ '^pg_toast'
+    AND c_namespace.nspname !~ '^pg_temp'
+    AND c.relkind IN ('r', 'p', 'v', 'm', 'f', 'S')
+    -- Exclude the privileges the owner implicitly holds
+    AND acl.grantee != c.relowner
+    -- Exclude relations belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_class'::REGCLASS
+            AND depend.objid = c.oid
+            AND depend.deptype = 'e'
+    )
+UNION ALL
+SELECT
+    (
+        CASE WHEN p.prokind = 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END
+    )::TEXT AS object_type,
+    p.proname::TEXT AS object_name,
+    pg_catalog.PG_GET_FUNCTION_IDENTITY_ARGUMENTS(
+        p.oid
+    )::TEXT AS object_identity_arguments,
+    p_namespace.nspname::TEXT AS object_schema_name,
+    COALESCE(grantee.rolname, 'PUBLIC')::TEXT AS grantee,
+    acl.privilege_type::TEXT AS privilege_type,
+    acl.is_grantable AS is_grantable
+FROM pg_catalog.pg_proc AS p
+INNER JOIN
+    pg_catalog.pg_namespace AS p_namespace
+    ON p.pronamespace = p_namespace.oid
+CROSS JOIN LATERAL pg_catalog.ACLEXPLODE(
+    COALESCE(p.proacl, pg_catalog.ACLDEFAULT('f', p.proowner))
+) AS acl
+LEFT JOIN pg_catalog.pg_roles AS grantee ON acl.grantee = grantee.oid
+WHERE
+    p_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND p_namespace.nspname !~ '^pg_toast'
+    AND p_namespace.nspname !~ '^pg_temp'
+    AND p.prokind IN ('f', 'p')
+    -- Exclude the privileges the owner implicitly holds
+    AND acl.grantee != p.proowner
+    -- Exclude routines belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_proc'::REGCLASS
+            AND depend.objid = p.oid
+            AND depend.deptype = 'e'
+    );
+
+-- name: GetStatisticsObjects :many
+SELECT
+    stat.stxname::TEXT AS statistics_name,
+    stat_namespace.nspname::TEXT AS statistics_schema_name,
+    table_c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name,
+    stat.stxkind::TEXT [] AS kinds,
+    (
+        SELECT
+            ARRAY_AGG(
+                att.attname
+                ORDER BY stxkey_ord.ord
+            )
+        FROM UNNEST(stat.stxkeys) WITH ORDINALITY AS stxkey_ord (attnum, ord)
+        INNER JOIN
+            pg_catalog.pg_attribute AS att
+            ON att.attrelid = table_c.oid AND stxkey_ord.attnum = att.attnum
+    )::TEXT [] AS column_names,
+    -- The statistics target is NULL by default as of Postgres 17 and -1 by default in earlier versions
+    COALESCE(stat.stxstattarget, -1)::INT AS statistics_target,
+    pg_catalog.pg_get_statisticsobjdef(stat.oid)::TEXT AS def
+FROM pg_catalog.pg_statistic_ext AS stat
+INNER JOIN
+    pg_catalog.pg_namespace AS stat_namespace
+    ON stat.stxnamespace = stat_namespace.oid
+INNER JOIN pg_catalog.pg_class AS table_c ON stat.stxrelid = table_c.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON table_c.relnamespace = table_namespace.oid
+WHERE
+    table_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND table_namespace.nspname !~ '^pg_toast'
+    AND table_namespace.nspname !~ '^pg_temp'
+    AND table_c.relkind IN ('r', 'p')
+    -- Exclude statistics objects of tables belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_class'::REGCLASS
+            AND depend.objid = table_c.oid
+            AND depend.deptype = 'e'
+    );
@@ -12,6 +12,170 @@ import (
 	"github.com/lib/pq"
 )
 
+const getAggregates = `-- name: GetAggregates :many
+SELECT
+    p.proname::TEXT AS aggregate_name,
+    p_namespace.nspname::TEXT AS aggregate_schema_name,
+    pg_catalog.pg_get_function_identity_arguments(
+        p.oid
+    )::TEXT AS aggregate_identity_arguments,
+    pg_catalog.format_type(agg.aggtranstype, NULL)::TEXT AS state_type,
+    state_fn.proname::TEXT AS state_func_name,
+    state_fn_namespace.nspname::TEXT AS state_func_schema_name,
+    pg_catalog.pg_get_function_identity_arguments(
+        state_fn.oid
+    )::TEXT AS state_func_identity_arguments,
+    COALESCE(final_fn.proname, '')::TEXT AS final_func_name,
+    COALESCE(final_fn_namespace.nspname, '')::TEXT AS final_func_schema_name,
+    COALESCE(
+        pg_catalog.pg_get_function_identity_arguments(final_fn.oid), ''
+    )::TEXT AS final_func_identity_arguments,
+    agg.aggfinalextra AS final_func_extra,
+    COALESCE(combine_fn.proname, '')::TEXT AS combine_func_name,
+    COALESCE(combine_fn_namespace.nspname, '')::TEXT AS combine_func_schema_name,
+    COALESCE(
+        pg_catalog.pg_get_function_identity_arguments(combine_fn.oid), ''
+    )::TEXT AS combine_func_identity_arguments,
+    COALESCE(serial_fn.proname, '')::TEXT AS serial_func_name,
+    COALESCE(serial_fn_namespace.nspname, '')::TEXT AS serial_func_schema_name,
+    COALESCE(
+        pg_catalog.pg_get_function_identity_arguments(serial_fn.oid), ''
+    )::TEXT AS serial_func_identity_arguments,
+    COALESCE(deserial_fn.proname, '')::TEXT AS deserial_func_name,
+    COALESCE(
+        deserial_fn_namespace.nspname, ''
+    )::TEXT AS deserial_func_schema_name,
+    COALESCE(
+        pg_catalog.pg_get_function_identity_arguments(deserial_fn.oid), ''
+    )::TEXT AS deserial_func_identity_arguments,
+    -- An empty initial condition is distinct from no initial condition
+    agg.agginitval IS NOT NULL AS has_initial_condition,
+    COALESCE(agg.agginitval, '')::TEXT AS initial_condition,
+    (
+        CASE p.proparallel
+            WHEN 's' THEN 'SAFE'
+            WHEN 'r' THEN 'RESTRICTED'
+            ELSE 'UNSAFE'
+        END
+    )::TEXT AS parallel
+FROM pg_catalog.pg_aggregate AS agg
+INNER JOIN pg_catalog.pg_proc AS p ON agg.aggfnoid = p.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS p_namespace
+    ON p.pronamespace = p_namespace.oid
+INNER JOIN pg_catalog.pg_proc AS state_fn ON agg.aggtransfn = state_fn.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS state_fn_namespace
+    ON state_fn.pronamespace = state_fn_namespace.oid
+-- The optional support functions are zero if they are not set
+LEFT JOIN pg_catalog.pg_proc AS final_fn ON agg.aggfinalfn = final_fn.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS final_fn_namespace
+    ON final_fn.pronamespace = final_fn_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_proc AS combine_fn
+    ON agg.aggcombinefn = combine_fn.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS combine_fn_namespace
+    ON combine_fn.pronamespace = combine_fn_namespace.oid
+LEFT JOIN pg_catalog.pg_proc AS serial_fn ON agg.aggserialfn = serial_fn.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS serial_fn_namespace
+    ON serial_fn.pronamespace = serial_fn_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_proc AS deserial_fn
+    ON agg.aggdeserialfn = deserial_fn.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS deserial_fn_namespace
+    ON deserial_fn.pronamespace = deserial_fn_namespace.oid
+WHERE
+    p_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND p_namespace.nspname !~ '^pg_toast'
+    AND p_namespace.nspname !~ '^pg_temp'
+    -- Exclude aggregates belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_proc'::REGCLASS
+            AND depend.objid = p.oid
+            AND depend.deptype = 'e'
+    )
+`
+
+type GetAggregatesRow struct {
+	AggregateName                 string
+	AggregateSchemaName           string
+	AggregateIdentityArguments    string
+	StateType                     string
+	StateFuncName                 string
+	StateFuncSchemaName           string
+	StateFuncIdentityArguments    string
+	FinalFuncName                 string
+	FinalFuncSchemaName           string
+	FinalFuncIdentityArguments    string
+	FinalFuncExtra                bool
+	CombineFuncName               string
+	CombineFuncSchemaName         string
+	CombineFuncIdentityArguments  string
+	SerialFuncName                string
+	SerialFuncSchemaName          string
+	SerialFuncIdentityArguments   string
+	DeserialFuncName              string
+	DeserialFuncSchemaName        string
+	DeserialFuncIdentityArguments string
+	HasInitialCondition           bool
+	InitialCondition              string
+	Parallel                      string
+}
+
+func (q *Queries) GetAggregates(ctx context.Context) ([]GetAggregatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAggregates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAggregatesRow
+	for rows.Next() {
+		var i GetAggregatesRow
+		if err := rows.Scan(
+			&i.AggregateName,
+			&i.AggregateSchemaName,
+			&i.AggregateIdentityArguments,
+			&i.StateType,
+			&i.StateFuncName,
+			&i.StateFuncSchemaName,
+			&i.StateFuncIdentityArguments,
+			&i.FinalFuncName,
+			&i.FinalFuncSchemaName,
+			&i.FinalFuncIdentityArguments,
+			&i.FinalFuncExtra,
+			&i.CombineFuncName,
+			&i.CombineFuncSchemaName,
+			&i.CombineFuncIdentityArguments,
+			&i.SerialFuncName,
+			&i.SerialFuncSchemaName,
+			&i.SerialFuncIdentityArguments,
+			&i.DeserialFuncName,
+			&i.DeserialFuncSchemaName,
+			&i.DeserialFuncIdentityArguments,
+			&i.HasInitialCondition,
+			&i.InitialCondition,
+			&i.Parallel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getCheckConstraints = `-- name: GetCheckConstraints :many
 SELECT
     pg_constraint.oid,
@@ -88,6 +252,97 @@ func (q *Queries) GetCheckConstraints(ctx context.Context) ([]GetCheckConstraint
 	return items, nil
 }
 
+const getCollations = `-- name: GetCollations :many
+SELECT
+    coll.collname::TEXT AS collation_name,
+    coll_namespace.nspname::TEXT AS collation_schema_name,
+    (
+        CASE coll.collprovider
+            WHEN 'i' THEN 'icu'
+            WHEN 'b' THEN 'builtin'
+            ELSE 'libc'
+        END
+    )::TEXT AS provider,
+    -- The column holding the ICU and builtin locales was renamed across versions, so it is read via to_jsonb
+    (
+        CASE
+            WHEN coll.collprovider = 'c' THEN ''
+            ELSE
+                COALESCE(
+                    TO_JSONB(coll) ->> 'colllocale',
+                    TO_JSONB(coll) ->> 'colliculocale',
+                    coll.collcollate,
+                    ''
+                )
+        END
+    )::TEXT AS locale,
+    (
+        CASE WHEN coll.collprovider = 'c' THEN coll.collcollate ELSE '' END
+    )::TEXT AS lc_collate,
+    (
+        CASE WHEN coll.collprovider = 'c' THEN coll.collctype ELSE '' END
+    )::TEXT AS lc_ctype,
+    coll.collisdeterministic AS is_deterministic
+FROM pg_catalog.pg_collation AS coll
+INNER JOIN
+    pg_catalog.pg_namespace AS coll_namespace
+    ON coll.collnamespace = coll_namespace.oid
+WHERE
+    coll_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND coll_namespace.nspname !~ '^pg_toast'
+    AND coll_namespace.nspname !~ '^pg_temp'
+    -- Exclude collations belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_collation'::REGCLASS
+            AND ext_depend.objid = coll.oid
+            AND ext_depend.deptype = 'e'
+    )
+`
+
+type GetCollationsRow struct {
+	CollationName       string
+	CollationSchemaName string
+	Provider            string
+	Locale              string
+	LcCollate           string
+	LcCtype             string
+	IsDeterministic     bool
+}
+
+func (q *Queries) GetCollations(ctx context.Context) ([]GetCollationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCollations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCollationsRow
+	for rows.Next() {
+		var i GetCollationsRow
+		if err := rows.Scan(
+			&i.CollationName,
+			&i.CollationSchemaName,
+			&i.Provider,
+			&i.Locale,
+			&i.LcCollate,
+			&i.LcCtype,
+			&i.IsDeterministic,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getColumnsForTable = `-- name: GetColumnsForTable :many
 WITH identity_col_seq AS (
     SELECT
@@ -129,8 +384,26 @@ SELECT
     identity_col_seq.seqmin AS min_value,
     identity_col_seq.seqcache AS cache_size,
     identity_col_seq.seqcycle AS is_cycle,
-    pg_catalog.format_type(a.atttypid, a.atttypmod) AS column_type
+    pg_catalog.format_type(a.atttypid, a.atttypmod) AS column_type,
+    a.attstorage::TEXT AS storage_type,
+    column_type.typstorage::TEXT AS type_storage_type,
+    (a.attgenerated = 's') AS is_generated,
+    (NOT a.attislocal) AS is_inherited,
+    -- Dependencies on built-in functions are not recorded in pg_depend, so find the functions called by the default
+    -- via the function ids in its expression tree
+    COALESCE((
+        SELECT BOOL_OR(default_func.provolatile = 'v')
+        FROM
+            REGEXP_MATCHES(
+                d.adbin::TEXT, ':(?:op)?funcid (\d+)', 'g'
+            ) AS func_ref (func_id)
+        INNER JOIN
+            pg_catalog.pg_proc AS default_func
+            ON func_ref.func_id[1]::OID = default_func.oid
+    ), false) AS is_default_volatile,
+    COALESCE(description.description, '')::TEXT AS comment
 FROM pg_catalog.pg_attribute AS a
+INNER JOIN pg_catalog.pg_type AS column_type ON a.atttypid = column_type.oid
 LEFT JOIN
     pg_catalog.pg_attrdef AS d
     ON (a.attrelid = d.adrelid AND a.attnum = d.adnum)
@@ -143,6 +416,12 @@ LEFT JOIN
     ON
         a.attrelid = identity_col_seq.owner_relid
         AND a.attnum = identity_col_seq.owner_attnum
+LEFT JOIN
+    pg_catalog.pg_description AS description
+    ON
+        a.attrelid = description.objoid
+        AND description.classoid = 'pg_class'::REGCLASS
+        AND a.attnum = description.objsubid
 WHERE
     a.attrelid = $1
     AND a.attnum > 0
@@ -165,6 +444,12 @@ type GetColumnsForTableRow struct {
 	CacheSize           sql.NullInt64
 	IsCycle             sql.NullBool
 	ColumnType          string
+	StorageType         string
+	TypeStorageType     string
+	IsGenerated         bool
+	IsInherited         bool
+	IsDefaultVolatile   bool
+	Comment             string
 }
 
 func (q *Queries) GetColumnsForTable(ctx context.Context, attrelid interface{}) ([]GetColumnsForTableRow, error) {
@@ -191,6 +476,198 @@ func (q *Queries) GetColumnsForTable(ctx context.Context, attrelid interface{})
 			&i.CacheSize,
 			&i.IsCycle,
 			&i.ColumnType,
+			&i.StorageType,
+			&i.TypeStorageType,
+			&i.IsGenerated,
+			&i.IsInherited,
+			&i.IsDefaultVolatile,
+			&i.Comment,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCompositeTypes = `-- name: GetCompositeTypes :many
+SELECT
+    pg_type.typname::TEXT AS type_name,
+    type_namespace.nspname::TEXT AS type_schema_name,
+    COALESCE(attrs.attribute_names, '{}')::TEXT [] AS attribute_names,
+    COALESCE(attrs.attribute_types, '{}')::TEXT [] AS attribute_types,
+    COALESCE(
+        attrs.attribute_collation_names, '{}'
+    )::TEXT [] AS attribute_collation_names,
+    COALESCE(
+        attrs.attribute_collation_schema_names, '{}'
+    )::TEXT [] AS attribute_collation_schema_names
+FROM pg_catalog.pg_type AS pg_type
+INNER JOIN
+    pg_catalog.pg_namespace AS type_namespace
+    ON pg_type.typnamespace = type_namespace.oid
+INNER JOIN
+    pg_catalog.pg_class AS type_class
+    ON pg_type.typrelid = type_class.oid
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(a.attname ORDER BY a.attnum) AS attribute_names,
+        ARRAY_AGG(
+            pg_catalog.format_type(a.atttypid, a.atttypmod)
+            ORDER BY a.attnum
+        ) AS attribute_types,
+        ARRAY_AGG(
+            COALESCE(coll.collname, '') ORDER BY a.attnum
+        ) AS attribute_collation_names,
+        ARRAY_AGG(
+            COALESCE(collation_namespace.nspname, '') ORDER BY a.attnum
+        ) AS attribute_collation_schema_names
+    FROM pg_catalog.pg_attribute AS a
+    LEFT JOIN pg_catalog.pg_collation AS coll ON a.attcollation = coll.oid
+    LEFT JOIN
+        pg_catalog.pg_namespace AS collation_namespace
+        ON coll.collnamespace = collation_namespace.oid
+    WHERE
+        a.attrelid = type_class.oid
+        AND a.attnum > 0
+        AND NOT a.attisdropped
+) AS attrs ON true
+WHERE
+    pg_type.typtype = 'c'
+    -- Exclude the row types of tables, views, etc.
+    AND type_class.relkind = 'c'
+    AND type_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND type_namespace.nspname !~ '^pg_toast'
+    AND type_namespace.nspname !~ '^pg_temp'
+    -- Exclude composite types belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_type'::REGCLASS
+            AND ext_depend.objid = pg_type.oid
+            AND ext_depend.deptype = 'e'
+    )
+`
+
+type GetCompositeTypesRow struct {
+	TypeName                      string
+	TypeSchemaName                string
+	AttributeNames                []string
+	AttributeTypes                []string
+	AttributeCollationNames       []string
+	AttributeCollationSchemaNames []string
+}
+
+func (q *Queries) GetCompositeTypes(ctx context.Context) ([]GetCompositeTypesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCompositeTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCompositeTypesRow
+	for rows.Next() {
+		var i GetCompositeTypesRow
+		if err := rows.Scan(
+			&i.TypeName,
+			&i.TypeSchemaName,
+			pq.Array(&i.AttributeNames),
+			pq.Array(&i.AttributeTypes),
+			pq.Array(&i.AttributeCollationNames),
+			pq.Array(&i.AttributeCollationSchemaNames),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDefaultPrivileges = `-- name: GetDefaultPrivileges :many
+SELECT
+    grantor.rolname::TEXT AS grantor,
+    COALESCE(default_acl_namespace.nspname, '')::TEXT AS schema_name,
+    (
+        CASE default_acl.defaclobjtype
+            WHEN 'r' THEN 'TABLES'
+            WHEN 'S' THEN 'SEQUENCES'
+            WHEN 'f' THEN 'FUNCTIONS'
+            WHEN 'T' THEN 'TYPES'
+            WHEN 'n' THEN 'SCHEMAS'
+        END
+    )::TEXT AS object_type,
+    COALESCE(grantee.rolname, 'PUBLIC')::TEXT AS grantee,
+    acl.privilege_type::TEXT AS privilege_type,
+    acl.is_grantable AS is_grantable
+FROM pg_catalog.pg_default_acl AS default_acl
+INNER JOIN pg_catalog.pg_roles AS grantor ON default_acl.defaclrole = grantor.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS default_acl_namespace
+    ON default_acl.defaclnamespace = default_acl_namespace.oid
+CROSS JOIN LATERAL pg_catalog.ACLEXPLODE(default_acl.defaclacl) AS acl
+LEFT JOIN pg_catalog.pg_roles AS grantee ON acl.grantee = grantee.oid
+WHERE
+    -- Default privileges that apply to all schemas store the complete set of privileges, including the built-in
+    -- defaults, e.g., EXECUTE on functions for PUBLIC. Exclude the built-in defaults, since they are implicit
+    default_acl.defaclnamespace != 0
+    OR NOT EXISTS (
+        SELECT builtin_acl.grantee
+        FROM
+            pg_catalog.ACLEXPLODE(
+                pg_catalog.ACLDEFAULT(
+                    (
+                        CASE
+                            WHEN default_acl.defaclobjtype = 'S' THEN 's'
+                            ELSE default_acl.defaclobjtype
+                        END
+                    )::"char",
+                    default_acl.defaclrole
+                )
+            ) AS builtin_acl
+        WHERE
+            builtin_acl.grantee = acl.grantee
+            AND builtin_acl.privilege_type = acl.privilege_type
+            AND builtin_acl.is_grantable = acl.is_grantable
+    )
+`
+
+type GetDefaultPrivilegesRow struct {
+	Grantor       string
+	SchemaName    string
+	ObjectType    string
+	Grantee       string
+	PrivilegeType string
+	IsGrantable   bool
+}
+
+func (q *Queries) GetDefaultPrivileges(ctx context.Context) ([]GetDefaultPrivilegesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDefaultPrivileges)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDefaultPrivilegesRow
+	for rows.Next() {
+		var i GetDefaultPrivilegesRow
+		if err := rows.Scan(
+			&i.Grantor,
+			&i.SchemaName,
+			&i.ObjectType,
+			&i.Grantee,
+			&i.PrivilegeType,
+			&i.IsGrantable,
 		); err != nil {
 			return nil, err
 		}
@@ -260,6 +737,100 @@ func (q *Queries) GetDependsOnFunctions(ctx context.Context, arg GetDependsOnFun
 	return items, nil
 }
 
+const getDomains = `-- name: GetDomains :many
+SELECT
+    pg_type.typname::TEXT AS domain_name,
+    type_namespace.nspname::TEXT AS domain_schema_name,
+    pg_catalog.format_type(
+        pg_type.typbasetype, pg_type.typtypmod
+    )::TEXT AS base_type,
+    COALESCE(coll.collname, '')::TEXT AS collation_name,
+    COALESCE(collation_namespace.nspname, '')::TEXT AS collation_schema_name,
+    COALESCE(pg_type.typdefault, '')::TEXT AS default_value,
+    pg_type.typnotnull AS is_not_null,
+    COALESCE(cons.constraint_names, '{}')::TEXT [] AS constraint_names,
+    COALESCE(cons.constraint_defs, '{}')::TEXT [] AS constraint_defs
+FROM pg_catalog.pg_type AS pg_type
+INNER JOIN
+    pg_catalog.pg_namespace AS type_namespace
+    ON pg_type.typnamespace = type_namespace.oid
+LEFT JOIN pg_catalog.pg_collation AS coll ON pg_type.typcollation = coll.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS collation_namespace
+    ON coll.collnamespace = collation_namespace.oid
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(con.conname ORDER BY con.conname) AS constraint_names,
+        ARRAY_AGG(
+            pg_catalog.pg_get_constraintdef(con.oid) ORDER BY con.conname
+        ) AS constraint_defs
+    FROM pg_catalog.pg_constraint AS con
+    WHERE
+        con.contypid = pg_type.oid
+        -- NOT NULL is tracked via pg_type.typnotnull
+        AND con.contype = 'c'
+) AS cons ON true
+WHERE
+    pg_type.typtype = 'd'
+    AND type_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND type_namespace.nspname !~ '^pg_toast'
+    AND type_namespace.nspname !~ '^pg_temp'
+    -- Exclude domains belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_type'::REGCLASS
+            AND ext_depend.objid = pg_type.oid
+            AND ext_depend.deptype = 'e'
+    )
+`
+
+type GetDomainsRow struct {
+	DomainName          string
+	DomainSchemaName    string
+	BaseType            string
+	CollationName       string
+	CollationSchemaName string
+	DefaultValue        string
+	IsNotNull           bool
+	ConstraintNames     []string
+	ConstraintDefs      []string
+}
+
+func (q *Queries) GetDomains(ctx context.Context) ([]GetDomainsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDomains)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDomainsRow
+	for rows.Next() {
+		var i GetDomainsRow
+		if err := rows.Scan(
+			&i.DomainName,
+			&i.DomainSchemaName,
+			&i.BaseType,
+			&i.CollationName,
+			&i.CollationSchemaName,
+			&i.DefaultValue,
+			&i.IsNotNull,
+			pq.Array(&i.ConstraintNames),
+			pq.Array(&i.ConstraintDefs),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getEnums = `-- name: GetEnums :many
 SELECT
     pg_type.typname::TEXT AS enum_name,
@@ -424,6 +995,75 @@ func (q *Queries) GetExtensions(ctx context.Context) ([]GetExtensionsRow, error)
 	return items, nil
 }
 
+const getForeignDataWrappers = `-- name: GetForeignDataWrappers :many
+SELECT
+    fdw.fdwname::TEXT AS wrapper_name,
+    COALESCE(handler_proc.proname, '')::TEXT AS handler_name,
+    COALESCE(handler_namespace.nspname, '')::TEXT AS handler_schema_name,
+    COALESCE(validator_proc.proname, '')::TEXT AS validator_name,
+    COALESCE(validator_namespace.nspname, '')::TEXT AS validator_schema_name,
+    COALESCE(fdw.fdwoptions, '{}')::TEXT [] AS options
+FROM pg_catalog.pg_foreign_data_wrapper AS fdw
+LEFT JOIN pg_catalog.pg_proc AS handler_proc ON fdw.fdwhandler = handler_proc.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS handler_namespace
+    ON handler_proc.pronamespace = handler_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_proc AS validator_proc
+    ON fdw.fdwvalidator = validator_proc.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS validator_namespace
+    ON validator_proc.pronamespace = validator_namespace.oid
+-- Exclude foreign-data wrappers belonging to extensions, e.g., postgres_fdw
+WHERE NOT EXISTS (
+    SELECT ext_depend.objid
+    FROM pg_catalog.pg_depend AS ext_depend
+    WHERE
+        ext_depend.classid = 'pg_foreign_data_wrapper'::REGCLASS
+        AND ext_depend.objid = fdw.oid
+        AND ext_depend.deptype = 'e'
+)
+`
+
+type GetForeignDataWrappersRow struct {
+	WrapperName         string
+	HandlerName         string
+	HandlerSchemaName   string
+	ValidatorName       string
+	ValidatorSchemaName string
+	Options             []string
+}
+
+func (q *Queries) GetForeignDataWrappers(ctx context.Context) ([]GetForeignDataWrappersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getForeignDataWrappers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetForeignDataWrappersRow
+	for rows.Next() {
+		var i GetForeignDataWrappersRow
+		if err := rows.Scan(
+			&i.WrapperName,
+			&i.HandlerName,
+			&i.HandlerSchemaName,
+			&i.ValidatorName,
+			&i.ValidatorSchemaName,
+			pq.Array(&i.Options),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getForeignKeyConstraints = `-- name: GetForeignKeyConstraints :many
 SELECT
     pg_constraint.conname::TEXT AS constraint_name,
@@ -432,6 +1072,8 @@ SELECT
     foreign_table_c.relname::TEXT AS foreign_table_name,
     foreign_table_namespace.nspname::TEXT AS foreign_table_schema_name,
     pg_constraint.convalidated AS is_valid,
+    pg_constraint.condeferrable AS is_deferrable,
+    pg_constraint.condeferred AS is_initially_deferred,
     pg_catalog.pg_get_constraintdef(pg_constraint.oid) AS constraint_def
 FROM pg_catalog.pg_constraint
 INNER JOIN
@@ -460,6 +1102,8 @@ type GetForeignKeyConstraintsRow struct {
 	ForeignTableName       string
 	ForeignTableSchemaName string
 	IsValid                bool
+	IsDeferrable           bool
+	IsInitiallyDeferred    bool
 	ConstraintDef          string
 }
 
@@ -479,6 +1123,8 @@ func (q *Queries) GetForeignKeyConstraints(ctx context.Context) ([]GetForeignKey
 			&i.ForeignTableName,
 			&i.ForeignTableSchemaName,
 			&i.IsValid,
+			&i.IsDeferrable,
+			&i.IsInitiallyDeferred,
 			&i.ConstraintDef,
 		); err != nil {
 			return nil, err
@@ -494,11 +1140,135 @@ func (q *Queries) GetForeignKeyConstraints(ctx context.Context) ([]GetForeignKey
 	return items, nil
 }
 
-const getFunctionTableDependencies = `-- name: GetFunctionTableDependencies :many
-SELECT DISTINCT
-    depends_on_c.relname::TEXT AS depends_on_table_name,
-    depends_on_ns.nspname::TEXT AS depends_on_table_schema_name
-FROM pg_catalog.pg_depend AS depend
+const getForeignServers = `-- name: GetForeignServers :many
+SELECT
+    srv.srvname::TEXT AS server_name,
+    fdw.fdwname::TEXT AS wrapper_name,
+    COALESCE(srv.srvtype, '')::TEXT AS server_type,
+    COALESCE(srv.srvversion, '')::TEXT AS server_version,
+    COALESCE(srv.srvoptions, '{}')::TEXT [] AS options
+FROM pg_catalog.pg_foreign_server AS srv
+INNER JOIN
+    pg_catalog.pg_foreign_data_wrapper AS fdw
+    ON srv.srvfdw = fdw.oid
+-- Exclude foreign servers belonging to extensions
+WHERE NOT EXISTS (
+    SELECT ext_depend.objid
+    FROM pg_catalog.pg_depend AS ext_depend
+    WHERE
+        ext_depend.classid = 'pg_foreign_server'::REGCLASS
+        AND ext_depend.objid = srv.oid
+        AND ext_depend.deptype = 'e'
+)
+`
+
+type GetForeignServersRow struct {
+	ServerName    string
+	WrapperName   string
+	ServerType    string
+	ServerVersion string
+	Options       []string
+}
+
+func (q *Queries) GetForeignServers(ctx context.Context) ([]GetForeignServersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getForeignServers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetForeignServersRow
+	for rows.Next() {
+		var i GetForeignServersRow
+		if err := rows.Scan(
+			&i.ServerName,
+			&i.WrapperName,
+			&i.ServerType,
+			&i.ServerVersion,
+			pq.Array(&i.Options),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getForeignTables = `-- name: GetForeignTables :many
+SELECT
+    c.oid,
+    c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name,
+    srv.srvname::TEXT AS server_name,
+    COALESCE(ft.ftoptions, '{}')::TEXT [] AS options
+FROM pg_catalog.pg_foreign_table AS ft
+INNER JOIN pg_catalog.pg_class AS c ON ft.ftrelid = c.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+INNER JOIN pg_catalog.pg_foreign_server AS srv ON ft.ftserver = srv.oid
+WHERE
+    table_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND table_namespace.nspname !~ '^pg_toast'
+    AND table_namespace.nspname !~ '^pg_temp'
+    -- Exclude foreign tables belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_class'::REGCLASS
+            AND ext_depend.objid = c.oid
+            AND ext_depend.deptype = 'e'
+    )
+`
+
+type GetForeignTablesRow struct {
+	Oid             interface{}
+	TableName       string
+	TableSchemaName string
+	ServerName      string
+	Options         []string
+}
+
+func (q *Queries) GetForeignTables(ctx context.Context) ([]GetForeignTablesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getForeignTables)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetForeignTablesRow
+	for rows.Next() {
+		var i GetForeignTablesRow
+		if err := rows.Scan(
+			&i.Oid,
+			&i.TableName,
+			&i.TableSchemaName,
+			&i.ServerName,
+			pq.Array(&i.Options),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFunctionTableDependencies = `-- name: GetFunctionTableDependencies :many
+SELECT DISTINCT
+    depends_on_c.relname::TEXT AS depends_on_table_name,
+    depends_on_ns.nspname::TEXT AS depends_on_table_schema_name
+FROM pg_catalog.pg_depend AS depend
 INNER JOIN pg_catalog.pg_class AS depends_on_c
     ON depend.refobjid = depends_on_c.oid
 INNER JOIN pg_catalog.pg_namespace AS depends_on_ns
@@ -540,6 +1310,77 @@ func (q *Queries) GetFunctionTableDependencies(ctx context.Context, functionOid
 	return items, nil
 }
 
+const getIndexOperatorClasses = `-- name: GetIndexOperatorClasses :many
+SELECT DISTINCT
+    c.relname::TEXT AS index_name,
+    c_namespace.nspname::TEXT AS index_schema_name,
+    opc.opcname::TEXT AS operator_class_name,
+    opc_namespace.nspname::TEXT AS operator_class_schema_name,
+    am.amname::TEXT AS index_method
+FROM pg_catalog.pg_index AS i
+INNER JOIN pg_catalog.pg_class AS c ON i.indexrelid = c.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS c_namespace
+    ON c.relnamespace = c_namespace.oid
+CROSS JOIN LATERAL UNNEST(i.indclass::OID []) AS indclass (opc_oid)
+INNER JOIN pg_catalog.pg_opclass AS opc ON indclass.opc_oid = opc.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS opc_namespace
+    ON opc.opcnamespace = opc_namespace.oid
+INNER JOIN pg_catalog.pg_am AS am ON opc.opcmethod = am.oid
+WHERE
+    c_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND c_namespace.nspname !~ '^pg_toast'
+    AND c_namespace.nspname !~ '^pg_temp'
+    -- Only operator classes that are tracked, i.e., not built-in and not belonging to extensions, are relevant
+    AND opc_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_opclass'::REGCLASS
+            AND depend.objid = opc.oid
+            AND depend.deptype = 'e'
+    )
+`
+
+type GetIndexOperatorClassesRow struct {
+	IndexName               string
+	IndexSchemaName         string
+	OperatorClassName       string
+	OperatorClassSchemaName string
+	IndexMethod             string
+}
+
+func (q *Queries) GetIndexOperatorClasses(ctx context.Context) ([]GetIndexOperatorClassesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getIndexOperatorClasses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetIndexOperatorClassesRow
+	for rows.Next() {
+		var i GetIndexOperatorClassesRow
+		if err := rows.Scan(
+			&i.IndexName,
+			&i.IndexSchemaName,
+			&i.OperatorClassName,
+			&i.OperatorClassSchemaName,
+			&i.IndexMethod,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getIndexes = `-- name: GetIndexes :many
 SELECT
     c.oid,
@@ -567,16 +1408,54 @@ SELECT
         INNER JOIN
             pg_catalog.pg_attribute AS att
             ON att.attrelid = table_c.oid AND indkey_ord.attnum = att.attnum
+        WHERE indkey_ord.ord <= i.indnkeyatts
     )::TEXT [] AS column_names,
-    COALESCE(con.conislocal, false) AS constraint_is_local
+    (
+        SELECT
+            ARRAY_AGG(
+                att.attname
+                ORDER BY indkey_ord.ord
+            )
+        FROM UNNEST(i.indkey) WITH ORDINALITY AS indkey_ord (attnum, ord)
+        INNER JOIN
+            pg_catalog.pg_attribute AS att
+            ON att.attrelid = table_c.oid AND indkey_ord.attnum = att.attnum
+        -- The columns after the key columns are the INCLUDE columns
+        WHERE indkey_ord.ord > i.indnkeyatts
+    )::TEXT [] AS included_column_names,
+    (
+        SELECT
+            ARRAY_AGG(
+                pg_catalog.pg_get_indexdef(c.oid, indkey_ord.ord::INT, true)
+                ORDER BY indkey_ord.ord
+            )
+        FROM UNNEST(i.indkey) WITH ORDINALITY AS indkey_ord (attnum, ord)
+        -- A zero entry in indkey indicates the column is an expression
+        WHERE indkey_ord.attnum = 0
+    )::TEXT [] AS expressions,
+    COALESCE(con.conislocal, false) AS constraint_is_local,
+    COALESCE(con.condeferrable, false) AS constraint_is_deferrable,
+    COALESCE(con.condeferred, false) AS constraint_is_initially_deferred,
+    COALESCE(
+        pg_catalog.pg_get_expr(i.indpred, i.indrelid), ''
+    )::TEXT AS predicate,
+    -- The tablespace is empty if the index is in the database's default tablespace
+    COALESCE(tablespace.spcname, '')::TEXT AS tablespace_name,
+    index_am.amname::TEXT AS index_method,
+    COALESCE(description.description, '')::TEXT AS comment,
+    COALESCE(c.reloptions, '{}')::TEXT [] AS storage_parameters
 FROM pg_catalog.pg_class AS c
 INNER JOIN pg_catalog.pg_index AS i ON (c.oid = i.indexrelid)
+INNER JOIN pg_catalog.pg_am AS index_am ON (c.relam = index_am.oid)
 INNER JOIN pg_catalog.pg_class AS table_c ON (i.indrelid = table_c.oid)
 INNER JOIN pg_catalog.pg_namespace AS table_namespace
     ON table_c.relnamespace = table_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_tablespace AS tablespace
+    ON c.reltablespace = tablespace.oid
 LEFT JOIN
     pg_catalog.pg_constraint AS con
-    ON (c.oid = con.conindid AND con.contype IN ('p', 'u', null))
+    ON (c.oid = con.conindid AND con.contype IN ('p', 'u', 'x', null))
 LEFT JOIN
     pg_catalog.pg_inherits AS idx_inherits
     ON (c.oid = idx_inherits.inhrelid)
@@ -586,6 +1465,12 @@ LEFT JOIN
 LEFT JOIN
     pg_catalog.pg_namespace AS parent_namespace
     ON parent_c.relnamespace = parent_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_description AS description
+    ON
+        c.oid = description.objoid
+        AND description.classoid = 'pg_class'::REGCLASS
+        AND description.objsubid = 0
 WHERE
     table_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
     AND table_namespace.nspname !~ '^pg_toast'
@@ -603,21 +1488,30 @@ WHERE
 `
 
 type GetIndexesRow struct {
-	Oid                   interface{}
-	IndexName             string
-	TableName             string
-	TableSchemaName       string
-	DefStmt               string
-	ConstraintName        string
-	ConstraintType        string
-	ConstraintDef         string
-	IndexIsValid          bool
-	IndexIsPk             bool
-	IndexIsUnique         bool
-	ParentIndexName       string
-	ParentIndexSchemaName string
-	ColumnNames           []string
-	ConstraintIsLocal     bool
+	Oid                           interface{}
+	IndexName                     string
+	TableName                     string
+	TableSchemaName               string
+	DefStmt                       string
+	ConstraintName                string
+	ConstraintType                string
+	ConstraintDef                 string
+	IndexIsValid                  bool
+	IndexIsPk                     bool
+	IndexIsUnique                 bool
+	ParentIndexName               string
+	ParentIndexSchemaName         string
+	ColumnNames                   []string
+	IncludedColumnNames           []string
+	Expressions                   []string
+	ConstraintIsLocal             bool
+	ConstraintIsDeferrable        bool
+	ConstraintIsInitiallyDeferred bool
+	Predicate                     string
+	TablespaceName                string
+	IndexMethod                   string
+	Comment                       string
+	StorageParameters             []string
 }
 
 func (q *Queries) GetIndexes(ctx context.Context) ([]GetIndexesRow, error) {
@@ -644,7 +1538,488 @@ func (q *Queries) GetIndexes(ctx context.Context) ([]GetIndexesRow, error) {
 			&i.ParentIndexName,
 			&i.ParentIndexSchemaName,
 			pq.Array(&i.ColumnNames),
+			pq.Array(&i.IncludedColumnNames),
+			pq.Array(&i.Expressions),
 			&i.ConstraintIsLocal,
+			&i.ConstraintIsDeferrable,
+			&i.ConstraintIsInitiallyDeferred,
+			&i.Predicate,
+			&i.TablespaceName,
+			&i.IndexMethod,
+			&i.Comment,
+			pq.Array(&i.StorageParameters),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMaterializedViews = `-- name: GetMaterializedViews :many
+SELECT
+    c.relname::TEXT AS materialized_view_name,
+    view_namespace.nspname::TEXT AS materialized_view_schema_name,
+    pg_catalog.pg_get_viewdef(c.oid, true) AS materialized_view_definition,
+    COALESCE(c.reloptions, '{}')::TEXT [] AS storage_parameters,
+    COALESCE(toast_c.reloptions, '{}')::TEXT [] AS toast_storage_parameters
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS view_namespace
+    ON c.relnamespace = view_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_class AS toast_c
+    ON c.reltoastrelid = toast_c.oid
+WHERE
+    view_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND view_namespace.nspname !~ '^pg_toast'
+    AND view_namespace.nspname !~ '^pg_temp'
+    AND c.relkind = 'm'
+    -- Exclude materialized views belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_class'::REGCLASS
+            AND depend.objid = c.oid
+            AND depend.deptype = 'e'
+    )
+`
+
+type GetMaterializedViewsRow struct {
+	MaterializedViewName       string
+	MaterializedViewSchemaName string
+	MaterializedViewDefinition string
+	StorageParameters          []string
+	ToastStorageParameters     []string
+}
+
+func (q *Queries) GetMaterializedViews(ctx context.Context) ([]GetMaterializedViewsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMaterializedViews)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMaterializedViewsRow
+	for rows.Next() {
+		var i GetMaterializedViewsRow
+		if err := rows.Scan(
+			&i.MaterializedViewName,
+			&i.MaterializedViewSchemaName,
+			&i.MaterializedViewDefinition,
+			pq.Array(&i.StorageParameters),
+			pq.Array(&i.ToastStorageParameters),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getObjectOwners = `-- name: GetObjectOwners :many
+SELECT
+    c.relname::TEXT AS object_name,
+    c_namespace.nspname::TEXT AS object_schema_name,
+    pg_catalog.pg_get_userbyid(c.relowner)::TEXT AS owner_name
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS c_namespace
+    ON c.relnamespace = c_namespace.oid
+WHERE
+    c.relkind IN ('r', 'p', 'v', 'm', 'S', 'f')
+    AND c_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND c_namespace.nspname !~ '^pg_toast'
+    AND c_namespace.nspname !~ '^pg_temp'
+    -- Exclude relations belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_class'::REGCLASS
+            AND depend.objid = c.oid
+            AND depend.deptype = 'e'
+    )
+UNION ALL
+SELECT
+    pg_proc.proname::TEXT AS object_name,
+    proc_namespace.nspname::TEXT AS object_schema_name,
+    pg_catalog.pg_get_userbyid(pg_proc.proowner)::TEXT AS owner_name
+FROM pg_catalog.pg_proc AS pg_proc
+INNER JOIN
+    pg_catalog.pg_namespace AS proc_namespace
+    ON pg_proc.pronamespace = proc_namespace.oid
+WHERE
+    proc_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND proc_namespace.nspname !~ '^pg_toast'
+    AND proc_namespace.nspname !~ '^pg_temp'
+    -- Exclude functions belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_proc'::REGCLASS
+            AND depend.objid = pg_proc.oid
+            AND depend.deptype = 'e'
+    )
+UNION ALL
+SELECT
+    pg_namespace.nspname::TEXT AS object_name,
+    pg_namespace.nspname::TEXT AS object_schema_name,
+    pg_catalog.pg_get_userbyid(pg_namespace.nspowner)::TEXT AS owner_name
+FROM pg_catalog.pg_namespace AS pg_namespace
+WHERE
+    pg_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND pg_namespace.nspname !~ '^pg_toast'
+    AND pg_namespace.nspname !~ '^pg_temp'
+    -- Exclude schemas owned by extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_namespace'::REGCLASS
+            AND depend.objid = pg_namespace.oid
+            AND depend.deptype = 'e'
+    )
+`
+
+type GetObjectOwnersRow struct {
+	ObjectName       string
+	ObjectSchemaName string
+	OwnerName        string
+}
+
+func (q *Queries) GetObjectOwners(ctx context.Context) ([]GetObjectOwnersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getObjectOwners)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetObjectOwnersRow
+	for rows.Next() {
+		var i GetObjectOwnersRow
+		if err := rows.Scan(&i.ObjectName, &i.ObjectSchemaName, &i.OwnerName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOperatorClassFunctions = `-- name: GetOperatorClassFunctions :many
+SELECT
+    amproc.amprocnum::INT AS support_number,
+    pg_proc.proname::TEXT AS func_name,
+    proc_namespace.nspname::TEXT AS func_schema_name,
+    pg_catalog.pg_get_function_identity_arguments(
+        pg_proc.oid
+    ) AS func_identity_arguments
+FROM pg_catalog.pg_amproc AS amproc
+-- Members of an operator class have an internal dependency on the operator class
+INNER JOIN pg_catalog.pg_depend AS depend
+    ON
+        depend.classid = 'pg_amproc'::REGCLASS
+        AND depend.objid = amproc.oid
+INNER JOIN pg_catalog.pg_proc AS pg_proc ON amproc.amproc = pg_proc.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS proc_namespace
+    ON pg_proc.pronamespace = proc_namespace.oid
+WHERE
+    depend.refclassid = 'pg_opclass'::REGCLASS
+    AND depend.refobjid = $1::OID
+ORDER BY amproc.amprocnum
+`
+
+type GetOperatorClassFunctionsRow struct {
+	SupportNumber         int32
+	FuncName              string
+	FuncSchemaName        string
+	FuncIdentityArguments string
+}
+
+func (q *Queries) GetOperatorClassFunctions(ctx context.Context, operatorClassOid interface{}) ([]GetOperatorClassFunctionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOperatorClassFunctions, operatorClassOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOperatorClassFunctionsRow
+	for rows.Next() {
+		var i GetOperatorClassFunctionsRow
+		if err := rows.Scan(
+			&i.SupportNumber,
+			&i.FuncName,
+			&i.FuncSchemaName,
+			&i.FuncIdentityArguments,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOperatorClassOperators = `-- name: GetOperatorClassOperators :many
+SELECT
+    amop.amopstrategy::INT AS strategy_number,
+    op.oprname::TEXT AS operator_name,
+    op_namespace.nspname::TEXT AS operator_schema_name,
+    COALESCE(
+        pg_catalog.format_type(NULLIF(op.oprleft, 0), NULL), ''
+    )::TEXT AS left_type,
+    COALESCE(
+        pg_catalog.format_type(NULLIF(op.oprright, 0), NULL), ''
+    )::TEXT AS right_type
+FROM pg_catalog.pg_amop AS amop
+-- Members of an operator class have an internal dependency on the operator class
+INNER JOIN pg_catalog.pg_depend AS depend
+    ON
+        depend.classid = 'pg_amop'::REGCLASS
+        AND depend.objid = amop.oid
+INNER JOIN pg_catalog.pg_operator AS op ON amop.amopopr = op.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS op_namespace
+    ON op.oprnamespace = op_namespace.oid
+WHERE
+    depend.refclassid = 'pg_opclass'::REGCLASS
+    AND depend.refobjid = $1::OID
+ORDER BY amop.amopstrategy
+`
+
+type GetOperatorClassOperatorsRow struct {
+	StrategyNumber     int32
+	OperatorName       string
+	OperatorSchemaName string
+	LeftType           string
+	RightType          string
+}
+
+func (q *Queries) GetOperatorClassOperators(ctx context.Context, operatorClassOid interface{}) ([]GetOperatorClassOperatorsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOperatorClassOperators, operatorClassOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOperatorClassOperatorsRow
+	for rows.Next() {
+		var i GetOperatorClassOperatorsRow
+		if err := rows.Scan(
+			&i.StrategyNumber,
+			&i.OperatorName,
+			&i.OperatorSchemaName,
+			&i.LeftType,
+			&i.RightType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOperatorClasses = `-- name: GetOperatorClasses :many
+SELECT
+    opc.oid,
+    opc.opcname::TEXT AS operator_class_name,
+    opc_namespace.nspname::TEXT AS operator_class_schema_name,
+    am.amname::TEXT AS index_method,
+    opc.opcdefault AS is_default,
+    pg_catalog.format_type(opc.opcintype, NULL)::TEXT AS input_type,
+    COALESCE(
+        pg_catalog.format_type(NULLIF(opc.opckeytype, 0), NULL), ''
+    )::TEXT AS storage_type
+FROM pg_catalog.pg_opclass AS opc
+INNER JOIN
+    pg_catalog.pg_namespace AS opc_namespace
+    ON opc.opcnamespace = opc_namespace.oid
+INNER JOIN pg_catalog.pg_am AS am ON opc.opcmethod = am.oid
+WHERE
+    opc_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND opc_namespace.nspname !~ '^pg_toast'
+    AND opc_namespace.nspname !~ '^pg_temp'
+    -- Exclude operator classes belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_opclass'::REGCLASS
+            AND depend.objid = opc.oid
+            AND depend.deptype = 'e'
+    )
+`
+
+type GetOperatorClassesRow struct {
+	Oid                     interface{}
+	OperatorClassName       string
+	OperatorClassSchemaName string
+	IndexMethod             string
+	IsDefault               bool
+	InputType               string
+	StorageType             string
+}
+
+func (q *Queries) GetOperatorClasses(ctx context.Context) ([]GetOperatorClassesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOperatorClasses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOperatorClassesRow
+	for rows.Next() {
+		var i GetOperatorClassesRow
+		if err := rows.Scan(
+			&i.Oid,
+			&i.OperatorClassName,
+			&i.OperatorClassSchemaName,
+			&i.IndexMethod,
+			&i.IsDefault,
+			&i.InputType,
+			&i.StorageType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOperators = `-- name: GetOperators :many
+SELECT
+    op.oid,
+    op.oprname::TEXT AS operator_name,
+    op_namespace.nspname::TEXT AS operator_schema_name,
+    COALESCE(
+        pg_catalog.format_type(NULLIF(op.oprleft, 0), NULL), ''
+    )::TEXT AS left_type,
+    COALESCE(
+        pg_catalog.format_type(NULLIF(op.oprright, 0), NULL), ''
+    )::TEXT AS right_type,
+    pg_proc.proname::TEXT AS func_name,
+    proc_namespace.nspname::TEXT AS func_schema_name,
+    pg_catalog.pg_get_function_identity_arguments(
+        pg_proc.oid
+    ) AS func_identity_arguments,
+    COALESCE(com.oprname, '')::TEXT AS commutator_name,
+    COALESCE(com_namespace.nspname, '')::TEXT AS commutator_schema_name,
+    COALESCE(neg.oprname, '')::TEXT AS negator_name,
+    COALESCE(neg_namespace.nspname, '')::TEXT AS negator_schema_name,
+    COALESCE(NULLIF(op.oprrest::TEXT, '-'), '')::TEXT AS restrict_func,
+    COALESCE(NULLIF(op.oprjoin::TEXT, '-'), '')::TEXT AS join_func,
+    op.oprcanhash AS can_hash,
+    op.oprcanmerge AS can_merge
+FROM pg_catalog.pg_operator AS op
+INNER JOIN
+    pg_catalog.pg_namespace AS op_namespace
+    ON op.oprnamespace = op_namespace.oid
+-- Shell operators, i.e., operators only referenced as a commutator or negator, have no function
+INNER JOIN pg_catalog.pg_proc AS pg_proc ON op.oprcode = pg_proc.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS proc_namespace
+    ON pg_proc.pronamespace = proc_namespace.oid
+LEFT JOIN pg_catalog.pg_operator AS com ON op.oprcom = com.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS com_namespace
+    ON com.oprnamespace = com_namespace.oid
+LEFT JOIN pg_catalog.pg_operator AS neg ON op.oprnegate = neg.oid
+LEFT JOIN
+    pg_catalog.pg_namespace AS neg_namespace
+    ON neg.oprnamespace = neg_namespace.oid
+WHERE
+    op_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND op_namespace.nspname !~ '^pg_toast'
+    AND op_namespace.nspname !~ '^pg_temp'
+    -- Exclude operators belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_operator'::REGCLASS
+            AND depend.objid = op.oid
+            AND depend.deptype = 'e'
+    )
+`
+
+type GetOperatorsRow struct {
+	Oid                   interface{}
+	OperatorName          string
+	OperatorSchemaName    string
+	LeftType              string
+	RightType             string
+	FuncName              string
+	FuncSchemaName        string
+	FuncIdentityArguments string
+	CommutatorName        string
+	CommutatorSchemaName  string
+	NegatorName           string
+	NegatorSchemaName     string
+	RestrictFunc          string
+	JoinFunc              string
+	CanHash               bool
+	CanMerge              bool
+}
+
+func (q *Queries) GetOperators(ctx context.Context) ([]GetOperatorsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOperators)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOperatorsRow
+	for rows.Next() {
+		var i GetOperatorsRow
+		if err := rows.Scan(
+			&i.Oid,
+			&i.OperatorName,
+			&i.OperatorSchemaName,
+			&i.LeftType,
+			&i.RightType,
+			&i.FuncName,
+			&i.FuncSchemaName,
+			&i.FuncIdentityArguments,
+			&i.CommutatorName,
+			&i.CommutatorSchemaName,
+			&i.NegatorName,
+			&i.NegatorSchemaName,
+			&i.RestrictFunc,
+			&i.JoinFunc,
+			&i.CanHash,
+			&i.CanMerge,
 		); err != nil {
 			return nil, err
 		}
@@ -757,6 +2132,127 @@ func (q *Queries) GetPolicies(ctx context.Context) ([]GetPoliciesRow, error) {
 	return items, nil
 }
 
+const getPrivileges = `-- name: GetPrivileges :many
+SELECT
+    (
+        CASE WHEN c.relkind = 'S' THEN 'SEQUENCE' ELSE 'TABLE' END
+    )::TEXT AS object_type,
+    c.relname::TEXT AS object_name,
+    ''::TEXT AS object_identity_arguments,
+    c_namespace.nspname::TEXT AS object_schema_name,
+    COALESCE(grantee.rolname, 'PUBLIC')::TEXT AS grantee,
+    acl.privilege_type::TEXT AS privilege_type,
+    acl.is_grantable AS is_grantable
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS c_namespace
+    ON c.relnamespace = c_namespace.oid
+CROSS JOIN LATERAL pg_catalog.ACLEXPLODE(
+    COALESCE(
+        c.relacl,
+        pg_catalog.ACLDEFAULT(
+            (CASE WHEN c.relkind = 'S' THEN 's' ELSE 'r' END)::"char",
+            c.relowner
+        )
+    )
+) AS acl
+LEFT JOIN pg_catalog.pg_roles AS grantee ON acl.grantee = grantee.oid
+WHERE
+    c_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND c_namespace.nspname !~ '^pg_toast'
+    AND c_namespace.nspname !~ '^pg_temp'
+    AND c.relkind IN ('r', 'p', 'v', 'm', 'f', 'S')
+    -- Exclude the privileges the owner implicitly holds
+    AND acl.grantee != c.relowner
+    -- Exclude relations belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_class'::REGCLASS
+            AND depend.objid = c.oid
+            AND depend.deptype = 'e'
+    )
+UNION ALL
+SELECT
+    (
+        CASE WHEN p.prokind = 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END
+    )::TEXT AS object_type,
+    p.proname::TEXT AS object_name,
+    pg_catalog.PG_GET_FUNCTION_IDENTITY_ARGUMENTS(
+        p.oid
+    )::TEXT AS object_identity_arguments,
+    p_namespace.nspname::TEXT AS object_schema_name,
+    COALESCE(grantee.rolname, 'PUBLIC')::TEXT AS grantee,
+    acl.privilege_type::TEXT AS privilege_type,
+    acl.is_grantable AS is_grantable
+FROM pg_catalog.pg_proc AS p
+INNER JOIN
+    pg_catalog.pg_namespace AS p_namespace
+    ON p.pronamespace = p_namespace.oid
+CROSS JOIN LATERAL pg_catalog.ACLEXPLODE(
+    COALESCE(p.proacl, pg_catalog.ACLDEFAULT('f', p.proowner))
+) AS acl
+LEFT JOIN pg_catalog.pg_roles AS grantee ON acl.grantee = grantee.oid
+WHERE
+    p_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND p_namespace.nspname !~ '^pg_toast'
+    AND p_namespace.nspname !~ '^pg_temp'
+    AND p.prokind IN ('f', 'p')
+    -- Exclude the privileges the owner implicitly holds
+    AND acl.grantee != p.proowner
+    -- Exclude routines belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_proc'::REGCLASS
+            AND depend.objid = p.oid
+            AND depend.deptype = 'e'
+    )
+`
+
+type GetPrivilegesRow struct {
+	ObjectType              string
+	ObjectName              string
+	ObjectIdentityArguments string
+	ObjectSchemaName        string
+	Grantee                 string
+	PrivilegeType           string
+	IsGrantable             bool
+}
+
+func (q *Queries) GetPrivileges(ctx context.Context) ([]GetPrivilegesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPrivileges)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPrivilegesRow
+	for rows.Next() {
+		var i GetPrivilegesRow
+		if err := rows.Scan(
+			&i.ObjectType,
+			&i.ObjectName,
+			&i.ObjectIdentityArguments,
+			&i.ObjectSchemaName,
+			&i.Grantee,
+			&i.PrivilegeType,
+			&i.IsGrantable,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProcs = `-- name: GetProcs :many
 SELECT
     pg_proc.oid,
@@ -766,7 +2262,10 @@ SELECT
     pg_catalog.pg_get_function_identity_arguments(
         pg_proc.oid
     ) AS func_identity_arguments,
-    pg_catalog.pg_get_functiondef(pg_proc.oid) AS func_def
+    pg_catalog.pg_get_functiondef(pg_proc.oid) AS func_def,
+    (pg_proc.prokind = 'w') AS is_window,
+    pg_catalog.pg_get_userbyid(pg_proc.proowner)::TEXT AS owner_name,
+    COALESCE(description.description, '')::TEXT AS comment
 FROM pg_catalog.pg_proc
 INNER JOIN
     pg_catalog.pg_namespace AS proc_namespace
@@ -774,6 +2273,12 @@ INNER JOIN
 INNER JOIN
     pg_catalog.pg_language AS proc_lang
     ON pg_proc.prolang = proc_lang.oid
+LEFT JOIN
+    pg_catalog.pg_description AS description
+    ON
+        pg_proc.oid = description.objoid
+        AND description.classoid = 'pg_proc'::REGCLASS
+        AND description.objsubid = 0
 WHERE
     proc_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
     AND proc_namespace.nspname !~ '^pg_toast'
@@ -790,31 +2295,131 @@ WHERE
     )
 `
 
-type GetProcsRow struct {
-	Oid                   interface{}
-	FuncName              string
-	FuncSchemaName        string
-	FuncLang              string
-	FuncIdentityArguments string
-	FuncDef               string
+type GetProcsRow struct {
+	Oid                   interface{}
+	FuncName              string
+	FuncSchemaName        string
+	FuncLang              string
+	FuncIdentityArguments string
+	FuncDef               string
+	IsWindow              bool
+	OwnerName             string
+	Comment               string
+}
+
+func (q *Queries) GetProcs(ctx context.Context, prokind interface{}) ([]GetProcsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProcs, prokind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProcsRow
+	for rows.Next() {
+		var i GetProcsRow
+		if err := rows.Scan(
+			&i.Oid,
+			&i.FuncName,
+			&i.FuncSchemaName,
+			&i.FuncLang,
+			&i.FuncIdentityArguments,
+			&i.FuncDef,
+			&i.IsWindow,
+			&i.OwnerName,
+			&i.Comment,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublicationTables = `-- name: GetPublicationTables :many
+SELECT
+    c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name
+FROM pg_catalog.pg_publication_rel AS pub_rel
+INNER JOIN pg_catalog.pg_class AS c ON pub_rel.prrelid = c.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+WHERE pub_rel.prpubid = $1::OID
+ORDER BY table_namespace.nspname, c.relname
+`
+
+type GetPublicationTablesRow struct {
+	TableName       string
+	TableSchemaName string
+}
+
+func (q *Queries) GetPublicationTables(ctx context.Context, publicationOid interface{}) ([]GetPublicationTablesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPublicationTables, publicationOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPublicationTablesRow
+	for rows.Next() {
+		var i GetPublicationTablesRow
+		if err := rows.Scan(&i.TableName, &i.TableSchemaName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublications = `-- name: GetPublications :many
+SELECT
+    pub.oid,
+    pub.pubname::TEXT AS publication_name,
+    pub.puballtables AS all_tables,
+    pub.pubinsert AS publish_insert,
+    pub.pubupdate AS publish_update,
+    pub.pubdelete AS publish_delete,
+    pub.pubtruncate AS publish_truncate
+FROM pg_catalog.pg_publication AS pub
+`
+
+type GetPublicationsRow struct {
+	Oid             interface{}
+	PublicationName string
+	AllTables       bool
+	PublishInsert   bool
+	PublishUpdate   bool
+	PublishDelete   bool
+	PublishTruncate bool
 }
 
-func (q *Queries) GetProcs(ctx context.Context, prokind interface{}) ([]GetProcsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getProcs, prokind)
+func (q *Queries) GetPublications(ctx context.Context) ([]GetPublicationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPublications)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetProcsRow
+	var items []GetPublicationsRow
 	for rows.Next() {
-		var i GetProcsRow
+		var i GetPublicationsRow
 		if err := rows.Scan(
 			&i.Oid,
-			&i.FuncName,
-			&i.FuncSchemaName,
-			&i.FuncLang,
-			&i.FuncIdentityArguments,
-			&i.FuncDef,
+			&i.PublicationName,
+			&i.AllTables,
+			&i.PublishInsert,
+			&i.PublishUpdate,
+			&i.PublishDelete,
+			&i.PublishTruncate,
 		); err != nil {
 			return nil, err
 		}
@@ -830,7 +2435,9 @@ func (q *Queries) GetProcs(ctx context.Context, prokind interface{}) ([]GetProcs
 }
 
 const getSchemas = `-- name: GetSchemas :many
-SELECT nspname::TEXT AS schema_name
+SELECT
+    nspname::TEXT AS schema_name,
+    pg_catalog.pg_get_userbyid(nspowner)::TEXT AS owner_name
 FROM pg_catalog.pg_namespace
 WHERE
     nspname NOT IN ('pg_catalog', 'information_schema')
@@ -847,19 +2454,24 @@ WHERE
     )
 `
 
-func (q *Queries) GetSchemas(ctx context.Context) ([]string, error) {
+type GetSchemasRow struct {
+	SchemaName string
+	OwnerName  string
+}
+
+func (q *Queries) GetSchemas(ctx context.Context) ([]GetSchemasRow, error) {
 	rows, err := q.db.QueryContext(ctx, getSchemas)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []string
+	var items []GetSchemasRow
 	for rows.Next() {
-		var schema_name string
-		if err := rows.Scan(&schema_name); err != nil {
+		var i GetSchemasRow
+		if err := rows.Scan(&i.SchemaName, &i.OwnerName); err != nil {
 			return nil, err
 		}
-		items = append(items, schema_name)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -883,7 +2495,8 @@ SELECT
     pg_seq.seqmin AS min_value,
     pg_seq.seqcache AS cache_size,
     pg_seq.seqcycle AS is_cycle,
-    FORMAT_TYPE(pg_seq.seqtypid, null) AS data_type
+    FORMAT_TYPE(pg_seq.seqtypid, null) AS data_type,
+    pg_catalog.pg_get_userbyid(seq_c.relowner)::TEXT AS owner_name
 FROM pg_catalog.pg_sequence AS pg_seq
 INNER JOIN pg_catalog.pg_class AS seq_c ON pg_seq.seqrelid = seq_c.oid
 INNER JOIN pg_catalog.pg_namespace AS seq_ns ON seq_c.relnamespace = seq_ns.oid
@@ -932,6 +2545,7 @@ type GetSequencesRow struct {
 	CacheSize          int64
 	IsCycle            bool
 	DataType           string
+	OwnerName          string
 }
 
 func (q *Queries) GetSequences(ctx context.Context) ([]GetSequencesRow, error) {
@@ -956,6 +2570,95 @@ func (q *Queries) GetSequences(ctx context.Context) ([]GetSequencesRow, error) {
 			&i.CacheSize,
 			&i.IsCycle,
 			&i.DataType,
+			&i.OwnerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStatisticsObjects = `-- name: GetStatisticsObjects :many
+SELECT
+    stat.stxname::TEXT AS statistics_name,
+    stat_namespace.nspname::TEXT AS statistics_schema_name,
+    table_c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name,
+    stat.stxkind::TEXT [] AS kinds,
+    (
+        SELECT
+            ARRAY_AGG(
+                att.attname
+                ORDER BY stxkey_ord.ord
+            )
+        FROM UNNEST(stat.stxkeys) WITH ORDINALITY AS stxkey_ord (attnum, ord)
+        INNER JOIN
+            pg_catalog.pg_attribute AS att
+            ON att.attrelid = table_c.oid AND stxkey_ord.attnum = att.attnum
+    )::TEXT [] AS column_names,
+    -- The statistics target is NULL by default as of Postgres 17 and -1 by default in earlier versions
+    COALESCE(stat.stxstattarget, -1)::INT AS statistics_target,
+    pg_catalog.pg_get_statisticsobjdef(stat.oid)::TEXT AS def
+FROM pg_catalog.pg_statistic_ext AS stat
+INNER JOIN
+    pg_catalog.pg_namespace AS stat_namespace
+    ON stat.stxnamespace = stat_namespace.oid
+INNER JOIN pg_catalog.pg_class AS table_c ON stat.stxrelid = table_c.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON table_c.relnamespace = table_namespace.oid
+WHERE
+    table_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND table_namespace.nspname !~ '^pg_toast'
+    AND table_namespace.nspname !~ '^pg_temp'
+    AND table_c.relkind IN ('r', 'p')
+    -- Exclude statistics objects of tables belonging to extensions
+    AND NOT EXISTS (
+        SELECT depend.objid
+        FROM pg_catalog.pg_depend AS depend
+        WHERE
+            depend.classid = 'pg_class'::REGCLASS
+            AND depend.objid = table_c.oid
+            AND depend.deptype = 'e'
+    )
+`
+
+type GetStatisticsObjectsRow struct {
+	StatisticsName       string
+	StatisticsSchemaName string
+	TableName            string
+	TableSchemaName      string
+	Kinds                []string
+	ColumnNames          []string
+	StatisticsTarget     int32
+	Def                  string
+}
+
+func (q *Queries) GetStatisticsObjects(ctx context.Context) ([]GetStatisticsObjectsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getStatisticsObjects)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStatisticsObjectsRow
+	for rows.Next() {
+		var i GetStatisticsObjectsRow
+		if err := rows.Scan(
+			&i.StatisticsName,
+			&i.StatisticsSchemaName,
+			&i.TableName,
+			&i.TableSchemaName,
+			pq.Array(&i.Kinds),
+			pq.Array(&i.ColumnNames),
+			&i.StatisticsTarget,
+			&i.Def,
 		); err != nil {
 			return nil, err
 		}
@@ -970,6 +2673,97 @@ func (q *Queries) GetSequences(ctx context.Context) ([]GetSequencesRow, error) {
 	return items, nil
 }
 
+const getTableRowEstimates = `-- name: GetTableRowEstimates :many
+SELECT
+    c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name,
+    c.reltuples::BIGINT AS estimated_rows
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+WHERE
+    table_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND table_namespace.nspname !~ '^pg_toast'
+    AND table_namespace.nspname !~ '^pg_temp'
+    AND (c.relkind = 'r' OR c.relkind = 'p')
+`
+
+type GetTableRowEstimatesRow struct {
+	TableName       string
+	TableSchemaName string
+	EstimatedRows   int64
+}
+
+func (q *Queries) GetTableRowEstimates(ctx context.Context) ([]GetTableRowEstimatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTableRowEstimates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTableRowEstimatesRow
+	for rows.Next() {
+		var i GetTableRowEstimatesRow
+		if err := rows.Scan(&i.TableName, &i.TableSchemaName, &i.EstimatedRows); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTableStatistics = `-- name: GetTableStatistics :one
+SELECT
+    c.relpages::BIGINT AS pages,
+    c.reltuples::BIGINT AS estimated_rows,
+    COALESCE((
+        SELECT SUM(s.avg_width)
+        FROM pg_catalog.pg_stats AS s
+        WHERE
+            s.schemaname = table_namespace.nspname
+            AND s.tablename = c.relname
+    ), 0)::BIGINT AS row_width,
+    (
+        COALESCE(stat.last_analyze, stat.last_autoanalyze) IS NOT NULL
+    )::BOOLEAN AS has_been_analyzed,
+    COALESCE(stat.n_mod_since_analyze, 0)::BIGINT AS rows_modified_since_analyze
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_stat_all_tables AS stat
+    ON c.oid = stat.relid
+WHERE c.oid = pg_catalog.to_regclass($1::TEXT)
+`
+
+type GetTableStatisticsRow struct {
+	Pages                    int64
+	EstimatedRows            int64
+	RowWidth                 int64
+	HasBeenAnalyzed          bool
+	RowsModifiedSinceAnalyze int64
+}
+
+func (q *Queries) GetTableStatistics(ctx context.Context, tableName string) (GetTableStatisticsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTableStatistics, tableName)
+	var i GetTableStatisticsRow
+	err := row.Scan(
+		&i.Pages,
+		&i.EstimatedRows,
+		&i.RowWidth,
+		&i.HasBeenAnalyzed,
+		&i.RowsModifiedSinceAnalyze,
+	)
+	return i, err
+}
+
 const getTables = `-- name: GetTables :many
 SELECT
     c.oid,
@@ -988,20 +2782,64 @@ SELECT
     (CASE
         WHEN c.relispartition THEN pg_catalog.pg_get_expr(c.relpartbound, c.oid)
         ELSE ''
-    END)::TEXT AS partition_for_values
+    END)::TEXT AS partition_for_values,
+    COALESCE(c.reloptions, '{}')::TEXT [] AS storage_parameters,
+    COALESCE(toast_c.reloptions, '{}')::TEXT [] AS toast_storage_parameters,
+    -- The parents of tables using (non-partition) inheritance, in the order they are inherited from
+    COALESCE(inheritance.parent_names, '{}')::TEXT [] AS inherits_from_names,
+    COALESCE(
+        inheritance.parent_schema_names, '{}'
+    )::TEXT [] AS inherits_from_schema_names,
+    -- The tablespace is empty if the table is in the database's default tablespace
+    COALESCE(tablespace.spcname, '')::TEXT AS tablespace_name,
+    pg_catalog.pg_get_userbyid(c.relowner)::TEXT AS owner_name,
+    COALESCE(description.description, '')::TEXT AS comment
 FROM pg_catalog.pg_class AS c
 INNER JOIN
     pg_catalog.pg_namespace AS table_namespace
     ON c.relnamespace = table_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_class AS toast_c
+    ON c.reltoastrelid = toast_c.oid
+LEFT JOIN
+    pg_catalog.pg_tablespace AS tablespace
+    ON c.reltablespace = tablespace.oid
 LEFT JOIN
     pg_catalog.pg_inherits AS table_inherits
-    ON c.oid = table_inherits.inhrelid
+    ON c.relispartition AND c.oid = table_inherits.inhrelid
 LEFT JOIN
     pg_catalog.pg_class AS parent_c
     ON table_inherits.inhparent = parent_c.oid
 LEFT JOIN
     pg_catalog.pg_namespace AS parent_namespace
     ON parent_c.relnamespace = parent_namespace.oid
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(
+            inheritance_parent_c.relname::TEXT
+            ORDER BY inheritance_inherits.inhseqno
+        ) AS parent_names,
+        ARRAY_AGG(
+            inheritance_parent_namespace.nspname::TEXT
+            ORDER BY inheritance_inherits.inhseqno
+        ) AS parent_schema_names
+    FROM pg_catalog.pg_inherits AS inheritance_inherits
+    INNER JOIN
+        pg_catalog.pg_class AS inheritance_parent_c
+        ON inheritance_inherits.inhparent = inheritance_parent_c.oid
+    INNER JOIN
+        pg_catalog.pg_namespace AS inheritance_parent_namespace
+        ON inheritance_parent_c.relnamespace = inheritance_parent_namespace.oid
+    WHERE
+        inheritance_inherits.inhrelid = c.oid
+        AND NOT c.relispartition
+) AS inheritance ON true
+LEFT JOIN
+    pg_catalog.pg_description AS description
+    ON
+        c.oid = description.objoid
+        AND description.classoid = 'pg_class'::REGCLASS
+        AND description.objsubid = 0
 WHERE
     table_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
     AND table_namespace.nspname !~ '^pg_toast'
@@ -1019,16 +2857,23 @@ WHERE
 `
 
 type GetTablesRow struct {
-	Oid                   interface{}
-	TableName             string
-	TableSchemaName       string
-	ReplicaIdentity       string
-	RlsEnabled            bool
-	RlsForced             bool
-	ParentTableName       string
-	ParentTableSchemaName string
-	PartitionKeyDef       string
-	PartitionForValues    string
+	Oid                     interface{}
+	TableName               string
+	TableSchemaName         string
+	ReplicaIdentity         string
+	RlsEnabled              bool
+	RlsForced               bool
+	ParentTableName         string
+	ParentTableSchemaName   string
+	PartitionKeyDef         string
+	PartitionForValues      string
+	StorageParameters       []string
+	ToastStorageParameters  []string
+	InheritsFromNames       []string
+	InheritsFromSchemaNames []string
+	TablespaceName          string
+	OwnerName               string
+	Comment                 string
 }
 
 func (q *Queries) GetTables(ctx context.Context) ([]GetTablesRow, error) {
@@ -1051,6 +2896,219 @@ func (q *Queries) GetTables(ctx context.Context) ([]GetTablesRow, error) {
 			&i.ParentTableSchemaName,
 			&i.PartitionKeyDef,
 			&i.PartitionForValues,
+			pq.Array(&i.StorageParameters),
+			pq.Array(&i.ToastStorageParameters),
+			pq.Array(&i.InheritsFromNames),
+			pq.Array(&i.InheritsFromSchemaNames),
+			&i.TablespaceName,
+			&i.OwnerName,
+			&i.Comment,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTablesByComment = `-- name: GetTablesByComment :many
+SELECT
+    c.relname::TEXT AS table_name,
+    table_namespace.nspname::TEXT AS table_schema_name
+FROM pg_catalog.pg_class AS c
+INNER JOIN
+    pg_catalog.pg_namespace AS table_namespace
+    ON c.relnamespace = table_namespace.oid
+WHERE
+    c.relkind = 'r'
+    AND pg_catalog.obj_description(c.oid, 'pg_class') = $1::TEXT
+`
+
+type GetTablesByCommentRow struct {
+	TableName       string
+	TableSchemaName string
+}
+
+func (q *Queries) GetTablesByComment(ctx context.Context, comment string) ([]GetTablesByCommentRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTablesByComment, comment)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTablesByCommentRow
+	for rows.Next() {
+		var i GetTablesByCommentRow
+		if err := rows.Scan(&i.TableName, &i.TableSchemaName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTextSearchConfigs = `-- name: GetTextSearchConfigs :many
+SELECT
+    cfg.cfgname::TEXT AS config_name,
+    cfg_namespace.nspname::TEXT AS config_schema_name,
+    prs.prsname::TEXT AS parser_name,
+    prs_namespace.nspname::TEXT AS parser_schema_name,
+    COALESCE(mappings.token_types, '{}')::TEXT [] AS mapping_token_types,
+    COALESCE(
+        mappings.dictionary_names, '{}'
+    )::TEXT [] AS mapping_dictionary_names,
+    COALESCE(
+        mappings.dictionary_schema_names, '{}'
+    )::TEXT [] AS mapping_dictionary_schema_names
+FROM pg_catalog.pg_ts_config AS cfg
+INNER JOIN
+    pg_catalog.pg_namespace AS cfg_namespace
+    ON cfg.cfgnamespace = cfg_namespace.oid
+INNER JOIN pg_catalog.pg_ts_parser AS prs ON cfg.cfgparser = prs.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS prs_namespace
+    ON prs.prsnamespace = prs_namespace.oid
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(
+            token_type.alias ORDER BY map.maptokentype, map.mapseqno
+        ) AS token_types,
+        ARRAY_AGG(
+            dict.dictname ORDER BY map.maptokentype, map.mapseqno
+        ) AS dictionary_names,
+        ARRAY_AGG(
+            dict_namespace.nspname ORDER BY map.maptokentype, map.mapseqno
+        ) AS dictionary_schema_names
+    FROM pg_catalog.pg_ts_config_map AS map
+    INNER JOIN
+        pg_catalog.ts_token_type(cfg.cfgparser) AS token_type
+        ON map.maptokentype = token_type.tokid
+    INNER JOIN pg_catalog.pg_ts_dict AS dict ON map.mapdict = dict.oid
+    INNER JOIN
+        pg_catalog.pg_namespace AS dict_namespace
+        ON dict.dictnamespace = dict_namespace.oid
+    WHERE map.mapcfg = cfg.oid
+) AS mappings ON true
+WHERE
+    cfg_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND cfg_namespace.nspname !~ '^pg_toast'
+    AND cfg_namespace.nspname !~ '^pg_temp'
+    -- Exclude configurations belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_ts_config'::REGCLASS
+            AND ext_depend.objid = cfg.oid
+            AND ext_depend.deptype = 'e'
+    )
+`
+
+type GetTextSearchConfigsRow struct {
+	ConfigName                   string
+	ConfigSchemaName             string
+	ParserName                   string
+	ParserSchemaName             string
+	MappingTokenTypes            []string
+	MappingDictionaryNames       []string
+	MappingDictionarySchemaNames []string
+}
+
+func (q *Queries) GetTextSearchConfigs(ctx context.Context) ([]GetTextSearchConfigsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTextSearchConfigs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTextSearchConfigsRow
+	for rows.Next() {
+		var i GetTextSearchConfigsRow
+		if err := rows.Scan(
+			&i.ConfigName,
+			&i.ConfigSchemaName,
+			&i.ParserName,
+			&i.ParserSchemaName,
+			pq.Array(&i.MappingTokenTypes),
+			pq.Array(&i.MappingDictionaryNames),
+			pq.Array(&i.MappingDictionarySchemaNames),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTextSearchDictionaries = `-- name: GetTextSearchDictionaries :many
+SELECT
+    dict.dictname::TEXT AS dictionary_name,
+    dict_namespace.nspname::TEXT AS dictionary_schema_name,
+    tmpl.tmplname::TEXT AS template_name,
+    tmpl_namespace.nspname::TEXT AS template_schema_name,
+    COALESCE(dict.dictinitoption, '')::TEXT AS options
+FROM pg_catalog.pg_ts_dict AS dict
+INNER JOIN
+    pg_catalog.pg_namespace AS dict_namespace
+    ON dict.dictnamespace = dict_namespace.oid
+INNER JOIN pg_catalog.pg_ts_template AS tmpl ON dict.dicttemplate = tmpl.oid
+INNER JOIN
+    pg_catalog.pg_namespace AS tmpl_namespace
+    ON tmpl.tmplnamespace = tmpl_namespace.oid
+WHERE
+    dict_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
+    AND dict_namespace.nspname !~ '^pg_toast'
+    AND dict_namespace.nspname !~ '^pg_temp'
+    -- Exclude dictionaries belonging to extensions
+    AND NOT EXISTS (
+        SELECT ext_depend.objid
+        FROM pg_catalog.pg_depend AS ext_depend
+        WHERE
+            ext_depend.classid = 'pg_ts_dict'::REGCLASS
+            AND ext_depend.objid = dict.oid
+            AND ext_depend.deptype = 'e'
+    )
+`
+
+type GetTextSearchDictionariesRow struct {
+	DictionaryName       string
+	DictionarySchemaName string
+	TemplateName         string
+	TemplateSchemaName   string
+	Options              string
+}
+
+func (q *Queries) GetTextSearchDictionaries(ctx context.Context) ([]GetTextSearchDictionariesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTextSearchDictionaries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTextSearchDictionariesRow
+	for rows.Next() {
+		var i GetTextSearchDictionariesRow
+		if err := rows.Scan(
+			&i.DictionaryName,
+			&i.DictionarySchemaName,
+			&i.TemplateName,
+			&i.TemplateSchemaName,
+			&i.Options,
 		); err != nil {
 			return nil, err
 		}
@@ -1157,7 +3215,8 @@ WHERE
     AND depend.classid = 'pg_rewrite'::REGCLASS
     AND depend.refclassid = 'pg_class'::REGCLASS
     AND depend.deptype = 'n'
-    AND depends_on_c.relkind IN ('r', 'v') -- 'r' for table, 'v' for view
+    -- 'r' for table, 'v' for view, 'm' for materialized view, 'f' for foreign table
+    AND depends_on_c.relkind IN ('r', 'v', 'm', 'f')
     AND depends_on_ns.nspname NOT IN ('pg_catalog', 'information_schema')
 `
 
@@ -1199,11 +3258,19 @@ const getViews = `-- name: GetViews :many
 SELECT
     c.relname::TEXT AS view_name,
     view_namespace.nspname::TEXT AS view_schema_name,
-    pg_catalog.pg_get_viewdef(c.oid, true) AS view_definition
+    pg_catalog.pg_get_viewdef(c.oid, true) AS view_definition,
+    pg_catalog.pg_get_userbyid(c.relowner)::TEXT AS owner_name,
+    COALESCE(description.description, '')::TEXT AS comment
 FROM pg_catalog.pg_class AS c
 INNER JOIN
     pg_catalog.pg_namespace AS view_namespace
     ON c.relnamespace = view_namespace.oid
+LEFT JOIN
+    pg_catalog.pg_description AS description
+    ON
+        c.oid = description.objoid
+        AND description.classoid = 'pg_class'::REGCLASS
+        AND description.objsubid = 0
 WHERE
     view_namespace.nspname NOT IN ('pg_catalog', 'information_schema')
     AND view_namespace.nspname !~ '^pg_toast'
@@ -1224,6 +3291,8 @@ type GetViewsRow struct {
 	ViewName       string
 	ViewSchemaName string
 	ViewDefinition string
+	OwnerName      string
+	Comment        string
 }
 
 func (q *Queries) GetViews(ctx context.Context) ([]GetViewsRow, error) {
@@ -1235,7 +3304,7 @@ func (q *Queries) GetViews(ctx context.Context) ([]GetViewsRow, error) {
 	var items []GetViewsRow
 	for rows.Next() {
 		var i GetViewsRow
-		if err := rows.Scan(&i.ViewName, &i.ViewSchemaName, &i.ViewDefinition); err != nil {
+		if err := rows.Scan(&i.ViewName, &i.ViewSchemaName, &i.ViewDefinition, &i.OwnerName, &i.Comment); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -0,0 +1,226 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	// atlasSimpleTypeRegex matches the types that Atlas can reference by name, e.g., "character varying(255)", which
+	// Atlas spells with underscores, i.e., character_varying(255). Other types, e.g., arrays, are written as sql("...")
+	atlasSimpleTypeRegex = regexp.MustCompile(`^[a-z][a-z0-9 ]*(?:\(\d+(?:,\s*\d+)?\))?$`)
+
+	atlasForeignKeyRegex       = regexp.MustCompile(`^FOREIGN KEY \((.+?)\) REFERENCES .+?\((.+?)\)`)
+	atlasForeignKeyActionRegex = regexp.MustCompile(`ON (UPDATE|DELETE) (CASCADE|RESTRICT|SET NULL|SET DEFAULT|NO ACTION)`)
+)
+
+// ExportAtlasHCL writes the schema in Atlas's HCL schema format, such that teams storing their schemas as Atlas HCL
+// can use the output as their desired state. Only schemas, enums, and tables are exported, including the tables'
+// columns, primary keys, indexes, foreign keys, check constraints, and comments. Other objects, e.g., views and
+// functions, are not supported by Atlas's open-source HCL format and are omitted.
+//
+// Types that Atlas cannot reference by name, e.g., arrays, as well as column defaults, are written as sql("...")
+// expressions. Objects are referenced by their unqualified names, so tables with the same name in different schemas
+// cannot be told apart in the output.
+func (s Schema) ExportAtlasHCL(w io.Writer) error {
+	sb := strings.Builder{}
+
+	for _, namedSchema := range s.NamedSchemas {
+		sb.WriteString(fmt.Sprintf("schema %s {\n}\n\n", atlasQuote(namedSchema.Name)))
+	}
+
+	enumRefsByType := make(map[string]string)
+	for _, enum := range s.Enums {
+		name := unescapeIdentifier(enum.EscapedName)
+		enumRefsByType[enum.GetFQEscapedName()] = "enum." + name
+		enumRefsByType[fmt.Sprintf("%s.%s", enum.SchemaName, name)] = "enum." + name
+		if enum.SchemaName == "public" {
+			enumRefsByType[name] = "enum." + name
+			enumRefsByType[enum.EscapedName] = "enum." + name
+		}
+
+		var values []string
+		for _, label := range enum.Labels {
+			values = append(values, atlasQuote(label))
+		}
+		sb.WriteString(fmt.Sprintf("enum %s {\n", atlasQuote(name)))
+		sb.WriteString(fmt.Sprintf("  schema = schema.%s\n", enum.SchemaName))
+		sb.WriteString(fmt.Sprintf("  values = [%s]\n", strings.Join(values, ", ")))
+		sb.WriteString("}\n\n")
+	}
+
+	for _, table := range s.Tables {
+		writeAtlasTable(&sb, table, s.Indexes, s.ForeignKeyConstraints, enumRefsByType)
+	}
+
+	if _, err := io.WriteString(w, strings.TrimSuffix(sb.String(), "\n")); err != nil {
+		return fmt.Errorf("writing Atlas HCL: %w", err)
+	}
+	return nil
+}
+
+func writeAtlasTable(sb *strings.Builder, table Table, indexes []Index, fks []ForeignKeyConstraint, enumRefsByType map[string]string) {
+	sb.WriteString(fmt.Sprintf("table %s {\n", atlasQuote(unescapeIdentifier(table.EscapedName))))
+	sb.WriteString(fmt.Sprintf("  schema = schema.%s\n", table.SchemaName))
+	if table.Comment != nil {
+		sb.WriteString(fmt.Sprintf("  comment = %s\n", atlasQuote(*table.Comment)))
+	}
+
+	for _, column := range table.Columns {
+		sb.WriteString(fmt.Sprintf("  column %s {\n", atlasQuote(column.Name)))
+		sb.WriteString(fmt.Sprintf("    null = %t\n", column.IsNullable))
+		sb.WriteString(fmt.Sprintf("    type = %s\n", buildAtlasType(column.Type, enumRefsByType)))
+		if column.Default != "" {
+			sb.WriteString(fmt.Sprintf("    default = sql(%s)\n", atlasQuote(column.Default)))
+		}
+		if column.IsCollated() {
+			sb.WriteString(fmt.Sprintf("    collate = %s\n", atlasQuote(unescapeIdentifier(column.Collation.EscapedName))))
+		}
+		if column.Comment != nil {
+			sb.WriteString(fmt.Sprintf("    comment = %s\n", atlasQuote(*column.Comment)))
+		}
+		if column.Identity != nil {
+			generated := "BY_DEFAULT"
+			if column.Identity.Type == ColumnIdentityTypeAlways {
+				generated = "ALWAYS"
+			}
+			sb.WriteString("    identity {\n")
+			sb.WriteString(fmt.Sprintf("      generated = %s\n", generated))
+			sb.WriteString(fmt.Sprintf("      start = %d\n", column.Identity.StartValue))
+			sb.WriteString(fmt.Sprintf("      increment = %d\n", column.Identity.Increment))
+			sb.WriteString("    }\n")
+		}
+		if column.IsGenerated {
+			sb.WriteString("    as {\n")
+			sb.WriteString(fmt.Sprintf("      expr = %s\n", atlasQuote(column.GenerationExpression)))
+			sb.WriteString("      type = STORED\n")
+			sb.WriteString("    }\n")
+		}
+		sb.WriteString("  }\n")
+	}
+
+	var tableIndexes []Index
+	for _, index := range indexes {
+		if index.OwningTable.GetName() == table.GetName() {
+			tableIndexes = append(tableIndexes, index)
+		}
+	}
+	for _, index := range tableIndexes {
+		if index.IsPk() {
+			sb.WriteString("  primary_key {\n")
+			sb.WriteString(fmt.Sprintf("    columns = [%s]\n", buildAtlasColumnRefs(index.Columns)))
+			sb.WriteString("  }\n")
+		}
+	}
+
+	for _, fk := range fks {
+		if fk.OwningTable.GetName() != table.GetName() {
+			continue
+		}
+		match := atlasForeignKeyRegex.FindStringSubmatch(fk.ConstraintDef)
+		if match == nil {
+			continue
+		}
+		actions := map[string]string{"UPDATE": "NO_ACTION", "DELETE": "NO_ACTION"}
+		for _, actionMatch := range atlasForeignKeyActionRegex.FindAllStringSubmatch(fk.ConstraintDef, -1) {
+			actions[actionMatch[1]] = strings.ReplaceAll(actionMatch[2], " ", "_")
+		}
+		refTable := unescapeIdentifier(fk.ForeignTable.EscapedName)
+		var refColumns []string
+		for _, column := range splitAtlasColumnList(match[2]) {
+			refColumns = append(refColumns, fmt.Sprintf("table.%s.column.%s", refTable, column))
+		}
+		sb.WriteString(fmt.Sprintf("  foreign_key %s {\n", atlasQuote(unescapeIdentifier(fk.EscapedName))))
+		sb.WriteString(fmt.Sprintf("    columns = [%s]\n", buildAtlasColumnRefs(splitAtlasColumnList(match[1]))))
+		sb.WriteString(fmt.Sprintf("    ref_columns = [%s]\n", strings.Join(refColumns, ", ")))
+		sb.WriteString(fmt.Sprintf("    on_update = %s\n", actions["UPDATE"]))
+		sb.WriteString(fmt.Sprintf("    on_delete = %s\n", actions["DELETE"]))
+		sb.WriteString("  }\n")
+	}
+
+	for _, index := range tableIndexes {
+		if index.IsPk() {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  index %s {\n", atlasQuote(index.Name)))
+		if index.IsUnique {
+			sb.WriteString("    unique = true\n")
+		}
+		if len(index.Expressions) == 0 {
+			sb.WriteString(fmt.Sprintf("    columns = [%s]\n", buildAtlasColumnRefs(index.Columns)))
+		} else {
+			for _, column := range index.Columns {
+				sb.WriteString(fmt.Sprintf("    on {\n      column = column.%s\n    }\n", column))
+			}
+			for _, expr := range index.Expressions {
+				sb.WriteString(fmt.Sprintf("    on {\n      expr = %s\n    }\n", atlasQuote(expr)))
+			}
+		}
+		if len(index.IncludedColumns) > 0 {
+			sb.WriteString(fmt.Sprintf("    include = [%s]\n", buildAtlasColumnRefs(index.IncludedColumns)))
+		}
+		if index.Method != "" && index.Method != "btree" {
+			sb.WriteString(fmt.Sprintf("    type = %s\n", strings.ToUpper(index.Method)))
+		}
+		if index.Predicate != "" {
+			sb.WriteString(fmt.Sprintf("    where = %s\n", atlasQuote(index.Predicate)))
+		}
+		if index.Comment != nil {
+			sb.WriteString(fmt.Sprintf("    comment = %s\n", atlasQuote(*index.Comment)))
+		}
+		sb.WriteString("  }\n")
+	}
+
+	for _, check := range table.CheckConstraints {
+		sb.WriteString(fmt.Sprintf("  check %s {\n", atlasQuote(check.Name)))
+		sb.WriteString(fmt.Sprintf("    expr = %s\n", atlasQuote(check.Expression)))
+		sb.WriteString("  }\n")
+	}
+
+	sb.WriteString("}\n\n")
+}
+
+// buildAtlasType returns the Atlas HCL expression of the column type
+func buildAtlasType(columnType string, enumRefsByType map[string]string) string {
+	if ref, ok := enumRefsByType[columnType]; ok {
+		return ref
+	}
+	if atlasSimpleTypeRegex.MatchString(columnType) {
+		return strings.ReplaceAll(strings.ReplaceAll(columnType, ", ", ","), " ", "_")
+	}
+	return fmt.Sprintf("sql(%s)", atlasQuote(columnType))
+}
+
+func buildAtlasColumnRefs(columns []string) string {
+	var refs []string
+	for _, column := range columns {
+		refs = append(refs, "column."+column)
+	}
+	return strings.Join(refs, ", ")
+}
+
+// splitAtlasColumnList splits a column list of a constraint definition, e.g., `id, "User"`, into the unescaped column
+// names
+func splitAtlasColumnList(columnList string) []string {
+	var columns []string
+	for _, column := range strings.Split(columnList, ",") {
+		columns = append(columns, unescapeIdentifier(strings.TrimSpace(column)))
+	}
+	return columns
+}
+
+// atlasQuote quotes the string as an HCL string literal. Template sequences, i.e., ${ and %{, are escaped, such that
+// the string is taken literally.
+func atlasQuote(s string) string {
+	return `"` + strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"${", "$${",
+		"%{", "%%{",
+	).Replace(s) + `"`
+}
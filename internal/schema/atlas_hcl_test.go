@@ -0,0 +1,173 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAtlasHCL(t *testing.T) {
+	comment := "The foobar's ${table}"
+	s := Schema{
+		NamedSchemas: []NamedSchema{{Name: "public"}},
+		Enums: []Enum{{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: `"color"`},
+			Labels:              []string{"red", "green"},
+		}},
+		Tables: []Table{
+			{
+				SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`},
+				Columns: []Column{
+					{Name: "id", Type: "integer", Identity: &ColumnIdentity{Type: ColumnIdentityTypeAlways, StartValue: 1, Increment: 1}},
+					{Name: "val", Type: "character varying(255)", Default: "'some value'::character varying", IsNullable: true,
+						Collation: SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: `"C"`}, Comment: &comment},
+					{Name: "color", Type: "color", Default: "'red'::color"},
+					{Name: "tags", Type: "text[]", IsNullable: true},
+					{Name: "doubled", Type: "integer", IsNullable: true, IsGenerated: true, GenerationExpression: "(id * 2)"},
+				},
+				CheckConstraints: []CheckConstraint{{Name: "val_check", Expression: "(length((val)::text) > 0)", IsValid: true}},
+				Comment:          &comment,
+			},
+			{
+				SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: `"bar"`},
+				Columns: []Column{
+					{Name: "id", Type: "bigint"},
+					{Name: "foobar_id", Type: "integer", IsNullable: true},
+					{Name: "price", Type: "numeric(10, 2)", IsNullable: true},
+				},
+			},
+		},
+		Indexes: []Index{
+			{
+				Name:        "foobar_pkey",
+				OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`},
+				Columns:     []string{"id"},
+				IsUnique:    true,
+				Method:      "btree",
+				Constraint:  &IndexConstraint{Type: PkIndexConstraintType, EscapedConstraintName: `"foobar_pkey"`},
+			},
+			{
+				Name:        "foobar_lower_val_idx",
+				OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`},
+				Columns:     []string{"id"},
+				Expressions: []string{"lower((val)::text)"},
+				IsUnique:    true,
+				Method:      "btree",
+				Predicate:   "(val IS NOT NULL)",
+			},
+			{
+				Name:            "foobar_tags_idx",
+				OwningTable:     SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`},
+				Columns:         []string{"tags"},
+				IncludedColumns: []string{"val"},
+				Method:          "gin",
+			},
+		},
+		ForeignKeyConstraints: []ForeignKeyConstraint{{
+			EscapedName:   `"bar_foobar_id_fkey"`,
+			OwningTable:   SchemaQualifiedName{SchemaName: "public", EscapedName: `"bar"`},
+			ForeignTable:  SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`},
+			ConstraintDef: "FOREIGN KEY (foobar_id) REFERENCES foobar(id) ON DELETE CASCADE",
+			IsValid:       true,
+		}},
+	}
+
+	sb := strings.Builder{}
+	require.NoError(t, s.ExportAtlasHCL(&sb))
+	assert.Equal(t, `schema "public" {
+}
+
+enum "color" {
+  schema = schema.public
+  values = ["red", "green"]
+}
+
+table "foobar" {
+  schema = schema.public
+  comment = "The foobar's $${table}"
+  column "id" {
+    null = false
+    type = integer
+    identity {
+      generated = ALWAYS
+      start = 1
+      increment = 1
+    }
+  }
+  column "val" {
+    null = true
+    type = character_varying(255)
+    default = sql("'some value'::character varying")
+    collate = "C"
+    comment = "The foobar's $${table}"
+  }
+  column "color" {
+    null = false
+    type = enum.color
+    default = sql("'red'::color")
+  }
+  column "tags" {
+    null = true
+    type = sql("text[]")
+  }
+  column "doubled" {
+    null = true
+    type = integer
+    as {
+      expr = "(id * 2)"
+      type = STORED
+    }
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "foobar_lower_val_idx" {
+    unique = true
+    on {
+      column = column.id
+    }
+    on {
+      expr = "lower((val)::text)"
+    }
+    where = "(val IS NOT NULL)"
+  }
+  index "foobar_tags_idx" {
+    columns = [column.tags]
+    include = [column.val]
+    type = GIN
+  }
+  check "val_check" {
+    expr = "(length((val)::text) > 0)"
+  }
+}
+
+table "bar" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = bigint
+  }
+  column "foobar_id" {
+    null = true
+    type = integer
+  }
+  column "price" {
+    null = true
+    type = numeric(10,2)
+  }
+  foreign_key "bar_foobar_id_fkey" {
+    columns = [column.foobar_id]
+    ref_columns = [table.foobar.column.id]
+    on_update = NO_ACTION
+    on_delete = CASCADE
+  }
+}
+`, sb.String())
+}
+
+func TestAtlasQuote(t *testing.T) {
+	assert.Equal(t, `"plain"`, atlasQuote("plain"))
+	assert.Equal(t, `"a \"quoted\" \\ value\nwith $${template} and %%{directive}"`, atlasQuote("a \"quoted\" \\ value\nwith ${template} and %{directive}"))
+}
@@ -0,0 +1,224 @@
+package schema
+
+// DeepCopy returns a copy of the schema that shares no memory with the original, i.e., modifying any slice, map, or
+// pointer field of the copy will not modify the original. Nil slices and maps remain nil, such that the copy is equal
+// to the original.
+func (s Schema) DeepCopy() Schema {
+	s.NamedSchemas = copySlice(s.NamedSchemas, nil)
+	s.Extensions = copySlice(s.Extensions, nil)
+	s.Collations = copySlice(s.Collations, nil)
+	s.ForeignDataWrappers = copySlice(s.ForeignDataWrappers, ForeignDataWrapper.DeepCopy)
+	s.ForeignServers = copySlice(s.ForeignServers, ForeignServer.DeepCopy)
+	s.Enums = copySlice(s.Enums, Enum.DeepCopy)
+	s.Domains = copySlice(s.Domains, Domain.DeepCopy)
+	s.CompositeTypes = copySlice(s.CompositeTypes, CompositeType.DeepCopy)
+	s.TextSearchDictionaries = copySlice(s.TextSearchDictionaries, nil)
+	s.TextSearchConfigs = copySlice(s.TextSearchConfigs, TextSearchConfig.DeepCopy)
+	s.Tables = copySlice(s.Tables, Table.DeepCopy)
+	s.ForeignTables = copySlice(s.ForeignTables, ForeignTable.DeepCopy)
+	s.Views = copySlice(s.Views, View.DeepCopy)
+	s.MaterializedViews = copySlice(s.MaterializedViews, MaterializedView.DeepCopy)
+	s.Indexes = copySlice(s.Indexes, Index.DeepCopy)
+	s.StatisticsObjects = copySlice(s.StatisticsObjects, StatisticsObject.DeepCopy)
+	s.ForeignKeyConstraints = copySlice(s.ForeignKeyConstraints, nil)
+	s.Sequences = copySlice(s.Sequences, Sequence.DeepCopy)
+	s.Functions = copySlice(s.Functions, Function.DeepCopy)
+	s.Procedures = copySlice(s.Procedures, Procedure.DeepCopy)
+	s.Aggregates = copySlice(s.Aggregates, Aggregate.DeepCopy)
+	s.Triggers = copySlice(s.Triggers, nil)
+	s.EventTriggers = copySlice(s.EventTriggers, EventTrigger.DeepCopy)
+	s.Operators = copySlice(s.Operators, nil)
+	s.OperatorClasses = copySlice(s.OperatorClasses, OperatorClass.DeepCopy)
+	s.Publications = copySlice(s.Publications, Publication.DeepCopy)
+	s.Privileges = copySlice(s.Privileges, nil)
+	s.DefaultPrivileges = copySlice(s.DefaultPrivileges, nil)
+	s.ObjectOwners = copySlice(s.ObjectOwners, nil)
+	return s
+}
+
+func (f ForeignDataWrapper) DeepCopy() ForeignDataWrapper {
+	f.Options = copyMap(f.Options)
+	return f
+}
+
+func (f ForeignServer) DeepCopy() ForeignServer {
+	f.Options = copyMap(f.Options)
+	return f
+}
+
+func (e Enum) DeepCopy() Enum {
+	e.Labels = copySlice(e.Labels, nil)
+	return e
+}
+
+func (d Domain) DeepCopy() Domain {
+	d.Constraints = copySlice(d.Constraints, nil)
+	return d
+}
+
+func (c CompositeType) DeepCopy() CompositeType {
+	c.Attributes = copySlice(c.Attributes, nil)
+	return c
+}
+
+func (c TextSearchConfig) DeepCopy() TextSearchConfig {
+	c.Mappings = copySlice(c.Mappings, TextSearchConfigMapping.DeepCopy)
+	return c
+}
+
+func (m TextSearchConfigMapping) DeepCopy() TextSearchConfigMapping {
+	m.Dictionaries = copySlice(m.Dictionaries, nil)
+	return m
+}
+
+func (t Table) DeepCopy() Table {
+	t.Columns = copySlice(t.Columns, Column.DeepCopy)
+	t.CheckConstraints = copySlice(t.CheckConstraints, CheckConstraint.DeepCopy)
+	t.Policies = copySlice(t.Policies, Policy.DeepCopy)
+	t.StorageParameters = copyMap(t.StorageParameters)
+	t.ParentTable = copyPtr(t.ParentTable)
+	t.InheritsFrom = copySlice(t.InheritsFrom, nil)
+	t.Comment = copyPtr(t.Comment)
+	return t
+}
+
+func (f ForeignTable) DeepCopy() ForeignTable {
+	f.Columns = copySlice(f.Columns, Column.DeepCopy)
+	f.Options = copyMap(f.Options)
+	return f
+}
+
+func (c Column) DeepCopy() Column {
+	c.Identity = copyPtr(c.Identity)
+	c.Comment = copyPtr(c.Comment)
+	return c
+}
+
+func (c CheckConstraint) DeepCopy() CheckConstraint {
+	c.KeyColumns = copySlice(c.KeyColumns, nil)
+	c.DependsOnFunctions = copySlice(c.DependsOnFunctions, nil)
+	return c
+}
+
+func (p Policy) DeepCopy() Policy {
+	p.AppliesTo = copySlice(p.AppliesTo, nil)
+	p.Columns = copySlice(p.Columns, nil)
+	return p
+}
+
+func (v View) DeepCopy() View {
+	v.DependsOnTables = copySlice(v.DependsOnTables, nil)
+	v.DependsOnViews = copySlice(v.DependsOnViews, nil)
+	v.DependsOnForeignTables = copySlice(v.DependsOnForeignTables, nil)
+	v.Comment = copyPtr(v.Comment)
+	return v
+}
+
+func (m MaterializedView) DeepCopy() MaterializedView {
+	m.StorageParameters = copyMap(m.StorageParameters)
+	m.Indexes = copySlice(m.Indexes, Index.DeepCopy)
+	m.DependsOnTables = copySlice(m.DependsOnTables, nil)
+	m.DependsOnViews = copySlice(m.DependsOnViews, nil)
+	m.DependsOnMaterializedViews = copySlice(m.DependsOnMaterializedViews, nil)
+	return m
+}
+
+func (i Index) DeepCopy() Index {
+	i.Columns = copySlice(i.Columns, nil)
+	i.IncludedColumns = copySlice(i.IncludedColumns, nil)
+	i.Expressions = copySlice(i.Expressions, nil)
+	i.Constraint = copyPtr(i.Constraint)
+	i.ParentIdx = copyPtr(i.ParentIdx)
+	i.PagesPerRange = copyPtr(i.PagesPerRange)
+	i.Autosummarize = copyPtr(i.Autosummarize)
+	i.GINParameters = copyMap(i.GINParameters)
+	i.DependsOnOperatorClasses = copySlice(i.DependsOnOperatorClasses, nil)
+	i.Comment = copyPtr(i.Comment)
+	return i
+}
+
+func (s StatisticsObject) DeepCopy() StatisticsObject {
+	s.Kinds = copySlice(s.Kinds, nil)
+	s.Columns = copySlice(s.Columns, nil)
+	return s
+}
+
+func (s Sequence) DeepCopy() Sequence {
+	s.Owner = copyPtr(s.Owner)
+	return s
+}
+
+func (f Function) DeepCopy() Function {
+	f.DependsOnFunctions = copySlice(f.DependsOnFunctions, nil)
+	f.DependsOnTables = copySlice(f.DependsOnTables, nil)
+	f.ReferencedColumns = copySlice(f.ReferencedColumns, nil)
+	f.Comment = copyPtr(f.Comment)
+	return f
+}
+
+func (p Procedure) DeepCopy() Procedure {
+	p.DependsOnFunctions = copySlice(p.DependsOnFunctions, nil)
+	return p
+}
+
+func (e EventTrigger) DeepCopy() EventTrigger {
+	e.Tags = copySlice(e.Tags, nil)
+	return e
+}
+
+func (a Aggregate) DeepCopy() Aggregate {
+	a.FinalFunction = copyPtr(a.FinalFunction)
+	a.CombineFunction = copyPtr(a.CombineFunction)
+	a.SerialFunction = copyPtr(a.SerialFunction)
+	a.DeserialFunction = copyPtr(a.DeserialFunction)
+	a.InitialCondition = copyPtr(a.InitialCondition)
+	a.DependsOnFunctions = copySlice(a.DependsOnFunctions, nil)
+	return a
+}
+
+func (o OperatorClass) DeepCopy() OperatorClass {
+	o.DependsOnOperators = copySlice(o.DependsOnOperators, nil)
+	o.DependsOnFunctions = copySlice(o.DependsOnFunctions, nil)
+	return o
+}
+
+func (p Publication) DeepCopy() Publication {
+	p.Tables = copySlice(p.Tables, nil)
+	p.Publish = copySlice(p.Publish, nil)
+	return p
+}
+
+// copySlice copies the slice, deep copying each value with copyFn. If copyFn is nil, the values are shallow copied,
+// which is only safe if the values do not contain any slices or pointers.
+func copySlice[S any](vals []S, copyFn func(S) S) []S {
+	if vals == nil {
+		return nil
+	}
+	copied := make([]S, len(vals))
+	for i, v := range vals {
+		if copyFn != nil {
+			v = copyFn(v)
+		}
+		copied[i] = v
+	}
+	return copied
+}
+
+func copyPtr[T any](val *T) *T {
+	if val == nil {
+		return nil
+	}
+	copied := *val
+	return &copied
+}
+
+func copyMap[K comparable, V any](vals map[K]V) map[K]V {
+	if vals == nil {
+		return nil
+	}
+	copied := make(map[K]V, len(vals))
+	for k, v := range vals {
+		copied[k] = v
+	}
+	return copied
+}
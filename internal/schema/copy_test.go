@@ -0,0 +1,229 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_DeepCopy(t *testing.T) {
+	name := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}
+	initialCondition := "0"
+	comment := "comment"
+	pagesPerRange := 16
+	autosummarize := true
+	// Every slice and pointer is populated, such that the test fails if a new slice or pointer field is not deep
+	// copied.
+	s := Schema{
+		NamedSchemas: []NamedSchema{{Name: "public"}},
+		Extensions:   []Extension{{SchemaQualifiedName: name, Version: "1.0"}},
+		Collations:   []Collation{{SchemaQualifiedName: name, Provider: "icu", Locale: "und-u-ks-level2"}},
+		ForeignDataWrappers: []ForeignDataWrapper{{
+			Name:    "fdw",
+			Handler: name,
+			Options: map[string]string{"debug": "true"},
+		}},
+		ForeignServers: []ForeignServer{{
+			Name:               "server",
+			ForeignDataWrapper: "fdw",
+			Options:            map[string]string{"host": "localhost"},
+		}},
+		Enums: []Enum{{SchemaQualifiedName: name, Labels: []string{"a", "b"}}},
+		Domains: []Domain{{
+			SchemaQualifiedName: name,
+			BaseType:            "integer",
+			Constraints:         []DomainConstraint{{Name: "positive", Check: "CHECK ((VALUE > 0))"}},
+		}},
+		CompositeTypes: []CompositeType{{
+			SchemaQualifiedName: name,
+			Attributes:          []CompositeTypeAttribute{{Name: "street", Type: "text"}},
+		}},
+		TextSearchDictionaries: []TextSearchDictionary{{
+			SchemaQualifiedName: name,
+			Template:            SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"simple\""},
+		}},
+		TextSearchConfigs: []TextSearchConfig{{
+			SchemaQualifiedName: name,
+			Parser:              SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"default\""},
+			Mappings:            []TextSearchConfigMapping{{TokenType: "word", Dictionaries: []SchemaQualifiedName{name}}},
+		}},
+		Tables: []Table{{
+			SchemaQualifiedName: name,
+			Columns: []Column{{
+				Name:     "id",
+				Type:     "integer",
+				Identity: &ColumnIdentity{Type: ColumnIdentityTypeAlways, StartValue: 1, Increment: 1},
+				Comment:  &comment,
+			}},
+			CheckConstraints: []CheckConstraint{{
+				Name:               "check",
+				KeyColumns:         []string{"id"},
+				Expression:         "(id > 0)",
+				DependsOnFunctions: []SchemaQualifiedName{name},
+			}},
+			Policies: []Policy{{
+				EscapedName: "\"policy\"",
+				AppliesTo:   []string{"PUBLIC"},
+				Columns:     []string{"id"},
+			}},
+			StorageParameters: map[string]string{"fillfactor": "70"},
+			ParentTable:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"parent\""},
+			InheritsFrom:      []SchemaQualifiedName{{SchemaName: "public", EscapedName: "\"base\""}},
+			Comment:           &comment,
+		}},
+		ForeignTables: []ForeignTable{{
+			SchemaQualifiedName: name,
+			Columns: []Column{{
+				Name:     "id",
+				Type:     "integer",
+				Identity: &ColumnIdentity{Type: ColumnIdentityTypeAlways, StartValue: 1, Increment: 1},
+				Comment:  &comment,
+			}},
+			Server:  "server",
+			Options: map[string]string{"table_name": "bar"},
+		}},
+		Views: []View{{
+			SchemaQualifiedName:    name,
+			DependsOnTables:        []SchemaQualifiedName{name},
+			DependsOnViews:         []SchemaQualifiedName{name},
+			DependsOnForeignTables: []SchemaQualifiedName{name},
+			Comment:                &comment,
+		}},
+		MaterializedViews: []MaterializedView{{
+			SchemaQualifiedName: name,
+			StorageParameters:   map[string]string{"fillfactor": "70"},
+			Indexes: []Index{{
+				Name:            "mv_idx",
+				OwningTable:     name,
+				Columns:         []string{"id"},
+				IncludedColumns: []string{"val"},
+				Expressions:     []string{"lower(val)"},
+				Constraint:      &IndexConstraint{Type: PkIndexConstraintType},
+				ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"parent_idx\""},
+				DependsOnOperatorClasses: []OperatorClassReference{
+					{SchemaQualifiedName: name, IndexMethod: "btree"},
+				},
+				PagesPerRange: &pagesPerRange,
+				Autosummarize: &autosummarize,
+				GINParameters: map[string]string{"fastupdate": "off"},
+				Comment:       &comment,
+			}},
+			DependsOnTables:            []SchemaQualifiedName{name},
+			DependsOnViews:             []SchemaQualifiedName{name},
+			DependsOnMaterializedViews: []SchemaQualifiedName{name},
+		}},
+		Indexes: []Index{{
+			Name:            "idx",
+			OwningTable:     name,
+			Columns:         []string{"id"},
+			IncludedColumns: []string{"val"},
+			Expressions:     []string{"lower(val)"},
+			Constraint:      &IndexConstraint{Type: PkIndexConstraintType},
+			ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"parent_idx\""},
+			DependsOnOperatorClasses: []OperatorClassReference{
+				{SchemaQualifiedName: name, IndexMethod: "btree"},
+			},
+			PagesPerRange: &pagesPerRange,
+			Autosummarize: &autosummarize,
+			GINParameters: map[string]string{"fastupdate": "off"},
+			Comment:       &comment,
+		}},
+		StatisticsObjects: []StatisticsObject{{
+			SchemaQualifiedName: name,
+			Table:               name,
+			Kinds:               []string{"ndistinct"},
+			Columns:             []string{"id", "val"},
+		}},
+		ForeignKeyConstraints: []ForeignKeyConstraint{{EscapedName: "\"fk\"", OwningTable: name, ForeignTable: name}},
+		Sequences: []Sequence{{
+			SchemaQualifiedName: name,
+			Owner:               &SequenceOwner{TableName: name, ColumnName: "id"},
+		}},
+		Functions: []Function{{
+			SchemaQualifiedName: name,
+			DependsOnFunctions:  []SchemaQualifiedName{name},
+			DependsOnTables:     []SchemaQualifiedName{name},
+			ReferencedColumns:   []TableColumnRef{{TableName: "foo", ColumnName: "id"}},
+			Comment:             &comment,
+		}},
+		Procedures: []Procedure{{
+			SchemaQualifiedName: name,
+			DependsOnFunctions:  []SchemaQualifiedName{name},
+		}},
+		Aggregates: []Aggregate{{
+			SchemaQualifiedName: name,
+			StateFunction:       name,
+			FinalFunction:       &name,
+			CombineFunction:     &name,
+			SerialFunction:      &name,
+			DeserialFunction:    &name,
+			InitialCondition:    &initialCondition,
+			DependsOnFunctions:  []SchemaQualifiedName{name},
+		}},
+		Triggers:      []Trigger{{EscapedName: "\"trigger\"", OwningTable: name, Function: name}},
+		EventTriggers: []EventTrigger{{Name: "event_trigger", Function: name, Tags: []string{"CREATE TABLE"}}},
+		Operators:     []Operator{{SchemaQualifiedName: name, Function: name}},
+		OperatorClasses: []OperatorClass{{
+			SchemaQualifiedName: name,
+			DependsOnOperators:  []SchemaQualifiedName{name},
+			DependsOnFunctions:  []SchemaQualifiedName{name},
+		}},
+		Publications: []Publication{{Name: "pub", Tables: []SchemaQualifiedName{name}, Publish: []string{"insert"}}},
+		Privileges:   []Privilege{{ObjectType: "TABLE", Object: name, Grantee: "reader", Type: "SELECT"}},
+		DefaultPrivileges: []DefaultPrivilege{
+			{Grantor: "postgres", SchemaName: "public", ObjectType: "TABLES", Grantee: "reader", Type: "SELECT"},
+		},
+		ObjectOwners: []string{"postgres"},
+	}
+
+	copied := s.DeepCopy()
+	assert.Equal(t, s, copied)
+	assertNoSharedMemory(t, reflect.ValueOf(s), reflect.ValueOf(copied), "Schema")
+
+	// Modifying the copy should not modify the original
+	copied.Tables[0].Columns[0].Identity.StartValue = 2
+	copied.Indexes[0].Columns[0] = "other"
+	copied.Publications[0].Tables = append(copied.Publications[0].Tables[:0], SchemaQualifiedName{})
+	assert.Equal(t, int64(1), s.Tables[0].Columns[0].Identity.StartValue)
+	assert.Equal(t, []string{"id"}, s.Indexes[0].Columns)
+	assert.Equal(t, []SchemaQualifiedName{name}, s.Publications[0].Tables)
+}
+
+func TestSchema_DeepCopy_PreservesNil(t *testing.T) {
+	s := Schema{Tables: []Table{{SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}}}}
+	copied := s.DeepCopy()
+	assert.Equal(t, s, copied)
+	assert.Nil(t, copied.Indexes)
+	assert.Nil(t, copied.Tables[0].Columns)
+}
+
+// assertNoSharedMemory asserts that the two values do not share any slices or pointers. It also asserts every slice
+// and pointer is populated, such that all fields are checked.
+func assertNoSharedMemory(t *testing.T, a, b reflect.Value, path string) {
+	switch a.Kind() {
+	case reflect.Ptr:
+		if !assert.False(t, a.IsNil(), "%s is nil", path) {
+			return
+		}
+		assert.NotEqual(t, a.Pointer(), b.Pointer(), "%s is shared", path)
+		assertNoSharedMemory(t, a.Elem(), b.Elem(), path)
+	case reflect.Map:
+		if !assert.NotZero(t, a.Len(), "%s is empty", path) {
+			return
+		}
+		assert.NotEqual(t, a.Pointer(), b.Pointer(), "%s is shared", path)
+	case reflect.Slice:
+		if !assert.NotZero(t, a.Len(), "%s is empty", path) {
+			return
+		}
+		assert.NotEqual(t, a.Pointer(), b.Pointer(), "%s is shared", path)
+		for i := 0; i < a.Len(); i++ {
+			assertNoSharedMemory(t, a.Index(i), b.Index(i), path+"[]")
+		}
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			assertNoSharedMemory(t, a.Field(i), b.Field(i), path+"."+a.Type().Field(i).Name)
+		}
+	}
+}
@@ -0,0 +1,1345 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pg_query "github.com/pganalyze/pg_query_go/v5"
+)
+
+var (
+	// psqlMetaCommandRegex matches psql meta-commands, e.g., `\restrict`, which newer versions of pg_dump emit but are
+	// not SQL
+	psqlMetaCommandRegex = regexp.MustCompile(`(?m)^\\.*$`)
+	// addConstraintRegex matches the ADD CONSTRAINT clause of an ALTER TABLE statement. The second matching group is
+	// the constraint definition, as returned by pg_get_constraintdef.
+	addConstraintRegex = regexp.MustCompile(`(?s)\bADD CONSTRAINT\s+("(?:[^"]|"")+"|\S+)\s+(.*)$`)
+	// setDefaultRegex matches the SET DEFAULT clause of an ALTER TABLE statement. The first matching group is the
+	// default expression, as returned by pg_get_expr.
+	setDefaultRegex = regexp.MustCompile(`(?s)\bSET DEFAULT\s+(.*)$`)
+	// viewCheckOptionRegex matches the check option that pg_dump appends to a view's definition
+	viewCheckOptionRegex = regexp.MustCompile(`\s+WITH (?:CASCADED|LOCAL) CHECK OPTION$`)
+	// sqlKeywordsRequiringQuotes are the keywords that quote_ident quotes, i.e., the keywords that are not unreserved
+	sqlKeywordsRequiringQuotes = map[string]bool{
+		"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true, "as": true,
+		"asc": true, "asymmetric": true, "authorization": true, "between": true, "bigint": true, "binary": true,
+		"bit": true, "boolean": true, "both": true, "case": true, "cast": true, "char": true, "character": true,
+		"check": true, "coalesce": true, "collate": true, "collation": true, "column": true, "concurrently": true,
+		"constraint": true, "create": true, "cross": true, "current_catalog": true, "current_date": true,
+		"current_role": true, "current_schema": true, "current_time": true, "current_timestamp": true,
+		"current_user": true, "dec": true, "decimal": true, "default": true, "deferrable": true, "desc": true,
+		"distinct": true, "do": true, "else": true, "end": true, "except": true, "exists": true, "extract": true,
+		"false": true, "fetch": true, "float": true, "for": true, "foreign": true, "freeze": true, "from": true,
+		"full": true, "grant": true, "greatest": true, "group": true, "grouping": true, "having": true,
+		"ilike": true, "in": true, "initially": true, "inner": true, "inout": true, "int": true, "integer": true,
+		"intersect": true, "interval": true, "into": true, "is": true, "isnull": true, "join": true,
+		"lateral": true, "leading": true, "least": true, "left": true, "like": true, "limit": true,
+		"localtime": true, "localtimestamp": true, "national": true, "natural": true, "nchar": true,
+		"none": true, "normalize": true, "not": true, "notnull": true, "null": true, "nullif": true,
+		"numeric": true, "offset": true, "on": true, "only": true, "or": true, "order": true, "out": true,
+		"outer": true, "overlaps": true, "overlay": true, "placing": true, "position": true, "precision": true,
+		"primary": true, "real": true, "references": true, "returning": true, "right": true, "row": true,
+		"select": true, "session_user": true, "setof": true, "similar": true, "smallint": true, "some": true,
+		"substring": true, "symmetric": true, "system_user": true, "table": true, "tablesample": true,
+		"then": true, "time": true, "timestamp": true, "to": true, "trailing": true, "treat": true, "trim": true,
+		"true": true, "union": true, "unique": true, "user": true, "using": true, "values": true,
+		"varchar": true, "variadic": true, "verbose": true, "when": true, "where": true, "window": true,
+		"with": true,
+	}
+	// volatileFunctionNames are the built-in (and commonly used extension) functions that are volatile. They are used
+	// to determine if a column's default is volatile, since the volatility of functions is not included in a dump.
+	volatileFunctionNames = map[string]bool{
+		"clock_timestamp":    true,
+		"gen_random_uuid":    true,
+		"nextval":            true,
+		"random":             true,
+		"setval":             true,
+		"timeofday":          true,
+		"uuid_generate_v1":   true,
+		"uuid_generate_v1mc": true,
+		"uuid_generate_v4":   true,
+	}
+	defaultColumnCollation = SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: EscapeIdentifier("default")}
+	// builtInTypes are the built-in types, keyed by their internal names, e.g., int4. The properties of types are not
+	// included in a dump, so they are hardcoded.
+	builtInTypes = map[string]builtInType{
+		"bool":        {name: "boolean", size: 1, storage: ColumnStorageTypePlain},
+		"bpchar":      {name: "character", size: -1, storage: ColumnStorageTypeExtended, collation: &defaultColumnCollation},
+		"bytea":       {name: "bytea", size: -1, storage: ColumnStorageTypeExtended},
+		"cidr":        {name: "cidr", size: -1, storage: ColumnStorageTypeMain},
+		"citext":      {name: "citext", size: -1, storage: ColumnStorageTypeExtended, collation: &defaultColumnCollation},
+		"date":        {name: "date", size: 4, storage: ColumnStorageTypePlain},
+		"float4":      {name: "real", size: 4, storage: ColumnStorageTypePlain},
+		"float8":      {name: "double precision", size: 8, storage: ColumnStorageTypePlain},
+		"inet":        {name: "inet", size: -1, storage: ColumnStorageTypeMain},
+		"int2":        {name: "smallint", size: 2, storage: ColumnStorageTypePlain},
+		"int4":        {name: "integer", size: 4, storage: ColumnStorageTypePlain},
+		"int8":        {name: "bigint", size: 8, storage: ColumnStorageTypePlain},
+		"interval":    {name: "interval", size: 16, storage: ColumnStorageTypePlain},
+		"json":        {name: "json", size: -1, storage: ColumnStorageTypeExtended},
+		"jsonb":       {name: "jsonb", size: -1, storage: ColumnStorageTypeExtended},
+		"macaddr":     {name: "macaddr", size: 6, storage: ColumnStorageTypePlain},
+		"macaddr8":    {name: "macaddr8", size: 8, storage: ColumnStorageTypePlain},
+		"money":       {name: "money", size: 8, storage: ColumnStorageTypePlain},
+		"numeric":     {name: "numeric", size: -1, storage: ColumnStorageTypeMain},
+		"oid":         {name: "oid", size: 4, storage: ColumnStorageTypePlain},
+		"point":       {name: "point", size: 16, storage: ColumnStorageTypePlain},
+		"text":        {name: "text", size: -1, storage: ColumnStorageTypeExtended, collation: &defaultColumnCollation},
+		"time":        {name: "time", suffix: " without time zone", size: 8, storage: ColumnStorageTypePlain},
+		"timestamp":   {name: "timestamp", suffix: " without time zone", size: 8, storage: ColumnStorageTypePlain},
+		"timestamptz": {name: "timestamp", suffix: " with time zone", size: 8, storage: ColumnStorageTypePlain},
+		"timetz":      {name: "time", suffix: " with time zone", size: 12, storage: ColumnStorageTypePlain},
+		"tsvector":    {name: "tsvector", size: -1, storage: ColumnStorageTypeExtended},
+		"uuid":        {name: "uuid", size: 16, storage: ColumnStorageTypePlain},
+		"varbit":      {name: "bit varying", size: -1, storage: ColumnStorageTypeExtended},
+		"bit":         {name: "bit", size: -1, storage: ColumnStorageTypeExtended},
+		"varchar":     {name: "character varying", size: -1, storage: ColumnStorageTypeExtended, collation: &defaultColumnCollation},
+		"xml":         {name: "xml", size: -1, storage: ColumnStorageTypeExtended},
+	}
+	columnStorageTypesByName = map[string]ColumnStorageType{
+		"plain":    ColumnStorageTypePlain,
+		"external": ColumnStorageTypeExternal,
+		"extended": ColumnStorageTypeExtended,
+		"main":     ColumnStorageTypeMain,
+	}
+)
+
+type (
+	builtInType struct {
+		// name is the name of the type as returned by format_type. The typmod, e.g., (255), is inserted between the
+		// name and the suffix.
+		name   string
+		suffix string
+		// size is the typlen of the type, i.e., -1 for variable-length types
+		size    int
+		storage ColumnStorageType
+		// collation is the default collation of the type. It is nil if the type is not collatable.
+		collation *SchemaQualifiedName
+	}
+
+	// columnType is a column's type as returned by format_type, along with the properties of the type that are not
+	// included in a dump
+	columnType struct {
+		name      string
+		size      int
+		storage   ColumnStorageType
+		collation SchemaQualifiedName
+	}
+
+	dumpParser struct {
+		dump string
+		// defaultTablespace is the tablespace set via `SET default_tablespace`, which pg_dump uses to place tables and
+		// indexes in non-default tablespaces
+		defaultTablespace string
+
+		schema             Schema
+		tableIdxsByName    map[string]int
+		viewIdxsByName     map[string]int
+		indexIdxsByName    map[string]int
+		sequenceIdxsByName map[string]int
+		enumNames          map[string]bool
+	}
+)
+
+// ParseDump parses the output of `pg_dump --schema-only` into a Schema, such that a schema can be diffed without a
+// connection to the database it was dumped from. The parsed schema is intended to be equivalent to the schema
+// returned by GetSchema from the dumped database with the default search_path, i.e., names in the public schema are
+// not qualified in expressions.
+//
+// Only a subset of objects is supported: schemas, extensions, enums, tables (excluding partitioned and inherited
+// tables), columns, check constraints, indexes (including primary key and unique constraints), foreign keys,
+// sequences, views, and comments on these objects. An error is returned if the dump contains any other statement,
+// e.g., CREATE FUNCTION or GRANT, rather than silently omitting the object from the schema. Ownership is not parsed.
+//
+// The properties of built-in types, e.g., their size, and the volatility of built-in functions are not included in a
+// dump, so they are derived from the type and function names. View definitions are returned exactly as they appear in
+// the dump, which might be parenthesized differently from GetSchema for views with operator expressions. Use
+// Schema.NormalizeViewDefinitions to normalize them.
+func ParseDump(r io.Reader) (Schema, error) {
+	dump, err := io.ReadAll(r)
+	if err != nil {
+		return Schema{}, fmt.Errorf("reading dump: %w", err)
+	}
+	p := &dumpParser{
+		// Blank out the meta-commands rather than removing them, such that the locations in the parse tree still
+		// refer to the dump
+		dump: psqlMetaCommandRegex.ReplaceAllStringFunc(string(dump), func(metaCommand string) string {
+			return strings.Repeat(" ", len(metaCommand))
+		}),
+		schema: Schema{
+			// The public schema is created by initdb, so pg_dump does not create it
+			NamedSchemas: []NamedSchema{{Name: "public"}},
+		},
+		tableIdxsByName:    make(map[string]int),
+		viewIdxsByName:     make(map[string]int),
+		indexIdxsByName:    make(map[string]int),
+		sequenceIdxsByName: make(map[string]int),
+		enumNames:          make(map[string]bool),
+	}
+
+	parseResult, err := pg_query.Parse(p.dump)
+	if err != nil {
+		return Schema{}, fmt.Errorf("parsing dump: %w", err)
+	}
+	for _, rawStmt := range parseResult.Stmts {
+		start, end := p.getStmtBounds(rawStmt)
+		if err := p.parseStmt(rawStmt.Stmt, start, end); err != nil {
+			return Schema{}, fmt.Errorf("line %d: %w", strings.Count(p.dump[:start], "\n")+1, err)
+		}
+	}
+	if err := p.resolveViewDependencies(); err != nil {
+		return Schema{}, fmt.Errorf("resolving view dependencies: %w", err)
+	}
+
+	return markPrimaryKeyColumns(p.schema), nil
+}
+
+// getStmtBounds gets the start and end of the statement in the dump, excluding the leading comments, which pg_dump
+// uses to describe each object, and the trailing semicolon
+func (p *dumpParser) getStmtBounds(rawStmt *pg_query.RawStmt) (int, int) {
+	start := int(rawStmt.StmtLocation)
+	end := len(p.dump)
+	if rawStmt.StmtLen > 0 {
+		end = start + int(rawStmt.StmtLen)
+	}
+	for start < end {
+		trimmed := strings.TrimLeft(p.dump[start:end], " \t\r\n")
+		start = end - len(trimmed)
+		if !strings.HasPrefix(trimmed, "--") {
+			break
+		}
+		lineEnd := strings.IndexByte(trimmed, '\n')
+		if lineEnd == -1 {
+			start = end
+			break
+		}
+		start += lineEnd + 1
+	}
+	return start, end - (len(p.dump[start:end]) - len(strings.TrimRight(p.dump[start:end], " \t\r\n;")))
+}
+
+func (p *dumpParser) parseStmt(node *pg_query.Node, start, end int) error {
+	stmtText := p.dump[start:end]
+	switch n := node.Node.(type) {
+	case *pg_query.Node_VariableSetStmt:
+		if n.VariableSetStmt.Name == "default_tablespace" {
+			p.defaultTablespace = ""
+			if len(n.VariableSetStmt.Args) > 0 {
+				p.defaultTablespace = n.VariableSetStmt.Args[0].GetAConst().GetSval().GetSval()
+			}
+		}
+		return nil
+	case *pg_query.Node_SelectStmt:
+		// pg_dump only uses SELECT statements to call set_config, e.g., to clear the search_path
+		if strings.HasPrefix(stmtText, "SELECT pg_catalog.set_config(") {
+			return nil
+		}
+	case *pg_query.Node_AlterOwnerStmt:
+		// Ownership is not parsed
+		return nil
+	case *pg_query.Node_CreateSchemaStmt:
+		if n.CreateSchemaStmt.Schemaname != "public" {
+			p.schema.NamedSchemas = append(p.schema.NamedSchemas, NamedSchema{Name: n.CreateSchemaStmt.Schemaname})
+		}
+		return nil
+	case *pg_query.Node_CreateExtensionStmt:
+		return p.parseCreateExtension(n.CreateExtensionStmt)
+	case *pg_query.Node_CreateEnumStmt:
+		return p.parseCreateEnum(n.CreateEnumStmt)
+	case *pg_query.Node_CreateStmt:
+		return p.parseCreateTable(n.CreateStmt, end)
+	case *pg_query.Node_AlterTableStmt:
+		return p.parseAlterTable(n.AlterTableStmt, stmtText)
+	case *pg_query.Node_IndexStmt:
+		return p.parseCreateIndex(n.IndexStmt, stmtText)
+	case *pg_query.Node_CreateSeqStmt:
+		return p.parseCreateSequence(n.CreateSeqStmt)
+	case *pg_query.Node_AlterSeqStmt:
+		return p.parseAlterSequence(n.AlterSeqStmt)
+	case *pg_query.Node_ViewStmt:
+		return p.parseCreateView(n.ViewStmt, end)
+	case *pg_query.Node_CommentStmt:
+		return p.parseComment(n.CommentStmt)
+	}
+	return fmt.Errorf("unsupported statement: %s", firstLine(stmtText))
+}
+
+func (p *dumpParser) parseCreateExtension(stmt *pg_query.CreateExtensionStmt) error {
+	extension := Extension{SchemaQualifiedName: SchemaQualifiedName{EscapedName: EscapeIdentifier(stmt.Extname)}}
+	for _, option := range stmt.Options {
+		defElem := option.GetDefElem()
+		switch defElem.GetDefname() {
+		case "schema":
+			extension.SchemaName = defElem.GetArg().GetString_().GetSval()
+		case "new_version":
+			extension.Version = defElem.GetArg().GetString_().GetSval()
+		default:
+			return fmt.Errorf("unsupported extension option %q", defElem.GetDefname())
+		}
+	}
+	if extension.SchemaName == "" {
+		return fmt.Errorf("extension %s has no schema", stmt.Extname)
+	}
+	p.schema.Extensions = append(p.schema.Extensions, extension)
+	return nil
+}
+
+func (p *dumpParser) parseCreateEnum(stmt *pg_query.CreateEnumStmt) error {
+	name, err := buildNameFromNodes(stmt.TypeName)
+	if err != nil {
+		return fmt.Errorf("building enum name: %w", err)
+	}
+	var labels []string
+	for _, val := range stmt.Vals {
+		labels = append(labels, val.GetString_().GetSval())
+	}
+	p.schema.Enums = append(p.schema.Enums, Enum{SchemaQualifiedName: name, Labels: labels})
+	p.enumNames[name.GetName()] = true
+	return nil
+}
+
+func (p *dumpParser) parseCreateTable(stmt *pg_query.CreateStmt, end int) error {
+	if stmt.Partspec != nil || stmt.Partbound != nil || len(stmt.InhRelations) > 0 || stmt.OfTypename != nil {
+		return fmt.Errorf("partitioned, inherited, and typed tables are not supported: %s", stmt.Relation.Relname)
+	}
+
+	table := Table{
+		SchemaQualifiedName: buildNameFromRangeVar(stmt.Relation),
+		ReplicaIdentity:     ReplicaIdentityDefault,
+		Tablespace:          p.defaultTablespace,
+	}
+	if stmt.Tablespacename != "" {
+		table.Tablespace = stmt.Tablespacename
+	}
+	for _, option := range stmt.Options {
+		defElem := option.GetDefElem()
+		value, err := getDefElemValue(defElem)
+		if err != nil {
+			return fmt.Errorf("getting storage parameter %q: %w", defElem.GetDefname(), err)
+		}
+		if table.StorageParameters == nil {
+			table.StorageParameters = make(map[string]string)
+		}
+		name := defElem.GetDefname()
+		if defElem.GetDefnamespace() != "" {
+			name = defElem.GetDefnamespace() + "." + name
+		}
+		table.StorageParameters[name] = value
+	}
+
+	for _, elt := range stmt.TableElts {
+		switch {
+		case elt.GetColumnDef() != nil:
+			columnDef := elt.GetColumnDef()
+			column, checkCons, err := p.buildColumn(columnDef, p.findElementEnd(int(columnDef.Location), end))
+			if err != nil {
+				return fmt.Errorf("building column %q: %w", columnDef.Colname, err)
+			}
+			table.Columns = append(table.Columns, column)
+			table.CheckConstraints = append(table.CheckConstraints, checkCons...)
+		case elt.GetConstraint() != nil:
+			constraint := elt.GetConstraint()
+			if constraint.Contype != pg_query.ConstrType_CONSTR_CHECK {
+				return fmt.Errorf("unsupported table constraint %q", constraint.Conname)
+			}
+			checkCon, err := p.buildCheckConstraint(constraint, p.dump[constraint.Location:p.findElementEnd(int(constraint.Location), end)])
+			if err != nil {
+				return fmt.Errorf("building check constraint %q: %w", constraint.Conname, err)
+			}
+			table.CheckConstraints = append(table.CheckConstraints, checkCon)
+		default:
+			return fmt.Errorf("unsupported table element in %s", table.GetFQEscapedName())
+		}
+	}
+	// The key columns of check constraints are only known once all columns are parsed
+	for i := range table.CheckConstraints {
+		keyColumns, err := getCheckConstraintKeyColumns(table, table.CheckConstraints[i].Expression)
+		if err != nil {
+			return fmt.Errorf("getting key columns of check constraint %q: %w", table.CheckConstraints[i].Name, err)
+		}
+		table.CheckConstraints[i].KeyColumns = keyColumns
+	}
+
+	p.tableIdxsByName[table.GetName()] = len(p.schema.Tables)
+	p.schema.Tables = append(p.schema.Tables, table)
+	return nil
+}
+
+// buildColumn builds the column from its definition, which ends at end. Check constraints declared on the column are
+// returned separately, since they belong to the table.
+func (p *dumpParser) buildColumn(columnDef *pg_query.ColumnDef, end int) (Column, []CheckConstraint, error) {
+	colType, err := p.buildColumnType(columnDef.TypeName)
+	if err != nil {
+		return Column{}, nil, fmt.Errorf("building type: %w", err)
+	}
+	column := Column{
+		Name:       columnDef.Colname,
+		Type:       colType.name,
+		Collation:  colType.collation,
+		IsNullable: true,
+		Size:       colType.size,
+	}
+	if columnDef.CollClause != nil {
+		collation, err := buildNameFromNodes(columnDef.CollClause.Collname)
+		if err != nil {
+			return Column{}, nil, fmt.Errorf("building collation name: %w", err)
+		}
+		column.Collation = collation
+	}
+
+	// Each clause of the column definition ends where the next one starts
+	clauseStarts := []int{end}
+	if columnDef.CollClause != nil {
+		clauseStarts = append(clauseStarts, int(columnDef.CollClause.Location))
+	}
+	for _, node := range columnDef.Constraints {
+		clauseStarts = append(clauseStarts, int(node.GetConstraint().Location))
+	}
+	getClauseText := func(start int) string {
+		clauseEnd := end
+		for _, clauseStart := range clauseStarts {
+			if clauseStart > start && clauseStart < clauseEnd {
+				clauseEnd = clauseStart
+			}
+		}
+		return strings.TrimSpace(p.dump[start:clauseEnd])
+	}
+
+	var checkCons []CheckConstraint
+	for _, node := range columnDef.Constraints {
+		constraint := node.GetConstraint()
+		clauseText := getClauseText(int(constraint.Location))
+		switch constraint.Contype {
+		case pg_query.ConstrType_CONSTR_NOTNULL:
+			column.IsNullable = false
+		case pg_query.ConstrType_CONSTR_NULL:
+			column.IsNullable = true
+		case pg_query.ConstrType_CONSTR_DEFAULT:
+			column.Default = unqualifyPublicNames(strings.TrimSpace(strings.TrimPrefix(clauseText, "DEFAULT")))
+			column.IsDefaultVolatile, err = isExpressionVolatile(column.Default)
+			if err != nil {
+				return Column{}, nil, fmt.Errorf("checking volatility of default: %w", err)
+			}
+		case pg_query.ConstrType_CONSTR_GENERATED:
+			expression, ok := getParenthesizedContent(clauseText, strings.IndexByte(clauseText, '('))
+			if !ok {
+				return Column{}, nil, fmt.Errorf("unexpected generated column definition %q", clauseText)
+			}
+			column.IsGenerated = true
+			column.GenerationExpression = unqualifyPublicNames(expression)
+		case pg_query.ConstrType_CONSTR_IDENTITY:
+			identity, err := buildColumnIdentity(constraint, colType.name)
+			if err != nil {
+				return Column{}, nil, fmt.Errorf("building identity: %w", err)
+			}
+			column.Identity = identity
+		case pg_query.ConstrType_CONSTR_CHECK:
+			checkCon, err := p.buildCheckConstraint(constraint, clauseText)
+			if err != nil {
+				return Column{}, nil, fmt.Errorf("building check constraint %q: %w", constraint.Conname, err)
+			}
+			checkCons = append(checkCons, checkCon)
+		default:
+			return Column{}, nil, fmt.Errorf("unsupported column constraint %q", clauseText)
+		}
+	}
+	return column, checkCons, nil
+}
+
+// buildColumnType builds the type as it would be returned by format_type. Types in the public schema are not
+// qualified, since they are on the default search_path.
+func (p *dumpParser) buildColumnType(typeName *pg_query.TypeName) (columnType, error) {
+	var names []string
+	for _, node := range typeName.Names {
+		names = append(names, node.GetString_().GetSval())
+	}
+	var typmods []string
+	for _, node := range typeName.Typmods {
+		aConst := node.GetAConst()
+		if aConst == nil || aConst.GetIval() == nil {
+			return columnType{}, fmt.Errorf("unsupported type modifier on %s", strings.Join(names, "."))
+		}
+		typmods = append(typmods, strconv.Itoa(int(aConst.GetIval().GetIval())))
+	}
+	typmod := ""
+	if len(typmods) > 0 {
+		typmod = "(" + strings.Join(typmods, ",") + ")"
+	}
+
+	colType := columnType{
+		// Variable-length and extended storage is the most common for types that are not built-in
+		size:    -1,
+		storage: ColumnStorageTypeExtended,
+	}
+	baseName := names[len(names)-1]
+	if builtIn, ok := builtInTypes[baseName]; ok {
+		colType.size = builtIn.size
+		colType.storage = builtIn.storage
+		if builtIn.collation != nil {
+			colType.collation = *builtIn.collation
+		}
+	}
+	if len(names) == 2 && p.enumNames[buildNameFromUnescaped(names[1], names[0]).GetName()] {
+		colType.size = 4
+		colType.storage = ColumnStorageTypePlain
+	}
+
+	if builtIn, ok := builtInTypes[baseName]; ok && names[0] == "pg_catalog" {
+		// The parser translates the SQL-standard names of built-in types, e.g., "timestamp with time zone", to their
+		// internal names
+		if baseName == "interval" && typmod != "" {
+			return columnType{}, fmt.Errorf("interval fields and precision are not supported")
+		}
+		colType.name = builtIn.name + typmod + builtIn.suffix
+	} else {
+		var escapedNames []string
+		for i, name := range names {
+			if i == 0 && len(names) > 1 && name == "public" {
+				continue
+			}
+			escapedNames = append(escapedNames, quoteIdentifierIfNeeded(name))
+		}
+		colType.name = strings.Join(escapedNames, ".") + typmod
+	}
+
+	if len(typeName.ArrayBounds) > 0 {
+		colType.name += "[]"
+		colType.size = -1
+		colType.storage = ColumnStorageTypeExtended
+	}
+	return colType, nil
+}
+
+// buildCheckConstraint builds the check constraint from its definition, e.g., `CONSTRAINT foo CHECK ((bar > 0))`. The
+// key columns are populated separately, once all the table's columns are known.
+func (p *dumpParser) buildCheckConstraint(constraint *pg_query.Constraint, def string) (CheckConstraint, error) {
+	checkIdx := strings.Index(def, "CHECK")
+	if checkIdx == -1 {
+		return CheckConstraint{}, fmt.Errorf("unexpected check constraint definition %q", def)
+	}
+	expression, ok := getParenthesizedContent(def, checkIdx+strings.IndexByte(def[checkIdx:], '('))
+	if !ok {
+		return CheckConstraint{}, fmt.Errorf("unexpected check constraint definition %q", def)
+	}
+	return CheckConstraint{
+		Name:          constraint.Conname,
+		Expression:    unqualifyPublicNames(expression),
+		IsValid:       !constraint.SkipValidation,
+		IsInheritable: !constraint.IsNoInherit,
+	}, nil
+}
+
+func (p *dumpParser) parseAlterTable(stmt *pg_query.AlterTableStmt, stmtText string) error {
+	for _, cmdNode := range stmt.Cmds {
+		cmd := cmdNode.GetAlterTableCmd()
+		if cmd.Subtype == pg_query.AlterTableType_AT_ChangeOwner {
+			// Ownership is not parsed
+			continue
+		}
+		if len(stmt.Cmds) > 1 {
+			return fmt.Errorf("unsupported statement: %s", firstLine(stmtText))
+		}
+
+		tableName := buildNameFromRangeVar(stmt.Relation)
+		tableIdx, ok := p.tableIdxsByName[tableName.GetName()]
+		if stmt.Objtype != pg_query.ObjectType_OBJECT_TABLE || !ok {
+			return fmt.Errorf("unsupported statement: %s", firstLine(stmtText))
+		}
+		table := &p.schema.Tables[tableIdx]
+
+		switch cmd.Subtype {
+		case pg_query.AlterTableType_AT_ColumnDefault:
+			column, err := getColumn(table, cmd.Name)
+			if err != nil {
+				return err
+			}
+			match := setDefaultRegex.FindStringSubmatch(stmtText)
+			if match == nil {
+				return fmt.Errorf("unsupported statement: %s", firstLine(stmtText))
+			}
+			column.Default = unqualifyPublicNames(strings.TrimSpace(match[1]))
+			column.IsDefaultVolatile, err = isExpressionVolatile(column.Default)
+			if err != nil {
+				return fmt.Errorf("checking volatility of default of column %q: %w", cmd.Name, err)
+			}
+		case pg_query.AlterTableType_AT_AddIdentity:
+			column, err := getColumn(table, cmd.Name)
+			if err != nil {
+				return err
+			}
+			identity, err := buildColumnIdentity(cmd.Def.GetConstraint(), column.Type)
+			if err != nil {
+				return fmt.Errorf("building identity of column %q: %w", cmd.Name, err)
+			}
+			column.Identity = identity
+		case pg_query.AlterTableType_AT_SetStorage:
+			column, err := getColumn(table, cmd.Name)
+			if err != nil {
+				return err
+			}
+			storageType, ok := columnStorageTypesByName[strings.ToLower(cmd.Def.GetString_().GetSval())]
+			if !ok {
+				return fmt.Errorf("unsupported storage type %q", cmd.Def.GetString_().GetSval())
+			}
+			colType, err := p.buildColumnTypeFromFormatted(column.Type)
+			if err != nil {
+				return fmt.Errorf("building type of column %q: %w", cmd.Name, err)
+			}
+			if storageType != colType.storage {
+				column.StorageType = storageType
+				column.DefaultStorageType = colType.storage
+			}
+		case pg_query.AlterTableType_AT_ReplicaIdentity:
+			table.ReplicaIdentity = ReplicaIdentity(cmd.Def.GetReplicaIdentityStmt().GetIdentityType())
+		case pg_query.AlterTableType_AT_EnableRowSecurity:
+			table.RLSEnabled = true
+		case pg_query.AlterTableType_AT_ForceRowSecurity:
+			table.RLSForced = true
+		case pg_query.AlterTableType_AT_AddConstraint:
+			match := addConstraintRegex.FindStringSubmatch(stmtText)
+			if match == nil {
+				return fmt.Errorf("unsupported statement: %s", firstLine(stmtText))
+			}
+			if err := p.parseAddConstraint(table, cmd.Def.GetConstraint(), match[2]); err != nil {
+				return fmt.Errorf("adding constraint %q to %s: %w", cmd.Def.GetConstraint().GetConname(), tableName.GetFQEscapedName(), err)
+			}
+		default:
+			return fmt.Errorf("unsupported statement: %s", firstLine(stmtText))
+		}
+	}
+	return nil
+}
+
+// buildColumnTypeFromFormatted builds the type from its formatted name, e.g., "character varying(255)"
+func (p *dumpParser) buildColumnTypeFromFormatted(formattedType string) (columnType, error) {
+	parseResult, err := pg_query.Parse(fmt.Sprintf("SELECT NULL::%s", formattedType))
+	if err != nil {
+		return columnType{}, fmt.Errorf("parsing type %q: %w", formattedType, err)
+	}
+	typeCast := parseResult.Stmts[0].Stmt.GetSelectStmt().GetTargetList()[0].GetResTarget().GetVal().GetTypeCast()
+	if typeCast == nil {
+		return columnType{}, fmt.Errorf("unexpected type %q", formattedType)
+	}
+	// Names in the public schema are unqualified in the formatted type
+	if names := typeCast.TypeName.Names; len(names) == 1 && p.enumNames[buildNameFromUnescaped(names[0].GetString_().GetSval(), "public").GetName()] {
+		typeCast.TypeName.Names = append([]*pg_query.Node{pg_query.MakeStrNode("public")}, names...)
+	}
+	return p.buildColumnType(typeCast.TypeName)
+}
+
+// parseAddConstraint parses a constraint added via ALTER TABLE, which is how pg_dump creates every constraint other
+// than check constraints. def is the definition of the constraint, as returned by pg_get_constraintdef.
+func (p *dumpParser) parseAddConstraint(table *Table, constraint *pg_query.Constraint, def string) error {
+	switch constraint.Contype {
+	case pg_query.ConstrType_CONSTR_CHECK:
+		checkCon, err := p.buildCheckConstraint(constraint, def)
+		if err != nil {
+			return err
+		}
+		checkCon.KeyColumns, err = getCheckConstraintKeyColumns(*table, checkCon.Expression)
+		if err != nil {
+			return fmt.Errorf("getting key columns: %w", err)
+		}
+		table.CheckConstraints = append(table.CheckConstraints, checkCon)
+	case pg_query.ConstrType_CONSTR_PRIMARY, pg_query.ConstrType_CONSTR_UNIQUE:
+		if constraint.Indexname != "" || len(constraint.Options) > 0 || constraint.WhereClause != nil {
+			return fmt.Errorf("unsupported constraint definition %q", def)
+		}
+		constraintType := PkIndexConstraintType
+		if constraint.Contype == pg_query.ConstrType_CONSTR_UNIQUE {
+			constraintType = UniqueIndexConstraintType
+		}
+		columns := getStrings(constraint.Keys)
+		includedColumns := getStrings(constraint.Including)
+		var escapedColumns []string
+		for _, column := range columns {
+			escapedColumns = append(escapedColumns, quoteIdentifierIfNeeded(column))
+		}
+		// The index definition is as returned by pg_get_indexdef
+		indexDef := fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s.%s USING btree (%s)",
+			quoteIdentifierIfNeeded(constraint.Conname),
+			quoteIdentifierIfNeeded(table.SchemaName),
+			quoteIdentifierIfNeeded(unescapeIdentifier(table.EscapedName)),
+			strings.Join(escapedColumns, ", "),
+		)
+		if len(includedColumns) > 0 {
+			var escapedIncludedColumns []string
+			for _, column := range includedColumns {
+				escapedIncludedColumns = append(escapedIncludedColumns, quoteIdentifierIfNeeded(column))
+			}
+			indexDef += fmt.Sprintf(" INCLUDE (%s)", strings.Join(escapedIncludedColumns, ", "))
+		}
+		if constraint.NullsNotDistinct {
+			indexDef += " NULLS NOT DISTINCT"
+		}
+		tablespace := p.defaultTablespace
+		if constraint.Indexspace != "" {
+			tablespace = constraint.Indexspace
+		}
+		p.addIndex(Index{
+			Name:            constraint.Conname,
+			OwningTable:     table.SchemaQualifiedName,
+			Columns:         columns,
+			IncludedColumns: includedColumns,
+			IsUnique:        true,
+			Method:          "btree",
+			Constraint: &IndexConstraint{
+				Type:                  constraintType,
+				EscapedConstraintName: EscapeIdentifier(constraint.Conname),
+				ConstraintDef:         def,
+				IsLocal:               true,
+				Deferrable:            constraint.Deferrable,
+				InitiallyDeferred:     constraint.Initdeferred,
+			},
+			GetIndexDefStmt: GetIndexDefStatement(indexDef),
+			Tablespace:      tablespace,
+		})
+	case pg_query.ConstrType_CONSTR_FOREIGN:
+		p.schema.ForeignKeyConstraints = append(p.schema.ForeignKeyConstraints, ForeignKeyConstraint{
+			EscapedName:       EscapeIdentifier(constraint.Conname),
+			OwningTable:       table.SchemaQualifiedName,
+			ForeignTable:      buildNameFromRangeVar(constraint.Pktable),
+			ConstraintDef:     unqualifyPublicNames(def),
+			IsValid:           !constraint.SkipValidation,
+			Deferrable:        constraint.Deferrable,
+			InitiallyDeferred: constraint.Initdeferred,
+		})
+	default:
+		return fmt.Errorf("unsupported constraint definition %q", def)
+	}
+	return nil
+}
+
+func (p *dumpParser) parseCreateIndex(stmt *pg_query.IndexStmt, stmtText string) error {
+	tableName := buildNameFromRangeVar(stmt.Relation)
+	if _, ok := p.tableIdxsByName[tableName.GetName()]; !ok {
+		return fmt.Errorf("unsupported statement: %s", firstLine(stmtText))
+	}
+
+	index := Index{
+		Name:            stmt.Idxname,
+		OwningTable:     tableName,
+		IsUnique:        stmt.Unique,
+		Method:          stmt.AccessMethod,
+		GetIndexDefStmt: GetIndexDefStatement(stmtText),
+		Tablespace:      p.defaultTablespace,
+	}
+	if stmt.TableSpace != "" {
+		index.Tablespace = stmt.TableSpace
+	}
+	for _, node := range stmt.IndexIncludingParams {
+		index.IncludedColumns = append(index.IncludedColumns, node.GetIndexElem().GetName())
+	}
+	var reloptions []string
+	for _, option := range stmt.Options {
+		value, err := getDefElemValue(option.GetDefElem())
+		if err != nil {
+			return fmt.Errorf("getting storage parameter %q of index %s: %w", option.GetDefElem().GetDefname(), stmt.Idxname, err)
+		}
+		reloptions = append(reloptions, fmt.Sprintf("%s=%s", option.GetDefElem().GetDefname(), value))
+	}
+	pagesPerRange, autosummarize, err := buildBrinParameters(reloptions)
+	if err != nil {
+		return fmt.Errorf("building storage parameters of index %s: %w", stmt.Idxname, err)
+	}
+	index.PagesPerRange = pagesPerRange
+	index.Autosummarize = autosummarize
+	index.GINParameters, err = buildGINParameters(reloptions)
+	if err != nil {
+		return fmt.Errorf("building storage parameters of index %s: %w", stmt.Idxname, err)
+	}
+
+	// The key columns are listed in parentheses after the access method
+	keysStart := strings.Index(stmtText, fmt.Sprintf(" USING %s (", stmt.AccessMethod))
+	if keysStart == -1 {
+		return fmt.Errorf("unexpected index definition %q", stmtText)
+	}
+	keysText, ok := getParenthesizedContent(stmtText, keysStart+len(fmt.Sprintf(" USING %s ", stmt.AccessMethod)))
+	if !ok {
+		return fmt.Errorf("unexpected index definition %q", stmtText)
+	}
+	keyTexts := splitTopLevel(keysText, ',')
+	if len(keyTexts) != len(stmt.IndexParams) {
+		return fmt.Errorf("unexpected index definition %q", stmtText)
+	}
+	for i, node := range stmt.IndexParams {
+		indexElem := node.GetIndexElem()
+		if indexElem.Expr == nil {
+			index.Columns = append(index.Columns, indexElem.Name)
+			continue
+		}
+		// Expressions that are not function calls are parenthesized in the index definition
+		keyText := strings.TrimSpace(keyTexts[i])
+		openIdx := strings.IndexByte(keyText, '(')
+		expressionEnd := findParenthesizedEnd(keyText, openIdx)
+		if openIdx == -1 || expressionEnd == -1 {
+			return fmt.Errorf("unexpected index expression %q", keyText)
+		}
+		expression := keyText[:expressionEnd+1]
+		if openIdx == 0 {
+			expression = keyText[1:expressionEnd]
+		}
+		index.Expressions = append(index.Expressions, unqualifyPublicNames(expression))
+	}
+	if stmt.WhereClause != nil {
+		whereIdx := strings.LastIndex(stmtText, " WHERE ")
+		if whereIdx == -1 {
+			return fmt.Errorf("unexpected index definition %q", stmtText)
+		}
+		index.Predicate = unqualifyPublicNames(strings.TrimSpace(stmtText[whereIdx+len(" WHERE "):]))
+	}
+
+	p.addIndex(index)
+	return nil
+}
+
+func (p *dumpParser) addIndex(index Index) {
+	p.indexIdxsByName[index.GetSchemaQualifiedName().GetName()] = len(p.schema.Indexes)
+	p.schema.Indexes = append(p.schema.Indexes, index)
+}
+
+func (p *dumpParser) parseCreateSequence(stmt *pg_query.CreateSeqStmt) error {
+	options, err := parseSequenceOptions(stmt.Options, "bigint")
+	if err != nil {
+		return fmt.Errorf("parsing options of sequence %s: %w", stmt.Sequence.Relname, err)
+	}
+	sequence := Sequence{
+		SchemaQualifiedName: buildNameFromRangeVar(stmt.Sequence),
+		Type:                options.Type,
+		StartValue:          options.StartValue,
+		Increment:           options.Increment,
+		MaxValue:            options.MaxValue,
+		MinValue:            options.MinValue,
+		CacheSize:           options.CacheSize,
+		Cycle:               options.Cycle,
+	}
+	p.sequenceIdxsByName[sequence.GetName()] = len(p.schema.Sequences)
+	p.schema.Sequences = append(p.schema.Sequences, sequence)
+	return nil
+}
+
+func (p *dumpParser) parseAlterSequence(stmt *pg_query.AlterSeqStmt) error {
+	sequenceName := buildNameFromRangeVar(stmt.Sequence)
+	sequenceIdx, ok := p.sequenceIdxsByName[sequenceName.GetName()]
+	if !ok || len(stmt.Options) != 1 || stmt.Options[0].GetDefElem().GetDefname() != "owned_by" {
+		return fmt.Errorf("unsupported ALTER SEQUENCE on %s", sequenceName.GetFQEscapedName())
+	}
+	ownedBy := getStrings(stmt.Options[0].GetDefElem().GetArg().GetList().GetItems())
+	if len(ownedBy) != 3 {
+		return fmt.Errorf("unexpected owner of sequence %s: %s", sequenceName.GetFQEscapedName(), strings.Join(ownedBy, "."))
+	}
+	p.schema.Sequences[sequenceIdx].Owner = &SequenceOwner{
+		TableName:  buildNameFromUnescaped(ownedBy[1], ownedBy[0]),
+		ColumnName: ownedBy[2],
+	}
+	return nil
+}
+
+func (p *dumpParser) parseCreateView(stmt *pg_query.ViewStmt, end int) error {
+	// pg_dump separates the view's name (and options) and its definition with " AS\n"
+	asIdx := strings.Index(p.dump[stmt.View.Location:end], " AS\n")
+	if asIdx == -1 {
+		return fmt.Errorf("unexpected definition of view %s", stmt.View.Relname)
+	}
+	definition := p.dump[int(stmt.View.Location)+asIdx+len(" AS\n") : end]
+	definition = viewCheckOptionRegex.ReplaceAllString(definition, "")
+
+	view := View{
+		SchemaQualifiedName: buildNameFromRangeVar(stmt.View),
+		// pg_get_viewdef includes the trailing semicolon, which pg_dump moves after the check option
+		Definition: unqualifyPublicNames(definition) + ";",
+	}
+	if viewIdx, ok := p.viewIdxsByName[view.GetName()]; ok {
+		// pg_dump creates a placeholder view and later replaces it to break circular dependencies
+		p.schema.Views[viewIdx] = view
+		return nil
+	}
+	p.viewIdxsByName[view.GetName()] = len(p.schema.Views)
+	p.schema.Views = append(p.schema.Views, view)
+	return nil
+}
+
+// resolveViewDependencies resolves the tables and views that each view depends on. It must be called after all
+// statements are parsed, since a view might depend on a view that replaces a placeholder later in the dump.
+func (p *dumpParser) resolveViewDependencies() error {
+	for i := range p.schema.Views {
+		view := &p.schema.Views[i]
+		seen := make(map[string]bool)
+		if err := walkParseTree(view.Definition, func(nodeType string, fields map[string]any) {
+			if nodeType != "RangeVar" {
+				return
+			}
+			relName, _ := fields["relname"].(string)
+			schemaName, _ := fields["schemaname"].(string)
+			if schemaName == "" {
+				// Names in the public schema are unqualified in the definition. Unqualified names that are not in
+				// the public schema, e.g., common table expressions, are not found below
+				schemaName = "public"
+			}
+			name := buildNameFromUnescaped(relName, schemaName)
+			if seen[name.GetName()] {
+				return
+			}
+			seen[name.GetName()] = true
+			if _, ok := p.tableIdxsByName[name.GetName()]; ok {
+				view.DependsOnTables = append(view.DependsOnTables, name)
+			} else if _, ok := p.viewIdxsByName[name.GetName()]; ok {
+				view.DependsOnViews = append(view.DependsOnViews, name)
+			}
+		}); err != nil {
+			return fmt.Errorf("parsing definition of view %s: %w", view.GetFQEscapedName(), err)
+		}
+	}
+	return nil
+}
+
+func (p *dumpParser) parseComment(stmt *pg_query.CommentStmt) error {
+	var comment *string
+	if stmt.Comment != "" {
+		comment = &stmt.Comment
+	}
+	var names []string
+	if list := stmt.Object.GetList(); list != nil {
+		names = getStrings(list.Items)
+	}
+
+	switch stmt.Objtype {
+	case pg_query.ObjectType_OBJECT_EXTENSION, pg_query.ObjectType_OBJECT_SCHEMA:
+		// Comments on extensions and schemas are not tracked
+		return nil
+	case pg_query.ObjectType_OBJECT_TABLE:
+		if len(names) == 2 {
+			if tableIdx, ok := p.tableIdxsByName[buildNameFromUnescaped(names[1], names[0]).GetName()]; ok {
+				p.schema.Tables[tableIdx].Comment = comment
+				return nil
+			}
+		}
+	case pg_query.ObjectType_OBJECT_COLUMN:
+		if len(names) == 3 {
+			if tableIdx, ok := p.tableIdxsByName[buildNameFromUnescaped(names[1], names[0]).GetName()]; ok {
+				column, err := getColumn(&p.schema.Tables[tableIdx], names[2])
+				if err != nil {
+					return err
+				}
+				column.Comment = comment
+				return nil
+			}
+		}
+	case pg_query.ObjectType_OBJECT_INDEX:
+		if len(names) == 2 {
+			if indexIdx, ok := p.indexIdxsByName[buildNameFromUnescaped(names[1], names[0]).GetName()]; ok {
+				p.schema.Indexes[indexIdx].Comment = comment
+				return nil
+			}
+		}
+	case pg_query.ObjectType_OBJECT_VIEW:
+		if len(names) == 2 {
+			if viewIdx, ok := p.viewIdxsByName[buildNameFromUnescaped(names[1], names[0]).GetName()]; ok {
+				p.schema.Views[viewIdx].Comment = comment
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unsupported comment on %s %s", strings.TrimPrefix(stmt.Objtype.String(), "OBJECT_"), strings.Join(names, "."))
+}
+
+// findElementEnd finds the end of the table element, e.g., a column definition, that starts at start, i.e., the
+// first comma or closing parenthesis that is not nested
+func (p *dumpParser) findElementEnd(start, end int) int {
+	depth := 0
+	for i := start; i < end; i++ {
+		switch p.dump[i] {
+		case '\'', '"':
+			i = findQuoteEnd(p.dump, i)
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return end
+}
+
+type sequenceOptions struct {
+	Type       string
+	StartValue int64
+	Increment  int64
+	MaxValue   int64
+	MinValue   int64
+	CacheSize  int64
+	Cycle      bool
+}
+
+// parseSequenceOptions parses the options of a sequence or identity column, filling in Postgres's defaults for the
+// options that pg_dump omits
+func parseSequenceOptions(options []*pg_query.Node, defaultType string) (sequenceOptions, error) {
+	seqOpts := sequenceOptions{Type: defaultType, Increment: 1, CacheSize: 1}
+	var startValue, minValue, maxValue *int64
+	for _, option := range options {
+		defElem := option.GetDefElem()
+		if defElem.GetDefname() == "as" {
+			typeNames := getStrings(defElem.GetArg().GetTypeName().GetNames())
+			formattedType, ok := builtInTypes[typeNames[len(typeNames)-1]]
+			if !ok {
+				return sequenceOptions{}, fmt.Errorf("unsupported sequence type")
+			}
+			seqOpts.Type = formattedType.name
+			continue
+		}
+		if defElem.GetDefname() == "sequence_name" {
+			continue
+		}
+		if defElem.GetDefname() == "cycle" {
+			seqOpts.Cycle = defElem.GetArg() == nil || defElem.GetArg().GetBoolean().GetBoolval()
+			continue
+		}
+		if defElem.GetArg() == nil {
+			// NO MINVALUE and NO MAXVALUE, which are the defaults
+			continue
+		}
+		value, err := getDefElemValue(defElem)
+		if err != nil {
+			return sequenceOptions{}, fmt.Errorf("getting %q: %w", defElem.GetDefname(), err)
+		}
+		intValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return sequenceOptions{}, fmt.Errorf("parsing %q: %w", defElem.GetDefname(), err)
+		}
+		switch defElem.GetDefname() {
+		case "start":
+			startValue = &intValue
+		case "increment":
+			seqOpts.Increment = intValue
+		case "minvalue":
+			minValue = &intValue
+		case "maxvalue":
+			maxValue = &intValue
+		case "cache":
+			seqOpts.CacheSize = intValue
+		default:
+			return sequenceOptions{}, fmt.Errorf("unsupported sequence option %q", defElem.GetDefname())
+		}
+	}
+
+	var typeMin, typeMax int64
+	switch seqOpts.Type {
+	case "smallint":
+		typeMin, typeMax = math.MinInt16, math.MaxInt16
+	case "integer":
+		typeMin, typeMax = math.MinInt32, math.MaxInt32
+	case "bigint":
+		typeMin, typeMax = math.MinInt64, math.MaxInt64
+	default:
+		return sequenceOptions{}, fmt.Errorf("unsupported sequence type %q", seqOpts.Type)
+	}
+	// The defaults of ascending and descending sequences differ
+	if seqOpts.Increment > 0 {
+		seqOpts.MinValue, seqOpts.MaxValue = 1, typeMax
+	} else {
+		seqOpts.MinValue, seqOpts.MaxValue = typeMin, -1
+	}
+	if minValue != nil {
+		seqOpts.MinValue = *minValue
+	}
+	if maxValue != nil {
+		seqOpts.MaxValue = *maxValue
+	}
+	seqOpts.StartValue = seqOpts.MinValue
+	if seqOpts.Increment < 0 {
+		seqOpts.StartValue = seqOpts.MaxValue
+	}
+	if startValue != nil {
+		seqOpts.StartValue = *startValue
+	}
+	return seqOpts, nil
+}
+
+func buildColumnIdentity(constraint *pg_query.Constraint, columnType string) (*ColumnIdentity, error) {
+	if constraint == nil || constraint.Contype != pg_query.ConstrType_CONSTR_IDENTITY {
+		return nil, fmt.Errorf("expected an identity constraint")
+	}
+	options, err := parseSequenceOptions(constraint.Options, columnType)
+	if err != nil {
+		return nil, fmt.Errorf("parsing sequence options: %w", err)
+	}
+	return &ColumnIdentity{
+		Type:       ColumnIdentityType(constraint.GeneratedWhen),
+		StartValue: options.StartValue,
+		Increment:  options.Increment,
+		MaxValue:   options.MaxValue,
+		MinValue:   options.MinValue,
+		CacheSize:  options.CacheSize,
+		Cycle:      options.Cycle,
+	}, nil
+}
+
+// getCheckConstraintKeyColumns gets the columns of the table referenced by the check constraint's expression, in the
+// order they are defined in the table
+func getCheckConstraintKeyColumns(table Table, expression string) ([]string, error) {
+	referenced := make(map[string]bool)
+	if err := walkParseTree("SELECT "+expression, func(nodeType string, fields map[string]any) {
+		if nodeType != "ColumnRef" {
+			return
+		}
+		columnFields, _ := fields["fields"].([]any)
+		if len(columnFields) != 1 {
+			return
+		}
+		if name, ok := getJSONStringNode(columnFields[0]); ok {
+			referenced[name] = true
+		}
+	}); err != nil {
+		return nil, err
+	}
+	var keyColumns []string
+	for _, column := range table.Columns {
+		if referenced[column.Name] {
+			keyColumns = append(keyColumns, column.Name)
+		}
+	}
+	return keyColumns, nil
+}
+
+// isExpressionVolatile returns whether the expression calls a volatile function. Only the functions in
+// volatileFunctionNames are known to be volatile.
+func isExpressionVolatile(expression string) (bool, error) {
+	isVolatile := false
+	if err := walkParseTree("SELECT "+expression, func(nodeType string, fields map[string]any) {
+		if nodeType != "FuncCall" {
+			return
+		}
+		funcName, _ := fields["funcname"].([]any)
+		if len(funcName) == 0 {
+			return
+		}
+		if name, ok := getJSONStringNode(funcName[len(funcName)-1]); ok && volatileFunctionNames[name] {
+			isVolatile = true
+		}
+	}); err != nil {
+		return false, err
+	}
+	return isVolatile, nil
+}
+
+// walkParseTree parses the SQL and calls visit with the type and fields of each node in the parse tree
+func walkParseTree(sql string, visit func(nodeType string, fields map[string]any)) error {
+	parseTreeJSON, err := pg_query.ParseToJSON(sql)
+	if err != nil {
+		return fmt.Errorf("parsing %q: %w", sql, err)
+	}
+	var parseTree any
+	if err := json.Unmarshal([]byte(parseTreeJSON), &parseTree); err != nil {
+		return fmt.Errorf("unmarshalling parse tree: %w", err)
+	}
+	var walk func(node any)
+	walk = func(node any) {
+		switch n := node.(type) {
+		case map[string]any:
+			for key, child := range n {
+				// Node types are capitalized, while fields are not
+				if fields, ok := child.(map[string]any); ok && len(key) > 0 && key[0] >= 'A' && key[0] <= 'Z' {
+					visit(key, fields)
+				}
+				walk(child)
+			}
+		case []any:
+			for _, child := range n {
+				walk(child)
+			}
+		}
+	}
+	walk(parseTree)
+	return nil
+}
+
+// getJSONStringNode gets the value of a String node in a JSON parse tree
+func getJSONStringNode(node any) (string, bool) {
+	stringNode, _ := node.(map[string]any)["String"].(map[string]any)
+	sval, ok := stringNode["sval"].(string)
+	return sval, ok
+}
+
+// unqualifyPublicNames removes the "public." qualification from the names in the SQL. pg_dump clears the search_path,
+// so every name is qualified, while names in the public schema are not qualified by GetSchema. Regclass literals,
+// e.g., 'public.foo_id_seq'::regclass, are also unqualified.
+func unqualifyPublicNames(sql string) string {
+	var sb strings.Builder
+	for i := 0; i < len(sql); i++ {
+		switch {
+		case sql[i] == '\'':
+			end := findQuoteEnd(sql, i)
+			literal := sql[i : end+1]
+			if strings.HasPrefix(sql[end+1:], "::regclass") {
+				literal = strings.Replace(literal, "'public.", "'", 1)
+			}
+			sb.WriteString(literal)
+			i = end
+		case sql[i] == '"':
+			end := findQuoteEnd(sql, i)
+			sb.WriteString(sql[i : end+1])
+			i = end
+		case strings.HasPrefix(sql[i:], "public.") && (i == 0 || !isIdentifierChar(sql[i-1])):
+			i += len("public.") - 1
+		default:
+			sb.WriteByte(sql[i])
+		}
+	}
+	return sb.String()
+}
+
+// findQuoteEnd finds the index of the quote that closes the quote at start. Doubled quotes are escaped quotes.
+func findQuoteEnd(s string, start int) int {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		if s[i] != quote {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(s) - 1
+}
+
+// findParenthesizedEnd finds the index of the parenthesis that closes the parenthesis at start. It returns -1 if
+// there is no closing parenthesis.
+func findParenthesizedEnd(s string, start int) int {
+	if start < 0 || start >= len(s) || s[start] != '(' {
+		return -1
+	}
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '"':
+			i = findQuoteEnd(s, i)
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// getParenthesizedContent gets the content between the parenthesis at start and its closing parenthesis
+func getParenthesizedContent(s string, start int) (string, bool) {
+	end := findParenthesizedEnd(s, start)
+	if end == -1 {
+		return "", false
+	}
+	return s[start+1 : end], true
+}
+
+// splitTopLevel splits the string on the separator, ignoring separators that are quoted or parenthesized
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, partStart := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '"':
+			i = findQuoteEnd(s, i)
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[partStart:i])
+				partStart = i + 1
+			}
+		}
+	}
+	return append(parts, s[partStart:])
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || c == '"' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// quoteIdentifierIfNeeded quotes the identifier if Postgres's quote_ident would, i.e., if it is not a lower-case
+// identifier or is a keyword
+func quoteIdentifierIfNeeded(name string) string {
+	needsQuotes := len(name) == 0 || (name[0] >= '0' && name[0] <= '9') || sqlKeywordsRequiringQuotes[name]
+	for i := 0; i < len(name) && !needsQuotes; i++ {
+		c := name[i]
+		needsQuotes = !(c == '_' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9'))
+	}
+	if !needsQuotes {
+		return name
+	}
+	return EscapeIdentifier(strings.ReplaceAll(name, `"`, `""`))
+}
+
+// unescapeIdentifier reverses EscapeIdentifier
+func unescapeIdentifier(escapedName string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(escapedName, `"`), `"`)
+}
+
+func buildNameFromRangeVar(rangeVar *pg_query.RangeVar) SchemaQualifiedName {
+	return buildNameFromUnescaped(rangeVar.Relname, rangeVar.Schemaname)
+}
+
+// buildNameFromNodes builds the name from a list of String nodes, e.g., the name of a type
+func buildNameFromNodes(nodes []*pg_query.Node) (SchemaQualifiedName, error) {
+	names := getStrings(nodes)
+	if len(names) != 2 {
+		return SchemaQualifiedName{}, fmt.Errorf("expected a schema-qualified name: %s", strings.Join(names, "."))
+	}
+	return buildNameFromUnescaped(names[1], names[0]), nil
+}
+
+func getStrings(nodes []*pg_query.Node) []string {
+	var strs []string
+	for _, node := range nodes {
+		strs = append(strs, node.GetString_().GetSval())
+	}
+	return strs
+}
+
+// getDefElemValue gets the value of an option, e.g., a storage parameter, as a string
+func getDefElemValue(defElem *pg_query.DefElem) (string, error) {
+	switch arg := defElem.GetArg().GetNode().(type) {
+	case *pg_query.Node_String_:
+		return arg.String_.Sval, nil
+	case *pg_query.Node_Integer:
+		return strconv.Itoa(int(arg.Integer.Ival)), nil
+	case *pg_query.Node_Float:
+		return arg.Float.Fval, nil
+	case *pg_query.Node_Boolean:
+		return strconv.FormatBool(arg.Boolean.Boolval), nil
+	case *pg_query.Node_TypeName:
+		return strings.Join(getStrings(arg.TypeName.Names), "."), nil
+	}
+	return "", fmt.Errorf("unsupported value")
+}
+
+func getColumn(table *Table, name string) (*Column, error) {
+	for i := range table.Columns {
+		if table.Columns[i].Name == name {
+			return &table.Columns[i], nil
+		}
+	}
+	return nil, fmt.Errorf("column %q not found in %s", name, table.GetFQEscapedName())
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,492 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/kr/pretty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/pgdump"
+	"github.com/stripe/pg-schema-diff/internal/pgengine"
+)
+
+const testDump = `--
+-- PostgreSQL database dump
+--
+
+\restrict abc123
+
+SET statement_timeout = 0;
+SET lock_timeout = 0;
+SET client_encoding = 'UTF8';
+SET standard_conforming_strings = on;
+SELECT pg_catalog.set_config('search_path', '', false);
+SET check_function_bodies = false;
+SET client_min_messages = warning;
+SET row_security = off;
+
+--
+-- Name: schema_1; Type: SCHEMA; Schema: -; Owner: postgres
+--
+
+CREATE SCHEMA schema_1;
+
+
+ALTER SCHEMA schema_1 OWNER TO postgres;
+
+--
+-- Name: pg_trgm; Type: EXTENSION; Schema: -; Owner: -
+--
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+
+--
+-- Name: EXTENSION pg_trgm; Type: COMMENT; Schema: -; Owner:
+--
+
+COMMENT ON EXTENSION pg_trgm IS 'text similarity measurement and index searching based on trigrams';
+
+
+--
+-- Name: color; Type: TYPE; Schema: public; Owner: postgres
+--
+
+CREATE TYPE public.color AS ENUM (
+    'red',
+    'green'
+);
+
+
+ALTER TYPE public.color OWNER TO postgres;
+
+SET default_tablespace = '';
+
+SET default_table_access_method = heap;
+
+--
+-- Name: foobar; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.foobar (
+    id integer NOT NULL,
+    val character varying(255) COLLATE pg_catalog."C" DEFAULT 'some, value'::character varying,
+    color public.color DEFAULT 'red'::public.color NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    tags text[],
+    "User" text,
+    doubled integer GENERATED ALWAYS AS ((id * 2)) STORED,
+    CONSTRAINT foobar_val_check CHECK ((length((val)::text) > 0))
+)
+WITH (fillfactor='70');
+
+
+ALTER TABLE public.foobar OWNER TO postgres;
+
+--
+-- Name: foobar_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE public.foobar_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER SEQUENCE public.foobar_id_seq OWNER TO postgres;
+
+--
+-- Name: foobar_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE public.foobar_id_seq OWNED BY public.foobar.id;
+
+
+--
+-- Name: bar; Type: TABLE; Schema: schema_1; Owner: postgres
+--
+
+CREATE TABLE schema_1.bar (
+    id bigint NOT NULL,
+    foobar_id integer,
+    data jsonb
+);
+
+
+--
+-- Name: bar_id_seq; Type: SEQUENCE; Schema: schema_1; Owner: postgres
+--
+
+ALTER TABLE schema_1.bar ALTER COLUMN id ADD GENERATED ALWAYS AS IDENTITY (
+    SEQUENCE NAME schema_1.bar_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+
+--
+-- Name: foobar_view; Type: VIEW; Schema: public; Owner: postgres
+--
+
+CREATE VIEW public.foobar_view AS
+ SELECT foobar.id,
+    foobar.val
+   FROM public.foobar;
+
+
+--
+-- Name: foobar id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.foobar ALTER COLUMN id SET DEFAULT nextval('public.foobar_id_seq'::regclass);
+
+
+--
+-- Name: foobar foobar_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.foobar
+    ADD CONSTRAINT foobar_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: bar bar_pkey; Type: CONSTRAINT; Schema: schema_1; Owner: postgres
+--
+
+ALTER TABLE ONLY schema_1.bar
+    ADD CONSTRAINT bar_pkey PRIMARY KEY (id) INCLUDE (foobar_id);
+
+
+--
+-- Name: foobar_val_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX foobar_val_idx ON public.foobar USING btree (val) WHERE ((val)::text <> ''::text);
+
+
+--
+-- Name: foobar_lower_val_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX foobar_lower_val_idx ON public.foobar USING btree (lower((val)::text), id);
+
+
+--
+-- Name: bar_data_idx; Type: INDEX; Schema: schema_1; Owner: postgres
+--
+
+CREATE INDEX bar_data_idx ON schema_1.bar USING gin (data jsonb_path_ops);
+
+
+--
+-- Name: bar bar_foobar_id_fkey; Type: FK CONSTRAINT; Schema: schema_1; Owner: postgres
+--
+
+ALTER TABLE ONLY schema_1.bar
+    ADD CONSTRAINT bar_foobar_id_fkey FOREIGN KEY (foobar_id) REFERENCES public.foobar(id) DEFERRABLE NOT VALID;
+
+
+--
+-- Name: TABLE foobar; Type: COMMENT; Schema: public; Owner: postgres
+--
+
+COMMENT ON TABLE public.foobar IS 'The foobar''s table';
+
+
+--
+-- Name: COLUMN foobar.val; Type: COMMENT; Schema: public; Owner: postgres
+--
+
+COMMENT ON COLUMN public.foobar.val IS 'The foobar''s value';
+
+
+--
+-- PostgreSQL database dump complete
+--
+
+\unrestrict abc123
+
+`
+
+func TestParseDump(t *testing.T) {
+	foobar := SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+	bar := SchemaQualifiedName{SchemaName: "schema_1", EscapedName: `"bar"`}
+	cCollation := SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: `"C"`}
+	tableComment := "The foobar's table"
+	columnComment := "The foobar's value"
+
+	schema, err := ParseDump(strings.NewReader(testDump))
+	require.NoError(t, err)
+
+	expected := Schema{
+		NamedSchemas: []NamedSchema{{Name: "public"}, {Name: "schema_1"}},
+		Extensions: []Extension{{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: `"pg_trgm"`},
+		}},
+		Enums: []Enum{{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: `"color"`},
+			Labels:              []string{"red", "green"},
+		}},
+		Tables: []Table{
+			{
+				SchemaQualifiedName: foobar,
+				Columns: []Column{
+					{Name: "id", Type: "integer", Default: "nextval('foobar_id_seq'::regclass)", IsDefaultVolatile: true, Size: 4},
+					{Name: "val", Type: "character varying(255)", Collation: cCollation, Default: "'some, value'::character varying", IsNullable: true, Size: -1, Comment: &columnComment},
+					{Name: "color", Type: "color", Default: "'red'::color", Size: 4},
+					{Name: "created_at", Type: "timestamp with time zone", Default: "CURRENT_TIMESTAMP", Size: 8},
+					{Name: "tags", Type: "text[]", Collation: defaultCollation, IsNullable: true, Size: -1},
+					{Name: "User", Type: "text", Collation: defaultCollation, IsNullable: true, Size: -1},
+					{Name: "doubled", Type: "integer", IsNullable: true, Size: 4, IsGenerated: true, GenerationExpression: "(id * 2)"},
+				},
+				CheckConstraints: []CheckConstraint{{
+					Name:          "foobar_val_check",
+					KeyColumns:    []string{"val"},
+					Expression:    "(length((val)::text) > 0)",
+					IsValid:       true,
+					IsInheritable: true,
+				}},
+				ReplicaIdentity:   ReplicaIdentityDefault,
+				StorageParameters: map[string]string{"fillfactor": "70"},
+				Comment:           &tableComment,
+			},
+			{
+				SchemaQualifiedName: bar,
+				Columns: []Column{
+					{Name: "id", Type: "bigint", Size: 8, Identity: &ColumnIdentity{
+						Type:       ColumnIdentityTypeAlways,
+						StartValue: 1,
+						Increment:  1,
+						MaxValue:   9223372036854775807,
+						MinValue:   1,
+						CacheSize:  1,
+					}},
+					{Name: "foobar_id", Type: "integer", IsNullable: true, Size: 4},
+					{Name: "data", Type: "jsonb", IsNullable: true, Size: -1},
+				},
+				ReplicaIdentity: ReplicaIdentityDefault,
+			},
+		},
+		Views: []View{{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_view"`},
+			Definition:          " SELECT foobar.id,\n    foobar.val\n   FROM foobar;",
+			DependsOnTables:     []SchemaQualifiedName{foobar},
+		}},
+		Indexes: []Index{
+			{
+				Name:        "foobar_pkey",
+				OwningTable: foobar,
+				Columns:     []string{"id"},
+				IsUnique:    true,
+				Method:      "btree",
+				Constraint: &IndexConstraint{
+					Type:                  PkIndexConstraintType,
+					EscapedConstraintName: `"foobar_pkey"`,
+					ConstraintDef:         "PRIMARY KEY (id)",
+					IsLocal:               true,
+				},
+				GetIndexDefStmt: "CREATE UNIQUE INDEX foobar_pkey ON public.foobar USING btree (id)",
+			},
+			{
+				Name:            "bar_pkey",
+				OwningTable:     bar,
+				Columns:         []string{"id"},
+				IncludedColumns: []string{"foobar_id"},
+				IsUnique:        true,
+				Method:          "btree",
+				Constraint: &IndexConstraint{
+					Type:                  PkIndexConstraintType,
+					EscapedConstraintName: `"bar_pkey"`,
+					ConstraintDef:         "PRIMARY KEY (id) INCLUDE (foobar_id)",
+					IsLocal:               true,
+				},
+				GetIndexDefStmt: "CREATE UNIQUE INDEX bar_pkey ON schema_1.bar USING btree (id) INCLUDE (foobar_id)",
+			},
+			{
+				Name:            "foobar_val_idx",
+				OwningTable:     foobar,
+				Columns:         []string{"val"},
+				Predicate:       "((val)::text <> ''::text)",
+				Method:          "btree",
+				GetIndexDefStmt: "CREATE INDEX foobar_val_idx ON public.foobar USING btree (val) WHERE ((val)::text <> ''::text)",
+			},
+			{
+				Name:            "foobar_lower_val_idx",
+				OwningTable:     foobar,
+				Columns:         []string{"id"},
+				Expressions:     []string{"lower((val)::text)"},
+				IsUnique:        true,
+				Method:          "btree",
+				GetIndexDefStmt: "CREATE UNIQUE INDEX foobar_lower_val_idx ON public.foobar USING btree (lower((val)::text), id)",
+			},
+			{
+				Name:            "bar_data_idx",
+				OwningTable:     bar,
+				Columns:         []string{"data"},
+				Method:          "gin",
+				GetIndexDefStmt: "CREATE INDEX bar_data_idx ON schema_1.bar USING gin (data jsonb_path_ops)",
+			},
+		},
+		ForeignKeyConstraints: []ForeignKeyConstraint{{
+			EscapedName:   `"bar_foobar_id_fkey"`,
+			OwningTable:   bar,
+			ForeignTable:  foobar,
+			ConstraintDef: "FOREIGN KEY (foobar_id) REFERENCES foobar(id) DEFERRABLE NOT VALID",
+			Deferrable:    true,
+		}},
+		Sequences: []Sequence{{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_id_seq"`},
+			Owner:               &SequenceOwner{TableName: foobar, ColumnName: "id"},
+			Type:                "integer",
+			StartValue:          1,
+			Increment:           1,
+			MaxValue:            2147483647,
+			MinValue:            1,
+			CacheSize:           1,
+		}},
+	}
+	assert.Equal(t, expected.Normalize(), schema.Normalize(), "expected=\n%# v \n parsed=%# v\n", pretty.Formatter(expected.Normalize()), pretty.Formatter(schema.Normalize()))
+}
+
+func TestParseDump_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		dump                string
+		expectedErrContains string
+	}{
+		{
+			name: "Unsupported object",
+			dump: `
+SET statement_timeout = 0;
+
+CREATE FUNCTION public.add(a integer, b integer) RETURNS integer
+    LANGUAGE sql IMMUTABLE
+    RETURN (a + b);
+`,
+			expectedErrContains: "line 4: unsupported statement: CREATE FUNCTION public.add(a integer, b integer) RETURNS integer",
+		},
+		{
+			name: "Privileges",
+			dump: `
+CREATE TABLE public.foobar (
+    id integer
+);
+GRANT SELECT ON TABLE public.foobar TO PUBLIC;
+`,
+			expectedErrContains: "unsupported statement: GRANT SELECT",
+		},
+		{
+			name: "Partitioned table",
+			dump: `
+CREATE TABLE public.foobar (
+    id integer
+)
+PARTITION BY RANGE (id);
+`,
+			expectedErrContains: "partitioned, inherited, and typed tables are not supported",
+		},
+		{
+			name: "Index on an unknown table",
+			dump: `
+CREATE INDEX foobar_idx ON public.foobar USING btree (id);
+`,
+			expectedErrContains: "unsupported statement: CREATE INDEX foobar_idx",
+		},
+		{
+			name:                "Invalid SQL",
+			dump:                `CREATE TABLE public.foobar (`,
+			expectedErrContains: "parsing dump",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseDump(strings.NewReader(tc.dump))
+			require.ErrorContains(t, err, tc.expectedErrContains)
+		})
+	}
+}
+
+func TestUnqualifyPublicNames(t *testing.T) {
+	for _, tc := range []struct {
+		sql      string
+		expected string
+	}{
+		{sql: "nextval('public.foobar_id_seq'::regclass)", expected: "nextval('foobar_id_seq'::regclass)"},
+		{sql: "nextval('schema_1.foobar_id_seq'::regclass)", expected: "nextval('schema_1.foobar_id_seq'::regclass)"},
+		{sql: "'public.value'::text", expected: "'public.value'::text"},
+		{sql: "'red'::public.color", expected: "'red'::color"},
+		{sql: `SELECT "public.foo".id FROM public.foo "public.foo"`, expected: `SELECT "public.foo".id FROM foo "public.foo"`},
+		{sql: "SELECT not_public.id FROM not_public.foo", expected: "SELECT not_public.id FROM not_public.foo"},
+	} {
+		t.Run(tc.sql, func(t *testing.T) {
+			assert.Equal(t, tc.expected, unqualifyPublicNames(tc.sql))
+		})
+	}
+}
+
+func TestParseDumpMatchesGetSchema(t *testing.T) {
+	engine, err := pgengine.StartEngine()
+	require.NoError(t, err)
+	defer engine.Close()
+
+	db, err := engine.CreateDatabase()
+	require.NoError(t, err)
+	defer db.DropDB()
+
+	connPool, err := sql.Open("pgx", db.GetDSN())
+	require.NoError(t, err)
+	defer connPool.Close()
+
+	_, err = connPool.ExecContext(context.Background(), `
+		CREATE EXTENSION pg_trgm;
+		CREATE SCHEMA schema_1;
+		CREATE TYPE color AS ENUM ('red', 'green');
+
+		CREATE TABLE foobar(
+			id SERIAL PRIMARY KEY,
+			val VARCHAR(255) COLLATE "C" DEFAULT 'some value' CHECK (LENGTH(val) > 0),
+			color color NOT NULL DEFAULT 'red',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			tags TEXT[],
+			"User" TEXT,
+			doubled INT GENERATED ALWAYS AS (id * 2) STORED
+		) WITH (fillfactor = 70);
+		CREATE INDEX foobar_val_idx ON foobar(val) WHERE val != '';
+		CREATE UNIQUE INDEX foobar_lower_val_idx ON foobar(LOWER(val), id);
+		CREATE VIEW foobar_view AS SELECT id, val FROM foobar;
+		COMMENT ON TABLE foobar IS 'The foobar''s table';
+		COMMENT ON COLUMN foobar.val IS 'The foobar''s value';
+
+		CREATE TABLE schema_1.bar(
+			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			foobar_id INT,
+			data JSONB
+		);
+		ALTER TABLE schema_1.bar ADD CONSTRAINT bar_foobar_id_fkey FOREIGN KEY (foobar_id) REFERENCES foobar(id) NOT VALID;
+		CREATE INDEX bar_data_idx ON schema_1.bar USING gin (data jsonb_path_ops);
+		CREATE SEQUENCE schema_1.descending_seq AS SMALLINT INCREMENT BY -1 CACHE 5 CYCLE;
+	`)
+	require.NoError(t, err)
+
+	fetchedSchema, err := GetSchema(context.Background(), connPool)
+	require.NoError(t, err)
+	// pg_dump does not include the versions of extensions
+	for i := range fetchedSchema.Extensions {
+		fetchedSchema.Extensions[i].Version = ""
+	}
+
+	dump, err := pgdump.GetDump(db, pgdump.WithSchemaOnly())
+	require.NoError(t, err)
+	parsedSchema, err := ParseDump(strings.NewReader(dump))
+	require.NoError(t, err)
+
+	assert.Equal(t, fetchedSchema.Normalize(), parsedSchema.Normalize(), "fetched=\n%# v \n parsed=%# v\n", pretty.Formatter(fetchedSchema.Normalize()), pretty.Formatter(parsedSchema.Normalize()))
+}
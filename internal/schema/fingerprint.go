@@ -0,0 +1,27 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Fingerprint computes a stable SHA-256 hash of the normalized schema. Unlike Hash, the fingerprint is stable across
+// versions of the hashing library and processes, so it can be persisted, e.g., to detect whether a database has drifted
+// from the schema it was last migrated to without computing a full diff.
+//
+// The fingerprint only depends on the objects in the schema, not the order they were fetched in. The owners of objects,
+// e.g., Table.Owner, are diffed, so they contribute to the fingerprint if they were fetched. ObjectOwners does not
+// contribute: it is only fetched for WithReassignOwned and summarizes the owners of the objects rather than describing
+// an object, so including it would make the fingerprint depend on the fetch options instead of the schema.
+func Fingerprint(s Schema) (string, error) {
+	s = s.Normalize()
+	s.ObjectOwners = nil
+	schemaJSON, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("marshalling schema: %w", err)
+	}
+	fingerprint := sha256.Sum256(schemaJSON)
+	return hex.EncodeToString(fingerprint[:]), nil
+}
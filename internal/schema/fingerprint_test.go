@@ -0,0 +1,279 @@
+package schema
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fingerprintTestSchema = Schema{
+	NamedSchemas: []NamedSchema{{Name: "public"}, {Name: "schema_1"}, {Name: "schema_2"}},
+	Extensions: []Extension{
+		{SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"pg_trgm\""}, Version: "1.6"},
+		{SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"citext\""}, Version: "1.6"},
+	},
+	Enums: []Enum{
+		{SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"color\""}, Labels: []string{"red", "green"}},
+		{SchemaQualifiedName: SchemaQualifiedName{SchemaName: "schema_1", EscapedName: "\"size\""}, Labels: []string{"s", "m", "l"}},
+	},
+	Domains: []Domain{
+		{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"positive_int\""},
+			BaseType:            "integer",
+			Constraints: []DomainConstraint{
+				{Name: "positive", Check: "CHECK ((VALUE > 0))"},
+				{Name: "small", Check: "CHECK ((VALUE < 100))"},
+			},
+		},
+		{SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"email\""}, BaseType: "text"},
+	},
+	Tables: []Table{
+		{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+			Columns: []Column{
+				{Name: "id", Type: "integer", Size: 4},
+				{Name: "content", Type: "text", Size: -1, IsNullable: true},
+			},
+			CheckConstraints: []CheckConstraint{
+				{Name: "id_check", Expression: "(id > 0)", IsValid: true, KeyColumns: []string{"id", "content"}},
+				{Name: "content_check", Expression: "(length(content) > 0)", IsValid: true, KeyColumns: []string{"content"}},
+			},
+			Policies: []Policy{
+				{EscapedName: "\"policy_1\"", AppliesTo: []string{"role_1", "PUBLIC"}, Cmd: AllPolicyCmd, Columns: []string{"id", "content"}},
+				{EscapedName: "\"policy_2\"", AppliesTo: []string{"PUBLIC"}, Cmd: SelectPolicyCmd},
+			},
+			ReplicaIdentity:   ReplicaIdentityDefault,
+			StorageParameters: map[string]string{"fillfactor": "70", "autovacuum_enabled": "false"},
+		},
+		{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "schema_1", EscapedName: "\"bar\""},
+			Columns:             []Column{{Name: "id", Type: "integer", Size: 4}},
+			ReplicaIdentity:     ReplicaIdentityDefault,
+		},
+	},
+	Views: []View{
+		{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_bar\""},
+			Definition:          " SELECT foo.id FROM foo JOIN schema_1.bar USING (id);",
+			DependsOnTables: []SchemaQualifiedName{
+				{SchemaName: "public", EscapedName: "\"foo\""},
+				{SchemaName: "schema_1", EscapedName: "\"bar\""},
+			},
+		},
+		{SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_ids\""}, Definition: " SELECT id FROM foo;"},
+	},
+	Indexes: []Index{
+		{Name: "foo_pkey", OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}, Columns: []string{"id"}, IsUnique: true},
+		{Name: "foo_content_idx", OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}, Columns: []string{"content", "id"}},
+		{Name: "bar_pkey", OwningTable: SchemaQualifiedName{SchemaName: "schema_1", EscapedName: "\"bar\""}, Columns: []string{"id"}, IsUnique: true},
+	},
+	ForeignKeyConstraints: []ForeignKeyConstraint{
+		{
+			EscapedName:   "\"bar_id_fk\"",
+			OwningTable:   SchemaQualifiedName{SchemaName: "schema_1", EscapedName: "\"bar\""},
+			ForeignTable:  SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+			ConstraintDef: "FOREIGN KEY (id) REFERENCES foo(id)",
+			IsValid:       true,
+		},
+	},
+	Sequences: []Sequence{
+		{SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_seq\""}, Type: "bigint", Increment: 1},
+		{SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"bar_seq\""}, Type: "bigint", Increment: 2},
+	},
+	Functions: []Function{
+		{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"add\"(a integer, b integer)"},
+			FunctionDef:         "CREATE OR REPLACE FUNCTION add(a integer, b integer) ...",
+			Language:            "sql",
+		},
+		{
+			SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_count\"()"},
+			FunctionDef:         "CREATE OR REPLACE FUNCTION foo_count() ...",
+			Language:            "sql",
+			DependsOnFunctions: []SchemaQualifiedName{
+				{SchemaName: "public", EscapedName: "\"add\"(a integer, b integer)"},
+				{SchemaName: "public", EscapedName: "\"increment\"(i integer)"},
+			},
+			DependsOnTables: []SchemaQualifiedName{
+				{SchemaName: "public", EscapedName: "\"foo\""},
+				{SchemaName: "schema_1", EscapedName: "\"bar\""},
+			},
+			ReferencedColumns: []TableColumnRef{
+				{TableName: "foo", ColumnName: "id"},
+				{TableName: "bar", ColumnName: "id"},
+				{TableName: "foo", ColumnName: "content"},
+			},
+		},
+	},
+	Triggers: []Trigger{
+		{EscapedName: "\"trigger_1\"", OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}},
+		{EscapedName: "\"trigger_2\"", OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}},
+	},
+	EventTriggers: []EventTrigger{
+		{Name: "event_trigger_1", Event: "ddl_command_start", Tags: []string{"CREATE TABLE", "ALTER TABLE", "DROP TABLE"}},
+		{Name: "event_trigger_2", Event: "sql_drop"},
+	},
+	Publications: []Publication{
+		{
+			Name:    "pub_1",
+			Tables:  []SchemaQualifiedName{{SchemaName: "public", EscapedName: "\"foo\""}, {SchemaName: "schema_1", EscapedName: "\"bar\""}},
+			Publish: []string{"insert", "update", "delete"},
+		},
+		{Name: "pub_2", AllTables: true, Publish: []string{"insert"}},
+	},
+	Privileges: []Privilege{
+		{ObjectType: "TABLE", Object: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}, Grantee: "role_1", Type: "SELECT"},
+		{ObjectType: "TABLE", Object: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}, Grantee: "role_1", Type: "INSERT"},
+		{ObjectType: "TABLE", Object: SchemaQualifiedName{SchemaName: "schema_1", EscapedName: "\"bar\""}, Grantee: PrivilegeGranteePublic, Type: "SELECT"},
+	},
+	ObjectOwners: []string{"owner_1", "owner_2"},
+}
+
+func shuffle[T any](r *rand.Rand, vals []T) []T {
+	if vals == nil {
+		return nil
+	}
+	shuffled := make([]T, len(vals))
+	copy(shuffled, vals)
+	r.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+// permuteSchema permutes the order of the schema's objects and of any nested lists whose order is not significant,
+// e.g., a view's dependencies. Lists whose order is significant, e.g., a table's columns, are not permuted.
+func permuteSchema(r *rand.Rand, s Schema) Schema {
+	s.NamedSchemas = shuffle(r, s.NamedSchemas)
+	s.Extensions = shuffle(r, s.Extensions)
+	s.Enums = shuffle(r, s.Enums)
+
+	var domains []Domain
+	for _, d := range s.Domains {
+		d.Constraints = shuffle(r, d.Constraints)
+		domains = append(domains, d)
+	}
+	s.Domains = shuffle(r, domains)
+
+	var tables []Table
+	for _, t := range s.Tables {
+		var checkConstraints []CheckConstraint
+		for _, cc := range t.CheckConstraints {
+			cc.KeyColumns = shuffle(r, cc.KeyColumns)
+			checkConstraints = append(checkConstraints, cc)
+		}
+		t.CheckConstraints = shuffle(r, checkConstraints)
+
+		var policies []Policy
+		for _, p := range t.Policies {
+			p.AppliesTo = shuffle(r, p.AppliesTo)
+			p.Columns = shuffle(r, p.Columns)
+			policies = append(policies, p)
+		}
+		t.Policies = shuffle(r, policies)
+		tables = append(tables, t)
+	}
+	s.Tables = shuffle(r, tables)
+
+	var views []View
+	for _, v := range s.Views {
+		v.DependsOnTables = shuffle(r, v.DependsOnTables)
+		v.DependsOnViews = shuffle(r, v.DependsOnViews)
+		views = append(views, v)
+	}
+	s.Views = shuffle(r, views)
+
+	s.Indexes = shuffle(r, s.Indexes)
+	s.ForeignKeyConstraints = shuffle(r, s.ForeignKeyConstraints)
+	s.Sequences = shuffle(r, s.Sequences)
+
+	var functions []Function
+	for _, f := range s.Functions {
+		f.DependsOnFunctions = shuffle(r, f.DependsOnFunctions)
+		f.DependsOnTables = shuffle(r, f.DependsOnTables)
+		f.ReferencedColumns = shuffle(r, f.ReferencedColumns)
+		functions = append(functions, f)
+	}
+	s.Functions = shuffle(r, functions)
+
+	s.Triggers = shuffle(r, s.Triggers)
+
+	var eventTriggers []EventTrigger
+	for _, et := range s.EventTriggers {
+		et.Tags = shuffle(r, et.Tags)
+		eventTriggers = append(eventTriggers, et)
+	}
+	s.EventTriggers = shuffle(r, eventTriggers)
+
+	var publications []Publication
+	for _, p := range s.Publications {
+		p.Tables = shuffle(r, p.Tables)
+		p.Publish = shuffle(r, p.Publish)
+		publications = append(publications, p)
+	}
+	s.Publications = shuffle(r, publications)
+
+	s.Privileges = shuffle(r, s.Privileges)
+	s.ObjectOwners = shuffle(r, s.ObjectOwners)
+	return s
+}
+
+func FuzzFingerprint(f *testing.F) {
+	for _, seed := range []int64{0, 1, 2, 42, 1337} {
+		f.Add(seed)
+	}
+
+	expectedFingerprint, err := Fingerprint(fingerprintTestSchema)
+	require.NoError(f, err)
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		fingerprint, err := Fingerprint(permuteSchema(rand.New(rand.NewSource(seed)), fingerprintTestSchema))
+		require.NoError(t, err)
+		assert.Equal(t, expectedFingerprint, fingerprint)
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint, err := Fingerprint(fingerprintTestSchema)
+	require.NoError(t, err)
+	assert.Len(t, fingerprint, 64)
+
+	// The roles that own objects are not diffed
+	withoutOwners := fingerprintTestSchema
+	withoutOwners.ObjectOwners = nil
+	withoutOwnersFingerprint, err := Fingerprint(withoutOwners)
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, withoutOwnersFingerprint)
+
+	// Any change to an object changes the fingerprint
+	changedSchema := fingerprintTestSchema
+	changedSchema.Sequences = []Sequence{fingerprintTestSchema.Sequences[0]}
+	changedFingerprint, err := Fingerprint(changedSchema)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint, changedFingerprint)
+
+	changedSchema = fingerprintTestSchema
+	changedSchema.Tables = append([]Table(nil), fingerprintTestSchema.Tables...)
+	changedSchema.Tables[1].Columns = []Column{{Name: "id", Type: "bigint", Size: 8}}
+	changedFingerprint, err = Fingerprint(changedSchema)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint, changedFingerprint)
+
+	// The owner of an object is diffed
+	changedSchema = fingerprintTestSchema
+	changedSchema.Tables = append([]Table(nil), fingerprintTestSchema.Tables...)
+	changedSchema.Tables[0].Owner = "some_other_role"
+	changedFingerprint, err = Fingerprint(changedSchema)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint, changedFingerprint)
+
+	// The order of columns is significant, e.g., for data packing
+	changedSchema = fingerprintTestSchema
+	changedSchema.Tables = append([]Table(nil), fingerprintTestSchema.Tables...)
+	changedSchema.Tables[0].Columns = []Column{fingerprintTestSchema.Tables[0].Columns[1], fingerprintTestSchema.Tables[0].Columns[0]}
+	changedFingerprint, err = Fingerprint(changedSchema)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint, changedFingerprint)
+}
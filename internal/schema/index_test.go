@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexNormalize(t *testing.T) {
+	foobar := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	tests := []struct {
+		name     string
+		indexes  []Index
+		expected []Index
+	}{
+		{
+			name: "Sort indexes alphabetically",
+			indexes: []Index{
+				{Name: "some_idx_b", OwningTable: foobar, Columns: []string{"bar"}},
+				{Name: "some_idx_a", OwningTable: foobar, Columns: []string{"foo"}},
+			},
+			expected: []Index{
+				{Name: "some_idx_a", OwningTable: foobar, Columns: []string{"foo"}},
+				{Name: "some_idx_b", OwningTable: foobar, Columns: []string{"bar"}},
+			},
+		},
+		{
+			name: "Collapse whitespace in expressions",
+			indexes: []Index{
+				{
+					Name:        "some_idx",
+					OwningTable: foobar,
+					Expressions: []string{" lower(email)\n", "COALESCE(name,\t\t'')", "(id  +  1)"},
+				},
+			},
+			expected: []Index{
+				{
+					Name:        "some_idx",
+					OwningTable: foobar,
+					Expressions: []string{"lower(email)", "COALESCE(name, '')", "(id + 1)"},
+				},
+			},
+		},
+		{
+			name: "Preserve whitespace in string literals and quoted identifiers",
+			indexes: []Index{
+				{
+					Name:        "some_idx",
+					OwningTable: foobar,
+					Expressions: []string{"(\"some  col\" ||  'a  b')"},
+				},
+			},
+			expected: []Index{
+				{
+					Name:        "some_idx",
+					OwningTable: foobar,
+					Expressions: []string{"(\"some  col\" || 'a  b')"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := Schema{Indexes: tt.indexes}
+			normalized := schema.Normalize()
+			assert.Equal(t, tt.expected, normalized.Indexes)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveIndexesToMaterializedViews(t *testing.T) {
+	table := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	mv := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar_mv\""}
+	otherMv := SchemaQualifiedName{SchemaName: "schema_1", EscapedName: "\"foobar_mv\""}
+
+	tableIdx := Index{Name: "foobar_idx", OwningTable: table}
+	mvIdx := Index{Name: "foobar_mv_idx", OwningTable: mv, IsUnique: true}
+	otherMvIdx := Index{Name: "foobar_mv_idx", OwningTable: otherMv}
+
+	materializedViews, indexes := moveIndexesToMaterializedViews(
+		[]MaterializedView{{SchemaQualifiedName: mv}, {SchemaQualifiedName: otherMv}},
+		[]Index{mvIdx, tableIdx, otherMvIdx},
+	)
+	assert.Equal(t, []MaterializedView{
+		{SchemaQualifiedName: mv, Indexes: []Index{mvIdx}},
+		{SchemaQualifiedName: otherMv, Indexes: []Index{otherMvIdx}},
+	}, materializedViews)
+	assert.Equal(t, []Index{tableIdx}, indexes)
+	assert.True(t, materializedViews[0].HasUniqueIndex())
+	assert.False(t, materializedViews[1].HasUniqueIndex())
+}
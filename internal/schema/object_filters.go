@@ -0,0 +1,238 @@
+package schema
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// WithIncludeObjects filters the schema to only include the objects matching any of the given glob patterns. This unions
+// with any patterns that are already included via WithIncludeObjects. If empty, then all objects are included.
+//
+// Patterns are matched against "<schema>.<name>" using path.Match syntax, e.g., "public.orders_*" or "*.audit_log".
+// The name is unescaped and, for functions and procedures, excludes the arguments.
+//
+// Tables, foreign tables, views, materialized views, sequences, functions, procedures, enums, domains, and composite
+// types are filtered. The indexes, foreign keys, triggers, statistics objects, and privileges of a filtered out object
+// are also filtered out. Other objects, e.g., extensions, are not filtered by object patterns.
+func WithIncludeObjects(patterns ...string) GetSchemaOpt {
+	return func(o *getSchemaOptions) {
+		o.includeObjects = append(o.includeObjects, patterns...)
+	}
+}
+
+// WithExcludeObjects filters the schema to exclude the objects matching any of the given glob patterns. Exclusions take
+// precedence over inclusions. See WithIncludeObjects for the pattern syntax and the objects that are filtered.
+func WithExcludeObjects(patterns ...string) GetSchemaOpt {
+	return func(o *getSchemaOptions) {
+		o.excludeObjects = append(o.excludeObjects, patterns...)
+	}
+}
+
+// objectFilter filters objects by glob patterns matched against their schema and unescaped name
+type objectFilter struct {
+	includePatterns []string
+	excludePatterns []string
+}
+
+func buildObjectFilter(options getSchemaOptions) (*objectFilter, error) {
+	if len(options.includeObjects) == 0 && len(options.excludeObjects) == 0 {
+		return nil, nil
+	}
+	for _, pattern := range append(append([]string(nil), options.includeObjects...), options.excludeObjects...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid object pattern %q: %w", pattern, err)
+		}
+	}
+	return &objectFilter{
+		includePatterns: options.includeObjects,
+		excludePatterns: options.excludeObjects,
+	}, nil
+}
+
+func (f *objectFilter) matches(name SchemaQualifiedName) bool {
+	objName := name.SchemaName + "." + unescapedObjectName(name)
+	if matchesAnyPattern(f.excludePatterns, objName) {
+		return false
+	}
+	return len(f.includePatterns) == 0 || matchesAnyPattern(f.includePatterns, objName)
+}
+
+func matchesAnyPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		// The patterns are validated when the filter is built, so the error can be ignored
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// unescapedObjectName gets the unescaped name of the object, e.g., foo for "foo" and "foo"(integer)
+func unescapedObjectName(name SchemaQualifiedName) string {
+	escapedName := name.EscapedName
+	if strings.HasPrefix(escapedName, `"`) {
+		if end := strings.Index(escapedName[1:], `"`); end >= 0 {
+			return escapedName[1 : end+1]
+		}
+	}
+	if end := strings.Index(escapedName, "("); end >= 0 {
+		return escapedName[:end]
+	}
+	return escapedName
+}
+
+// filterSchemaObjects removes the objects that do not match the filter from the schema, such that they are treated as
+// if they do not exist. An error is returned if an object that is kept depends on an object that is filtered out, since
+// the migration of the kept object could not be planned correctly.
+func (f *objectFilter) filterSchemaObjects(s Schema) (Schema, error) {
+	// filteredOut contains the names of the objects that are filtered out, keyed by GetName()
+	filteredOut := make(map[string]bool)
+	keep := func(name SchemaQualifiedName) bool {
+		if f.matches(name) {
+			return true
+		}
+		filteredOut[name.GetName()] = true
+		return false
+	}
+
+	allSequences := s.Sequences
+	s.Tables = filterSlice(s.Tables, func(t Table) bool { return keep(t.SchemaQualifiedName) })
+	s.ForeignTables = filterSlice(s.ForeignTables, func(t ForeignTable) bool { return keep(t.SchemaQualifiedName) })
+	s.Views = filterSlice(s.Views, func(v View) bool { return keep(v.SchemaQualifiedName) })
+	s.MaterializedViews = filterSlice(s.MaterializedViews, func(mv MaterializedView) bool { return keep(mv.SchemaQualifiedName) })
+	s.Sequences = filterSlice(s.Sequences, func(seq Sequence) bool { return keep(seq.SchemaQualifiedName) })
+	s.Functions = filterSlice(s.Functions, func(fn Function) bool { return keep(fn.SchemaQualifiedName) })
+	s.Procedures = filterSlice(s.Procedures, func(p Procedure) bool { return keep(p.SchemaQualifiedName) })
+	s.Aggregates = filterSlice(s.Aggregates, func(a Aggregate) bool { return keep(a.SchemaQualifiedName) })
+	s.Enums = filterSlice(s.Enums, func(e Enum) bool { return keep(e.SchemaQualifiedName) })
+	s.Domains = filterSlice(s.Domains, func(d Domain) bool { return keep(d.SchemaQualifiedName) })
+	s.CompositeTypes = filterSlice(s.CompositeTypes, func(ct CompositeType) bool { return keep(ct.SchemaQualifiedName) })
+
+	// The objects that belong to a filtered out object are also filtered out
+	isKept := func(name SchemaQualifiedName) bool {
+		return !filteredOut[name.GetName()]
+	}
+	s.Indexes = filterSlice(s.Indexes, func(idx Index) bool { return isKept(idx.OwningTable) })
+	s.ForeignKeyConstraints = filterSlice(s.ForeignKeyConstraints, func(fk ForeignKeyConstraint) bool { return isKept(fk.OwningTable) })
+	s.Triggers = filterSlice(s.Triggers, func(t Trigger) bool { return isKept(t.OwningTable) })
+	s.StatisticsObjects = filterSlice(s.StatisticsObjects, func(so StatisticsObject) bool { return isKept(so.Table) })
+	s.Privileges = filterSlice(s.Privileges, func(p Privilege) bool { return isKept(p.Object) })
+	var publications []Publication
+	for _, p := range s.Publications {
+		p.Tables = filterSlice(p.Tables, isKept)
+		publications = append(publications, p)
+	}
+	s.Publications = publications
+
+	if crossBoundaryDeps := findCrossBoundaryDependencies(s, allSequences, filteredOut); len(crossBoundaryDeps) > 0 {
+		return Schema{}, fmt.Errorf("included objects depend on objects that are filtered out: %s", strings.Join(crossBoundaryDeps, "; "))
+	}
+	return s, nil
+}
+
+// findCrossBoundaryDependencies finds the dependencies of the objects in the schema on objects that are filtered out.
+// allSequences are the sequences before filtering, since a kept table depends on the sequences it owns. It returns a
+// sorted description of each dependency.
+func findCrossBoundaryDependencies(s Schema, allSequences []Sequence, filteredOut map[string]bool) []string {
+	var crossBoundaryDeps []string
+	checkDeps := func(objType string, obj SchemaQualifiedName, depType string, deps ...SchemaQualifiedName) {
+		for _, dep := range deps {
+			if filteredOut[dep.GetName()] {
+				crossBoundaryDeps = append(crossBoundaryDeps, fmt.Sprintf("%s %s depends on %s %s", objType, obj.GetFQEscapedName(), depType, dep.GetFQEscapedName()))
+			}
+		}
+	}
+
+	for _, t := range s.Tables {
+		if t.ParentTable != nil {
+			checkDeps("partition", t.SchemaQualifiedName, "parent table", *t.ParentTable)
+		}
+		for _, cc := range t.CheckConstraints {
+			checkDeps("table", t.SchemaQualifiedName, "function", cc.DependsOnFunctions...)
+		}
+	}
+	for _, v := range s.Views {
+		checkDeps("view", v.SchemaQualifiedName, "table", v.DependsOnTables...)
+		checkDeps("view", v.SchemaQualifiedName, "view", v.DependsOnViews...)
+		checkDeps("view", v.SchemaQualifiedName, "foreign table", v.DependsOnForeignTables...)
+	}
+	for _, mv := range s.MaterializedViews {
+		checkDeps("materialized view", mv.SchemaQualifiedName, "table", mv.DependsOnTables...)
+		checkDeps("materialized view", mv.SchemaQualifiedName, "view", mv.DependsOnViews...)
+		checkDeps("materialized view", mv.SchemaQualifiedName, "materialized view", mv.DependsOnMaterializedViews...)
+	}
+	for _, fn := range s.Functions {
+		checkDeps("function", fn.SchemaQualifiedName, "function", fn.DependsOnFunctions...)
+		checkDeps("function", fn.SchemaQualifiedName, "table", fn.DependsOnTables...)
+	}
+	for _, p := range s.Procedures {
+		checkDeps("procedure", p.SchemaQualifiedName, "function", p.DependsOnFunctions...)
+	}
+	for _, agg := range s.Aggregates {
+		checkDeps("aggregate", agg.SchemaQualifiedName, "function", agg.DependsOnFunctions...)
+	}
+	for _, seq := range s.Sequences {
+		if seq.Owner != nil {
+			checkDeps("sequence", seq.SchemaQualifiedName, "owning table", seq.Owner.TableName)
+		}
+	}
+	keptTables := make(map[string]bool)
+	for _, t := range s.Tables {
+		keptTables[t.GetName()] = true
+	}
+	for _, seq := range allSequences {
+		if seq.Owner != nil && keptTables[seq.Owner.TableName.GetName()] {
+			checkDeps("table", seq.Owner.TableName, "owned sequence", seq.SchemaQualifiedName)
+		}
+	}
+	for _, fk := range s.ForeignKeyConstraints {
+		checkDeps("table", fk.OwningTable, "referenced table", fk.ForeignTable)
+	}
+	for _, t := range s.Triggers {
+		checkDeps("table", t.OwningTable, "trigger function", t.Function)
+	}
+
+	sort.Strings(crossBoundaryDeps)
+	return crossBoundaryDeps
+}
+
+func filterSlice[T any](objs []T, keep func(T) bool) []T {
+	var filteredObjs []T
+	for _, obj := range objs {
+		if keep(obj) {
+			filteredObjs = append(filteredObjs, obj)
+		}
+	}
+	return filteredObjs
+}
+
+// TrackingTableComment is the comment that marks a table as a migration tracking table, e.g., the table that migration
+// history is recorded in. Tracking tables are excluded from the schema, such that they never appear in schema diffs.
+const TrackingTableComment = "pg-schema-diff migration tracking table"
+
+// excludeTrackingTables removes the tracking tables and the objects that belong to them, e.g., their indexes, from the
+// schema.
+func excludeTrackingTables(s Schema, trackingTables []SchemaQualifiedName) (Schema, error) {
+	if len(trackingTables) == 0 {
+		return s, nil
+	}
+	var excludePatterns []string
+	for _, t := range trackingTables {
+		excludePatterns = append(excludePatterns, escapeGlobPattern(t.SchemaName+"."+unescapedObjectName(t)))
+	}
+	return (&objectFilter{excludePatterns: excludePatterns}).filterSchemaObjects(s)
+}
+
+// escapeGlobPattern escapes the special characters of path.Match, such that the pattern only matches the name itself
+func escapeGlobPattern(name string) string {
+	sb := strings.Builder{}
+	for _, r := range name {
+		if strings.ContainsRune(`\*?[`, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
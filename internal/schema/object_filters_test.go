@@ -0,0 +1,205 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectFilter_FilterSchemaObjects(t *testing.T) {
+	ordersName := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"orders\""}
+	usersName := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"users\""}
+	auditLogName := SchemaQualifiedName{SchemaName: "audit", EscapedName: "\"audit_log\""}
+	auditFnName := SchemaQualifiedName{SchemaName: "audit", EscapedName: "\"audit_fn\"()"}
+	usersViewName := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"users_view\""}
+	ordersSeqName := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"orders_id_seq\""}
+
+	fullSchema := Schema{
+		Tables: []Table{
+			{SchemaQualifiedName: ordersName},
+			{SchemaQualifiedName: usersName},
+			{SchemaQualifiedName: auditLogName},
+		},
+		Views: []View{
+			{SchemaQualifiedName: usersViewName, DependsOnTables: []SchemaQualifiedName{usersName}},
+		},
+		Indexes: []Index{
+			{Name: "orders_pkey", OwningTable: ordersName},
+			{Name: "users_pkey", OwningTable: usersName},
+		},
+		Sequences: []Sequence{
+			{SchemaQualifiedName: ordersSeqName, Owner: &SequenceOwner{TableName: ordersName, ColumnName: "id"}},
+		},
+		Functions: []Function{
+			{SchemaQualifiedName: auditFnName, DependsOnTables: []SchemaQualifiedName{auditLogName}},
+		},
+		Triggers: []Trigger{
+			{EscapedName: "\"orders_audit\"", OwningTable: ordersName, Function: auditFnName},
+		},
+		Privileges: []Privilege{
+			{ObjectType: "TABLE", Object: usersName, Grantee: "reader", Type: "SELECT"},
+		},
+		Publications: []Publication{
+			{Name: "pub", Tables: []SchemaQualifiedName{ordersName, usersName}},
+		},
+	}
+
+	for _, tc := range []struct {
+		name                string
+		opts                []GetSchemaOpt
+		expectedSchema      Schema
+		expectedErrContains []string
+	}{
+		{
+			name:           "no filters",
+			expectedSchema: fullSchema,
+		},
+		{
+			name: "include objects by glob",
+			opts: []GetSchemaOpt{WithIncludeObjects("public.users*")},
+			expectedSchema: Schema{
+				Tables: []Table{
+					{SchemaQualifiedName: usersName},
+				},
+				Views:      fullSchema.Views,
+				Indexes:    []Index{{Name: "users_pkey", OwningTable: usersName}},
+				Privileges: fullSchema.Privileges,
+				Publications: []Publication{
+					{Name: "pub", Tables: []SchemaQualifiedName{usersName}},
+				},
+			},
+		},
+		{
+			name: "exclude objects by glob across schemas",
+			opts: []GetSchemaOpt{WithExcludeObjects("audit.*", "public.users*")},
+			expectedErrContains: []string{
+				`table "public"."orders" depends on trigger function "audit"."audit_fn"()`,
+			},
+		},
+		{
+			name: "exclude takes precedence over include",
+			opts: []GetSchemaOpt{
+				WithIncludeObjects("public.*", "audit.audit_fn"),
+				WithIncludeObjects("audit.audit_log"),
+				WithExcludeObjects("public.orders*"),
+			},
+			expectedSchema: Schema{
+				Tables: []Table{
+					{SchemaQualifiedName: usersName},
+					{SchemaQualifiedName: auditLogName},
+				},
+				Views:      fullSchema.Views,
+				Indexes:    []Index{{Name: "users_pkey", OwningTable: usersName}},
+				Functions:  fullSchema.Functions,
+				Privileges: fullSchema.Privileges,
+				Publications: []Publication{
+					{Name: "pub", Tables: []SchemaQualifiedName{usersName}},
+				},
+			},
+		},
+		{
+			name: "cross-boundary dependencies",
+			opts: []GetSchemaOpt{WithExcludeObjects("public.users", "audit.audit_log", "public.orders_id_seq")},
+			expectedErrContains: []string{
+				`function "audit"."audit_fn"() depends on table "audit"."audit_log"`,
+				`table "public"."orders" depends on owned sequence "public"."orders_id_seq"`,
+				`view "public"."users_view" depends on table "public"."users"`,
+			},
+		},
+		{
+			name: "sequence depends on filtered out owning table",
+			opts: []GetSchemaOpt{WithIncludeObjects("public.orders_id_seq")},
+			expectedErrContains: []string{
+				`sequence "public"."orders_id_seq" depends on owning table "public"."orders"`,
+			},
+		},
+		{
+			name:                "invalid pattern",
+			opts:                []GetSchemaOpt{WithIncludeObjects("public.[orders")},
+			expectedErrContains: []string{`invalid object pattern "public.[orders"`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			options := getSchemaOptions{}
+			for _, opt := range tc.opts {
+				opt(&options)
+			}
+			filter, err := buildObjectFilter(options)
+			if err != nil {
+				require.NotEmpty(t, tc.expectedErrContains)
+				for _, errContains := range tc.expectedErrContains {
+					assert.ErrorContains(t, err, errContains)
+				}
+				return
+			}
+			if filter == nil {
+				assert.Equal(t, tc.expectedSchema, fullSchema)
+				return
+			}
+
+			filteredSchema, err := filter.filterSchemaObjects(fullSchema)
+			if len(tc.expectedErrContains) > 0 {
+				for _, errContains := range tc.expectedErrContains {
+					assert.ErrorContains(t, err, errContains)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSchema, filteredSchema)
+		})
+	}
+}
+
+func TestUnescapedObjectName(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		escapedName  string
+		expectedName string
+	}{
+		{name: "table", escapedName: "\"foo\"", expectedName: "foo"},
+		{name: "function", escapedName: "\"foo\"(a integer, b text)", expectedName: "foo"},
+		{name: "operator", escapedName: "===(integer, integer)", expectedName: "==="},
+		{name: "unescaped", escapedName: "foo", expectedName: "foo"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedName, unescapedObjectName(SchemaQualifiedName{SchemaName: "public", EscapedName: tc.escapedName}))
+		})
+	}
+}
+
+func TestExcludeTrackingTables(t *testing.T) {
+	trackingTableName := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"schema_migrations\""}
+	similarTableName := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"schema_migrations_2\""}
+	globTableName := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"migrations_*\""}
+	otherTableName := SchemaQualifiedName{SchemaName: "public", EscapedName: "\"migrations_foo\""}
+
+	s := Schema{
+		Tables: []Table{
+			{SchemaQualifiedName: trackingTableName},
+			{SchemaQualifiedName: similarTableName},
+			{SchemaQualifiedName: globTableName},
+			{SchemaQualifiedName: otherTableName},
+		},
+		Indexes: []Index{
+			{Name: "schema_migrations_pkey", OwningTable: trackingTableName},
+			{Name: "schema_migrations_2_pkey", OwningTable: similarTableName},
+		},
+	}
+
+	filteredSchema, err := excludeTrackingTables(s, []SchemaQualifiedName{trackingTableName, globTableName})
+	require.NoError(t, err)
+	assert.Equal(t, Schema{
+		Tables: []Table{
+			{SchemaQualifiedName: similarTableName},
+			{SchemaQualifiedName: otherTableName},
+		},
+		Indexes: []Index{
+			{Name: "schema_migrations_2_pkey", OwningTable: similarTableName},
+		},
+	}, filteredSchema)
+
+	unfilteredSchema, err := excludeTrackingTables(s, nil)
+	require.NoError(t, err)
+	assert.Equal(t, s, unfilteredSchema)
+}
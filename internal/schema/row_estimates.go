@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/internal/queries"
+)
+
+// GetTableRowEstimates gets the estimated number of rows in each table, keyed by the table's name (see
+// SchemaQualifiedName.GetName). The estimates come from pg_class.reltuples, which is only updated by VACUUM, ANALYZE, and
+// a few DDL commands, e.g., CREATE INDEX. Tables that have never been vacuumed or analyzed have no estimate and are
+// omitted.
+func GetTableRowEstimates(ctx context.Context, db queries.DBTX) (map[string]int64, error) {
+	rawEstimates, err := queries.New(db).GetTableRowEstimates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetTableRowEstimates: %w", err)
+	}
+	estimates := make(map[string]int64)
+	for _, e := range rawEstimates {
+		if e.EstimatedRows < 0 {
+			// Postgres 14+ reports -1 for tables that have never been vacuumed or analyzed
+			continue
+		}
+		estimates[SchemaQualifiedName{
+			SchemaName:  e.TableSchemaName,
+			EscapedName: EscapeIdentifier(e.TableName),
+		}.GetName()] = e.EstimatedRows
+	}
+	return estimates, nil
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/mitchellh/hashstructure/v2"
 	pg_query "github.com/pganalyze/pg_query_go/v5"
@@ -51,18 +53,40 @@ func (o SchemaQualifiedName) IsEmpty() bool {
 
 // Schema is the schema of the database, not just a single Postgres schema.
 type Schema struct {
-	NamedSchemas          []NamedSchema
-	Extensions            []Extension
-	Enums                 []Enum
-	Tables                []Table
-	Views                 []View
-	Indexes               []Index
-	ForeignKeyConstraints []ForeignKeyConstraint
-	Sequences             []Sequence
-	Functions             []Function
-	Procedures            []Procedure
-	Triggers              []Trigger
-	EventTriggers         []EventTrigger
+	NamedSchemas           []NamedSchema
+	Extensions             []Extension
+	Collations             []Collation
+	ForeignDataWrappers    []ForeignDataWrapper
+	ForeignServers         []ForeignServer
+	Enums                  []Enum
+	Domains                []Domain
+	CompositeTypes         []CompositeType
+	TextSearchDictionaries []TextSearchDictionary
+	TextSearchConfigs      []TextSearchConfig
+	Tables                 []Table
+	ForeignTables          []ForeignTable
+	Views                  []View
+	MaterializedViews      []MaterializedView
+	Indexes                []Index
+	StatisticsObjects      []StatisticsObject
+	ForeignKeyConstraints  []ForeignKeyConstraint
+	Sequences              []Sequence
+	Functions              []Function
+	Procedures             []Procedure
+	Aggregates             []Aggregate
+	Triggers               []Trigger
+	EventTriggers          []EventTrigger
+	Operators              []Operator
+	OperatorClasses        []OperatorClass
+	Publications           []Publication
+	// Privileges and DefaultPrivileges are only fetched if WithPrivileges is provided
+	Privileges        []Privilege
+	DefaultPrivileges []DefaultPrivilege
+
+	// ObjectOwners is the set of roles that own at least one object in the schema. It is only fetched if
+	// WithObjectOwners is provided. It is not diffed, so it is excluded from the hash. The owner of each object, e.g.,
+	// Table.Owner, is diffed instead.
+	ObjectOwners []string `hash:"ignore"`
 }
 
 // Normalize normalizes the schema (alphabetically sorts tables and columns in tables).
@@ -70,36 +94,89 @@ type Schema struct {
 func (s Schema) Normalize() Schema {
 	s.NamedSchemas = sortSchemaObjectsByName(s.NamedSchemas)
 	s.Extensions = sortSchemaObjectsByName(s.Extensions)
+	s.Collations = sortSchemaObjectsByName(s.Collations)
+	s.ForeignDataWrappers = sortSchemaObjectsByName(s.ForeignDataWrappers)
+	s.ForeignServers = sortSchemaObjectsByName(s.ForeignServers)
 	s.Enums = sortSchemaObjectsByName(s.Enums)
 
+	var normDomains []Domain
+	for _, d := range sortSchemaObjectsByName(s.Domains) {
+		d.Constraints = sortSchemaObjectsByName(d.Constraints)
+		normDomains = append(normDomains, d)
+	}
+	s.Domains = normDomains
+
+	s.CompositeTypes = sortSchemaObjectsByName(s.CompositeTypes)
+	s.TextSearchDictionaries = sortSchemaObjectsByName(s.TextSearchDictionaries)
+	s.TextSearchConfigs = sortSchemaObjectsByName(s.TextSearchConfigs)
+
+	s = markPrimaryKeyColumns(s)
+
 	var normTables []Table
 	for _, t := range sortSchemaObjectsByName(s.Tables) {
 		normTables = append(normTables, normalizeTable(t))
 	}
 	s.Tables = normTables
 
+	s.ForeignTables = sortSchemaObjectsByName(s.ForeignTables)
+
 	var normViews []View
 	for _, view := range sortSchemaObjectsByName(s.Views) {
 		view.DependsOnTables = sortSchemaObjectsByName(view.DependsOnTables)
 		view.DependsOnViews = sortSchemaObjectsByName(view.DependsOnViews)
+		if len(view.DependsOnForeignTables) > 0 {
+			view.DependsOnForeignTables = sortSchemaObjectsByName(view.DependsOnForeignTables)
+		}
 		normViews = append(normViews, view)
 	}
 	s.Views = normViews
 
-	s.Indexes = sortSchemaObjectsByName(s.Indexes)
+	var normMaterializedViews []MaterializedView
+	for _, mv := range sortSchemaObjectsByName(s.MaterializedViews) {
+		mv.Indexes = normalizeIndexes(mv.Indexes)
+		mv.DependsOnTables = sortSchemaObjectsByName(mv.DependsOnTables)
+		mv.DependsOnViews = sortSchemaObjectsByName(mv.DependsOnViews)
+		mv.DependsOnMaterializedViews = sortSchemaObjectsByName(mv.DependsOnMaterializedViews)
+		normMaterializedViews = append(normMaterializedViews, mv)
+	}
+	s.MaterializedViews = normMaterializedViews
+
+	s.Indexes = normalizeIndexes(s.Indexes)
+	s.StatisticsObjects = sortSchemaObjectsByName(s.StatisticsObjects)
 	s.ForeignKeyConstraints = sortSchemaObjectsByName(s.ForeignKeyConstraints)
 	s.Sequences = sortSchemaObjectsByName(s.Sequences)
 
 	var normFunctions []Function
 	for _, function := range sortSchemaObjectsByName(s.Functions) {
 		function.DependsOnFunctions = sortSchemaObjectsByName(function.DependsOnFunctions)
+		if len(function.DependsOnTables) > 0 {
+			function.DependsOnTables = sortSchemaObjectsByName(function.DependsOnTables)
+		}
+		if len(function.ReferencedColumns) > 0 {
+			function.ReferencedColumns = sortByKey(function.ReferencedColumns, func(r TableColumnRef) string {
+				return r.TableName + "." + r.ColumnName
+			})
+		}
 		normFunctions = append(normFunctions, function)
 	}
 	s.Functions = normFunctions
 
-	s.Procedures = sortSchemaObjectsByName(s.Procedures)
+	var normProcedures []Procedure
+	for _, procedure := range sortSchemaObjectsByName(s.Procedures) {
+		procedure.DependsOnFunctions = sortSchemaObjectsByName(procedure.DependsOnFunctions)
+		normProcedures = append(normProcedures, procedure)
+	}
+	s.Procedures = normProcedures
+
+	var normAggregates []Aggregate
+	for _, agg := range sortSchemaObjectsByName(s.Aggregates) {
+		agg.DependsOnFunctions = sortSchemaObjectsByName(agg.DependsOnFunctions)
+		normAggregates = append(normAggregates, agg)
+	}
+	s.Aggregates = normAggregates
+
 	s.Triggers = sortSchemaObjectsByName(s.Triggers)
-	
+
 	var normEventTriggers []EventTrigger
 	for _, et := range sortSchemaObjectsByName(s.EventTriggers) {
 		et.Tags = sortByKey(et.Tags, func(s string) string { return s })
@@ -107,6 +184,31 @@ func (s Schema) Normalize() Schema {
 	}
 	s.EventTriggers = normEventTriggers
 
+	s.Operators = sortSchemaObjectsByName(s.Operators)
+
+	var normOperatorClasses []OperatorClass
+	for _, opc := range sortSchemaObjectsByName(s.OperatorClasses) {
+		opc.DependsOnOperators = sortSchemaObjectsByName(opc.DependsOnOperators)
+		opc.DependsOnFunctions = sortSchemaObjectsByName(opc.DependsOnFunctions)
+		normOperatorClasses = append(normOperatorClasses, opc)
+	}
+	s.OperatorClasses = normOperatorClasses
+
+	var normPublications []Publication
+	for _, p := range sortSchemaObjectsByName(s.Publications) {
+		p.Tables = sortSchemaObjectsByName(p.Tables)
+		if len(p.Publish) > 0 {
+			p.Publish = sortByKey(p.Publish, func(s string) string { return s })
+		}
+		normPublications = append(normPublications, p)
+	}
+	s.Publications = normPublications
+
+	s.Privileges = sortSchemaObjectsByName(s.Privileges)
+	s.DefaultPrivileges = sortSchemaObjectsByName(s.DefaultPrivileges)
+
+	s.ObjectOwners = sortByKey(s.ObjectOwners, func(s string) string { return s })
+
 	return s
 }
 
@@ -137,6 +239,84 @@ func normalizeTable(t Table) Table {
 	return t
 }
 
+// markPrimaryKeyColumns sets IsPrimaryKey on the columns of each table that are part of a primary key index of the
+// schema. The columns of the tables are copied, so the input schema is not modified.
+func markPrimaryKeyColumns(s Schema) Schema {
+	pkColumnsByTable := make(map[string]map[string]bool)
+	for _, index := range s.Indexes {
+		if !index.IsPk() {
+			continue
+		}
+		pkColumns := make(map[string]bool)
+		for _, column := range index.Columns {
+			pkColumns[column] = true
+		}
+		pkColumnsByTable[index.OwningTable.GetName()] = pkColumns
+	}
+
+	if s.Tables == nil {
+		return s
+	}
+	tables := make([]Table, len(s.Tables))
+	for i, t := range s.Tables {
+		pkColumns := pkColumnsByTable[t.GetName()]
+		if t.Columns != nil {
+			columns := make([]Column, len(t.Columns))
+			for j, column := range t.Columns {
+				column.IsPrimaryKey = column.IsPrimaryKey || pkColumns[column.Name]
+				columns[j] = column
+			}
+			t.Columns = columns
+		}
+		tables[i] = t
+	}
+	s.Tables = tables
+	return s
+}
+
+func normalizeIndexes(indexes []Index) []Index {
+	var normIndexes []Index
+	for _, index := range sortSchemaObjectsByName(indexes) {
+		if len(index.Expressions) > 0 {
+			var normExpressions []string
+			for _, expr := range index.Expressions {
+				normExpressions = append(normExpressions, normalizeExpressionWhitespace(expr))
+			}
+			index.Expressions = normExpressions
+		}
+		if len(index.DependsOnOperatorClasses) > 0 {
+			index.DependsOnOperatorClasses = sortSchemaObjectsByName(index.DependsOnOperatorClasses)
+		}
+		normIndexes = append(normIndexes, index)
+	}
+	return normIndexes
+}
+
+// normalizeExpressionWhitespace collapses all whitespace outside of string literals and quoted identifiers into a
+// single space, so expressions that only differ in whitespace are considered equal.
+func normalizeExpressionWhitespace(expr string) string {
+	sb := strings.Builder{}
+	var quote rune
+	pendingSpace := false
+	for _, r := range strings.TrimSpace(expr) {
+		if quote == 0 && unicode.IsSpace(r) {
+			pendingSpace = true
+			continue
+		}
+		if pendingSpace {
+			sb.WriteRune(' ')
+			pendingSpace = false
+		}
+		if quote == 0 && (r == '\'' || r == '"') {
+			quote = r
+		} else if r == quote {
+			quote = 0
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
 // sortSchemaObjectsByName returns a (copied) sorted list of schema objects.
 func sortSchemaObjectsByName[S Object](vals []S) []S {
 	return sortByKey(vals, func(v S) string {
@@ -175,6 +355,8 @@ const (
 // schema
 type NamedSchema struct {
 	Name string
+	// Owner is the role that owns the schema. It is only fetched if WithOwners is provided; otherwise, it is empty.
+	Owner string
 }
 
 func (n NamedSchema) GetName() string {
@@ -186,19 +368,143 @@ type Extension struct {
 	Version string
 }
 
+// ForeignDataWrapper is a foreign-data wrapper created via `CREATE FOREIGN DATA WRAPPER`. Foreign-data wrappers are
+// not scoped to a schema. Wrappers created by extensions, e.g., postgres_fdw, are not included.
+type ForeignDataWrapper struct {
+	Name string
+	// Handler and Validator are the handler and validator functions of the wrapper. They are empty if the wrapper has
+	// no handler or validator, respectively
+	Handler   SchemaQualifiedName
+	Validator SchemaQualifiedName
+	// Options is nil if the wrapper has no options
+	Options map[string]string
+}
+
+func (f ForeignDataWrapper) GetName() string {
+	return f.Name
+}
+
+// ForeignServer is a foreign server created via `CREATE SERVER`. Foreign servers are not scoped to a schema.
+type ForeignServer struct {
+	Name               string
+	ForeignDataWrapper string
+	// Type and Version are empty if they were not specified
+	Type    string
+	Version string
+	// Options is nil if the server has no options
+	Options map[string]string
+}
+
+func (f ForeignServer) GetName() string {
+	return f.Name
+}
+
+// Collation is a collation created via `CREATE COLLATION`
+type Collation struct {
+	SchemaQualifiedName
+	// Provider is the locale provider, i.e., "libc", "icu", or "builtin"
+	Provider string
+	// Locale is the locale of an icu or builtin collation, e.g., "und-u-ks-level2". It is empty for libc collations
+	Locale string
+	// LcCollate and LcCtype are the locales of a libc collation. They are empty for icu and builtin collations
+	LcCollate string
+	LcCtype   string
+	// IsDeterministic is false if the collation considers strings equal even if they consist of different bytes
+	IsDeterministic bool
+}
+
 type Enum struct {
 	SchemaQualifiedName
 	Labels []string
 }
 
+// Domain is a type created via `CREATE DOMAIN`
+type Domain struct {
+	SchemaQualifiedName
+	// BaseType is the formatted name of the underlying type, e.g., "character varying(255)"
+	BaseType string
+	// Collation is empty if the base type is not collatable
+	Collation SchemaQualifiedName
+	// Default is the default expression. It is empty if the domain has no default
+	Default   string
+	IsNotNull bool
+	// Constraints are the domain's check constraints. The NOT NULL constraint is tracked via IsNotNull
+	Constraints []DomainConstraint
+}
+
+func (d Domain) IsCollated() bool {
+	return !d.Collation.IsEmpty()
+}
+
+type DomainConstraint struct {
+	Name string
+	// Check is the constraint definition, e.g., "CHECK ((VALUE > 0))", as returned by pg_get_constraintdef
+	Check string
+}
+
+func (c DomainConstraint) GetName() string {
+	return c.Name
+}
+
+// CompositeType is a type created via `CREATE TYPE ... AS (...)`. The row types of tables and views are not included.
+type CompositeType struct {
+	SchemaQualifiedName
+	// Attributes are the attributes of the type, in the order they are defined
+	Attributes []CompositeTypeAttribute
+}
+
+type CompositeTypeAttribute struct {
+	Name string
+	Type string
+	// Collation is empty if the attribute's type is not collatable
+	Collation SchemaQualifiedName
+}
+
+func (a CompositeTypeAttribute) IsCollated() bool {
+	return !a.Collation.IsEmpty()
+}
+
+// TextSearchDictionary is a dictionary created via `CREATE TEXT SEARCH DICTIONARY`
+type TextSearchDictionary struct {
+	SchemaQualifiedName
+	Template SchemaQualifiedName
+	// Options are the template-specific options of the dictionary, e.g., "language = 'english', stopwords = 'english'",
+	// as stored in pg_ts_dict.dictinitoption. It is empty if the dictionary has no options
+	Options string
+}
+
+// TextSearchConfig is a configuration created via `CREATE TEXT SEARCH CONFIGURATION`
+type TextSearchConfig struct {
+	SchemaQualifiedName
+	Parser SchemaQualifiedName
+	// Mappings are the configuration's token type mappings, ordered by the parser's token type ids
+	Mappings []TextSearchConfigMapping
+}
+
+type TextSearchConfigMapping struct {
+	// TokenType is the alias of the parser's token type, e.g., "asciiword"
+	TokenType string
+	// Dictionaries are consulted in order until one recognizes the token
+	Dictionaries []SchemaQualifiedName
+}
+
+func (m TextSearchConfigMapping) GetName() string {
+	return m.TokenType
+}
+
 type Table struct {
 	SchemaQualifiedName
 	Columns          []Column
 	CheckConstraints []CheckConstraint
 	Policies         []Policy
 	ReplicaIdentity  ReplicaIdentity
-	RLSEnabled       bool
-	RLSForced        bool
+	// RLSEnabled and RLSForced are pg_class.relrowsecurity and pg_class.relforcerowsecurity, respectively
+	RLSEnabled bool
+	RLSForced  bool
+	// StorageParameters are the storage parameters of the table, e.g., fillfactor. The storage parameters of the
+	// table's TOAST table are prefixed with "toast.", e.g., toast.autovacuum_enabled. It is nil if the table has no
+	// storage parameters.
+	StorageParameters map[string]string
 
 	// PartitionKeyDef is the output of Pg function pg_get_partkeydef:
 	// PARTITION BY $PartitionKeyDef
@@ -207,12 +513,52 @@ type Table struct {
 
 	ParentTable *SchemaQualifiedName
 	ForValues   string
+
+	// InheritsFrom are the parents of a table using (non-partition) inheritance, i.e., CREATE TABLE ... INHERITS, in
+	// the order they are inherited from
+	InheritsFrom []SchemaQualifiedName
+
+	// Tablespace is the name of the tablespace the table is stored in. It is empty if the table is stored in the
+	// database's default tablespace.
+	Tablespace string
+
+	// Comment is the comment on the table, i.e., COMMENT ON TABLE. It is nil if the table has no comment.
+	Comment *string
+
+	// Owner is the role that owns the table. It is only fetched if WithOwners is provided; otherwise, it is empty.
+	Owner string
+
+	// DistributionColumn is the column that Citus distributes the table by. It is only fetched if
+	// WithCitusDistributionColumns is provided; otherwise, it is empty. It is also empty if the table is not distributed.
+	DistributionColumn string
 }
 
 func (t Table) IsPartitioned() bool {
 	return len(t.PartitionKeyDef) > 0
 }
 
+// IsDefaultPartition returns whether the table is the default partition of its partitioned table, i.e., it holds the
+// rows that do not belong in any other partition
+func (t Table) IsDefaultPartition() bool {
+	return t.IsPartition() && t.ForValues == "DEFAULT"
+}
+
+// IsHashPartition returns whether the table is a partition of a hash partitioned table
+func (t Table) IsHashPartition() bool {
+	return t.IsPartition() && strings.HasPrefix(t.ForValues, "FOR VALUES WITH")
+}
+
+// HasPrimaryKey returns whether the table has a primary key, i.e., whether any of its columns is part of a primary
+// key constraint
+func (t Table) HasPrimaryKey() bool {
+	for _, column := range t.Columns {
+		if column.IsPrimaryKey {
+			return true
+		}
+	}
+	return false
+}
+
 // IsPartition returns whether the table is a partition.
 // It represents a mismatch in modeling because the ForValues and ParentTable are stored separately.
 // Instead, the fields should be stored under the same struct as a nilable pointer, and this function should be deleted.
@@ -228,6 +574,52 @@ type View struct {
 	DependsOnTables []SchemaQualifiedName
 	// DependsOnViews contains other views this view depends on
 	DependsOnViews []SchemaQualifiedName
+	// DependsOnForeignTables contains the foreign tables this view depends on
+	DependsOnForeignTables []SchemaQualifiedName
+	// Comment is the comment on the view, i.e., COMMENT ON VIEW. It is nil if the view has no comment.
+	Comment *string
+	// Owner is the role that owns the view. It is only fetched if WithOwners is provided; otherwise, it is empty.
+	Owner string
+}
+
+// ForeignTable is a table created via `CREATE FOREIGN TABLE`
+type ForeignTable struct {
+	SchemaQualifiedName
+	// Columns are the columns of the foreign table, in the order they are defined. Only the name, type, collation,
+	// nullability, and default of each column are tracked
+	Columns []Column
+	Server  string
+	// Options is nil if the foreign table has no options
+	Options map[string]string
+}
+
+// MaterializedView is a view created via `CREATE MATERIALIZED VIEW`
+type MaterializedView struct {
+	SchemaQualifiedName
+	// Definition is the SQL definition of the materialized view (the SELECT statement)
+	Definition string
+	// StorageParameters are the storage parameters of the materialized view. Like tables, the storage parameters of
+	// the TOAST table are prefixed with "toast."
+	StorageParameters map[string]string
+	// Indexes are the indexes on the materialized view. They are not included in Schema.Indexes
+	Indexes []Index
+	// DependsOnTables contains the tables this materialized view depends on
+	DependsOnTables []SchemaQualifiedName
+	// DependsOnViews contains the views this materialized view depends on
+	DependsOnViews []SchemaQualifiedName
+	// DependsOnMaterializedViews contains the other materialized views this materialized view depends on
+	DependsOnMaterializedViews []SchemaQualifiedName
+}
+
+// HasUniqueIndex returns true if the materialized view has a unique index, which is required to refresh it
+// concurrently
+func (m MaterializedView) HasUniqueIndex() bool {
+	for _, index := range m.Indexes {
+		if index.IsUnique {
+			return true
+		}
+	}
+	return false
 }
 
 type ColumnIdentityType string
@@ -257,15 +649,49 @@ type (
 		//   ''::text
 		//   CURRENT_TIMESTAMP
 		// If empty, indicates that there is no default value.
-		Default    string
-		IsNullable bool
+		Default string
+		// IsDefaultVolatile is true if the default value calls a volatile function, e.g., nextval() or random().
+		// Adding a column with a volatile default rewrites the table, since the default must be evaluated for every
+		// existing row.
+		IsDefaultVolatile bool
+		IsNullable        bool
 		// Size is the number of bytes required to store the value.
 		// It is used for data-packing purposes
 		Size     int
 		Identity *ColumnIdentity
+		// StorageType is the storage strategy of the column, i.e., attstorage. It is only populated if it differs from
+		// the default storage strategy of the column's type.
+		StorageType ColumnStorageType
+		// DefaultStorageType is the default storage strategy of the column's type. It is only populated if StorageType
+		// is populated, such that the column can be reverted to its default storage strategy.
+		DefaultStorageType ColumnStorageType
+		// IsGenerated is true if the column is a stored generated column, i.e., GENERATED ALWAYS AS (...) STORED
+		IsGenerated bool
+		// GenerationExpression is the expression used to compute the value of a generated column. It is only
+		// populated if IsGenerated is true. Generated columns never have a Default.
+		GenerationExpression string
+		// IsInherited is true if the column is only defined by the parents of a table using inheritance, i.e., it
+		// was not declared locally in the table. It is never populated for partitions.
+		IsInherited bool
+		// Comment is the comment on the column, i.e., COMMENT ON COLUMN. It is nil if the column has no comment.
+		Comment *string
+		// IsPrimaryKey is true if the column is part of the primary key of its table. It is derived from the primary
+		// key index of the table, so it is not part of the hash or the fingerprint of the schema.
+		IsPrimaryKey bool `hash:"ignore" json:"-"`
 	}
 )
 
+// ColumnStorageType represents the attstorage value in the pg_attribute system catalog.
+// See docs for possible values: https://www.postgresql.org/docs/current/catalog-pg-type.html#CATALOG-PG-TYPE
+type ColumnStorageType string
+
+const (
+	ColumnStorageTypePlain    ColumnStorageType = "p"
+	ColumnStorageTypeExternal ColumnStorageType = "e"
+	ColumnStorageTypeExtended ColumnStorageType = "x"
+	ColumnStorageTypeMain     ColumnStorageType = "m"
+)
+
 func (c Column) GetName() string {
 	return c.Name
 }
@@ -299,13 +725,18 @@ type (
 	IndexConstraintType string
 
 	// IndexConstraint informally represents a constraint that is always 1:1 with an index, i.e.,
-	// primary and unique constraints. It's easiest to just treat these like a property of the index rather than
+	// primary key, unique, and exclusion constraints. It's easiest to just treat these like a property of the index rather than
 	// a separate entity
 	IndexConstraint struct {
 		Type                  IndexConstraintType
 		EscapedConstraintName string
 		ConstraintDef         string
 		IsLocal               bool
+		// Deferrable is true if the constraint is DEFERRABLE
+		Deferrable bool
+		// InitiallyDeferred is true if the constraint is INITIALLY DEFERRED. It can only be true if the constraint is
+		// deferrable
+		InitiallyDeferred bool
 	}
 
 	Index struct {
@@ -313,9 +744,29 @@ type (
 		// Referencing the name is an anti-pattern because it is not qualified. Use should use GetSchemaQualifiedName instead.
 		Name        string
 		OwningTable SchemaQualifiedName
-		Columns     []string
+		// Columns are the key columns of the index
+		Columns []string
+		// IncludedColumns are the non-key columns of a covering index, i.e., `INCLUDE (col1, col2)`
+		IncludedColumns []string
+		// Expressions are the expressions of the index's expression columns, e.g., lower(email), in the order they
+		// appear in the index. Expression columns are not included in Columns.
+		Expressions []string
 		IsInvalid   bool
 		IsUnique    bool
+		// Predicate is the WHERE clause of a partial index, as returned by pg_get_expr. It is empty if the index is
+		// not partial.
+		Predicate string
+		// Method is the index's access method, e.g., btree, hash, gin, gist, spgist, or brin
+		Method string
+		// PagesPerRange is the pages_per_range storage parameter of a BRIN index. It is nil if it is not set, i.e.,
+		// the default is used.
+		PagesPerRange *int
+		// Autosummarize is the autosummarize storage parameter of a BRIN index. It is nil if it is not set, i.e., the
+		// default is used.
+		Autosummarize *bool
+		// GINParameters are the storage parameters of a GIN index that are set, i.e., fastupdate and
+		// gin_pending_list_limit. The value of fastupdate is normalized to "on" or "off".
+		GINParameters map[string]string
 
 		Constraint *IndexConstraint
 
@@ -323,11 +774,28 @@ type (
 		GetIndexDefStmt GetIndexDefStatement
 
 		ParentIdx *SchemaQualifiedName
+
+		// DependsOnOperatorClasses contains the operator classes used by the index that are neither built-in nor
+		// created by an extension
+		DependsOnOperatorClasses []OperatorClassReference
+
+		// Tablespace is the name of the tablespace the index is stored in. It is empty if the index is stored in the
+		// database's default tablespace.
+		Tablespace string
+
+		// Comment is the comment on the index, i.e., COMMENT ON INDEX. It is nil if the index has no comment.
+		Comment *string
 	}
 )
 
 const (
 	PkIndexConstraintType IndexConstraintType = "p"
+	// UniqueIndexConstraintType is a unique constraint, i.e., `CONSTRAINT foo UNIQUE (col)`. Unlike a standalone unique
+	// index, the backing index cannot be dropped without dropping the constraint.
+	UniqueIndexConstraintType IndexConstraintType = "u"
+	// ExclusionIndexConstraintType is an exclusion constraint, i.e., `EXCLUDE USING gist (col WITH &&)`. Unlike primary
+	// key and unique constraints, exclusion constraints cannot be added using an existing index.
+	ExclusionIndexConstraintType IndexConstraintType = "x"
 )
 
 func (i Index) GetName() string {
@@ -345,6 +813,10 @@ func (i Index) IsPk() bool {
 	return i.Constraint != nil && i.Constraint.Type == PkIndexConstraintType
 }
 
+func (i Index) IsExclusionConstraint() bool {
+	return i.Constraint != nil && i.Constraint.Type == ExclusionIndexConstraintType
+}
+
 type CheckConstraint struct {
 	Name string
 	// KeyColumns are the columns that the constraint applies to
@@ -359,12 +831,33 @@ func (c CheckConstraint) GetName() string {
 	return c.Name
 }
 
+// StatisticsObject is an extended statistics object created via `CREATE STATISTICS`. Statistics objects on
+// materialized views and foreign tables are not tracked.
+type StatisticsObject struct {
+	SchemaQualifiedName
+	Table SchemaQualifiedName
+	// Kinds contains the enabled statistics kinds, i.e., "ndistinct", "dependencies", and "mcv"
+	Kinds []string
+	// Columns contains the columns the statistics are computed on. Expressions are only included in Def.
+	Columns []string
+	// StatisticsTarget is the statistics target set via `ALTER STATISTICS ... SET STATISTICS`. It is -1 if the
+	// system default is used.
+	StatisticsTarget int
+	// Def is the statement required to completely (re)create the statistics object
+	Def string
+}
+
 type ForeignKeyConstraint struct {
 	EscapedName   string
 	OwningTable   SchemaQualifiedName
 	ForeignTable  SchemaQualifiedName
 	ConstraintDef string
 	IsValid       bool
+	// Deferrable is true if the constraint is DEFERRABLE
+	Deferrable bool
+	// InitiallyDeferred is true if the constraint is INITIALLY DEFERRED. It can only be true if the constraint is
+	// deferrable
+	InitiallyDeferred bool
 }
 
 func (f ForeignKeyConstraint) GetName() string {
@@ -388,6 +881,9 @@ type (
 		MinValue   int64
 		CacheSize  int64
 		Cycle      bool
+		// OwnerRole is the role that owns the sequence. It is only fetched if WithOwners is provided; otherwise, it is
+		// empty. It is not to be confused with Owner, which is the column the sequence is owned by.
+		OwnerRole string
 	}
 )
 
@@ -401,11 +897,17 @@ type Function struct {
 	// can track the dependencies of the function (or not)
 	Language           string
 	DependsOnFunctions []SchemaQualifiedName
+	// IsWindow is true if the function is a window function, i.e., it was created with the WINDOW attribute
+	IsWindow bool
 	// DependsOnTables contains the tables this function depends on
 	DependsOnTables []SchemaQualifiedName
 	// ReferencedColumns contains table.column pairs that this function references
 	// This is populated by parsing the function body for SQL functions
 	ReferencedColumns []TableColumnRef
+	// Comment is the comment on the function, i.e., COMMENT ON FUNCTION. It is nil if the function has no comment.
+	Comment *string
+	// Owner is the role that owns the function. It is only fetched if WithOwners is provided; otherwise, it is empty.
+	Owner string
 }
 
 // TableColumnRef represents a reference to a specific table column
@@ -420,6 +922,46 @@ type Procedure struct {
 	// the procedure, as returned by `pg_get_functiondef`. It is a CREATE OR REPLACE
 	// statement.
 	Def string
+	// Language is the language of the procedure. Like functions, this determines if we can track the dependencies
+	// of the procedure (or not)
+	Language string
+	// DependsOnFunctions contains the functions and procedures this procedure depends on, e.g., the procedures it
+	// calls
+	DependsOnFunctions []SchemaQualifiedName
+}
+
+// Aggregate represents a user-defined aggregate function, i.e., one created via `CREATE AGGREGATE`. Like procs,
+// aggregates are identified by their name AND their argument types, so the escaped name includes the argument types,
+// e.g., `"avg_custom"(numeric)`. Moving-aggregate mode and sort operators are not supported.
+type Aggregate struct {
+	SchemaQualifiedName
+	// InputTypes are the identity arguments of the aggregate, e.g., "numeric". They are empty if the aggregate
+	// takes no arguments, i.e., it is declared with `*`.
+	InputTypes string
+	// StateType is the data type of the aggregate's state value
+	StateType string
+	// StateFunction is the name of the state transition function (SFUNC). Like the other support functions, it does
+	// not include the function's arguments.
+	StateFunction SchemaQualifiedName
+	// FinalFunction is the name of the final function (FINALFUNC). It is nil if the aggregate has no final function.
+	FinalFunction *SchemaQualifiedName
+	// FinalFunctionExtra is true if extra dummy arguments are passed to the final function (FINALFUNC_EXTRA)
+	FinalFunctionExtra bool
+	// CombineFunction is the name of the function used to combine partial states for parallel aggregation
+	// (COMBINEFUNC). It is nil if the aggregate has no combine function.
+	CombineFunction *SchemaQualifiedName
+	// SerialFunction and DeserialFunction are the names of the functions used to (de)serialize an internal state
+	// for parallel aggregation (SERIALFUNC and DESERIALFUNC). They are nil if they are not set.
+	SerialFunction   *SchemaQualifiedName
+	DeserialFunction *SchemaQualifiedName
+	// InitialCondition is the initial value of the state (INITCOND). It is nil if the aggregate has no initial
+	// condition, in which case the state starts as null.
+	InitialCondition *string
+	// Parallel is the parallel safety of the aggregate, i.e., SAFE, RESTRICTED, or UNSAFE
+	Parallel string
+	// DependsOnFunctions contains the support functions of the aggregate, including their arguments, such that they
+	// can be matched to the functions in the schema
+	DependsOnFunctions []SchemaQualifiedName
 }
 
 var (
@@ -479,17 +1021,125 @@ func (t Trigger) GetName() string {
 }
 
 type EventTrigger struct {
-	Name       string
-	Event      string // e.g., "ddl_command_start", "ddl_command_end", "table_rewrite", "sql_drop"
-	Function   SchemaQualifiedName
-	Enabled    string // 'O' = enabled, 'D' = disabled, 'R' = replica only, 'A' = always
-	Tags       []string // e.g., ["CREATE TABLE", "ALTER TABLE"]
+	Name     string
+	Event    string // e.g., "ddl_command_start", "ddl_command_end", "table_rewrite", "sql_drop"
+	Function SchemaQualifiedName
+	Enabled  string   // 'O' = enabled, 'D' = disabled, 'R' = replica only, 'A' = always
+	Tags     []string // e.g., ["CREATE TABLE", "ALTER TABLE"]
 }
 
 func (e EventTrigger) GetName() string {
 	return e.Name
 }
 
+// Operator represents a user-defined operator, i.e., one created via `CREATE OPERATOR`. Like procs, operators are
+// identified by their name AND their argument types, so the escaped name includes the argument types, e.g.,
+// `//(text, text)`.
+type Operator struct {
+	SchemaQualifiedName
+	// Function is the function that implements the operator
+	Function SchemaQualifiedName
+	// Def is the statement required to completely (re)create the operator. Postgres does not provide a function
+	// to get the definition of an operator, so it is built from pg_operator. Operators cannot be altered in a
+	// meaningful way, so any change to the operator requires it to be dropped and re-created.
+	Def string
+}
+
+// OperatorClass represents a user-defined operator class, i.e., one created via `CREATE OPERATOR CLASS`.
+type OperatorClass struct {
+	SchemaQualifiedName
+	// IndexMethod is the index access method the operator class is for, e.g., btree
+	IndexMethod string
+	// DependsOnOperators contains the operators that are members of the operator class
+	DependsOnOperators []SchemaQualifiedName
+	// DependsOnFunctions contains the support functions that are members of the operator class
+	DependsOnFunctions []SchemaQualifiedName
+	// Def is the statement required to completely (re)create the operator class
+	Def string
+}
+
+// GetName gets the name of the operator class. Operator classes are unique by their name and index method.
+func (o OperatorClass) GetName() string {
+	return buildOperatorClassName(o.SchemaQualifiedName, o.IndexMethod)
+}
+
+// OperatorClassReference references an operator class by its name and index method
+type OperatorClassReference struct {
+	SchemaQualifiedName
+	IndexMethod string
+}
+
+// GetName gets the name of the referenced operator class, i.e., the name of the OperatorClass it references
+func (o OperatorClassReference) GetName() string {
+	return buildOperatorClassName(o.SchemaQualifiedName, o.IndexMethod)
+}
+
+func buildOperatorClassName(name SchemaQualifiedName, indexMethod string) string {
+	return fmt.Sprintf("%s USING %s", name.GetFQEscapedName(), indexMethod)
+}
+
+// Publication represents a logical replication publication. Publications are not scoped to a schema.
+type Publication struct {
+	Name string
+	// AllTables is true if the publication was created with `FOR ALL TABLES`, i.e., it automatically includes tables
+	// created in the future. If true, Tables is empty.
+	AllTables bool
+	// Tables contains the tables explicitly included in the publication
+	Tables []SchemaQualifiedName
+	// Publish contains the operations that are published, e.g., insert, update, delete, truncate
+	Publish []string
+}
+
+func (p Publication) GetName() string {
+	return p.Name
+}
+
+// PrivilegeGranteePublic is the grantee of privileges granted to all roles
+const PrivilegeGranteePublic = "PUBLIC"
+
+// Privilege is a privilege on a table, sequence, function, or procedure granted via `GRANT`. Each privilege type,
+// e.g., SELECT and INSERT, is a separate Privilege. The privileges the owner of an object implicitly holds are
+// excluded, and column-level privileges are not tracked.
+type Privilege struct {
+	// ObjectType is the object type as written in a GRANT statement, i.e., "TABLE", "SEQUENCE", "FUNCTION", or
+	// "PROCEDURE". The privileges of views, materialized views, and foreign tables have the "TABLE" object type.
+	ObjectType string
+	Object     SchemaQualifiedName
+	// Grantee is the name of the role the privilege is granted to. It is PrivilegeGranteePublic if the privilege is
+	// granted to all roles
+	Grantee string
+	// Type is the type of the privilege, e.g., "SELECT" or "EXECUTE"
+	Type        string
+	IsGrantable bool
+}
+
+func (p Privilege) GetName() string {
+	return fmt.Sprintf("%s %s %s %s", p.ObjectType, p.Object.GetFQEscapedName(), p.Grantee, p.Type)
+}
+
+// DefaultPrivilege is a privilege granted via `ALTER DEFAULT PRIVILEGES` to objects created by the grantor in the
+// future. Like Privilege, each privilege type is a separate DefaultPrivilege. Revoking the built-in default privileges,
+// e.g., EXECUTE on functions from PUBLIC, is not tracked.
+type DefaultPrivilege struct {
+	// Grantor is the name of the role whose future objects the privilege applies to, i.e., the `FOR ROLE` role
+	Grantor string
+	// SchemaName is the name of the schema the privilege is scoped to. It is empty if the privilege applies to all
+	// schemas.
+	SchemaName string
+	// ObjectType is the object type as written in an ALTER DEFAULT PRIVILEGES statement, i.e., "TABLES", "SEQUENCES",
+	// "FUNCTIONS", "TYPES", or "SCHEMAS"
+	ObjectType string
+	// Grantee is the name of the role the privilege is granted to. It is PrivilegeGranteePublic if the privilege is
+	// granted to all roles
+	Grantee     string
+	Type        string
+	IsGrantable bool
+}
+
+func (d DefaultPrivilege) GetName() string {
+	return fmt.Sprintf("%s %s %s %s %s", d.Grantor, d.SchemaName, d.ObjectType, d.Grantee, d.Type)
+}
+
 type (
 	GetSchemaOpt func(*getSchemaOptions)
 )
@@ -512,6 +1162,49 @@ func WithExcludeSchemas(schemas ...string) GetSchemaOpt {
 	}
 }
 
+// WithOnlySchemas filters the schema to only include the given schemas, i.e., the equivalent of `pg_dump --schema`.
+// It behaves like WithIncludeSchemas; however, dependencies on objects outside the included schemas, e.g., a function
+// in "accounting" calling a function in "public", are omitted from DependsOnFunctions and DependsOnTables, since those
+// objects are outside the managed scope.
+func WithOnlySchemas(schemas ...string) GetSchemaOpt {
+	return func(o *getSchemaOptions) {
+		o.includeSchemas = append(o.includeSchemas, schemas...)
+		o.omitOutOfScopeDependencies = true
+	}
+}
+
+// WithObjectOwners fetches the roles that own objects in the schema into Schema.ObjectOwners.
+func WithObjectOwners() GetSchemaOpt {
+	return func(o *getSchemaOptions) {
+		o.fetchObjectOwners = true
+	}
+}
+
+// WithOwners fetches the roles that own schemas, tables, views, sequences, and functions, e.g., Table.Owner. Without it, the
+// owners are empty, i.e., ownership is not diffed.
+func WithOwners() GetSchemaOpt {
+	return func(o *getSchemaOptions) {
+		o.includeOwners = true
+	}
+}
+
+// WithPrivileges fetches the privileges granted on tables, views, sequences, functions, and procedures and the default
+// privileges, i.e., Schema.Privileges and Schema.DefaultPrivileges. Without it, no privileges are fetched, i.e.,
+// privileges are not diffed.
+func WithPrivileges() GetSchemaOpt {
+	return func(o *getSchemaOptions) {
+		o.includePrivileges = true
+	}
+}
+
+// WithCitusDistributionColumns fetches the distribution column of each table distributed by Citus, i.e.,
+// Table.DistributionColumn. If Citus is not installed, no distribution columns are fetched.
+func WithCitusDistributionColumns() GetSchemaOpt {
+	return func(o *getSchemaOptions) {
+		o.fetchCitusDistributionColumns = true
+	}
+}
+
 type getSchemaOptions struct {
 	// includeSchemas is a list of schemas to include in the schema. If empty, then all schemas are included.
 	// We could have built a more complex set of options using the nameFilter system (nested unions and intersections);
@@ -519,6 +1212,21 @@ type getSchemaOptions struct {
 	includeSchemas []string
 	// excludeSchemas is the exclude analog of includeSchemas.
 	excludeSchemas []string
+	// omitOutOfScopeDependencies omits dependencies on objects that are filtered out of the schema.
+	omitOutOfScopeDependencies bool
+	// fetchObjectOwners fetches the roles that own objects in the schema.
+	fetchObjectOwners bool
+	// includeOwners fetches the owner of each schema, table, view, sequence, and function.
+	includeOwners bool
+	// includePrivileges fetches the privileges granted on the schema objects and the default privileges.
+	includePrivileges bool
+	// fetchCitusDistributionColumns fetches the distribution column of each table distributed by Citus.
+	fetchCitusDistributionColumns bool
+	// includeObjects is a list of glob patterns of objects to include in the schema. If empty, then all objects are
+	// included.
+	includeObjects []string
+	// excludeObjects is the exclude analog of includeObjects.
+	excludeObjects []string
 }
 
 // GetSchema fetches the database schema. It is a non-atomic operation.
@@ -545,11 +1253,54 @@ func GetSchema(ctx context.Context, db queries.DBTX, opts ...GetSchemaOpt) (Sche
 		return Schema{}, fmt.Errorf("building name filter: %w", err)
 	}
 
-	return (&schemaFetcher{
-		q:                      queries.New(db),
+	objectFilter, err := buildObjectFilter(options)
+	if err != nil {
+		return Schema{}, fmt.Errorf("building object filter: %w", err)
+	}
+
+	dependencyFilter := func(SchemaQualifiedName) bool {
+		return true
+	}
+	if options.omitOutOfScopeDependencies {
+		dependencyFilter = nameFilter
+	}
+
+	q := queries.New(db)
+	schema, err := (&schemaFetcher{
+		q:                      q,
 		goroutineRunnerFactory: goroutineRunnerFactory,
 		nameFilter:             nameFilter,
+		dependencyFilter:       dependencyFilter,
+		fetchObjectOwners:      options.fetchObjectOwners,
+		includeOwners:          options.includeOwners,
+		includePrivileges:      options.includePrivileges,
+
+		fetchCitusDistributionColumns: options.fetchCitusDistributionColumns,
 	}).getSchema(ctx)
+	if err != nil {
+		return Schema{}, err
+	}
+
+	rawTrackingTables, err := q.GetTablesByComment(ctx, TrackingTableComment)
+	if err != nil {
+		return Schema{}, fmt.Errorf("GetTablesByComment: %w", err)
+	}
+	var trackingTables []SchemaQualifiedName
+	for _, rawTrackingTable := range rawTrackingTables {
+		trackingTables = append(trackingTables, buildNameFromUnescaped(rawTrackingTable.TableName, rawTrackingTable.TableSchemaName))
+	}
+	schema, err = excludeTrackingTables(schema, trackingTables)
+	if err != nil {
+		return Schema{}, fmt.Errorf("excluding tracking tables: %w", err)
+	}
+
+	if objectFilter != nil {
+		schema, err = objectFilter.filterSchemaObjects(schema)
+		if err != nil {
+			return Schema{}, fmt.Errorf("filtering objects: %w", err)
+		}
+	}
+	return schema, nil
 }
 
 func buildNameFilter(options getSchemaOptions) (nameFilter, error) {
@@ -621,6 +1372,19 @@ type (
 		// Examples of dependencies that could be filtered out include the functions used by triggers and the parent
 		// tables of partitions.
 		nameFilter nameFilter
+		// dependencyFilter is a filter that determines which dependencies, e.g., DependsOnFunctions, to include in
+		// the schema.
+		dependencyFilter nameFilter
+		// fetchObjectOwners determines whether the owners of the schema objects are fetched.
+		fetchObjectOwners bool
+		// includeOwners determines whether the owner of each schema, table, view, sequence, and function is fetched.
+		includeOwners bool
+		// includePrivileges determines whether the privileges granted on the schema objects and the default
+		// privileges are fetched.
+		includePrivileges bool
+		// fetchCitusDistributionColumns determines whether the distribution columns of Citus distributed tables are
+		// fetched.
+		fetchCitusDistributionColumns bool
 	}
 )
 
@@ -648,13 +1412,69 @@ func (s *schemaFetcher) getSchema(ctx context.Context) (Schema, error) {
 		return Schema{}, fmt.Errorf("starting enums future: %w", err)
 	}
 
+	domainsFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]Domain, error) {
+		return s.fetchDomains(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting domains future: %w", err)
+	}
+
+	compositeTypesFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]CompositeType, error) {
+		return s.fetchCompositeTypes(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting composite types future: %w", err)
+	}
+
+	textSearchDictionariesFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]TextSearchDictionary, error) {
+		return s.fetchTextSearchDictionaries(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting text search dictionaries future: %w", err)
+	}
+
+	textSearchConfigsFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]TextSearchConfig, error) {
+		return s.fetchTextSearchConfigs(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting text search configs future: %w", err)
+	}
+
+	collationsFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]Collation, error) {
+		return s.fetchCollations(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting collations future: %w", err)
+	}
+
+	foreignDataWrappersFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]ForeignDataWrapper, error) {
+		return s.fetchForeignDataWrappers(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting foreign data wrappers future: %w", err)
+	}
+
+	foreignServersFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]ForeignServer, error) {
+		return s.fetchForeignServers(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting foreign servers future: %w", err)
+	}
+
 	tablesFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]Table, error) {
 		return s.fetchTables(ctx)
 	})
 	if err != nil {
 		return Schema{}, fmt.Errorf("starting tables future: %w", err)
 	}
-	
+
+	foreignTablesFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]ForeignTable, error) {
+		return s.fetchForeignTables(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting foreign tables future: %w", err)
+	}
+
 	viewsFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]View, error) {
 		return s.fetchViews(ctx)
 	})
@@ -662,6 +1482,13 @@ func (s *schemaFetcher) getSchema(ctx context.Context) (Schema, error) {
 		return Schema{}, fmt.Errorf("starting views future: %w", err)
 	}
 
+	materializedViewsFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]MaterializedView, error) {
+		return s.fetchMaterializedViews(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting materialized views future: %w", err)
+	}
+
 	indexesFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]Index, error) {
 		return s.fetchIndexes(ctx)
 	})
@@ -669,6 +1496,13 @@ func (s *schemaFetcher) getSchema(ctx context.Context) (Schema, error) {
 		return Schema{}, fmt.Errorf("starting indexes future: %w", err)
 	}
 
+	statisticsObjectsFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]StatisticsObject, error) {
+		return s.fetchStatisticsObjects(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting statistics objects future: %w", err)
+	}
+
 	fkConsFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]ForeignKeyConstraint, error) {
 		return s.fetchForeignKeyCons(ctx)
 	})
@@ -697,13 +1531,20 @@ func (s *schemaFetcher) getSchema(ctx context.Context) (Schema, error) {
 		return Schema{}, fmt.Errorf("starting functions future: %w", err)
 	}
 
+	aggregatesFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]Aggregate, error) {
+		return s.fetchAggregates(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting aggregates future: %w", err)
+	}
+
 	triggersFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]Trigger, error) {
 		return s.fetchTriggers(ctx)
 	})
 	if err != nil {
 		return Schema{}, fmt.Errorf("starting triggers future: %w", err)
 	}
-	
+
 	eventTriggersFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]EventTrigger, error) {
 		return s.fetchEventTriggers(ctx)
 	})
@@ -711,35 +1552,121 @@ func (s *schemaFetcher) getSchema(ctx context.Context) (Schema, error) {
 		return Schema{}, fmt.Errorf("starting event triggers future: %w", err)
 	}
 
-	schemas, err := namedSchemasFuture.Get(ctx)
+	operatorsFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]Operator, error) {
+		return s.fetchOperators(ctx)
+	})
 	if err != nil {
-		return Schema{}, fmt.Errorf("getting named schemas: %w", err)
+		return Schema{}, fmt.Errorf("starting operators future: %w", err)
 	}
 
-	extensions, err := extensionsFuture.Get(ctx)
+	operatorClassesFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]OperatorClass, error) {
+		return s.fetchOperatorClasses(ctx)
+	})
 	if err != nil {
-		return Schema{}, fmt.Errorf("getting extensions: %w", err)
+		return Schema{}, fmt.Errorf("starting operator classes future: %w", err)
 	}
 
-	enums, err := enumsFuture.Get(ctx)
+	publicationsFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]Publication, error) {
+		return s.fetchPublications(ctx)
+	})
 	if err != nil {
-		return Schema{}, fmt.Errorf("getting enums: %w", err)
+		return Schema{}, fmt.Errorf("starting publications future: %w", err)
 	}
 
-	tables, err := tablesFuture.Get(ctx)
+	privilegesFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]Privilege, error) {
+		return s.fetchPrivileges(ctx)
+	})
 	if err != nil {
-		return Schema{}, fmt.Errorf("getting tables: %w", err)
+		return Schema{}, fmt.Errorf("starting privileges future: %w", err)
 	}
-	
-	views, err := viewsFuture.Get(ctx)
+
+	defaultPrivilegesFuture, err := concurrent.SubmitFuture(ctx, goroutineRunner, func() ([]DefaultPrivilege, error) {
+		return s.fetchDefaultPrivileges(ctx)
+	})
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting default privileges future: %w", err)
+	}
+
+	schemas, err := namedSchemasFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting named schemas: %w", err)
+	}
+
+	extensions, err := extensionsFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting extensions: %w", err)
+	}
+
+	enums, err := enumsFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting enums: %w", err)
+	}
+
+	domains, err := domainsFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting domains: %w", err)
+	}
+
+	compositeTypes, err := compositeTypesFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting composite types: %w", err)
+	}
+
+	textSearchDictionaries, err := textSearchDictionariesFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting text search dictionaries: %w", err)
+	}
+
+	textSearchConfigs, err := textSearchConfigsFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting text search configs: %w", err)
+	}
+
+	collations, err := collationsFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting collations: %w", err)
+	}
+
+	foreignDataWrappers, err := foreignDataWrappersFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting foreign data wrappers: %w", err)
+	}
+
+	foreignServers, err := foreignServersFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting foreign servers: %w", err)
+	}
+
+	tables, err := tablesFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting tables: %w", err)
+	}
+
+	foreignTables, err := foreignTablesFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting foreign tables: %w", err)
+	}
+
+	views, err := viewsFuture.Get(ctx)
 	if err != nil {
 		return Schema{}, fmt.Errorf("getting views: %w", err)
 	}
 
+	materializedViews, err := materializedViewsFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting materialized views: %w", err)
+	}
+
 	indexes, err := indexesFuture.Get(ctx)
 	if err != nil {
 		return Schema{}, fmt.Errorf("getting indexes: %w", err)
 	}
+	materializedViews, indexes = moveIndexesToMaterializedViews(materializedViews, indexes)
+
+	statisticsObjects, err := statisticsObjectsFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting statistics objects: %w", err)
+	}
 
 	fkCons, err := fkConsFuture.Get(ctx)
 	if err != nil {
@@ -761,42 +1688,98 @@ func (s *schemaFetcher) getSchema(ctx context.Context) (Schema, error) {
 		return Schema{}, fmt.Errorf("getting procedures: %w", err)
 	}
 
+	aggregates, err := aggregatesFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting aggregates: %w", err)
+	}
+
 	triggers, err := triggersFuture.Get(ctx)
 	if err != nil {
 		return Schema{}, fmt.Errorf("getting triggers: %w", err)
 	}
-	
+
 	eventTriggers, err := eventTriggersFuture.Get(ctx)
 	if err != nil {
 		return Schema{}, fmt.Errorf("getting event triggers: %w", err)
 	}
 
-	return Schema{
-		NamedSchemas:          schemas,
-		Extensions:            extensions,
-		Enums:                 enums,
-		Tables:                tables,
-		Views:                 views,
-		Indexes:               indexes,
-		ForeignKeyConstraints: fkCons,
-		Sequences:             sequences,
-		Functions:             functions,
-		Procedures:            procedures,
-		Triggers:              triggers,
-		EventTriggers:         eventTriggers,
-	}, nil
+	operators, err := operatorsFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting operators: %w", err)
+	}
+
+	operatorClasses, err := operatorClassesFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting operator classes: %w", err)
+	}
+
+	publications, err := publicationsFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting publications: %w", err)
+	}
+
+	privileges, err := privilegesFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting privileges: %w", err)
+	}
+
+	defaultPrivileges, err := defaultPrivilegesFuture.Get(ctx)
+	if err != nil {
+		return Schema{}, fmt.Errorf("getting default privileges: %w", err)
+	}
+
+	var objectOwners []string
+	if s.fetchObjectOwners {
+		objectOwners, err = s.fetchOwners(ctx)
+		if err != nil {
+			return Schema{}, fmt.Errorf("getting object owners: %w", err)
+		}
+	}
+
+	return markPrimaryKeyColumns(Schema{
+		NamedSchemas:           schemas,
+		Extensions:             extensions,
+		Collations:             collations,
+		ForeignDataWrappers:    foreignDataWrappers,
+		ForeignServers:         foreignServers,
+		Enums:                  enums,
+		Domains:                domains,
+		CompositeTypes:         compositeTypes,
+		TextSearchDictionaries: textSearchDictionaries,
+		TextSearchConfigs:      textSearchConfigs,
+		Tables:                 tables,
+		ForeignTables:          foreignTables,
+		Views:                  views,
+		MaterializedViews:      materializedViews,
+		Indexes:                indexes,
+		StatisticsObjects:      statisticsObjects,
+		ForeignKeyConstraints:  fkCons,
+		Sequences:              sequences,
+		Functions:              functions,
+		Procedures:             procedures,
+		Aggregates:             aggregates,
+		Triggers:               triggers,
+		EventTriggers:          eventTriggers,
+		Operators:              operators,
+		OperatorClasses:        operatorClasses,
+		Publications:           publications,
+		Privileges:             privileges,
+		DefaultPrivileges:      defaultPrivileges,
+		ObjectOwners:           objectOwners,
+	}), nil
 }
 
 func (s *schemaFetcher) fetchNamedSchemas(ctx context.Context) ([]NamedSchema, error) {
-	schemaNames, err := s.q.GetSchemas(ctx)
+	rawSchemas, err := s.q.GetSchemas(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("GetSchemas(): %w", err)
 	}
 
 	var schemas []NamedSchema
-	for _, schemaName := range schemaNames {
+	for _, rawSchema := range rawSchemas {
 		schemas = append(schemas, NamedSchema{
-			Name: schemaName,
+			Name:  rawSchema.SchemaName,
+			Owner: s.buildOwner(rawSchema.OwnerName),
 		})
 	}
 
@@ -870,6 +1853,223 @@ func (s *schemaFetcher) fetchEnums(ctx context.Context) ([]Enum, error) {
 	return enums, nil
 }
 
+func (s *schemaFetcher) fetchDomains(ctx context.Context) ([]Domain, error) {
+	rawDomains, err := s.q.GetDomains(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetDomains: %w", err)
+	}
+
+	var domains []Domain
+	for _, rawDomain := range rawDomains {
+		if len(rawDomain.ConstraintDefs) != len(rawDomain.ConstraintNames) {
+			return nil, fmt.Errorf("domain %s has mismatched constraint arrays", rawDomain.DomainName)
+		}
+
+		var constraints []DomainConstraint
+		for i, name := range rawDomain.ConstraintNames {
+			constraints = append(constraints, DomainConstraint{
+				Name:  name,
+				Check: rawDomain.ConstraintDefs[i],
+			})
+		}
+
+		collation := SchemaQualifiedName{}
+		if len(rawDomain.CollationName) > 0 {
+			collation = SchemaQualifiedName{
+				EscapedName: EscapeIdentifier(rawDomain.CollationName),
+				SchemaName:  rawDomain.CollationSchemaName,
+			}
+		}
+
+		domains = append(domains, Domain{
+			SchemaQualifiedName: SchemaQualifiedName{
+				SchemaName:  rawDomain.DomainSchemaName,
+				EscapedName: EscapeIdentifier(rawDomain.DomainName),
+			},
+			BaseType:    rawDomain.BaseType,
+			Collation:   collation,
+			Default:     rawDomain.DefaultValue,
+			IsNotNull:   rawDomain.IsNotNull,
+			Constraints: constraints,
+		})
+	}
+
+	domains = filterSliceByName(
+		domains,
+		func(domain Domain) SchemaQualifiedName {
+			return domain.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return domains, nil
+}
+
+func (s *schemaFetcher) fetchCompositeTypes(ctx context.Context) ([]CompositeType, error) {
+	rawCompositeTypes, err := s.q.GetCompositeTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetCompositeTypes: %w", err)
+	}
+
+	var compositeTypes []CompositeType
+	for _, rawCompositeType := range rawCompositeTypes {
+		if len(rawCompositeType.AttributeTypes) != len(rawCompositeType.AttributeNames) ||
+			len(rawCompositeType.AttributeCollationNames) != len(rawCompositeType.AttributeNames) ||
+			len(rawCompositeType.AttributeCollationSchemaNames) != len(rawCompositeType.AttributeNames) {
+			return nil, fmt.Errorf("composite type %s has mismatched attribute arrays", rawCompositeType.TypeName)
+		}
+
+		var attributes []CompositeTypeAttribute
+		for i, name := range rawCompositeType.AttributeNames {
+			collation := SchemaQualifiedName{}
+			if len(rawCompositeType.AttributeCollationNames[i]) > 0 {
+				collation = SchemaQualifiedName{
+					EscapedName: EscapeIdentifier(rawCompositeType.AttributeCollationNames[i]),
+					SchemaName:  rawCompositeType.AttributeCollationSchemaNames[i],
+				}
+			}
+			attributes = append(attributes, CompositeTypeAttribute{
+				Name:      name,
+				Type:      rawCompositeType.AttributeTypes[i],
+				Collation: collation,
+			})
+		}
+
+		compositeTypes = append(compositeTypes, CompositeType{
+			SchemaQualifiedName: SchemaQualifiedName{
+				SchemaName:  rawCompositeType.TypeSchemaName,
+				EscapedName: EscapeIdentifier(rawCompositeType.TypeName),
+			},
+			Attributes: attributes,
+		})
+	}
+
+	compositeTypes = filterSliceByName(
+		compositeTypes,
+		func(compositeType CompositeType) SchemaQualifiedName {
+			return compositeType.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return compositeTypes, nil
+}
+
+func (s *schemaFetcher) fetchCollations(ctx context.Context) ([]Collation, error) {
+	rawCollations, err := s.q.GetCollations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetCollations: %w", err)
+	}
+
+	var collations []Collation
+	for _, rawCollation := range rawCollations {
+		collations = append(collations, Collation{
+			SchemaQualifiedName: SchemaQualifiedName{
+				SchemaName:  rawCollation.CollationSchemaName,
+				EscapedName: EscapeIdentifier(rawCollation.CollationName),
+			},
+			Provider:        rawCollation.Provider,
+			Locale:          rawCollation.Locale,
+			LcCollate:       rawCollation.LcCollate,
+			LcCtype:         rawCollation.LcCtype,
+			IsDeterministic: rawCollation.IsDeterministic,
+		})
+	}
+
+	collations = filterSliceByName(
+		collations,
+		func(collation Collation) SchemaQualifiedName {
+			return collation.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return collations, nil
+}
+
+func (s *schemaFetcher) fetchTextSearchDictionaries(ctx context.Context) ([]TextSearchDictionary, error) {
+	rawDictionaries, err := s.q.GetTextSearchDictionaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetTextSearchDictionaries: %w", err)
+	}
+
+	var dictionaries []TextSearchDictionary
+	for _, rawDictionary := range rawDictionaries {
+		dictionaries = append(dictionaries, TextSearchDictionary{
+			SchemaQualifiedName: SchemaQualifiedName{
+				SchemaName:  rawDictionary.DictionarySchemaName,
+				EscapedName: EscapeIdentifier(rawDictionary.DictionaryName),
+			},
+			Template: SchemaQualifiedName{
+				SchemaName:  rawDictionary.TemplateSchemaName,
+				EscapedName: EscapeIdentifier(rawDictionary.TemplateName),
+			},
+			Options: rawDictionary.Options,
+		})
+	}
+
+	dictionaries = filterSliceByName(
+		dictionaries,
+		func(dictionary TextSearchDictionary) SchemaQualifiedName {
+			return dictionary.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return dictionaries, nil
+}
+
+func (s *schemaFetcher) fetchTextSearchConfigs(ctx context.Context) ([]TextSearchConfig, error) {
+	rawConfigs, err := s.q.GetTextSearchConfigs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetTextSearchConfigs: %w", err)
+	}
+
+	var configs []TextSearchConfig
+	for _, rawConfig := range rawConfigs {
+		if len(rawConfig.MappingDictionaryNames) != len(rawConfig.MappingTokenTypes) ||
+			len(rawConfig.MappingDictionarySchemaNames) != len(rawConfig.MappingTokenTypes) {
+			return nil, fmt.Errorf("text search config %s has mismatched mapping arrays", rawConfig.ConfigName)
+		}
+
+		// The mapping arrays contain one element per (token type, dictionary) pair, ordered by token type and then by
+		// the order in which the dictionaries are consulted
+		var mappings []TextSearchConfigMapping
+		for i, tokenType := range rawConfig.MappingTokenTypes {
+			if len(mappings) == 0 || mappings[len(mappings)-1].TokenType != tokenType {
+				mappings = append(mappings, TextSearchConfigMapping{TokenType: tokenType})
+			}
+			mapping := &mappings[len(mappings)-1]
+			mapping.Dictionaries = append(mapping.Dictionaries, SchemaQualifiedName{
+				SchemaName:  rawConfig.MappingDictionarySchemaNames[i],
+				EscapedName: EscapeIdentifier(rawConfig.MappingDictionaryNames[i]),
+			})
+		}
+
+		configs = append(configs, TextSearchConfig{
+			SchemaQualifiedName: SchemaQualifiedName{
+				SchemaName:  rawConfig.ConfigSchemaName,
+				EscapedName: EscapeIdentifier(rawConfig.ConfigName),
+			},
+			Parser: SchemaQualifiedName{
+				SchemaName:  rawConfig.ParserSchemaName,
+				EscapedName: EscapeIdentifier(rawConfig.ParserName),
+			},
+			Mappings: mappings,
+		})
+	}
+
+	configs = filterSliceByName(
+		configs,
+		func(config TextSearchConfig) SchemaQualifiedName {
+			return config.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return configs, nil
+}
+
 func (s *schemaFetcher) fetchTables(ctx context.Context) ([]Table, error) {
 	rawTables, err := s.q.GetTables(ctx)
 	if err != nil {
@@ -911,6 +2111,13 @@ func (s *schemaFetcher) fetchTables(ctx context.Context) ([]Table, error) {
 		return nil, fmt.Errorf("getting tables: %w", err)
 	}
 
+	if s.fetchCitusDistributionColumns {
+		tables, err = s.setCitusDistributionColumns(ctx, tables)
+		if err != nil {
+			return nil, fmt.Errorf("setCitusDistributionColumns(): %w", err)
+		}
+	}
+
 	tables = filterSliceByName(
 		tables,
 		func(t Table) SchemaQualifiedName {
@@ -922,6 +2129,26 @@ func (s *schemaFetcher) fetchTables(ctx context.Context) ([]Table, error) {
 	return tables, nil
 }
 
+// setCitusDistributionColumns sets the distribution column of each table that is distributed by Citus
+func (s *schemaFetcher) setCitusDistributionColumns(ctx context.Context, tables []Table) ([]Table, error) {
+	rawDistributedTables, err := s.q.GetCitusDistributedTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetCitusDistributedTables(): %w", err)
+	}
+	distributionColumnsByTableName := make(map[string]string)
+	for _, rawTable := range rawDistributedTables {
+		tableName := buildNameFromUnescaped(rawTable.TableName, rawTable.TableSchemaName)
+		distributionColumnsByTableName[tableName.GetName()] = rawTable.DistributionColumn
+	}
+
+	var tablesWithDistributionColumns []Table
+	for _, table := range tables {
+		table.DistributionColumn = distributionColumnsByTableName[table.GetName()]
+		tablesWithDistributionColumns = append(tablesWithDistributionColumns, table)
+	}
+	return tablesWithDistributionColumns, nil
+}
+
 func (s *schemaFetcher) buildTable(
 	ctx context.Context,
 	table queries.GetTablesRow,
@@ -956,7 +2183,7 @@ func (s *schemaFetcher) buildTable(
 			}
 		}
 
-		columns = append(columns, Column{
+		c := Column{
 			Name:       column.ColumnName,
 			Type:       column.ColumnType,
 			Collation:  collation,
@@ -969,7 +2196,24 @@ func (s *schemaFetcher) buildTable(
 			Default:  column.DefaultValue,
 			Size:     int(column.ColumnSize),
 			Identity: identity,
-		})
+			Comment:  buildComment(column.Comment),
+		}
+		if column.IsGenerated {
+			// The generation expression of a generated column is stored in pg_attrdef, just like a default value
+			c.IsGenerated = true
+			c.GenerationExpression = column.DefaultValue
+			c.Default = ""
+		} else {
+			c.IsDefaultVolatile = column.IsDefaultVolatile
+		}
+		if column.StorageType != column.TypeStorageType {
+			c.StorageType = ColumnStorageType(column.StorageType)
+			c.DefaultStorageType = ColumnStorageType(column.TypeStorageType)
+		}
+		if len(table.InheritsFromNames) > 0 {
+			c.IsInherited = column.IsInherited
+		}
+		columns = append(columns, c)
 	}
 
 	var parentTable *SchemaQualifiedName
@@ -979,6 +2223,14 @@ func (s *schemaFetcher) buildTable(
 			EscapedName: EscapeIdentifier(table.ParentTableName),
 		}
 	}
+	var inheritsFrom []SchemaQualifiedName
+	for i, parentName := range table.InheritsFromNames {
+		inheritsFrom = append(inheritsFrom, buildNameFromUnescaped(parentName, table.InheritsFromSchemaNames[i]))
+	}
+	storageParameters, err := buildStorageParameters(table.StorageParameters, table.ToastStorageParameters)
+	if err != nil {
+		return Table{}, fmt.Errorf("building storage parameters: %w", err)
+	}
 	schemaQualifiedName := SchemaQualifiedName{
 		SchemaName:  table.TableSchemaName,
 		EscapedName: EscapeIdentifier(table.TableName),
@@ -991,14 +2243,64 @@ func (s *schemaFetcher) buildTable(
 		ReplicaIdentity:     ReplicaIdentity(table.ReplicaIdentity),
 		RLSEnabled:          table.RlsEnabled,
 		RLSForced:           table.RlsForced,
+		StorageParameters:   storageParameters,
 
 		PartitionKeyDef: table.PartitionKeyDef,
 
 		ParentTable: parentTable,
 		ForValues:   table.PartitionForValues,
+
+		InheritsFrom: inheritsFrom,
+
+		Tablespace: table.TablespaceName,
+
+		Comment: buildComment(table.Comment),
+		Owner:   s.buildOwner(table.OwnerName),
 	}, nil
 }
 
+// buildComment builds the comment of an object from its description in pg_description. Postgres removes the comment
+// of an object if it is set to an empty string, so an empty description means the object has no comment.
+func buildComment(description string) *string {
+	if len(description) == 0 {
+		return nil
+	}
+	return &description
+}
+
+// buildOwner builds the owner of an object. The owner is empty if owners are not fetched, such that ownership is only
+// diffed if WithOwners is provided.
+func (s *schemaFetcher) buildOwner(ownerName string) string {
+	if !s.includeOwners {
+		return ""
+	}
+	return ownerName
+}
+
+// buildStorageParameters builds the storage parameters of a table from the reloptions of the table and its TOAST
+// table. Reloptions are of the form "key=value".
+func buildStorageParameters(reloptions, toastReloptions []string) (map[string]string, error) {
+	if len(reloptions) == 0 && len(toastReloptions) == 0 {
+		return nil, nil
+	}
+	params := make(map[string]string)
+	for _, option := range reloptions {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return nil, fmt.Errorf("unexpected storage parameter format %q", option)
+		}
+		params[key] = value
+	}
+	for _, option := range toastReloptions {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return nil, fmt.Errorf("unexpected toast storage parameter format %q", option)
+		}
+		params["toast."+key] = value
+	}
+	return params, nil
+}
+
 type checkConstraintAndTable struct {
 	checkConstraint CheckConstraint
 	table           SchemaQualifiedName
@@ -1073,9 +2375,27 @@ func (s *schemaFetcher) fetchIndexes(ctx context.Context) ([]Index, error) {
 		return nil, fmt.Errorf("GetIndexes: %w", err)
 	}
 
+	rawOperatorClasses, err := s.q.GetIndexOperatorClasses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetIndexOperatorClasses: %w", err)
+	}
+	operatorClassesByIndexName := make(map[string][]OperatorClassReference)
+	for _, rawOperatorClass := range rawOperatorClasses {
+		indexName := buildNameFromUnescaped(rawOperatorClass.IndexName, rawOperatorClass.IndexSchemaName).GetName()
+		operatorClassesByIndexName[indexName] = append(operatorClassesByIndexName[indexName], OperatorClassReference{
+			SchemaQualifiedName: buildNameFromUnescaped(rawOperatorClass.OperatorClassName, rawOperatorClass.OperatorClassSchemaName),
+			IndexMethod:         rawOperatorClass.IndexMethod,
+		})
+	}
+
 	var idxs []Index
 	for _, idx := range rawIndexes {
-		idxs = append(idxs, s.buildIndex(idx))
+		index, err := s.buildIndex(idx)
+		if err != nil {
+			return nil, fmt.Errorf("building index %q: %w", idx.IndexName, err)
+		}
+		index.DependsOnOperatorClasses = operatorClassesByIndexName[index.GetSchemaQualifiedName().GetName()]
+		idxs = append(idxs, index)
 	}
 
 	idxs = filterSliceByName(
@@ -1089,7 +2409,7 @@ func (s *schemaFetcher) fetchIndexes(ctx context.Context) ([]Index, error) {
 	return idxs, nil
 }
 
-func (s *schemaFetcher) buildIndex(rawIndex queries.GetIndexesRow) Index {
+func (s *schemaFetcher) buildIndex(rawIndex queries.GetIndexesRow) (Index, error) {
 	var indexConstraint *IndexConstraint
 	if rawIndex.ConstraintName != "" {
 		indexConstraint = &IndexConstraint{
@@ -1097,6 +2417,8 @@ func (s *schemaFetcher) buildIndex(rawIndex queries.GetIndexesRow) Index {
 			EscapedConstraintName: EscapeIdentifier(rawIndex.ConstraintName),
 			ConstraintDef:         rawIndex.ConstraintDef,
 			IsLocal:               rawIndex.ConstraintIsLocal,
+			Deferrable:            rawIndex.ConstraintIsDeferrable,
+			InitiallyDeferred:     rawIndex.ConstraintIsInitiallyDeferred,
 		}
 	}
 
@@ -1108,6 +2430,15 @@ func (s *schemaFetcher) buildIndex(rawIndex queries.GetIndexesRow) Index {
 		}
 	}
 
+	pagesPerRange, autosummarize, err := buildBrinParameters(rawIndex.StorageParameters)
+	if err != nil {
+		return Index{}, err
+	}
+	ginParameters, err := buildGINParameters(rawIndex.StorageParameters)
+	if err != nil {
+		return Index{}, err
+	}
+
 	return Index{
 		OwningTable: SchemaQualifiedName{
 			SchemaName:  rawIndex.TableSchemaName,
@@ -1115,14 +2446,136 @@ func (s *schemaFetcher) buildIndex(rawIndex queries.GetIndexesRow) Index {
 		},
 		Name:            rawIndex.IndexName,
 		Columns:         rawIndex.ColumnNames,
+		IncludedColumns: rawIndex.IncludedColumnNames,
+		Expressions:     rawIndex.Expressions,
 		GetIndexDefStmt: GetIndexDefStatement(rawIndex.DefStmt),
 		IsInvalid:       !rawIndex.IndexIsValid,
 		IsUnique:        rawIndex.IndexIsUnique,
+		Predicate:       rawIndex.Predicate,
+		Method:          rawIndex.IndexMethod,
+		PagesPerRange:   pagesPerRange,
+		Autosummarize:   autosummarize,
+		GINParameters:   ginParameters,
 
 		Constraint: indexConstraint,
 
 		ParentIdx: parentIdx,
+
+		Tablespace: rawIndex.TablespaceName,
+
+		Comment: buildComment(rawIndex.Comment),
+	}, nil
+}
+
+// buildBrinParameters builds the BRIN storage parameters of an index from its reloptions. Reloptions are of the form
+// "key=value". The other storage parameters are only included in the index's definition.
+func buildBrinParameters(reloptions []string) (pagesPerRange *int, autosummarize *bool, err error) {
+	for _, option := range reloptions {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("unexpected storage parameter format %q", option)
+		}
+		switch key {
+		case "pages_per_range":
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parsing pages_per_range %q: %w", value, err)
+			}
+			pagesPerRange = &parsed
+		case "autosummarize":
+			parsed, err := parseBoolReloption(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parsing autosummarize: %w", err)
+			}
+			autosummarize = &parsed
+		}
+	}
+	return pagesPerRange, autosummarize, nil
+}
+
+// buildGINParameters builds the GIN storage parameters of an index from its reloptions. It returns nil if none are set.
+func buildGINParameters(reloptions []string) (map[string]string, error) {
+	var params map[string]string
+	for _, option := range reloptions {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return nil, fmt.Errorf("unexpected storage parameter format %q", option)
+		}
+		switch key {
+		case "fastupdate":
+			fastUpdate, err := parseBoolReloption(value)
+			if err != nil {
+				return nil, fmt.Errorf("parsing fastupdate: %w", err)
+			}
+			value = "off"
+			if fastUpdate {
+				value = "on"
+			}
+		case "gin_pending_list_limit":
+		default:
+			continue
+		}
+		if params == nil {
+			params = make(map[string]string)
+		}
+		params[key] = value
+	}
+	return params, nil
+}
+
+// parseBoolReloption parses a boolean reloption. Reloptions are stored as they were specified, so any of the
+// spellings Postgres accepts for a boolean, e.g., "on", "true", or "1", may be used.
+func parseBoolReloption(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "true", "t", "yes", "y", "1":
+		return true, nil
+	case "off", "false", "f", "no", "n", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", value)
+}
+
+// statisticsKindsByCode maps the codes of pg_statistic_ext.stxkind to the statistics kinds used in CREATE STATISTICS.
+// The "e" kind (expressions) is omitted, since it is implicitly enabled for statistics objects on expressions.
+var statisticsKindsByCode = map[string]string{
+	"d": "ndistinct",
+	"f": "dependencies",
+	"m": "mcv",
+}
+
+func (s *schemaFetcher) fetchStatisticsObjects(ctx context.Context) ([]StatisticsObject, error) {
+	rawStatisticsObjects, err := s.q.GetStatisticsObjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetStatisticsObjects: %w", err)
+	}
+
+	var statisticsObjects []StatisticsObject
+	for _, rawStatisticsObject := range rawStatisticsObjects {
+		var kinds []string
+		for _, code := range rawStatisticsObject.Kinds {
+			if kind, ok := statisticsKindsByCode[code]; ok {
+				kinds = append(kinds, kind)
+			}
+		}
+		statisticsObjects = append(statisticsObjects, StatisticsObject{
+			SchemaQualifiedName: buildNameFromUnescaped(rawStatisticsObject.StatisticsName, rawStatisticsObject.StatisticsSchemaName),
+			Table:               buildNameFromUnescaped(rawStatisticsObject.TableName, rawStatisticsObject.TableSchemaName),
+			Kinds:               kinds,
+			Columns:             rawStatisticsObject.ColumnNames,
+			StatisticsTarget:    int(rawStatisticsObject.StatisticsTarget),
+			Def:                 rawStatisticsObject.Def,
+		})
 	}
+
+	statisticsObjects = filterSliceByName(
+		statisticsObjects,
+		func(statisticsObject StatisticsObject) SchemaQualifiedName {
+			return statisticsObject.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return statisticsObjects, nil
 }
 
 func (s *schemaFetcher) fetchForeignKeyCons(ctx context.Context) ([]ForeignKeyConstraint, error) {
@@ -1143,8 +2596,10 @@ func (s *schemaFetcher) fetchForeignKeyCons(ctx context.Context) ([]ForeignKeyCo
 				SchemaName:  rawFkCon.ForeignTableSchemaName,
 				EscapedName: EscapeIdentifier(rawFkCon.ForeignTableName),
 			},
-			ConstraintDef: rawFkCon.ConstraintDef,
-			IsValid:       rawFkCon.IsValid,
+			ConstraintDef:     rawFkCon.ConstraintDef,
+			IsValid:           rawFkCon.IsValid,
+			Deferrable:        rawFkCon.IsDeferrable,
+			InitiallyDeferred: rawFkCon.IsInitiallyDeferred,
 		})
 	}
 
@@ -1193,6 +2648,7 @@ func (s *schemaFetcher) fetchSequences(ctx context.Context) ([]Sequence, error)
 			MinValue:   rawSeq.MinValue,
 			CacheSize:  rawSeq.CacheSize,
 			Cycle:      rawSeq.IsCycle,
+			OwnerRole:  s.buildOwner(rawSeq.OwnerName),
 		})
 	}
 
@@ -1215,7 +2671,7 @@ func (s *schemaFetcher) fetchViews(ctx context.Context) ([]View, error) {
 	if err != nil {
 		return nil, fmt.Errorf("GetViews: %w", err)
 	}
-	
+
 	var views []View
 	for _, rawView := range rawViews {
 		// Get view dependencies
@@ -1226,10 +2682,11 @@ func (s *schemaFetcher) fetchViews(ctx context.Context) ([]View, error) {
 		if err != nil {
 			return nil, fmt.Errorf("GetViewDependencies(%s.%s): %w", rawView.ViewSchemaName, rawView.ViewName, err)
 		}
-		
+
 		var dependsOnTables []SchemaQualifiedName
 		var dependsOnViews []SchemaQualifiedName
-		
+		var dependsOnForeignTables []SchemaQualifiedName
+
 		for _, dep := range deps {
 			kind, ok := dep.DependsOnKind.(string)
 			if !ok {
@@ -1245,20 +2702,36 @@ func (s *schemaFetcher) fetchViews(ctx context.Context) ([]View, error) {
 					SchemaName:  dep.DependsOnSchemaName,
 					EscapedName: EscapeIdentifier(dep.DependsOnName),
 				})
+			} else if kind == "f" { // 'f' for foreign table
+				dependsOnForeignTables = append(dependsOnForeignTables, SchemaQualifiedName{
+					SchemaName:  dep.DependsOnSchemaName,
+					EscapedName: EscapeIdentifier(dep.DependsOnName),
+				})
 			}
 		}
-		
+
+		dependsOnTables = filterSliceByName(
+			dependsOnTables,
+			func(name SchemaQualifiedName) SchemaQualifiedName {
+				return name
+			},
+			s.dependencyFilter,
+		)
+
 		views = append(views, View{
 			SchemaQualifiedName: SchemaQualifiedName{
 				SchemaName:  rawView.ViewSchemaName,
 				EscapedName: EscapeIdentifier(rawView.ViewName),
 			},
-			Definition:       rawView.ViewDefinition,
-			DependsOnTables: dependsOnTables,
-			DependsOnViews:  dependsOnViews,
+			Definition:             rawView.ViewDefinition,
+			DependsOnTables:        dependsOnTables,
+			DependsOnViews:         dependsOnViews,
+			DependsOnForeignTables: dependsOnForeignTables,
+			Comment:                buildComment(rawView.Comment),
+			Owner:                  s.buildOwner(rawView.OwnerName),
 		})
 	}
-	
+
 	views = filterSliceByName(
 		views,
 		func(view View) SchemaQualifiedName {
@@ -1266,15 +2739,119 @@ func (s *schemaFetcher) fetchViews(ctx context.Context) ([]View, error) {
 		},
 		s.nameFilter,
 	)
-	
+
 	return views, nil
 }
 
+func (s *schemaFetcher) fetchMaterializedViews(ctx context.Context) ([]MaterializedView, error) {
+	rawMaterializedViews, err := s.q.GetMaterializedViews(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetMaterializedViews: %w", err)
+	}
+
+	var materializedViews []MaterializedView
+	for _, rawMaterializedView := range rawMaterializedViews {
+		deps, err := s.q.GetViewDependencies(ctx, queries.GetViewDependenciesParams{
+			Relname: rawMaterializedView.MaterializedViewName,
+			Nspname: rawMaterializedView.MaterializedViewSchemaName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("GetViewDependencies(%s.%s): %w", rawMaterializedView.MaterializedViewSchemaName, rawMaterializedView.MaterializedViewName, err)
+		}
+
+		var dependsOnTables, dependsOnViews, dependsOnMaterializedViews []SchemaQualifiedName
+		for _, dep := range deps {
+			kind, ok := dep.DependsOnKind.(string)
+			if !ok {
+				continue
+			}
+			name := SchemaQualifiedName{
+				SchemaName:  dep.DependsOnSchemaName,
+				EscapedName: EscapeIdentifier(dep.DependsOnName),
+			}
+			switch kind {
+			case "r":
+				dependsOnTables = append(dependsOnTables, name)
+			case "v":
+				dependsOnViews = append(dependsOnViews, name)
+			case "m":
+				dependsOnMaterializedViews = append(dependsOnMaterializedViews, name)
+			}
+		}
+
+		dependsOnTables = filterSliceByName(
+			dependsOnTables,
+			func(name SchemaQualifiedName) SchemaQualifiedName {
+				return name
+			},
+			s.dependencyFilter,
+		)
+
+		storageParameters, err := buildStorageParameters(rawMaterializedView.StorageParameters, rawMaterializedView.ToastStorageParameters)
+		if err != nil {
+			return nil, fmt.Errorf("building storage parameters: %w", err)
+		}
+
+		materializedViews = append(materializedViews, MaterializedView{
+			SchemaQualifiedName: SchemaQualifiedName{
+				SchemaName:  rawMaterializedView.MaterializedViewSchemaName,
+				EscapedName: EscapeIdentifier(rawMaterializedView.MaterializedViewName),
+			},
+			Definition:                 rawMaterializedView.MaterializedViewDefinition,
+			StorageParameters:          storageParameters,
+			DependsOnTables:            dependsOnTables,
+			DependsOnViews:             dependsOnViews,
+			DependsOnMaterializedViews: dependsOnMaterializedViews,
+		})
+	}
+
+	materializedViews = filterSliceByName(
+		materializedViews,
+		func(materializedView MaterializedView) SchemaQualifiedName {
+			return materializedView.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return materializedViews, nil
+}
+
+// moveIndexesToMaterializedViews moves the indexes that are owned by materialized views from the indexes into
+// their materialized views. Unlike the indexes of tables, the indexes of materialized views are migrated alongside the
+// materialized view.
+func moveIndexesToMaterializedViews(materializedViews []MaterializedView, indexes []Index) ([]MaterializedView, []Index) {
+	indexesByOwner := make(map[string][]Index)
+	for _, index := range indexes {
+		indexesByOwner[index.OwningTable.GetName()] = append(indexesByOwner[index.OwningTable.GetName()], index)
+	}
+
+	var updatedMaterializedViews []MaterializedView
+	for _, mv := range materializedViews {
+		mv.Indexes = indexesByOwner[mv.GetName()]
+		delete(indexesByOwner, mv.GetName())
+		updatedMaterializedViews = append(updatedMaterializedViews, mv)
+	}
+
+	var tableIndexes []Index
+	for _, index := range indexes {
+		if _, ok := indexesByOwner[index.OwningTable.GetName()]; ok {
+			tableIndexes = append(tableIndexes, index)
+		}
+	}
+	return updatedMaterializedViews, tableIndexes
+}
+
 func (s *schemaFetcher) fetchFunctions(ctx context.Context) ([]Function, error) {
 	rawFunctions, err := s.q.GetProcs(ctx, "f")
 	if err != nil {
 		return nil, fmt.Errorf("GetProcs: %w", err)
 	}
+	// Window functions are regular functions with the WINDOW attribute, but Postgres tracks them as their own kind
+	rawWindowFunctions, err := s.q.GetProcs(ctx, "w")
+	if err != nil {
+		return nil, fmt.Errorf("GetProcs: %w", err)
+	}
+	rawFunctions = append(rawFunctions, rawWindowFunctions...)
 
 	goroutineRunner := s.goroutineRunnerFactory()
 	var functionFutures []concurrent.Future[Function]
@@ -1316,7 +2893,7 @@ func (s *schemaFetcher) buildFunction(ctx context.Context, rawFunction queries.G
 	if err != nil {
 		return Function{}, fmt.Errorf("GetFunctionTableDependencies(%s): %w", rawFunction.Oid, err)
 	}
-	
+
 	var dependsOnTables []SchemaQualifiedName
 	for _, dep := range tableDeps {
 		dependsOnTables = append(dependsOnTables, SchemaQualifiedName{
@@ -1324,13 +2901,23 @@ func (s *schemaFetcher) buildFunction(ctx context.Context, rawFunction queries.G
 			EscapedName: EscapeIdentifier(dep.DependsOnTableName),
 		})
 	}
+	dependsOnTables = filterSliceByName(
+		dependsOnTables,
+		func(name SchemaQualifiedName) SchemaQualifiedName {
+			return name
+		},
+		s.dependencyFilter,
+	)
 
 	fn := Function{
 		SchemaQualifiedName: buildProcName(rawFunction.FuncName, rawFunction.FuncIdentityArguments, rawFunction.FuncSchemaName),
 		FunctionDef:         rawFunction.FuncDef,
 		Language:            rawFunction.FuncLang,
 		DependsOnFunctions:  dependsOnFunctions,
+		IsWindow:            rawFunction.IsWindow,
 		DependsOnTables:     dependsOnTables,
+		Comment:             buildComment(rawFunction.Comment),
+		Owner:               s.buildOwner(rawFunction.OwnerName),
 	}
 
 	// For SQL functions, parse the body to extract column references
@@ -1355,6 +2942,14 @@ func (s *schemaFetcher) fetchDependsOnFunctions(ctx context.Context, systemCatal
 		functionNames = append(functionNames, buildProcName(rawFunction.FuncName, rawFunction.FuncIdentityArguments, rawFunction.FuncSchemaName))
 	}
 
+	functionNames = filterSliceByName(
+		functionNames,
+		func(name SchemaQualifiedName) SchemaQualifiedName {
+			return name
+		},
+		s.dependencyFilter,
+	)
+
 	return functionNames, nil
 }
 
@@ -1366,9 +2961,16 @@ func (s *schemaFetcher) fetchProcedures(ctx context.Context) ([]Procedure, error
 
 	var procedures []Procedure
 	for _, rawProcedure := range rawProcedures {
+		dependsOnFunctions, err := s.fetchDependsOnFunctions(ctx, "pg_proc", rawProcedure.Oid)
+		if err != nil {
+			return nil, fmt.Errorf("fetchDependsOnFunctions(%s): %w", rawProcedure.Oid, err)
+		}
+
 		p := Procedure{
 			SchemaQualifiedName: buildProcName(rawProcedure.FuncName, rawProcedure.FuncIdentityArguments, rawProcedure.FuncSchemaName),
 			Def:                 rawProcedure.FuncDef,
+			Language:            rawProcedure.FuncLang,
+			DependsOnFunctions:  dependsOnFunctions,
 		}
 		procedures = append(procedures, p)
 	}
@@ -1384,6 +2986,57 @@ func (s *schemaFetcher) fetchProcedures(ctx context.Context) ([]Procedure, error
 	return procedures, nil
 }
 
+func (s *schemaFetcher) fetchAggregates(ctx context.Context) ([]Aggregate, error) {
+	rawAggregates, err := s.q.GetAggregates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetAggregates: %w", err)
+	}
+
+	var aggregates []Aggregate
+	for _, rawAggregate := range rawAggregates {
+		aggregate := Aggregate{
+			SchemaQualifiedName: buildProcName(rawAggregate.AggregateName, rawAggregate.AggregateIdentityArguments, rawAggregate.AggregateSchemaName),
+			InputTypes:          rawAggregate.AggregateIdentityArguments,
+			StateType:           rawAggregate.StateType,
+			StateFunction:       buildNameFromUnescaped(rawAggregate.StateFuncName, rawAggregate.StateFuncSchemaName),
+			FinalFunctionExtra:  rawAggregate.FinalFuncExtra,
+			Parallel:            rawAggregate.Parallel,
+			DependsOnFunctions: []SchemaQualifiedName{
+				buildProcName(rawAggregate.StateFuncName, rawAggregate.StateFuncIdentityArguments, rawAggregate.StateFuncSchemaName),
+			},
+		}
+		// supportFunction returns the name of an optional support function and records the dependency on it. It
+		// returns nil if the function is not set.
+		supportFunction := func(name, identityArguments, schemaName string) *SchemaQualifiedName {
+			if len(name) == 0 {
+				return nil
+			}
+			aggregate.DependsOnFunctions = append(aggregate.DependsOnFunctions, buildProcName(name, identityArguments, schemaName))
+			fn := buildNameFromUnescaped(name, schemaName)
+			return &fn
+		}
+		aggregate.FinalFunction = supportFunction(rawAggregate.FinalFuncName, rawAggregate.FinalFuncIdentityArguments, rawAggregate.FinalFuncSchemaName)
+		aggregate.CombineFunction = supportFunction(rawAggregate.CombineFuncName, rawAggregate.CombineFuncIdentityArguments, rawAggregate.CombineFuncSchemaName)
+		aggregate.SerialFunction = supportFunction(rawAggregate.SerialFuncName, rawAggregate.SerialFuncIdentityArguments, rawAggregate.SerialFuncSchemaName)
+		aggregate.DeserialFunction = supportFunction(rawAggregate.DeserialFuncName, rawAggregate.DeserialFuncIdentityArguments, rawAggregate.DeserialFuncSchemaName)
+		if rawAggregate.HasInitialCondition {
+			initialCondition := rawAggregate.InitialCondition
+			aggregate.InitialCondition = &initialCondition
+		}
+		aggregates = append(aggregates, aggregate)
+	}
+
+	aggregates = filterSliceByName(
+		aggregates,
+		func(aggregate Aggregate) SchemaQualifiedName {
+			return aggregate.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return aggregates, nil
+}
+
 type policyAndTable struct {
 	policy Policy
 	table  SchemaQualifiedName
@@ -1430,7 +3083,7 @@ func (s *schemaFetcher) fetchEventTriggers(ctx context.Context) ([]EventTrigger,
 	if err != nil {
 		return nil, fmt.Errorf("GetEventTriggers: %w", err)
 	}
-	
+
 	var eventTriggers []EventTrigger
 	for _, rawET := range rawEventTriggers {
 		// Parse function name to get schema and name
@@ -1446,12 +3099,12 @@ func (s *schemaFetcher) fetchEventTriggers(ctx context.Context) ([]EventTrigger,
 		}
 		// Remove parentheses if present
 		funcName = strings.TrimSuffix(funcName, "()")
-		
+
 		enabled, ok := rawET.Enabled.(string)
 		if !ok {
 			enabled = "O" // Default to enabled
 		}
-		
+
 		eventTriggers = append(eventTriggers, EventTrigger{
 			Name:  rawET.EventTriggerName,
 			Event: rawET.Event,
@@ -1463,7 +3116,7 @@ func (s *schemaFetcher) fetchEventTriggers(ctx context.Context) ([]EventTrigger,
 			Tags:    rawET.Tags,
 		})
 	}
-	
+
 	return eventTriggers, nil
 }
 
@@ -1497,6 +3150,403 @@ func (s *schemaFetcher) fetchTriggers(ctx context.Context) ([]Trigger, error) {
 	return triggers, nil
 }
 
+func (s *schemaFetcher) fetchOperators(ctx context.Context) ([]Operator, error) {
+	rawOperators, err := s.q.GetOperators(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetOperators: %w", err)
+	}
+
+	var operators []Operator
+	for _, rawOperator := range rawOperators {
+		name := buildOperatorName(rawOperator.OperatorName, rawOperator.LeftType, rawOperator.RightType, rawOperator.OperatorSchemaName)
+		function := buildProcName(rawOperator.FuncName, rawOperator.FuncIdentityArguments, rawOperator.FuncSchemaName)
+
+		var options []string
+		options = append(options, fmt.Sprintf("FUNCTION = %s", buildNameFromUnescaped(rawOperator.FuncName, rawOperator.FuncSchemaName).GetFQEscapedName()))
+		if len(rawOperator.LeftType) > 0 {
+			options = append(options, fmt.Sprintf("LEFTARG = %s", rawOperator.LeftType))
+		}
+		if len(rawOperator.RightType) > 0 {
+			options = append(options, fmt.Sprintf("RIGHTARG = %s", rawOperator.RightType))
+		}
+		if len(rawOperator.CommutatorName) > 0 {
+			options = append(options, fmt.Sprintf("COMMUTATOR = OPERATOR(%s.%s)", EscapeIdentifier(rawOperator.CommutatorSchemaName), rawOperator.CommutatorName))
+		}
+		if len(rawOperator.NegatorName) > 0 {
+			options = append(options, fmt.Sprintf("NEGATOR = OPERATOR(%s.%s)", EscapeIdentifier(rawOperator.NegatorSchemaName), rawOperator.NegatorName))
+		}
+		if len(rawOperator.RestrictFunc) > 0 {
+			options = append(options, fmt.Sprintf("RESTRICT = %s", rawOperator.RestrictFunc))
+		}
+		if len(rawOperator.JoinFunc) > 0 {
+			options = append(options, fmt.Sprintf("JOIN = %s", rawOperator.JoinFunc))
+		}
+		if rawOperator.CanHash {
+			options = append(options, "HASHES")
+		}
+		if rawOperator.CanMerge {
+			options = append(options, "MERGES")
+		}
+
+		operators = append(operators, Operator{
+			SchemaQualifiedName: name,
+			Function:            function,
+			Def: fmt.Sprintf("CREATE OPERATOR %s.%s (\n\t%s\n)",
+				EscapeIdentifier(rawOperator.OperatorSchemaName),
+				rawOperator.OperatorName,
+				strings.Join(options, ",\n\t"),
+			),
+		})
+	}
+
+	operators = filterSliceByName(
+		operators,
+		func(operator Operator) SchemaQualifiedName {
+			return operator.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return operators, nil
+}
+
+func (s *schemaFetcher) fetchOperatorClasses(ctx context.Context) ([]OperatorClass, error) {
+	rawOperatorClasses, err := s.q.GetOperatorClasses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetOperatorClasses: %w", err)
+	}
+
+	var operatorClasses []OperatorClass
+	for _, rawOperatorClass := range rawOperatorClasses {
+		rawOperators, err := s.q.GetOperatorClassOperators(ctx, rawOperatorClass.Oid)
+		if err != nil {
+			return nil, fmt.Errorf("GetOperatorClassOperators(%s): %w", rawOperatorClass.Oid, err)
+		}
+		rawFunctions, err := s.q.GetOperatorClassFunctions(ctx, rawOperatorClass.Oid)
+		if err != nil {
+			return nil, fmt.Errorf("GetOperatorClassFunctions(%s): %w", rawOperatorClass.Oid, err)
+		}
+
+		var items []string
+		var dependsOnOperators []SchemaQualifiedName
+		for _, rawOperator := range rawOperators {
+			operator := buildOperatorName(rawOperator.OperatorName, rawOperator.LeftType, rawOperator.RightType, rawOperator.OperatorSchemaName)
+			items = append(items, fmt.Sprintf("OPERATOR %d %s", rawOperator.StrategyNumber, operator.GetFQEscapedName()))
+			dependsOnOperators = append(dependsOnOperators, operator)
+		}
+		var dependsOnFunctions []SchemaQualifiedName
+		for _, rawFunction := range rawFunctions {
+			function := buildProcName(rawFunction.FuncName, rawFunction.FuncIdentityArguments, rawFunction.FuncSchemaName)
+			items = append(items, fmt.Sprintf("FUNCTION %d %s", rawFunction.SupportNumber, function.GetFQEscapedName()))
+			dependsOnFunctions = append(dependsOnFunctions, function)
+		}
+		if len(rawOperatorClass.StorageType) > 0 {
+			items = append(items, fmt.Sprintf("STORAGE %s", rawOperatorClass.StorageType))
+		}
+
+		name := buildNameFromUnescaped(rawOperatorClass.OperatorClassName, rawOperatorClass.OperatorClassSchemaName)
+		defaultModifier := ""
+		if rawOperatorClass.IsDefault {
+			defaultModifier = " DEFAULT"
+		}
+		operatorClasses = append(operatorClasses, OperatorClass{
+			SchemaQualifiedName: name,
+			IndexMethod:         rawOperatorClass.IndexMethod,
+			DependsOnOperators:  dependsOnOperators,
+			DependsOnFunctions:  dependsOnFunctions,
+			Def: fmt.Sprintf("CREATE OPERATOR CLASS %s%s FOR TYPE %s USING %s AS\n\t%s",
+				name.GetFQEscapedName(),
+				defaultModifier,
+				rawOperatorClass.InputType,
+				rawOperatorClass.IndexMethod,
+				strings.Join(items, ",\n\t"),
+			),
+		})
+	}
+
+	operatorClasses = filterSliceByName(
+		operatorClasses,
+		func(operatorClass OperatorClass) SchemaQualifiedName {
+			return operatorClass.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return operatorClasses, nil
+}
+
+func (s *schemaFetcher) fetchPublications(ctx context.Context) ([]Publication, error) {
+	rawPublications, err := s.q.GetPublications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetPublications: %w", err)
+	}
+
+	var publications []Publication
+	for _, rawPublication := range rawPublications {
+		rawTables, err := s.q.GetPublicationTables(ctx, rawPublication.Oid)
+		if err != nil {
+			return nil, fmt.Errorf("GetPublicationTables(%s): %w", rawPublication.Oid, err)
+		}
+		var tables []SchemaQualifiedName
+		for _, rawTable := range rawTables {
+			tables = append(tables, buildNameFromUnescaped(rawTable.TableName, rawTable.TableSchemaName))
+		}
+		// Publications are not scoped to a schema, so only their membership is filtered
+		tables = filterSliceByName(tables, func(table SchemaQualifiedName) SchemaQualifiedName {
+			return table
+		}, s.nameFilter)
+
+		var publish []string
+		for _, op := range []struct {
+			name        string
+			isPublished bool
+		}{
+			{name: "insert", isPublished: rawPublication.PublishInsert},
+			{name: "update", isPublished: rawPublication.PublishUpdate},
+			{name: "delete", isPublished: rawPublication.PublishDelete},
+			{name: "truncate", isPublished: rawPublication.PublishTruncate},
+		} {
+			if op.isPublished {
+				publish = append(publish, op.name)
+			}
+		}
+
+		publications = append(publications, Publication{
+			Name:      rawPublication.PublicationName,
+			AllTables: rawPublication.AllTables,
+			Tables:    tables,
+			Publish:   publish,
+		})
+	}
+
+	return publications, nil
+}
+
+func (s *schemaFetcher) fetchPrivileges(ctx context.Context) ([]Privilege, error) {
+	if !s.includePrivileges {
+		return nil, nil
+	}
+
+	rawPrivileges, err := s.q.GetPrivileges(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetPrivileges: %w", err)
+	}
+
+	var privileges []Privilege
+	for _, rawPrivilege := range rawPrivileges {
+		object := buildNameFromUnescaped(rawPrivilege.ObjectName, rawPrivilege.ObjectSchemaName)
+		if rawPrivilege.ObjectType == "FUNCTION" || rawPrivilege.ObjectType == "PROCEDURE" {
+			object = buildProcName(rawPrivilege.ObjectName, rawPrivilege.ObjectIdentityArguments, rawPrivilege.ObjectSchemaName)
+		}
+		privileges = append(privileges, Privilege{
+			ObjectType:  rawPrivilege.ObjectType,
+			Object:      object,
+			Grantee:     rawPrivilege.Grantee,
+			Type:        rawPrivilege.PrivilegeType,
+			IsGrantable: rawPrivilege.IsGrantable,
+		})
+	}
+
+	privileges = filterSliceByName(
+		privileges,
+		func(privilege Privilege) SchemaQualifiedName {
+			return privilege.Object
+		},
+		s.nameFilter,
+	)
+
+	return privileges, nil
+}
+
+func (s *schemaFetcher) fetchDefaultPrivileges(ctx context.Context) ([]DefaultPrivilege, error) {
+	if !s.includePrivileges {
+		return nil, nil
+	}
+
+	rawDefaultPrivileges, err := s.q.GetDefaultPrivileges(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetDefaultPrivileges: %w", err)
+	}
+
+	var defaultPrivileges []DefaultPrivilege
+	for _, rawDefaultPrivilege := range rawDefaultPrivileges {
+		defaultPrivilege := DefaultPrivilege{
+			Grantor:     rawDefaultPrivilege.Grantor,
+			SchemaName:  rawDefaultPrivilege.SchemaName,
+			ObjectType:  rawDefaultPrivilege.ObjectType,
+			Grantee:     rawDefaultPrivilege.Grantee,
+			Type:        rawDefaultPrivilege.PrivilegeType,
+			IsGrantable: rawDefaultPrivilege.IsGrantable,
+		}
+		// Default privileges that apply to all schemas are not scoped to a schema, so they are not filtered
+		if len(defaultPrivilege.SchemaName) > 0 && !s.nameFilter(SchemaQualifiedName{
+			SchemaName:  defaultPrivilege.SchemaName,
+			EscapedName: EscapeIdentifier(defaultPrivilege.SchemaName),
+		}) {
+			continue
+		}
+		defaultPrivileges = append(defaultPrivileges, defaultPrivilege)
+	}
+
+	return defaultPrivileges, nil
+}
+
+func (s *schemaFetcher) fetchForeignDataWrappers(ctx context.Context) ([]ForeignDataWrapper, error) {
+	rawWrappers, err := s.q.GetForeignDataWrappers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetForeignDataWrappers: %w", err)
+	}
+
+	var wrappers []ForeignDataWrapper
+	for _, rawWrapper := range rawWrappers {
+		options, err := buildForeignOptions(rawWrapper.Options)
+		if err != nil {
+			return nil, fmt.Errorf("building options of foreign data wrapper %s: %w", rawWrapper.WrapperName, err)
+		}
+
+		var handler SchemaQualifiedName
+		if len(rawWrapper.HandlerName) > 0 {
+			handler = buildNameFromUnescaped(rawWrapper.HandlerName, rawWrapper.HandlerSchemaName)
+		}
+		var validator SchemaQualifiedName
+		if len(rawWrapper.ValidatorName) > 0 {
+			validator = buildNameFromUnescaped(rawWrapper.ValidatorName, rawWrapper.ValidatorSchemaName)
+		}
+
+		wrappers = append(wrappers, ForeignDataWrapper{
+			Name:      rawWrapper.WrapperName,
+			Handler:   handler,
+			Validator: validator,
+			Options:   options,
+		})
+	}
+
+	return wrappers, nil
+}
+
+func (s *schemaFetcher) fetchForeignServers(ctx context.Context) ([]ForeignServer, error) {
+	rawServers, err := s.q.GetForeignServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetForeignServers: %w", err)
+	}
+
+	var servers []ForeignServer
+	for _, rawServer := range rawServers {
+		options, err := buildForeignOptions(rawServer.Options)
+		if err != nil {
+			return nil, fmt.Errorf("building options of foreign server %s: %w", rawServer.ServerName, err)
+		}
+		servers = append(servers, ForeignServer{
+			Name:               rawServer.ServerName,
+			ForeignDataWrapper: rawServer.WrapperName,
+			Type:               rawServer.ServerType,
+			Version:            rawServer.ServerVersion,
+			Options:            options,
+		})
+	}
+
+	return servers, nil
+}
+
+func (s *schemaFetcher) fetchForeignTables(ctx context.Context) ([]ForeignTable, error) {
+	rawForeignTables, err := s.q.GetForeignTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetForeignTables: %w", err)
+	}
+
+	var foreignTables []ForeignTable
+	for _, rawForeignTable := range rawForeignTables {
+		rawColumns, err := s.q.GetColumnsForTable(ctx, rawForeignTable.Oid)
+		if err != nil {
+			return nil, fmt.Errorf("GetColumnsForTable(%s): %w", rawForeignTable.Oid, err)
+		}
+		var columns []Column
+		for _, column := range rawColumns {
+			collation := SchemaQualifiedName{}
+			if len(column.CollationName) > 0 {
+				collation = SchemaQualifiedName{
+					EscapedName: EscapeIdentifier(column.CollationName),
+					SchemaName:  column.CollationSchemaName,
+				}
+			}
+			columns = append(columns, Column{
+				Name:       column.ColumnName,
+				Type:       column.ColumnType,
+				Collation:  collation,
+				IsNullable: !column.IsNotNull,
+				Default:    column.DefaultValue,
+				Size:       int(column.ColumnSize),
+			})
+		}
+
+		options, err := buildForeignOptions(rawForeignTable.Options)
+		if err != nil {
+			return nil, fmt.Errorf("building options of foreign table %s: %w", rawForeignTable.TableName, err)
+		}
+
+		foreignTables = append(foreignTables, ForeignTable{
+			SchemaQualifiedName: buildNameFromUnescaped(rawForeignTable.TableName, rawForeignTable.TableSchemaName),
+			Columns:             columns,
+			Server:              rawForeignTable.ServerName,
+			Options:             options,
+		})
+	}
+
+	foreignTables = filterSliceByName(
+		foreignTables,
+		func(foreignTable ForeignTable) SchemaQualifiedName {
+			return foreignTable.SchemaQualifiedName
+		},
+		s.nameFilter,
+	)
+
+	return foreignTables, nil
+}
+
+// buildForeignOptions builds the options of a foreign-data wrapper, foreign server, or foreign table from their
+// "key=value" representation in the catalog
+func buildForeignOptions(rawOptions []string) (map[string]string, error) {
+	if len(rawOptions) == 0 {
+		return nil, nil
+	}
+	options := make(map[string]string)
+	for _, option := range rawOptions {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return nil, fmt.Errorf("unexpected option format %q", option)
+		}
+		options[key] = value
+	}
+	return options, nil
+}
+
+func (s *schemaFetcher) fetchOwners(ctx context.Context) ([]string, error) {
+	rawOwners, err := s.q.GetObjectOwners(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetObjectOwners: %w", err)
+	}
+
+	rawOwners = filterSliceByName(
+		rawOwners,
+		func(rawOwner queries.GetObjectOwnersRow) SchemaQualifiedName {
+			return buildNameFromUnescaped(rawOwner.ObjectName, rawOwner.ObjectSchemaName)
+		},
+		s.nameFilter,
+	)
+
+	seenOwners := make(map[string]bool)
+	var owners []string
+	for _, rawOwner := range rawOwners {
+		if seenOwners[rawOwner.OwnerName] {
+			continue
+		}
+		seenOwners[rawOwner.OwnerName] = true
+		owners = append(owners, rawOwner.OwnerName)
+	}
+
+	return owners, nil
+}
+
 // buildProcName is used to build the schema qualified name for a proc (function, procedure), i.e., anything
 // identified by a name AND its arguments.
 func buildProcName(name, identityArguments, schemaName string) SchemaQualifiedName {
@@ -1506,6 +3556,22 @@ func buildProcName(name, identityArguments, schemaName string) SchemaQualifiedNa
 	}
 }
 
+// buildOperatorName is used to build the schema qualified name for an operator. Similar to procs, operators are
+// identified by their name AND their argument types. Operator names are never quoted. Prefix operators do not have
+// a left argument, which is denoted by NONE.
+func buildOperatorName(name, leftType, rightType, schemaName string) SchemaQualifiedName {
+	if len(leftType) == 0 {
+		leftType = "NONE"
+	}
+	if len(rightType) == 0 {
+		rightType = "NONE"
+	}
+	return SchemaQualifiedName{
+		SchemaName:  schemaName,
+		EscapedName: fmt.Sprintf("%s(%s, %s)", name, leftType, rightType),
+	}
+}
+
 func buildNameFromUnescaped(unescapedName, schemaName string) SchemaQualifiedName {
 	return SchemaQualifiedName{
 		EscapedName: EscapeIdentifier(unescapedName),
@@ -1526,7 +3592,7 @@ func EscapeIdentifier(name string) string {
 func extractColumnReferences(functionDef string) []TableColumnRef {
 	var refs []TableColumnRef
 	seen := make(map[string]bool)
-	
+
 	// Extract the function body from the CREATE FUNCTION statement
 	// Look for content between AS $function$ ... $function$ or AS $$ ... $$
 	bodyRe := regexp.MustCompile(`(?is)AS\s+\$[^$]*\$(.*)\$[^$]*\$`)
@@ -1534,21 +3600,21 @@ func extractColumnReferences(functionDef string) []TableColumnRef {
 	if len(matches) < 2 {
 		return refs
 	}
-	
+
 	body := strings.TrimSpace(matches[1])
-	
+
 	// Parse the function body using pg_query
 	result, err := pg_query.Parse(body)
 	if err != nil {
 		// If parsing fails, fall back to regex-based approach
 		return extractColumnReferencesRegex(body)
 	}
-	
+
 	// Walk through the parse tree to find column references
 	for _, stmt := range result.Stmts {
 		extractRefsFromNode(stmt.Stmt, &refs, &seen)
 	}
-	
+
 	return refs
 }
 
@@ -1557,14 +3623,14 @@ func extractRefsFromNode(node *pg_query.Node, refs *[]TableColumnRef, seen *map[
 	if node == nil {
 		return
 	}
-	
+
 	// First, check all possible node types that might contain other nodes
 	// This ensures we don't miss any nested structures
-	
+
 	// Handle ColumnRef nodes - these represent column references
 	if colRef := node.GetColumnRef(); colRef != nil {
 		var tableName, columnName string
-		
+
 		// ColumnRef fields is a list that can be:
 		// - [column] for unqualified column reference
 		// - [table, column] for qualified reference
@@ -1577,7 +3643,7 @@ func extractRefsFromNode(node *pg_query.Node, refs *[]TableColumnRef, seen *map[
 			if colNode := colRef.Fields[1].GetString_(); colNode != nil {
 				columnName = colNode.Sval
 			}
-			
+
 			if tableName != "" && columnName != "" {
 				key := tableName + "." + columnName
 				if !(*seen)[key] {
@@ -1591,32 +3657,32 @@ func extractRefsFromNode(node *pg_query.Node, refs *[]TableColumnRef, seen *map[
 		}
 		return // ColumnRef is a leaf node
 	}
-	
+
 	// Handle SelectStmt
 	if selectStmt := node.GetSelectStmt(); selectStmt != nil {
 		// Process target list
 		for _, target := range selectStmt.TargetList {
 			extractRefsFromNode(target, refs, seen)
 		}
-		
+
 		// Process FROM clause
 		for _, from := range selectStmt.FromClause {
 			extractRefsFromNode(from, refs, seen)
 		}
-		
+
 		// Process WHERE clause
 		if selectStmt.WhereClause != nil {
 			extractRefsFromNode(selectStmt.WhereClause, refs, seen)
 		}
 	}
-	
+
 	// Handle ResTarget (result target in SELECT list)
 	if resTarget := node.GetResTarget(); resTarget != nil {
 		if resTarget.Val != nil {
 			extractRefsFromNode(resTarget.Val, refs, seen)
 		}
 	}
-	
+
 	// Handle A_Expr (expressions)
 	if aExpr := node.GetAExpr(); aExpr != nil {
 		if aExpr.Lexpr != nil {
@@ -1626,34 +3692,34 @@ func extractRefsFromNode(node *pg_query.Node, refs *[]TableColumnRef, seen *map[
 			extractRefsFromNode(aExpr.Rexpr, refs, seen)
 		}
 	}
-	
+
 	// Handle FuncCall (function calls)
 	if funcCall := node.GetFuncCall(); funcCall != nil {
 		for _, arg := range funcCall.Args {
 			extractRefsFromNode(arg, refs, seen)
 		}
 	}
-	
+
 	// Handle CoalesceExpr
 	if coalesceExpr := node.GetCoalesceExpr(); coalesceExpr != nil {
 		for _, arg := range coalesceExpr.Args {
 			extractRefsFromNode(arg, refs, seen)
 		}
 	}
-	
+
 	// Handle List nodes (generic lists)
 	if list := node.GetList(); list != nil {
 		for _, item := range list.Items {
 			extractRefsFromNode(item, refs, seen)
 		}
 	}
-	
+
 	// Handle RangeVar (table references in FROM clause)
 	if rangeVar := node.GetRangeVar(); rangeVar != nil {
 		// Track the table name for context
 		// Note: We'd need more sophisticated tracking to handle aliases
 	}
-	
+
 	// Handle JoinExpr
 	if joinExpr := node.GetJoinExpr(); joinExpr != nil {
 		if joinExpr.Larg != nil {
@@ -1672,20 +3738,20 @@ func extractRefsFromNode(node *pg_query.Node, refs *[]TableColumnRef, seen *map[
 func extractColumnReferencesRegex(body string) []TableColumnRef {
 	var refs []TableColumnRef
 	seen := make(map[string]bool)
-	
+
 	// Pattern to match table.column references
 	columnRefRe := regexp.MustCompile(`\b(\w+)\.(\w+)\b`)
-	
+
 	for _, match := range columnRefRe.FindAllStringSubmatch(body, -1) {
 		if len(match) >= 3 {
 			tableName := match[1]
 			columnName := match[2]
-			
+
 			// Skip some common false positives
 			if tableName == "pg_catalog" || tableName == "information_schema" {
 				continue
 			}
-			
+
 			key := tableName + "." + columnName
 			if !seen[key] {
 				seen[key] = true
@@ -1696,6 +3762,6 @@ func extractColumnReferencesRegex(body string) []TableColumnRef {
 			}
 		}
 	}
-	
+
 	return refs
 }
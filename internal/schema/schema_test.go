@@ -34,12 +34,15 @@ var (
 		EscapedName: `"C"`,
 		SchemaName:  "pg_catalog",
 	}
+	brinPagesPerRange = 16
+	brinAutosummarize = true
 
 	testCases = []*testCase{
 		{
 			name: "Simple schema (validate all schema objects and schema name filters)",
 			opts: []GetSchemaOpt{
 				WithIncludeSchemas("public", "schema_1", "schema_2"),
+				WithPrivileges(),
 			},
 			ddl: []string{`
 			CREATE SCHEMA schema_1;
@@ -56,6 +59,13 @@ var (
 			-- Validate types are filtered out
 			CREATE TYPE schema_filtered_1.foobar_enum AS ENUM ('foobar_1', 'foobar_2');		
 
+			CREATE DOMAIN positive_int AS INT DEFAULT 1 NOT NULL CONSTRAINT positive_int_check CHECK (VALUE > 0);
+			-- Validate domains are filtered out
+			CREATE DOMAIN schema_filtered_1.positive_int AS INT;
+			CREATE TYPE foobar_address AS (street TEXT COLLATE "C", zip INT, kind foobar_enum);
+			-- Validate composite types are filtered out
+			CREATE TYPE schema_filtered_1.foobar_address AS (street TEXT);
+
 			CREATE SEQUENCE schema_1.foobar_sequence
 			    AS BIGINT
 				INCREMENT BY 2
@@ -103,7 +113,10 @@ var (
 			ALTER TABLE schema_2.foo ADD CONSTRAINT author_content_check CHECK ( LENGTH(content) > 0 AND LENGTH(author) > 0 ) NO INHERIT NOT VALID;
 			CREATE INDEX some_idx ON schema_2.foo (created_at DESC, author ASC);
 			CREATE UNIQUE INDEX some_unique_idx ON schema_2.foo (content);
-			CREATE INDEX some_gin_idx ON schema_2.foo USING GIN (author schema_1.gin_trgm_ops);
+			CREATE INDEX some_gin_idx ON schema_2.foo USING GIN (author schema_1.gin_trgm_ops) WITH (fastupdate = off, gin_pending_list_limit = 128);
+			CREATE UNIQUE INDEX some_partial_unique_idx ON schema_2.foo (author) WHERE version > 0;
+			CREATE INDEX some_expression_idx ON schema_2.foo (LOWER(content), version) INCLUDE (created_at);
+			CREATE INDEX some_brin_idx ON schema_2.foo USING BRIN (created_at) WITH (pages_per_range = 16, autosummarize = on);
 			ALTER TABLE schema_2.foo REPLICA IDENTITY USING INDEX some_unique_idx;
 
 			CREATE POLICY foo_policy_1 ON schema_2.foo
@@ -244,11 +257,32 @@ var (
 						Labels:              []string{"foobar_1", "foobar_2"},
 					},
 				},
+				Domains: []Domain{
+					{
+						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"positive_int\""},
+						BaseType:            "integer",
+						Default:             "1",
+						IsNotNull:           true,
+						Constraints: []DomainConstraint{
+							{Name: "positive_int_check", Check: "CHECK ((VALUE > 0))"},
+						},
+					},
+				},
+				CompositeTypes: []CompositeType{
+					{
+						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar_address\""},
+						Attributes: []CompositeTypeAttribute{
+							{Name: "street", Type: "text", Collation: cCollation},
+							{Name: "zip", Type: "integer"},
+							{Name: "kind", Type: "foobar_enum"},
+						},
+					},
+				},
 				Tables: []Table{
 					{
 						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "schema_2", EscapedName: "\"foo\""},
 						Columns: []Column{
-							{Name: "id", Type: "integer", Size: 4, Default: "nextval('schema_2.foo_id_seq'::regclass)"},
+							{Name: "id", Type: "integer", Size: 4, Default: "nextval('schema_2.foo_id_seq'::regclass)", IsDefaultVolatile: true},
 							{Name: "author", Type: "text", IsNullable: true, Size: -1, Collation: cCollation},
 							{Name: "content", Type: "text", Default: "''::text", Size: -1, Collation: defaultCollation},
 							{Name: "created_at", Type: "timestamp without time zone", Default: "CURRENT_TIMESTAMP", Size: 8},
@@ -386,12 +420,14 @@ var (
 						IsUnique:        true,
 						Constraint:      &IndexConstraint{Type: PkIndexConstraintType, EscapedConstraintName: "\"foo_pkey\"", ConstraintDef: "PRIMARY KEY (id, version)", IsLocal: true},
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_pkey ON schema_2.foo USING btree (id, version)",
+						Method:          "btree",
 					},
 					{
 						OwningTable:     SchemaQualifiedName{SchemaName: "schema_2", EscapedName: "\"foo\""},
 						Name:            "some_idx",
 						Columns:         []string{"created_at", "author"},
 						GetIndexDefStmt: "CREATE INDEX some_idx ON schema_2.foo USING btree (created_at DESC, author)",
+						Method:          "btree",
 					},
 					{
 						OwningTable:     SchemaQualifiedName{SchemaName: "schema_2", EscapedName: "\"foo\""},
@@ -399,12 +435,42 @@ var (
 						Columns:         []string{"content"},
 						IsUnique:        true,
 						GetIndexDefStmt: "CREATE UNIQUE INDEX some_unique_idx ON schema_2.foo USING btree (content)",
+						Method:          "btree",
 					},
 					{
 						OwningTable:     SchemaQualifiedName{SchemaName: "schema_2", EscapedName: "\"foo\""},
 						Name:            "some_gin_idx",
 						Columns:         []string{"author"},
-						GetIndexDefStmt: "CREATE INDEX some_gin_idx ON schema_2.foo USING gin (author schema_1.gin_trgm_ops)",
+						GetIndexDefStmt: "CREATE INDEX some_gin_idx ON schema_2.foo USING gin (author schema_1.gin_trgm_ops) WITH (fastupdate=off, gin_pending_list_limit='128')",
+						Method:          "gin",
+						GINParameters:   map[string]string{"fastupdate": "off", "gin_pending_list_limit": "128"},
+					},
+					{
+						OwningTable:     SchemaQualifiedName{SchemaName: "schema_2", EscapedName: "\"foo\""},
+						Name:            "some_partial_unique_idx",
+						Columns:         []string{"author"},
+						IsUnique:        true,
+						Predicate:       "(version > 0)",
+						GetIndexDefStmt: "CREATE UNIQUE INDEX some_partial_unique_idx ON schema_2.foo USING btree (author) WHERE (version > 0)",
+						Method:          "btree",
+					},
+					{
+						OwningTable:     SchemaQualifiedName{SchemaName: "schema_2", EscapedName: "\"foo\""},
+						Name:            "some_expression_idx",
+						Columns:         []string{"version"},
+						IncludedColumns: []string{"created_at"},
+						Expressions:     []string{"lower(content)"},
+						GetIndexDefStmt: "CREATE INDEX some_expression_idx ON schema_2.foo USING btree (lower(content), version) INCLUDE (created_at)",
+						Method:          "btree",
+					},
+					{
+						OwningTable:     SchemaQualifiedName{SchemaName: "schema_2", EscapedName: "\"foo\""},
+						Name:            "some_brin_idx",
+						Columns:         []string{"created_at"},
+						GetIndexDefStmt: "CREATE INDEX some_brin_idx ON schema_2.foo USING brin (created_at) WITH (pages_per_range='16', autosummarize='on')",
+						Method:          "brin",
+						PagesPerRange:   &brinPagesPerRange,
+						Autosummarize:   &brinAutosummarize,
 					},
 					{
 						Name: "some_idx",
@@ -417,6 +483,7 @@ var (
 							"version",
 						},
 						GetIndexDefStmt: "CREATE INDEX some_idx ON schema_1.foo_fk USING btree (id, version)",
+						Method:          "btree",
 					},
 				},
 				Functions: []Function{
@@ -444,10 +511,12 @@ var (
 					{
 						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_plpgsql_procedure\"(IN foobar numeric)"},
 						Def:                 "CREATE OR REPLACE PROCEDURE public.some_plpgsql_procedure(IN foobar numeric)\n LANGUAGE plpgsql\nAS $procedure$\n\t\t\t\tBEGIN\n\t\t\t\t\tRAISE NOTICE 'some notice';\n\t\t\t\tEND\n\t\t\t\t$procedure$\n",
+						Language:            "plpgsql",
 					},
 					{
 						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "schema_2", EscapedName: "\"some_insert_procedure\"(IN a integer, IN b integer)"},
 						Def:                 "CREATE OR REPLACE PROCEDURE schema_2.some_insert_procedure(IN a integer, IN b integer)\n LANGUAGE sql\nBEGIN ATOMIC\n INSERT INTO schema_2.foo DEFAULT VALUES;\nEND\n",
+						Language:            "sql",
 					},
 				},
 				Triggers: []Trigger{
@@ -458,10 +527,18 @@ var (
 						GetTriggerDefStmt: "CREATE TRIGGER some_trigger BEFORE UPDATE ON schema_2.foo FOR EACH ROW WHEN ((old.* IS DISTINCT FROM new.*)) EXECUTE FUNCTION schema_filtered_1.increment_version()",
 					},
 				},
+				Privileges: []Privilege{
+					{ObjectType: "FUNCTION", Object: SchemaQualifiedName{EscapedName: "\"function_with_dependencies\"(a integer, b integer)", SchemaName: "public"}, Grantee: PrivilegeGranteePublic, Type: "EXECUTE"},
+					{ObjectType: "FUNCTION", Object: SchemaQualifiedName{EscapedName: "\"increment\"(i integer)", SchemaName: "schema_1"}, Grantee: PrivilegeGranteePublic, Type: "EXECUTE"},
+					{ObjectType: "FUNCTION", Object: SchemaQualifiedName{EscapedName: "\"increment_version\"()", SchemaName: "public"}, Grantee: PrivilegeGranteePublic, Type: "EXECUTE"},
+					{ObjectType: "PROCEDURE", Object: SchemaQualifiedName{EscapedName: "\"some_plpgsql_procedure\"(IN foobar numeric)", SchemaName: "public"}, Grantee: PrivilegeGranteePublic, Type: "EXECUTE"},
+					{ObjectType: "PROCEDURE", Object: SchemaQualifiedName{EscapedName: "\"some_insert_procedure\"(IN a integer, IN b integer)", SchemaName: "schema_2"}, Grantee: PrivilegeGranteePublic, Type: "EXECUTE"},
+				},
 			},
 		},
 		{
 			name: "Partition test",
+			opts: []GetSchemaOpt{WithPrivileges()},
 			ddl: []string{`
 			CREATE TABLE foo (
 				id SERIAL CHECK (id > 0),
@@ -533,7 +610,7 @@ var (
 					{
 						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
 						Columns: []Column{
-							{Name: "id", Type: "integer", Size: 4, Default: "nextval('foo_id_seq'::regclass)"},
+							{Name: "id", Type: "integer", Size: 4, Default: "nextval('foo_id_seq'::regclass)", IsDefaultVolatile: true},
 							{Name: "author", Type: "text", Size: -1, Collation: cCollation},
 							{Name: "content", Type: "text", Default: "''::text", IsNullable: true, Size: -1, Collation: defaultCollation},
 							{Name: "genre", Type: "character varying(256)", Size: -1, Collation: defaultCollation},
@@ -554,7 +631,7 @@ var (
 						ParentTable:         &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
 						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_1\""},
 						Columns: []Column{
-							{Name: "id", Type: "integer", Size: 4, Default: "nextval('foo_id_seq'::regclass)"},
+							{Name: "id", Type: "integer", Size: 4, Default: "nextval('foo_id_seq'::regclass)", IsDefaultVolatile: true},
 							{Name: "author", Type: "text", Size: -1, Collation: cCollation},
 							{Name: "content", Type: "text", Default: "''::text", Size: -1, Collation: defaultCollation},
 							{Name: "genre", Type: "character varying(256)", Size: -1, Collation: defaultCollation},
@@ -569,7 +646,7 @@ var (
 						ParentTable:         &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
 						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_2\""},
 						Columns: []Column{
-							{Name: "id", Type: "integer", Size: 4, Default: "nextval('foo_id_seq'::regclass)"},
+							{Name: "id", Type: "integer", Size: 4, Default: "nextval('foo_id_seq'::regclass)", IsDefaultVolatile: true},
 							{Name: "author", Type: "text", Size: -1, Collation: cCollation},
 							{Name: "content", Type: "text", Default: "''::text", IsNullable: true, Size: -1, Collation: defaultCollation},
 							{Name: "genre", Type: "character varying(256)", Size: -1, Collation: defaultCollation},
@@ -584,7 +661,7 @@ var (
 						ParentTable:         &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
 						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_3\""},
 						Columns: []Column{
-							{Name: "id", Type: "integer", Size: 4, Default: "nextval('foo_id_seq'::regclass)"},
+							{Name: "id", Type: "integer", Size: 4, Default: "nextval('foo_id_seq'::regclass)", IsDefaultVolatile: true},
 							{Name: "author", Type: "text", Size: -1, Collation: cCollation},
 							{Name: "content", Type: "text", Default: "''::text", IsNullable: true, Size: -1, Collation: defaultCollation},
 							{Name: "genre", Type: "character varying(256)", Size: -1, Collation: defaultCollation},
@@ -669,21 +746,25 @@ var (
 						Name:        "foo_pkey", Columns: []string{"author", "id"}, IsUnique: true,
 						Constraint:      &IndexConstraint{Type: PkIndexConstraintType, EscapedConstraintName: "\"foo_pkey\"", ConstraintDef: "PRIMARY KEY (author, id)", IsLocal: true},
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_pkey ON ONLY public.foo USING btree (author, id)",
+						Method:          "btree",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
 						Name:        "some_partitioned_idx", Columns: []string{"author"},
 						GetIndexDefStmt: "CREATE INDEX some_partitioned_idx ON ONLY public.foo USING hash (author)",
+						Method:          "hash",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
 						Name:        "some_unique_partitioned_idx", Columns: []string{"author", "created_at"}, IsUnique: true,
 						GetIndexDefStmt: "CREATE UNIQUE INDEX some_unique_partitioned_idx ON ONLY public.foo USING btree (author, created_at DESC)",
+						Method:          "btree",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
 						Name:        "some_invalid_idx", Columns: []string{"author", "genre"}, IsInvalid: true, IsUnique: false,
 						GetIndexDefStmt: "CREATE INDEX some_invalid_idx ON ONLY public.foo USING btree (author, genre)",
+						Method:          "btree",
 					},
 					// foo_1 indexes
 					{
@@ -691,17 +772,20 @@ var (
 						Name:        "foo_1_author_idx", Columns: []string{"author"},
 						ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_partitioned_idx\""},
 						GetIndexDefStmt: "CREATE INDEX foo_1_author_idx ON public.foo_1 USING hash (author)",
+						Method:          "hash",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_1\""},
 						Name:        "foo_1_author_created_at_idx", Columns: []string{"author", "created_at"}, IsUnique: true,
 						ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_unique_partitioned_idx\""},
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_1_author_created_at_idx ON public.foo_1 USING btree (author, created_at DESC)",
+						Method:          "btree",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_1\""},
 						Name:        "foo_1_local_idx", Columns: []string{"author", "content"}, IsUnique: true,
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_1_local_idx ON public.foo_1 USING btree (author, content)",
+						Method:          "btree",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_1\""},
@@ -709,6 +793,7 @@ var (
 						ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_pkey\""},
 						Constraint:      &IndexConstraint{Type: PkIndexConstraintType, EscapedConstraintName: "\"foo_1_pkey\"", ConstraintDef: "PRIMARY KEY (author, id)"},
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_1_pkey ON public.foo_1 USING btree (author, id)",
+						Method:          "btree",
 					},
 					// foo_2 indexes
 					{
@@ -716,17 +801,20 @@ var (
 						Name:        "foo_2_author_idx", Columns: []string{"author"},
 						ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_partitioned_idx\""},
 						GetIndexDefStmt: "CREATE INDEX foo_2_author_idx ON public.foo_2 USING hash (author)",
+						Method:          "hash",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_2\""},
 						Name:        "foo_2_author_created_at_idx", Columns: []string{"author", "created_at"}, IsUnique: true,
 						ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_unique_partitioned_idx\""},
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_2_author_created_at_idx ON public.foo_2 USING btree (author, created_at DESC)",
+						Method:          "btree",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_2\""},
 						Name:        "foo_2_local_idx", Columns: []string{"author", "id"}, IsUnique: true,
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_2_local_idx ON public.foo_2 USING btree (author DESC, id)",
+						Method:          "btree",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_2\""},
@@ -734,6 +822,7 @@ var (
 						ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_pkey\""},
 						Constraint:      &IndexConstraint{Type: PkIndexConstraintType, EscapedConstraintName: "\"foo_2_pkey\"", ConstraintDef: "PRIMARY KEY (author, id)"},
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_2_pkey ON public.foo_2 USING btree (author, id)",
+						Method:          "btree",
 					},
 					// foo_3 indexes
 					{
@@ -741,17 +830,20 @@ var (
 						Name:        "foo_3_author_idx", Columns: []string{"author"},
 						ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_partitioned_idx\""},
 						GetIndexDefStmt: "CREATE INDEX foo_3_author_idx ON public.foo_3 USING hash (author)",
+						Method:          "hash",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_3\""},
 						Name:        "foo_3_author_created_at_idx", Columns: []string{"author", "created_at"}, IsUnique: true,
 						ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_unique_partitioned_idx\""},
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_3_author_created_at_idx ON public.foo_3 USING btree (author, created_at DESC)",
+						Method:          "btree",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_3\""},
 						Name:        "foo_3_local_idx", Columns: []string{"author", "created_at"}, IsUnique: true,
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_3_local_idx ON public.foo_3 USING btree (author, created_at)",
+						Method:          "btree",
 					},
 					{
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_3\""},
@@ -759,6 +851,7 @@ var (
 						ParentIdx:       &SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_pkey\""},
 						Constraint:      &IndexConstraint{Type: PkIndexConstraintType, EscapedConstraintName: "\"foo_3_pkey\"", ConstraintDef: "PRIMARY KEY (author, id)"},
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_3_pkey ON public.foo_3 USING btree (author, id)",
+						Method:          "btree",
 					},
 				},
 				ForeignKeyConstraints: []ForeignKeyConstraint{
@@ -814,6 +907,9 @@ var (
 						GetTriggerDefStmt: "CREATE TRIGGER some_partition_trigger BEFORE UPDATE ON public.foo_1 FOR EACH ROW WHEN ((old.* IS DISTINCT FROM new.*)) EXECUTE FUNCTION increment_version()",
 					},
 				},
+				Privileges: []Privilege{
+					{ObjectType: "FUNCTION", Object: SchemaQualifiedName{EscapedName: "\"increment_version\"()", SchemaName: "public"}, Grantee: PrivilegeGranteePublic, Type: "EXECUTE"},
+				},
 			},
 		},
 		{
@@ -861,6 +957,7 @@ var (
 						Name:        "foo_1_pkey", Columns: []string{"author", "id"}, IsUnique: true,
 						Constraint:      &IndexConstraint{Type: PkIndexConstraintType, EscapedConstraintName: "\"foo_1_pkey\"", ConstraintDef: "PRIMARY KEY (author, id)", IsLocal: true},
 						GetIndexDefStmt: "CREATE UNIQUE INDEX foo_1_pkey ON public.foo_1 USING btree (author, id)",
+						Method:          "btree",
 					},
 				},
 			},
@@ -902,7 +999,7 @@ var (
 							{Name: "integer", Type: "integer", Default: "0", Size: 4},
 							{Name: "big_integer", Type: "bigint", Default: "0", Size: 8},
 							{Name: "decimal", Type: "numeric(65,10)", Default: "0.0", Size: -1},
-							{Name: "serial", Type: "integer", Collation: SchemaQualifiedName{}, Default: "nextval('foo_serial_seq'::regclass)", IsNullable: false, Size: 4},
+							{Name: "serial", Type: "integer", Collation: SchemaQualifiedName{}, Default: "nextval('foo_serial_seq'::regclass)", IsDefaultVolatile: true, IsNullable: false, Size: 4},
 							{Name: "identity_always", Type: "bigint", Size: 8,
 								Identity: &ColumnIdentity{
 									Type:       ColumnIdentityTypeAlways,
@@ -976,6 +1073,7 @@ var (
 						OwningTable: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
 						Name:        "foo_value_idx", Columns: []string{"renamed_value"},
 						GetIndexDefStmt: "CREATE INDEX foo_value_idx ON public.foo USING btree (renamed_value)",
+						Method:          "btree",
 					},
 				},
 			},
@@ -1118,6 +1216,74 @@ var (
 				},
 			},
 		},
+		{
+			name: "Generated columns",
+			ddl: []string{`
+			CREATE TABLE foo (
+				price NUMERIC NOT NULL,
+				name TEXT,
+				price_with_tax NUMERIC GENERATED ALWAYS AS (price * 1.1) STORED,
+				lower_name TEXT NOT NULL GENERATED ALWAYS AS (lower(name)) STORED
+			);
+		`},
+			expectedSchema: Schema{
+				NamedSchemas: []NamedSchema{
+					{Name: "public"},
+				},
+				Tables: []Table{
+					{
+						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+						Columns: []Column{
+							{Name: "price", Type: "numeric", Size: -1},
+							{Name: "name", Type: "text", IsNullable: true, Size: -1, Collation: defaultCollation},
+							{Name: "price_with_tax", Type: "numeric", IsNullable: true, Size: -1, IsGenerated: true, GenerationExpression: "(price * 1.1)"},
+							{Name: "lower_name", Type: "text", Size: -1, Collation: defaultCollation, IsGenerated: true, GenerationExpression: "lower(name)"},
+						},
+						ReplicaIdentity: ReplicaIdentityDefault,
+					},
+				},
+			},
+		},
+		{
+			name: "Text search dictionaries and configurations",
+			ddl: []string{`
+			CREATE TEXT SEARCH DICTIONARY english_stem_nostop (TEMPLATE = snowball, Language = english);
+			CREATE TEXT SEARCH CONFIGURATION my_english (PARSER = default);
+			ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR word, asciiword WITH english_stem_nostop, simple;
+			ALTER TEXT SEARCH CONFIGURATION my_english ADD MAPPING FOR email WITH simple;
+		`},
+			expectedSchema: Schema{
+				NamedSchemas: []NamedSchema{
+					{Name: "public"},
+				},
+				TextSearchDictionaries: []TextSearchDictionary{
+					{
+						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"english_stem_nostop\""},
+						Template:            SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"snowball\""},
+						Options:             "language = 'english'",
+					},
+				},
+				TextSearchConfigs: []TextSearchConfig{
+					{
+						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"my_english\""},
+						Parser:              SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"default\""},
+						Mappings: []TextSearchConfigMapping{
+							{TokenType: "asciiword", Dictionaries: []SchemaQualifiedName{
+								{SchemaName: "public", EscapedName: "\"english_stem_nostop\""},
+								{SchemaName: "pg_catalog", EscapedName: "\"simple\""},
+							}},
+							{TokenType: "word", Dictionaries: []SchemaQualifiedName{
+								{SchemaName: "public", EscapedName: "\"english_stem_nostop\""},
+								{SchemaName: "pg_catalog", EscapedName: "\"simple\""},
+							}},
+							{TokenType: "email", Dictionaries: []SchemaQualifiedName{
+								{SchemaName: "pg_catalog", EscapedName: "\"simple\""},
+							}},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "Filters - exclude schemas",
 			opts: []GetSchemaOpt{
@@ -1173,6 +1339,110 @@ var (
 				},
 			},
 		},
+		{
+			name: "Filters - only schemas omits out of scope dependencies",
+			opts: []GetSchemaOpt{
+				WithOnlySchemas("schema_1"),
+				WithPrivileges(),
+			},
+			ddl: []string{`
+				CREATE SCHEMA schema_1;
+				CREATE SCHEMA schema_2;
+				CREATE FUNCTION schema_2.add(a integer, b integer) RETURNS integer
+					LANGUAGE SQL
+					IMMUTABLE
+					RETURNS NULL ON NULL INPUT
+					RETURN a + b;
+				CREATE FUNCTION schema_1.add_one(a integer) RETURNS integer
+					LANGUAGE SQL
+					IMMUTABLE
+					RETURNS NULL ON NULL INPUT
+					RETURN schema_2.add(a, 1);
+			`},
+			expectedSchema: Schema{
+				NamedSchemas: []NamedSchema{
+					{Name: "schema_1"},
+				},
+				Functions: []Function{
+					{
+						SchemaQualifiedName: SchemaQualifiedName{EscapedName: "\"add_one\"(a integer)", SchemaName: "schema_1"},
+						FunctionDef:         "CREATE OR REPLACE FUNCTION schema_1.add_one(a integer)\n RETURNS integer\n LANGUAGE sql\n IMMUTABLE STRICT\nRETURN schema_2.add(a, 1)\n",
+						Language:            "sql",
+					},
+				},
+				Privileges: []Privilege{
+					{ObjectType: "FUNCTION", Object: SchemaQualifiedName{EscapedName: "\"add_one\"(a integer)", SchemaName: "schema_1"}, Grantee: PrivilegeGranteePublic, Type: "EXECUTE"},
+				},
+			},
+		},
+		{
+			name: "Filters - include and exclude objects",
+			opts: []GetSchemaOpt{
+				WithIncludeObjects("public.orders*"),
+				WithExcludeObjects("*.orders_archive"),
+			},
+			ddl: []string{`
+				CREATE TABLE orders(id INT PRIMARY KEY);
+				CREATE TABLE orders_archive(id INT);
+				CREATE TABLE users(id INT);
+				CREATE INDEX users_id_idx ON users(id);
+			`},
+			expectedSchema: Schema{
+				NamedSchemas: []NamedSchema{
+					{Name: "public"},
+				},
+				Tables: []Table{
+					{
+						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"orders\""},
+						Columns: []Column{
+							{Name: "id", Type: "integer", Size: 4},
+						},
+						ReplicaIdentity: ReplicaIdentityDefault,
+					},
+				},
+				Indexes: []Index{
+					{
+						OwningTable:     SchemaQualifiedName{SchemaName: "public", EscapedName: "\"orders\""},
+						Name:            "orders_pkey",
+						Columns:         []string{"id"},
+						IsUnique:        true,
+						Constraint:      &IndexConstraint{Type: PkIndexConstraintType, EscapedConstraintName: "\"orders_pkey\"", ConstraintDef: "PRIMARY KEY (id)", IsLocal: true},
+						GetIndexDefStmt: "CREATE UNIQUE INDEX orders_pkey ON public.orders USING btree (id)",
+						Method:          "btree",
+					},
+				},
+			},
+		},
+		{
+			name: "Filters - included view depends on excluded table",
+			opts: []GetSchemaOpt{
+				WithExcludeObjects("public.foo*"),
+			},
+			ddl: []string{`
+				CREATE TABLE foobar(id INT);
+				CREATE VIEW bar_view AS SELECT id FROM foobar;
+			`},
+			expectedErrContains: `view "public"."bar_view" depends on table "public"."foobar"`,
+		},
+		{
+			name: "Filters - tracking tables are excluded",
+			ddl: []string{`
+				CREATE TABLE foobar();
+				CREATE TABLE schema_migrations(version TEXT PRIMARY KEY);
+				COMMENT ON TABLE schema_migrations IS 'pg-schema-diff migration tracking table';
+			`},
+			expectedSchema: Schema{
+				NamedSchemas: []NamedSchema{
+					{Name: "public"},
+				},
+				Tables: []Table{
+					{
+						SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""},
+						ReplicaIdentity:     ReplicaIdentityDefault,
+					},
+				},
+			},
+		},
 		{
 			name: "Filter - include and exclude the same schema",
 			opts: []GetSchemaOpt{
@@ -1358,3 +1628,34 @@ func TestTriggerDefStmtToCreateOrReplace(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildBrinParameters(t *testing.T) {
+	pagesPerRange, autosummarize, err := buildBrinParameters([]string{"pages_per_range=16", "autosummarize=on"})
+	require.NoError(t, err)
+	require.NotNil(t, pagesPerRange)
+	assert.Equal(t, 16, *pagesPerRange)
+	require.NotNil(t, autosummarize)
+	assert.True(t, *autosummarize)
+
+	pagesPerRange, autosummarize, err = buildBrinParameters([]string{"fillfactor=70", "autosummarize=false"})
+	require.NoError(t, err)
+	assert.Nil(t, pagesPerRange)
+	require.NotNil(t, autosummarize)
+	assert.False(t, *autosummarize)
+
+	_, _, err = buildBrinParameters([]string{"pages_per_range=abc"})
+	assert.Error(t, err)
+}
+
+func TestBuildGINParameters(t *testing.T) {
+	params, err := buildGINParameters([]string{"fastupdate=false", "gin_pending_list_limit=128", "fillfactor=70"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"fastupdate": "off", "gin_pending_list_limit": "128"}, params)
+
+	params, err = buildGINParameters([]string{"pages_per_range=16"})
+	require.NoError(t, err)
+	assert.Nil(t, params)
+
+	_, err = buildGINParameters([]string{"fastupdate=maybe"})
+	assert.Error(t, err)
+}
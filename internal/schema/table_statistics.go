@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/internal/queries"
+)
+
+// TableStatistics are the statistics Postgres keeps about the size of a table
+type TableStatistics struct {
+	// Pages is the number of disk pages of the table, from pg_class.relpages
+	Pages int64
+	// EstimatedRows is the estimated number of rows, from pg_class.reltuples. It is -1 on Postgres 14+ if the table has
+	// never been vacuumed or analyzed.
+	EstimatedRows int64
+	// RowWidth is the estimated average width of a row in bytes, from pg_stats. It is 0 if the table has never been
+	// analyzed.
+	RowWidth                 int64
+	HasBeenAnalyzed          bool
+	RowsModifiedSinceAnalyze int64
+}
+
+// GetTableStatistics gets the statistics of the table. The table name is resolved like a table name in SQL, i.e., it can
+// be schema-qualified and quoted, e.g., `"public"."foobar"` or `public.foobar`. It returns false if the table does not
+// exist.
+func GetTableStatistics(ctx context.Context, db queries.DBTX, tableName string) (TableStatistics, bool, error) {
+	rawStats, err := queries.New(db).GetTableStatistics(ctx, tableName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TableStatistics{}, false, nil
+	} else if err != nil {
+		return TableStatistics{}, false, fmt.Errorf("GetTableStatistics: %w", err)
+	}
+	return TableStatistics{
+		Pages:                    rawStats.Pages,
+		EstimatedRows:            rawStats.EstimatedRows,
+		RowWidth:                 rawStats.RowWidth,
+		HasBeenAnalyzed:          rawStats.HasBeenAnalyzed,
+		RowsModifiedSinceAnalyze: rawStats.RowsModifiedSinceAnalyze,
+	}, true, nil
+}
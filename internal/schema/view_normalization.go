@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pg_query "github.com/pganalyze/pg_query_go/v5"
+)
+
+const (
+	normalizeViewSavepoint = "pgschemadiff_normalize_view"
+	normalizeViewName      = "pgschemadiff_normalize_view"
+)
+
+// NormalizeViewDefinitions returns a copy of the schema where the definitions of views and materialized views are
+// normalized to the format of pg_get_viewdef, e.g., `SELECT a, b FROM t` becomes ` SELECT t.a,\n    t.b\n   FROM t;`,
+// which is the format GetSchema fetches them in. This prevents spurious diffs between user-written definitions, e.g.,
+// from ParseDump, and definitions fetched from a database.
+//
+// Each definition is parsed by pg_query to check that it is a single SELECT statement. The deparsed statement is then
+// created as a temporary view in the database, and its definition is read back via pg_get_viewdef. Since the database
+// resolves the names in the definition, the objects a view selects from must exist in it, and names are qualified
+// relative to the connection's search_path, like in GetSchema. Definitions that cannot be created in the database, e.g.,
+// because a table they select from does not exist yet, are left unchanged. The temporary views are always rolled back.
+func (s Schema) NormalizeViewDefinitions(ctx context.Context, db *sql.DB) (Schema, error) {
+	if len(s.Views) == 0 && len(s.MaterializedViews) == 0 {
+		return s, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return Schema{}, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Copy the slices, such that the original schema is not mutated
+	s.Views = append([]View(nil), s.Views...)
+	for i, view := range s.Views {
+		definition, err := normalizeViewDefinition(ctx, tx, view.Definition)
+		if err != nil {
+			return Schema{}, fmt.Errorf("normalizing definition of view %s: %w", view.GetFQEscapedName(), err)
+		}
+		s.Views[i].Definition = definition
+	}
+	s.MaterializedViews = append([]MaterializedView(nil), s.MaterializedViews...)
+	for i, mv := range s.MaterializedViews {
+		definition, err := normalizeViewDefinition(ctx, tx, mv.Definition)
+		if err != nil {
+			return Schema{}, fmt.Errorf("normalizing definition of materialized view %s: %w", mv.GetFQEscapedName(), err)
+		}
+		s.MaterializedViews[i].Definition = definition
+	}
+
+	return s, nil
+}
+
+// normalizeViewDefinition round-trips the definition through a temporary view. If the temporary view cannot be
+// created, the definition is returned unchanged.
+func normalizeViewDefinition(ctx context.Context, tx *sql.Tx, definition string) (string, error) {
+	query, err := deparseViewQuery(definition)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+normalizeViewSavepoint); err != nil {
+		return "", fmt.Errorf("creating savepoint: %w", err)
+	}
+	// Rolling back to the savepoint drops the temporary view, or recovers the transaction if it could not be created
+	rollbackToSavepoint := func() error {
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+normalizeViewSavepoint); err != nil {
+			return fmt.Errorf("rolling back to savepoint: %w", err)
+		}
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("CREATE TEMPORARY VIEW %s AS %s", normalizeViewName, query)); err != nil {
+		return definition, rollbackToSavepoint()
+	}
+	var normalized string
+	if err := tx.QueryRowContext(ctx,
+		fmt.Sprintf("SELECT pg_catalog.pg_get_viewdef('pg_temp.%s'::regclass, true)", normalizeViewName),
+	).Scan(&normalized); err != nil {
+		return "", fmt.Errorf("getting normalized definition: %w", err)
+	}
+	if err := rollbackToSavepoint(); err != nil {
+		return "", err
+	}
+	return normalized, nil
+}
+
+// deparseViewQuery parses the definition of a view and deparses it. It returns an error unless the definition is a
+// single SELECT statement, so it can be safely embedded in a CREATE VIEW statement.
+func deparseViewQuery(definition string) (string, error) {
+	tree, err := pg_query.Parse(definition)
+	if err != nil {
+		return "", fmt.Errorf("parsing definition: %w", err)
+	}
+	if len(tree.Stmts) != 1 || tree.Stmts[0].Stmt.GetSelectStmt() == nil {
+		return "", fmt.Errorf("definition must be a single SELECT statement")
+	}
+	query, err := pg_query.Deparse(tree)
+	if err != nil {
+		return "", fmt.Errorf("deparsing definition: %w", err)
+	}
+	return query, nil
+}
@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/pgengine"
+)
+
+func TestDeparseViewQuery(t *testing.T) {
+	query, err := deparseViewQuery("select a,\n  b from foobar -- the foobar table")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT a, b FROM foobar", query)
+
+	query, err = deparseViewQuery(" SELECT foobar.a\n   FROM foobar;")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT foobar.a FROM foobar", query)
+
+	_, err = deparseViewQuery("SELECT a FROM foobar; DROP TABLE foobar")
+	assert.ErrorContains(t, err, "definition must be a single SELECT statement")
+	_, err = deparseViewQuery("DELETE FROM foobar")
+	assert.ErrorContains(t, err, "definition must be a single SELECT statement")
+	_, err = deparseViewQuery("SELECT FROM WHERE")
+	assert.ErrorContains(t, err, "parsing definition")
+}
+
+func TestNormalizeViewDefinitions(t *testing.T) {
+	engine, err := pgengine.StartEngine()
+	require.NoError(t, err)
+	defer engine.Close()
+
+	db, err := engine.CreateDatabase()
+	require.NoError(t, err)
+	defer db.DropDB()
+
+	connPool, err := sql.Open("pgx", db.GetDSN())
+	require.NoError(t, err)
+	defer connPool.Close()
+
+	_, err = connPool.ExecContext(context.Background(), `
+		CREATE TABLE foobar(id INT PRIMARY KEY, a TEXT, b TEXT);
+		CREATE VIEW foobar_view AS SELECT a, b FROM foobar WHERE id > 0;
+		CREATE MATERIALIZED VIEW foobar_mv AS SELECT id, a FROM foobar;
+	`)
+	require.NoError(t, err)
+	fetchedSchema, err := GetSchema(context.Background(), connPool)
+	require.NoError(t, err)
+
+	userSchema := fetchedSchema.DeepCopy()
+	userSchema.Views[0].Definition = "select a, b from foobar where id > 0"
+	userSchema.MaterializedViews[0].Definition = "SELECT foobar.id, foobar.a FROM public.foobar"
+	userSchema.Views = append(userSchema.Views, View{
+		SchemaQualifiedName: SchemaQualifiedName{SchemaName: "public", EscapedName: `"missing_view"`},
+		Definition:          "SELECT a FROM missing_table",
+	})
+
+	normalizedSchema, err := userSchema.NormalizeViewDefinitions(context.Background(), connPool)
+	require.NoError(t, err)
+	assert.Equal(t, fetchedSchema.Views[0].Definition, normalizedSchema.Views[0].Definition)
+	assert.Equal(t, fetchedSchema.MaterializedViews[0].Definition, normalizedSchema.MaterializedViews[0].Definition)
+	// Views that cannot be created in the database are left unchanged
+	assert.Equal(t, "SELECT a FROM missing_table", normalizedSchema.Views[1].Definition)
+	// The original schema should not be mutated
+	assert.Equal(t, "select a, b from foobar where id > 0", userSchema.Views[0].Definition)
+
+	// The temporary views are rolled back
+	var count int
+	require.NoError(t, connPool.QueryRowContext(context.Background(),
+		"SELECT COUNT(*) FROM pg_catalog.pg_class WHERE relname = 'pgschemadiff_normalize_view'").Scan(&count))
+	assert.Zero(t, count)
+
+	_, err = userSchema.NormalizeViewDefinitions(context.Background(), connPool)
+	require.NoError(t, err)
+	userSchema.Views[0].Definition = "SELECT a FROM foobar; DROP TABLE foobar"
+	_, err = userSchema.NormalizeViewDefinitions(context.Background(), connPool)
+	assert.ErrorContains(t, err, `normalizing definition of view "public"."foobar_view"`)
+}
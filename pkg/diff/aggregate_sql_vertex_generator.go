@@ -0,0 +1,105 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var migrationHazardAggregateDropped = MigrationHazard{
+	Type: MigrationHazardTypeHasUntrackableDependencies,
+	Message: "Aggregates cannot be replaced, so changing an aggregate drops and re-creates it. The aggregate is " +
+		"dropped with CASCADE, and the objects that depend on it, e.g., views and functions using it, are not " +
+		"tracked. They will be dropped along with the aggregate.",
+}
+
+type aggregateSQLVertexGenerator struct{}
+
+func newAggregateSqlVertexGenerator() sqlVertexGenerator[schema.Aggregate, aggregateDiff] {
+	return legacyToNewSqlVertexGenerator[schema.Aggregate, aggregateDiff](&aggregateSQLVertexGenerator{})
+}
+
+func (a *aggregateSQLVertexGenerator) Add(aggregate schema.Aggregate) ([]Statement, error) {
+	options := []string{
+		fmt.Sprintf("SFUNC = %s", aggregate.StateFunction.GetFQEscapedName()),
+		fmt.Sprintf("STYPE = %s", aggregate.StateType),
+	}
+	if aggregate.FinalFunction != nil {
+		options = append(options, fmt.Sprintf("FINALFUNC = %s", aggregate.FinalFunction.GetFQEscapedName()))
+	}
+	if aggregate.FinalFunctionExtra {
+		options = append(options, "FINALFUNC_EXTRA")
+	}
+	if aggregate.CombineFunction != nil {
+		options = append(options, fmt.Sprintf("COMBINEFUNC = %s", aggregate.CombineFunction.GetFQEscapedName()))
+	}
+	if aggregate.SerialFunction != nil {
+		options = append(options, fmt.Sprintf("SERIALFUNC = %s", aggregate.SerialFunction.GetFQEscapedName()))
+	}
+	if aggregate.DeserialFunction != nil {
+		options = append(options, fmt.Sprintf("DESERIALFUNC = %s", aggregate.DeserialFunction.GetFQEscapedName()))
+	}
+	if aggregate.InitialCondition != nil {
+		options = append(options, fmt.Sprintf("INITCOND = '%s'", strings.ReplaceAll(*aggregate.InitialCondition, "'", "''")))
+	}
+	if len(aggregate.Parallel) > 0 && aggregate.Parallel != "UNSAFE" {
+		options = append(options, fmt.Sprintf("PARALLEL = %s", aggregate.Parallel))
+	}
+
+	return []Statement{{
+		DDL:         fmt.Sprintf("CREATE AGGREGATE %s (\n\t%s\n)", buildAggregateSignature(aggregate), strings.Join(options, ",\n\t")),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}}, nil
+}
+
+func (a *aggregateSQLVertexGenerator) Delete(aggregate schema.Aggregate) ([]Statement, error) {
+	return []Statement{{
+		DDL:         fmt.Sprintf("DROP AGGREGATE %s CASCADE", buildAggregateSignature(aggregate)),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardAggregateDropped},
+	}}, nil
+}
+
+func (a *aggregateSQLVertexGenerator) Alter(diff aggregateDiff) ([]Statement, error) {
+	// Aggregates are always re-created if they have changed, so there should never be anything to alter.
+	if !cmp.Equal(diff.old, diff.new) {
+		return nil, fmt.Errorf("altering aggregate to resolve the following diff %s: %w", cmp.Diff(diff.old, diff.new), ErrNotImplemented)
+	}
+	return nil, nil
+}
+
+// buildAggregateSignature builds the fully-qualified name and arguments used to reference the aggregate, e.g.,
+// `"public"."avg_custom"(numeric)`. Aggregates without arguments are referenced with `*`.
+func buildAggregateSignature(aggregate schema.Aggregate) string {
+	if len(aggregate.InputTypes) == 0 {
+		return strings.TrimSuffix(aggregate.GetFQEscapedName(), "()") + "(*)"
+	}
+	return aggregate.GetFQEscapedName()
+}
+
+func (a *aggregateSQLVertexGenerator) GetSQLVertexId(aggregate schema.Aggregate, diffType diffType) sqlVertexId {
+	return buildSchemaObjVertexId("aggregate", aggregate.GetFQEscapedName(), diffType)
+}
+
+func (a *aggregateSQLVertexGenerator) GetAddAlterDependencies(newAggregate, _ schema.Aggregate) ([]dependency, error) {
+	deps := []dependency{
+		mustRun(a.GetSQLVertexId(newAggregate, diffTypeAddAlter)).after(a.GetSQLVertexId(newAggregate, diffTypeDelete)),
+	}
+	// The support functions must exist before the aggregate is created
+	for _, function := range newAggregate.DependsOnFunctions {
+		deps = append(deps, mustRun(a.GetSQLVertexId(newAggregate, diffTypeAddAlter)).after(buildFunctionVertexId(function, diffTypeAddAlter)))
+	}
+	return deps, nil
+}
+
+func (a *aggregateSQLVertexGenerator) GetDeleteDependencies(aggregate schema.Aggregate) ([]dependency, error) {
+	var deps []dependency
+	for _, function := range aggregate.DependsOnFunctions {
+		deps = append(deps, mustRun(a.GetSQLVertexId(aggregate, diffTypeDelete)).before(buildFunctionVertexId(function, diffTypeDelete)))
+	}
+	return deps, nil
+}
@@ -0,0 +1,128 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestAggregateSQLVertexGenerator(t *testing.T) {
+	initialCondition := "it's"
+	for _, tc := range []struct {
+		name      string
+		aggregate schema.Aggregate
+
+		expectedAddDDL    string
+		expectedDeleteDDL string
+	}{
+		{
+			name: "Parallel aggregate",
+			aggregate: schema.Aggregate{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"avg_custom\"(numeric)"},
+				InputTypes:          "numeric",
+				StateType:           "internal",
+				StateFunction:       schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"add_state\""},
+				FinalFunction:       &schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"final_state\""},
+				FinalFunctionExtra:  true,
+				CombineFunction:     &schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"combine_state\""},
+				SerialFunction:      &schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"serialize_state\""},
+				DeserialFunction:    &schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"deserialize_state\""},
+				InitialCondition:    &initialCondition,
+				Parallel:            "SAFE",
+			},
+			expectedAddDDL: "CREATE AGGREGATE \"public\".\"avg_custom\"(numeric) (\n" +
+				"\tSFUNC = \"public\".\"add_state\",\n" +
+				"\tSTYPE = internal,\n" +
+				"\tFINALFUNC = \"public\".\"final_state\",\n" +
+				"\tFINALFUNC_EXTRA,\n" +
+				"\tCOMBINEFUNC = \"public\".\"combine_state\",\n" +
+				"\tSERIALFUNC = \"public\".\"serialize_state\",\n" +
+				"\tDESERIALFUNC = \"public\".\"deserialize_state\",\n" +
+				"\tINITCOND = 'it''s',\n" +
+				"\tPARALLEL = SAFE\n" +
+				")",
+			expectedDeleteDDL: "DROP AGGREGATE \"public\".\"avg_custom\"(numeric) CASCADE",
+		},
+		{
+			name: "Aggregate without arguments",
+			aggregate: schema.Aggregate{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"count_rows\"()"},
+				StateType:           "bigint",
+				StateFunction:       schema.SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"int8inc\""},
+				Parallel:            "UNSAFE",
+			},
+			expectedAddDDL: "CREATE AGGREGATE \"public\".\"count_rows\"(*) (\n" +
+				"\tSFUNC = \"pg_catalog\".\"int8inc\",\n" +
+				"\tSTYPE = bigint\n" +
+				")",
+			expectedDeleteDDL: "DROP AGGREGATE \"public\".\"count_rows\"(*) CASCADE",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			generator := &aggregateSQLVertexGenerator{}
+
+			addStmts, err := generator.Add(tc.aggregate)
+			require.NoError(t, err)
+			require.Len(t, addStmts, 1)
+			assert.Equal(t, tc.expectedAddDDL, addStmts[0].DDL)
+			assert.Empty(t, addStmts[0].Hazards)
+
+			deleteStmts, err := generator.Delete(tc.aggregate)
+			require.NoError(t, err)
+			require.Len(t, deleteStmts, 1)
+			assert.Equal(t, tc.expectedDeleteDDL, deleteStmts[0].DDL)
+			assert.Equal(t, []MigrationHazard{migrationHazardAggregateDropped}, deleteStmts[0].Hazards)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_AggregateChanged(t *testing.T) {
+	stateFunctionName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"add_state\"(numeric, numeric)"}
+	buildSchema := func(initialCondition string, stateFunctionDef string) schema.Schema {
+		return schema.Schema{
+			Functions: []schema.Function{{
+				SchemaQualifiedName: stateFunctionName,
+				FunctionDef:         stateFunctionDef,
+				Language:            "sql",
+			}},
+			Aggregates: []schema.Aggregate{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"sum_custom\"(numeric)"},
+				InputTypes:          "numeric",
+				StateType:           "numeric",
+				StateFunction:       schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"add_state\""},
+				InitialCondition:    &initialCondition,
+				Parallel:            "UNSAFE",
+				DependsOnFunctions:  []schema.SchemaQualifiedName{stateFunctionName},
+			}},
+		}
+	}
+
+	oldSchema := buildSchema("0", "CREATE OR REPLACE FUNCTION public.add_state(numeric, numeric) RETURNS numeric LANGUAGE sql AS $$ SELECT $1 + $2 $$")
+	newSchema := buildSchema("1", "CREATE OR REPLACE FUNCTION public.add_state(numeric, numeric) RETURNS numeric LANGUAGE sql AS $$ SELECT $1 + $2 + 0 $$")
+
+	stmts, err := generateMigrationStatements(oldSchema, newSchema, &planOptions{})
+	require.NoError(t, err)
+
+	stmtIdx := func(prefix string) int {
+		for i, stmt := range stmts {
+			if strings.HasPrefix(stmt.DDL, prefix) {
+				return i
+			}
+		}
+		require.Failf(t, "statement not found", "%q not found in %v", prefix, stmts)
+		return -1
+	}
+	dropAggregateIdx := stmtIdx("DROP AGGREGATE")
+	replaceFunctionIdx := stmtIdx("CREATE OR REPLACE FUNCTION")
+	createAggregateIdx := stmtIdx("CREATE AGGREGATE")
+
+	// The aggregate is re-created after its state function is replaced
+	require.Len(t, stmts, 3)
+	assert.Less(t, dropAggregateIdx, createAggregateIdx)
+	assert.Less(t, replaceFunctionIdx, createAggregateIdx)
+	assert.Equal(t, "CREATE AGGREGATE \"public\".\"sum_custom\"(numeric) (\n\tSFUNC = \"public\".\"add_state\",\n\tSTYPE = numeric,\n\tINITCOND = '1'\n)", stmts[createAggregateIdx].DDL)
+	assert.Equal(t, []MigrationHazard{migrationHazardAggregateDropped}, stmts[dropAggregateIdx].Hazards)
+}
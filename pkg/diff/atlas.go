@@ -0,0 +1,30 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExportAtlasHCL writes the plan as an Atlas migration file, such that teams that apply their migrations with Atlas can
+// use pg-schema-diff to generate them. Atlas migration files are plain SQL: each statement is terminated by a semicolon
+// and preceded by `SET` statements that apply its statement and lock timeouts, which are reset at the end of the file,
+// as well as comments with its index and hazards. If any statement cannot run in a transaction, e.g.,
+// `CREATE INDEX CONCURRENTLY`, the file starts with the `atlas:txmode none` directive, since Atlas runs each file in a
+// transaction by default. Advisory statements are only written as comments, since they are not executed as part of the
+// migration.
+//
+// The file's checksum in atlas.sum is not updated; run `atlas migrate hash` after adding the file to the migration
+// directory.
+func (p Plan) ExportAtlasHCL(w io.Writer) error {
+	sb := strings.Builder{}
+	if p.hasNoTransactionStatements() {
+		sb.WriteString("-- atlas:txmode none\n\n")
+	}
+	writeSQLMigrationStatements(&sb, p.Statements)
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("writing Atlas migration file: %w", err)
+	}
+	return nil
+}
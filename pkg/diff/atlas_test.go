@@ -0,0 +1,53 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan_ExportAtlasHCL(t *testing.T) {
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: `ALTER TABLE "public"."foobar" ADD COLUMN "bar" text`, Timeout: 3 * time.Second, LockTimeout: 3 * time.Second},
+			{
+				DDL:                   `CREATE INDEX CONCURRENTLY foobar_bar_idx ON public.foobar USING btree (bar)`,
+				Timeout:               20 * time.Minute,
+				LockTimeout:           3 * time.Second,
+				RequiresNoTransaction: true,
+				Hazards:               []MigrationHazard{{Type: MigrationHazardTypeIndexBuild, Message: "This might affect\nperformance"}},
+			},
+			{DDL: "ANALYZE \"public\".\"foobar\"", IsAdvisory: true},
+		},
+	}
+
+	sb := strings.Builder{}
+	require.NoError(t, plan.ExportAtlasHCL(&sb))
+	assert.Equal(t, `-- atlas:txmode none
+
+-- Statement 1
+SET SESSION statement_timeout = 3000;
+SET SESSION lock_timeout = 3000;
+ALTER TABLE "public"."foobar" ADD COLUMN "bar" text;
+
+-- Statement 2
+-- Hazard INDEX_BUILD: This might affect performance
+SET SESSION statement_timeout = 1200000;
+SET SESSION lock_timeout = 3000;
+CREATE INDEX CONCURRENTLY foobar_bar_idx ON public.foobar USING btree (bar);
+
+-- Statement 3
+-- Advisory: This statement is not executed as part of the migration
+-- ANALYZE "public"."foobar";
+
+RESET statement_timeout;
+RESET lock_timeout;
+`, sb.String())
+
+	sb.Reset()
+	require.NoError(t, Plan{Statements: []Statement{{DDL: "CREATE OR REPLACE FUNCTION public.one()\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT 1 $function$\n"}}}.ExportAtlasHCL(&sb))
+	assert.Equal(t, "-- Statement 1\nCREATE OR REPLACE FUNCTION public.one()\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT 1 $function$;\n", sb.String())
+}
@@ -0,0 +1,81 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+const brinIndexMethod = "brin"
+
+var (
+	// indexStorageParametersRegex matches the WITH clause of an index definition from pg_get_indexdef, e.g.,
+	// ` WITH (pages_per_range='16', autosummarize='on')`
+	indexStorageParametersRegex = regexp.MustCompile(`\s+WITH \([^()]*\)`)
+
+	migrationHazardBrinPagesPerRangeChanged = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresAccessExclusiveLock,
+		Message: "Changing pages_per_range locks out all accesses to the index. It should be fast. The existing page " +
+			"ranges keep their size until the index is rebuilt, e.g., via REINDEX INDEX CONCURRENTLY.",
+	}
+)
+
+// canAlterBrinParameters returns true if the indexes only differ in their BRIN storage parameters, i.e.,
+// pages_per_range and autosummarize, which can be changed without re-creating the index
+func canAlterBrinParameters(old, new schema.Index) bool {
+	if old.Method != brinIndexMethod || new.Method != brinIndexMethod {
+		return false
+	}
+	return removeIndexStorageParameters(string(old.GetIndexDefStmt)) == removeIndexStorageParameters(string(new.GetIndexDefStmt))
+}
+
+func removeIndexStorageParameters(indexDef string) string {
+	return indexStorageParametersRegex.ReplaceAllString(indexDef, "")
+}
+
+// buildAlterBrinParametersStatements builds the statements to change the BRIN storage parameters of the index. The
+// parameters that are not set on the new index are reset to their defaults.
+func buildAlterBrinParametersStatements(old, new schema.Index) []Statement {
+	var setParams, resetParams []string
+	var setHazards, resetHazards []MigrationHazard
+	if !cmp.Equal(old.PagesPerRange, new.PagesPerRange) {
+		if new.PagesPerRange == nil {
+			resetParams = append(resetParams, "pages_per_range")
+			resetHazards = append(resetHazards, migrationHazardBrinPagesPerRangeChanged)
+		} else {
+			setParams = append(setParams, fmt.Sprintf("pages_per_range = %d", *new.PagesPerRange))
+			setHazards = append(setHazards, migrationHazardBrinPagesPerRangeChanged)
+		}
+	}
+	if !cmp.Equal(old.Autosummarize, new.Autosummarize) {
+		if new.Autosummarize == nil {
+			resetParams = append(resetParams, "autosummarize")
+		} else {
+			setParams = append(setParams, fmt.Sprintf("autosummarize = %s", strconv.FormatBool(*new.Autosummarize)))
+		}
+	}
+
+	alterIndexPrefix := fmt.Sprintf("ALTER INDEX %s", new.GetSchemaQualifiedName().GetFQEscapedName())
+	var stmts []Statement
+	if len(setParams) > 0 {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s SET (%s)", alterIndexPrefix, strings.Join(setParams, ", ")),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     setHazards,
+		})
+	}
+	if len(resetParams) > 0 {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s RESET (%s)", alterIndexPrefix, strings.Join(resetParams, ", ")),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     resetHazards,
+		})
+	}
+	return stmts
+}
@@ -0,0 +1,117 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestBrinIndexParameters(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	table := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             []schema.Column{{Name: "created_at", Type: "timestamp with time zone"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	pagesPerRange16, pagesPerRange32 := 16, 32
+	autosummarizeOn := true
+	buildBrinIndex := func(pagesPerRange *int, autosummarize *bool, def string) schema.Index {
+		return schema.Index{
+			OwningTable:     foobar,
+			Name:            "created_at_idx",
+			Columns:         []string{"created_at"},
+			Method:          "brin",
+			PagesPerRange:   pagesPerRange,
+			Autosummarize:   autosummarize,
+			GetIndexDefStmt: schema.GetIndexDefStatement(def),
+		}
+	}
+
+	for _, tc := range []struct {
+		name            string
+		oldIndex        schema.Index
+		newIndex        schema.Index
+		expectedDDL     []string
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name:            "Change pages_per_range",
+			oldIndex:        buildBrinIndex(&pagesPerRange16, nil, "CREATE INDEX created_at_idx ON public.foobar USING brin (created_at) WITH (pages_per_range='16')"),
+			newIndex:        buildBrinIndex(&pagesPerRange32, nil, "CREATE INDEX created_at_idx ON public.foobar USING brin (created_at) WITH (pages_per_range='32')"),
+			expectedDDL:     []string{`ALTER INDEX "public"."created_at_idx" SET (pages_per_range = 32)`},
+			expectedHazards: []MigrationHazard{migrationHazardBrinPagesPerRangeChanged},
+		},
+		{
+			name:        "Set autosummarize",
+			oldIndex:    buildBrinIndex(&pagesPerRange16, nil, "CREATE INDEX created_at_idx ON public.foobar USING brin (created_at) WITH (pages_per_range='16')"),
+			newIndex:    buildBrinIndex(&pagesPerRange16, &autosummarizeOn, "CREATE INDEX created_at_idx ON public.foobar USING brin (created_at) WITH (pages_per_range='16', autosummarize='on')"),
+			expectedDDL: []string{`ALTER INDEX "public"."created_at_idx" SET (autosummarize = true)`},
+		},
+		{
+			name:            "Reset pages_per_range and set autosummarize",
+			oldIndex:        buildBrinIndex(&pagesPerRange16, nil, "CREATE INDEX created_at_idx ON public.foobar USING brin (created_at) WITH (pages_per_range='16')"),
+			newIndex:        buildBrinIndex(nil, &autosummarizeOn, "CREATE INDEX created_at_idx ON public.foobar USING brin (created_at) WITH (autosummarize='on')"),
+			expectedDDL:     []string{`ALTER INDEX "public"."created_at_idx" SET (autosummarize = true)`, `ALTER INDEX "public"."created_at_idx" RESET (pages_per_range)`},
+			expectedHazards: []MigrationHazard{migrationHazardBrinPagesPerRangeChanged},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(
+				schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{tc.oldIndex}},
+				schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{tc.newIndex}},
+				&planOptions{},
+			)
+			require.NoError(t, err)
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			// The index is not rebuilt
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+
+	t.Run("Change method from BRIN to btree", func(t *testing.T) {
+		oldIndex := buildBrinIndex(&pagesPerRange16, nil, "CREATE INDEX created_at_idx ON public.foobar USING brin (created_at) WITH (pages_per_range='16')")
+		newIndex := schema.Index{
+			OwningTable:     foobar,
+			Name:            "created_at_idx",
+			Columns:         []string{"created_at"},
+			Method:          "btree",
+			GetIndexDefStmt: "CREATE INDEX created_at_idx ON public.foobar USING btree (created_at)",
+		}
+		stmts, err := generateMigrationStatements(
+			schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{oldIndex}},
+			schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{newIndex}},
+			&planOptions{},
+		)
+		require.NoError(t, err)
+		// The index is re-created using the new method
+		require.Len(t, stmts, 3)
+		assert.True(t, strings.HasPrefix(stmts[0].DDL, "ALTER INDEX \"public\".\"created_at_idx\" RENAME TO"), stmts[0].DDL)
+		assert.Equal(t, "CREATE INDEX CONCURRENTLY created_at_idx ON public.foobar USING btree (created_at)", stmts[1].DDL)
+		assert.Equal(t, []MigrationHazard{migrationHazardIndexBuildConcurrently}, stmts[1].Hazards)
+		assert.True(t, strings.HasPrefix(stmts[2].DDL, "DROP INDEX CONCURRENTLY"), stmts[2].DDL)
+		assert.Equal(t, []MigrationHazard{migrationHazardIndexDroppedQueryPerf}, stmts[2].Hazards)
+	})
+
+	t.Run("Other changes to a BRIN index re-create it", func(t *testing.T) {
+		oldIndex := buildBrinIndex(&pagesPerRange16, nil, "CREATE INDEX created_at_idx ON public.foobar USING brin (created_at) WITH (pages_per_range='16')")
+		newIndex := buildBrinIndex(&pagesPerRange32, nil, "CREATE INDEX created_at_idx ON public.foobar USING brin (created_at) WITH (pages_per_range='32') WHERE (created_at > '2020-01-01')")
+		newIndex.Predicate = "(created_at > '2020-01-01')"
+		stmts, err := generateMigrationStatements(
+			schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{oldIndex}},
+			schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{newIndex}},
+			&planOptions{},
+		)
+		require.NoError(t, err)
+		require.Len(t, stmts, 3)
+		assert.Equal(t, "CREATE INDEX CONCURRENTLY created_at_idx ON public.foobar USING brin (created_at) WITH (pages_per_range='32') WHERE (created_at > '2020-01-01')", stmts[1].DDL)
+	})
+}
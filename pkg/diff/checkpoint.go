@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// CheckpointStore stores the progress of a plan's execution, such that an execution that failed midway can be resumed
+// from where it stopped. See Executor.RunWithCheckpoint.
+type CheckpointStore interface {
+	// Load returns the index of the last completed statement, or -1 if no statements have been completed
+	Load() (int, error)
+	// Save records the index of the last completed statement
+	Save(int) error
+}
+
+// RunWithCheckpoint executes the plan against the database, resuming after the last completed statement recorded in
+// the checkpointStore. Each statement is committed on its own, and the checkpoint is saved after each statement
+// completes, such that a failed execution can be resumed by calling RunWithCheckpoint again with the same plan and
+// checkpointStore. Like RunInTransaction, the plan's hooks and progress reporter are run around the statements, and
+// plans with unacknowledged hazards are rejected.
+//
+// A statement might be committed without its checkpoint being saved, e.g., if the process crashes in between. To resume
+// safely, statements are made idempotent where possible, e.g., `CREATE TABLE` is run as `CREATE TABLE IF NOT EXISTS`
+// and `ADD COLUMN` as `ADD COLUMN IF NOT EXISTS`. Concurrent index builds are not, since a failed build leaves an
+// invalid index behind, which must be dropped before resuming.
+func (e *Executor) RunWithCheckpoint(ctx context.Context, plan Plan, db *sql.DB, checkpointStore CheckpointStore) error {
+	if err := plan.checkHazardsAcknowledged(); err != nil {
+		return err
+	}
+
+	checkpoint, err := checkpointStore.Load()
+	if err != nil {
+		return fmt.Errorf("loading checkpoint: %w", err)
+	}
+	if checkpoint < -1 || checkpoint >= len(plan.Statements) {
+		return fmt.Errorf("checkpoint %d is out of range for a plan with %d statements. The checkpoint might be from a different plan", checkpoint, len(plan.Statements))
+	}
+	if checkpoint >= 0 {
+		e.logger.Warnf("Resuming the plan after statement %d, which was completed by a previous execution", checkpoint)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	guardedPlan := plan
+	guardedPlan.Statements = nil
+	for _, stmt := range plan.Statements {
+		stmt.DDL = addIdempotencyGuard(stmt.DDL)
+		guardedPlan.Statements = append(guardedPlan.Statements, stmt)
+	}
+
+	return guardedPlan.RunWithMigrationHooks(ctx, func(ctx context.Context, plan Plan) error {
+		for idx := checkpoint + 1; idx < len(plan.Statements); idx++ {
+			var err error
+			if plan.Statements[idx].RequiresNoTransaction {
+				err = e.runBatchWithoutTransaction(ctx, conn, plan, []int{idx})
+			} else {
+				err = e.runBatchInTransaction(ctx, conn, plan, []int{idx})
+			}
+			if err != nil {
+				var execErr ExecutionError
+				if errors.As(err, &execErr) {
+					for i := 0; i < idx; i++ {
+						execErr.PermanentStatementIndexes = append(execErr.PermanentStatementIndexes, i)
+					}
+					return execErr
+				}
+				return err
+			}
+			if err := checkpointStore.Save(idx); err != nil {
+				return fmt.Errorf("saving checkpoint after statement %d: %w", idx, err)
+			}
+			if err := e.waitForReplicationLag(ctx, plan, []int{idx}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryCheckpointStore struct {
+	checkpoint int
+	saveErr    error
+}
+
+func newMemoryCheckpointStore() *memoryCheckpointStore {
+	return &memoryCheckpointStore{checkpoint: -1}
+}
+
+func (s *memoryCheckpointStore) Load() (int, error) {
+	return s.checkpoint, nil
+}
+
+func (s *memoryCheckpointStore) Save(checkpoint int) error {
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.checkpoint = checkpoint
+	return nil
+}
+
+type recordingProgressReporter struct {
+	steps []int
+}
+
+func (r *recordingProgressReporter) ReportProgress(_ context.Context, step int, _ int, _ Statement) {
+	r.steps = append(r.steps, step)
+}
+
+func TestRunWithCheckpoint_InvalidCheckpoint(t *testing.T) {
+	plan := Plan{Statements: []Statement{{DDL: `CREATE SCHEMA "foo"`}}}
+	// The checkpoint is validated before connecting to the database
+	err := NewExecutor().RunWithCheckpoint(context.Background(), plan, nil, &memoryCheckpointStore{checkpoint: 1})
+	assert.ErrorContains(t, err, "checkpoint 1 is out of range for a plan with 1 statements")
+}
+
+func (suite *planGeneratorTestSuite) TestExecutor_RunWithCheckpoint() {
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: "CREATE TABLE foobar(id INT PRIMARY KEY, val TEXT)", Timeout: 3 * time.Second},
+			{DDL: "INSERT INTO foobar VALUES (1, 'a')", Timeout: 3 * time.Second},
+			{DDL: "ALTER TABLE missing_table ADD COLUMN val TEXT", Timeout: 3 * time.Second},
+			{DDL: "CREATE INDEX CONCURRENTLY foobar_val_idx ON foobar(val)", Timeout: time.Minute, RequiresNoTransaction: true},
+		},
+	}
+	checkpointStore := newMemoryCheckpointStore()
+	err := NewExecutor().RunWithCheckpoint(context.Background(), plan, connPool, checkpointStore)
+	var execErr ExecutionError
+	suite.Require().ErrorAs(err, &execErr)
+	suite.Equal(2, execErr.StatementIndex)
+	suite.Equal([]int{0, 1}, execErr.PermanentStatementIndexes)
+	suite.Equal(1, checkpointStore.checkpoint)
+
+	// Fix the failing statement and resume. The completed statements are not run again, otherwise the insert would
+	// fail with a duplicate key
+	plan.Statements[2] = Statement{DDL: "ALTER TABLE foobar ADD COLUMN other_val TEXT", Timeout: 3 * time.Second}
+	progressReporter := &recordingProgressReporter{}
+	plan = plan.SetProgressReporter(progressReporter)
+	suite.Require().NoError(NewExecutor().RunWithCheckpoint(context.Background(), plan, connPool, checkpointStore))
+	suite.Equal(3, checkpointStore.checkpoint)
+	suite.Equal([]int{3, 4}, progressReporter.steps)
+
+	_, err = connPool.ExecContext(context.Background(), "SELECT val, other_val FROM foobar")
+	suite.NoError(err)
+}
+
+func (suite *planGeneratorTestSuite) TestExecutor_RunWithCheckpointStatementCompletedWithoutCheckpoint() {
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: "CREATE TABLE foobar(id INT PRIMARY KEY)", Timeout: 3 * time.Second},
+			{DDL: "ALTER TABLE foobar ADD COLUMN val TEXT", Timeout: 3 * time.Second},
+		},
+	}
+	// Simulate a crash after the first statement is committed but before its checkpoint is saved
+	saveErr := errors.New("some error")
+	checkpointStore := &memoryCheckpointStore{checkpoint: -1, saveErr: saveErr}
+	err := NewExecutor().RunWithCheckpoint(context.Background(), plan, connPool, checkpointStore)
+	suite.ErrorIs(err, saveErr)
+	suite.Equal(-1, checkpointStore.checkpoint)
+
+	// The first statement is run again, which succeeds because of its idempotency guard
+	checkpointStore.saveErr = nil
+	suite.Require().NoError(NewExecutor().RunWithCheckpoint(context.Background(), plan, connPool, checkpointStore))
+	suite.Equal(1, checkpointStore.checkpoint)
+	_, err = connPool.ExecContext(context.Background(), "SELECT val FROM foobar")
+	suite.NoError(err)
+}
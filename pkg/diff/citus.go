@@ -0,0 +1,55 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+// WithCitusMode configures the plan generation for a database that distributes tables with Citus. The distribution
+// column of each distributed table in the current schema is fetched, and statements that Citus cannot execute without
+// re-distributing the table have a MigrationHazardTypeImpossibleWithoutDowntime hazard: dropping the table, dropping
+// or changing the type of its distribution column, and adding a primary key that does not include its distribution
+// column.
+//
+// The distribution columns are only fetched from databases where Citus is installed, so schemas from DDL, which are
+// fetched from a temporary database, are not distributed.
+func WithCitusMode() PlanOpt {
+	return func(opts *planOptions) {
+		opts.getSchemaOpts = append(opts.getSchemaOpts, schema.WithCitusDistributionColumns())
+	}
+}
+
+var migrationHazardCitusDistributedTableDropped = MigrationHazard{
+	Type: MigrationHazardTypeImpossibleWithoutDowntime,
+	Message: "The table is distributed by Citus. Dropping it drops its shards on all nodes, and re-creating it " +
+		"requires re-distributing it via create_distributed_table.",
+}
+
+func migrationHazardCitusDistributionColumnChanged(column string) MigrationHazard {
+	return MigrationHazard{
+		Type: MigrationHazardTypeImpossibleWithoutDowntime,
+		Message: fmt.Sprintf("Column %q is the Citus distribution column of the table. Citus cannot drop or change "+
+			"the type of the distribution column without un-distributing and re-distributing the table.", column),
+	}
+}
+
+func migrationHazardCitusPrimaryKeyExcludesDistributionColumn(column string) MigrationHazard {
+	return MigrationHazard{
+		Type: MigrationHazardTypeImpossibleWithoutDowntime,
+		Message: fmt.Sprintf("The table is distributed by Citus on column %q, which the primary key does not "+
+			"include. Citus cannot add a primary key that does not include the distribution column.", column),
+	}
+}
+
+// buildDistributionColumnsByTableName builds a map of table name to the Citus distribution column of the table. Tables
+// that are not distributed are omitted.
+func buildDistributionColumnsByTableName(tables []schema.Table) map[string]string {
+	distributionColumnsByTableName := make(map[string]string)
+	for _, table := range tables {
+		if len(table.DistributionColumn) > 0 {
+			distributionColumnsByTableName[table.GetName()] = table.DistributionColumn
+		}
+	}
+	return distributionColumnsByTableName
+}
@@ -0,0 +1,117 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestCitusDistributedTables(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+	distributedTable := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns: []schema.Column{
+			{Name: "tenant_id", Type: "integer"},
+			{Name: "id", Type: "integer"},
+			{Name: "foo", Type: "text"},
+		},
+		ReplicaIdentity:    schema.ReplicaIdentityDefault,
+		DistributionColumn: "tenant_id",
+	}
+	// Schemas from DDL are fetched from a database without Citus, so their tables are not distributed
+	undistributedTable := distributedTable
+	undistributedTable.DistributionColumn = ""
+
+	withColumns := func(table schema.Table, columns ...schema.Column) schema.Table {
+		table.Columns = columns
+		return table
+	}
+	buildPk := func(columns ...string) schema.Index {
+		return schema.Index{
+			OwningTable:     foobar,
+			Name:            "foobar_pkey",
+			Columns:         columns,
+			IsUnique:        true,
+			Method:          "btree",
+			Constraint:      &schema.IndexConstraint{Type: schema.PkIndexConstraintType, EscapedConstraintName: `"foobar_pkey"`},
+			GetIndexDefStmt: schema.GetIndexDefStatement("CREATE UNIQUE INDEX foobar_pkey ON public.foobar USING btree (id)"),
+		}
+	}
+
+	for _, tc := range []struct {
+		name                   string
+		oldSchema              schema.Schema
+		newSchema              schema.Schema
+		expectDowntimeHazardIn string
+	}{
+		{
+			name:                   "Drop distributed table",
+			oldSchema:              schema.Schema{Tables: []schema.Table{distributedTable}},
+			newSchema:              schema.Schema{},
+			expectDowntimeHazardIn: `DROP TABLE "public"."foobar"`,
+		},
+		{
+			name:                   "Drop distribution column",
+			oldSchema:              schema.Schema{Tables: []schema.Table{distributedTable}},
+			newSchema:              schema.Schema{Tables: []schema.Table{withColumns(undistributedTable, distributedTable.Columns[1:]...)}},
+			expectDowntimeHazardIn: `ALTER TABLE "public"."foobar" DROP COLUMN "tenant_id"`,
+		},
+		{
+			name:      "Change type of distribution column",
+			oldSchema: schema.Schema{Tables: []schema.Table{distributedTable}},
+			newSchema: schema.Schema{Tables: []schema.Table{withColumns(undistributedTable,
+				schema.Column{Name: "tenant_id", Type: "bigint"},
+				distributedTable.Columns[1],
+				distributedTable.Columns[2],
+			)}},
+			expectDowntimeHazardIn: `ALTER TABLE "public"."foobar" ALTER COLUMN "tenant_id" SET DATA TYPE bigint using "tenant_id"::bigint`,
+		},
+		{
+			name:                   "Add primary key without distribution column",
+			oldSchema:              schema.Schema{Tables: []schema.Table{distributedTable}},
+			newSchema:              schema.Schema{Tables: []schema.Table{undistributedTable}, Indexes: []schema.Index{buildPk("id")}},
+			expectDowntimeHazardIn: `ALTER TABLE "public"."foobar" ADD CONSTRAINT "foobar_pkey" PRIMARY KEY USING INDEX "foobar_pkey"`,
+		},
+		{
+			name:      "Add primary key with distribution column",
+			oldSchema: schema.Schema{Tables: []schema.Table{distributedTable}},
+			newSchema: schema.Schema{Tables: []schema.Table{undistributedTable}, Indexes: []schema.Index{buildPk("tenant_id", "id")}},
+		},
+		{
+			name:      "Change non-distribution columns",
+			oldSchema: schema.Schema{Tables: []schema.Table{distributedTable}},
+			newSchema: schema.Schema{Tables: []schema.Table{withColumns(undistributedTable,
+				distributedTable.Columns[0],
+				schema.Column{Name: "id", Type: "bigint"},
+				schema.Column{Name: "bar", Type: "text"},
+			)}},
+		},
+		{
+			name:      "Drop table that is not distributed",
+			oldSchema: schema.Schema{Tables: []schema.Table{undistributedTable}},
+			newSchema: schema.Schema{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(tc.oldSchema, tc.newSchema, &planOptions{ignoreChangesToColOrder: true})
+			require.NoError(t, err)
+			require.NotEmpty(t, stmts)
+
+			var stmtsWithDowntimeHazard []string
+			for _, stmt := range stmts {
+				for _, hazard := range stmt.Hazards {
+					if hazard.Type == MigrationHazardTypeImpossibleWithoutDowntime {
+						stmtsWithDowntimeHazard = append(stmtsWithDowntimeHazard, stmt.DDL)
+					}
+				}
+			}
+			if len(tc.expectDowntimeHazardIn) == 0 {
+				assert.Empty(t, stmtsWithDowntimeHazard)
+			} else {
+				assert.Equal(t, []string{tc.expectDowntimeHazardIn}, stmtsWithDowntimeHazard)
+			}
+		})
+	}
+}
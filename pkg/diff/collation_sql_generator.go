@@ -0,0 +1,76 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var migrationHazardCollationRecreated = MigrationHazard{
+	Type: MigrationHazardTypeHasUntrackableDependencies,
+	Message: "Collations cannot be altered, so the collation will be dropped and re-created. This will fail if any " +
+		"columns, indexes, or other objects still use the collation. Indexes that use the collation must be rebuilt.",
+}
+
+// collationSQLGenerator is a SQL generator for collations. Collations are added before and dropped after all objects
+// that might use them, i.e., domains, composite types, columns, and indexes.
+type collationSQLGenerator struct{}
+
+func (c *collationSQLGenerator) Add(collation schema.Collation) ([]Statement, error) {
+	options := []string{fmt.Sprintf("PROVIDER = %s", collation.Provider)}
+	if len(collation.Locale) > 0 {
+		options = append(options, fmt.Sprintf("LOCALE = %s", quoteCollationLocale(collation.Locale)))
+	}
+	if len(collation.LcCollate) > 0 {
+		options = append(options, fmt.Sprintf("LC_COLLATE = %s", quoteCollationLocale(collation.LcCollate)))
+	}
+	if len(collation.LcCtype) > 0 {
+		options = append(options, fmt.Sprintf("LC_CTYPE = %s", quoteCollationLocale(collation.LcCtype)))
+	}
+	if !collation.IsDeterministic {
+		options = append(options, "DETERMINISTIC = false")
+	}
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("CREATE COLLATION %s (%s)", collation.GetFQEscapedName(), strings.Join(options, ", ")),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (c *collationSQLGenerator) Delete(collation schema.Collation) ([]Statement, error) {
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("DROP COLLATION %s", collation.GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (c *collationSQLGenerator) Alter(diff collationDiff) ([]Statement, error) {
+	if diff.old == diff.new {
+		return nil, nil
+	}
+
+	// Collations cannot be altered in place (ALTER COLLATION only supports renames and refreshing the version). Similar
+	// to domains, the collation must be re-created in the alter statement, since the normal delete -> add ordering
+	// would drop the collation after all other objects are migrated.
+	deletes, err := c.Delete(diff.old)
+	if err != nil {
+		return nil, fmt.Errorf("generating delete statements: %w", err)
+	}
+	adds, err := c.Add(diff.new)
+	if err != nil {
+		return nil, fmt.Errorf("generating add statements: %w", err)
+	}
+	stmts := append(deletes, adds...)
+	stmts[0].Hazards = append(stmts[0].Hazards, migrationHazardCollationRecreated)
+	return stmts, nil
+}
+
+func quoteCollationLocale(locale string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(locale, "'", "''"))
+}
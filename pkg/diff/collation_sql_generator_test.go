@@ -0,0 +1,72 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var collationName = schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"case_insensitive\""}
+
+func TestCollationSQLGenerator_Add(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		collation   schema.Collation
+		expectedDDL string
+	}{
+		{
+			name: "icu",
+			collation: schema.Collation{
+				SchemaQualifiedName: collationName,
+				Provider:            "icu",
+				Locale:              "und-u-ks-level2",
+			},
+			expectedDDL: "CREATE COLLATION \"public\".\"case_insensitive\" (PROVIDER = icu, LOCALE = 'und-u-ks-level2', DETERMINISTIC = false)",
+		},
+		{
+			name: "deterministic libc",
+			collation: schema.Collation{
+				SchemaQualifiedName: collationName,
+				Provider:            "libc",
+				LcCollate:           "C",
+				LcCtype:             "en_US.utf8",
+				IsDeterministic:     true,
+			},
+			expectedDDL: "CREATE COLLATION \"public\".\"case_insensitive\" (PROVIDER = libc, LC_COLLATE = 'C', LC_CTYPE = 'en_US.utf8')",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := (&collationSQLGenerator{}).Add(tc.collation)
+			require.NoError(t, err)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tc.expectedDDL, stmts[0].DDL)
+		})
+	}
+}
+
+func TestCollationSQLGenerator_Alter(t *testing.T) {
+	old := schema.Collation{SchemaQualifiedName: collationName, Provider: "icu", Locale: "und-u-ks-level2"}
+
+	stmts, err := (&collationSQLGenerator{}).Alter(collationDiff{oldAndNew: oldAndNew[schema.Collation]{old: old, new: old}})
+	require.NoError(t, err)
+	assert.Empty(t, stmts)
+
+	stmts, err = (&collationSQLGenerator{}).Alter(collationDiff{oldAndNew: oldAndNew[schema.Collation]{
+		old: old,
+		new: schema.Collation{SchemaQualifiedName: collationName, Provider: "icu", Locale: "und-u-ks-level1"},
+	}})
+	require.NoError(t, err)
+	var ddl []string
+	var hazards []MigrationHazard
+	for _, stmt := range stmts {
+		ddl = append(ddl, stmt.DDL)
+		hazards = append(hazards, stmt.Hazards...)
+	}
+	assert.Equal(t, []string{
+		"DROP COLLATION \"public\".\"case_insensitive\"",
+		"CREATE COLLATION \"public\".\"case_insensitive\" (PROVIDER = icu, LOCALE = 'und-u-ks-level1', DETERMINISTIC = false)",
+	}, ddl)
+	assert.Equal(t, []MigrationHazard{migrationHazardCollationRecreated}, hazards)
+}
@@ -0,0 +1,307 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+// ColumnRenameCandidate is a column that might have been renamed: the old column does not exist in the table in the new
+// schema, and the new column, which does not exist in the table in the current schema, has the same type and default.
+// Candidates are only detected if the plan is generated with WithDetectColumnRenames. Confirm a candidate via
+// Plan.ConfirmColumnRename to rename the column rather than dropping and re-adding it.
+type ColumnRenameCandidate struct {
+	Table schema.SchemaQualifiedName `json:"table"`
+	Old   string                     `json:"old"`
+	New   string                     `json:"new"`
+}
+
+// columnRename is a rename of a column configured via WithRenamedColumn or Plan.ConfirmColumnRename
+type columnRename struct {
+	table schema.SchemaQualifiedName
+	old   string
+	new   string
+}
+
+// ConfirmColumnRename confirms the column rename candidate, returning a new plan that renames the old column via
+// `ALTER TABLE ... RENAME COLUMN ... TO ...` rather than dropping the old column and adding the new column. Postgres
+// updates the views that reference the column, so they are only re-created if their definitions still differ from the
+// new schema, e.g., because the output column was renamed as well.
+//
+// Like ConfirmRename, the plan must be returned by Generate with WithDetectColumnRenames, and the new plan is not
+// validated. To validate it, generate the plan again with WithRenamedColumn.
+func (p Plan) ConfirmColumnRename(table schema.SchemaQualifiedName, oldCol, newCol string) (Plan, error) {
+	if p.renameState == nil {
+		return Plan{}, fmt.Errorf("plan was not generated with rename detection enabled")
+	}
+	candidate := ColumnRenameCandidate{Table: table, Old: oldCol, New: newCol}
+	var isCandidate bool
+	for _, c := range p.ColumnRenameCandidates {
+		if c == candidate {
+			isCandidate = true
+			break
+		}
+	}
+	if !isCandidate {
+		return Plan{}, fmt.Errorf("%s.%s -> %s is not a column rename candidate", table.GetFQEscapedName(), schema.EscapeIdentifier(oldCol), schema.EscapeIdentifier(newCol))
+	}
+
+	options := p.renameState.planOptions
+	options.columnRenames = append(append([]columnRename(nil), options.columnRenames...), columnRename{table: table, old: oldCol, new: newCol})
+	return buildPlan(p.renameState.currentSchema, p.renameState.newSchema, &options)
+}
+
+// detectColumnRenameCandidates finds the columns that were dropped from the tables in the old schema that might have
+// been renamed to the columns added to the same tables in the new schema. A dropped column and an added column are a
+// candidate if they have the same type, collation, and default. If a dropped column has several such candidates, only
+// the ones at the same position in the table are offered, if there are any.
+func detectColumnRenameCandidates(oldSchema, newSchema schema.Schema) []ColumnRenameCandidate {
+	newTablesByName := buildSchemaObjByNameMap(newSchema.Tables)
+
+	var candidates []ColumnRenameCandidate
+	for _, oldTable := range oldSchema.Tables {
+		newTable, ok := newTablesByName[oldTable.GetName()]
+		if !ok {
+			continue
+		}
+		oldColumnsByName := buildSchemaObjByNameMap(oldTable.Columns)
+		newColumnsByName := buildSchemaObjByNameMap(newTable.Columns)
+		for oldPosition, oldCol := range oldTable.Columns {
+			if _, ok := newColumnsByName[oldCol.Name]; ok {
+				continue
+			}
+			var matches, matchesAtPosition []ColumnRenameCandidate
+			for newPosition, newCol := range newTable.Columns {
+				if _, ok := oldColumnsByName[newCol.Name]; ok {
+					continue
+				}
+				if oldCol.Type != newCol.Type || oldCol.Collation != newCol.Collation || oldCol.Default != newCol.Default {
+					continue
+				}
+				match := ColumnRenameCandidate{Table: oldTable.SchemaQualifiedName, Old: oldCol.Name, New: newCol.Name}
+				matches = append(matches, match)
+				if oldPosition == newPosition {
+					matchesAtPosition = append(matchesAtPosition, match)
+				}
+			}
+			if len(matchesAtPosition) > 0 {
+				matches = matchesAtPosition
+			}
+			candidates = append(candidates, matches...)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Table != candidates[j].Table {
+			return candidates[i].Table.GetName() < candidates[j].Table.GetName()
+		}
+		if candidates[i].Old != candidates[j].Old {
+			return candidates[i].Old < candidates[j].Old
+		}
+		return candidates[i].New < candidates[j].New
+	})
+	return candidates
+}
+
+// buildRenameColumnStatement builds the statement to rename a column. Renaming a column does not move any data, so it
+// only briefly acquires an access exclusive lock. Postgres automatically updates the objects that reference the column
+// by number, e.g., indexes and views, but not the bodies of functions, so the functions in the schema that might
+// reference the column are included in the hazards.
+func buildRenameColumnStatement(s schema.Schema, rename columnRename) Statement {
+	hazards := []MigrationHazard{
+		{
+			Type:    MigrationHazardTypeAcquiresAccessExclusiveLock,
+			Message: "Renaming a column briefly acquires an access exclusive lock on the table. It does not rewrite the table, so it should be fast.",
+		},
+		{
+			Type: MigrationHazardTypeHasUntrackableDependencies,
+			Message: "Queries that reference the column by its old name will fail after the column is renamed. These " +
+				"references cannot be tracked.",
+		},
+	}
+	if functionNames := getFunctionsReferencingColumn(s, rename); len(functionNames) > 0 {
+		hazards = append(hazards, MigrationHazard{
+			Type: MigrationHazardTypeCorrectness,
+			Message: fmt.Sprintf("The bodies of the following functions might reference the column by its old name. "+
+				"Function bodies are not updated when a column is renamed, so they will fail until they are re-created: %s",
+				strings.Join(functionNames, ", ")),
+		})
+	}
+	return Statement{
+		DDL:         fmt.Sprintf("%s RENAME COLUMN %s TO %s", alterTablePrefix(rename.table), schema.EscapeIdentifier(rename.old), schema.EscapeIdentifier(rename.new)),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     hazards,
+	}
+}
+
+// getFunctionsReferencingColumn gets the names of the functions and procedures that might reference the column. A
+// function references the column if its parsed body references it, i.e., `table.column`, or its definition mentions both
+// the table and the column.
+func getFunctionsReferencingColumn(s schema.Schema, rename columnRename) []string {
+	tableName := unescapeIdentifier(rename.table.EscapedName)
+	mentionsTableAndColumn := func(def string) bool {
+		return containsIdentifier(def, tableName) && containsIdentifier(def, rename.old)
+	}
+
+	var names []string
+	for _, f := range s.Functions {
+		isReferenced := mentionsTableAndColumn(f.FunctionDef)
+		for _, ref := range f.ReferencedColumns {
+			if ref.TableName == tableName && ref.ColumnName == rename.old {
+				isReferenced = true
+			}
+		}
+		if isReferenced {
+			names = append(names, f.GetFQEscapedName())
+		}
+	}
+	for _, p := range s.Procedures {
+		if mentionsTableAndColumn(p.Def) {
+			names = append(names, p.GetFQEscapedName())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// containsIdentifier returns true if the definition contains the identifier, i.e., not as part of a longer identifier
+func containsIdentifier(def, identifier string) bool {
+	return regexp.MustCompile(`(^|[^\w$])` + regexp.QuoteMeta(identifier) + `($|[^\w$])`).MatchString(def)
+}
+
+// renameColumns returns a copy of the schema where the columns are renamed, i.e., the schema that Postgres would report
+// after running `ALTER TABLE ... RENAME COLUMN ... TO ...`. The column is renamed in the table and in the indexes and
+// constraints on the table. The views that reference the column are updated the way Postgres updates them, i.e., they
+// reference the new column but keep their output column names. Other objects that reference the column, e.g.,
+// functions, are left untouched, such that they are re-created if their definitions changed.
+//
+// Renames that were already applied, i.e., the old column does not exist and the new column does, are skipped. The
+// renames that were not already applied are returned.
+func renameColumns(s schema.Schema, renames []columnRename) (schema.Schema, []columnRename, error) {
+	if len(renames) == 0 {
+		return s, nil, nil
+	}
+
+	tablesByName := buildSchemaObjByNameMap(s.Tables)
+	var pendingRenames []columnRename
+	for _, rename := range renames {
+		table, ok := tablesByName[rename.table.GetName()]
+		if !ok {
+			return schema.Schema{}, nil, fmt.Errorf("cannot rename column of table %s because the table does not exist", rename.table.GetFQEscapedName())
+		}
+		columnsByName := buildSchemaObjByNameMap(table.Columns)
+		_, oldExists := columnsByName[rename.old]
+		_, newExists := columnsByName[rename.new]
+		switch {
+		case !oldExists && newExists:
+			continue
+		case !oldExists:
+			return schema.Schema{}, nil, fmt.Errorf("cannot rename column %q of table %s because it does not exist", rename.old, rename.table.GetFQEscapedName())
+		case newExists:
+			return schema.Schema{}, nil, fmt.Errorf("cannot rename column %q of table %s to %q because %q already exists", rename.old, rename.table.GetFQEscapedName(), rename.new, rename.new)
+		}
+		pendingRenames = append(pendingRenames, rename)
+		// Apply the rename, such that subsequent renames of the same table are validated against the renamed columns
+		for i := range table.Columns {
+			if table.Columns[i].Name == rename.old {
+				table.Columns = append([]schema.Column(nil), table.Columns...)
+				table.Columns[i].Name = rename.new
+			}
+		}
+		tablesByName[rename.table.GetName()] = table
+	}
+	if len(pendingRenames) == 0 {
+		return s, nil, nil
+	}
+
+	renamed := s.DeepCopy()
+	for _, rename := range pendingRenames {
+		renameColumn(&renamed, rename)
+	}
+	return renamed, pendingRenames, nil
+}
+
+func renameColumn(s *schema.Schema, rename columnRename) {
+	oldRefs := identifierForms(schema.EscapeIdentifier(rename.old))
+	newRef := simplestIdentifierForm(schema.EscapeIdentifier(rename.new))
+	renameInDef := func(def string) string {
+		return replaceIdentifierReferences(def, oldRefs, newRef)
+	}
+	renameInList := func(cols []string) {
+		for i, col := range cols {
+			if col == rename.old {
+				cols[i] = rename.new
+			}
+		}
+	}
+
+	for i := range s.Tables {
+		table := &s.Tables[i]
+		if table.SchemaQualifiedName != rename.table {
+			continue
+		}
+		for j := range table.Columns {
+			if table.Columns[j].Name == rename.old {
+				table.Columns[j].Name = rename.new
+			}
+		}
+		for j := range table.CheckConstraints {
+			renameInList(table.CheckConstraints[j].KeyColumns)
+			table.CheckConstraints[j].Expression = renameInDef(table.CheckConstraints[j].Expression)
+		}
+	}
+
+	for i := range s.Indexes {
+		idx := &s.Indexes[i]
+		if idx.OwningTable != rename.table {
+			continue
+		}
+		renameInList(idx.Columns)
+		renameInList(idx.IncludedColumns)
+		for j := range idx.Expressions {
+			idx.Expressions[j] = renameInDef(idx.Expressions[j])
+		}
+		idx.Predicate = renameInDef(idx.Predicate)
+		// Only rename the column after "USING", such that the index and table names are left untouched
+		if def := string(idx.GetIndexDefStmt); strings.Contains(def, " USING ") {
+			usingIdx := strings.Index(def, " USING ")
+			idx.GetIndexDefStmt = schema.GetIndexDefStatement(def[:usingIdx] + renameInDef(def[usingIdx:]))
+		}
+		if idx.Constraint != nil {
+			idx.Constraint.ConstraintDef = renameInDef(idx.Constraint.ConstraintDef)
+		}
+	}
+
+	for i := range s.ForeignKeyConstraints {
+		fk := &s.ForeignKeyConstraints[i]
+		referencesIdx := strings.Index(fk.ConstraintDef, " REFERENCES ")
+		if referencesIdx == -1 {
+			continue
+		}
+		localDef, foreignDef := fk.ConstraintDef[:referencesIdx], fk.ConstraintDef[referencesIdx:]
+		if fk.OwningTable == rename.table {
+			localDef = renameInDef(localDef)
+		}
+		if fk.ForeignTable == rename.table {
+			// Only rename the columns in the referenced column list, e.g., "REFERENCES foo(id)"
+			if openIdx, closeIdx := strings.Index(foreignDef, "("), strings.Index(foreignDef, ")"); openIdx != -1 && closeIdx > openIdx {
+				foreignDef = foreignDef[:openIdx] + renameInDef(foreignDef[openIdx:closeIdx]) + foreignDef[closeIdx:]
+			}
+		}
+		fk.ConstraintDef = localDef + foreignDef
+	}
+
+	repairedViewsByName := buildSchemaObjByNameMap(repairViewsAfterColumnRename(s.Views, TableColumnChange{
+		Table:         rename.table,
+		OldColumnName: rename.old,
+		NewColumnName: rename.new,
+	}))
+	for i, view := range s.Views {
+		if repairedView, ok := repairedViewsByName[view.GetName()]; ok {
+			s.Views[i] = repairedView
+		}
+	}
+}
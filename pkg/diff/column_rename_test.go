@@ -0,0 +1,202 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestDetectColumnRenameCandidates(t *testing.T) {
+	fooName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"`}
+	buildSchema := func(columns ...schema.Column) schema.Schema {
+		return schema.Schema{Tables: []schema.Table{{
+			SchemaQualifiedName: fooName,
+			Columns:             columns,
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		}}}
+	}
+	id := schema.Column{Name: "id", Type: "integer"}
+	val := schema.Column{Name: "val", Type: "text", IsNullable: true, Collation: defaultCollation}
+	renamedVal := val
+	renamedVal.Name = "renamed_val"
+	otherVal := val
+	otherVal.Name = "other_val"
+	renamedValWithDefault := renamedVal
+	renamedValWithDefault.Default = "'some default'::text"
+	renamedValWithOtherType := renamedVal
+	renamedValWithOtherType.Type = "character varying(255)"
+
+	for _, tc := range []struct {
+		name               string
+		oldSchema          schema.Schema
+		newSchema          schema.Schema
+		expectedCandidates []ColumnRenameCandidate
+	}{
+		{
+			name:               "Column with same type",
+			oldSchema:          buildSchema(id, val),
+			newSchema:          buildSchema(id, renamedVal),
+			expectedCandidates: []ColumnRenameCandidate{{Table: fooName, Old: "val", New: "renamed_val"}},
+		},
+		{
+			name:      "Column with different default",
+			oldSchema: buildSchema(id, val),
+			newSchema: buildSchema(id, renamedValWithDefault),
+		},
+		{
+			name:      "Column with different type",
+			oldSchema: buildSchema(id, val),
+			newSchema: buildSchema(id, renamedValWithOtherType),
+		},
+		{
+			name:               "Multiple matching columns prefer the same position",
+			oldSchema:          buildSchema(id, val),
+			newSchema:          buildSchema(id, renamedVal, otherVal),
+			expectedCandidates: []ColumnRenameCandidate{{Table: fooName, Old: "val", New: "renamed_val"}},
+		},
+		{
+			name:      "Multiple matching columns at other positions",
+			oldSchema: buildSchema(val, id),
+			newSchema: buildSchema(id, renamedVal, otherVal),
+			expectedCandidates: []ColumnRenameCandidate{
+				{Table: fooName, Old: "val", New: "other_val"},
+				{Table: fooName, Old: "val", New: "renamed_val"},
+			},
+		},
+		{
+			name:      "Column still exists",
+			oldSchema: buildSchema(id, val),
+			newSchema: buildSchema(id, val, renamedVal),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedCandidates, detectColumnRenameCandidates(tc.oldSchema, tc.newSchema))
+		})
+	}
+}
+
+func TestPlan_ConfirmColumnRename(t *testing.T) {
+	fooName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"`}
+	barName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"bar"`}
+	buildSchema := func(colName string) schema.Schema {
+		return schema.Schema{
+			Tables: []schema.Table{
+				{
+					SchemaQualifiedName: fooName,
+					Columns: []schema.Column{
+						{Name: "id", Type: "integer"},
+						{Name: colName, Type: "integer", IsNullable: true},
+					},
+					CheckConstraints: []schema.CheckConstraint{{
+						Name:          "positive",
+						KeyColumns:    []string{colName},
+						Expression:    "(" + colName + " > 0)",
+						IsValid:       true,
+						IsInheritable: true,
+					}},
+					ReplicaIdentity: schema.ReplicaIdentityDefault,
+				},
+				{
+					SchemaQualifiedName: barName,
+					Columns:             []schema.Column{{Name: "foo_val", Type: "integer", IsNullable: true}},
+					ReplicaIdentity:     schema.ReplicaIdentityDefault,
+				},
+			},
+			Indexes: []schema.Index{{
+				Name:            "foo_val_idx",
+				OwningTable:     fooName,
+				Columns:         []string{colName},
+				IsUnique:        true,
+				GetIndexDefStmt: schema.GetIndexDefStatement("CREATE UNIQUE INDEX foo_val_idx ON public.foo USING btree (" + colName + ")"),
+			}},
+			ForeignKeyConstraints: []schema.ForeignKeyConstraint{{
+				EscapedName:   `"bar_foo_fk"`,
+				OwningTable:   barName,
+				ForeignTable:  fooName,
+				ConstraintDef: "FOREIGN KEY (foo_val) REFERENCES foo(" + colName + ")",
+				IsValid:       true,
+			}},
+			Views: []schema.View{
+				{
+					SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo_view"`},
+					Definition:          " SELECT " + colName + "\n   FROM foo;",
+					DependsOnTables:     []schema.SchemaQualifiedName{fooName},
+				},
+				{
+					SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo_val_view"`},
+					Definition:          " SELECT foo." + colName + " AS val\n   FROM foo;",
+					DependsOnTables:     []schema.SchemaQualifiedName{fooName},
+				},
+			},
+			Functions: []schema.Function{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"sum_foo"()`},
+				FunctionDef:         "CREATE OR REPLACE FUNCTION public.sum_foo()\n RETURNS bigint\n LANGUAGE sql\nAS $function$SELECT sum(val) FROM public.foo$function$\n",
+				Language:            "sql",
+				ReferencedColumns:   []schema.TableColumnRef{{TableName: "foo", ColumnName: "val"}},
+			}},
+		}
+	}
+	oldSchema := buildSchema("val")
+	newSchema := buildSchema("renamed_val")
+	// The function body is not updated by the rename, so it is unchanged in the new schema
+	newSchema.Functions = oldSchema.Functions
+
+	plan, err := buildPlan(oldSchema, newSchema, &planOptions{detectColumnRenames: true})
+	require.NoError(t, err)
+	assert.Empty(t, plan.RenameCandidates)
+	assert.Equal(t, []ColumnRenameCandidate{{Table: fooName, Old: "val", New: "renamed_val"}}, plan.ColumnRenameCandidates)
+	assert.Contains(t, getDDL(plan), `ALTER TABLE "public"."foo" DROP COLUMN "val"`)
+
+	_, err = plan.ConfirmColumnRename(fooName, "renamed_val", "val")
+	assert.ErrorContains(t, err, "not a column rename candidate")
+	_, err = Plan{}.ConfirmColumnRename(fooName, "val", "renamed_val")
+	assert.ErrorContains(t, err, "rename detection")
+
+	renamedPlan, err := plan.ConfirmColumnRename(fooName, "val", "renamed_val")
+	require.NoError(t, err)
+	assert.Empty(t, renamedPlan.ColumnRenameCandidates)
+	require.NotEmpty(t, renamedPlan.Statements)
+	renameStmt := renamedPlan.Statements[0]
+	assert.Equal(t, `ALTER TABLE "public"."foo" RENAME COLUMN "val" TO "renamed_val"`, renameStmt.DDL)
+	var hazardTypes []MigrationHazardType
+	for _, hazard := range renameStmt.Hazards {
+		hazardTypes = append(hazardTypes, hazard.Type)
+		if hazard.Type == MigrationHazardTypeCorrectness {
+			assert.Contains(t, hazard.Message, `"public"."sum_foo"()`)
+		}
+	}
+	assert.Equal(t, []MigrationHazardType{
+		MigrationHazardTypeAcquiresAccessExclusiveLock,
+		MigrationHazardTypeHasUntrackableDependencies,
+		MigrationHazardTypeCorrectness,
+	}, hazardTypes)
+
+	ddl := getDDL(renamedPlan)
+	for _, stmt := range ddl {
+		assert.NotContains(t, stmt, "DROP COLUMN")
+		assert.NotContains(t, stmt, "ADD COLUMN")
+		// The index and constraints reference the column by number, so they are not re-created
+		assert.NotContains(t, stmt, "foo_val_idx")
+		assert.NotContains(t, stmt, "bar_foo_fk")
+		assert.NotContains(t, stmt, `"positive"`)
+	}
+	// Postgres keeps the output column name of the view, which differs from the new view, so it is re-created
+	assert.Contains(t, ddl, `DROP VIEW "public"."foo_view"`)
+	// The view that already aliases the column to its old name matches the new view after the rename
+	for _, stmt := range ddl {
+		assert.NotContains(t, stmt, "foo_val_view")
+	}
+
+	_, err = buildPlan(oldSchema, newSchema, &planOptions{columnRenames: []columnRename{{table: fooName, old: "missing", new: "renamed_val"}}})
+	assert.ErrorContains(t, err, "does not exist")
+	_, err = buildPlan(oldSchema, newSchema, &planOptions{columnRenames: []columnRename{{table: fooName, old: "val", new: "id"}}})
+	assert.ErrorContains(t, err, "already exists")
+
+	t.Run("Already applied renames are skipped", func(t *testing.T) {
+		plan, err := buildPlan(newSchema, newSchema, &planOptions{columnRenames: []columnRename{{table: fooName, old: "val", new: "renamed_val"}}})
+		require.NoError(t, err)
+		assert.Empty(t, plan.Statements)
+	})
+}
@@ -0,0 +1,33 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// buildCommentStatements builds the statements to change the comment on an object from oldComment to newComment, e.g.,
+// `COMMENT ON TABLE "public"."foo" IS 'some comment'`. objectType is the type of the object used in the COMMENT ON
+// statement, e.g., TABLE. A removed comment is set to NULL. No statements are built if the comment is unchanged.
+//
+// Comments are metadata, so changing them never deletes data and has no hazards. The statements are meant to be run
+// after the rest of the statements for the object, i.e., once the object exists in its new form.
+func buildCommentStatements(objectType, escapedObjectName string, oldComment, newComment *string) []Statement {
+	if cmp.Equal(oldComment, newComment) {
+		return nil
+	}
+	commentLiteral := "NULL"
+	if newComment != nil {
+		commentLiteral = quoteComment(*newComment)
+	}
+	return []Statement{{
+		DDL:         fmt.Sprintf("COMMENT ON %s %s IS %s", objectType, escapedObjectName, commentLiteral),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}}
+}
+
+func quoteComment(comment string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(comment, "'", "''"))
+}
@@ -0,0 +1,174 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestBuildCommentStatements(t *testing.T) {
+	comment := "It's a\nmulti-line 'comment'"
+	otherComment := "some other comment"
+	for _, tc := range []struct {
+		name       string
+		oldComment *string
+		newComment *string
+
+		expectedDDL []string
+	}{
+		{
+			name:        "Add comment",
+			newComment:  &comment,
+			expectedDDL: []string{"COMMENT ON TABLE \"public\".\"foo\" IS 'It''s a\nmulti-line ''comment'''"},
+		},
+		{
+			name:        "Change comment",
+			oldComment:  &otherComment,
+			newComment:  &comment,
+			expectedDDL: []string{"COMMENT ON TABLE \"public\".\"foo\" IS 'It''s a\nmulti-line ''comment'''"},
+		},
+		{
+			name:        "Remove comment",
+			oldComment:  &comment,
+			expectedDDL: []string{"COMMENT ON TABLE \"public\".\"foo\" IS NULL"},
+		},
+		{
+			name:       "Unchanged comment",
+			oldComment: &comment,
+			newComment: &comment,
+		},
+		{
+			name: "No comment",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts := buildCommentStatements("TABLE", "\"public\".\"foo\"", tc.oldComment, tc.newComment)
+			var ddl []string
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				assert.Empty(t, stmt.Hazards)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_Comments(t *testing.T) {
+	fooName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"`}
+	buildSchema := func(tableComment, columnComment, indexComment *string) schema.Schema {
+		return schema.Schema{
+			Tables: []schema.Table{{
+				SchemaQualifiedName: fooName,
+				Columns:             []schema.Column{{Name: "id", Type: "integer", Comment: columnComment}},
+				ReplicaIdentity:     schema.ReplicaIdentityDefault,
+				Comment:             tableComment,
+			}},
+			Indexes: []schema.Index{{
+				OwningTable:     fooName,
+				Name:            "foo_id_idx",
+				Columns:         []string{"id"},
+				GetIndexDefStmt: "CREATE INDEX foo_id_idx ON public.foo USING btree (id)",
+				Comment:         indexComment,
+			}},
+		}
+	}
+	tableComment := "The foo's table"
+	columnComment := "The foo's\nid"
+	indexComment := "The foo's index"
+
+	t.Run("Add comments", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(buildSchema(nil, nil, nil), buildSchema(&tableComment, &columnComment, &indexComment), &planOptions{})
+		require.NoError(t, err)
+		var ddl []string
+		for _, stmt := range stmts {
+			ddl = append(ddl, stmt.DDL)
+			assert.Empty(t, stmt.Hazards)
+		}
+		// The index must not be re-created to change its comment
+		assert.ElementsMatch(t, []string{
+			"COMMENT ON TABLE \"public\".\"foo\" IS 'The foo''s table'",
+			"COMMENT ON COLUMN \"public\".\"foo\".\"id\" IS 'The foo''s\nid'",
+			"COMMENT ON INDEX \"public\".\"foo_id_idx\" IS 'The foo''s index'",
+		}, ddl)
+	})
+
+	t.Run("Remove comments", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(buildSchema(&tableComment, &columnComment, &indexComment), buildSchema(nil, nil, nil), &planOptions{})
+		require.NoError(t, err)
+		var ddl []string
+		for _, stmt := range stmts {
+			ddl = append(ddl, stmt.DDL)
+		}
+		assert.ElementsMatch(t, []string{
+			"COMMENT ON TABLE \"public\".\"foo\" IS NULL",
+			"COMMENT ON COLUMN \"public\".\"foo\".\"id\" IS NULL",
+			"COMMENT ON INDEX \"public\".\"foo_id_idx\" IS NULL",
+		}, ddl)
+	})
+
+	t.Run("Create table with comments", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(schema.Schema{}, buildSchema(&tableComment, &columnComment, &indexComment), &planOptions{})
+		require.NoError(t, err)
+		var ddl []string
+		for _, stmt := range stmts {
+			ddl = append(ddl, stmt.DDL)
+		}
+		require.Len(t, ddl, 5)
+		// The comments are set after the objects they are on are created
+		assert.Equal(t, []string{
+			"COMMENT ON TABLE \"public\".\"foo\" IS 'The foo''s table'",
+			"COMMENT ON COLUMN \"public\".\"foo\".\"id\" IS 'The foo''s\nid'",
+		}, ddl[1:3])
+		assert.Equal(t, "COMMENT ON INDEX \"public\".\"foo_id_idx\" IS 'The foo''s index'", ddl[4])
+	})
+}
+
+func TestViewAndFunctionCommentChanged(t *testing.T) {
+	comment := "It's a comment"
+
+	t.Run("View", func(t *testing.T) {
+		view := schema.View{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo_view"`},
+			Definition:          " SELECT 1;",
+		}
+		viewWithComment := view
+		viewWithComment.Comment = &comment
+
+		// Only the comment changes, so the view is not re-created
+		stmts, err := (&viewSQLVertexGenerator{}).Alter(viewDiff{oldAndNew: oldAndNew[schema.View]{old: view, new: viewWithComment}})
+		require.NoError(t, err)
+		require.Len(t, stmts, 1)
+		assert.Equal(t, "COMMENT ON VIEW \"public\".\"foo_view\" IS 'It''s a comment'", stmts[0].DDL)
+
+		stmts, err = (&viewSQLVertexGenerator{}).Add(viewWithComment)
+		require.NoError(t, err)
+		require.Len(t, stmts, 2)
+		assert.Equal(t, "COMMENT ON VIEW \"public\".\"foo_view\" IS 'It''s a comment'", stmts[1].DDL)
+	})
+
+	t.Run("Function", func(t *testing.T) {
+		function := schema.Function{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"add"(a integer, b integer)`},
+			FunctionDef:         "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT a + b $function$\n",
+			Language:            "sql",
+		}
+		functionWithComment := function
+		functionWithComment.Comment = &comment
+
+		// Only the comment changes, so the function is not replaced
+		stmts, err := (&functionSQLVertexGenerator{}).Alter(functionDiff{oldAndNew: oldAndNew[schema.Function]{old: functionWithComment, new: function}})
+		require.NoError(t, err)
+		require.Len(t, stmts, 1)
+		assert.Equal(t, "COMMENT ON FUNCTION \"public\".\"add\"(a integer, b integer) IS NULL", stmts[0].DDL)
+
+		// The comment is preserved when the function is replaced, so it is not set again
+		changedFunctionWithComment := functionWithComment
+		changedFunctionWithComment.FunctionDef = "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT b + a $function$\n"
+		stmts, err = (&functionSQLVertexGenerator{}).Alter(functionDiff{oldAndNew: oldAndNew[schema.Function]{old: functionWithComment, new: changedFunctionWithComment}})
+		require.NoError(t, err)
+		require.Len(t, stmts, 1)
+		assert.Equal(t, changedFunctionWithComment.FunctionDef, stmts[0].DDL)
+	})
+}
@@ -0,0 +1,37 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+// WithSchemaMapping compares the named schema targetSchemaName in the target schema with the named schema
+// sourceSchemaName in the source schema, e.g., to compare `staging.users` with `production.users`. The plan migrates the
+// objects in sourceSchemaName, i.e., it can be applied to the source database. The target schema must not also contain
+// a schema named sourceSchemaName.
+//
+// Schema filters, e.g., WithIncludeSchemas, are applied before the mapping, so they must allow both names.
+func WithSchemaMapping(sourceSchemaName, targetSchemaName string) PlanOpt {
+	return func(opts *planOptions) {
+		opts.schemaMappings = append(opts.schemaMappings, namedSchemaRename{oldName: targetSchemaName, newName: sourceSchemaName})
+	}
+}
+
+// CompareSchemas generates a plan to migrate the schema of the sourceDB to the schema of the targetDB, e.g., to diff a
+// staging database against production without writing either schema to files. Like Generate, the plan is validated
+// unless WithDoNotValidatePlan is passed, which requires a tempDbFactory.
+func CompareSchemas(ctx context.Context, sourceDB, targetDB *sql.DB, opts ...PlanOpt) (Plan, error) {
+	return Generate(ctx, DBSchemaSource(sourceDB), DBSchemaSource(targetDB), opts...)
+}
+
+// applySchemaMappings renames the named schemas of the target schema to the names of the schemas they are compared with
+func applySchemaMappings(targetSchema schema.Schema, mappings []namedSchemaRename) (schema.Schema, error) {
+	mapped, _, err := renameNamedSchemas(targetSchema, mappings)
+	if err != nil {
+		return schema.Schema{}, fmt.Errorf("mapping schemas: %w", err)
+	}
+	return mapped, nil
+}
@@ -0,0 +1,78 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestApplySchemaMappings(t *testing.T) {
+	targetSchema := schema.Schema{
+		NamedSchemas: []schema.NamedSchema{{Name: "public"}, {Name: "production"}},
+		Tables: []schema.Table{{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "production", EscapedName: `"myapp_users"`},
+			Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		}},
+	}
+
+	mappedSchema, err := applySchemaMappings(targetSchema, []namedSchemaRename{{oldName: "production", newName: "staging"}})
+	require.NoError(t, err)
+	assert.Equal(t, []schema.NamedSchema{{Name: "public"}, {Name: "staging"}}, mappedSchema.NamedSchemas)
+	assert.Equal(t, schema.SchemaQualifiedName{SchemaName: "staging", EscapedName: `"myapp_users"`}, mappedSchema.Tables[0].SchemaQualifiedName)
+	// The target schema is not modified
+	assert.Equal(t, "production", targetSchema.Tables[0].SchemaName)
+
+	_, err = applySchemaMappings(targetSchema, []namedSchemaRename{{oldName: "missing", newName: "staging"}})
+	assert.ErrorContains(t, err, "does not exist")
+	_, err = applySchemaMappings(targetSchema, []namedSchemaRename{{oldName: "production", newName: "public"}})
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func (suite *planGeneratorTestSuite) TestCompareSchemas() {
+	suite.mustApplyDDLToTestDb([]string{`
+		CREATE SCHEMA staging;
+		CREATE TABLE staging.myapp_users(id INT PRIMARY KEY);
+	`})
+	sourceDB := suite.mustGetTestDBPool()
+	defer sourceDB.Close()
+
+	targetTestDB, err := suite.pgEngine.CreateDatabase()
+	suite.Require().NoError(err)
+	defer targetTestDB.DropDB()
+	targetDB, err := sql.Open("pgx", targetTestDB.GetDSN())
+	suite.Require().NoError(err)
+	defer targetDB.Close()
+	_, err = targetDB.Exec(`
+		CREATE SCHEMA production;
+		CREATE TABLE production.myapp_users(id INT PRIMARY KEY, email TEXT);
+		CREATE INDEX myapp_users_email_idx ON production.myapp_users(email);
+	`)
+	suite.Require().NoError(err)
+
+	tempDbFactory := suite.mustBuildTempDbFactory(context.Background())
+	defer tempDbFactory.Close()
+
+	plan, err := CompareSchemas(context.Background(), sourceDB, targetDB,
+		WithTempDbFactory(tempDbFactory),
+		WithSchemaMapping("staging", "production"),
+	)
+	suite.Require().NoError(err)
+	suite.Equal([]string{
+		`ALTER TABLE "staging"."myapp_users" ADD COLUMN "email" text COLLATE "pg_catalog"."default"`,
+		`CREATE INDEX CONCURRENTLY myapp_users_email_idx ON staging.myapp_users USING btree (email)`,
+		`ANALYZE "staging"."myapp_users"`,
+	}, getDDL(plan))
+
+	suite.mustApplyMigrationPlan(sourceDB, plan)
+	plan, err = CompareSchemas(context.Background(), sourceDB, targetDB,
+		WithTempDbFactory(tempDbFactory),
+		WithSchemaMapping("staging", "production"),
+	)
+	suite.Require().NoError(err)
+	suite.Empty(plan.Statements)
+}
@@ -0,0 +1,167 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	migrationHazardCompositeTypeAttributeDropped = MigrationHazard{
+		Type:    MigrationHazardTypeDeletesData,
+		Message: "Deletes the attribute's data from all columns using the type",
+	}
+	migrationHazardCompositeTypeAttributeTypeChanged = MigrationHazard{
+		Type: MigrationHazardTypeImpactsDatabasePerformance,
+		Message: "Changing the type of an attribute will fail if any column uses the type. Tables created from the " +
+			"type will be re-written.",
+	}
+	migrationHazardCompositeTypeRecreated = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "Attributes were re-ordered within the composite type, so the type must be dropped and re-created. " +
+			"This will fail if any columns still use the type. Functions that reference the type are not tracked and " +
+			"might break.",
+	}
+)
+
+// compositeTypeSQLGenerator is a SQL generator for composite types. Like enums, composite types are added before and
+// dropped after all other objects that might depend on them, e.g., tables, views, and functions.
+type compositeTypeSQLGenerator struct{}
+
+func (c *compositeTypeSQLGenerator) Add(compositeType schema.CompositeType) ([]Statement, error) {
+	var attributeDefs []string
+	for _, attribute := range compositeType.Attributes {
+		attributeDefs = append(attributeDefs, buildCompositeTypeAttributeDefinition(attribute))
+	}
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("CREATE TYPE %s AS (%s)", compositeType.GetFQEscapedName(), strings.Join(attributeDefs, ", ")),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (c *compositeTypeSQLGenerator) Delete(compositeType schema.CompositeType) ([]Statement, error) {
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("DROP TYPE %s", compositeType.GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (c *compositeTypeSQLGenerator) Alter(diff compositeTypeDiff) ([]Statement, error) {
+	oldAttributesByName := make(map[string]schema.CompositeTypeAttribute)
+	for _, attribute := range diff.old.Attributes {
+		oldAttributesByName[attribute.Name] = attribute
+	}
+	newAttributesByName := make(map[string]schema.CompositeTypeAttribute)
+	for _, attribute := range diff.new.Attributes {
+		newAttributesByName[attribute.Name] = attribute
+	}
+
+	if !isCompositeTypeAttributeOrderPreserved(diff.old.Attributes, diff.new.Attributes) {
+		// Attributes can only be added to the end of a composite type, so the type must be re-created. Similar to enums,
+		// this must be done in the alter statement, since the normal delete -> add ordering would drop the type after
+		// all other objects are migrated.
+		deletes, err := c.Delete(diff.old)
+		if err != nil {
+			return nil, fmt.Errorf("generating delete statements: %w", err)
+		}
+		adds, err := c.Add(diff.new)
+		if err != nil {
+			return nil, fmt.Errorf("generating add statements: %w", err)
+		}
+		stmts := append(deletes, adds...)
+		stmts[0].Hazards = append(stmts[0].Hazards, migrationHazardCompositeTypeRecreated)
+		return stmts, nil
+	}
+
+	var stmts []Statement
+	for _, attribute := range diff.old.Attributes {
+		if _, ok := newAttributesByName[attribute.Name]; ok {
+			continue
+		}
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s DROP ATTRIBUTE %s CASCADE", alterTypePrefix(diff.old), schema.EscapeIdentifier(attribute.Name)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardCompositeTypeAttributeDropped},
+		})
+	}
+
+	for _, attribute := range diff.new.Attributes {
+		oldAttribute, ok := oldAttributesByName[attribute.Name]
+		if !ok || cmp.Equal(oldAttribute, attribute) {
+			continue
+		}
+		sb := strings.Builder{}
+		sb.WriteString(fmt.Sprintf("%s ALTER ATTRIBUTE %s TYPE %s", alterTypePrefix(diff.new), schema.EscapeIdentifier(attribute.Name), attribute.Type))
+		if attribute.IsCollated() {
+			sb.WriteString(fmt.Sprintf(" COLLATE %s", attribute.Collation.GetFQEscapedName()))
+		}
+		sb.WriteString(" CASCADE")
+		stmts = append(stmts, Statement{
+			DDL:         sb.String(),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardCompositeTypeAttributeTypeChanged},
+		})
+	}
+
+	for _, attribute := range diff.new.Attributes {
+		if _, ok := oldAttributesByName[attribute.Name]; ok {
+			continue
+		}
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s ADD ATTRIBUTE %s CASCADE", alterTypePrefix(diff.new), buildCompositeTypeAttributeDefinition(attribute)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	}
+
+	return stmts, nil
+}
+
+// isCompositeTypeAttributeOrderPreserved returns true if the new attributes can be reached from the old attributes
+// without re-ordering any attributes, i.e., the attributes in both lists are in the same relative order and all added
+// attributes are at the end of the new attributes.
+func isCompositeTypeAttributeOrderPreserved(oldAttributes, newAttributes []schema.CompositeTypeAttribute) bool {
+	newAttributeNames := make(map[string]bool)
+	for _, attribute := range newAttributes {
+		newAttributeNames[attribute.Name] = true
+	}
+	var retainedAttributeNames []string
+	for _, attribute := range oldAttributes {
+		if newAttributeNames[attribute.Name] {
+			retainedAttributeNames = append(retainedAttributeNames, attribute.Name)
+		}
+	}
+
+	if len(retainedAttributeNames) > len(newAttributes) {
+		return false
+	}
+	for i, name := range retainedAttributeNames {
+		if newAttributes[i].Name != name {
+			return false
+		}
+	}
+	return true
+}
+
+func buildCompositeTypeAttributeDefinition(attribute schema.CompositeTypeAttribute) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%s %s", schema.EscapeIdentifier(attribute.Name), attribute.Type))
+	if attribute.IsCollated() {
+		sb.WriteString(fmt.Sprintf(" COLLATE %s", attribute.Collation.GetFQEscapedName()))
+	}
+	return sb.String()
+}
+
+func alterTypePrefix(compositeType schema.CompositeType) string {
+	return fmt.Sprintf("ALTER TYPE %s", compositeType.GetFQEscapedName())
+}
@@ -0,0 +1,80 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestCompositeTypeSQLGenerator_Alter(t *testing.T) {
+	typeName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_type\""}
+	cCollation := schema.SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"C\""}
+	for _, tc := range []struct {
+		name            string
+		oldAttributes   []schema.CompositeTypeAttribute
+		newAttributes   []schema.CompositeTypeAttribute
+		expectedDDL     []string
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name:          "add attribute",
+			oldAttributes: []schema.CompositeTypeAttribute{{Name: "a", Type: "integer"}},
+			newAttributes: []schema.CompositeTypeAttribute{{Name: "a", Type: "integer"}, {Name: "b", Type: "text", Collation: cCollation}},
+			expectedDDL: []string{
+				"ALTER TYPE \"public\".\"some_type\" ADD ATTRIBUTE \"b\" text COLLATE \"pg_catalog\".\"C\" CASCADE",
+			},
+		},
+		{
+			name:          "drop attribute and alter attribute type",
+			oldAttributes: []schema.CompositeTypeAttribute{{Name: "a", Type: "integer"}, {Name: "b", Type: "integer"}},
+			newAttributes: []schema.CompositeTypeAttribute{{Name: "b", Type: "bigint"}},
+			expectedDDL: []string{
+				"ALTER TYPE \"public\".\"some_type\" DROP ATTRIBUTE \"a\" CASCADE",
+				"ALTER TYPE \"public\".\"some_type\" ALTER ATTRIBUTE \"b\" TYPE bigint CASCADE",
+			},
+			expectedHazards: []MigrationHazard{
+				migrationHazardCompositeTypeAttributeDropped,
+				migrationHazardCompositeTypeAttributeTypeChanged,
+			},
+		},
+		{
+			name:          "reorder attributes",
+			oldAttributes: []schema.CompositeTypeAttribute{{Name: "a", Type: "integer"}, {Name: "b", Type: "integer"}},
+			newAttributes: []schema.CompositeTypeAttribute{{Name: "b", Type: "integer"}, {Name: "a", Type: "integer"}},
+			expectedDDL: []string{
+				"DROP TYPE \"public\".\"some_type\"",
+				"CREATE TYPE \"public\".\"some_type\" AS (\"b\" integer, \"a\" integer)",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardCompositeTypeRecreated},
+		},
+		{
+			name:          "add attribute before existing attribute",
+			oldAttributes: []schema.CompositeTypeAttribute{{Name: "b", Type: "integer"}},
+			newAttributes: []schema.CompositeTypeAttribute{{Name: "a", Type: "integer"}, {Name: "b", Type: "integer"}},
+			expectedDDL: []string{
+				"DROP TYPE \"public\".\"some_type\"",
+				"CREATE TYPE \"public\".\"some_type\" AS (\"a\" integer, \"b\" integer)",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardCompositeTypeRecreated},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := (&compositeTypeSQLGenerator{}).Alter(compositeTypeDiff{oldAndNew: oldAndNew[schema.CompositeType]{
+				old: schema.CompositeType{SchemaQualifiedName: typeName, Attributes: tc.oldAttributes},
+				new: schema.CompositeType{SchemaQualifiedName: typeName, Attributes: tc.newAttributes},
+			}})
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+}
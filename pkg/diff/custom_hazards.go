@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	builtInHazardTypes = map[MigrationHazardType]bool{
+		MigrationHazardTypeAcquiresAccessExclusiveLock:   true,
+		MigrationHazardTypeAcquiresShareLock:             true,
+		MigrationHazardTypeAcquiresShareRowExclusiveLock: true,
+		MigrationHazardTypeCorrectness:                   true,
+		MigrationHazardTypeDeletesData:                   true,
+		MigrationHazardTypeHasUntrackableDependencies:    true,
+		MigrationHazardTypeIndexBuild:                    true,
+		MigrationHazardTypeIndexDropped:                  true,
+		MigrationHazardTypeImpactsDatabasePerformance:    true,
+		MigrationHazardTypeIsUserGenerated:               true,
+		MigrationHazardTypeExtensionVersionUpgrade:       true,
+		MigrationHazardTypeAuthzUpdate:                   true,
+		MigrationHazardTypeColumnOrderChange:             true,
+		MigrationHazardTypeImpossibleToRollback:          true,
+		MigrationHazardTypeLongRunning:                   true,
+		MigrationHazardTypeImpossibleWithoutDowntime:     true,
+		MigrationHazardTypePgBouncerIncompatible:         true,
+		MigrationHazardTypeRequiresSuperuser:             true,
+		MigrationHazardTypeValidateConstraint:            true,
+	}
+
+	customHazardTypesMu sync.RWMutex
+	// customHazardTypes maps the keys of the hazard types registered via RegisterHazardType to their descriptions
+	customHazardTypes = make(map[MigrationHazardType]string)
+)
+
+// RegisterHazardType registers a custom hazard type, e.g., for a domain-specific rule like "this table is read by the
+// billing service", and returns it. The key is the hazard type's serialized form, e.g., in the JSON of a plan, so
+// downstream tooling can match on it without importing this package. Custom hazards can be added to plans via
+// WithHazardGenerators.
+//
+// Registering the same key with the same description more than once returns the same type. RegisterHazardType panics if
+// the key is empty, is a built-in hazard type, or was already registered with a different description.
+func RegisterHazardType(key string, description string) MigrationHazardType {
+	if len(key) == 0 {
+		panic("diff: hazard type key must not be empty")
+	}
+	hazardType := MigrationHazardType(key)
+	if builtInHazardTypes[hazardType] {
+		panic(fmt.Sprintf("diff: hazard type %s is a built-in hazard type", key))
+	}
+
+	customHazardTypesMu.Lock()
+	defer customHazardTypesMu.Unlock()
+	if existingDescription, ok := customHazardTypes[hazardType]; ok && existingDescription != description {
+		panic(fmt.Sprintf("diff: hazard type %s is already registered with a different description", key))
+	}
+	customHazardTypes[hazardType] = description
+	return hazardType
+}
+
+// GetHazardTypeDescription returns the description of a hazard type registered via RegisterHazardType. It returns false
+// if the hazard type is not a registered custom hazard type.
+func GetHazardTypeDescription(hazardType MigrationHazardType) (string, bool) {
+	customHazardTypesMu.RLock()
+	defer customHazardTypesMu.RUnlock()
+	description, ok := customHazardTypes[hazardType]
+	return description, ok
+}
+
+func isKnownHazardType(hazardType MigrationHazardType) bool {
+	if builtInHazardTypes[hazardType] {
+		return true
+	}
+	_, ok := GetHazardTypeDescription(hazardType)
+	return ok
+}
+
+// HazardGenerator generates additional hazards for the statements of a plan, e.g., to flag statements that touch tables
+// owned by another team.
+type HazardGenerator interface {
+	// GenerateHazards returns the hazards to add to the statement. The hazards must be of a built-in hazard type or of
+	// a type registered via RegisterHazardType.
+	GenerateHazards(stmt Statement) ([]MigrationHazard, error)
+}
+
+// WithHazardGenerators adds the hazards generated by the generators to each statement of the plan. The generators run
+// in the order they are passed.
+func WithHazardGenerators(generators ...HazardGenerator) PlanOpt {
+	return func(opts *planOptions) {
+		opts.hazardGenerators = append(opts.hazardGenerators, generators...)
+	}
+}
+
+// addGeneratedHazards adds the hazards generated by the generators to the statements
+func addGeneratedHazards(stmts []Statement, generators []HazardGenerator) ([]Statement, error) {
+	if len(generators) == 0 {
+		return stmts, nil
+	}
+	var newStmts []Statement
+	for i, stmt := range stmts {
+		for _, generator := range generators {
+			hazards, err := generator.GenerateHazards(stmt)
+			if err != nil {
+				return nil, fmt.Errorf("generating hazards for statement %d: %w", i, err)
+			}
+			for _, hazard := range hazards {
+				if !isKnownHazardType(hazard.Type) {
+					return nil, fmt.Errorf("generating hazards for statement %d: hazard type %s is not registered. Register it via RegisterHazardType", i, hazard.Type)
+				}
+			}
+			if len(hazards) > 0 {
+				stmt.Hazards = append(append([]MigrationHazard(nil), stmt.Hazards...), hazards...)
+			}
+		}
+		newStmts = append(newStmts, stmt)
+	}
+	return newStmts, nil
+}
@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+type hazardGeneratorFunc func(stmt Statement) ([]MigrationHazard, error)
+
+func (f hazardGeneratorFunc) GenerateHazards(stmt Statement) ([]MigrationHazard, error) {
+	return f(stmt)
+}
+
+func TestRegisterHazardType(t *testing.T) {
+	hazardType := RegisterHazardType("TEST_REGISTERED_HAZARD", "Some description")
+	assert.Equal(t, MigrationHazardType("TEST_REGISTERED_HAZARD"), hazardType)
+	description, ok := GetHazardTypeDescription(hazardType)
+	assert.True(t, ok)
+	assert.Equal(t, "Some description", description)
+
+	// Registering the same type again is a no-op
+	assert.Equal(t, hazardType, RegisterHazardType("TEST_REGISTERED_HAZARD", "Some description"))
+
+	_, ok = GetHazardTypeDescription(MigrationHazardTypeDeletesData)
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { RegisterHazardType("", "Some description") })
+	assert.Panics(t, func() { RegisterHazardType(MigrationHazardTypeDeletesData, "Some description") })
+	assert.Panics(t, func() { RegisterHazardType("TEST_REGISTERED_HAZARD", "Some other description") })
+}
+
+func TestWithHazardGenerators(t *testing.T) {
+	billingHazardType := RegisterHazardType("TEST_BILLING_TABLE_CHANGED", "The table is read by the billing service")
+	billingHazardGenerator := hazardGeneratorFunc(func(stmt Statement) ([]MigrationHazard, error) {
+		if !strings.Contains(stmt.DDL, `"invoices"`) {
+			return nil, nil
+		}
+		return []MigrationHazard{{
+			Type:    billingHazardType,
+			Message: "Coordinate this change with the billing team",
+		}}, nil
+	})
+
+	invoices := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"invoices"`},
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	foobar := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`},
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	newSchema := schema.Schema{Tables: []schema.Table{foobar, invoices}}
+
+	t.Run("Hazards are added to the matching statements", func(t *testing.T) {
+		plan, err := buildPlan(schema.Schema{}, newSchema, &planOptions{hazardGenerators: []HazardGenerator{billingHazardGenerator}})
+		require.NoError(t, err)
+
+		var billingStmts []Statement
+		for _, stmt := range plan.Statements {
+			if strings.Contains(stmt.DDL, `"invoices"`) {
+				billingStmts = append(billingStmts, stmt)
+				assert.Contains(t, stmt.Hazards, MigrationHazard{Type: billingHazardType, Message: "Coordinate this change with the billing team"})
+			} else {
+				for _, hazard := range stmt.Hazards {
+					assert.NotEqual(t, billingHazardType, hazard.Type)
+				}
+			}
+		}
+		require.NotEmpty(t, billingStmts)
+
+		data, err := json.Marshal(plan)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"type":"TEST_BILLING_TABLE_CHANGED"`)
+	})
+
+	t.Run("Unregistered hazard types are rejected", func(t *testing.T) {
+		unregisteredHazardGenerator := hazardGeneratorFunc(func(stmt Statement) ([]MigrationHazard, error) {
+			return []MigrationHazard{{Type: "TEST_UNREGISTERED_HAZARD", Message: "Some message"}}, nil
+		})
+		_, err := buildPlan(schema.Schema{}, newSchema, &planOptions{hazardGenerators: []HazardGenerator{unregisteredHazardGenerator}})
+		assert.ErrorContains(t, err, "hazard type TEST_UNREGISTERED_HAZARD is not registered")
+	})
+
+	t.Run("Generator errors are returned", func(t *testing.T) {
+		generatorErr := errors.New("some error")
+		failingHazardGenerator := hazardGeneratorFunc(func(stmt Statement) ([]MigrationHazard, error) {
+			return nil, generatorErr
+		})
+		_, err := buildPlan(schema.Schema{}, newSchema, &planOptions{hazardGenerators: []HazardGenerator{failingHazardGenerator}})
+		assert.ErrorIs(t, err, generatorErr)
+	})
+}
@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"fmt"
+
+	externalschema "github.com/stripe/pg-schema-diff/pkg/schema"
+)
+
+type (
+	// VertexId identifies a vertex in the graph of statements that is topologically sorted to order the plan's
+	// statements. Vertices with the same id are merged. Build ids via BuildVertexId or BuildTableVertexId.
+	VertexId = sqlVertexId
+
+	// DiffType is the kind of change a vertex resolves for a schema object. Most schema objects have a DiffTypeDelete
+	// vertex and a DiffTypeAddAlter vertex.
+	DiffType = diffType
+
+	// VertexPriority determines whether a vertex's statements run sooner or later in the topological sort, among the
+	// orderings that satisfy the dependencies.
+	VertexPriority = sqlPriority
+
+	// Vertex is a set of statements in the graph of statements
+	Vertex struct {
+		Id         VertexId
+		Priority   VertexPriority
+		Statements []Statement
+	}
+
+	// Dependency indicates the statements of the Source vertex must run before the statements of the Target vertex.
+	// If either vertex does not exist, an empty vertex is added for it.
+	Dependency struct {
+		Source VertexId
+		Target VertexId
+	}
+
+	// VertexGenerator generates the vertices for custom schema objects, i.e., objects the diff engine does not manage,
+	// given the old and new schemas. The vertices are sorted along with the built-in vertices, so the dependencies
+	// can reference the built-in vertices, e.g., via BuildTableVertexId.
+	//
+	// The generated statements must not change the schema objects that the diff engine manages, otherwise the
+	// plan's validation fails.
+	VertexGenerator func(oldSchema, newSchema externalschema.Schema) ([]Vertex, []Dependency, error)
+)
+
+const (
+	DiffTypeDelete   = diffTypeDelete
+	DiffTypeAddAlter = diffTypeAddAlter
+
+	VertexPrioritySooner = sqlPrioritySooner
+	VertexPriorityUnset  = sqlPriorityUnset
+	VertexPriorityLater  = sqlPriorityLater
+)
+
+// WithAdditionalVertexGenerators adds the statements generated by the generators to the plan. The statements are ordered
+// along with the built-in statements according to the generators' dependencies.
+func WithAdditionalVertexGenerators(generators ...VertexGenerator) PlanOpt {
+	return func(opts *planOptions) {
+		opts.additionalVertexGenerators = append(opts.additionalVertexGenerators, generators...)
+	}
+}
+
+// BuildVertexId builds the id of the vertex for a custom schema object. The objType should not be the type of a built-in
+// schema object, e.g., "table", otherwise the vertex is merged with the built-in vertex.
+func BuildVertexId(objType string, id string, diffType DiffType) VertexId {
+	return buildSchemaObjVertexId(objType, id, diffType)
+}
+
+// BuildTableVertexId builds the id of the vertex that deletes or adds/alters the table, e.g., for a custom schema object
+// to depend on.
+func BuildTableVertexId(name externalschema.SchemaQualifiedName, diffType DiffType) VertexId {
+	return buildTableVertexId(name, diffType)
+}
+
+// generateAdditionalPartialGraph generates the partial graph of the additional vertex generators
+func generateAdditionalPartialGraph(generators []VertexGenerator, oldSchema, newSchema externalschema.Schema) (partialSQLGraph, error) {
+	var partialGraph partialSQLGraph
+	for i, generator := range generators {
+		vertices, deps, err := generator(oldSchema, newSchema)
+		if err != nil {
+			return partialSQLGraph{}, fmt.Errorf("running additional vertex generator %d: %w", i, err)
+		}
+		for _, vertex := range vertices {
+			if vertex.Id == nil {
+				return partialSQLGraph{}, fmt.Errorf("additional vertex generator %d: vertex has no id", i)
+			}
+			partialGraph.vertices = append(partialGraph.vertices, sqlVertex{
+				id:         vertex.Id,
+				priority:   vertex.Priority,
+				statements: vertex.Statements,
+			})
+		}
+		for _, dep := range deps {
+			if dep.Source == nil || dep.Target == nil {
+				return partialSQLGraph{}, fmt.Errorf("additional vertex generator %d: dependency is missing its source or target", i)
+			}
+			partialGraph.dependencies = append(partialGraph.dependencies, mustRun(dep.Source).before(dep.Target))
+		}
+	}
+	return partialGraph, nil
+}
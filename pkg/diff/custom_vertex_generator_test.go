@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+	externalschema "github.com/stripe/pg-schema-diff/pkg/schema"
+)
+
+// cronJobVertexGenerator is a toy generator for cron jobs that vacuum each table. A table's cron job must be scheduled
+// after the table is created and unscheduled before the table is dropped.
+func cronJobVertexGenerator(oldSchema, newSchema externalschema.Schema) ([]Vertex, []Dependency, error) {
+	oldTablesByName := buildSchemaObjByNameMap(oldSchema.Tables)
+	newTablesByName := buildSchemaObjByNameMap(newSchema.Tables)
+
+	var vertices []Vertex
+	var deps []Dependency
+	for _, table := range newSchema.Tables {
+		if _, ok := oldTablesByName[table.GetName()]; ok {
+			continue
+		}
+		id := BuildVertexId("cron_job", table.GetName(), DiffTypeAddAlter)
+		vertices = append(vertices, Vertex{
+			Id:       id,
+			Priority: VertexPrioritySooner,
+			Statements: []Statement{{
+				DDL: fmt.Sprintf("SELECT cron.schedule('vacuum %s', '@daily', 'VACUUM %s')", table.EscapedName, table.GetFQEscapedName()),
+			}},
+		})
+		deps = append(deps, Dependency{Source: BuildTableVertexId(table.SchemaQualifiedName, DiffTypeAddAlter), Target: id})
+	}
+	for _, table := range oldSchema.Tables {
+		if _, ok := newTablesByName[table.GetName()]; ok {
+			continue
+		}
+		id := BuildVertexId("cron_job", table.GetName(), DiffTypeDelete)
+		vertices = append(vertices, Vertex{
+			Id:       id,
+			Priority: VertexPriorityLater,
+			Statements: []Statement{{
+				DDL: fmt.Sprintf("SELECT cron.unschedule('vacuum %s')", table.EscapedName),
+			}},
+		})
+		deps = append(deps, Dependency{Source: id, Target: BuildTableVertexId(table.SchemaQualifiedName, DiffTypeDelete)})
+	}
+	return vertices, deps, nil
+}
+
+func TestWithAdditionalVertexGenerators(t *testing.T) {
+	oldTable := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"old_table"`},
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	newTable := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"new_table"`},
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	oldSchema := schema.Schema{Tables: []schema.Table{oldTable}}
+	newSchema := schema.Schema{Tables: []schema.Table{newTable}}
+
+	t.Run("Custom statements are ordered with the built-in statements", func(t *testing.T) {
+		plan, err := buildPlan(oldSchema, newSchema, &planOptions{
+			additionalVertexGenerators: []VertexGenerator{cronJobVertexGenerator},
+		})
+		require.NoError(t, err)
+		ddl := getDDL(plan)
+		require.Len(t, ddl, 4)
+
+		indexOf := func(stmt string) int {
+			for i, d := range ddl {
+				if d == stmt {
+					return i
+				}
+			}
+			require.Failf(t, "statement not found", "%s not in %v", stmt, ddl)
+			return -1
+		}
+		createIdx := indexOf("CREATE TABLE \"public\".\"new_table\" (\n\t\"id\" integer NOT NULL\n)")
+		scheduleIdx := indexOf(`SELECT cron.schedule('vacuum "new_table"', '@daily', 'VACUUM "public"."new_table"')`)
+		unscheduleIdx := indexOf(`SELECT cron.unschedule('vacuum "old_table"')`)
+		dropIdx := indexOf(`DROP TABLE "public"."old_table"`)
+		assert.Less(t, createIdx, scheduleIdx)
+		assert.Less(t, unscheduleIdx, dropIdx)
+		assert.Contains(t, plan.Dependencies, StatementDependency{Statement: scheduleIdx, DependsOn: createIdx})
+		assert.Contains(t, plan.Dependencies, StatementDependency{Statement: dropIdx, DependsOn: unscheduleIdx})
+	})
+
+	t.Run("Cyclic dependencies are rejected", func(t *testing.T) {
+		cyclicVertexGenerator := func(_, _ externalschema.Schema) ([]Vertex, []Dependency, error) {
+			id := BuildVertexId("cron_job", newTable.GetName(), DiffTypeAddAlter)
+			tableId := BuildTableVertexId(newTable.SchemaQualifiedName, DiffTypeAddAlter)
+			return []Vertex{{Id: id, Statements: []Statement{{DDL: "SELECT 1"}}}},
+				[]Dependency{{Source: id, Target: tableId}, {Source: tableId, Target: id}},
+				nil
+		}
+		_, err := buildPlan(oldSchema, newSchema, &planOptions{
+			additionalVertexGenerators: []VertexGenerator{cyclicVertexGenerator},
+		})
+		var cyclicErr *CyclicDependencyError
+		assert.ErrorAs(t, err, &cyclicErr)
+	})
+
+	t.Run("Generator errors are returned", func(t *testing.T) {
+		generatorErr := errors.New("some error")
+		_, err := buildPlan(oldSchema, newSchema, &planOptions{
+			additionalVertexGenerators: []VertexGenerator{func(_, _ externalschema.Schema) ([]Vertex, []Dependency, error) {
+				return nil, nil, generatorErr
+			}},
+		})
+		assert.ErrorIs(t, err, generatorErr)
+	})
+}
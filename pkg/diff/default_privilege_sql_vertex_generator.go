@@ -0,0 +1,103 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var migrationHazardDefaultPrivilegeRolesUntracked = MigrationHazard{
+	Type: MigrationHazardTypeHasUntrackableDependencies,
+	Message: "Roles are not tracked. Altering the default privileges will fail if the grantor or grantee role does not " +
+		"exist in the target database.",
+}
+
+// defaultPrivilegeSQLVertexGenerator is a SQL vertex generator for default privileges. Schemas are created before the
+// SQL graph is executed and dropped after, so default privileges scoped to a schema need no explicit dependencies on
+// it.
+type defaultPrivilegeSQLVertexGenerator struct{}
+
+func newDefaultPrivilegeSqlVertexGenerator() sqlVertexGenerator[schema.DefaultPrivilege, defaultPrivilegeDiff] {
+	return legacyToNewSqlVertexGenerator[schema.DefaultPrivilege, defaultPrivilegeDiff](&defaultPrivilegeSQLVertexGenerator{})
+}
+
+func (d *defaultPrivilegeSQLVertexGenerator) Add(defaultPrivilege schema.DefaultPrivilege) ([]Statement, error) {
+	ddl := fmt.Sprintf("%s GRANT %s ON %s TO %s",
+		buildAlterDefaultPrivilegesPrefix(defaultPrivilege),
+		defaultPrivilege.Type,
+		defaultPrivilege.ObjectType,
+		buildDefaultPrivilegeGrantee(defaultPrivilege),
+	)
+	if defaultPrivilege.IsGrantable {
+		ddl += " WITH GRANT OPTION"
+	}
+	return []Statement{{
+		DDL:         ddl,
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardPrivilegeGranted, migrationHazardDefaultPrivilegeRolesUntracked},
+	}}, nil
+}
+
+func (d *defaultPrivilegeSQLVertexGenerator) Delete(defaultPrivilege schema.DefaultPrivilege) ([]Statement, error) {
+	return []Statement{{
+		DDL: fmt.Sprintf("%s REVOKE %s ON %s FROM %s",
+			buildAlterDefaultPrivilegesPrefix(defaultPrivilege),
+			defaultPrivilege.Type,
+			defaultPrivilege.ObjectType,
+			buildDefaultPrivilegeGrantee(defaultPrivilege),
+		),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardPrivilegeRevoked},
+	}}, nil
+}
+
+func (d *defaultPrivilegeSQLVertexGenerator) Alter(diff defaultPrivilegeDiff) ([]Statement, error) {
+	if diff.old.IsGrantable == diff.new.IsGrantable {
+		return nil, nil
+	}
+	if diff.new.IsGrantable {
+		return d.Add(diff.new)
+	}
+	return []Statement{{
+		DDL: fmt.Sprintf("%s REVOKE GRANT OPTION FOR %s ON %s FROM %s",
+			buildAlterDefaultPrivilegesPrefix(diff.new),
+			diff.new.Type,
+			diff.new.ObjectType,
+			buildDefaultPrivilegeGrantee(diff.new),
+		),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardPrivilegeRevoked},
+	}}, nil
+}
+
+func buildAlterDefaultPrivilegesPrefix(defaultPrivilege schema.DefaultPrivilege) string {
+	prefix := fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s", schema.EscapeIdentifier(defaultPrivilege.Grantor))
+	if len(defaultPrivilege.SchemaName) > 0 {
+		prefix += fmt.Sprintf(" IN SCHEMA %s", schema.EscapeIdentifier(defaultPrivilege.SchemaName))
+	}
+	return prefix
+}
+
+func buildDefaultPrivilegeGrantee(defaultPrivilege schema.DefaultPrivilege) string {
+	if defaultPrivilege.Grantee == schema.PrivilegeGranteePublic {
+		return defaultPrivilege.Grantee
+	}
+	return schema.EscapeIdentifier(defaultPrivilege.Grantee)
+}
+
+func (d *defaultPrivilegeSQLVertexGenerator) GetSQLVertexId(defaultPrivilege schema.DefaultPrivilege, diffType diffType) sqlVertexId {
+	return buildSchemaObjVertexId("default_privilege", defaultPrivilege.GetName(), diffType)
+}
+
+func (d *defaultPrivilegeSQLVertexGenerator) GetAddAlterDependencies(newDefaultPrivilege, _ schema.DefaultPrivilege) ([]dependency, error) {
+	return []dependency{
+		mustRun(d.GetSQLVertexId(newDefaultPrivilege, diffTypeAddAlter)).after(d.GetSQLVertexId(newDefaultPrivilege, diffTypeDelete)),
+	}, nil
+}
+
+func (d *defaultPrivilegeSQLVertexGenerator) GetDeleteDependencies(_ schema.DefaultPrivilege) ([]dependency, error) {
+	return nil, nil
+}
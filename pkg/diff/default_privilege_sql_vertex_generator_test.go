@@ -0,0 +1,107 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestDefaultPrivilegeSQLVertexGenerator(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		generate        func(g *defaultPrivilegeSQLVertexGenerator) ([]Statement, error)
+		expectedDDL     []string
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name: "grant on tables in schema",
+			generate: func(g *defaultPrivilegeSQLVertexGenerator) ([]Statement, error) {
+				return g.Add(schema.DefaultPrivilege{Grantor: "owner", SchemaName: "schema_1", ObjectType: "TABLES", Grantee: "reader", Type: "SELECT"})
+			},
+			expectedDDL:     []string{"ALTER DEFAULT PRIVILEGES FOR ROLE \"owner\" IN SCHEMA \"schema_1\" GRANT SELECT ON TABLES TO \"reader\""},
+			expectedHazards: []MigrationHazard{migrationHazardPrivilegeGranted, migrationHazardDefaultPrivilegeRolesUntracked},
+		},
+		{
+			name: "grant on sequences in all schemas",
+			generate: func(g *defaultPrivilegeSQLVertexGenerator) ([]Statement, error) {
+				return g.Add(schema.DefaultPrivilege{Grantor: "owner", ObjectType: "SEQUENCES", Grantee: "writer", Type: "USAGE", IsGrantable: true})
+			},
+			expectedDDL:     []string{"ALTER DEFAULT PRIVILEGES FOR ROLE \"owner\" GRANT USAGE ON SEQUENCES TO \"writer\" WITH GRANT OPTION"},
+			expectedHazards: []MigrationHazard{migrationHazardPrivilegeGranted, migrationHazardDefaultPrivilegeRolesUntracked},
+		},
+		{
+			name: "revoke on functions",
+			generate: func(g *defaultPrivilegeSQLVertexGenerator) ([]Statement, error) {
+				return g.Delete(schema.DefaultPrivilege{Grantor: "owner", SchemaName: "schema_1", ObjectType: "FUNCTIONS", Grantee: schema.PrivilegeGranteePublic, Type: "EXECUTE"})
+			},
+			expectedDDL:     []string{"ALTER DEFAULT PRIVILEGES FOR ROLE \"owner\" IN SCHEMA \"schema_1\" REVOKE EXECUTE ON FUNCTIONS FROM PUBLIC"},
+			expectedHazards: []MigrationHazard{migrationHazardPrivilegeRevoked},
+		},
+		{
+			name: "revoke grant option",
+			generate: func(g *defaultPrivilegeSQLVertexGenerator) ([]Statement, error) {
+				return g.Alter(defaultPrivilegeDiff{oldAndNew: oldAndNew[schema.DefaultPrivilege]{
+					old: schema.DefaultPrivilege{Grantor: "owner", ObjectType: "TABLES", Grantee: "reader", Type: "SELECT", IsGrantable: true},
+					new: schema.DefaultPrivilege{Grantor: "owner", ObjectType: "TABLES", Grantee: "reader", Type: "SELECT"},
+				}})
+			},
+			expectedDDL:     []string{"ALTER DEFAULT PRIVILEGES FOR ROLE \"owner\" REVOKE GRANT OPTION FOR SELECT ON TABLES FROM \"reader\""},
+			expectedHazards: []MigrationHazard{migrationHazardPrivilegeRevoked},
+		},
+		{
+			name: "no change",
+			generate: func(g *defaultPrivilegeSQLVertexGenerator) ([]Statement, error) {
+				return g.Alter(defaultPrivilegeDiff{oldAndNew: oldAndNew[schema.DefaultPrivilege]{
+					old: schema.DefaultPrivilege{Grantor: "owner", ObjectType: "TABLES", Grantee: "reader", Type: "SELECT"},
+					new: schema.DefaultPrivilege{Grantor: "owner", ObjectType: "TABLES", Grantee: "reader", Type: "SELECT"},
+				}})
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := tc.generate(&defaultPrivilegeSQLVertexGenerator{})
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_DefaultPrivileges(t *testing.T) {
+	oldSchema := schema.Schema{
+		DefaultPrivileges: []schema.DefaultPrivilege{
+			{Grantor: "owner", SchemaName: "schema_1", ObjectType: "TABLES", Grantee: "reader", Type: "SELECT"},
+			{Grantor: "owner", SchemaName: "schema_1", ObjectType: "TABLES", Grantee: "reader", Type: "INSERT"},
+		},
+	}
+	newSchema := schema.Schema{
+		NamedSchemas: []schema.NamedSchema{{Name: "schema_2"}},
+		DefaultPrivileges: []schema.DefaultPrivilege{
+			{Grantor: "owner", SchemaName: "schema_1", ObjectType: "TABLES", Grantee: "reader", Type: "SELECT"},
+			{Grantor: "owner", SchemaName: "schema_2", ObjectType: "SEQUENCES", Grantee: "reader", Type: "USAGE"},
+		},
+	}
+
+	stmts, err := generateMigrationStatements(oldSchema, newSchema, &planOptions{})
+	require.NoError(t, err)
+	var ddl []string
+	for _, stmt := range stmts {
+		ddl = append(ddl, stmt.DDL)
+	}
+	// Only the changed privileges are granted or revoked, and the schema is created first
+	require.NotEmpty(t, ddl)
+	assert.Equal(t, "CREATE SCHEMA \"schema_2\"", ddl[0])
+	assert.ElementsMatch(t, []string{
+		"ALTER DEFAULT PRIVILEGES FOR ROLE \"owner\" IN SCHEMA \"schema_1\" REVOKE INSERT ON TABLES FROM \"reader\"",
+		"ALTER DEFAULT PRIVILEGES FOR ROLE \"owner\" IN SCHEMA \"schema_2\" GRANT USAGE ON SEQUENCES TO \"reader\"",
+	}, ddl[1:])
+}
@@ -0,0 +1,135 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	migrationHazardDomainRecreated = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "The base type or collation of the domain changed, so the domain must be dropped and re-created. " +
+			"This will fail if any columns still use the domain. Functions that reference the domain are not tracked and " +
+			"might break.",
+	}
+	migrationHazardDomainConstraintValidated = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresShareLock,
+		Message: "Every column that uses the domain is scanned to validate the constraint. This locks out writes to " +
+			"the tables while they are being scanned.",
+	}
+)
+
+// domainSQLGenerator is a SQL generator for domains. Like enums, domains are added before and dropped after all other
+// objects that might depend on them, e.g., tables, views, and functions.
+type domainSQLGenerator struct{}
+
+func (d *domainSQLGenerator) Add(domain schema.Domain) ([]Statement, error) {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("CREATE DOMAIN %s AS %s", domain.GetFQEscapedName(), domain.BaseType))
+	if domain.IsCollated() {
+		sb.WriteString(fmt.Sprintf(" COLLATE %s", domain.Collation.GetFQEscapedName()))
+	}
+	if len(domain.Default) > 0 {
+		sb.WriteString(fmt.Sprintf(" DEFAULT %s", domain.Default))
+	}
+	if domain.IsNotNull {
+		sb.WriteString(" NOT NULL")
+	}
+	for _, constraint := range domain.Constraints {
+		sb.WriteString(fmt.Sprintf(" CONSTRAINT %s %s", schema.EscapeIdentifier(constraint.Name), constraint.Check))
+	}
+	return []Statement{
+		{
+			DDL:         sb.String(),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (d *domainSQLGenerator) Delete(domain schema.Domain) ([]Statement, error) {
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("DROP DOMAIN %s", domain.GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (d *domainSQLGenerator) Alter(diff domainDiff) ([]Statement, error) {
+	if diff.old.BaseType != diff.new.BaseType || diff.old.Collation != diff.new.Collation {
+		// The base type of a domain cannot be altered. Similar to enums, the domain must be re-created in the alter
+		// statement, since the normal delete -> add ordering would drop the domain after all other objects are migrated.
+		deletes, err := d.Delete(diff.old)
+		if err != nil {
+			return nil, fmt.Errorf("generating delete statements: %w", err)
+		}
+		adds, err := d.Add(diff.new)
+		if err != nil {
+			return nil, fmt.Errorf("generating add statements: %w", err)
+		}
+		stmts := append(deletes, adds...)
+		stmts[0].Hazards = append(stmts[0].Hazards, migrationHazardDomainRecreated)
+		return stmts, nil
+	}
+
+	alterPrefix := fmt.Sprintf("ALTER DOMAIN %s", diff.new.GetFQEscapedName())
+	oldConstraintsByName := buildSchemaObjByNameMap(diff.old.Constraints)
+	newConstraintsByName := buildSchemaObjByNameMap(diff.new.Constraints)
+
+	var stmts []Statement
+	for _, constraint := range diff.old.Constraints {
+		if newConstraint, ok := newConstraintsByName[constraint.Name]; ok && newConstraint == constraint {
+			continue
+		}
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s DROP CONSTRAINT %s", alterPrefix, schema.EscapeIdentifier(constraint.Name)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	}
+
+	if diff.old.Default != diff.new.Default {
+		ddl := fmt.Sprintf("%s DROP DEFAULT", alterPrefix)
+		if len(diff.new.Default) > 0 {
+			ddl = fmt.Sprintf("%s SET DEFAULT %s", alterPrefix, diff.new.Default)
+		}
+		stmts = append(stmts, Statement{
+			DDL:         ddl,
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	}
+
+	if diff.old.IsNotNull && !diff.new.IsNotNull {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s DROP NOT NULL", alterPrefix),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	} else if !diff.old.IsNotNull && diff.new.IsNotNull {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s SET NOT NULL", alterPrefix),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardDomainConstraintValidated},
+		})
+	}
+
+	for _, constraint := range diff.new.Constraints {
+		if oldConstraint, ok := oldConstraintsByName[constraint.Name]; ok && oldConstraint == constraint {
+			continue
+		}
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s ADD CONSTRAINT %s %s", alterPrefix, schema.EscapeIdentifier(constraint.Name), constraint.Check),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardDomainConstraintValidated},
+		})
+	}
+
+	return stmts, nil
+}
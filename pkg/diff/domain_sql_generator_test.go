@@ -0,0 +1,115 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestDomainSQLGenerator_Add(t *testing.T) {
+	stmts, err := (&domainSQLGenerator{}).Add(schema.Domain{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_domain\""},
+		BaseType:            "text",
+		Collation:           schema.SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"C\""},
+		Default:             "'foo'::text",
+		IsNotNull:           true,
+		Constraints: []schema.DomainConstraint{
+			{Name: "not_empty", Check: "CHECK ((VALUE <> ''::text))"},
+			{Name: "short", Check: "CHECK ((length(VALUE) < 10))"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "CREATE DOMAIN \"public\".\"some_domain\" AS text COLLATE \"pg_catalog\".\"C\" DEFAULT 'foo'::text NOT NULL "+
+		"CONSTRAINT \"not_empty\" CHECK ((VALUE <> ''::text)) CONSTRAINT \"short\" CHECK ((length(VALUE) < 10))", stmts[0].DDL)
+}
+
+func TestDomainSQLGenerator_Alter(t *testing.T) {
+	domainName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_domain\""}
+	positive := schema.DomainConstraint{Name: "positive", Check: "CHECK ((VALUE > 0))"}
+	for _, tc := range []struct {
+		name            string
+		old             schema.Domain
+		new             schema.Domain
+		expectedDDL     []string
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name: "set default, set not null, and add constraint",
+			old:  schema.Domain{SchemaQualifiedName: domainName, BaseType: "integer"},
+			new: schema.Domain{
+				SchemaQualifiedName: domainName,
+				BaseType:            "integer",
+				Default:             "1",
+				IsNotNull:           true,
+				Constraints:         []schema.DomainConstraint{positive},
+			},
+			expectedDDL: []string{
+				"ALTER DOMAIN \"public\".\"some_domain\" SET DEFAULT 1",
+				"ALTER DOMAIN \"public\".\"some_domain\" SET NOT NULL",
+				"ALTER DOMAIN \"public\".\"some_domain\" ADD CONSTRAINT \"positive\" CHECK ((VALUE > 0))",
+			},
+			expectedHazards: []MigrationHazard{
+				migrationHazardDomainConstraintValidated,
+				migrationHazardDomainConstraintValidated,
+			},
+		},
+		{
+			name: "drop default, drop not null, and drop constraint",
+			old: schema.Domain{
+				SchemaQualifiedName: domainName,
+				BaseType:            "integer",
+				Default:             "1",
+				IsNotNull:           true,
+				Constraints:         []schema.DomainConstraint{positive},
+			},
+			new: schema.Domain{SchemaQualifiedName: domainName, BaseType: "integer"},
+			expectedDDL: []string{
+				"ALTER DOMAIN \"public\".\"some_domain\" DROP CONSTRAINT \"positive\"",
+				"ALTER DOMAIN \"public\".\"some_domain\" DROP DEFAULT",
+				"ALTER DOMAIN \"public\".\"some_domain\" DROP NOT NULL",
+			},
+		},
+		{
+			name: "change constraint",
+			old:  schema.Domain{SchemaQualifiedName: domainName, BaseType: "integer", Constraints: []schema.DomainConstraint{positive}},
+			new: schema.Domain{SchemaQualifiedName: domainName, BaseType: "integer", Constraints: []schema.DomainConstraint{
+				{Name: "positive", Check: "CHECK ((VALUE >= 1))"},
+			}},
+			expectedDDL: []string{
+				"ALTER DOMAIN \"public\".\"some_domain\" DROP CONSTRAINT \"positive\"",
+				"ALTER DOMAIN \"public\".\"some_domain\" ADD CONSTRAINT \"positive\" CHECK ((VALUE >= 1))",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardDomainConstraintValidated},
+		},
+		{
+			name: "change base type",
+			old:  schema.Domain{SchemaQualifiedName: domainName, BaseType: "integer", Constraints: []schema.DomainConstraint{positive}},
+			new:  schema.Domain{SchemaQualifiedName: domainName, BaseType: "bigint", Constraints: []schema.DomainConstraint{positive}},
+			expectedDDL: []string{
+				"DROP DOMAIN \"public\".\"some_domain\"",
+				"CREATE DOMAIN \"public\".\"some_domain\" AS bigint CONSTRAINT \"positive\" CHECK ((VALUE > 0))",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardDomainRecreated},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := (&domainSQLGenerator{}).Alter(domainDiff{oldAndNew: oldAndNew[schema.Domain]{
+				old: tc.old,
+				new: tc.new,
+			}})
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+}
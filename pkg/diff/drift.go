@@ -0,0 +1,188 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+// DriftKind is how an object in the live database differs from the expected schema
+type DriftKind string
+
+const (
+	// DriftKindAdded is an object that is in the live database but not in the expected schema
+	DriftKindAdded DriftKind = "ADDED"
+	// DriftKindRemoved is an object that is in the expected schema but not in the live database
+	DriftKindRemoved DriftKind = "REMOVED"
+	// DriftKindModified is an object that is in both the live database and the expected schema but differs between them
+	DriftKindModified DriftKind = "MODIFIED"
+)
+
+type (
+	// DriftItem is an object that differs between the live database and the expected schema
+	DriftItem struct {
+		// ObjectType is the type of the object, e.g., "table" or "column"
+		ObjectType string
+		// Name is the escaped name of the object. Columns are qualified by their table, e.g., "public"."foo"."bar"
+		Name string
+		Kind DriftKind
+	}
+
+	// DriftReport is the result of comparing the live database against the expected schema
+	DriftReport struct {
+		HasDrift bool
+		// Plan is the plan to migrate the live database to the expected schema
+		Plan Plan
+		// Items are the objects that differ between the live database and the expected schema, grouped by object type
+		// and then by kind
+		Items []DriftItem
+	}
+)
+
+// DetectDrift compares the schema of the live database against the expected schema, e.g., the application's DDL via
+// DDLSchemaSource. It returns the objects that have drifted and the plan to migrate the database back to the expected
+// schema. The plan is not applied: the database is only read from.
+//
+// The options are the same as for Generate, e.g., the plan is validated unless WithDoNotValidatePlan is provided, so a
+// tempDbFactory is required.
+func DetectDrift(ctx context.Context, db *sql.DB, expectedSchema SchemaSource, opts ...PlanOpt) (DriftReport, error) {
+	planOptions := buildPlanOptions(opts)
+
+	liveSchema, targetSchema, err := getCurrentAndNewSchemas(ctx, DBSchemaSource(db), expectedSchema, planOptions)
+	if err != nil {
+		return DriftReport{}, err
+	}
+
+	plan, err := generateForSchemas(ctx, liveSchema, targetSchema, planOptions)
+	if err != nil {
+		return DriftReport{}, err
+	}
+
+	if !planOptions.doNotNormalizeFunctionBodies {
+		liveSchema = normalizeFunctionBodies(liveSchema, targetSchema)
+	}
+	if planOptions.ignoreFormattingDiffs {
+		liveSchema = ignoreFormattingDifferences(liveSchema, targetSchema)
+	}
+	items := buildDriftItems(liveSchema, targetSchema)
+
+	return DriftReport{
+		HasDrift: len(items) > 0 || len(plan.Statements) > 0,
+		Plan:     plan,
+		Items:    items,
+	}, nil
+}
+
+// buildDriftItems builds the objects that differ between the live and expected schemas. Columns are compared
+// individually, so a table is only reported as modified if something other than its columns differs. Column order is
+// not compared.
+func buildDriftItems(live, expected schema.Schema) []DriftItem {
+	live = live.Normalize()
+	expected = expected.Normalize()
+
+	var items []DriftItem
+	items = append(items, buildDriftItemsForObjects("schema", live.NamedSchemas, expected.NamedSchemas)...)
+	items = append(items, buildDriftItemsForObjects("extension", live.Extensions, expected.Extensions)...)
+	items = append(items, buildDriftItemsForObjects("collation", live.Collations, expected.Collations)...)
+	items = append(items, buildDriftItemsForObjects("foreign data wrapper", live.ForeignDataWrappers, expected.ForeignDataWrappers)...)
+	items = append(items, buildDriftItemsForObjects("foreign server", live.ForeignServers, expected.ForeignServers)...)
+	items = append(items, buildDriftItemsForObjects("enum", live.Enums, expected.Enums)...)
+	items = append(items, buildDriftItemsForObjects("domain", live.Domains, expected.Domains)...)
+	items = append(items, buildDriftItemsForObjects("composite type", live.CompositeTypes, expected.CompositeTypes)...)
+	items = append(items, buildDriftItemsForObjects("text search dictionary", live.TextSearchDictionaries, expected.TextSearchDictionaries)...)
+	items = append(items, buildDriftItemsForObjects("text search configuration", live.TextSearchConfigs, expected.TextSearchConfigs)...)
+	items = append(items, buildTableDriftItems(live.Tables, expected.Tables)...)
+	items = append(items, buildDriftItemsForObjects("foreign table", live.ForeignTables, expected.ForeignTables)...)
+	items = append(items, buildDriftItemsForObjects("view", live.Views, expected.Views)...)
+	items = append(items, buildDriftItemsForObjects("materialized view", live.MaterializedViews, expected.MaterializedViews)...)
+	items = append(items, buildDriftItemsForObjects("index", live.Indexes, expected.Indexes)...)
+	items = append(items, buildDriftItemsForObjects("statistics object", live.StatisticsObjects, expected.StatisticsObjects)...)
+	items = append(items, buildDriftItemsForObjects("foreign key constraint", live.ForeignKeyConstraints, expected.ForeignKeyConstraints)...)
+	items = append(items, buildDriftItemsForObjects("sequence", live.Sequences, expected.Sequences)...)
+	items = append(items, buildDriftItemsForObjects("function", live.Functions, expected.Functions)...)
+	items = append(items, buildDriftItemsForObjects("procedure", live.Procedures, expected.Procedures)...)
+	items = append(items, buildDriftItemsForObjects("aggregate", live.Aggregates, expected.Aggregates)...)
+	items = append(items, buildDriftItemsForObjects("trigger", live.Triggers, expected.Triggers)...)
+	items = append(items, buildDriftItemsForObjects("event trigger", live.EventTriggers, expected.EventTriggers)...)
+	items = append(items, buildDriftItemsForObjects("operator", live.Operators, expected.Operators)...)
+	items = append(items, buildDriftItemsForObjects("operator class", live.OperatorClasses, expected.OperatorClasses)...)
+	items = append(items, buildDriftItemsForObjects("publication", live.Publications, expected.Publications)...)
+	items = append(items, buildDriftItemsForObjects("privilege", live.Privileges, expected.Privileges)...)
+	items = append(items, buildDriftItemsForObjects("default privilege", live.DefaultPrivileges, expected.DefaultPrivileges)...)
+	return items
+}
+
+// buildTableDriftItems builds the drift items for tables and their columns
+func buildTableDriftItems(live, expected []schema.Table) []DriftItem {
+	items := buildDriftItemsForObjects("table", withoutColumns(live), withoutColumns(expected))
+
+	expectedTablesByName := make(map[string]schema.Table)
+	for _, table := range expected {
+		expectedTablesByName[table.GetName()] = table
+	}
+	for _, liveTable := range live {
+		expectedTable, ok := expectedTablesByName[liveTable.GetName()]
+		if !ok {
+			continue
+		}
+		for _, item := range buildDriftItemsForObjects("column", liveTable.Columns, expectedTable.Columns) {
+			item.Name = fmt.Sprintf("%s.%s", liveTable.GetName(), schema.EscapeIdentifier(item.Name))
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func withoutColumns(tables []schema.Table) []schema.Table {
+	var tablesWithoutColumns []schema.Table
+	for _, table := range tables {
+		table.Columns = nil
+		tablesWithoutColumns = append(tablesWithoutColumns, table)
+	}
+	return tablesWithoutColumns
+}
+
+// buildDriftItemsForObjects builds the drift items for a list of objects of the same type. The items are sorted by
+// kind and then by name.
+func buildDriftItemsForObjects[S schema.Object](objectType string, live, expected []S) []DriftItem {
+	expectedByName := make(map[string]S)
+	for _, obj := range expected {
+		expectedByName[obj.GetName()] = obj
+	}
+
+	var added, removed, modified []DriftItem
+	for _, liveObj := range sortedByName(live) {
+		expectedObj, ok := expectedByName[liveObj.GetName()]
+		if !ok {
+			added = append(added, DriftItem{ObjectType: objectType, Name: liveObj.GetName(), Kind: DriftKindAdded})
+			continue
+		}
+		delete(expectedByName, liveObj.GetName())
+		if !cmp.Equal(liveObj, expectedObj) {
+			modified = append(modified, DriftItem{ObjectType: objectType, Name: liveObj.GetName(), Kind: DriftKindModified})
+		}
+	}
+	for _, expectedObj := range sortedByName(expected) {
+		if _, ok := expectedByName[expectedObj.GetName()]; ok {
+			removed = append(removed, DriftItem{ObjectType: objectType, Name: expectedObj.GetName(), Kind: DriftKindRemoved})
+		}
+	}
+
+	var items []DriftItem
+	items = append(items, added...)
+	items = append(items, removed...)
+	items = append(items, modified...)
+	return items
+}
+
+func sortedByName[S schema.Object](objs []S) []S {
+	sorted := append([]S(nil), objs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetName() < sorted[j].GetName()
+	})
+	return sorted
+}
@@ -0,0 +1,94 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestBuildDriftItems(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+	table := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}, {Name: "foo", Type: "text"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	index := schema.Index{
+		OwningTable:     foobar,
+		Name:            "foo_idx",
+		Columns:         []string{"foo"},
+		Method:          "btree",
+		GetIndexDefStmt: "CREATE INDEX foo_idx ON public.foobar USING btree (foo)",
+	}
+	function := schema.Function{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"add"(a integer, b integer)`},
+		FunctionDef:         "CREATE OR REPLACE FUNCTION public.add(a integer, b integer) RETURNS integer LANGUAGE sql AS $$ SELECT a + b $$",
+		Language:            "sql",
+	}
+	expected := schema.Schema{
+		Tables:    []schema.Table{table},
+		Indexes:   []schema.Index{index},
+		Functions: []schema.Function{function},
+	}
+
+	t.Run("No drift", func(t *testing.T) {
+		assert.Empty(t, buildDriftItems(expected, expected))
+	})
+
+	t.Run("Added column", func(t *testing.T) {
+		liveTable := table
+		liveTable.Columns = append([]schema.Column{{Name: "bar", Type: "text"}}, table.Columns...)
+		live := expected
+		live.Tables = []schema.Table{liveTable}
+
+		assert.Equal(t, []DriftItem{
+			{ObjectType: "column", Name: `"public"."foobar"."bar"`, Kind: DriftKindAdded},
+		}, buildDriftItems(live, expected))
+	})
+
+	t.Run("Column order is not compared", func(t *testing.T) {
+		liveTable := table
+		liveTable.Columns = []schema.Column{table.Columns[1], table.Columns[0]}
+		live := expected
+		live.Tables = []schema.Table{liveTable}
+
+		assert.Empty(t, buildDriftItems(live, expected))
+	})
+
+	t.Run("Dropped index", func(t *testing.T) {
+		live := expected
+		live.Indexes = nil
+
+		assert.Equal(t, []DriftItem{
+			{ObjectType: "index", Name: `"public"."foo_idx"`, Kind: DriftKindRemoved},
+		}, buildDriftItems(live, expected))
+	})
+
+	t.Run("Modified function body", func(t *testing.T) {
+		liveFunction := function
+		liveFunction.FunctionDef = "CREATE OR REPLACE FUNCTION public.add(a integer, b integer) RETURNS integer LANGUAGE sql AS $$ SELECT a - b $$"
+		live := expected
+		live.Functions = []schema.Function{liveFunction}
+
+		assert.Equal(t, []DriftItem{
+			{ObjectType: "function", Name: `"public"."add"(a integer, b integer)`, Kind: DriftKindModified},
+		}, buildDriftItems(live, expected))
+	})
+
+	t.Run("Added and removed tables", func(t *testing.T) {
+		other := schema.Table{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"other"`},
+			Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		}
+		live := schema.Schema{Tables: []schema.Table{other}}
+
+		assert.Equal(t, []DriftItem{
+			{ObjectType: "table", Name: `"public"."other"`, Kind: DriftKindAdded},
+			{ObjectType: "table", Name: `"public"."foobar"`, Kind: DriftKindRemoved},
+			{ObjectType: "index", Name: `"public"."foo_idx"`, Kind: DriftKindRemoved},
+			{ObjectType: "function", Name: `"public"."add"(a integer, b integer)`, Kind: DriftKindRemoved},
+		}, buildDriftItems(live, expected))
+	})
+}
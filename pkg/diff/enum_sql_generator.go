@@ -38,12 +38,18 @@ func (e *enumSQLGenerator) Delete(enum schema.Enum) ([]Statement, error) {
 	}, nil
 }
 
+var migrationHazardEnumRecreated = MigrationHazard{
+	Type: MigrationHazardTypeHasUntrackableDependencies,
+	Message: "Values were removed from or re-ordered within the enum, so the enum must be dropped and re-created. " +
+		"This will fail if any columns still use the enum. Functions that reference the enum are not tracked and might " +
+		"break.",
+}
+
 func (e *enumSQLGenerator) Alter(diff enumDiff) ([]Statement, error) {
 	oldCopy := diff.old
 	oldVals := set.NewSet(diff.old.Labels...)
-	newVals := set.NewSet(diff.new.Labels...)
-	if len(set.Difference(oldVals, newVals)) > 0 {
-		// Old values cannot be deleted, so we will try to re-create the enum. Normally, we wouldn't try this
+	if !isOnlyAddingEnumValues(diff.old.Labels, diff.new.Labels) {
+		// Values cannot be deleted or re-ordered, so we will try to re-create the enum. Normally, we wouldn't try this
 		// in sqlGenerator.Alter, and we would rely on the forceRecreate functionality of diff. However, if we tried the
 		// normal delete -> add -> alter -> {all other generated SQL}, migrations involving deleting an enum would
 		// fail because tables would still be using the enum. As a result, we must push re-creating the enum into the alter statement.
@@ -63,7 +69,9 @@ func (e *enumSQLGenerator) Alter(diff enumDiff) ([]Statement, error) {
 		if err != nil {
 			return nil, fmt.Errorf("generating add statements: %w", err)
 		}
-		return append(deletes, adds...), nil
+		stmts := append(deletes, adds...)
+		stmts[0].Hazards = append(stmts[0].Hazards, migrationHazardEnumRecreated)
+		return stmts, nil
 	}
 
 	var stmts []Statement
@@ -94,3 +102,24 @@ func (e *enumSQLGenerator) Alter(diff enumDiff) ([]Statement, error) {
 
 	return stmts, nil
 }
+
+// isOnlyAddingEnumValues returns true if the new labels can be reached from the old labels by only adding values, i.e.,
+// the old labels appear in the new labels in the same order.
+func isOnlyAddingEnumValues(oldLabels, newLabels []string) bool {
+	oldVals := set.NewSet(oldLabels...)
+	var retainedLabels []string
+	for _, label := range newLabels {
+		if oldVals.Has(label) {
+			retainedLabels = append(retainedLabels, label)
+		}
+	}
+	if len(oldLabels) != len(retainedLabels) {
+		return false
+	}
+	for i := range oldLabels {
+		if oldLabels[i] != retainedLabels[i] {
+			return false
+		}
+	}
+	return true
+}
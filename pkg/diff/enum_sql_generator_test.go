@@ -0,0 +1,72 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestEnumSQLGenerator_Alter(t *testing.T) {
+	enumName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"some_enum\""}
+	for _, tc := range []struct {
+		name           string
+		oldLabels      []string
+		newLabels      []string
+		expectedDDL    []string
+		expectRecreate bool
+	}{
+		{
+			name:      "add values",
+			oldLabels: []string{"b", "d"},
+			newLabels: []string{"a", "b", "c", "d", "e"},
+			expectedDDL: []string{
+				"ALTER TYPE \"public\".\"some_enum\" ADD VALUE 'e'",
+				"ALTER TYPE \"public\".\"some_enum\" ADD VALUE 'c' BEFORE 'd'",
+				"ALTER TYPE \"public\".\"some_enum\" ADD VALUE 'a' BEFORE 'b'",
+			},
+		},
+		{
+			name:      "remove value",
+			oldLabels: []string{"a", "b", "c"},
+			newLabels: []string{"a", "c"},
+			expectedDDL: []string{
+				"DROP TYPE \"public\".\"some_enum\"",
+				"CREATE TYPE \"public\".\"some_enum\" AS ENUM ('a', 'c')",
+			},
+			expectRecreate: true,
+		},
+		{
+			name:      "reorder values and add value",
+			oldLabels: []string{"a", "b"},
+			newLabels: []string{"b", "c", "a"},
+			expectedDDL: []string{
+				"DROP TYPE \"public\".\"some_enum\"",
+				"CREATE TYPE \"public\".\"some_enum\" AS ENUM ('b', 'c', 'a')",
+			},
+			expectRecreate: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := (&enumSQLGenerator{}).Alter(enumDiff{oldAndNew: oldAndNew[schema.Enum]{
+				old: schema.Enum{SchemaQualifiedName: enumName, Labels: tc.oldLabels},
+				new: schema.Enum{SchemaQualifiedName: enumName, Labels: tc.newLabels},
+			}})
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			if tc.expectRecreate {
+				assert.Equal(t, []MigrationHazard{migrationHazardEnumRecreated}, hazards)
+			} else {
+				assert.Empty(t, hazards)
+			}
+		})
+	}
+}
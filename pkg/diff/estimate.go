@@ -0,0 +1,184 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+const (
+	// EstimateConfidenceHigh is the confidence of estimates based on up-to-date statistics
+	EstimateConfidenceHigh = "high"
+	// EstimateConfidenceLow is the confidence of estimates based on missing or stale statistics
+	EstimateConfidenceLow = "low"
+
+	// The following rates are rough estimates of how fast Postgres processes a table on typical hardware. They are only
+	// used to estimate how long statements will take.
+
+	// estimateIndexBuildPagesPerSecond is how many pages of a table Postgres can scan and sort per second to build an index
+	estimateIndexBuildPagesPerSecond = 10_000
+	// estimateScanPagesPerSecond is how many pages of a table Postgres can scan per second, e.g., to validate a
+	// constraint
+	estimateScanPagesPerSecond = 25_000
+	// estimateRewriteBytesPerSecond is how many bytes of a table Postgres can rewrite per second
+	estimateRewriteBytesPerSecond = 50 << 20
+	// estimateRowOverheadBytes is the size of a row's header and line pointer, which pg_stats' widths exclude
+	estimateRowOverheadBytes = 28
+	// estimatePageSizeBytes is the default size of a Postgres page
+	estimatePageSizeBytes = 8192
+)
+
+var (
+	estimateCreateIndexRegex = regexp.MustCompile(`^CREATE (?:UNIQUE )?INDEX (CONCURRENTLY )?(?:IF NOT EXISTS )?` + identifierPattern + ` ON (?:ONLY )?(` + qualifiedIdentifierPattern + `) `)
+	estimateAlterTableRegex  = regexp.MustCompile(`(?s)^ALTER TABLE (?:ONLY )?(` + qualifiedIdentifierPattern + `) (.*)$`)
+
+	estimateAddIndexConstraintRegex = regexp.MustCompile(`(?s)^ADD CONSTRAINT ` + identifierPattern + ` (?:PRIMARY KEY|UNIQUE) \(`)
+	estimateAddScanConstraintRegex  = regexp.MustCompile(`(?s)^ADD CONSTRAINT ` + identifierPattern + ` (?:CHECK ?\(|FOREIGN KEY )`)
+)
+
+// StatementEstimate is the estimated duration of a statement of a plan
+type StatementEstimate struct {
+	Statement         Statement
+	EstimatedDuration time.Duration
+	// Confidence is EstimateConfidenceLow if the estimate is based on missing or stale statistics
+	Confidence string
+	// Recommendation describes how to improve the estimate, e.g., by analyzing the table. It is empty if the estimate
+	// is confident.
+	Recommendation string
+}
+
+// estimateWork is the work a statement does proportional to the size of a table
+type estimateWork int
+
+const (
+	estimateWorkNone estimateWork = iota
+	estimateWorkIndexBuild
+	estimateWorkConcurrentIndexBuild
+	estimateWorkScan
+	estimateWorkRewrite
+)
+
+// EstimateDuration estimates how long each statement of the plan will take against the database, based on the
+// statistics Postgres keeps about the size of each table, e.g., pg_class.relpages and pg_class.reltuples:
+//   - Index builds are proportional to the number of pages of the table
+//   - Table rewrites, e.g., changing a column's type, are proportional to the number of rows times their width
+//   - Validating a constraint is proportional to the number of pages of the table
+//
+// Statements that only change the catalog are estimated to take no time. Estimates do not include the time spent
+// waiting for locks. If a table's statistics are missing or stale, the estimate has EstimateConfidenceLow and
+// recommends analyzing the table first.
+func (p Plan) EstimateDuration(ctx context.Context, db *sql.DB) ([]StatementEstimate, error) {
+	statsByTable := make(map[string]*schema.TableStatistics)
+	var estimates []StatementEstimate
+	for _, stmt := range p.Statements {
+		table, work := getEstimateWork(stmt)
+		if work == estimateWorkNone {
+			estimates = append(estimates, StatementEstimate{Statement: stmt, Confidence: EstimateConfidenceHigh})
+			continue
+		}
+		stats, ok := statsByTable[table]
+		if !ok {
+			tableStats, exists, err := schema.GetTableStatistics(ctx, db, table)
+			if err != nil {
+				return nil, fmt.Errorf("getting statistics of table %s: %w", table, err)
+			}
+			if exists {
+				stats = &tableStats
+			}
+			statsByTable[table] = stats
+		}
+		estimates = append(estimates, estimateStatement(stmt, table, work, stats))
+	}
+	return estimates, nil
+}
+
+// getEstimateWork returns the table the statement processes and how. It returns estimateWorkNone if the statement's
+// duration does not depend on the size of a table.
+func getEstimateWork(stmt Statement) (string, estimateWork) {
+	if stmt.IsAdvisory {
+		return "", estimateWorkNone
+	}
+	if match := estimateCreateIndexRegex.FindStringSubmatch(stmt.DDL); match != nil {
+		if match[1] != "" {
+			return match[2], estimateWorkConcurrentIndexBuild
+		}
+		return match[2], estimateWorkIndexBuild
+	}
+	match := estimateAlterTableRegex.FindStringSubmatch(stmt.DDL)
+	if match == nil {
+		return "", estimateWorkNone
+	}
+	table, action := match[1], match[2]
+	switch {
+	case strings.Contains(action, " SET DATA TYPE "), hasTableRewriteHazard(stmt):
+		return table, estimateWorkRewrite
+	case strings.HasSuffix(action, " NOT VALID"):
+		return "", estimateWorkNone
+	case estimateAddIndexConstraintRegex.MatchString(action):
+		return table, estimateWorkIndexBuild
+	case strings.HasPrefix(action, "VALIDATE CONSTRAINT "),
+		estimateAddScanConstraintRegex.MatchString(action),
+		strings.HasSuffix(action, " SET NOT NULL"):
+		return table, estimateWorkScan
+	}
+	return "", estimateWorkNone
+}
+
+// hasTableRewriteHazard returns true if the statement has a hazard indicating it rewrites the table
+func hasTableRewriteHazard(stmt Statement) bool {
+	for _, hazard := range stmt.Hazards {
+		for _, rewriteHazard := range []MigrationHazard{migrationHazardGeneratedColumnAdded, migrationHazardColumnAddedWithVolatileDefault} {
+			if hazard.Type == rewriteHazard.Type && hazard.Message == rewriteHazard.Message {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// estimateStatement estimates the duration of the statement from the statistics of the table it processes. The stats
+// are nil if the table does not exist yet, i.e., it is created by the plan, in which case it is empty.
+func estimateStatement(stmt Statement, table string, work estimateWork, stats *schema.TableStatistics) StatementEstimate {
+	estimate := StatementEstimate{Statement: stmt, Confidence: EstimateConfidenceHigh}
+	if stats == nil {
+		return estimate
+	}
+
+	switch work {
+	case estimateWorkIndexBuild:
+		estimate.EstimatedDuration = durationForRate(stats.Pages, estimateIndexBuildPagesPerSecond)
+	case estimateWorkConcurrentIndexBuild:
+		// Concurrent index builds scan the table twice
+		estimate.EstimatedDuration = durationForRate(stats.Pages, estimateIndexBuildPagesPerSecond) +
+			durationForRate(stats.Pages, estimateScanPagesPerSecond)
+	case estimateWorkScan:
+		estimate.EstimatedDuration = durationForRate(stats.Pages, estimateScanPagesPerSecond)
+	case estimateWorkRewrite:
+		rewriteBytes := stats.Pages * estimatePageSizeBytes
+		if stats.EstimatedRows >= 0 && stats.RowWidth > 0 {
+			rewriteBytes = stats.EstimatedRows * (stats.RowWidth + estimateRowOverheadBytes)
+		}
+		estimate.EstimatedDuration = durationForRate(rewriteBytes, estimateRewriteBytesPerSecond)
+	}
+
+	if !stats.HasBeenAnalyzed || stats.EstimatedRows < 0 {
+		estimate.Confidence = EstimateConfidenceLow
+		estimate.Recommendation = fmt.Sprintf("Table %s has never been analyzed. Run ANALYZE %s for a more accurate estimate", table, table)
+	} else if stats.RowsModifiedSinceAnalyze*10 > stats.EstimatedRows {
+		// Mirror autovacuum's default analyze threshold of 10% of the table's rows
+		estimate.Confidence = EstimateConfidenceLow
+		estimate.Recommendation = fmt.Sprintf("The statistics of table %s are stale: ~%d rows were modified since it was last analyzed. "+
+			"Run ANALYZE %s for a more accurate estimate", table, stats.RowsModifiedSinceAnalyze, table)
+	}
+	return estimate
+}
+
+// durationForRate returns how long processing the units at the rate per second takes
+func durationForRate(units, unitsPerSecond int64) time.Duration {
+	return time.Duration(float64(units) / float64(unitsPerSecond) * float64(time.Second))
+}
@@ -0,0 +1,159 @@
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestGetEstimateWork(t *testing.T) {
+	for _, tc := range []struct {
+		stmt          Statement
+		expectedTable string
+		expectedWork  estimateWork
+	}{
+		{
+			stmt:          Statement{DDL: "CREATE INDEX CONCURRENTLY foobar_val_idx ON public.foobar USING btree (val)"},
+			expectedTable: "public.foobar",
+			expectedWork:  estimateWorkConcurrentIndexBuild,
+		},
+		{
+			stmt:          Statement{DDL: `CREATE UNIQUE INDEX foobar_val_idx ON ONLY "public"."foobar" USING btree (val)`},
+			expectedTable: `"public"."foobar"`,
+			expectedWork:  estimateWorkIndexBuild,
+		},
+		{
+			stmt:          Statement{DDL: `ALTER TABLE "public"."foobar" ALTER COLUMN "val" SET DATA TYPE bigint using "val"::bigint`},
+			expectedTable: `"public"."foobar"`,
+			expectedWork:  estimateWorkRewrite,
+		},
+		{
+			stmt: Statement{
+				DDL:     `ALTER TABLE "public"."foobar" ADD COLUMN "val" double precision DEFAULT random()`,
+				Hazards: []MigrationHazard{migrationHazardColumnAddedWithVolatileDefault},
+			},
+			expectedTable: `"public"."foobar"`,
+			expectedWork:  estimateWorkRewrite,
+		},
+		{
+			stmt:          Statement{DDL: `ALTER TABLE "public"."foobar" VALIDATE CONSTRAINT "foobar_val_check"`},
+			expectedTable: `"public"."foobar"`,
+			expectedWork:  estimateWorkScan,
+		},
+		{
+			stmt:          Statement{DDL: `ALTER TABLE "public"."foobar" ADD CONSTRAINT "foobar_val_check" CHECK((val > 0))`},
+			expectedTable: `"public"."foobar"`,
+			expectedWork:  estimateWorkScan,
+		},
+		{
+			stmt:         Statement{DDL: `ALTER TABLE "public"."foobar" ADD CONSTRAINT "foobar_val_check" CHECK((val > 0)) NOT VALID`},
+			expectedWork: estimateWorkNone,
+		},
+		{
+			stmt:          Statement{DDL: `ALTER TABLE "public"."foobar" ADD CONSTRAINT "foobar_pkey" PRIMARY KEY (id)`},
+			expectedTable: `"public"."foobar"`,
+			expectedWork:  estimateWorkIndexBuild,
+		},
+		{
+			stmt:         Statement{DDL: `ALTER TABLE "public"."foobar" ADD COLUMN "val" text`},
+			expectedWork: estimateWorkNone,
+		},
+		{
+			stmt:         Statement{DDL: `ANALYZE "public"."foobar"`, IsAdvisory: true},
+			expectedWork: estimateWorkNone,
+		},
+	} {
+		t.Run(tc.stmt.DDL, func(t *testing.T) {
+			table, work := getEstimateWork(tc.stmt)
+			assert.Equal(t, tc.expectedTable, table)
+			assert.Equal(t, tc.expectedWork, work)
+		})
+	}
+}
+
+func TestEstimateStatement(t *testing.T) {
+	analyzedStats := &schema.TableStatistics{
+		Pages:           100_000,
+		EstimatedRows:   10_000_000,
+		RowWidth:        52,
+		HasBeenAnalyzed: true,
+	}
+
+	estimate := estimateStatement(Statement{}, "foobar", estimateWorkIndexBuild, analyzedStats)
+	assert.Equal(t, 10*time.Second, estimate.EstimatedDuration)
+	assert.Equal(t, EstimateConfidenceHigh, estimate.Confidence)
+	assert.Empty(t, estimate.Recommendation)
+
+	estimate = estimateStatement(Statement{}, "foobar", estimateWorkConcurrentIndexBuild, analyzedStats)
+	assert.Equal(t, 14*time.Second, estimate.EstimatedDuration)
+
+	estimate = estimateStatement(Statement{}, "foobar", estimateWorkScan, analyzedStats)
+	assert.Equal(t, 4*time.Second, estimate.EstimatedDuration)
+
+	// 10M rows * 80 bytes / 50MiB/s
+	estimate = estimateStatement(Statement{}, "foobar", estimateWorkRewrite, analyzedStats)
+	assert.InDelta(t, 15.26, estimate.EstimatedDuration.Seconds(), 0.01)
+
+	t.Run("Tables created by the plan are empty", func(t *testing.T) {
+		estimate := estimateStatement(Statement{}, "foobar", estimateWorkIndexBuild, nil)
+		assert.Zero(t, estimate.EstimatedDuration)
+		assert.Equal(t, EstimateConfidenceHigh, estimate.Confidence)
+	})
+
+	t.Run("Stale statistics", func(t *testing.T) {
+		staleStats := *analyzedStats
+		staleStats.RowsModifiedSinceAnalyze = 2_000_000
+		estimate := estimateStatement(Statement{}, "foobar", estimateWorkIndexBuild, &staleStats)
+		assert.Equal(t, EstimateConfidenceLow, estimate.Confidence)
+		assert.Contains(t, estimate.Recommendation, "Run ANALYZE foobar")
+
+		neverAnalyzedStats := schema.TableStatistics{Pages: 100, EstimatedRows: -1}
+		estimate = estimateStatement(Statement{}, "foobar", estimateWorkRewrite, &neverAnalyzedStats)
+		assert.Equal(t, EstimateConfidenceLow, estimate.Confidence)
+		assert.Contains(t, estimate.Recommendation, "has never been analyzed")
+		// The rewrite is estimated from the number of pages
+		assert.Equal(t, durationForRate(100*estimatePageSizeBytes, estimateRewriteBytesPerSecond), estimate.EstimatedDuration)
+	})
+}
+
+func (suite *planGeneratorTestSuite) TestPlan_EstimateDuration() {
+	suite.mustApplyDDLToTestDb([]string{`
+		CREATE TABLE empty_table(id INT PRIMARY KEY, val TEXT);
+		CREATE TABLE large_table(id INT PRIMARY KEY, val TEXT);
+		INSERT INTO large_table SELECT i, md5(i::TEXT) FROM generate_series(1, 200000) AS i;
+		ANALYZE empty_table;
+		ANALYZE large_table;
+	`})
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: "CREATE INDEX CONCURRENTLY empty_table_val_idx ON public.empty_table USING btree (val)"},
+			{DDL: "CREATE INDEX CONCURRENTLY large_table_val_idx ON public.large_table USING btree (val)"},
+			{DDL: `ALTER TABLE "public"."large_table" ADD COLUMN "other_val" text`},
+			{DDL: `CREATE INDEX new_table_val_idx ON public.new_table USING btree (val)`},
+		},
+	}
+	estimates, err := plan.EstimateDuration(context.Background(), connPool)
+	suite.Require().NoError(err)
+	suite.Require().Len(estimates, 4)
+
+	suite.Less(estimates[0].EstimatedDuration, time.Millisecond)
+	suite.Greater(estimates[1].EstimatedDuration, estimates[0].EstimatedDuration)
+	suite.Zero(estimates[2].EstimatedDuration)
+	suite.Zero(estimates[3].EstimatedDuration)
+
+	// Modifying most of the table's rows makes its statistics stale. The statistics of the modifications are reported
+	// asynchronously
+	_, err = connPool.Exec("UPDATE large_table SET val = 'updated'")
+	suite.Require().NoError(err)
+	suite.Eventually(func() bool {
+		estimates, err := plan.EstimateDuration(context.Background(), connPool)
+		suite.Require().NoError(err)
+		return estimates[1].Confidence == EstimateConfidenceLow
+	}, 5*time.Second, 100*time.Millisecond)
+}
@@ -0,0 +1,324 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stripe/pg-schema-diff/pkg/log"
+)
+
+// ExecutionError is an error from executing a statement of the plan. Statements that were committed before the
+// failure cannot be rolled back.
+type ExecutionError struct {
+	// StatementIndex is the position of the failed statement in the plan
+	StatementIndex int
+	Statement      Statement
+	// PermanentStatementIndexes are the positions of the statements that were committed before the failure. Their
+	// changes are permanent, i.e., the database is in a state between the current and target schemas.
+	PermanentStatementIndexes []int
+	// Err is the underlying error
+	Err error
+}
+
+func (e ExecutionError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("executing statement %d: %s: %s. ", e.StatementIndex, e.Statement.ToSQL(), e.Err))
+	if e.Statement.RequiresNoTransaction {
+		sb.WriteString("The statement was run outside a transaction, so it may have been partially applied, e.g., an invalid index may be left behind. ")
+	}
+	if len(e.PermanentStatementIndexes) == 0 {
+		sb.WriteString("No statements were committed")
+	} else {
+		var idxStrs []string
+		for _, idx := range e.PermanentStatementIndexes {
+			idxStrs = append(idxStrs, fmt.Sprintf("%d", idx))
+		}
+		sb.WriteString(fmt.Sprintf("Statements %s were already committed and cannot be rolled back", strings.Join(idxStrs, ", ")))
+	}
+	return sb.String()
+}
+
+func (e ExecutionError) Unwrap() error {
+	return e.Err
+}
+
+type (
+	// Executor executes plans against a database
+	Executor struct {
+		logger log.Logger
+		// lockTimeoutRetries is the number of times a statement is retried if it exceeds its lock timeout
+		lockTimeoutRetries int
+		// lockTimeoutRetryBackoff is the time to wait before the first retry. It doubles with each retry.
+		lockTimeoutRetryBackoff time.Duration
+		// replicationLagChecker checks the replication lag around long-running statements if set
+		replicationLagChecker      ReplicationLagChecker
+		maxReplicationLagSeconds   float64
+		replicationLagPollInterval time.Duration
+	}
+
+	ExecutorOpt func(e *Executor)
+)
+
+// WithExecutorLogger configures the logger that the Executor logs to, e.g., the suppressed hazards of each statement
+func WithExecutorLogger(logger log.Logger) ExecutorOpt {
+	return func(e *Executor) {
+		e.logger = logger
+	}
+}
+
+// WithLockTimeoutRetries configures the Executor to retry a statement up to retries times if it fails because it exceeded
+// its lock timeout, e.g., because a long-running query holds a conflicting lock. The Executor waits initialBackoff before
+// the first retry, doubling the wait with each retry.
+//
+// Within a transaction, the statement is retried from a savepoint, so the preceding statements of the transaction are
+// not rolled back. Their locks are held while waiting to retry.
+func WithLockTimeoutRetries(retries int, initialBackoff time.Duration) ExecutorOpt {
+	return func(e *Executor) {
+		e.lockTimeoutRetries = retries
+		e.lockTimeoutRetryBackoff = initialBackoff
+	}
+}
+
+func NewExecutor(opts ...ExecutorOpt) *Executor {
+	e := &Executor{
+		logger:                     log.SimpleLogger(),
+		replicationLagPollInterval: replicationLagPollIntervalDefault,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// RunInTransaction executes the plan against the database, grouping the statements into as few transactions as
+// possible. Consecutive statements that can be run in a transaction are run in a single transaction, such that if one
+// of them fails, all of them are rolled back. Statements that require no transaction, e.g., `CREATE INDEX
+// CONCURRENTLY`, cannot be run in a transaction block: the open transaction is committed before each of them, and a new
+// transaction is started after it. Advisory statements are skipped.
+//
+// The statements are executed in order on a single connection, with the plan's migration hooks, statement hooks, and
+// progress reporter run around them. The suppressed hazards of each statement are logged with their justification before
+// it is executed. If a statement fails, an ExecutionError is returned describing which statements were already
+// committed and are permanent.
+//
+// If the plan requires its hazards to be acknowledged, i.e., it was generated with WithRequireHazardAcknowledgment, an
+// error wrapping ErrUnacknowledgedHazards is returned without executing any statements while it has unsuppressed hazards.
+func (e *Executor) RunInTransaction(ctx context.Context, plan Plan, db *sql.DB) error {
+	if err := plan.checkHazardsAcknowledged(); err != nil {
+		return err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	return plan.RunWithMigrationHooks(ctx, func(ctx context.Context, plan Plan) error {
+		var permanentStmtIdxs []int
+		for _, batch := range buildExecutionBatches(plan.Statements) {
+			var err error
+			if batch.inTransaction {
+				err = e.runBatchInTransaction(ctx, conn, plan, batch.stmtIdxs)
+			} else {
+				err = e.runBatchWithoutTransaction(ctx, conn, plan, batch.stmtIdxs)
+			}
+			if err != nil {
+				var execErr ExecutionError
+				if errors.As(err, &execErr) {
+					execErr.PermanentStatementIndexes = permanentStmtIdxs
+					return execErr
+				}
+				return err
+			}
+			permanentStmtIdxs = append(permanentStmtIdxs, batch.stmtIdxs...)
+			if err := e.waitForReplicationLag(ctx, plan, batch.stmtIdxs); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// executionBatch is a group of consecutive statements that are committed together
+type executionBatch struct {
+	// stmtIdxs are the positions of the batch's statements in the plan, including advisory statements, which are not
+	// executed
+	stmtIdxs      []int
+	inTransaction bool
+}
+
+// buildExecutionBatches groups the statements into batches. Consecutive statements that can be run in a transaction
+// are grouped into a single batch, and each statement that requires no transaction is in its own batch. Advisory
+// statements are added to the batch they are positioned in, such that progress is reported for them in order.
+func buildExecutionBatches(stmts []Statement) []executionBatch {
+	var batches []executionBatch
+	for i, stmt := range stmts {
+		if stmt.RequiresNoTransaction && !stmt.IsAdvisory {
+			batches = append(batches, executionBatch{stmtIdxs: []int{i}})
+			continue
+		}
+		if len(batches) == 0 || !batches[len(batches)-1].inTransaction {
+			batches = append(batches, executionBatch{inTransaction: true})
+		}
+		batches[len(batches)-1].stmtIdxs = append(batches[len(batches)-1].stmtIdxs, i)
+	}
+	return batches
+}
+
+func (e *Executor) runBatchInTransaction(ctx context.Context, conn *sql.Conn, plan Plan, stmtIdxs []int) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, idx := range stmtIdxs {
+		if err := e.runStatement(ctx, plan, idx, func(ctx context.Context, stmt Statement) error {
+			if err := setLocalTimeout(ctx, tx, "statement_timeout", stmt.Timeout); err != nil {
+				return err
+			}
+			if err := setLocalTimeout(ctx, tx, "lock_timeout", stmt.LockTimeout); err != nil {
+				return err
+			}
+			return e.execWithLockTimeoutRetries(ctx, idx, func() error {
+				if e.lockTimeoutRetries == 0 {
+					_, err := tx.ExecContext(ctx, stmt.ToSQL())
+					return err
+				}
+				return execWithSavepoint(ctx, tx, stmt.ToSQL())
+			})
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		lastIdx := stmtIdxs[len(stmtIdxs)-1]
+		return ExecutionError{
+			StatementIndex: lastIdx,
+			Statement:      plan.Statements[lastIdx],
+			Err:            fmt.Errorf("committing transaction: %w", err),
+		}
+	}
+	return nil
+}
+
+func (e *Executor) runBatchWithoutTransaction(ctx context.Context, conn *sql.Conn, plan Plan, stmtIdxs []int) error {
+	for _, idx := range stmtIdxs {
+		if err := e.runStatement(ctx, plan, idx, func(ctx context.Context, stmt Statement) error {
+			// The timeouts are set at the SESSION-level, since the statement is not run in a transaction. The previous
+			// timeouts are restored afterward, such that they do not carry over to the connection once it's returned to
+			// the pool
+			restoreTimeouts, err := setSessionTimeouts(ctx, conn, stmt)
+			if err != nil {
+				return err
+			}
+			defer restoreTimeouts()
+			return e.execWithLockTimeoutRetries(ctx, idx, func() error {
+				_, err := conn.ExecContext(ctx, stmt.ToSQL())
+				return err
+			})
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runStatement runs the plan's statement at idx via execute with the plan's statement hooks and progress reporter. If
+// the statement fails, an ExecutionError is returned.
+func (e *Executor) runStatement(ctx context.Context, plan Plan, idx int, execute func(ctx context.Context, stmt Statement) error) error {
+	plan.ReportProgress(ctx, idx)
+	if plan.Statements[idx].IsAdvisory {
+		return nil
+	}
+	for _, hazard := range plan.Statements[idx].Hazards {
+		if hazard.Suppressed {
+			e.logger.Warnf("Executing statement %d with suppressed hazard %s. Justification: %s", idx, hazard.String(), hazard.Justification)
+		}
+	}
+	if err := e.checkReplicationLag(ctx, plan.Statements[idx]); err != nil {
+		return ExecutionError{
+			StatementIndex: idx,
+			Statement:      plan.Statements[idx],
+			Err:            err,
+		}
+	}
+	if err := plan.RunStatementWithHooks(ctx, idx, execute); err != nil {
+		return ExecutionError{
+			StatementIndex: idx,
+			Statement:      plan.Statements[idx],
+			Err:            err,
+		}
+	}
+	return nil
+}
+
+// setSessionTimeouts sets the statement's timeouts at the SESSION-level. It returns a function that restores the
+// previous timeouts. Errors restoring the timeouts are ignored, since the statement has already been executed.
+func setSessionTimeouts(ctx context.Context, conn *sql.Conn, stmt Statement) (func(), error) {
+	var prevStatementTimeout, prevLockTimeout string
+	if err := conn.QueryRowContext(ctx, "SELECT current_setting('statement_timeout'), current_setting('lock_timeout')").Scan(&prevStatementTimeout, &prevLockTimeout); err != nil {
+		return nil, fmt.Errorf("getting current timeouts: %w", err)
+	}
+	restore := func() {
+		_, _ = conn.ExecContext(ctx, "SELECT set_config('statement_timeout', $1, false), set_config('lock_timeout', $2, false)", prevStatementTimeout, prevLockTimeout)
+	}
+	for _, timeout := range []struct {
+		setting string
+		value   time.Duration
+	}{
+		{setting: "statement_timeout", value: stmt.Timeout},
+		{setting: "lock_timeout", value: stmt.LockTimeout},
+	} {
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET SESSION %s = %d", timeout.setting, timeout.value.Milliseconds())); err != nil {
+			restore()
+			return nil, fmt.Errorf("setting %s: %w", timeout.setting, err)
+		}
+	}
+	return restore, nil
+}
+
+// execWithLockTimeoutRetries runs exec, retrying it with exponential backoff if it fails because the statement exceeded
+// its lock timeout
+func (e *Executor) execWithLockTimeoutRetries(ctx context.Context, stmtIdx int, exec func() error) error {
+	backoff := e.lockTimeoutRetryBackoff
+	for retry := 1; ; retry++ {
+		err := exec()
+		if err == nil || retry > e.lockTimeoutRetries || !isLockTimeoutError(err) {
+			return err
+		}
+		e.logger.Warnf("Statement %d exceeded its lock timeout. Retrying in %s (retry %d of %d)", stmtIdx, backoff, retry, e.lockTimeoutRetries)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting to retry: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// execWithSavepoint executes the statement in the transaction from a savepoint, such that the transaction can continue if
+// the statement fails because it exceeded its lock timeout
+func execWithSavepoint(ctx context.Context, tx *sql.Tx, stmt string) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT pgschemadiff_statement"); err != nil {
+		return fmt.Errorf("creating savepoint: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, stmt); err != nil {
+		if isLockTimeoutError(err) {
+			if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT pgschemadiff_statement"); rollbackErr != nil {
+				return errors.Join(err, fmt.Errorf("rolling back to savepoint: %w", rollbackErr))
+			}
+		}
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT pgschemadiff_statement"); err != nil {
+		return fmt.Errorf("releasing savepoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestBuildExecutionBatches(t *testing.T) {
+	stmts := []Statement{
+		{DDL: "ALTER TABLE foobar ADD COLUMN val TEXT"},
+		{DDL: "ALTER TABLE foobar ADD COLUMN other_val TEXT"},
+		{DDL: "CREATE INDEX CONCURRENTLY foobar_val_idx ON foobar(val)", RequiresNoTransaction: true},
+		{DDL: "CREATE INDEX CONCURRENTLY foobar_other_val_idx ON foobar(other_val)", RequiresNoTransaction: true},
+		{DDL: "ALTER TABLE foobar ADD CONSTRAINT foobar_val_key UNIQUE USING INDEX foobar_val_idx"},
+		{DDL: "ANALYZE foobar", IsAdvisory: true},
+	}
+	assert.Equal(t, []executionBatch{
+		{stmtIdxs: []int{0, 1}, inTransaction: true},
+		{stmtIdxs: []int{2}},
+		{stmtIdxs: []int{3}},
+		{stmtIdxs: []int{4, 5}, inTransaction: true},
+	}, buildExecutionBatches(stmts))
+
+	assert.Empty(t, buildExecutionBatches(nil))
+}
+
+func TestExecutionError(t *testing.T) {
+	underlyingErr := errors.New("some error")
+	for _, tc := range []struct {
+		name          string
+		err           ExecutionError
+		expectedError string
+	}{
+		{
+			name: "no statements committed",
+			err: ExecutionError{
+				StatementIndex: 1,
+				Statement:      Statement{DDL: "ALTER TABLE foobar ADD COLUMN val TEXT"},
+				Err:            underlyingErr,
+			},
+			expectedError: "executing statement 1: ALTER TABLE foobar ADD COLUMN val TEXT;: some error. No statements were committed",
+		},
+		{
+			name: "statements committed",
+			err: ExecutionError{
+				StatementIndex:            3,
+				Statement:                 Statement{DDL: "CREATE INDEX CONCURRENTLY foobar_val_idx ON foobar(val)", RequiresNoTransaction: true},
+				PermanentStatementIndexes: []int{0, 1, 2},
+				Err:                       underlyingErr,
+			},
+			expectedError: "executing statement 3: CREATE INDEX CONCURRENTLY foobar_val_idx ON foobar(val);: some error. " +
+				"The statement was run outside a transaction, so it may have been partially applied, e.g., an invalid index may be left behind. " +
+				"Statements 0, 1, 2 were already committed and cannot be rolled back",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualError(t, tc.err, tc.expectedError)
+			assert.ErrorIs(t, tc.err, underlyingErr)
+		})
+	}
+}
+
+func (suite *planGeneratorTestSuite) TestExecutor_RunInTransaction() {
+	suite.mustApplyDDLToTestDb([]string{`CREATE TABLE foobar(id INT PRIMARY KEY);`})
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: "ALTER TABLE foobar ADD COLUMN val TEXT", Timeout: 3 * time.Second, LockTimeout: time.Second},
+			{DDL: "CREATE INDEX CONCURRENTLY foobar_val_idx ON foobar(val)", Timeout: time.Minute, LockTimeout: time.Second, RequiresNoTransaction: true},
+			{DDL: "ALTER TABLE foobar ADD COLUMN other_val TEXT", Timeout: 3 * time.Second, LockTimeout: time.Second},
+			{DDL: "ANALYZE missing_table", IsAdvisory: true},
+			{DDL: "ALTER TABLE foobar ADD CONSTRAINT foobar_val_key UNIQUE USING INDEX foobar_val_idx", Timeout: 3 * time.Second},
+		},
+	}
+	suite.Require().NoError(NewExecutor().RunInTransaction(context.Background(), plan, connPool))
+	_, err := connPool.ExecContext(context.Background(), "SELECT val, other_val FROM foobar")
+	suite.NoError(err)
+	_, err = connPool.ExecContext(context.Background(), "INSERT INTO foobar VALUES (1, 'a'), (2, 'a')")
+	suite.Error(err)
+}
+
+func (suite *planGeneratorTestSuite) TestExecutor_RunInTransactionPartialFailure() {
+	suite.mustApplyDDLToTestDb([]string{`CREATE TABLE foobar(id INT PRIMARY KEY);`})
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: "ALTER TABLE foobar ADD COLUMN val TEXT", Timeout: 3 * time.Second},
+			{DDL: "CREATE INDEX CONCURRENTLY foobar_val_idx ON foobar(val)", Timeout: time.Minute, RequiresNoTransaction: true},
+			{DDL: "ALTER TABLE foobar ADD COLUMN other_val TEXT", Timeout: 3 * time.Second},
+			{DDL: "ALTER TABLE missing_table ADD COLUMN val TEXT", Timeout: 3 * time.Second},
+		},
+	}
+	err := NewExecutor().RunInTransaction(context.Background(), plan, connPool)
+	var execErr ExecutionError
+	suite.Require().ErrorAs(err, &execErr)
+	suite.Equal(3, execErr.StatementIndex)
+	suite.Equal([]int{0, 1}, execErr.PermanentStatementIndexes)
+
+	// The statements before the non-transactional statement are committed, but the statements in the failed transaction
+	// are rolled back
+	_, err = connPool.ExecContext(context.Background(), "SELECT val FROM foobar")
+	suite.NoError(err)
+	_, err = connPool.ExecContext(context.Background(), "SELECT other_val FROM foobar")
+	suite.Error(err)
+}
+
+type recordingLogger struct {
+	warnings []string
+}
+
+func (l *recordingLogger) Errorf(string, ...any) {}
+
+func (l *recordingLogger) Warnf(msg string, args ...any) {
+	l.warnings = append(l.warnings, fmt.Sprintf(msg, args...))
+}
+
+func (suite *planGeneratorTestSuite) TestExecutor_RunInTransactionLogsSuppressedHazards() {
+	suite.mustApplyDDLToTestDb([]string{`CREATE TABLE foobar(id INT PRIMARY KEY, val TEXT);`})
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	plan := Plan{
+		Statements: []Statement{
+			{
+				DDL:     "ALTER TABLE foobar DROP COLUMN val",
+				Timeout: 3 * time.Second,
+				Hazards: []MigrationHazard{{Type: MigrationHazardTypeDeletesData, Message: "Deletes all values in the column"}},
+			},
+		},
+	}
+	plan = plan.SetRequireHazardAcknowledgment(true)
+	plan, err := plan.SuppressHazard(0, MigrationHazardTypeDeletesData, "The column was never written to")
+	suite.Require().NoError(err)
+
+	logger := &recordingLogger{}
+	suite.Require().NoError(NewExecutor(WithExecutorLogger(logger)).RunInTransaction(context.Background(), plan, connPool))
+	suite.Equal([]string{
+		"Executing statement 0 with suppressed hazard DELETES_DATA: Deletes all values in the column. Justification: The column was never written to",
+	}, logger.warnings)
+}
+
+func TestWithLockTimeout(t *testing.T) {
+	newSchema := schema.Schema{
+		NamedSchemas: []schema.NamedSchema{{Name: "foo"}, {Name: "bar"}},
+	}
+	plan, err := buildPlan(schema.Schema{}, newSchema, &planOptions{lockTimeout: 250 * time.Millisecond})
+	require.NoError(t, err)
+	require.Len(t, plan.Statements, 2)
+	for _, stmt := range plan.Statements {
+		assert.Equal(t, 250*time.Millisecond, stmt.LockTimeout)
+	}
+}
+
+func TestExecWithLockTimeoutRetries(t *testing.T) {
+	lockTimeoutErr := &pgconn.PgError{Code: "55P03", Message: "canceling statement due to lock timeout"}
+	for _, tc := range []struct {
+		name             string
+		retries          int
+		errs             []error
+		expectedAttempts int
+		expectedErr      error
+	}{
+		{
+			name:             "succeeds after retries",
+			retries:          3,
+			errs:             []error{lockTimeoutErr, lockTimeoutErr, nil},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "retries exhausted",
+			retries:          2,
+			errs:             []error{lockTimeoutErr, lockTimeoutErr, lockTimeoutErr, nil},
+			expectedAttempts: 3,
+			expectedErr:      lockTimeoutErr,
+		},
+		{
+			name:             "no retries",
+			errs:             []error{lockTimeoutErr, nil},
+			expectedAttempts: 1,
+			expectedErr:      lockTimeoutErr,
+		},
+		{
+			name:             "other errors are not retried",
+			retries:          3,
+			errs:             []error{&pgconn.PgError{Code: "42P01"}, nil},
+			expectedAttempts: 1,
+			expectedErr:      &pgconn.PgError{Code: "42P01"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			executor := NewExecutor(WithExecutorLogger(logger), WithLockTimeoutRetries(tc.retries, time.Millisecond))
+			var attempts int
+			err := executor.execWithLockTimeoutRetries(context.Background(), 0, func() error {
+				err := tc.errs[attempts]
+				attempts++
+				return err
+			})
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedAttempts, attempts)
+			assert.Len(t, logger.warnings, tc.expectedAttempts-1)
+		})
+	}
+
+	t.Run("backoff doubles", func(t *testing.T) {
+		logger := &recordingLogger{}
+		executor := NewExecutor(WithExecutorLogger(logger), WithLockTimeoutRetries(2, time.Millisecond))
+		_ = executor.execWithLockTimeoutRetries(context.Background(), 4, func() error { return lockTimeoutErr })
+		assert.Equal(t, []string{
+			"Statement 4 exceeded its lock timeout. Retrying in 1ms (retry 1 of 2)",
+			"Statement 4 exceeded its lock timeout. Retrying in 2ms (retry 2 of 2)",
+		}, logger.warnings)
+	})
+
+	t.Run("context canceled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		executor := NewExecutor(WithLockTimeoutRetries(1, time.Hour))
+		err := executor.execWithLockTimeoutRetries(ctx, 0, func() error { return lockTimeoutErr })
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func (suite *planGeneratorTestSuite) TestExecutor_LockTimeoutRetries() {
+	suite.mustApplyDDLToTestDb([]string{`CREATE TABLE foobar(id INT PRIMARY KEY);`})
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: "ALTER TABLE foobar ADD COLUMN val TEXT", Timeout: 3 * time.Second, LockTimeout: 50 * time.Millisecond},
+			{DDL: "ALTER TABLE foobar ADD COLUMN other_val TEXT", Timeout: 3 * time.Second, LockTimeout: 50 * time.Millisecond},
+		},
+	}
+
+	// Hold a conflicting lock from another connection
+	lockTx, err := connPool.BeginTx(context.Background(), nil)
+	suite.Require().NoError(err)
+	_, err = lockTx.ExecContext(context.Background(), "LOCK TABLE foobar IN ACCESS SHARE MODE")
+	suite.Require().NoError(err)
+
+	err = NewExecutor().RunInTransaction(context.Background(), plan, connPool)
+	suite.Require().Error(err)
+	suite.True(isLockTimeoutError(err))
+
+	// Release the lock while the executor is backing off
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_ = lockTx.Rollback()
+	}()
+	suite.Require().NoError(NewExecutor(WithLockTimeoutRetries(5, 50*time.Millisecond)).RunInTransaction(context.Background(), plan, connPool))
+	_, err = connPool.ExecContext(context.Background(), "SELECT val, other_val FROM foobar")
+	suite.NoError(err)
+
+	// The previous session-level lock timeout is restored after a statement run outside a transaction
+	conn, err := connPool.Conn(context.Background())
+	suite.Require().NoError(err)
+	defer conn.Close()
+	_, err = conn.ExecContext(context.Background(), "SET lock_timeout = '7s'")
+	suite.Require().NoError(err)
+	restoreTimeouts, err := setSessionTimeouts(context.Background(), conn, Statement{Timeout: time.Second, LockTimeout: time.Second})
+	suite.Require().NoError(err)
+	restoreTimeouts()
+	var lockTimeout string
+	suite.Require().NoError(conn.QueryRowContext(context.Background(), "SHOW lock_timeout").Scan(&lockTimeout))
+	suite.Equal("7s", lockTimeout)
+}
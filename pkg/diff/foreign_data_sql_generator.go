@@ -0,0 +1,359 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	migrationHazardForeignServerRecreated = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "The foreign-data wrapper or type of the server changed, so the server must be dropped and re-created. " +
+			"This will fail if any foreign tables or user mappings still use the server.",
+	}
+	migrationHazardForeignServerDeleted = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "User mappings are not tracked. Dropping the server will fail if any user mappings for the server " +
+			"still exist.",
+	}
+)
+
+// foreignDataWrapperSQLGenerator is a SQL generator for foreign-data wrappers. Like extensions, foreign-data wrappers
+// are added before and dropped after all other objects that might depend on them, i.e., foreign servers.
+type foreignDataWrapperSQLGenerator struct{}
+
+func (f *foreignDataWrapperSQLGenerator) Add(wrapper schema.ForeignDataWrapper) ([]Statement, error) {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("CREATE FOREIGN DATA WRAPPER %s", schema.EscapeIdentifier(wrapper.Name)))
+	if !wrapper.Handler.IsEmpty() {
+		sb.WriteString(fmt.Sprintf(" HANDLER %s", wrapper.Handler.GetFQEscapedName()))
+	}
+	if !wrapper.Validator.IsEmpty() {
+		sb.WriteString(fmt.Sprintf(" VALIDATOR %s", wrapper.Validator.GetFQEscapedName()))
+	}
+	if len(wrapper.Options) > 0 {
+		sb.WriteString(fmt.Sprintf(" OPTIONS (%s)", buildForeignOptionList(wrapper.Options)))
+	}
+	return []Statement{
+		{
+			DDL:         sb.String(),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (f *foreignDataWrapperSQLGenerator) Delete(wrapper schema.ForeignDataWrapper) ([]Statement, error) {
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("DROP FOREIGN DATA WRAPPER %s", schema.EscapeIdentifier(wrapper.Name)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (f *foreignDataWrapperSQLGenerator) Alter(diff foreignDataWrapperDiff) ([]Statement, error) {
+	var alterations []string
+	if diff.old.Handler != diff.new.Handler {
+		if diff.new.Handler.IsEmpty() {
+			alterations = append(alterations, "NO HANDLER")
+		} else {
+			alterations = append(alterations, fmt.Sprintf("HANDLER %s", diff.new.Handler.GetFQEscapedName()))
+		}
+	}
+	if diff.old.Validator != diff.new.Validator {
+		if diff.new.Validator.IsEmpty() {
+			alterations = append(alterations, "NO VALIDATOR")
+		} else {
+			alterations = append(alterations, fmt.Sprintf("VALIDATOR %s", diff.new.Validator.GetFQEscapedName()))
+		}
+	}
+	if optionAlterations := buildAlterForeignOptionList(diff.old.Options, diff.new.Options); len(optionAlterations) > 0 {
+		alterations = append(alterations, fmt.Sprintf("OPTIONS (%s)", optionAlterations))
+	}
+	if len(alterations) == 0 {
+		return nil, nil
+	}
+
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("ALTER FOREIGN DATA WRAPPER %s %s", schema.EscapeIdentifier(diff.new.Name), strings.Join(alterations, " ")),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+// foreignServerSQLGenerator is a SQL generator for foreign servers. Foreign servers are added after the foreign-data
+// wrappers they use and before all other objects that might depend on them, i.e., foreign tables. They are dropped in
+// the reverse order.
+type foreignServerSQLGenerator struct{}
+
+func (f *foreignServerSQLGenerator) Add(server schema.ForeignServer) ([]Statement, error) {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("CREATE SERVER %s", schema.EscapeIdentifier(server.Name)))
+	if len(server.Type) > 0 {
+		sb.WriteString(fmt.Sprintf(" TYPE %s", quoteForeignOptionValue(server.Type)))
+	}
+	if len(server.Version) > 0 {
+		sb.WriteString(fmt.Sprintf(" VERSION %s", quoteForeignOptionValue(server.Version)))
+	}
+	sb.WriteString(fmt.Sprintf(" FOREIGN DATA WRAPPER %s", schema.EscapeIdentifier(server.ForeignDataWrapper)))
+	if len(server.Options) > 0 {
+		sb.WriteString(fmt.Sprintf(" OPTIONS (%s)", buildForeignOptionList(server.Options)))
+	}
+	return []Statement{
+		{
+			DDL:         sb.String(),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (f *foreignServerSQLGenerator) Delete(server schema.ForeignServer) ([]Statement, error) {
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("DROP SERVER %s", schema.EscapeIdentifier(server.Name)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardForeignServerDeleted},
+		},
+	}, nil
+}
+
+func (f *foreignServerSQLGenerator) Alter(diff foreignServerDiff) ([]Statement, error) {
+	if diff.old.ForeignDataWrapper != diff.new.ForeignDataWrapper || diff.old.Type != diff.new.Type {
+		// The foreign-data wrapper and type of a server cannot be altered. Similar to domains, the server must be
+		// re-created in the alter statement, since the normal delete -> add ordering would drop the server after all
+		// other objects are migrated.
+		deletes, err := f.Delete(diff.old)
+		if err != nil {
+			return nil, fmt.Errorf("generating delete statements: %w", err)
+		}
+		adds, err := f.Add(diff.new)
+		if err != nil {
+			return nil, fmt.Errorf("generating add statements: %w", err)
+		}
+		stmts := append(deletes, adds...)
+		stmts[0].Hazards = append(stmts[0].Hazards, migrationHazardForeignServerRecreated)
+		return stmts, nil
+	}
+
+	var alterations []string
+	if diff.old.Version != diff.new.Version {
+		// A version cannot be removed from a server, so an empty version is set instead
+		alterations = append(alterations, fmt.Sprintf("VERSION %s", quoteForeignOptionValue(diff.new.Version)))
+	}
+	if optionAlterations := buildAlterForeignOptionList(diff.old.Options, diff.new.Options); len(optionAlterations) > 0 {
+		alterations = append(alterations, fmt.Sprintf("OPTIONS (%s)", optionAlterations))
+	}
+	if len(alterations) == 0 {
+		return nil, nil
+	}
+
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("ALTER SERVER %s %s", schema.EscapeIdentifier(diff.new.Name), strings.Join(alterations, " ")),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+type foreignTableSQLVertexGenerator struct{}
+
+func newForeignTableSqlVertexGenerator() sqlVertexGenerator[schema.ForeignTable, foreignTableDiff] {
+	return legacyToNewSqlVertexGenerator[schema.ForeignTable, foreignTableDiff](&foreignTableSQLVertexGenerator{})
+}
+
+func (f *foreignTableSQLVertexGenerator) Add(foreignTable schema.ForeignTable) ([]Statement, error) {
+	var columnDefs []string
+	for _, column := range foreignTable.Columns {
+		columnDef, err := buildColumnDefinition(column)
+		if err != nil {
+			return nil, fmt.Errorf("building column definition: %w", err)
+		}
+		columnDefs = append(columnDefs, "\t"+columnDef)
+	}
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("CREATE FOREIGN TABLE %s (\n%s\n) SERVER %s",
+		foreignTable.GetFQEscapedName(),
+		strings.Join(columnDefs, ",\n"),
+		schema.EscapeIdentifier(foreignTable.Server),
+	))
+	if len(foreignTable.Options) > 0 {
+		sb.WriteString(fmt.Sprintf(" OPTIONS (%s)", buildForeignOptionList(foreignTable.Options)))
+	}
+	return []Statement{
+		{
+			DDL:         sb.String(),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (f *foreignTableSQLVertexGenerator) Delete(foreignTable schema.ForeignTable) ([]Statement, error) {
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("DROP FOREIGN TABLE %s", foreignTable.GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+// Alter alters the columns and options of the foreign table. Changes to the server are resolved by re-creating the
+// foreign table (see buildForeignTableDiff). Foreign tables store no data locally, so none of these statements rewrite
+// or scan any data.
+func (f *foreignTableSQLVertexGenerator) Alter(diff foreignTableDiff) ([]Statement, error) {
+	alterPrefix := fmt.Sprintf("ALTER FOREIGN TABLE %s", diff.new.GetFQEscapedName())
+	oldColumnsByName := buildSchemaObjByNameMap(diff.old.Columns)
+	newColumnsByName := buildSchemaObjByNameMap(diff.new.Columns)
+
+	var stmts []Statement
+	for _, column := range diff.old.Columns {
+		if _, ok := newColumnsByName[column.GetName()]; ok {
+			continue
+		}
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s DROP COLUMN %s", alterPrefix, schema.EscapeIdentifier(column.Name)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	}
+
+	for _, column := range diff.new.Columns {
+		oldColumn, ok := oldColumnsByName[column.GetName()]
+		if !ok {
+			columnDef, err := buildColumnDefinition(column)
+			if err != nil {
+				return nil, fmt.Errorf("building column definition: %w", err)
+			}
+			stmts = append(stmts, Statement{
+				DDL:         fmt.Sprintf("%s ADD COLUMN %s", alterPrefix, columnDef),
+				Timeout:     statementTimeoutDefault,
+				LockTimeout: lockTimeoutDefault,
+			})
+			continue
+		}
+
+		alterColumnPrefix := fmt.Sprintf("%s ALTER COLUMN %s", alterPrefix, schema.EscapeIdentifier(column.Name))
+		var ddls []string
+		if oldColumn.Type != column.Type || oldColumn.Collation != column.Collation {
+			ddl := fmt.Sprintf("%s TYPE %s", alterColumnPrefix, column.Type)
+			if column.IsCollated() {
+				ddl += fmt.Sprintf(" COLLATE %s", column.Collation.GetFQEscapedName())
+			}
+			ddls = append(ddls, ddl)
+		}
+		if oldColumn.IsNullable != column.IsNullable {
+			if column.IsNullable {
+				ddls = append(ddls, fmt.Sprintf("%s DROP NOT NULL", alterColumnPrefix))
+			} else {
+				ddls = append(ddls, fmt.Sprintf("%s SET NOT NULL", alterColumnPrefix))
+			}
+		}
+		if oldColumn.Default != column.Default {
+			if len(column.Default) == 0 {
+				ddls = append(ddls, fmt.Sprintf("%s DROP DEFAULT", alterColumnPrefix))
+			} else {
+				ddls = append(ddls, fmt.Sprintf("%s SET DEFAULT %s", alterColumnPrefix, column.Default))
+			}
+		}
+		for _, ddl := range ddls {
+			stmts = append(stmts, Statement{
+				DDL:         ddl,
+				Timeout:     statementTimeoutDefault,
+				LockTimeout: lockTimeoutDefault,
+			})
+		}
+	}
+
+	if optionAlterations := buildAlterForeignOptionList(diff.old.Options, diff.new.Options); len(optionAlterations) > 0 {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s OPTIONS (%s)", alterPrefix, optionAlterations),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	}
+
+	return stmts, nil
+}
+
+func (f *foreignTableSQLVertexGenerator) GetSQLVertexId(foreignTable schema.ForeignTable, diffType diffType) sqlVertexId {
+	return buildForeignTableVertexId(foreignTable.SchemaQualifiedName, diffType)
+}
+
+func buildForeignTableVertexId(name schema.SchemaQualifiedName, diffType diffType) sqlVertexId {
+	return buildSchemaObjVertexId("foreign_table", name.GetFQEscapedName(), diffType)
+}
+
+func (f *foreignTableSQLVertexGenerator) GetAddAlterDependencies(newForeignTable, _ schema.ForeignTable) ([]dependency, error) {
+	return []dependency{
+		mustRun(f.GetSQLVertexId(newForeignTable, diffTypeAddAlter)).after(f.GetSQLVertexId(newForeignTable, diffTypeDelete)),
+	}, nil
+}
+
+func (f *foreignTableSQLVertexGenerator) GetDeleteDependencies(_ schema.ForeignTable) ([]dependency, error) {
+	return nil, nil
+}
+
+// buildForeignTableDiff builds the diff for a foreign table. The server of a foreign table cannot be altered, so the
+// foreign table is re-created if its server changes.
+func buildForeignTableDiff(old, new schema.ForeignTable, _, _ int) (foreignTableDiff, bool, error) {
+	return foreignTableDiff{
+		oldAndNew: oldAndNew[schema.ForeignTable]{
+			old: old,
+			new: new,
+		},
+	}, old.Server != new.Server, nil
+}
+
+// buildForeignOptionList builds the options of a foreign-data wrapper, foreign server, or foreign table, e.g.,
+// "dbname 'foo', host 'localhost'". The options are sorted by key, such that the output is deterministic.
+func buildForeignOptionList(options map[string]string) string {
+	var optionDefs []string
+	for _, key := range sortedForeignOptionKeys(options) {
+		optionDefs = append(optionDefs, fmt.Sprintf("%s %s", key, quoteForeignOptionValue(options[key])))
+	}
+	return strings.Join(optionDefs, ", ")
+}
+
+// buildAlterForeignOptionList builds the option alterations that change the old options to the new options, e.g.,
+// "ADD port '5432', SET host 'localhost', DROP dbname". It is empty if the options are unchanged.
+func buildAlterForeignOptionList(oldOptions, newOptions map[string]string) string {
+	var optionDefs []string
+	for _, key := range sortedForeignOptionKeys(newOptions) {
+		oldValue, ok := oldOptions[key]
+		if !ok {
+			optionDefs = append(optionDefs, fmt.Sprintf("ADD %s %s", key, quoteForeignOptionValue(newOptions[key])))
+		} else if oldValue != newOptions[key] {
+			optionDefs = append(optionDefs, fmt.Sprintf("SET %s %s", key, quoteForeignOptionValue(newOptions[key])))
+		}
+	}
+	for _, key := range sortedForeignOptionKeys(oldOptions) {
+		if _, ok := newOptions[key]; !ok {
+			optionDefs = append(optionDefs, fmt.Sprintf("DROP %s", key))
+		}
+	}
+	return strings.Join(optionDefs, ", ")
+}
+
+func sortedForeignOptionKeys(options map[string]string) []string {
+	var keys []string
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func quoteForeignOptionValue(value string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
+}
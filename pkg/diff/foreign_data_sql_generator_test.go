@@ -0,0 +1,165 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var foreignTableName = schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"remote_users\""}
+
+func TestForeignDataWrapperSQLGenerator(t *testing.T) {
+	handler := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"my_fdw_handler\""}
+	validator := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"my_fdw_validator\""}
+
+	stmts, err := (&foreignDataWrapperSQLGenerator{}).Add(schema.ForeignDataWrapper{
+		Name:      "my_fdw",
+		Handler:   handler,
+		Validator: validator,
+		Options:   map[string]string{"debug": "true", "batch_size": "100"},
+	})
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "CREATE FOREIGN DATA WRAPPER \"my_fdw\" HANDLER \"public\".\"my_fdw_handler\" VALIDATOR \"public\".\"my_fdw_validator\" OPTIONS (batch_size '100', debug 'true')", stmts[0].DDL)
+
+	stmts, err = (&foreignDataWrapperSQLGenerator{}).Alter(foreignDataWrapperDiff{oldAndNew: oldAndNew[schema.ForeignDataWrapper]{
+		old: schema.ForeignDataWrapper{
+			Name:      "my_fdw",
+			Handler:   handler,
+			Validator: validator,
+			Options:   map[string]string{"debug": "true", "batch_size": "100"},
+		},
+		new: schema.ForeignDataWrapper{
+			Name:    "my_fdw",
+			Handler: handler,
+			Options: map[string]string{"batch_size": "200", "fetch_size": "50"},
+		},
+	}})
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "ALTER FOREIGN DATA WRAPPER \"my_fdw\" NO VALIDATOR OPTIONS (SET batch_size '200', ADD fetch_size '50', DROP debug)", stmts[0].DDL)
+}
+
+func TestForeignServerSQLGenerator_Alter(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		old             schema.ForeignServer
+		new             schema.ForeignServer
+		expectedDDL     []string
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name: "no change",
+			old:  schema.ForeignServer{Name: "remote", ForeignDataWrapper: "postgres_fdw", Options: map[string]string{"host": "localhost"}},
+			new:  schema.ForeignServer{Name: "remote", ForeignDataWrapper: "postgres_fdw", Options: map[string]string{"host": "localhost"}},
+		},
+		{
+			name: "change version and options",
+			old: schema.ForeignServer{
+				Name:               "remote",
+				ForeignDataWrapper: "postgres_fdw",
+				Version:            "14",
+				Options:            map[string]string{"host": "localhost", "port": "5432"},
+			},
+			new: schema.ForeignServer{
+				Name:               "remote",
+				ForeignDataWrapper: "postgres_fdw",
+				Version:            "15",
+				Options:            map[string]string{"host": "db.example.com", "dbname": "o'brien"},
+			},
+			expectedDDL: []string{
+				"ALTER SERVER \"remote\" VERSION '15' OPTIONS (ADD dbname 'o''brien', SET host 'db.example.com', DROP port)",
+			},
+		},
+		{
+			name: "change foreign data wrapper",
+			old:  schema.ForeignServer{Name: "remote", ForeignDataWrapper: "postgres_fdw"},
+			new:  schema.ForeignServer{Name: "remote", ForeignDataWrapper: "my_fdw", Type: "oracle"},
+			expectedDDL: []string{
+				"DROP SERVER \"remote\"",
+				"CREATE SERVER \"remote\" TYPE 'oracle' FOREIGN DATA WRAPPER \"my_fdw\"",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardForeignServerDeleted, migrationHazardForeignServerRecreated},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := (&foreignServerSQLGenerator{}).Alter(foreignServerDiff{oldAndNew: oldAndNew[schema.ForeignServer]{
+				old: tc.old,
+				new: tc.new,
+			}})
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+}
+
+func TestForeignTableSQLVertexGenerator(t *testing.T) {
+	generator := &foreignTableSQLVertexGenerator{}
+	old := schema.ForeignTable{
+		SchemaQualifiedName: foreignTableName,
+		Columns: []schema.Column{
+			{Name: "id", Type: "integer"},
+			{Name: "name", Type: "text", IsNullable: true},
+			{Name: "legacy", Type: "text", IsNullable: true},
+		},
+		Server:  "remote",
+		Options: map[string]string{"table_name": "users"},
+	}
+
+	stmts, err := generator.Add(old)
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "CREATE FOREIGN TABLE \"public\".\"remote_users\" (\n"+
+		"\t\"id\" integer NOT NULL,\n"+
+		"\t\"name\" text,\n"+
+		"\t\"legacy\" text\n"+
+		") SERVER \"remote\" OPTIONS (table_name 'users')", stmts[0].DDL)
+
+	stmts, err = generator.Alter(foreignTableDiff{oldAndNew: oldAndNew[schema.ForeignTable]{
+		old: old,
+		new: schema.ForeignTable{
+			SchemaQualifiedName: foreignTableName,
+			Columns: []schema.Column{
+				{Name: "id", Type: "bigint"},
+				{Name: "name", Type: "text", Default: "'unknown'::text"},
+				{Name: "email", Type: "text", IsNullable: true},
+			},
+			Server:  "remote",
+			Options: map[string]string{"table_name": "users_v2"},
+		},
+	}})
+	require.NoError(t, err)
+	var ddl []string
+	for _, stmt := range stmts {
+		ddl = append(ddl, stmt.DDL)
+		assert.Empty(t, stmt.Hazards)
+	}
+	assert.Equal(t, []string{
+		"ALTER FOREIGN TABLE \"public\".\"remote_users\" DROP COLUMN \"legacy\"",
+		"ALTER FOREIGN TABLE \"public\".\"remote_users\" ALTER COLUMN \"id\" TYPE bigint",
+		"ALTER FOREIGN TABLE \"public\".\"remote_users\" ALTER COLUMN \"name\" SET NOT NULL",
+		"ALTER FOREIGN TABLE \"public\".\"remote_users\" ALTER COLUMN \"name\" SET DEFAULT 'unknown'::text",
+		"ALTER FOREIGN TABLE \"public\".\"remote_users\" ADD COLUMN \"email\" text",
+		"ALTER FOREIGN TABLE \"public\".\"remote_users\" OPTIONS (SET table_name 'users_v2')",
+	}, ddl)
+}
+
+func TestBuildForeignTableDiff_RecreatesOnServerChange(t *testing.T) {
+	_, requiresRecreation, err := buildForeignTableDiff(
+		schema.ForeignTable{SchemaQualifiedName: foreignTableName, Server: "remote"},
+		schema.ForeignTable{SchemaQualifiedName: foreignTableName, Server: "other_remote"},
+		0, 0,
+	)
+	require.NoError(t, err)
+	assert.True(t, requiresRecreation)
+}
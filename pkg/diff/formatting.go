@@ -0,0 +1,216 @@
+package diff
+
+import (
+	"strings"
+
+	pg_query "github.com/pganalyze/pg_query_go/v5"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+// ignoreFormattingDifferences returns a copy of the old schema where the definitions of functions, procedures, views,
+// and materialized views are replaced with the definitions from the new schema if they only differ in formatting, i.e., comments,
+// whitespace, and keyword casing. The bodies of functions and procedures are only normalized if they are written in SQL
+// or PL/pgSQL; bodies of other languages, e.g., PL/Python, are compared exactly. In effect, it tells the SQL generator to ignore formatting-only changes, while still
+// using the new schema's definitions for any statements it generates.
+//
+// Note: We need to copy all arrays we modify because otherwise those arrays (effectively pointers) will still exist
+// in the original structs, leading to mutation.
+func ignoreFormattingDifferences(oldSchema, newSchema schema.Schema) schema.Schema {
+	newFunctionsByName := buildSchemaObjByNameMap(newSchema.Functions)
+	copiedFunctions := append([]schema.Function(nil), oldSchema.Functions...)
+	for i, function := range copiedFunctions {
+		if newFunction, ok := newFunctionsByName[function.GetName()]; ok && isFormattingEquivalent(function.Language, function.FunctionDef, newFunction.FunctionDef) {
+			copiedFunctions[i].FunctionDef = newFunction.FunctionDef
+		}
+	}
+	oldSchema.Functions = copiedFunctions
+
+	newProceduresByName := buildSchemaObjByNameMap(newSchema.Procedures)
+	copiedProcedures := append([]schema.Procedure(nil), oldSchema.Procedures...)
+	for i, procedure := range copiedProcedures {
+		if newProcedure, ok := newProceduresByName[procedure.GetName()]; ok && isFormattingEquivalent(procedure.Language, procedure.Def, newProcedure.Def) {
+			copiedProcedures[i].Def = newProcedure.Def
+		}
+	}
+	oldSchema.Procedures = copiedProcedures
+
+	newViewsByName := buildSchemaObjByNameMap(newSchema.Views)
+	copiedViews := append([]schema.View(nil), oldSchema.Views...)
+	for i, view := range copiedViews {
+		if newView, ok := newViewsByName[view.GetName()]; ok && isFormattingEquivalent("", view.Definition, newView.Definition) {
+			copiedViews[i].Definition = newView.Definition
+		}
+	}
+	oldSchema.Views = copiedViews
+
+	newMaterializedViewsByName := buildSchemaObjByNameMap(newSchema.MaterializedViews)
+	copiedMaterializedViews := append([]schema.MaterializedView(nil), oldSchema.MaterializedViews...)
+	for i, mv := range copiedMaterializedViews {
+		if newMv, ok := newMaterializedViewsByName[mv.GetName()]; ok && isFormattingEquivalent("", mv.Definition, newMv.Definition) {
+			copiedMaterializedViews[i].Definition = newMv.Definition
+		}
+	}
+	oldSchema.MaterializedViews = copiedMaterializedViews
+
+	return oldSchema
+}
+
+// isFormattingEquivalent returns true if the definitions only differ in formatting. bodyLanguage is the language of the
+// dollar-quoted body of the definitions, e.g., "plpgsql" for a function; it is empty for definitions without a body.
+func isFormattingEquivalent(bodyLanguage, a, b string) bool {
+	return a == b || normalizeFormatting(bodyLanguage, a) == normalizeFormatting(bodyLanguage, b)
+}
+
+// normalizeFormatting strips comments, collapses whitespace, and lowercases keywords outside of string literals and
+// quoted identifiers. If the body is written in SQL or PL/pgSQL, the dollar-quoted body is normalized as well; otherwise,
+// dollar-quoted strings are left untouched, e.g., whitespace and comments are significant in PL/Python. If the result
+// can be parsed, it is deparsed by pg_query to get a canonical representation.
+func normalizeFormatting(bodyLanguage, sql string) string {
+	switch strings.ToLower(bodyLanguage) {
+	case "sql", "plpgsql":
+	default:
+		bodyLanguage = ""
+	}
+	normalized := normalizeFormattingText(sql, len(bodyLanguage) > 0)
+	tree, err := pg_query.Parse(normalized)
+	if err != nil {
+		return normalized
+	}
+	deparsed, err := pg_query.Deparse(tree)
+	if err != nil {
+		return normalized
+	}
+	return deparsed
+}
+
+// normalizeFormattingText normalizes the formatting of the sql. If normalizeBody is true, the dollar-quoted strings are
+// normalized as SQL, i.e., they are function bodies; the dollar-quoted strings within them are left untouched.
+func normalizeFormattingText(sql string, normalizeBody bool) string {
+	sb := strings.Builder{}
+	// pendingSpace is used to collapse all whitespace and comments into a single space
+	pendingSpace := false
+	write := func(s string) {
+		if pendingSpace && sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		pendingSpace = false
+		sb.WriteString(s)
+	}
+
+	for i := 0; i < len(sql); {
+		switch {
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end == -1 {
+				end = len(sql) - i
+			}
+			i += end
+			pendingSpace = true
+		case strings.HasPrefix(sql[i:], "/*"):
+			// Block comments can be nested
+			depth := 0
+			for i < len(sql) {
+				if strings.HasPrefix(sql[i:], "/*") {
+					depth++
+					i += 2
+				} else if strings.HasPrefix(sql[i:], "*/") {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					i++
+				}
+			}
+			pendingSpace = true
+		case sql[i] == '\'' || sql[i] == '"':
+			end := findClosingQuote(sql, i)
+			write(sql[i:end])
+			i = end
+		case sql[i] == '$' && dollarQuoteTag(sql[i:]) != "":
+			tag := dollarQuoteTag(sql[i:])
+			bodyStart := i + len(tag)
+			bodyEnd := strings.Index(sql[bodyStart:], tag)
+			if bodyEnd == -1 {
+				write(sql[i:])
+				i = len(sql)
+				continue
+			}
+			bodyEnd += bodyStart
+			if normalizeBody {
+				write(tag + normalizeFormattingText(sql[bodyStart:bodyEnd], false) + tag)
+			} else {
+				write(sql[i : bodyEnd+len(tag)])
+			}
+			i = bodyEnd + len(tag)
+		case strings.IndexByte(" \t\n\r\f\v", sql[i]) != -1:
+			pendingSpace = true
+			i++
+		case isIdentifierStart(sql[i]):
+			end := i + 1
+			for end < len(sql) && isIdentifierPart(sql[end]) {
+				end++
+			}
+			word := sql[i:end]
+			if isKeyword(word) {
+				// Keywords are case-insensitive. Identifiers are left untouched
+				word = strings.ToLower(word)
+			}
+			write(word)
+			i = end
+		default:
+			write(sql[i : i+1])
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// isKeyword returns true if the word is a SQL keyword, e.g., SELECT
+func isKeyword(word string) bool {
+	scanResult, err := pg_query.Scan(word)
+	if err != nil || len(scanResult.Tokens) != 1 {
+		return false
+	}
+	return scanResult.Tokens[0].KeywordKind != pg_query.KeywordKind_NO_KEYWORD
+}
+
+// findClosingQuote returns the index after the closing quote of the quoted string starting at start. Quotes are
+// escaped by doubling them. In escape string constants, e.g., E'it\'s', they can also be escaped with a backslash.
+func findClosingQuote(sql string, start int) int {
+	quote := sql[start]
+	isEscapeString := quote == '\'' && start > 0 && (sql[start-1] == 'E' || sql[start-1] == 'e') &&
+		(start == 1 || !isIdentifierPart(sql[start-2]))
+	for i := start + 1; i < len(sql); i++ {
+		if isEscapeString && sql[i] == '\\' {
+			i++
+			continue
+		}
+		if sql[i] != quote {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(sql)
+}
+
+// dollarQuoteTag returns the dollar quote tag, e.g., $$ or $function$, at the start of the sql. If the sql does not
+// start with a dollar quote tag, e.g., it is a positional parameter like $1, it returns an empty string.
+func dollarQuoteTag(sql string) string {
+	for i := 1; i < len(sql); i++ {
+		c := sql[i]
+		if c == '$' {
+			return sql[:i+1]
+		}
+		isIdentifierChar := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 1 && c >= '0' && c <= '9')
+		if !isIdentifierChar {
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestIsFormattingEquivalent(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		bodyLanguage string
+		a            string
+		b            string
+		expected     bool
+	}{
+		{
+			name:     "Whitespace and keyword casing",
+			a:        "SELECT a,\n    b\n   FROM foobar",
+			b:        "select a, b from   foobar",
+			expected: true,
+		},
+		{
+			name:     "Comments",
+			a:        "SELECT a -- the a column\nFROM /* a /* nested */ comment */ foobar",
+			b:        "SELECT a FROM foobar",
+			expected: true,
+		},
+		{
+			name:         "Function bodies",
+			bodyLanguage: "sql",
+			a: `CREATE OR REPLACE FUNCTION public.add(a integer, b integer)
+ RETURNS integer
+ LANGUAGE sql
+AS $function$
+    -- Add the numbers
+    SELECT a + b
+$function$
+`,
+			b:        `CREATE OR REPLACE FUNCTION public.add(a integer, b integer) RETURNS integer LANGUAGE sql AS $function$ select a + b $function$`,
+			expected: true,
+		},
+		{
+			name:         "PL/pgSQL bodies keep the casing of identifiers",
+			bodyLanguage: "plpgsql",
+			a:            "CREATE FUNCTION public.f() RETURNS integer LANGUAGE plpgsql AS $$ BEGIN RETURN myVar; END; $$",
+			b:            "CREATE FUNCTION public.f() RETURNS integer LANGUAGE plpgsql AS $$\n  begin\n    return myvar;\n  end;\n$$",
+			expected:     false,
+		},
+		{
+			name:         "PL/pgSQL bodies only differing in keyword casing",
+			bodyLanguage: "plpgsql",
+			a:            "CREATE FUNCTION public.f() RETURNS integer LANGUAGE plpgsql AS $$ BEGIN RETURN myVar; END; $$",
+			b:            "CREATE FUNCTION public.f() RETURNS integer LANGUAGE plpgsql AS $$\n  begin\n    -- Return the var\n    return myVar;\n  end;\n$$",
+			expected:     true,
+		},
+		{
+			name:         "Dollar-quoted strings within bodies are compared exactly",
+			bodyLanguage: "plpgsql",
+			a:            "CREATE FUNCTION public.f() RETURNS void LANGUAGE plpgsql AS $$ BEGIN EXECUTE $q$SELECT  1$q$; END; $$",
+			b:            "CREATE FUNCTION public.f() RETURNS void LANGUAGE plpgsql AS $$ BEGIN EXECUTE $q$SELECT 1$q$; END; $$",
+			expected:     false,
+		},
+		{
+			name:         "Bodies of other languages are compared exactly",
+			bodyLanguage: "plpython3u",
+			a:            "CREATE FUNCTION public.f() RETURNS integer LANGUAGE plpython3u AS $$\nif True:\n    return 1\nreturn 2\n$$",
+			b:            "CREATE FUNCTION public.f() RETURNS integer LANGUAGE plpython3u AS $$\nif True:\n    return 1\n    return 2\n$$",
+			expected:     false,
+		},
+		{
+			name:         "Bodies of other languages keep their casing",
+			bodyLanguage: "plv8",
+			a:            "CREATE FUNCTION public.f() RETURNS integer LANGUAGE plv8 AS $$ return myVar; $$",
+			b:            "CREATE FUNCTION public.f() RETURNS integer LANGUAGE plv8 AS $$ return myvar; $$",
+			expected:     false,
+		},
+		{
+			name:     "Escape string constants",
+			a:        `SELECT E'it\'s -- not a comment' AS "A"`,
+			b:        `SELECT E'it\'s -- still not a comment' AS "A"`,
+			expected: false,
+		},
+		{
+			name:     "Escape string constants are compared exactly",
+			a:        `SELECT E'it\'s  here' FROM foobar`,
+			b:        `SELECT E'it\'s here' FROM foobar`,
+			expected: false,
+		},
+		{
+			name:     "String literals are compared exactly",
+			a:        "SELECT 'Hello  world' FROM foobar",
+			b:        "SELECT 'hello world' FROM foobar",
+			expected: false,
+		},
+		{
+			name:     "Quoted identifiers are compared exactly",
+			a:        `SELECT "Foo" FROM foobar`,
+			b:        `SELECT "foo" FROM foobar`,
+			expected: false,
+		},
+		{
+			name:     "Different columns",
+			a:        "SELECT a FROM foobar",
+			b:        "SELECT b FROM foobar",
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isFormattingEquivalent(tc.bodyLanguage, tc.a, tc.b))
+		})
+	}
+}
+
+func TestIgnoreFormattingDifferences(t *testing.T) {
+	oldFunction := schema.Function{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"add"(a integer, b integer)`},
+		FunctionDef:         "CREATE OR REPLACE FUNCTION public.add(a integer, b integer) RETURNS integer LANGUAGE sql AS $$ SELECT a + b $$",
+		Language:            "sql",
+	}
+	newFunction := oldFunction
+	newFunction.FunctionDef = "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $$\n    select a + b -- Add the numbers\n$$"
+	changedFunction := oldFunction
+	changedFunction.FunctionDef = "CREATE OR REPLACE FUNCTION public.add(a integer, b integer) RETURNS integer LANGUAGE sql AS $$ SELECT a - b $$"
+
+	oldSchema := schema.Schema{Functions: []schema.Function{oldFunction}}
+
+	normalizedSchema := ignoreFormattingDifferences(oldSchema, schema.Schema{Functions: []schema.Function{newFunction}})
+	assert.Equal(t, []schema.Function{newFunction}, normalizedSchema.Functions)
+	// The original schema should not be mutated
+	assert.Equal(t, []schema.Function{oldFunction}, oldSchema.Functions)
+
+	normalizedSchema = ignoreFormattingDifferences(oldSchema, schema.Schema{Functions: []schema.Function{changedFunction}})
+	assert.Equal(t, []schema.Function{oldFunction}, normalizedSchema.Functions)
+}
+
+func (suite *planGeneratorTestSuite) TestNormalizeViewDefinitions_EmptyDiff() {
+	suite.mustApplyDDLToTestDb([]string{`
+		CREATE TABLE foobar(id INT PRIMARY KEY, a TEXT, b TEXT);
+		CREATE VIEW foobar_view AS SELECT a, b FROM foobar;
+	`})
+	db := suite.mustGetTestDBPool()
+	defer db.Close()
+	currentSchema, err := schema.GetSchema(context.Background(), db)
+	suite.Require().NoError(err)
+
+	// pg_get_viewdef qualifies the columns, so the user-written definition differs from the fetched one
+	userSchema := currentSchema.DeepCopy()
+	userSchema.Views[0].Definition = "SELECT a, b FROM foobar"
+	stmts, err := generateMigrationStatements(currentSchema, userSchema, &planOptions{})
+	suite.Require().NoError(err)
+	suite.NotEmpty(stmts)
+
+	normalizedSchema, err := userSchema.NormalizeViewDefinitions(context.Background(), db)
+	suite.Require().NoError(err)
+	stmts, err = generateMigrationStatements(currentSchema, normalizedSchema, &planOptions{})
+	suite.Require().NoError(err)
+	suite.Empty(stmts)
+}
@@ -0,0 +1,180 @@
+package diff
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	pg_query "github.com/pganalyze/pg_query_go/v5"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	// functionBodyStartRegex matches the start of a dollar-quoted function body, e.g., `AS $function$`. The tag is
+	// captured.
+	functionBodyStartRegex = regexp.MustCompile(`(?i)\bAS\s+(\$(?:[a-z_][a-z_0-9]*)?\$)`)
+
+	functionBodyIndentationRegex        = regexp.MustCompile(`(?m)^[ \t]+`)
+	functionBodyTrailingWhitespaceRegex = regexp.MustCompile(`(?m)[ \t\r]+$`)
+	functionBodyCommentLineRegex        = regexp.MustCompile(`(?m)^--.*$`)
+	functionBodyBlankLinesRegex         = regexp.MustCompile(`\n{2,}`)
+	// functionBodyLiteralPlaceholderRegex matches the placeholders that replace the literals of a PL/pgSQL body while
+	// it is normalized. The index of the literal is captured.
+	functionBodyLiteralPlaceholderRegex = regexp.MustCompile("\x00([0-9]+)\x00")
+)
+
+// FunctionNormalizer canonicalizes the bodies of functions and procedures, such that definitions whose bodies only
+// differ in formatting are equal:
+//   - SQL bodies are parsed and deparsed by pg_query, which removes comments and canonicalizes whitespace and keyword
+//     casing. If a body cannot be parsed, it is normalized like a PL/pgSQL body.
+//   - PL/pgSQL bodies have their indentation, trailing whitespace, blank lines, and comment-only lines removed
+//   - Bodies of other languages, e.g., PL/Python, where indentation can be significant, only have their leading and
+//     trailing whitespace, as well as the trailing whitespace of each line, removed
+//
+// Only the body is normalized: the rest of the definition, as returned by pg_get_functiondef, is already canonical.
+// The string literals, quoted identifiers, and dollar-quoted strings of PL/pgSQL bodies are left untouched, e.g.,
+// changes to the indentation of a multi-line string literal are not ignored.
+type FunctionNormalizer struct{}
+
+// NormalizeFunctionDef returns the definition of a function or procedure written in the language with its body
+// normalized. Definitions without a dollar-quoted body, e.g., C functions, are returned unchanged.
+func (n FunctionNormalizer) NormalizeFunctionDef(language, def string) string {
+	match := functionBodyStartRegex.FindStringSubmatchIndex(def)
+	if match == nil {
+		return def
+	}
+	tag := def[match[2]:match[3]]
+	bodyStart := match[1]
+	bodyLen := strings.Index(def[bodyStart:], tag)
+	if bodyLen == -1 {
+		return def
+	}
+	bodyEnd := bodyStart + bodyLen
+	return def[:bodyStart] + n.NormalizeBody(language, def[bodyStart:bodyEnd]) + def[bodyEnd:]
+}
+
+// NormalizeBody returns the normalized body of a function or procedure written in the language
+func (n FunctionNormalizer) NormalizeBody(language, body string) string {
+	switch strings.ToLower(language) {
+	case "sql":
+		if normalized, ok := normalizeSQLFunctionBody(body); ok {
+			return normalized
+		}
+		return normalizePLpgSQLFunctionBody(body)
+	case "plpgsql":
+		return normalizePLpgSQLFunctionBody(body)
+	default:
+		return strings.TrimSpace(functionBodyTrailingWhitespaceRegex.ReplaceAllString(body, ""))
+	}
+}
+
+// IsEquivalent returns true if the definitions are equal after their bodies are normalized
+func (n FunctionNormalizer) IsEquivalent(languageA, defA, languageB, defB string) bool {
+	return defA == defB || n.NormalizeFunctionDef(languageA, defA) == n.NormalizeFunctionDef(languageB, defB)
+}
+
+func normalizeSQLFunctionBody(body string) (string, bool) {
+	tree, err := pg_query.Parse(body)
+	if err != nil {
+		return "", false
+	}
+	deparsed, err := pg_query.Deparse(tree)
+	if err != nil {
+		return "", false
+	}
+	return deparsed, true
+}
+
+func normalizePLpgSQLFunctionBody(body string) string {
+	body, literals := maskFunctionBodyLiterals(body)
+	body = functionBodyIndentationRegex.ReplaceAllString(body, "")
+	body = functionBodyTrailingWhitespaceRegex.ReplaceAllString(body, "")
+	body = functionBodyCommentLineRegex.ReplaceAllString(body, "")
+	body = functionBodyBlankLinesRegex.ReplaceAllString(body, "\n")
+	body = strings.TrimSpace(body)
+	return functionBodyLiteralPlaceholderRegex.ReplaceAllStringFunc(body, func(placeholder string) string {
+		idx, _ := strconv.Atoi(strings.Trim(placeholder, "\x00"))
+		return literals[idx]
+	})
+}
+
+// maskFunctionBodyLiterals replaces the string literals, quoted identifiers, and dollar-quoted strings of the body with
+// placeholders that do not contain whitespace, such that they are not changed by normalization. It returns the masked
+// body and the literals, indexed by the number in their placeholder. Comments are skipped, such that quotes in
+// comments do not start a literal.
+func maskFunctionBodyLiterals(body string) (string, []string) {
+	var literals []string
+	sb := strings.Builder{}
+	mask := func(literal string) {
+		sb.WriteString("\x00" + strconv.Itoa(len(literals)) + "\x00")
+		literals = append(literals, literal)
+	}
+	for i := 0; i < len(body); {
+		switch {
+		case strings.HasPrefix(body[i:], "--"):
+			end := strings.IndexByte(body[i:], '\n')
+			if end == -1 {
+				end = len(body) - i
+			}
+			sb.WriteString(body[i : i+end])
+			i += end
+		case strings.HasPrefix(body[i:], "/*"):
+			end := strings.Index(body[i+2:], "*/")
+			if end == -1 {
+				end = len(body) - i
+			} else {
+				end += 4
+			}
+			sb.WriteString(body[i : i+end])
+			i += end
+		case body[i] == '\'' || body[i] == '"':
+			end := findClosingQuote(body, i)
+			mask(body[i:end])
+			i = end
+		case body[i] == '$' && dollarQuoteTag(body[i:]) != "":
+			tag := dollarQuoteTag(body[i:])
+			end := strings.Index(body[i+len(tag):], tag)
+			if end == -1 {
+				end = len(body) - i
+			} else {
+				end += 2 * len(tag)
+			}
+			mask(body[i : i+end])
+			i += end
+		default:
+			sb.WriteByte(body[i])
+			i++
+		}
+	}
+	return sb.String(), literals
+}
+
+// normalizeFunctionBodies returns a copy of the old schema where the definitions of functions and procedures are
+// replaced with the definitions from the new schema if their bodies only differ in formatting. See FunctionNormalizer.
+//
+// Note: Like ignoreFormattingDifferences, we need to copy all arrays we modify to avoid mutating the original structs.
+func normalizeFunctionBodies(oldSchema, newSchema schema.Schema) schema.Schema {
+	normalizer := FunctionNormalizer{}
+
+	newFunctionsByName := buildSchemaObjByNameMap(newSchema.Functions)
+	copiedFunctions := append([]schema.Function(nil), oldSchema.Functions...)
+	for i, function := range copiedFunctions {
+		if newFunction, ok := newFunctionsByName[function.GetName()]; ok &&
+			normalizer.IsEquivalent(function.Language, function.FunctionDef, newFunction.Language, newFunction.FunctionDef) {
+			copiedFunctions[i].FunctionDef = newFunction.FunctionDef
+		}
+	}
+	oldSchema.Functions = copiedFunctions
+
+	newProceduresByName := buildSchemaObjByNameMap(newSchema.Procedures)
+	copiedProcedures := append([]schema.Procedure(nil), oldSchema.Procedures...)
+	for i, procedure := range copiedProcedures {
+		if newProcedure, ok := newProceduresByName[procedure.GetName()]; ok &&
+			normalizer.IsEquivalent(procedure.Language, procedure.Def, newProcedure.Language, newProcedure.Def) {
+			copiedProcedures[i].Def = newProcedure.Def
+		}
+	}
+	oldSchema.Procedures = copiedProcedures
+
+	return oldSchema
+}
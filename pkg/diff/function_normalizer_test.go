@@ -0,0 +1,137 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestFunctionNormalizer_IsEquivalent(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		language string
+		a        string
+		b        string
+		expected bool
+	}{
+		{
+			name:     "SQL indentation, casing, and comments",
+			language: "sql",
+			a:        "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$\n    -- Add the numbers\n    SELECT a +\n        b\n$function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$select a + b$function$\n",
+			expected: true,
+		},
+		{
+			name:     "SQL body changes",
+			language: "sql",
+			a:        "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT a + b $function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT a - b $function$\n",
+			expected: false,
+		},
+		{
+			name:     "SQL string literals are compared exactly",
+			language: "sql",
+			a:        "CREATE OR REPLACE FUNCTION public.greet()\n RETURNS text\n LANGUAGE sql\nAS $function$ SELECT 'Hello  world' $function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.greet()\n RETURNS text\n LANGUAGE sql\nAS $function$ SELECT 'Hello world' $function$\n",
+			expected: false,
+		},
+		{
+			name:     "PL/pgSQL indentation, blank lines, and comment lines",
+			language: "plpgsql",
+			a:        "CREATE OR REPLACE FUNCTION public.incr(a integer)\n RETURNS integer\n LANGUAGE plpgsql\nAS $function$\nBEGIN\n    -- Increment the number\n    RETURN a + 1;\n\nEND;\n$function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.incr(a integer)\n RETURNS integer\n LANGUAGE plpgsql\nAS $function$\n  BEGIN\n\tRETURN a + 1;  \n  END;\n$function$\n",
+			expected: true,
+		},
+		{
+			name:     "PL/pgSQL indentation of multi-line string literals",
+			language: "plpgsql",
+			a:        "CREATE OR REPLACE FUNCTION public.greet()\n RETURNS text\n LANGUAGE plpgsql\nAS $function$\nBEGIN\n    RETURN 'Hello\n    world';\nEND;\n$function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.greet()\n RETURNS text\n LANGUAGE plpgsql\nAS $function$\nBEGIN\n    RETURN 'Hello\nworld';\nEND;\n$function$\n",
+			expected: false,
+		},
+		{
+			name:     "PL/pgSQL comment lines in quoted and dollar-quoted strings",
+			language: "plpgsql",
+			a:        "CREATE OR REPLACE FUNCTION public.run()\n RETURNS void\n LANGUAGE plpgsql\nAS $function$\nBEGIN\n    EXECUTE $sql$\n-- Some comment\nSELECT 1$sql$;\n    PERFORM \"some\n-- column\";\nEND;\n$function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.run()\n RETURNS void\n LANGUAGE plpgsql\nAS $function$\nBEGIN\n    EXECUTE $sql$\nSELECT 1$sql$;\n    PERFORM \"some\n-- column\";\nEND;\n$function$\n",
+			expected: false,
+		},
+		{
+			name:     "PL/pgSQL quotes in comments",
+			language: "plpgsql",
+			a:        "CREATE OR REPLACE FUNCTION public.greet()\n RETURNS text\n LANGUAGE plpgsql\nAS $function$\nBEGIN\n    -- Don't change the greeting\n    RETURN 'Hello ''world''';\n    /* It's also */\nEND;\n$function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.greet()\n RETURNS text\n LANGUAGE plpgsql\nAS $function$\nBEGIN\nRETURN 'Hello ''world''';\n/* It's also */\nEND;\n$function$\n",
+			expected: true,
+		},
+		{
+			name:     "PL/pgSQL body changes",
+			language: "plpgsql",
+			a:        "CREATE OR REPLACE FUNCTION public.incr(a integer)\n RETURNS integer\n LANGUAGE plpgsql\nAS $function$\nBEGIN\n    RETURN a + 1;\nEND;\n$function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.incr(a integer)\n RETURNS integer\n LANGUAGE plpgsql\nAS $function$\nBEGIN\n    RETURN a + 2;\nEND;\n$function$\n",
+			expected: false,
+		},
+		{
+			name:     "Indentation is significant in other languages",
+			language: "plpython3u",
+			a:        "CREATE OR REPLACE FUNCTION public.pymax(a integer, b integer)\n RETURNS integer\n LANGUAGE plpython3u\nAS $function$\nif a > b:\n    return a\nreturn b\n$function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.pymax(a integer, b integer)\n RETURNS integer\n LANGUAGE plpython3u\nAS $function$\nif a > b:\n    return a\n    return b\n$function$\n",
+			expected: false,
+		},
+		{
+			name:     "Leading and trailing whitespace in other languages",
+			language: "plpython3u",
+			a:        "CREATE OR REPLACE FUNCTION public.pymax(a integer, b integer)\n RETURNS integer\n LANGUAGE plpython3u\nAS $function$\nif a > b:\n    return a\nreturn b\n$function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.pymax(a integer, b integer)\n RETURNS integer\n LANGUAGE plpython3u\nAS $function$\n\nif a > b:   \n    return a\nreturn b\n\n$function$\n",
+			expected: true,
+		},
+		{
+			name:     "Headers are compared exactly",
+			language: "sql",
+			a:        "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT a + b $function$\n",
+			b:        "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\n IMMUTABLE\nAS $function$ SELECT a + b $function$\n",
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FunctionNormalizer{}.IsEquivalent(tc.language, tc.a, tc.language, tc.b))
+		})
+	}
+}
+
+func TestFunctionNormalizer_NormalizeFunctionDef(t *testing.T) {
+	normalizer := FunctionNormalizer{}
+	// Definitions without a dollar-quoted body are returned unchanged
+	cDef := "CREATE OR REPLACE FUNCTION public.foo()\n RETURNS integer\n LANGUAGE c\nAS '$libdir/foo', $function$foo$function$\n"
+	assert.Equal(t, cDef, normalizer.NormalizeFunctionDef("c", cDef))
+	sqlStandardDef := "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nRETURN (a + b)\n"
+	assert.Equal(t, sqlStandardDef, normalizer.NormalizeFunctionDef("sql", sqlStandardDef))
+
+	assert.Equal(t,
+		"CREATE OR REPLACE FUNCTION public.incr(a integer)\n RETURNS integer\n LANGUAGE plpgsql\nAS $$BEGIN\nRETURN a + 1;\nEND;$$",
+		normalizer.NormalizeFunctionDef("plpgsql", "CREATE OR REPLACE FUNCTION public.incr(a integer)\n RETURNS integer\n LANGUAGE plpgsql\nAS $$\n    BEGIN\n        RETURN a + 1;\n    END;\n$$"),
+	)
+}
+
+func TestGenerateMigrationStatements_NormalizeFunctionBodies(t *testing.T) {
+	oldFunction := schema.Function{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"incr"(a integer)`},
+		FunctionDef:         "CREATE OR REPLACE FUNCTION public.incr(a integer)\n RETURNS integer\n LANGUAGE plpgsql\nAS $function$\nBEGIN\n    RETURN a + 1;\nEND;\n$function$\n",
+		Language:            "plpgsql",
+	}
+	newFunction := oldFunction
+	newFunction.FunctionDef = "CREATE OR REPLACE FUNCTION public.incr(a integer)\n RETURNS integer\n LANGUAGE plpgsql\nAS $function$\n        BEGIN\n            -- Increment the number\n            RETURN a + 1;\n        END;\n$function$\n"
+	oldSchema := schema.Schema{Functions: []schema.Function{oldFunction}}
+	newSchema := schema.Schema{Functions: []schema.Function{newFunction}}
+
+	stmts, err := generateMigrationStatements(oldSchema, newSchema, &planOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, stmts)
+	// The original schema should not be mutated
+	assert.Equal(t, []schema.Function{oldFunction}, oldSchema.Functions)
+
+	stmts, err = generateMigrationStatements(oldSchema, newSchema, &planOptions{doNotNormalizeFunctionBodies: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{newFunction.FunctionDef}, getDDL(Plan{Statements: stmts}))
+}
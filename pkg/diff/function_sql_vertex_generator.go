@@ -2,11 +2,20 @@ package diff
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stripe/pg-schema-diff/internal/schema"
 )
 
+var (
+	// functionDefLanguageRegex matches the LANGUAGE line of a def returned by `pg_get_functiondef`
+	functionDefLanguageRegex = regexp.MustCompile(`(?m)^ LANGUAGE \S+$`)
+	// functionDefWindowRegex matches the WINDOW line of a def returned by `pg_get_functiondef`
+	functionDefWindowRegex = regexp.MustCompile(`(?m)^ WINDOW$`)
+)
+
 type functionSQLVertexGenerator struct {
 	// functionsInNewSchemaByName is a map of function name to functions in the new schema.
 	// These functions are not necessarily new
@@ -15,12 +24,27 @@ type functionSQLVertexGenerator struct {
 	// Track table alterations happening in this migration so we can ensure
 	// functions run after columns they depend on are added
 	tableDiffs []tableDiff
+
+	// deletedFunctionsByInputSignature is a map of the input signature to the functions being deleted. A function's
+	// escaped name includes its OUT parameters, but Postgres only uses its input parameters to identify it. Thus,
+	// changing the OUT parameters of a function results in a delete and an add of functions that conflict.
+	deletedFunctionsByInputSignature map[string][]schema.Function
+
+	// existingRoles is the set of roles known to exist in the target database. See buildExistingRoles.
+	existingRoles map[string]bool
 }
 
-func newFunctionSqlVertexGenerator(functionsInNewSchemaByName map[string]schema.Function, tableDiffs []tableDiff) sqlVertexGenerator[schema.Function, functionDiff] {
+func newFunctionSqlVertexGenerator(functionsInNewSchemaByName map[string]schema.Function, tableDiffs []tableDiff, deletedFunctions []schema.Function, existingRoles map[string]bool) sqlVertexGenerator[schema.Function, functionDiff] {
+	deletedFunctionsByInputSignature := make(map[string][]schema.Function)
+	for _, f := range deletedFunctions {
+		signature := buildFunctionInputSignature(f.SchemaQualifiedName)
+		deletedFunctionsByInputSignature[signature] = append(deletedFunctionsByInputSignature[signature], f)
+	}
 	return legacyToNewSqlVertexGenerator[schema.Function, functionDiff](&functionSQLVertexGenerator{
-		functionsInNewSchemaByName: functionsInNewSchemaByName,
-		tableDiffs: tableDiffs,
+		functionsInNewSchemaByName:       functionsInNewSchemaByName,
+		tableDiffs:                       tableDiffs,
+		deletedFunctionsByInputSignature: deletedFunctionsByInputSignature,
+		existingRoles:                    existingRoles,
 	})
 }
 
@@ -35,12 +59,29 @@ func (f *functionSQLVertexGenerator) Add(function schema.Function) ([]Statement,
 				"created/altered before this statement.",
 		})
 	}
-	return []Statement{{
-		DDL:         function.FunctionDef,
+	stmts := []Statement{{
+		DDL:         buildFunctionDef(function),
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
 		Hazards:     hazards,
-	}}, nil
+	}}
+	stmts = append(stmts, buildCommentStatements("FUNCTION", function.GetFQEscapedName(), nil, function.Comment)...)
+	return append(stmts, buildAlterOwnerStatements("FUNCTION", buildFunctionInputSignature(function.SchemaQualifiedName), "", function.Owner, f.existingRoles)...), nil
+}
+
+// buildFunctionDef builds the CREATE OR REPLACE statement for the function. The def returned by
+// `pg_get_functiondef` already includes the WINDOW attribute for window functions. If it is missing, it is
+// added after the LANGUAGE clause, which is where `pg_get_functiondef` places it.
+func buildFunctionDef(function schema.Function) string {
+	def := function.FunctionDef
+	if !function.IsWindow || functionDefWindowRegex.MatchString(def) {
+		return def
+	}
+	loc := functionDefLanguageRegex.FindStringIndex(def)
+	if loc == nil {
+		return def
+	}
+	return def[:loc[1]] + "\n WINDOW" + def[loc[1]:]
 }
 
 func (f *functionSQLVertexGenerator) Delete(function schema.Function) ([]Statement, error) {
@@ -68,7 +109,21 @@ func (f *functionSQLVertexGenerator) Alter(diff functionDiff) ([]Statement, erro
 	if cmp.Equal(diff.old, diff.new) {
 		return nil, nil
 	}
-	return f.Add(diff.new)
+	metadataStmts := buildCommentStatements("FUNCTION", diff.new.GetFQEscapedName(), diff.old.Comment, diff.new.Comment)
+	metadataStmts = append(metadataStmts, buildAlterOwnerStatements("FUNCTION", buildFunctionInputSignature(diff.new.SchemaQualifiedName), diff.old.Owner, diff.new.Owner, f.existingRoles)...)
+	// The comment and owner of a function can be changed without replacing it
+	oldWithNewMetadata := diff.old
+	oldWithNewMetadata.Comment = diff.new.Comment
+	oldWithNewMetadata.Owner = diff.new.Owner
+	if cmp.Equal(oldWithNewMetadata, diff.new) {
+		return metadataStmts, nil
+	}
+	// CREATE OR REPLACE preserves the comment and owner of the function, so they only need to be set if they changed
+	stmts, err := f.Add(diff.new)
+	if err != nil {
+		return nil, err
+	}
+	return append(stmts[:1], metadataStmts...), nil
 }
 
 func canFunctionDependenciesBeTracked(function schema.Function) bool {
@@ -83,15 +138,46 @@ func buildFunctionVertexId(name schema.SchemaQualifiedName, diffType diffType) s
 	return buildSchemaObjVertexId("function", name.GetFQEscapedName(), diffType)
 }
 
+// buildFunctionInputSignature builds the signature Postgres uses to identify a function, i.e., its name and input
+// parameters. The escaped name of a function, e.g., `"foo"(a integer, OUT b text)`, includes its OUT parameters, which
+// are stripped.
+func buildFunctionInputSignature(name schema.SchemaQualifiedName) string {
+	argsStart := strings.Index(name.EscapedName, "\"(")
+	if argsStart == -1 {
+		return name.GetFQEscapedName()
+	}
+	argsStart += len("\"")
+	args := strings.TrimSuffix(strings.TrimPrefix(name.EscapedName[argsStart:], "("), ")")
+
+	var inputArgs []string
+	for _, arg := range strings.Split(args, ", ") {
+		if strings.HasPrefix(arg, "OUT ") {
+			continue
+		}
+		inputArgs = append(inputArgs, arg)
+	}
+	return fmt.Sprintf("%s.%s(%s)", schema.EscapeIdentifier(name.SchemaName), name.EscapedName[:argsStart], strings.Join(inputArgs, ", "))
+}
+
 func (f *functionSQLVertexGenerator) GetAddAlterDependencies(newFunction, oldFunction schema.Function) ([]dependency, error) {
-	// Since functions can just be `CREATE OR REPLACE`, there will never be a case where a function is
-	// added and dropped in the same migration. Thus, we don't need a dependency on the delete vertex of a function
-	// because there won't be one if it is being added/altered
-	var deps []dependency
+	// Functions can usually just be `CREATE OR REPLACE`. The exception is changing whether the function is a window
+	// function, in which case the function is re-created and must be dropped before it is added again
+	deps := []dependency{
+		mustRun(f.GetSQLVertexId(newFunction, diffTypeAddAlter)).after(f.GetSQLVertexId(newFunction, diffTypeDelete)),
+	}
 	for _, depFunction := range newFunction.DependsOnFunctions {
 		deps = append(deps, mustRun(f.GetSQLVertexId(newFunction, diffTypeAddAlter)).after(buildFunctionVertexId(depFunction, diffTypeAddAlter)))
 	}
 
+	// If a function with the same input signature is being deleted, e.g., because the OUT parameters changed, it
+	// must be deleted before this function is created. Otherwise, the functions will conflict.
+	for _, deletedFunction := range f.deletedFunctionsByInputSignature[buildFunctionInputSignature(newFunction.SchemaQualifiedName)] {
+		if deletedFunction.GetName() == newFunction.GetName() {
+			continue
+		}
+		deps = append(deps, mustRun(f.GetSQLVertexId(newFunction, diffTypeAddAlter)).after(buildFunctionVertexId(deletedFunction.SchemaQualifiedName, diffTypeDelete)))
+	}
+
 	// A function depends on all tables it references
 	for _, depTable := range newFunction.DependsOnTables {
 		deps = append(deps, mustRun(f.GetSQLVertexId(newFunction, diffTypeAddAlter)).after(
@@ -0,0 +1,184 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestBuildFunctionInputSignature(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    schema.SchemaQualifiedName
+		expected string
+	}{
+		{
+			name:     "No arguments",
+			input:    schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"()`},
+			expected: `"public"."foo"()`,
+		},
+		{
+			name:     "Only input arguments",
+			input:    schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"(a integer, b text)`},
+			expected: `"public"."foo"(a integer, b text)`,
+		},
+		{
+			name:     "OUT arguments are stripped",
+			input:    schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"(a integer, OUT b text, OUT c integer)`},
+			expected: `"public"."foo"(a integer)`,
+		},
+		{
+			name:     "INOUT arguments are kept",
+			input:    schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"(INOUT a integer, OUT b text)`},
+			expected: `"public"."foo"(INOUT a integer)`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, buildFunctionInputSignature(tc.input))
+		})
+	}
+}
+
+func TestFunctionSQLVertexGenerator_OutParameters(t *testing.T) {
+	intOutFunction := schema.Function{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"get_value"(a integer, OUT b integer)`},
+		FunctionDef:         "CREATE OR REPLACE FUNCTION public.get_value(a integer, OUT b integer) LANGUAGE sql AS $$ SELECT a $$",
+		Language:            "sql",
+	}
+	textOutFunction := schema.Function{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"get_value"(a integer, OUT b text)`},
+		FunctionDef:         "CREATE OR REPLACE FUNCTION public.get_value(a integer, OUT b text) LANGUAGE sql AS $$ SELECT a::TEXT $$",
+		Language:            "sql",
+	}
+
+	// Functions with different OUT parameter types are distinct functions with distinct vertices
+	assert.NotEqual(t,
+		buildFunctionVertexId(intOutFunction.SchemaQualifiedName, diffTypeAddAlter),
+		buildFunctionVertexId(textOutFunction.SchemaQualifiedName, diffTypeAddAlter),
+	)
+
+	// Since they share input parameters, the old function must be dropped before the new function is created
+	gen := newFunctionSqlVertexGenerator(nil, nil, []schema.Function{intOutFunction}, nil)
+	partialGraph, err := gen.Add(textOutFunction)
+	require.NoError(t, err)
+	assert.Contains(t, partialGraph.dependencies,
+		mustRun(buildFunctionVertexId(textOutFunction.SchemaQualifiedName, diffTypeAddAlter)).after(buildFunctionVertexId(intOutFunction.SchemaQualifiedName, diffTypeDelete)),
+	)
+}
+
+func TestFunctionSQLVertexGenerator_CrossSchemaTableDependency(t *testing.T) {
+	transactionsTable := schema.SchemaQualifiedName{SchemaName: "accounting", EscapedName: `"transactions"`}
+	getSummaryFunction := schema.Function{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "reporting", EscapedName: `"get_summary"()`},
+		FunctionDef:         "CREATE OR REPLACE FUNCTION reporting.get_summary() RETURNS bigint LANGUAGE sql BEGIN ATOMIC SELECT sum(amount) FROM accounting.transactions; END",
+		Language:            "sql",
+		DependsOnTables:     []schema.SchemaQualifiedName{transactionsTable},
+	}
+
+	gen := newFunctionSqlVertexGenerator(nil, nil, nil, nil)
+	partialGraph, err := gen.Add(getSummaryFunction)
+	require.NoError(t, err)
+	assert.Contains(t, partialGraph.dependencies,
+		mustRun(buildFunctionVertexId(getSummaryFunction.SchemaQualifiedName, diffTypeAddAlter)).after(buildTableVertexId(transactionsTable, diffTypeAddAlter)),
+	)
+}
+
+func TestFunctionSQLVertexGenerator_WindowFunction(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		function schema.Function
+
+		expectedDDL string
+	}{
+		{
+			name: "WINDOW attribute is added after the LANGUAGE clause",
+			function: schema.Function{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"my_row_number"()`},
+				FunctionDef:         "CREATE OR REPLACE FUNCTION public.my_row_number()\n RETURNS bigint\n LANGUAGE internal\n IMMUTABLE\nAS $function$window_row_number$function$\n",
+				Language:            "internal",
+				IsWindow:            true,
+			},
+			expectedDDL: "CREATE OR REPLACE FUNCTION public.my_row_number()\n RETURNS bigint\n LANGUAGE internal\n WINDOW\n IMMUTABLE\nAS $function$window_row_number$function$\n",
+		},
+		{
+			name: "WINDOW attribute is not duplicated",
+			function: schema.Function{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"my_row_number"()`},
+				FunctionDef:         "CREATE OR REPLACE FUNCTION public.my_row_number()\n RETURNS bigint\n LANGUAGE internal\n WINDOW\n IMMUTABLE\nAS $function$window_row_number$function$\n",
+				Language:            "internal",
+				IsWindow:            true,
+			},
+			expectedDDL: "CREATE OR REPLACE FUNCTION public.my_row_number()\n RETURNS bigint\n LANGUAGE internal\n WINDOW\n IMMUTABLE\nAS $function$window_row_number$function$\n",
+		},
+		{
+			name: "Non-window function",
+			function: schema.Function{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"add"(a integer, b integer)`},
+				FunctionDef:         "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nRETURN (a + b)\n",
+				Language:            "sql",
+			},
+			expectedDDL: "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nRETURN (a + b)\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			partialGraph, err := newFunctionSqlVertexGenerator(nil, nil, nil, nil).Add(tc.function)
+			require.NoError(t, err)
+			require.Len(t, partialGraph.vertices, 1)
+			require.Len(t, partialGraph.vertices[0].statements, 1)
+			assert.Equal(t, tc.expectedDDL, partialGraph.vertices[0].statements[0].DDL)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_WindowAttributeChanged(t *testing.T) {
+	function := schema.Function{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"my_row_number"()`},
+		FunctionDef:         "CREATE OR REPLACE FUNCTION public.my_row_number()\n RETURNS bigint\n LANGUAGE internal\n IMMUTABLE\nAS $function$window_row_number$function$\n",
+		Language:            "internal",
+	}
+	windowFunction := function
+	windowFunction.IsWindow = true
+
+	for _, tc := range []struct {
+		name        string
+		oldFunction schema.Function
+		newFunction schema.Function
+
+		expectedCreateDDL string
+	}{
+		{
+			name:              "Add WINDOW attribute",
+			oldFunction:       function,
+			newFunction:       windowFunction,
+			expectedCreateDDL: "CREATE OR REPLACE FUNCTION public.my_row_number()\n RETURNS bigint\n LANGUAGE internal\n WINDOW\n IMMUTABLE\nAS $function$window_row_number$function$\n",
+		},
+		{
+			name:              "Remove WINDOW attribute",
+			oldFunction:       windowFunction,
+			newFunction:       function,
+			expectedCreateDDL: function.FunctionDef,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Re-created functions are executable by PUBLIC by default
+			privileges := []schema.Privilege{{
+				ObjectType: "FUNCTION",
+				Object:     function.SchemaQualifiedName,
+				Grantee:    schema.PrivilegeGranteePublic,
+				Type:       "EXECUTE",
+			}}
+			oldSchema := schema.Schema{Functions: []schema.Function{tc.oldFunction}, Privileges: privileges}
+			newSchema := schema.Schema{Functions: []schema.Function{tc.newFunction}, Privileges: privileges}
+
+			stmts, err := generateMigrationStatements(oldSchema, newSchema, &planOptions{})
+			require.NoError(t, err)
+			// The functions differ only in the WINDOW attribute, which cannot be changed via CREATE OR REPLACE, so the
+			// function is re-created
+			require.Len(t, stmts, 2)
+			assert.Equal(t, "DROP FUNCTION \"public\".\"my_row_number\"()", stmts[0].DDL)
+			assert.Equal(t, tc.expectedCreateDDL, stmts[1].DDL)
+		})
+	}
+}
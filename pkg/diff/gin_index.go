@@ -0,0 +1,54 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+const ginIndexMethod = "gin"
+
+var (
+	migrationHazardGINParametersChanged = MigrationHazard{
+		Type:    MigrationHazardTypeAcquiresAccessExclusiveLock,
+		Message: "Changing the storage parameters of a GIN index locks out all accesses to the index. It should be fast.",
+	}
+	migrationHazardGINFastUpdateDisabled = MigrationHazard{
+		Type: MigrationHazardTypeImpactsDatabasePerformance,
+		Message: "Disabling fastupdate does not flush the entries already in the index's pending list. Consider " +
+			"running VACUUM on the table or calling gin_clean_pending_list on the index afterward.",
+	}
+)
+
+// canAlterGINParameters returns true if the indexes only differ in their GIN storage parameters, i.e., fastupdate and
+// gin_pending_list_limit, which can be changed without re-creating the index
+func canAlterGINParameters(old, new schema.Index) bool {
+	if old.Method != ginIndexMethod || new.Method != ginIndexMethod {
+		return false
+	}
+	return removeIndexStorageParameters(string(old.GetIndexDefStmt)) == removeIndexStorageParameters(string(new.GetIndexDefStmt))
+}
+
+// buildAlterGINParametersStatements builds the statements to change the GIN storage parameters of the index. The
+// parameters that are not set on the new index are reset to their defaults.
+func buildAlterGINParametersStatements(old, new schema.Index) []Statement {
+	stmts := alterStorageParametersStatements(
+		fmt.Sprintf("ALTER INDEX %s", new.GetSchemaQualifiedName().GetFQEscapedName()),
+		old.GINParameters,
+		new.GINParameters,
+	)
+	for i := range stmts {
+		stmts[i].Hazards = append(stmts[i].Hazards, migrationHazardGINParametersChanged)
+	}
+	if isGINFastUpdateDisabled(old, new) {
+		// fastupdate is set to off by the SET statement, which precedes the RESET statement
+		stmts[0].Hazards = append(stmts[0].Hazards, migrationHazardGINFastUpdateDisabled)
+	}
+	return stmts
+}
+
+// isGINFastUpdateDisabled returns true if fastupdate is on for the old index, which is the default, and off for the
+// new index
+func isGINFastUpdateDisabled(old, new schema.Index) bool {
+	return old.GINParameters["fastupdate"] != "off" && new.GINParameters["fastupdate"] == "off"
+}
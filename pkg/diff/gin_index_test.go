@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestGINIndexParameters(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	table := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             []schema.Column{{Name: "payload", Type: "jsonb"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	buildGINIndex := func(params map[string]string, def string) schema.Index {
+		return schema.Index{
+			OwningTable:     foobar,
+			Name:            "payload_idx",
+			Columns:         []string{"payload"},
+			Method:          "gin",
+			GINParameters:   params,
+			GetIndexDefStmt: schema.GetIndexDefStatement(def),
+		}
+	}
+
+	for _, tc := range []struct {
+		name            string
+		oldIndexes      []schema.Index
+		newIndexes      []schema.Index
+		expectedDDL     []string
+		expectedHazards [][]MigrationHazard
+	}{
+		{
+			name: "Add index with fastupdate off",
+			newIndexes: []schema.Index{
+				buildGINIndex(map[string]string{"fastupdate": "off"}, "CREATE INDEX payload_idx ON public.foobar USING gin (payload) WITH (fastupdate=off)"),
+			},
+			expectedDDL:     []string{"CREATE INDEX CONCURRENTLY payload_idx ON public.foobar USING gin (payload) WITH (fastupdate=off)"},
+			expectedHazards: [][]MigrationHazard{{migrationHazardIndexBuildConcurrently}},
+		},
+		{
+			name: "Disable fastupdate",
+			oldIndexes: []schema.Index{
+				buildGINIndex(nil, "CREATE INDEX payload_idx ON public.foobar USING gin (payload)"),
+			},
+			newIndexes: []schema.Index{
+				buildGINIndex(map[string]string{"fastupdate": "off"}, "CREATE INDEX payload_idx ON public.foobar USING gin (payload) WITH (fastupdate=off)"),
+			},
+			expectedDDL:     []string{`ALTER INDEX "public"."payload_idx" SET (fastupdate=off)`},
+			expectedHazards: [][]MigrationHazard{{migrationHazardGINParametersChanged, migrationHazardGINFastUpdateDisabled}},
+		},
+		{
+			name: "Enable fastupdate",
+			oldIndexes: []schema.Index{
+				buildGINIndex(map[string]string{"fastupdate": "off"}, "CREATE INDEX payload_idx ON public.foobar USING gin (payload) WITH (fastupdate=off)"),
+			},
+			newIndexes: []schema.Index{
+				buildGINIndex(map[string]string{"fastupdate": "on"}, "CREATE INDEX payload_idx ON public.foobar USING gin (payload) WITH (fastupdate='on')"),
+			},
+			expectedDDL:     []string{`ALTER INDEX "public"."payload_idx" SET (fastupdate=on)`},
+			expectedHazards: [][]MigrationHazard{{migrationHazardGINParametersChanged}},
+		},
+		{
+			name: "Reset parameters to their defaults",
+			oldIndexes: []schema.Index{
+				buildGINIndex(
+					map[string]string{"fastupdate": "off", "gin_pending_list_limit": "128"},
+					"CREATE INDEX payload_idx ON public.foobar USING gin (payload) WITH (fastupdate=off, gin_pending_list_limit='128')",
+				),
+			},
+			newIndexes: []schema.Index{
+				buildGINIndex(nil, "CREATE INDEX payload_idx ON public.foobar USING gin (payload)"),
+			},
+			expectedDDL:     []string{`ALTER INDEX "public"."payload_idx" RESET (fastupdate, gin_pending_list_limit)`},
+			expectedHazards: [][]MigrationHazard{{migrationHazardGINParametersChanged}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(
+				schema.Schema{Tables: []schema.Table{table}, Indexes: tc.oldIndexes},
+				schema.Schema{Tables: []schema.Table{table}, Indexes: tc.newIndexes},
+				&planOptions{},
+			)
+			require.NoError(t, err)
+			var ddl []string
+			var hazards [][]MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnacknowledgedHazards is returned by the Executor if the plan requires its hazards to be acknowledged and has
+// hazards that are not suppressed
+var ErrUnacknowledgedHazards = errors.New("plan has unacknowledged hazards")
+
+// WithRequireHazardAcknowledgment configures the plan such that the Executor refuses to run it while it has hazards
+// that are not suppressed via Plan.SuppressHazard. This forces callers to explicitly acknowledge or fix each hazard.
+func WithRequireHazardAcknowledgment() PlanOpt {
+	return func(opts *planOptions) {
+		opts.requireHazardAcknowledgment = true
+	}
+}
+
+// SetRequireHazardAcknowledgment sets whether the Executor refuses to run the plan while it has unsuppressed hazards,
+// e.g., after deserializing a plan.
+func (p Plan) SetRequireHazardAcknowledgment(require bool) Plan {
+	p.requireHazardAcknowledgment = require
+	return p
+}
+
+// SuppressHazard returns a new plan where the hazards of the given type on the statement at stmtIndex are marked as
+// suppressed, i.e., an operator acknowledged the hazards are safe. The justification is recorded on the hazards and
+// logged by the Executor before the statement is executed. It must not be empty.
+func (p Plan) SuppressHazard(stmtIndex int, hazardType MigrationHazardType, justification string) (Plan, error) {
+	if stmtIndex < 0 || stmtIndex >= len(p.Statements) {
+		return Plan{}, fmt.Errorf("statement index %d out of range for %d statements", stmtIndex, len(p.Statements))
+	}
+	if len(strings.TrimSpace(justification)) == 0 {
+		return Plan{}, fmt.Errorf("a justification is required to suppress a hazard")
+	}
+
+	stmt := p.Statements[stmtIndex]
+	hazards := append([]MigrationHazard(nil), stmt.Hazards...)
+	found := false
+	for i, hazard := range hazards {
+		if hazard.Type != hazardType {
+			continue
+		}
+		hazards[i].Suppressed = true
+		hazards[i].Justification = justification
+		found = true
+	}
+	if !found {
+		return Plan{}, fmt.Errorf("statement %d has no hazard of type %s", stmtIndex, hazardType)
+	}
+	stmt.Hazards = hazards
+
+	p.Statements = append([]Statement(nil), p.Statements...)
+	p.Statements[stmtIndex] = stmt
+	return p, nil
+}
+
+// UnsuppressedHazards returns the hazards of the plan's statements that have not been suppressed via SuppressHazard,
+// in the order of the statements.
+func (p Plan) UnsuppressedHazards() []MigrationHazard {
+	var hazards []MigrationHazard
+	for _, stmt := range p.Statements {
+		for _, hazard := range stmt.Hazards {
+			if !hazard.Suppressed {
+				hazards = append(hazards, hazard)
+			}
+		}
+	}
+	return hazards
+}
+
+// checkHazardsAcknowledged returns an error if the plan requires its hazards to be acknowledged and has unsuppressed
+// hazards
+func (p Plan) checkHazardsAcknowledged() error {
+	if !p.requireHazardAcknowledgment {
+		return nil
+	}
+	unsuppressedHazards := p.UnsuppressedHazards()
+	if len(unsuppressedHazards) == 0 {
+		return nil
+	}
+	var hazardStrs []string
+	for _, hazard := range unsuppressedHazards {
+		hazardStrs = append(hazardStrs, hazard.String())
+	}
+	return fmt.Errorf("%w. Suppress or fix each of them:\n%s", ErrUnacknowledgedHazards, strings.Join(hazardStrs, "\n"))
+}
@@ -0,0 +1,78 @@
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressHazard(t *testing.T) {
+	plan := Plan{
+		Statements: []Statement{
+			{
+				DDL: `ALTER TABLE "public"."foobar" DROP COLUMN "bar"`,
+				Hazards: []MigrationHazard{
+					{Type: MigrationHazardTypeDeletesData, Message: "Deletes all values in the column"},
+				},
+			},
+			{
+				DDL: `CREATE INDEX CONCURRENTLY foo_idx ON public.foobar USING btree (foo)`,
+				Hazards: []MigrationHazard{
+					{Type: MigrationHazardTypeIndexBuild, Message: "Builds an index"},
+				},
+			},
+		},
+	}
+
+	suppressedPlan, err := plan.SuppressHazard(0, MigrationHazardTypeDeletesData, "The column was never written to")
+	require.NoError(t, err)
+	assert.Equal(t, []MigrationHazard{{
+		Type:          MigrationHazardTypeDeletesData,
+		Message:       "Deletes all values in the column",
+		Suppressed:    true,
+		Justification: "The column was never written to",
+	}}, suppressedPlan.Statements[0].Hazards)
+	assert.Equal(t, []MigrationHazard{{Type: MigrationHazardTypeIndexBuild, Message: "Builds an index"}}, suppressedPlan.UnsuppressedHazards())
+	// The original plan is not modified
+	assert.False(t, plan.Statements[0].Hazards[0].Suppressed)
+	assert.Len(t, plan.UnsuppressedHazards(), 2)
+
+	suppressedPlan, err = suppressedPlan.SuppressHazard(1, MigrationHazardTypeIndexBuild, "The table is small")
+	require.NoError(t, err)
+	assert.Empty(t, suppressedPlan.UnsuppressedHazards())
+
+	t.Run("Suppressed hazards are serialized", func(t *testing.T) {
+		data, err := json.Marshal(suppressedPlan)
+		require.NoError(t, err)
+		var deserializedPlan Plan
+		require.NoError(t, json.Unmarshal(data, &deserializedPlan))
+		assert.Equal(t, suppressedPlan.Statements, deserializedPlan.Statements)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := plan.SuppressHazard(2, MigrationHazardTypeDeletesData, "The column was never written to")
+		assert.ErrorContains(t, err, "out of range")
+		_, err = plan.SuppressHazard(0, MigrationHazardTypeDeletesData, " ")
+		assert.ErrorContains(t, err, "justification is required")
+		_, err = plan.SuppressHazard(0, MigrationHazardTypeIndexBuild, "The table is small")
+		assert.ErrorContains(t, err, "has no hazard of type INDEX_BUILD")
+	})
+
+	t.Run("Executor refuses to run plans with unacknowledged hazards", func(t *testing.T) {
+		requiringPlan := plan.SetRequireHazardAcknowledgment(true)
+		requiringPlan, err := requiringPlan.SuppressHazard(0, MigrationHazardTypeDeletesData, "The column was never written to")
+		require.NoError(t, err)
+		// The plan is rejected before connecting to the database
+		err = NewExecutor().RunInTransaction(context.Background(), requiringPlan, nil)
+		assert.ErrorIs(t, err, ErrUnacknowledgedHazards)
+		assert.ErrorContains(t, err, "INDEX_BUILD: Builds an index")
+		assert.NotContains(t, err.Error(), "DELETES_DATA")
+	})
+
+	t.Run("Hazards do not need to be acknowledged by default", func(t *testing.T) {
+		assert.NoError(t, plan.checkHazardsAcknowledged())
+	})
+}
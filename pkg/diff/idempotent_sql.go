@@ -0,0 +1,136 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// idempotentSQLDollarQuoteTag is the tag of the dollar-quoted body of the DO blocks that make statements idempotent
+	idempotentSQLDollarQuoteTag = "$pgschemadiff_idempotent$"
+	// idempotentSQLDOBlockPrefix and idempotentSQLDOBlockSuffix wrap the statements that cannot be guarded with
+	// `IF NOT EXISTS` in a DO block that ignores errors about the object already existing
+	idempotentSQLDOBlockPrefix = "DO " + idempotentSQLDollarQuoteTag + "\nBEGIN\n\t"
+	idempotentSQLDOBlockSuffix = ";\nEXCEPTION WHEN duplicate_table OR duplicate_object OR duplicate_function THEN NULL;\nEND\n" + idempotentSQLDollarQuoteTag
+)
+
+var (
+	// idempotencyGuardedPrefixes maps the prefixes of statements to the prefixes that make the statements no-ops if they
+	// were already applied. The first matching prefix is used. Concurrent index builds are not guarded: a failed
+	// concurrent build leaves an invalid index behind, which `IF NOT EXISTS` would silently keep.
+	idempotencyGuardedPrefixes = buildIdempotencyGuardedPrefixes()
+
+	// idempotencyGuardAlterTableRegex matches the statements that add or drop a column or drop a constraint. The first
+	// matching group is the `ALTER TABLE` prefix, and the second is the action.
+	idempotencyGuardAlterTableRegex = regexp.MustCompile(`^(ALTER TABLE (?:ONLY )?` + qualifiedIdentifierPattern + ` )(ADD COLUMN|DROP COLUMN|DROP CONSTRAINT) `)
+	// idempotentSQLAddConstraintRegex matches the statements that add a constraint, which do not support IF NOT EXISTS
+	idempotentSQLAddConstraintRegex = regexp.MustCompile(`^ALTER TABLE (?:ONLY )?` + qualifiedIdentifierPattern + ` ADD CONSTRAINT `)
+)
+
+type idempotencyGuardedPrefix struct {
+	prefix        string
+	guardedPrefix string
+}
+
+func buildIdempotencyGuardedPrefixes() []idempotencyGuardedPrefix {
+	prefixes := []idempotencyGuardedPrefix{
+		{prefix: "CREATE INDEX CONCURRENTLY ", guardedPrefix: "CREATE INDEX CONCURRENTLY "},
+		{prefix: "CREATE UNIQUE INDEX CONCURRENTLY ", guardedPrefix: "CREATE UNIQUE INDEX CONCURRENTLY "},
+		{prefix: "DROP INDEX CONCURRENTLY ", guardedPrefix: "DROP INDEX CONCURRENTLY IF EXISTS "},
+	}
+	for _, objectType := range []string{
+		"TABLE", "SCHEMA", "EXTENSION", "SEQUENCE", "INDEX", "UNIQUE INDEX", "MATERIALIZED VIEW", "STATISTICS",
+		"COLLATION", "FOREIGN TABLE", "SERVER",
+	} {
+		prefixes = append(prefixes, idempotencyGuardedPrefix{
+			prefix:        fmt.Sprintf("CREATE %s ", objectType),
+			guardedPrefix: fmt.Sprintf("CREATE %s IF NOT EXISTS ", objectType),
+		})
+	}
+	// OPERATOR CLASS must precede OPERATOR, since OPERATOR is a prefix of it
+	for _, objectType := range []string{
+		"TABLE", "SCHEMA", "EXTENSION", "SEQUENCE", "INDEX", "VIEW", "MATERIALIZED VIEW", "STATISTICS", "COLLATION",
+		"FOREIGN TABLE", "FOREIGN DATA WRAPPER", "SERVER", "TYPE", "DOMAIN", "FUNCTION", "PROCEDURE", "AGGREGATE",
+		"OPERATOR CLASS", "OPERATOR", "TRIGGER", "EVENT TRIGGER", "POLICY", "PUBLICATION", "TEXT SEARCH DICTIONARY",
+		"TEXT SEARCH CONFIGURATION",
+	} {
+		prefixes = append(prefixes, idempotencyGuardedPrefix{
+			prefix:        fmt.Sprintf("DROP %s ", objectType),
+			guardedPrefix: fmt.Sprintf("DROP %s IF EXISTS ", objectType),
+		})
+	}
+	return prefixes
+}
+
+// WithIdempotentSQL generates statements that can be re-run without error if they were already applied, e.g., to
+// re-run a migration that failed midway. Statements use `IF NOT EXISTS` and `IF EXISTS` where Postgres supports them,
+// e.g., `CREATE TABLE IF NOT EXISTS` and `ALTER TABLE ... ADD COLUMN IF NOT EXISTS`. Other statements that create
+// objects, e.g., `CREATE TYPE` and `ADD CONSTRAINT`, are wrapped in a DO block that ignores errors about the object
+// already existing.
+//
+// Some statements remain non-idempotent: statements that cannot run in a transaction block, e.g.,
+// `CREATE INDEX CONCURRENTLY`, cannot be wrapped in a DO block, and renames cannot be guarded.
+func WithIdempotentSQL() PlanOpt {
+	return func(opts *planOptions) {
+		opts.idempotentSQL = true
+	}
+}
+
+// makeStatementIdempotent returns the statement modified such that it is a no-op if it was already applied. See
+// WithIdempotentSQL.
+func makeStatementIdempotent(stmt Statement) Statement {
+	if stmt.IsAdvisory {
+		return stmt
+	}
+	if guardedDDL := addIdempotencyGuard(stmt.DDL); guardedDDL != stmt.DDL {
+		stmt.DDL = guardedDDL
+		return stmt
+	}
+	if stmt.RequiresNoTransaction || strings.Contains(stmt.DDL, idempotentSQLDollarQuoteTag) {
+		return stmt
+	}
+	isCreateStatement := strings.HasPrefix(stmt.DDL, "CREATE ") && !strings.HasPrefix(stmt.DDL, "CREATE OR REPLACE ")
+	if isCreateStatement || idempotentSQLAddConstraintRegex.MatchString(stmt.DDL) {
+		stmt.DDL = idempotentSQLDOBlockPrefix + strings.ReplaceAll(stmt.DDL, "\n", "\n\t") + idempotentSQLDOBlockSuffix
+	}
+	return stmt
+}
+
+// unwrapIdempotentSQL returns the statement wrapped in a DO block by makeStatementIdempotent. The DDL is returned
+// unmodified if it is not wrapped.
+func unwrapIdempotentSQL(ddl string) string {
+	if !strings.HasPrefix(ddl, idempotentSQLDOBlockPrefix) || !strings.HasSuffix(ddl, idempotentSQLDOBlockSuffix) {
+		return ddl
+	}
+	ddl = strings.TrimSuffix(strings.TrimPrefix(ddl, idempotentSQLDOBlockPrefix), idempotentSQLDOBlockSuffix)
+	return strings.ReplaceAll(ddl, "\n\t", "\n")
+}
+
+// addIdempotencyGuard returns the DDL modified to be a no-op if it was already applied, e.g., `CREATE TABLE` is
+// replaced with `CREATE TABLE IF NOT EXISTS`. The DDL is returned unmodified if it cannot be guarded.
+func addIdempotencyGuard(ddl string) string {
+	for _, p := range idempotencyGuardedPrefixes {
+		if !strings.HasPrefix(ddl, p.prefix) {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimPrefix(ddl, p.prefix), "IF ") {
+			// The statement is already guarded
+			return ddl
+		}
+		return p.guardedPrefix + strings.TrimPrefix(ddl, p.prefix)
+	}
+
+	if match := idempotencyGuardAlterTableRegex.FindStringSubmatch(ddl); match != nil {
+		rest := strings.TrimPrefix(ddl, match[0])
+		if strings.HasPrefix(rest, "IF ") {
+			return ddl
+		}
+		guard := "IF EXISTS"
+		if match[2] == "ADD COLUMN" {
+			guard = "IF NOT EXISTS"
+		}
+		return fmt.Sprintf("%s%s %s %s", match[1], match[2], guard, rest)
+	}
+	return ddl
+}
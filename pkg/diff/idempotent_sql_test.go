@@ -0,0 +1,172 @@
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddIdempotencyGuard(t *testing.T) {
+	for _, tc := range []struct {
+		ddl         string
+		expectedDDL string
+	}{
+		{
+			ddl:         "CREATE TABLE \"public\".\"foobar\" (\n\t\"id\" integer\n)",
+			expectedDDL: "CREATE TABLE IF NOT EXISTS \"public\".\"foobar\" (\n\t\"id\" integer\n)",
+		},
+		{
+			ddl:         `CREATE TABLE IF NOT EXISTS "public"."foobar" ()`,
+			expectedDDL: `CREATE TABLE IF NOT EXISTS "public"."foobar" ()`,
+		},
+		{
+			ddl:         `CREATE SCHEMA "foo"`,
+			expectedDDL: `CREATE SCHEMA IF NOT EXISTS "foo"`,
+		},
+		{
+			ddl:         `CREATE EXTENSION "pg_trgm" WITH SCHEMA "public"`,
+			expectedDDL: `CREATE EXTENSION IF NOT EXISTS "pg_trgm" WITH SCHEMA "public"`,
+		},
+		{
+			ddl:         `CREATE UNIQUE INDEX foo_idx ON public.foobar USING btree (foo)`,
+			expectedDDL: `CREATE UNIQUE INDEX IF NOT EXISTS foo_idx ON public.foobar USING btree (foo)`,
+		},
+		{
+			ddl:         `CREATE INDEX CONCURRENTLY foo_idx ON public.foobar USING btree (foo)`,
+			expectedDDL: `CREATE INDEX CONCURRENTLY foo_idx ON public.foobar USING btree (foo)`,
+		},
+		{
+			ddl:         `DROP INDEX CONCURRENTLY "public"."foo_idx"`,
+			expectedDDL: `DROP INDEX CONCURRENTLY IF EXISTS "public"."foo_idx"`,
+		},
+		{
+			ddl:         `DROP TABLE "public"."foobar"`,
+			expectedDDL: `DROP TABLE IF EXISTS "public"."foobar"`,
+		},
+		{
+			ddl:         `DROP MATERIALIZED VIEW "public"."foobar_mv"`,
+			expectedDDL: `DROP MATERIALIZED VIEW IF EXISTS "public"."foobar_mv"`,
+		},
+		{
+			ddl:         `ALTER TABLE "public"."foobar" ADD COLUMN "bar" text`,
+			expectedDDL: `ALTER TABLE "public"."foobar" ADD COLUMN IF NOT EXISTS "bar" text`,
+		},
+		{
+			ddl:         `ALTER TABLE "public"."foobar" DROP COLUMN "bar"`,
+			expectedDDL: `ALTER TABLE "public"."foobar" DROP COLUMN IF EXISTS "bar"`,
+		},
+		{
+			ddl:         `ALTER TABLE "public"."foobar" DROP CONSTRAINT "foobar_bar_check"`,
+			expectedDDL: `ALTER TABLE "public"."foobar" DROP CONSTRAINT IF EXISTS "foobar_bar_check"`,
+		},
+		{
+			ddl:         `DROP OPERATOR CLASS "public"."foo_ops" USING btree`,
+			expectedDDL: `DROP OPERATOR CLASS IF EXISTS "public"."foo_ops" USING btree`,
+		},
+		{
+			ddl:         `ALTER TABLE "public"."foobar" ALTER COLUMN "bar" SET NOT NULL`,
+			expectedDDL: `ALTER TABLE "public"."foobar" ALTER COLUMN "bar" SET NOT NULL`,
+		},
+	} {
+		t.Run(tc.ddl, func(t *testing.T) {
+			assert.Equal(t, tc.expectedDDL, addIdempotencyGuard(tc.ddl))
+		})
+	}
+}
+
+func TestMakeStatementIdempotent(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		stmt         Statement
+		expectedStmt Statement
+	}{
+		{
+			name:         "Guarded statement",
+			stmt:         Statement{DDL: `DROP TABLE "public"."foobar"`},
+			expectedStmt: Statement{DDL: `DROP TABLE IF EXISTS "public"."foobar"`},
+		},
+		{
+			name: "Create statement without IF NOT EXISTS support",
+			stmt: Statement{DDL: `CREATE TYPE "public"."color" AS ENUM ('red', 'green')`},
+			expectedStmt: Statement{DDL: "DO $pgschemadiff_idempotent$\n" +
+				"BEGIN\n" +
+				"\tCREATE TYPE \"public\".\"color\" AS ENUM ('red', 'green');\n" +
+				"EXCEPTION WHEN duplicate_table OR duplicate_object OR duplicate_function THEN NULL;\n" +
+				"END\n" +
+				"$pgschemadiff_idempotent$"},
+		},
+		{
+			name: "Add constraint",
+			stmt: Statement{DDL: `ALTER TABLE "public"."foobar" ADD CONSTRAINT "foobar_pkey" PRIMARY KEY ("id")`},
+			expectedStmt: Statement{DDL: "DO $pgschemadiff_idempotent$\n" +
+				"BEGIN\n" +
+				"\tALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"foobar_pkey\" PRIMARY KEY (\"id\");\n" +
+				"EXCEPTION WHEN duplicate_table OR duplicate_object OR duplicate_function THEN NULL;\n" +
+				"END\n" +
+				"$pgschemadiff_idempotent$"},
+		},
+		{
+			name:         "Create or replace statement",
+			stmt:         Statement{DDL: `CREATE OR REPLACE VIEW "public"."foobar_view" AS SELECT 1`},
+			expectedStmt: Statement{DDL: `CREATE OR REPLACE VIEW "public"."foobar_view" AS SELECT 1`},
+		},
+		{
+			name:         "Statement that requires no transaction",
+			stmt:         Statement{DDL: `CREATE INDEX CONCURRENTLY foo_idx ON public.foobar USING btree (foo)`, RequiresNoTransaction: true},
+			expectedStmt: Statement{DDL: `CREATE INDEX CONCURRENTLY foo_idx ON public.foobar USING btree (foo)`, RequiresNoTransaction: true},
+		},
+		{
+			name:         "Advisory statement",
+			stmt:         Statement{DDL: `CREATE STATISTICS foo_stats ON foo, bar FROM public.foobar`, IsAdvisory: true},
+			expectedStmt: Statement{DDL: `CREATE STATISTICS foo_stats ON foo, bar FROM public.foobar`, IsAdvisory: true},
+		},
+		{
+			name:         "Rename",
+			stmt:         Statement{DDL: `ALTER INDEX "public"."foo_idx" RENAME TO "bar_idx"`},
+			expectedStmt: Statement{DDL: `ALTER INDEX "public"."foo_idx" RENAME TO "bar_idx"`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedStmt, makeStatementIdempotent(tc.stmt))
+		})
+	}
+}
+
+func (suite *planGeneratorTestSuite) TestGenerate_IdempotentSQL() {
+	suite.mustApplyDDLToTestDb([]string{`
+		CREATE TABLE foobar(id INT PRIMARY KEY, val TEXT);
+		CREATE TABLE to_drop(id INT);
+	`})
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	tempDbFactory := suite.mustBuildTempDbFactory(context.Background())
+	defer tempDbFactory.Close()
+
+	plan, err := Generate(context.Background(), DBSchemaSource(connPool), DDLSchemaSource([]string{`
+		CREATE TYPE color AS ENUM ('red', 'green');
+		CREATE TABLE foobar(id INT PRIMARY KEY, other_val TEXT, color color);
+		CREATE INDEX foobar_other_val_idx ON foobar(other_val);
+		CREATE TABLE bar(
+			id INT PRIMARY KEY,
+			foobar_id INT REFERENCES foobar(id),
+			CHECK (id > 0)
+		);
+		CREATE VIEW bar_view AS SELECT id FROM bar;
+	`}),
+		WithTempDbFactory(tempDbFactory),
+		WithIdempotentSQL(),
+		WithDoNotUseConcurrentIndexOperations(),
+	)
+	suite.Require().NoError(err)
+
+	suite.mustApplyMigrationPlan(connPool, plan)
+	// The plan can be applied again without error
+	suite.mustApplyMigrationPlan(connPool, plan)
+
+	_, err = connPool.ExecContext(context.Background(), "SELECT id, other_val, color FROM foobar")
+	suite.NoError(err)
+	_, err = connPool.ExecContext(context.Background(), "SELECT id FROM bar_view")
+	suite.NoError(err)
+}
@@ -0,0 +1,37 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/internal/lint"
+)
+
+type (
+	// LintIssue is a potential problem with a schema, e.g., a table without a primary key. Issues do not prevent a
+	// migration from being planned or executed.
+	LintIssue = lint.Issue
+	// LintSeverity is the severity of a LintIssue
+	LintSeverity = lint.Severity
+)
+
+const (
+	LintSeverityWarning = lint.SeverityWarning
+)
+
+// Lint returns the lint issues found in the schema of the source. The issues are deterministically ordered.
+//
+// Only the options that affect how the schema is fetched are used, e.g., WithIncludeSchemas and WithTempDbFactory.
+// A tempDbFactory is required if the source is a DDLSchemaSource.
+func Lint(ctx context.Context, source SchemaSource, opts ...PlanOpt) ([]LintIssue, error) {
+	planOptions := buildPlanOptions(opts)
+	s, err := source.GetSchema(ctx, schemaSourcePlanDeps{
+		tempDBFactory: planOptions.tempDbFactory,
+		logger:        planOptions.logger,
+		getSchemaOpts: planOptions.getSchemaOpts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting schema: %w", err)
+	}
+	return lint.LintSchema(s), nil
+}
@@ -0,0 +1,33 @@
+package diff
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/pkg/schema"
+)
+
+func TestLint(t *testing.T) {
+	s, err := schema.ParseDump(strings.NewReader(`
+CREATE TABLE public.foo (
+    id integer NOT NULL
+);
+CREATE TABLE public.bar (
+    id integer NOT NULL
+);
+ALTER TABLE ONLY public.bar
+    ADD CONSTRAINT bar_pkey PRIMARY KEY (id);
+`))
+	require.NoError(t, err)
+
+	issues, err := Lint(context.Background(), SchemaSchemaSource(s))
+	require.NoError(t, err)
+	assert.Equal(t, []LintIssue{{
+		Severity:   LintSeverityWarning,
+		ObjectName: `"public"."foo"`,
+		Message:    `table "public"."foo" has no primary key. Tables without primary keys can cause problems for logical replication, ORMs, and some backup tools.`,
+	}}, issues)
+}
@@ -0,0 +1,139 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	// advisoryColumnChangeRegex matches the statements that add, drop, or change the type of a column. The first
+	// matching group is the table.
+	advisoryColumnChangeRegex        = regexp.MustCompile(`^ALTER TABLE (?:ONLY )?(` + qualifiedIdentifierPattern + `) (?:ADD COLUMN |DROP COLUMN |ALTER COLUMN ` + identifierPattern + ` SET DATA TYPE )`)
+	advisoryDropTableRegex           = regexp.MustCompile(`^DROP TABLE (` + qualifiedIdentifierPattern + `)`)
+	analyzeAdvisoryAnalyzeTableRegex = regexp.MustCompile(`^ANALYZE (` + qualifiedIdentifierPattern + `)$`)
+
+	// vacuumAdvisoryUpdateRegex matches UPDATE statements. The first matching group is the table.
+	vacuumAdvisoryUpdateRegex      = regexp.MustCompile(`(?is)^UPDATE (?:ONLY )?(` + qualifiedIdentifierPattern + `) (?:AS ` + identifierPattern + ` )?SET `)
+	vacuumAdvisoryWhereRegex       = regexp.MustCompile(`(?i)\bWHERE\b`)
+	vacuumAdvisoryVacuumTableRegex = regexp.MustCompile(`^VACUUM FREEZE (` + qualifiedIdentifierPattern + `)$`)
+)
+
+// WithDoNotEmitAnalyzeAdvisories configures the plan generation to not generate the advisory `ANALYZE` statements for
+// tables whose columns are added, dropped, or changed type. See buildAnalyzeAdvisoryStatements.
+func WithDoNotEmitAnalyzeAdvisories() PlanOpt {
+	return func(opts *planOptions) {
+		opts.doNotEmitAnalyzeAdvisories = true
+	}
+}
+
+// WithDoNotEmitVacuumAdvisories configures the plan generation to not generate the advisory `VACUUM FREEZE`
+// statements for tables whose rows are all rewritten by a backfill. See buildVacuumAdvisoryStatements.
+func WithDoNotEmitVacuumAdvisories() PlanOpt {
+	return func(opts *planOptions) {
+		opts.doNotEmitVacuumAdvisories = true
+	}
+}
+
+// buildAdvisoryMaintenanceStatements builds the advisory statements to maintain the tables changed by the statements
+// after the migration
+func buildAdvisoryMaintenanceStatements(stmts []Statement, planOptions *planOptions) []Statement {
+	var advisoryStmts []Statement
+	if !planOptions.doNotEmitVacuumAdvisories {
+		advisoryStmts = append(advisoryStmts, buildVacuumAdvisoryStatements(stmts)...)
+	}
+	if !planOptions.rdsMode && !planOptions.doNotEmitAnalyzeAdvisories {
+		advisoryStmts = append(advisoryStmts, buildAnalyzeAdvisoryStatements(stmts)...)
+	}
+	return advisoryStmts
+}
+
+// buildVacuumAdvisoryStatements builds an advisory `VACUUM FREEZE` statement for each table whose rows are all
+// rewritten by a backfill, i.e., an UPDATE without a WHERE clause or the addition of a column whose values are computed
+// for every existing row, e.g., a stored generated column. Every rewritten row has the migration's transaction ID, so
+// the rows would otherwise all become eligible for an anti-wraparound autovacuum at once. The tables are vacuumed in the
+// order they are first backfilled. Tables that are dropped by the statements and tables that already have an advisory
+// `VACUUM FREEZE` statement are skipped.
+func buildVacuumAdvisoryStatements(stmts []Statement) []Statement {
+	var tables []string
+	isSkipped := make(map[string]bool)
+	for _, stmt := range stmts {
+		if stmt.IsAdvisory {
+			if match := vacuumAdvisoryVacuumTableRegex.FindStringSubmatch(stmt.DDL); match != nil {
+				isSkipped[match[1]] = true
+			}
+			continue
+		}
+		if match := advisoryDropTableRegex.FindStringSubmatch(stmt.DDL); match != nil {
+			isSkipped[match[1]] = true
+			continue
+		}
+		if match := vacuumAdvisoryUpdateRegex.FindStringSubmatch(stmt.DDL); match != nil && !vacuumAdvisoryWhereRegex.MatchString(stmt.DDL) {
+			tables = append(tables, match[1])
+		} else if match := advisoryColumnChangeRegex.FindStringSubmatch(stmt.DDL); match != nil && hasTableRewriteHazard(stmt) {
+			tables = append(tables, match[1])
+		}
+	}
+
+	var vacuumStmts []Statement
+	for _, table := range filterAdvisoryTables(tables, isSkipped) {
+		// Vacuuming a large table can take a while, so it is left to the user to run it after the migration
+		vacuumStmts = append(vacuumStmts, Statement{
+			DDL:                   fmt.Sprintf("VACUUM FREEZE %s", table),
+			Timeout:               statementTimeoutVacuumTable,
+			LockTimeout:           lockTimeoutDefault,
+			RequiresNoTransaction: true,
+			IsAdvisory:            true,
+		})
+	}
+	return vacuumStmts
+}
+
+// buildAnalyzeAdvisoryStatements builds an advisory `ANALYZE` statement for each table whose columns are added,
+// dropped, or changed type by the statements, since the table's statistics no longer reflect its columns. The tables are
+// analyzed in the order they are first altered. Tables that are dropped by the statements and tables that already have
+// an advisory `ANALYZE` statement, e.g., to populate new statistics objects, are skipped.
+func buildAnalyzeAdvisoryStatements(stmts []Statement) []Statement {
+	var tables []string
+	isSkipped := make(map[string]bool)
+	for _, stmt := range stmts {
+		if stmt.IsAdvisory {
+			if match := analyzeAdvisoryAnalyzeTableRegex.FindStringSubmatch(stmt.DDL); match != nil {
+				isSkipped[match[1]] = true
+			}
+			continue
+		}
+		if match := advisoryDropTableRegex.FindStringSubmatch(stmt.DDL); match != nil {
+			isSkipped[match[1]] = true
+			continue
+		}
+		if match := advisoryColumnChangeRegex.FindStringSubmatch(stmt.DDL); match != nil {
+			tables = append(tables, match[1])
+		}
+	}
+
+	var analyzeStmts []Statement
+	for _, table := range filterAdvisoryTables(tables, isSkipped) {
+		// Analyzing a large table can take a while, so it is left to the user to run it after the migration
+		analyzeStmts = append(analyzeStmts, Statement{
+			DDL:         fmt.Sprintf("ANALYZE %s", table),
+			Timeout:     statementTimeoutAnalyzeTable,
+			LockTimeout: lockTimeoutDefault,
+			IsAdvisory:  true,
+		})
+	}
+	return analyzeStmts
+}
+
+// filterAdvisoryTables returns the tables without duplicates and skipped tables, keeping the first occurrence of each
+func filterAdvisoryTables(tables []string, isSkipped map[string]bool) []string {
+	var filteredTables []string
+	isAdded := make(map[string]bool)
+	for _, table := range tables {
+		if isSkipped[table] || isAdded[table] {
+			continue
+		}
+		isAdded[table] = true
+		filteredTables = append(filteredTables, table)
+	}
+	return filteredTables
+}
@@ -0,0 +1,107 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestBuildAnalyzeAdvisoryStatements(t *testing.T) {
+	stmts := []Statement{
+		{DDL: `ALTER TABLE "public"."foobar" ADD COLUMN "val" text`},
+		{DDL: `ALTER TABLE "public"."foobar" DROP COLUMN "other_val"`},
+		{DDL: `ALTER TABLE "public"."bar" ALTER COLUMN "val" SET DATA TYPE bigint using "val"::bigint`},
+		{DDL: `ALTER TABLE "public"."bar" ALTER COLUMN "val" SET DEFAULT 0`},
+		{DDL: `ALTER TABLE "public"."dropped" DROP COLUMN "val"`},
+		{DDL: `DROP TABLE "public"."dropped"`},
+		{DDL: `ALTER TABLE "public"."stats" ADD COLUMN "val" text`},
+		{DDL: `ANALYZE "public"."stats"`, IsAdvisory: true},
+		{DDL: `ALTER TABLE "public"."unchanged_columns" ADD CONSTRAINT "some_check" CHECK(true)`},
+	}
+	assert.Equal(t, []Statement{
+		{DDL: `ANALYZE "public"."foobar"`, Timeout: statementTimeoutAnalyzeTable, LockTimeout: lockTimeoutDefault, IsAdvisory: true},
+		{DDL: `ANALYZE "public"."bar"`, Timeout: statementTimeoutAnalyzeTable, LockTimeout: lockTimeoutDefault, IsAdvisory: true},
+	}, buildAnalyzeAdvisoryStatements(stmts))
+}
+
+func TestBuildVacuumAdvisoryStatements(t *testing.T) {
+	stmts := []Statement{
+		{DDL: `UPDATE "public"."foobar" SET "val" = 'x'`},
+		{DDL: `UPDATE "public"."partial" SET "val" = 'x' WHERE "id" < 100`},
+		{
+			DDL:     `ALTER TABLE "public"."bar" ADD COLUMN "total" numeric GENERATED ALWAYS AS ((price * 1.1)) STORED`,
+			Hazards: []MigrationHazard{migrationHazardGeneratedColumnAdded},
+		},
+		{DDL: `ALTER TABLE "public"."not_backfilled" ADD COLUMN "val" text`},
+		{DDL: `UPDATE "public"."foobar" SET "other_val" = 'y'`},
+		{DDL: `UPDATE "public"."dropped" SET "val" = 'x'`},
+		{DDL: `DROP TABLE "public"."dropped"`},
+	}
+	assert.Equal(t, []Statement{
+		{DDL: `VACUUM FREEZE "public"."foobar"`, Timeout: statementTimeoutVacuumTable, LockTimeout: lockTimeoutDefault, RequiresNoTransaction: true, IsAdvisory: true},
+		{DDL: `VACUUM FREEZE "public"."bar"`, Timeout: statementTimeoutVacuumTable, LockTimeout: lockTimeoutDefault, RequiresNoTransaction: true, IsAdvisory: true},
+	}, buildVacuumAdvisoryStatements(stmts))
+}
+
+func TestGenerateMigrationStatements_AnalyzeAdvisories(t *testing.T) {
+	foobar := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`},
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	newFoobar := foobar
+	newFoobar.Columns = append(newFoobar.Columns, schema.Column{Name: "val", Type: "text", IsNullable: true}, schema.Column{Name: "other_val", Type: "text", IsNullable: true})
+	oldSchema := schema.Schema{Tables: []schema.Table{foobar}}
+	newSchema := schema.Schema{Tables: []schema.Table{newFoobar}}
+
+	plan, err := buildPlan(oldSchema, newSchema, &planOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "public"."foobar" ADD COLUMN "other_val" text`,
+		`ALTER TABLE "public"."foobar" ADD COLUMN "val" text`,
+		`ANALYZE "public"."foobar"`,
+	}, getDDL(plan))
+	assert.Equal(t, plan.Statements[:2], plan.ExecutableStatements())
+	assert.Equal(t, []Statement{
+		{DDL: `ANALYZE "public"."foobar"`, Timeout: statementTimeoutAnalyzeTable, LockTimeout: lockTimeoutDefault, IsAdvisory: true},
+	}, plan.AdvisoryStatements())
+	assert.Contains(t, plan.Dependencies, StatementDependency{Statement: 2, DependsOn: 1})
+
+	plan, err = buildPlan(oldSchema, newSchema, &planOptions{doNotEmitAnalyzeAdvisories: true})
+	require.NoError(t, err)
+	assert.Empty(t, plan.AdvisoryStatements())
+	assert.Len(t, plan.ExecutableStatements(), 2)
+}
+
+func TestGenerateMigrationStatements_VacuumAdvisories(t *testing.T) {
+	foobar := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`},
+		Columns:             []schema.Column{{Name: "price", Type: "numeric"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	withColumn := func(column schema.Column) schema.Schema {
+		table := foobar
+		table.Columns = append(append([]schema.Column(nil), foobar.Columns...), column)
+		return schema.Schema{Tables: []schema.Table{table}}
+	}
+	oldSchema := schema.Schema{Tables: []schema.Table{foobar}}
+
+	// Adding a stored generated column backfills its values for every row
+	plan, err := buildPlan(oldSchema, withColumn(schema.Column{Name: "total", Type: "numeric", IsNullable: true, IsGenerated: true, GenerationExpression: "(price * 1.1)"}), &planOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`VACUUM FREEZE "public"."foobar"`,
+		`ANALYZE "public"."foobar"`,
+	}, getDDL(Plan{Statements: plan.AdvisoryStatements()}))
+
+	plan, err = buildPlan(oldSchema, withColumn(schema.Column{Name: "total", Type: "numeric", IsNullable: true, IsGenerated: true, GenerationExpression: "(price * 1.1)"}), &planOptions{doNotEmitVacuumAdvisories: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`ANALYZE "public"."foobar"`}, getDDL(Plan{Statements: plan.AdvisoryStatements()}))
+
+	// Adding a regular column does not backfill the table
+	plan, err = buildPlan(oldSchema, withColumn(schema.Column{Name: "total", Type: "numeric", IsNullable: true}), &planOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`ANALYZE "public"."foobar"`}, getDDL(Plan{Statements: plan.AdvisoryStatements()}))
+}
@@ -0,0 +1,254 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	migrationHazardMaterializedViewPopulated = MigrationHazard{
+		Type: MigrationHazardTypeImpactsDatabasePerformance,
+		Message: "Creating a materialized view runs its query to populate it. This might take a while and consume a " +
+			"significant amount of resources.",
+	}
+	migrationHazardMaterializedViewDeleted = MigrationHazard{
+		Type:    MigrationHazardTypeDeletesData,
+		Message: "Deletes the materialized view and its data",
+	}
+	migrationHazardMaterializedViewRefreshed = MigrationHazard{
+		Type: MigrationHazardTypeImpactsDatabasePerformance,
+		Message: "Refreshing a materialized view re-runs its query. Refreshing concurrently does not block reads, but " +
+			"it might take a while and consume a significant amount of resources.",
+	}
+)
+
+type materializedViewSQLVertexGenerator struct {
+	// nonConcurrentIndexOps is true if indexes should be built without CONCURRENTLY
+	nonConcurrentIndexOps bool
+	// nonConcurrentIndexDrops is true if indexes should be dropped without CONCURRENTLY
+	nonConcurrentIndexDrops bool
+}
+
+func (m *materializedViewSQLVertexGenerator) Add(mv schema.MaterializedView) ([]Statement, error) {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("CREATE MATERIALIZED VIEW %s", mv.GetFQEscapedName()))
+	if len(mv.StorageParameters) > 0 {
+		sb.WriteString(fmt.Sprintf(" WITH (%s)", buildStorageParameterList(mv.StorageParameters)))
+	}
+	sb.WriteString(fmt.Sprintf(" AS %s", mv.Definition))
+
+	stmts := []Statement{{
+		DDL:         sb.String(),
+		Timeout:     statementTimeoutMaterializedViewBuild,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardMaterializedViewPopulated},
+	}}
+	// The materialized view is new, so its indexes don't need to be built concurrently
+	for _, index := range mv.Indexes {
+		createIdxStmt, err := addTablespaceToCreateIndexStmt(string(index.GetIndexDefStmt), index)
+		if err != nil {
+			return nil, fmt.Errorf("adding tablespace to create index statement: %w", err)
+		}
+		stmts = append(stmts, Statement{
+			DDL:         createIdxStmt,
+			Timeout:     statementTimeoutMaterializedViewBuild,
+			LockTimeout: lockTimeoutDefault,
+		})
+		stmts = append(stmts, buildCommentStatements("INDEX", index.GetSchemaQualifiedName().GetFQEscapedName(), nil, index.Comment)...)
+	}
+	return stmts, nil
+}
+
+func (m *materializedViewSQLVertexGenerator) Delete(mv schema.MaterializedView) ([]Statement, error) {
+	return []Statement{{
+		DDL:         fmt.Sprintf("DROP MATERIALIZED VIEW %s", mv.GetFQEscapedName()),
+		Timeout:     statementTimeoutTableDrop,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardMaterializedViewDeleted},
+	}}, nil
+}
+
+// Alter alters the storage parameters and indexes of the materialized view. Changes to the definition are resolved by
+// re-creating the materialized view (see buildMaterializedViewDiff).
+func (m *materializedViewSQLVertexGenerator) Alter(diff materializedViewDiff) ([]Statement, error) {
+	alterPrefix := fmt.Sprintf("ALTER MATERIALIZED VIEW %s", diff.new.GetFQEscapedName())
+	stmts := alterStorageParametersStatements(alterPrefix, diff.old.StorageParameters, diff.new.StorageParameters)
+
+	oldIndexesByName := buildSchemaObjByNameMap(diff.old.Indexes)
+	newIndexesByName := buildSchemaObjByNameMap(diff.new.Indexes)
+	for _, index := range diff.old.Indexes {
+		if newIndex, ok := newIndexesByName[index.GetName()]; ok && newIndex.GetIndexDefStmt == index.GetIndexDefStmt {
+			if newIndex.Tablespace != index.Tablespace {
+				alterIndexPrefix := fmt.Sprintf("ALTER INDEX %s", newIndex.GetSchemaQualifiedName().GetFQEscapedName())
+				stmts = append(stmts, buildSetTablespaceStatement(alterIndexPrefix, newIndex.Tablespace, false))
+			}
+			stmts = append(stmts, buildCommentStatements("INDEX", newIndex.GetSchemaQualifiedName().GetFQEscapedName(), index.Comment, newIndex.Comment)...)
+			continue
+		}
+		stmts = append(stmts, m.dropIndexStatement(index))
+	}
+	for _, index := range diff.new.Indexes {
+		if oldIndex, ok := oldIndexesByName[index.GetName()]; ok && oldIndex.GetIndexDefStmt == index.GetIndexDefStmt {
+			continue
+		}
+		stmt, err := m.createIndexStatement(index)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+		stmts = append(stmts, buildCommentStatements("INDEX", index.GetSchemaQualifiedName().GetFQEscapedName(), nil, index.Comment)...)
+	}
+
+	return stmts, nil
+}
+
+func (m *materializedViewSQLVertexGenerator) dropIndexStatement(index schema.Index) Statement {
+	if m.nonConcurrentIndexDrops {
+		return Statement{
+			DDL:         fmt.Sprintf("DROP INDEX %s", index.GetSchemaQualifiedName().GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardIndexDroppedAcquiresLock, migrationHazardIndexDroppedQueryPerf},
+		}
+	}
+	return Statement{
+		DDL:                   fmt.Sprintf("DROP INDEX CONCURRENTLY %s", index.GetSchemaQualifiedName().GetFQEscapedName()),
+		Timeout:               statementTimeoutConcurrentIndexDrop,
+		LockTimeout:           lockTimeoutDefault,
+		Hazards:               []MigrationHazard{migrationHazardIndexDroppedQueryPerf},
+		RequiresNoTransaction: true,
+	}
+}
+
+func (m *materializedViewSQLVertexGenerator) createIndexStatement(index schema.Index) (Statement, error) {
+	if m.nonConcurrentIndexOps {
+		createIdxStmt, err := addTablespaceToCreateIndexStmt(string(index.GetIndexDefStmt), index)
+		if err != nil {
+			return Statement{}, fmt.Errorf("adding tablespace to create index statement: %w", err)
+		}
+		return Statement{
+			DDL:         createIdxStmt,
+			Timeout:     statementTimeoutConcurrentIndexBuild,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardIndexBuildNonConcurrently, migrationHazardIndexBuildAcquiresShareLock},
+		}, nil
+	}
+	createIdxStmt, err := index.GetIndexDefStmt.ToCreateIndexConcurrently()
+	if err != nil {
+		return Statement{}, fmt.Errorf("modifying index def statement to concurrently: %w", err)
+	}
+	createIdxStmt, err = addTablespaceToCreateIndexStmt(createIdxStmt, index)
+	if err != nil {
+		return Statement{}, fmt.Errorf("adding tablespace to create index statement: %w", err)
+	}
+	return Statement{
+		DDL:                   createIdxStmt,
+		Timeout:               statementTimeoutConcurrentIndexBuild,
+		LockTimeout:           lockTimeoutDefault,
+		Hazards:               []MigrationHazard{migrationHazardIndexBuildConcurrently},
+		RequiresNoTransaction: true,
+	}, nil
+}
+
+func (m *materializedViewSQLVertexGenerator) GetSQLVertexId(mv schema.MaterializedView, diffType diffType) sqlVertexId {
+	return buildMaterializedViewVertexId(mv.SchemaQualifiedName, diffType)
+}
+
+func buildMaterializedViewVertexId(name schema.SchemaQualifiedName, diffType diffType) sqlVertexId {
+	return buildSchemaObjVertexId("materialized_view", name.GetFQEscapedName(), diffType)
+}
+
+func (m *materializedViewSQLVertexGenerator) GetAddAlterDependencies(newMv, _ schema.MaterializedView) ([]dependency, error) {
+	deps := []dependency{
+		mustRun(m.GetSQLVertexId(newMv, diffTypeAddAlter)).after(m.GetSQLVertexId(newMv, diffTypeDelete)),
+	}
+	for _, table := range newMv.DependsOnTables {
+		deps = append(deps, mustRun(m.GetSQLVertexId(newMv, diffTypeAddAlter)).after(buildTableVertexId(table, diffTypeAddAlter)))
+	}
+	for _, view := range newMv.DependsOnViews {
+		deps = append(deps, mustRun(m.GetSQLVertexId(newMv, diffTypeAddAlter)).after(buildViewVertexId(view, diffTypeAddAlter)))
+	}
+	for _, mv := range newMv.DependsOnMaterializedViews {
+		deps = append(deps, mustRun(m.GetSQLVertexId(newMv, diffTypeAddAlter)).after(buildMaterializedViewVertexId(mv, diffTypeAddAlter)))
+	}
+	for _, index := range newMv.Indexes {
+		for _, operatorClass := range index.DependsOnOperatorClasses {
+			deps = append(deps, mustRun(m.GetSQLVertexId(newMv, diffTypeAddAlter)).after(buildOperatorClassVertexId(operatorClass, diffTypeAddAlter)))
+		}
+	}
+	return deps, nil
+}
+
+func (m *materializedViewSQLVertexGenerator) GetDeleteDependencies(mv schema.MaterializedView) ([]dependency, error) {
+	var deps []dependency
+	// The materialized view must be dropped before the objects it depends on are dropped or altered, since altering
+	// them might involve dropping columns or re-creating them
+	for _, table := range mv.DependsOnTables {
+		deps = append(deps,
+			mustRun(m.GetSQLVertexId(mv, diffTypeDelete)).before(buildTableVertexId(table, diffTypeDelete)),
+			mustRun(m.GetSQLVertexId(mv, diffTypeDelete)).before(buildTableVertexId(table, diffTypeAddAlter)),
+		)
+	}
+	for _, view := range mv.DependsOnViews {
+		deps = append(deps,
+			mustRun(m.GetSQLVertexId(mv, diffTypeDelete)).before(buildViewVertexId(view, diffTypeDelete)),
+			mustRun(m.GetSQLVertexId(mv, diffTypeDelete)).before(buildViewVertexId(view, diffTypeAddAlter)),
+		)
+	}
+	for _, depMv := range mv.DependsOnMaterializedViews {
+		deps = append(deps,
+			mustRun(m.GetSQLVertexId(mv, diffTypeDelete)).before(buildMaterializedViewVertexId(depMv, diffTypeDelete)),
+			mustRun(m.GetSQLVertexId(mv, diffTypeDelete)).before(buildMaterializedViewVertexId(depMv, diffTypeAddAlter)),
+		)
+	}
+	for _, index := range mv.Indexes {
+		for _, operatorClass := range index.DependsOnOperatorClasses {
+			deps = append(deps, mustRun(m.GetSQLVertexId(mv, diffTypeDelete)).before(buildOperatorClassVertexId(operatorClass, diffTypeDelete)))
+		}
+	}
+	return deps, nil
+}
+
+// buildMaterializedViewDiff builds the diff for a materialized view. The definition of a materialized view cannot be
+// altered, so the materialized view is re-created if its definition changes. It is also re-created if any of the
+// operator classes used by its indexes are re-created, since the operator classes cannot be dropped while the indexes
+// exist.
+func buildMaterializedViewDiff(deletedOperatorClassesByName map[string]schema.OperatorClass, old, new schema.MaterializedView) (materializedViewDiff, bool, error) {
+	requiresRecreation := old.Definition != new.Definition
+	for _, index := range new.Indexes {
+		for _, operatorClass := range index.DependsOnOperatorClasses {
+			if _, isDeleted := deletedOperatorClassesByName[operatorClass.GetName()]; isDeleted {
+				requiresRecreation = true
+			}
+		}
+	}
+	return materializedViewDiff{
+		oldAndNew: oldAndNew[schema.MaterializedView]{
+			old: old,
+			new: new,
+		},
+	}, requiresRecreation, nil
+}
+
+// buildRefreshMaterializedViewStatements builds the statements to concurrently refresh the materialized views that are
+// re-created by the migration. A re-created materialized view is populated as soon as its dependencies are migrated,
+// which might be before later statements in the plan modify the data it reads, so it is refreshed at the end of the
+// plan. Only materialized views with a unique index can be refreshed concurrently.
+func buildRefreshMaterializedViewStatements(diffs listDiff[schema.MaterializedView, materializedViewDiff]) []Statement {
+	deletedByName := buildSchemaObjByNameMap(diffs.deletes)
+	var stmts []Statement
+	for _, mv := range diffs.adds {
+		if _, isRecreated := deletedByName[mv.GetName()]; !isRecreated || !mv.HasUniqueIndex() {
+			continue
+		}
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("REFRESH MATERIALIZED VIEW CONCURRENTLY %s", mv.GetFQEscapedName()),
+			Timeout:     statementTimeoutMaterializedViewBuild,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardMaterializedViewRefreshed},
+		})
+	}
+	return stmts
+}
@@ -0,0 +1,103 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestMaterializedViewSQLVertexGenerator_Alter(t *testing.T) {
+	mvName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_mv"`}
+	idIdx := schema.Index{Name: "foobar_mv_id_idx", OwningTable: mvName, IsUnique: true, GetIndexDefStmt: "CREATE UNIQUE INDEX foobar_mv_id_idx ON public.foobar_mv USING btree (id)"}
+	valIdx := schema.Index{Name: "foobar_mv_val_idx", OwningTable: mvName, GetIndexDefStmt: "CREATE INDEX foobar_mv_val_idx ON public.foobar_mv USING btree (val)"}
+
+	stmts, err := (&materializedViewSQLVertexGenerator{}).Alter(materializedViewDiff{oldAndNew: oldAndNew[schema.MaterializedView]{
+		old: schema.MaterializedView{
+			SchemaQualifiedName: mvName,
+			StorageParameters:   map[string]string{"fillfactor": "70", "autovacuum_enabled": "false"},
+			Indexes:             []schema.Index{idIdx},
+		},
+		new: schema.MaterializedView{
+			SchemaQualifiedName: mvName,
+			StorageParameters:   map[string]string{"fillfactor": "80"},
+			Indexes:             []schema.Index{valIdx},
+		},
+	}})
+	require.NoError(t, err)
+
+	var ddl []string
+	for _, stmt := range stmts {
+		ddl = append(ddl, stmt.DDL)
+	}
+	assert.Equal(t, []string{
+		`ALTER MATERIALIZED VIEW "public"."foobar_mv" SET (fillfactor=80)`,
+		`ALTER MATERIALIZED VIEW "public"."foobar_mv" RESET (autovacuum_enabled)`,
+		`DROP INDEX CONCURRENTLY "public"."foobar_mv_id_idx"`,
+		"CREATE INDEX CONCURRENTLY foobar_mv_val_idx ON public.foobar_mv USING btree (val)",
+	}, ddl)
+}
+
+func TestGenerateMigrationStatements_RecreateMaterializedView(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+	table := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}, {Name: "val", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	mvName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_mv"`}
+	buildMaterializedView := func(definition string, indexes ...schema.Index) schema.MaterializedView {
+		return schema.MaterializedView{
+			SchemaQualifiedName: mvName,
+			Definition:          definition,
+			Indexes:             indexes,
+			DependsOnTables:     []schema.SchemaQualifiedName{foobar},
+		}
+	}
+	uniqueIdx := schema.Index{Name: "foobar_mv_id_idx", OwningTable: mvName, IsUnique: true, GetIndexDefStmt: "CREATE UNIQUE INDEX foobar_mv_id_idx ON public.foobar_mv USING btree (id)"}
+	nonUniqueIdx := schema.Index{Name: "foobar_mv_id_idx", OwningTable: mvName, GetIndexDefStmt: "CREATE INDEX foobar_mv_id_idx ON public.foobar_mv USING btree (id)"}
+
+	for _, tc := range []struct {
+		name        string
+		oldMv       schema.MaterializedView
+		newMv       schema.MaterializedView
+		expectedDDL []string
+	}{
+		{
+			name:  "Re-created with a unique index is refreshed",
+			oldMv: buildMaterializedView(" SELECT foobar.id\n   FROM foobar;", uniqueIdx),
+			newMv: buildMaterializedView(" SELECT foobar.id,\n    foobar.val\n   FROM foobar;", uniqueIdx),
+			expectedDDL: []string{
+				`DROP MATERIALIZED VIEW "public"."foobar_mv"`,
+				"CREATE MATERIALIZED VIEW \"public\".\"foobar_mv\" AS  SELECT foobar.id,\n    foobar.val\n   FROM foobar;",
+				"CREATE UNIQUE INDEX foobar_mv_id_idx ON public.foobar_mv USING btree (id)",
+				`REFRESH MATERIALIZED VIEW CONCURRENTLY "public"."foobar_mv"`,
+			},
+		},
+		{
+			name:  "Re-created without a unique index is not refreshed",
+			oldMv: buildMaterializedView(" SELECT foobar.id\n   FROM foobar;", nonUniqueIdx),
+			newMv: buildMaterializedView(" SELECT foobar.id,\n    foobar.val\n   FROM foobar;", nonUniqueIdx),
+			expectedDDL: []string{
+				`DROP MATERIALIZED VIEW "public"."foobar_mv"`,
+				"CREATE MATERIALIZED VIEW \"public\".\"foobar_mv\" AS  SELECT foobar.id,\n    foobar.val\n   FROM foobar;",
+				"CREATE INDEX foobar_mv_id_idx ON public.foobar_mv USING btree (id)",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(
+				schema.Schema{Tables: []schema.Table{table}, MaterializedViews: []schema.MaterializedView{tc.oldMv}},
+				schema.Schema{Tables: []schema.Table{table}, MaterializedViews: []schema.MaterializedView{tc.newMv}},
+				&planOptions{},
+			)
+			require.NoError(t, err)
+			var ddl []string
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	mergeCreateIndexRegex   = regexp.MustCompile(`^CREATE (?:UNIQUE )?INDEX (?:CONCURRENTLY )?(?:IF NOT EXISTS )?(` + identifierPattern + `) ON (?:ONLY )?(` + qualifiedIdentifierPattern + `)`)
+	mergeIndexRegex         = regexp.MustCompile(`^(?:(?:ALTER|DROP) INDEX (?:CONCURRENTLY )?(?:IF EXISTS )?|REINDEX INDEX (?:CONCURRENTLY )?|COMMENT ON INDEX )(` + qualifiedIdentifierPattern + `)`)
+	mergeTableSubObjRegex   = regexp.MustCompile(`(?s)^(?:(?:CREATE (?:OR REPLACE )?|DROP )TRIGGER|(?:CREATE|ALTER|DROP) POLICY|COMMENT ON (?:CONSTRAINT|TRIGGER|POLICY)) .*? ON (` + qualifiedIdentifierPattern + `)`)
+	mergeCommentColumnRegex = regexp.MustCompile(`^COMMENT ON COLUMN (` + qualifiedIdentifierPattern + `)\.` + identifierPattern + ` IS `)
+	mergeReferencesRegex    = regexp.MustCompile(`^ALTER TABLE .* REFERENCES (` + qualifiedIdentifierPattern + `)`)
+	mergeObjectRegex        = regexp.MustCompile(`^(?:CREATE (?:OR REPLACE )?(?:UNLOGGED )?|ALTER |DROP |REFRESH |COMMENT ON )` + mergeObjectTypePattern + ` (?:CONCURRENTLY )?(?:IF (?:NOT )?EXISTS )?(?:ONLY )?(` + qualifiedIdentifierPattern + `)`)
+)
+
+const (
+	// mergeObjectTypePattern matches the types of the objects whose statements are checked for merge conflicts. The
+	// type is captured.
+	mergeObjectTypePattern = `(TABLE|FOREIGN TABLE|VIEW|MATERIALIZED VIEW|SEQUENCE|TYPE|DOMAIN|FUNCTION|PROCEDURE|AGGREGATE|` +
+		`SCHEMA|EXTENSION|COLLATION|STATISTICS|PUBLICATION|EVENT TRIGGER|SERVER|FOREIGN DATA WRAPPER|` +
+		`TEXT SEARCH CONFIGURATION|TEXT SEARCH DICTIONARY)`
+)
+
+// MergeConflict is an object that both plans passed to MergePlans modify
+type MergeConflict struct {
+	// Object is the type and name of the object, e.g., TABLE "public"."foobar"
+	Object string
+	// StatementsA and StatementsB are the indexes of the statements that modify the object in each plan
+	StatementsA []int
+	StatementsB []int
+}
+
+// MergeConflictError is returned by MergePlans if both plans modify the same objects
+type MergeConflictError struct {
+	Conflicts []MergeConflict
+}
+
+func (e MergeConflictError) Error() string {
+	var conflicts []string
+	for _, c := range e.Conflicts {
+		conflicts = append(conflicts, fmt.Sprintf("%s (statements %v of the first plan and %v of the second plan)", c.Object, c.StatementsA, c.StatementsB))
+	}
+	return fmt.Sprintf("plans modify the same objects: %s", strings.Join(conflicts, ", "))
+}
+
+// MergePlans merges two plans generated against the same database, e.g., by two teams that each change different
+// objects, into a single plan. The merged plan contains the statements of a followed by the statements of b, and its
+// dependencies are the union of the dependencies of both plans, so each plan's statements still run in an order that
+// respects its dependencies.
+//
+// The object each statement modifies is derived from its DDL, e.g., ALTER TABLE "public"."foobar" modifies the table
+// "public"."foobar", and an index is modified by the statements that create, alter, or drop it. If both plans modify
+// the same object, a MergeConflictError listing the conflicts is returned, since one plan's statements might not apply
+// after the other's. Advisory statements and statements whose object cannot be derived, e.g., GRANTs, are not checked
+// for conflicts.
+//
+// The plans must have the same CurrentSchemaHash, unless either is empty. Rename candidates are not merged, since they
+// cannot be confirmed on the merged plan.
+func MergePlans(a, b Plan) (Plan, error) {
+	if a.CurrentSchemaHash != "" && b.CurrentSchemaHash != "" && a.CurrentSchemaHash != b.CurrentSchemaHash {
+		return Plan{}, fmt.Errorf("plans were generated against different schemas: current schema hashes %q and %q do not match",
+			a.CurrentSchemaHash, b.CurrentSchemaHash)
+	}
+	if conflicts := findMergeConflicts(a, b); len(conflicts) > 0 {
+		return Plan{}, MergeConflictError{Conflicts: conflicts}
+	}
+
+	merged := Plan{
+		Statements:                  append(append([]Statement(nil), a.Statements...), b.Statements...),
+		CurrentSchemaHash:           a.CurrentSchemaHash,
+		Hazards:                     append(append([]MigrationHazard(nil), a.Hazards...), b.Hazards...),
+		migrationHooks:              append(append([]MigrationHook(nil), a.migrationHooks...), b.migrationHooks...),
+		statementHooks:              append(append([]StatementHook(nil), a.statementHooks...), b.statementHooks...),
+		progressReporter:            a.progressReporter,
+		pgBouncerMode:               a.pgBouncerMode || b.pgBouncerMode,
+		requireHazardAcknowledgment: a.requireHazardAcknowledgment || b.requireHazardAcknowledgment,
+		renderState:                 mergeRenderStates(a.renderState, b.renderState),
+	}
+	if merged.CurrentSchemaHash == "" {
+		merged.CurrentSchemaHash = b.CurrentSchemaHash
+	}
+	if merged.progressReporter == nil {
+		merged.progressReporter = b.progressReporter
+	}
+	// If neither plan has dependencies, each statement of the merged plan is treated as depending on the statement
+	// before it, like in the original plans
+	if a.Dependencies != nil || b.Dependencies != nil {
+		merged.Dependencies = a.getDependencies()
+		for _, dep := range b.getDependencies() {
+			merged.Dependencies = append(merged.Dependencies, StatementDependency{
+				Statement: dep.Statement + len(a.Statements),
+				DependsOn: dep.DependsOn + len(a.Statements),
+			})
+		}
+		if merged.Dependencies == nil {
+			merged.Dependencies = []StatementDependency{}
+		}
+	}
+	return merged, nil
+}
+
+// findMergeConflicts returns the objects that both plans modify, sorted by object
+func findMergeConflicts(a, b Plan) []MergeConflict {
+	stmtIdxsByObjectA := getStatementIdxsByModifiedObject(a)
+	stmtIdxsByObjectB := getStatementIdxsByModifiedObject(b)
+	var conflicts []MergeConflict
+	for object, stmtIdxsA := range stmtIdxsByObjectA {
+		if stmtIdxsB, ok := stmtIdxsByObjectB[object]; ok {
+			conflicts = append(conflicts, MergeConflict{Object: object, StatementsA: stmtIdxsA, StatementsB: stmtIdxsB})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Object < conflicts[j].Object
+	})
+	return conflicts
+}
+
+func getStatementIdxsByModifiedObject(p Plan) map[string][]int {
+	stmtIdxsByObject := make(map[string][]int)
+	for i, stmt := range p.Statements {
+		if stmt.IsAdvisory {
+			continue
+		}
+		for _, object := range getModifiedObjects(stmt.DDL) {
+			stmtIdxsByObject[object] = append(stmtIdxsByObject[object], i)
+		}
+	}
+	return stmtIdxsByObject
+}
+
+// getModifiedObjects returns the types and normalized names of the objects the statement modifies, e.g.,
+// TABLE "public"."foobar". Statements on the sub-objects of a table, e.g., triggers, policies, and column comments,
+// modify the table. Creating an index modifies both the index and its table, and adding a foreign key modifies both
+// the table and the table it references, since neither statement applies if the other plan drops the table. The
+// owning table of a statement that alters or drops an existing index cannot be derived from its DDL, so only the
+// index is returned.
+func getModifiedObjects(ddl string) []string {
+	ddl = unwrapIdempotentSQL(ddl)
+	if match := mergeCreateIndexRegex.FindStringSubmatch(ddl); match != nil {
+		indexName, ok := qualifyIndexName(match[1], match[2])
+		if !ok {
+			indexName = match[1]
+		}
+		return []string{buildMergeObjectKey("INDEX", indexName), buildMergeObjectKey("TABLE", match[2])}
+	}
+	if match := mergeIndexRegex.FindStringSubmatch(ddl); match != nil {
+		return []string{buildMergeObjectKey("INDEX", match[1])}
+	}
+	for _, regex := range []*regexp.Regexp{mergeTableSubObjRegex, mergeCommentColumnRegex} {
+		if match := regex.FindStringSubmatch(ddl); match != nil {
+			return []string{buildMergeObjectKey("TABLE", match[1])}
+		}
+	}
+	if match := mergeObjectRegex.FindStringSubmatch(ddl); match != nil {
+		objects := []string{buildMergeObjectKey(match[1], match[2])}
+		if referencesMatch := mergeReferencesRegex.FindStringSubmatch(ddl); referencesMatch != nil {
+			referencedTableName := referencesMatch[1]
+			if len(splitQualifiedIdentifier(referencedTableName)) == 1 {
+				// Postgres does not qualify the referenced table in constraint definitions if it is on the search path
+				referencedTableName = "public." + referencedTableName
+			}
+			if referencedTable := buildMergeObjectKey("TABLE", referencedTableName); referencedTable != objects[0] {
+				objects = append(objects, referencedTable)
+			}
+		}
+		return objects
+	}
+	return nil
+}
+
+func buildMergeObjectKey(objectType, name string) string {
+	return fmt.Sprintf("%s %s", objectType, normalizeQualifiedIdentifier(name))
+}
@@ -0,0 +1,141 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePlans(t *testing.T) {
+	a := Plan{
+		Statements: []Statement{
+			{DDL: `ALTER TABLE "public"."foobar" ADD COLUMN "bar" text`},
+			{DDL: `CREATE INDEX CONCURRENTLY foobar_bar_idx ON public.foobar USING btree (bar)`, RequiresNoTransaction: true},
+			{DDL: `ANALYZE "public"."foobar"`, IsAdvisory: true},
+		},
+		CurrentSchemaHash: "some-hash",
+		Dependencies: []StatementDependency{
+			{Statement: 1, DependsOn: 0},
+			{Statement: 2, DependsOn: 0},
+		},
+	}
+	b := Plan{
+		Statements: []Statement{
+			{DDL: `CREATE TABLE "public"."other" (` + "\n" + `	"id" integer NOT NULL` + "\n" + `)`},
+			{DDL: `ALTER TABLE "public"."other" ADD CONSTRAINT "other_pkey" PRIMARY KEY (id)`},
+			{DDL: `ANALYZE "public"."foobar"`, IsAdvisory: true},
+		},
+		CurrentSchemaHash: "some-hash",
+	}
+
+	t.Run("Independent plans", func(t *testing.T) {
+		merged, err := MergePlans(a, b)
+		require.NoError(t, err)
+		assert.Len(t, merged.Statements, len(a.Statements)+len(b.Statements))
+		assert.Equal(t, append(append([]Statement(nil), a.Statements...), b.Statements...), merged.Statements)
+		assert.Equal(t, "some-hash", merged.CurrentSchemaHash)
+		// b has no dependencies, so each of its statements depends on the one before it
+		assert.Equal(t, []StatementDependency{
+			{Statement: 1, DependsOn: 0},
+			{Statement: 2, DependsOn: 0},
+			{Statement: 4, DependsOn: 3},
+			{Statement: 5, DependsOn: 4},
+		}, merged.Dependencies)
+		for _, dep := range merged.Dependencies {
+			assert.Greater(t, dep.Statement, dep.DependsOn)
+		}
+	})
+
+	t.Run("Plans without dependencies", func(t *testing.T) {
+		merged, err := MergePlans(b, Plan{Statements: []Statement{{DDL: `CREATE SCHEMA "foo"`}}})
+		require.NoError(t, err)
+		assert.Len(t, merged.Statements, 4)
+		assert.Equal(t, "some-hash", merged.CurrentSchemaHash)
+		assert.Nil(t, merged.Dependencies)
+	})
+
+	t.Run("Plans modifying the same table conflict", func(t *testing.T) {
+		conflicting := Plan{
+			Statements: []Statement{
+				{DDL: `ALTER TABLE public.foobar ADD COLUMN "baz" integer`},
+				{DDL: `CREATE INDEX foobar_baz_idx ON "public"."foobar" USING btree (baz)`},
+				{DDL: `CREATE OR REPLACE TRIGGER "some_trigger" BEFORE UPDATE ON "public"."other" FOR EACH ROW EXECUTE FUNCTION increment_version()`},
+			},
+		}
+		_, err := MergePlans(conflicting, b)
+		var conflictErr MergeConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, []MergeConflict{
+			{Object: `TABLE "public"."other"`, StatementsA: []int{2}, StatementsB: []int{0, 1}},
+		}, conflictErr.Conflicts)
+
+		_, err = MergePlans(a, conflicting)
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, []MergeConflict{
+			{Object: `TABLE "public"."foobar"`, StatementsA: []int{0, 1}, StatementsB: []int{0, 1}},
+		}, conflictErr.Conflicts)
+		assert.EqualError(t, err, `plans modify the same objects: TABLE "public"."foobar" (statements [0 1] of the first plan and [0 1] of the second plan)`)
+	})
+
+	t.Run("Dropping a table conflicts with creating an index on it", func(t *testing.T) {
+		_, err := MergePlans(
+			Plan{Statements: []Statement{{DDL: `DROP TABLE "public"."foobar"`}}},
+			Plan{Statements: []Statement{{DDL: `CREATE INDEX CONCURRENTLY foobar_val_idx ON public.foobar USING btree (val)`, RequiresNoTransaction: true}}},
+		)
+		var conflictErr MergeConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, []MergeConflict{
+			{Object: `TABLE "public"."foobar"`, StatementsA: []int{0}, StatementsB: []int{0}},
+		}, conflictErr.Conflicts)
+	})
+
+	t.Run("Dropping a table conflicts with adding a foreign key that references it", func(t *testing.T) {
+		_, err := MergePlans(
+			Plan{Statements: []Statement{{DDL: `DROP TABLE "public"."foobar"`}}},
+			Plan{Statements: []Statement{{DDL: `ALTER TABLE "public"."other" ADD CONSTRAINT "other_foobar_fk" FOREIGN KEY (foobar_id) REFERENCES foobar(id) NOT VALID`}}},
+		)
+		var conflictErr MergeConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, []MergeConflict{
+			{Object: `TABLE "public"."foobar"`, StatementsA: []int{0}, StatementsB: []int{0}},
+		}, conflictErr.Conflicts)
+	})
+
+	t.Run("Plans generated against different schemas", func(t *testing.T) {
+		_, err := MergePlans(a, Plan{CurrentSchemaHash: "other-hash"})
+		assert.ErrorContains(t, err, "plans were generated against different schemas")
+	})
+}
+
+func TestGetModifiedObjects(t *testing.T) {
+	for _, tc := range []struct {
+		ddl             string
+		expectedObjects []string
+	}{
+		{ddl: `CREATE TABLE "public"."foobar" ("id" integer)`, expectedObjects: []string{`TABLE "public"."foobar"`}},
+		{ddl: `ALTER TABLE ONLY public.foobar DROP COLUMN "bar"`, expectedObjects: []string{`TABLE "public"."foobar"`}},
+		{ddl: `DROP TABLE "public"."foobar"`, expectedObjects: []string{`TABLE "public"."foobar"`}},
+		{ddl: `CREATE UNIQUE INDEX CONCURRENTLY foobar_idx ON public.foobar USING btree (id)`, expectedObjects: []string{`INDEX "public"."foobar_idx"`, `TABLE "public"."foobar"`}},
+		{ddl: `DROP INDEX CONCURRENTLY "public"."foobar_idx"`, expectedObjects: []string{`INDEX "public"."foobar_idx"`}},
+		{ddl: `COMMENT ON COLUMN "public"."foobar"."id" IS 'The id'`, expectedObjects: []string{`TABLE "public"."foobar"`}},
+		{ddl: `DROP POLICY "some_policy" ON "public"."foobar"`, expectedObjects: []string{`TABLE "public"."foobar"`}},
+		{ddl: "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT a + b $function$\n", expectedObjects: []string{`FUNCTION "public"."add"`}},
+		{ddl: `CREATE MATERIALIZED VIEW "public"."foobar_mv" AS SELECT 1`, expectedObjects: []string{`MATERIALIZED VIEW "public"."foobar_mv"`}},
+		{ddl: `CREATE SCHEMA "foo"`, expectedObjects: []string{`SCHEMA "foo"`}},
+		{ddl: `CREATE EXTENSION IF NOT EXISTS "pg_trgm" WITH SCHEMA "public"`, expectedObjects: []string{`EXTENSION "pg_trgm"`}},
+		{
+			ddl:             `ALTER TABLE "public"."other" ADD CONSTRAINT "other_foobar_fk" FOREIGN KEY (foobar_id) REFERENCES "schema_1"."foobar"(id)`,
+			expectedObjects: []string{`TABLE "public"."other"`, `TABLE "schema_1"."foobar"`},
+		},
+		{
+			ddl:             "DO $pgschemadiff_idempotent$\nBEGIN\n\tCREATE TYPE \"public\".\"color\" AS ENUM ('red');\nEXCEPTION WHEN duplicate_table OR duplicate_object OR duplicate_function THEN NULL;\nEND\n$pgschemadiff_idempotent$",
+			expectedObjects: []string{`TYPE "public"."color"`},
+		},
+		{ddl: `GRANT SELECT ON "public"."foobar" TO "some_role"`},
+	} {
+		t.Run(tc.ddl, func(t *testing.T) {
+			assert.Equal(t, tc.expectedObjects, getModifiedObjects(tc.ddl))
+		})
+	}
+}
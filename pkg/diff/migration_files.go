@@ -0,0 +1,187 @@
+package diff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	liquibaseChangelogNamespace      = "http://www.liquibase.org/xml/ns/dbchangelog"
+	liquibaseChangelogSchemaLocation = liquibaseChangelogNamespace + " " + liquibaseChangelogNamespace + "/dbchangelog-latest.xsd"
+	liquibaseChangeSetAuthor         = "pg-schema-diff"
+)
+
+var (
+	// flywayVersionRegex matches the versions Flyway accepts, e.g., 1, 1.2, or 2024_01_01
+	flywayVersionRegex = regexp.MustCompile(`^\d+(?:[._]\d+)*$`)
+)
+
+type (
+	liquibaseChangelog struct {
+		XMLName        xml.Name             `xml:"databaseChangeLog"`
+		Namespace      string               `xml:"xmlns,attr"`
+		XSINamespace   string               `xml:"xmlns:xsi,attr"`
+		SchemaLocation string               `xml:"xsi:schemaLocation,attr"`
+		ChangeSets     []liquibaseChangeSet `xml:"changeSet"`
+	}
+
+	liquibaseChangeSet struct {
+		ID               string             `xml:"id,attr"`
+		Author           string             `xml:"author,attr"`
+		RunInTransaction bool               `xml:"runInTransaction,attr"`
+		SQL              liquibaseSQL       `xml:"sql"`
+		Rollback         *liquibaseRollback `xml:"rollback"`
+	}
+
+	liquibaseSQL struct {
+		SplitStatements bool   `xml:"splitStatements,attr"`
+		StripComments   bool   `xml:"stripComments,attr"`
+		SQL             string `xml:",chardata"`
+	}
+
+	// liquibaseRollback is the rollback of a change set. If it has no statements, rolling back the change set is a
+	// no-op, e.g., because rolling back an earlier change set drops the table the change set alters.
+	liquibaseRollback struct {
+		SQL []liquibaseSQL `xml:"sql"`
+	}
+)
+
+// ExportFlywayMigration writes the plan as a Flyway versioned migration. The file should be named
+// V<version>__<description>.sql; the version must consist of numbers separated by dots or underscores, e.g., 1.2 or
+// 2024_01_01. Statements are separated by comments with their index and hazards and preceded by `SET` statements that
+// apply their timeouts, like ExportAtlasHCL. Advisory statements are only written as comments.
+//
+// If any statement cannot run in a transaction, e.g., `CREATE INDEX CONCURRENTLY`, the migration must be configured
+// with executeInTransaction=false in a V<version>__<description>.sql.conf script config file, which the header of the
+// file points out.
+func (p Plan) ExportFlywayMigration(w io.Writer, version string) error {
+	if !flywayVersionRegex.MatchString(version) {
+		return fmt.Errorf("invalid Flyway version %q: it must consist of numbers separated by dots or underscores", version)
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("-- Flyway migration V%s. Name this file V%s__<description>.sql\n", version, version))
+	if p.hasNoTransactionStatements() {
+		sb.WriteString(fmt.Sprintf("-- This migration contains statements that cannot run in a transaction. Set executeInTransaction=false in V%s__<description>.sql.conf\n", version))
+	}
+	sb.WriteString("\n")
+	writeSQLMigrationStatements(&sb, p.Statements)
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("writing Flyway migration: %w", err)
+	}
+	return nil
+}
+
+// ExportLiquibaseChangelog writes the plan as a Liquibase XML changelog. Each statement is a change set with a single
+// `<sql>` change, identified by <id>-<statement number>, such that statements that cannot run in a transaction, e.g.,
+// `CREATE INDEX CONCURRENTLY`, can run in their own change set. The hazards of each statement are written as SQL
+// comments in its `<sql>` change. Advisory statements are omitted, since they are not executed as part of the migration.
+//
+// Change sets have a `<rollback>` if the statement can be reversed, using the same rollback statements as
+// GenerateRollback. The timeouts of the statements are not applied, since Liquibase executes each change as a single
+// statement.
+func (p Plan) ExportLiquibaseChangelog(w io.Writer, id string) error {
+	if id == "" {
+		return fmt.Errorf("id must not be empty")
+	}
+
+	changelog := liquibaseChangelog{
+		Namespace:      liquibaseChangelogNamespace,
+		XSINamespace:   "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: liquibaseChangelogSchemaLocation,
+	}
+	rg := newRollbackGenerator(p.Statements)
+	for i, stmt := range p.Statements {
+		if stmt.IsAdvisory {
+			continue
+		}
+		sb := strings.Builder{}
+		writeHazardComments(&sb, stmt.Hazards)
+		sb.WriteString(strings.TrimRight(stmt.DDL, " \t\n;"))
+
+		changeSet := liquibaseChangeSet{
+			ID:               fmt.Sprintf("%s-%d", id, i+1),
+			Author:           liquibaseChangeSetAuthor,
+			RunInTransaction: !stmt.RequiresNoTransaction,
+			SQL:              liquibaseSQL{SQL: sb.String()},
+		}
+		rollbackStmts, ok, err := rg.buildRollbackStatements(stmt)
+		if err != nil {
+			return fmt.Errorf("generating rollback of %q: %w", stmt.DDL, err)
+		}
+		if ok {
+			changeSet.Rollback = &liquibaseRollback{}
+			for _, rollbackStmt := range rollbackStmts {
+				changeSet.Rollback.SQL = append(changeSet.Rollback.SQL, liquibaseSQL{SQL: rollbackStmt.DDL})
+			}
+		}
+		changelog.ChangeSets = append(changelog.ChangeSets, changeSet)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing Liquibase changelog: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(changelog); err != nil {
+		return fmt.Errorf("writing Liquibase changelog: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("writing Liquibase changelog: %w", err)
+	}
+	return nil
+}
+
+func (p Plan) hasNoTransactionStatements() bool {
+	for _, stmt := range p.Statements {
+		if stmt.RequiresNoTransaction && !stmt.IsAdvisory {
+			return true
+		}
+	}
+	return false
+}
+
+// writeSQLMigrationStatements writes the statements to a SQL migration file. Each statement is preceded by a comment
+// with its index and hazards and the `SET` statements that apply its timeouts, which are reset at the end of the file.
+// Advisory statements are written as comments.
+func writeSQLMigrationStatements(sb *strings.Builder, stmts []Statement) {
+	setsTimeouts := false
+	for i, stmt := range stmts {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("-- Statement %d\n", i+1))
+		ddl := strings.TrimRight(stmt.DDL, " \t\n;") + ";"
+		if stmt.IsAdvisory {
+			sb.WriteString("-- Advisory: This statement is not executed as part of the migration\n")
+			for _, line := range strings.Split(ddl, "\n") {
+				sb.WriteString(strings.TrimRight("-- "+line, " ") + "\n")
+			}
+			continue
+		}
+		writeHazardComments(sb, stmt.Hazards)
+		if stmt.Timeout > 0 {
+			sb.WriteString(fmt.Sprintf("SET SESSION statement_timeout = %d;\n", stmt.Timeout.Milliseconds()))
+			setsTimeouts = true
+		}
+		if stmt.LockTimeout > 0 {
+			sb.WriteString(fmt.Sprintf("SET SESSION lock_timeout = %d;\n", stmt.LockTimeout.Milliseconds()))
+			setsTimeouts = true
+		}
+		sb.WriteString(ddl + "\n")
+	}
+	if setsTimeouts {
+		// The migration tool might reuse the connection, so the timeouts must not outlive the migration
+		sb.WriteString("\nRESET statement_timeout;\nRESET lock_timeout;\n")
+	}
+}
+
+func writeHazardComments(sb *strings.Builder, hazards []MigrationHazard) {
+	for _, hazard := range hazards {
+		sb.WriteString(fmt.Sprintf("-- Hazard %s: %s\n", hazard.Type, strings.ReplaceAll(hazard.Message, "\n", " ")))
+	}
+}
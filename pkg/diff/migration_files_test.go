@@ -0,0 +1,169 @@
+package diff
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var migrationFilesTestPlan = Plan{
+	Statements: []Statement{
+		{DDL: `CREATE TABLE "public"."foobar" (` + "\n" + `	"id" integer NOT NULL` + "\n" + `)`, Timeout: 3 * time.Second, LockTimeout: 3 * time.Second},
+		{
+			DDL:                   `CREATE INDEX CONCURRENTLY foobar_id_idx ON public.foobar USING btree (id)`,
+			Timeout:               20 * time.Minute,
+			LockTimeout:           3 * time.Second,
+			RequiresNoTransaction: true,
+			Hazards:               []MigrationHazard{{Type: MigrationHazardTypeIndexBuild, Message: "This might affect performance"}},
+		},
+		{
+			DDL:         "CREATE OR REPLACE FUNCTION public.one()\n RETURNS integer\n LANGUAGE plpgsql\nAS $function$ BEGIN RETURN 1; END; $function$\n",
+			Timeout:     3 * time.Second,
+			LockTimeout: 3 * time.Second,
+		},
+		{
+			DDL:     `ALTER TABLE "public"."other" DROP COLUMN "bar"`,
+			Timeout: 3 * time.Second,
+			Hazards: []MigrationHazard{{Type: MigrationHazardTypeDeletesData, Message: "Deletes all values in the column"}},
+		},
+		{DDL: `ANALYZE "public"."other"`, IsAdvisory: true},
+	},
+}
+
+func TestPlan_ExportFlywayMigration(t *testing.T) {
+	sb := strings.Builder{}
+	require.NoError(t, migrationFilesTestPlan.ExportFlywayMigration(&sb, "2024.01.1"))
+	migration := sb.String()
+	assert.True(t, strings.HasPrefix(migration, "-- Flyway migration V2024.01.1. Name this file V2024.01.1__<description>.sql\n"+
+		"-- This migration contains statements that cannot run in a transaction. Set executeInTransaction=false in V2024.01.1__<description>.sql.conf\n"))
+	assert.Contains(t, migration, "-- Statement 4\n-- Hazard DELETES_DATA: Deletes all values in the column\nSET SESSION statement_timeout = 3000;\n")
+
+	assert.Equal(t, []string{
+		"SET SESSION statement_timeout = 3000",
+		"SET SESSION lock_timeout = 3000",
+		"CREATE TABLE \"public\".\"foobar\" (\n\t\"id\" integer NOT NULL\n)",
+		"SET SESSION statement_timeout = 1200000",
+		"SET SESSION lock_timeout = 3000",
+		"CREATE INDEX CONCURRENTLY foobar_id_idx ON public.foobar USING btree (id)",
+		"SET SESSION statement_timeout = 3000",
+		"SET SESSION lock_timeout = 3000",
+		"CREATE OR REPLACE FUNCTION public.one()\n RETURNS integer\n LANGUAGE plpgsql\nAS $function$ BEGIN RETURN 1; END; $function$",
+		"SET SESSION statement_timeout = 3000",
+		`ALTER TABLE "public"."other" DROP COLUMN "bar"`,
+		"RESET statement_timeout",
+		"RESET lock_timeout",
+	}, scanSQLStatements(migration))
+
+	assert.ErrorContains(t, migrationFilesTestPlan.ExportFlywayMigration(&sb, "1.a"), `invalid Flyway version "1.a"`)
+}
+
+func TestPlan_ExportLiquibaseChangelog(t *testing.T) {
+	sb := strings.Builder{}
+	require.NoError(t, migrationFilesTestPlan.ExportLiquibaseChangelog(&sb, "add-foobar"))
+	assert.True(t, strings.HasPrefix(sb.String(), xml.Header+`<databaseChangeLog xmlns="http://www.liquibase.org/xml/ns/dbchangelog"`))
+
+	// The changelog should be well-formed XML
+	decoder := xml.NewDecoder(strings.NewReader(sb.String()))
+	for {
+		_, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+	}
+
+	var changelog liquibaseChangelog
+	require.NoError(t, xml.Unmarshal([]byte(sb.String()), &changelog))
+	assert.Equal(t, []liquibaseChangeSet{
+		{
+			ID:               "add-foobar-1",
+			Author:           "pg-schema-diff",
+			RunInTransaction: true,
+			SQL:              liquibaseSQL{SQL: "CREATE TABLE \"public\".\"foobar\" (\n\t\"id\" integer NOT NULL\n)"},
+			Rollback:         &liquibaseRollback{SQL: []liquibaseSQL{{SQL: `DROP TABLE "public"."foobar"`}}},
+		},
+		{
+			ID:               "add-foobar-2",
+			Author:           "pg-schema-diff",
+			RunInTransaction: false,
+			SQL:              liquibaseSQL{SQL: "-- Hazard INDEX_BUILD: This might affect performance\nCREATE INDEX CONCURRENTLY foobar_id_idx ON public.foobar USING btree (id)"},
+			// Dropping the table drops the index, so rolling back the index is a no-op
+			Rollback: &liquibaseRollback{},
+		},
+		{
+			ID:               "add-foobar-3",
+			Author:           "pg-schema-diff",
+			RunInTransaction: true,
+			SQL:              liquibaseSQL{SQL: "CREATE OR REPLACE FUNCTION public.one()\n RETURNS integer\n LANGUAGE plpgsql\nAS $function$ BEGIN RETURN 1; END; $function$"},
+		},
+		{
+			ID:               "add-foobar-4",
+			Author:           "pg-schema-diff",
+			RunInTransaction: true,
+			SQL:              liquibaseSQL{SQL: "-- Hazard DELETES_DATA: Deletes all values in the column\nALTER TABLE \"public\".\"other\" DROP COLUMN \"bar\""},
+		},
+	}, changelog.ChangeSets)
+
+	assert.ErrorContains(t, migrationFilesTestPlan.ExportLiquibaseChangelog(&sb, ""), "id must not be empty")
+
+	t.Run("Idempotent SQL", func(t *testing.T) {
+		idempotentPlan := Plan{Statements: make([]Statement, len(migrationFilesTestPlan.Statements))}
+		for i, stmt := range migrationFilesTestPlan.Statements {
+			idempotentPlan.Statements[i] = makeStatementIdempotent(stmt)
+		}
+		sb := strings.Builder{}
+		require.NoError(t, idempotentPlan.ExportLiquibaseChangelog(&sb, "add-foobar"))
+		var changelog liquibaseChangelog
+		require.NoError(t, xml.Unmarshal([]byte(sb.String()), &changelog))
+		require.Len(t, changelog.ChangeSets, 4)
+		assert.Equal(t, "CREATE TABLE IF NOT EXISTS \"public\".\"foobar\" (\n\t\"id\" integer NOT NULL\n)", changelog.ChangeSets[0].SQL.SQL)
+		// The guard is not mistaken for the name of the table
+		assert.Equal(t, &liquibaseRollback{SQL: []liquibaseSQL{{SQL: `DROP TABLE "public"."foobar"`}}}, changelog.ChangeSets[0].Rollback)
+		assert.Equal(t, &liquibaseRollback{}, changelog.ChangeSets[1].Rollback)
+		assert.Nil(t, changelog.ChangeSets[2].Rollback)
+		assert.Nil(t, changelog.ChangeSets[3].Rollback)
+	})
+}
+
+// scanSQLStatements splits a SQL migration file into its statements, like Flyway's SQL scanner: comments are skipped,
+// and semicolons in string literals and dollar-quoted strings do not end a statement
+func scanSQLStatements(sql string) []string {
+	var stmts []string
+	sb := strings.Builder{}
+	for i := 0; i < len(sql); {
+		switch {
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end == -1 {
+				end = len(sql) - i
+			}
+			i += end
+		case sql[i] == '\'' || sql[i] == '"':
+			end := findClosingQuote(sql, i)
+			sb.WriteString(sql[i:end])
+			i = end
+		case sql[i] == '$' && dollarQuoteTag(sql[i:]) != "":
+			tag := dollarQuoteTag(sql[i:])
+			end := strings.Index(sql[i+len(tag):], tag) + i + 2*len(tag)
+			sb.WriteString(sql[i:end])
+			i = end
+		case sql[i] == ';':
+			stmts = append(stmts, strings.TrimSpace(sb.String()))
+			sb.Reset()
+			i++
+		default:
+			sb.WriteByte(sql[i])
+			i++
+		}
+	}
+	if strings.TrimSpace(sb.String()) != "" {
+		stmts = append(stmts, strings.TrimSpace(sb.String()))
+	}
+	return stmts
+}
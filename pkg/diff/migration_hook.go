@@ -0,0 +1,132 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MigrationHook injects custom logic around the execution of a plan, e.g., disabling triggers, pausing queues, or
+// updating monitoring.
+//
+// Hooks run outside any migration transaction: they are not given the connection the plan is executed on, and any
+// changes they make to the database are not rolled back if the migration fails.
+type MigrationHook interface {
+	// BeforeMigration is called before any of the plan's statements are executed. If it returns an error, the
+	// migration is aborted before any statements are executed.
+	BeforeMigration(ctx context.Context, plan Plan) error
+	// AfterMigration is called after the plan is executed. err is the error that the migration failed with, or nil if
+	// it succeeded. It is also called if the migration is aborted by the BeforeMigration of a hook registered after it,
+	// such that it can undo what its BeforeMigration did.
+	AfterMigration(ctx context.Context, plan Plan, err error) error
+}
+
+// WithMigrationHooks configures the plan to run the hooks around its execution. The hooks are run by
+// Plan.RunWithMigrationHooks.
+func WithMigrationHooks(hooks ...MigrationHook) PlanOpt {
+	return func(opts *planOptions) {
+		opts.migrationHooks = append(opts.migrationHooks, hooks...)
+	}
+}
+
+// AddMigrationHooks adds hooks to run around the plan's execution, e.g., after deserializing a plan. The hooks are run
+// by RunWithMigrationHooks after the hooks already added to the plan.
+func (p Plan) AddMigrationHooks(hooks ...MigrationHook) Plan {
+	p.migrationHooks = append(append([]MigrationHook(nil), p.migrationHooks...), hooks...)
+	return p
+}
+
+// RunWithMigrationHooks executes the plan via the provided execute function, e.g., your own plan executor, and runs
+// the plan's hooks around it. The BeforeMigration of each hook is called in the order the hooks were added, and the
+// AfterMigration of each hook is called in the reverse order.
+//
+// If the BeforeMigration of a hook returns an error, the plan is not executed, and the AfterMigration of the hooks
+// whose BeforeMigration already ran are called with the error. The AfterMigration of every hook is called, even if the
+// AfterMigration of another hook returns an error.
+func (p Plan) RunWithMigrationHooks(ctx context.Context, execute func(ctx context.Context, plan Plan) error) error {
+	var migrationErr error
+	hooksRunBefore := 0
+	for i, hook := range p.migrationHooks {
+		if err := hook.BeforeMigration(ctx, p); err != nil {
+			migrationErr = fmt.Errorf("running before migration hook %d: %w", i, err)
+			break
+		}
+		hooksRunBefore++
+	}
+
+	if migrationErr == nil {
+		migrationErr = execute(ctx, p)
+	}
+
+	errs := []error{migrationErr}
+	for i := hooksRunBefore - 1; i >= 0; i-- {
+		if err := p.migrationHooks[i].AfterMigration(ctx, p, migrationErr); err != nil {
+			errs = append(errs, fmt.Errorf("running after migration hook %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StatementHook injects custom logic around the execution of each statement of a plan, e.g., emitting metrics, writing
+// audit logs, or aborting the migration if replication lag exceeds a threshold.
+type StatementHook interface {
+	// BeforeStatement is called before the statement at index is executed. If it returns an error, the statement is not
+	// executed.
+	BeforeStatement(ctx context.Context, stmt Statement, index int) error
+	// AfterStatement is called after the statement at index is executed, even if it failed. err is the error that the
+	// statement failed with, or nil if it succeeded. It is also called if the statement is not executed because the
+	// BeforeStatement of a hook registered after it returned an error.
+	AfterStatement(ctx context.Context, stmt Statement, index int, duration time.Duration, err error)
+}
+
+// WithStatementHooks configures the plan to run the hooks around the execution of each of its statements. The hooks are
+// run by Plan.RunStatementWithHooks.
+func WithStatementHooks(hooks ...StatementHook) PlanOpt {
+	return func(opts *planOptions) {
+		opts.statementHooks = append(opts.statementHooks, hooks...)
+	}
+}
+
+// AddStatementHooks adds hooks to run around the execution of each of the plan's statements, e.g., after deserializing
+// a plan. The hooks are run by RunStatementWithHooks after the hooks already added to the plan.
+func (p Plan) AddStatementHooks(hooks ...StatementHook) Plan {
+	p.statementHooks = append(append([]StatementHook(nil), p.statementHooks...), hooks...)
+	return p
+}
+
+// RunStatementWithHooks executes the plan's statement at index via the provided execute function and runs the plan's
+// statement hooks around it. It should be called by your plan executor for each statement, in order. The hooks are
+// called synchronously: the BeforeStatement of each hook is called in the order the hooks were added, and the
+// AfterStatement of each hook is called in the reverse order.
+//
+// If the BeforeStatement of a hook returns an error, the statement is not executed, and the AfterStatement of the hooks
+// whose BeforeStatement already ran are called with the error.
+func (p Plan) RunStatementWithHooks(ctx context.Context, index int, execute func(ctx context.Context, stmt Statement) error) error {
+	if index < 0 || index >= len(p.Statements) {
+		return fmt.Errorf("statement index %d out of range for %d statements", index, len(p.Statements))
+	}
+	stmt := p.Statements[index]
+
+	var stmtErr error
+	hooksRunBefore := 0
+	for i, hook := range p.statementHooks {
+		if err := hook.BeforeStatement(ctx, stmt, index); err != nil {
+			stmtErr = fmt.Errorf("running before statement hook %d: %w", i, err)
+			break
+		}
+		hooksRunBefore++
+	}
+
+	var duration time.Duration
+	if stmtErr == nil {
+		start := time.Now()
+		stmtErr = execute(ctx, stmt)
+		duration = time.Since(start)
+	}
+
+	for i := hooksRunBefore - 1; i >= 0; i-- {
+		p.statementHooks[i].AfterStatement(ctx, stmt, index, duration, stmtErr)
+	}
+	return stmtErr
+}
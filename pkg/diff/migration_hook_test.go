@@ -0,0 +1,248 @@
+package diff_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+)
+
+type recordingMigrationHook struct {
+	name      string
+	calls     *[]string
+	beforeErr error
+	afterErr  error
+
+	beforePlan   *diff.Plan
+	migrationErr error
+}
+
+func (r *recordingMigrationHook) BeforeMigration(_ context.Context, plan diff.Plan) error {
+	*r.calls = append(*r.calls, "before "+r.name)
+	r.beforePlan = &plan
+	return r.beforeErr
+}
+
+func (r *recordingMigrationHook) AfterMigration(_ context.Context, _ diff.Plan, err error) error {
+	*r.calls = append(*r.calls, "after "+r.name)
+	r.migrationErr = err
+	return r.afterErr
+}
+
+func TestPlan_RunWithMigrationHooks(t *testing.T) {
+	plan := diff.Plan{
+		Statements:        []diff.Statement{{DDL: "statement 1"}},
+		CurrentSchemaHash: "some-hash",
+	}
+
+	for _, tc := range []struct {
+		name                string
+		beforeErrs          []error
+		afterErrs           []error
+		executeErr          error
+		expectedCalls       []string
+		expectedErrContains []string
+	}{
+		{
+			name:          "no hooks",
+			beforeErrs:    nil,
+			expectedCalls: []string{"execute"},
+		},
+		{
+			name:       "hooks run in order before and in reverse order after",
+			beforeErrs: []error{nil, nil, nil},
+			afterErrs:  []error{nil, nil, nil},
+			expectedCalls: []string{
+				"before hook_0", "before hook_1", "before hook_2",
+				"execute",
+				"after hook_2", "after hook_1", "after hook_0",
+			},
+		},
+		{
+			name:       "before hook error aborts migration",
+			beforeErrs: []error{nil, fmt.Errorf("queue not paused"), nil},
+			afterErrs:  []error{nil, nil, nil},
+			expectedCalls: []string{
+				"before hook_0", "before hook_1",
+				"after hook_0",
+			},
+			expectedErrContains: []string{"running before migration hook 1", "queue not paused"},
+		},
+		{
+			name:       "execute error is passed to after hooks",
+			beforeErrs: []error{nil, nil},
+			afterErrs:  []error{nil, nil},
+			executeErr: fmt.Errorf("statement failed"),
+			expectedCalls: []string{
+				"before hook_0", "before hook_1",
+				"execute",
+				"after hook_1", "after hook_0",
+			},
+			expectedErrContains: []string{"statement failed"},
+		},
+		{
+			name:       "after hook errors do not prevent other after hooks",
+			beforeErrs: []error{nil, nil},
+			afterErrs:  []error{fmt.Errorf("monitoring not updated"), fmt.Errorf("queue not resumed")},
+			expectedCalls: []string{
+				"before hook_0", "before hook_1",
+				"execute",
+				"after hook_1", "after hook_0",
+			},
+			expectedErrContains: []string{
+				"running after migration hook 1: queue not resumed",
+				"running after migration hook 0: monitoring not updated",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			var hooks []*recordingMigrationHook
+			var migrationHooks []diff.MigrationHook
+			for i, beforeErr := range tc.beforeErrs {
+				var afterErr error
+				if i < len(tc.afterErrs) {
+					afterErr = tc.afterErrs[i]
+				}
+				hook := &recordingMigrationHook{
+					name:      fmt.Sprintf("hook_%d", i),
+					calls:     &calls,
+					beforeErr: beforeErr,
+					afterErr:  afterErr,
+				}
+				hooks = append(hooks, hook)
+				migrationHooks = append(migrationHooks, hook)
+			}
+
+			err := plan.AddMigrationHooks(migrationHooks...).RunWithMigrationHooks(context.Background(), func(_ context.Context, p diff.Plan) error {
+				assert.Equal(t, plan.Statements, p.Statements)
+				calls = append(calls, "execute")
+				return tc.executeErr
+			})
+			assert.Equal(t, tc.expectedCalls, calls)
+			if len(tc.expectedErrContains) == 0 {
+				assert.NoError(t, err)
+			}
+			for _, errContains := range tc.expectedErrContains {
+				assert.ErrorContains(t, err, errContains)
+			}
+			if tc.executeErr != nil {
+				assert.ErrorIs(t, err, tc.executeErr)
+			}
+
+			for _, hook := range hooks {
+				if hook.beforePlan == nil {
+					// The hook's BeforeMigration was never called
+					continue
+				}
+				assert.Equal(t, plan.Statements, hook.beforePlan.Statements)
+				if tc.executeErr != nil {
+					assert.ErrorIs(t, hook.migrationErr, tc.executeErr)
+				}
+			}
+		})
+	}
+}
+
+type recordingStatementHook struct {
+	name      string
+	calls     *[]string
+	beforeErr error
+
+	afterErr      error
+	afterDuration time.Duration
+}
+
+func (r *recordingStatementHook) BeforeStatement(_ context.Context, stmt diff.Statement, index int) error {
+	*r.calls = append(*r.calls, fmt.Sprintf("before %s %d: %s", r.name, index, stmt.DDL))
+	return r.beforeErr
+}
+
+func (r *recordingStatementHook) AfterStatement(_ context.Context, stmt diff.Statement, index int, duration time.Duration, err error) {
+	*r.calls = append(*r.calls, fmt.Sprintf("after %s %d: %s", r.name, index, stmt.DDL))
+	r.afterErr = err
+	r.afterDuration = duration
+}
+
+func TestPlan_RunStatementWithHooks(t *testing.T) {
+	plan := diff.Plan{
+		Statements: []diff.Statement{
+			{DDL: "statement 0"},
+			{DDL: "statement 1"},
+		},
+	}
+
+	t.Run("hooks run around each statement", func(t *testing.T) {
+		var calls []string
+		hooks := []diff.StatementHook{
+			&recordingStatementHook{name: "hook_0", calls: &calls},
+			&recordingStatementHook{name: "hook_1", calls: &calls},
+		}
+		planWithHooks := plan.AddStatementHooks(hooks...)
+		for i := range planWithHooks.Statements {
+			assert.NoError(t, planWithHooks.RunStatementWithHooks(context.Background(), i, func(_ context.Context, stmt diff.Statement) error {
+				calls = append(calls, "execute "+stmt.DDL)
+				return nil
+			}))
+		}
+		assert.Equal(t, []string{
+			"before hook_0 0: statement 0", "before hook_1 0: statement 0",
+			"execute statement 0",
+			"after hook_1 0: statement 0", "after hook_0 0: statement 0",
+			"before hook_0 1: statement 1", "before hook_1 1: statement 1",
+			"execute statement 1",
+			"after hook_1 1: statement 1", "after hook_0 1: statement 1",
+		}, calls)
+	})
+
+	t.Run("after hooks are called when the statement fails", func(t *testing.T) {
+		var calls []string
+		hook := &recordingStatementHook{name: "hook_0", calls: &calls}
+		executeErr := fmt.Errorf("lock timeout")
+		err := plan.AddStatementHooks(hook).RunStatementWithHooks(context.Background(), 1, func(_ context.Context, stmt diff.Statement) error {
+			time.Sleep(time.Millisecond)
+			calls = append(calls, "execute "+stmt.DDL)
+			return executeErr
+		})
+		assert.ErrorIs(t, err, executeErr)
+		assert.Equal(t, []string{
+			"before hook_0 1: statement 1",
+			"execute statement 1",
+			"after hook_0 1: statement 1",
+		}, calls)
+		assert.ErrorIs(t, hook.afterErr, executeErr)
+		assert.GreaterOrEqual(t, hook.afterDuration, time.Millisecond)
+	})
+
+	t.Run("before hook error aborts statement", func(t *testing.T) {
+		var calls []string
+		hooks := []*recordingStatementHook{
+			{name: "hook_0", calls: &calls},
+			{name: "hook_1", calls: &calls, beforeErr: fmt.Errorf("replication lag too high")},
+			{name: "hook_2", calls: &calls},
+		}
+		err := plan.AddStatementHooks(hooks[0], hooks[1], hooks[2]).RunStatementWithHooks(context.Background(), 0, func(_ context.Context, stmt diff.Statement) error {
+			calls = append(calls, "execute "+stmt.DDL)
+			return nil
+		})
+		assert.EqualError(t, err, "running before statement hook 1: replication lag too high")
+		assert.Equal(t, []string{
+			"before hook_0 0: statement 0", "before hook_1 0: statement 0",
+			"after hook_0 0: statement 0",
+		}, calls)
+		assert.ErrorContains(t, hooks[0].afterErr, "replication lag too high")
+		assert.Zero(t, hooks[0].afterDuration)
+	})
+
+	t.Run("index out of range", func(t *testing.T) {
+		err := plan.RunStatementWithHooks(context.Background(), 2, func(context.Context, diff.Statement) error {
+			t.Fatal("statement should not be executed")
+			return nil
+		})
+		assert.ErrorContains(t, err, "out of range")
+	})
+}
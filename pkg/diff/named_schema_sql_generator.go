@@ -8,24 +8,70 @@ import (
 
 // namedSchemaSQLGenerator generates SQL statements for named schemas. It's much easier to make this a SQLGenerator
 // rather than SQLVertexGenerator and setup the dependency for each schema entity that may depend on the named schema.
-type namedSchemaSQLGenerator struct{}
+type namedSchemaSQLGenerator struct {
+	// namedSchemasWithObjects is the set of named schemas that contain objects in the old schema.
+	namedSchemasWithObjects map[string]bool
+	// existingRoles is the set of roles known to exist in the target database. See buildExistingRoles.
+	existingRoles map[string]bool
+}
 
 func (n *namedSchemaSQLGenerator) Add(s schema.NamedSchema) ([]Statement, error) {
-	return []Statement{{
+	stmts := []Statement{{
 		DDL:         fmt.Sprintf("CREATE SCHEMA %s", schema.EscapeIdentifier(s.Name)),
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
-	}}, nil
+	}}
+	return append(stmts, buildAlterOwnerStatements("SCHEMA", schema.EscapeIdentifier(s.Name), "", s.Owner, n.existingRoles)...), nil
 }
 
 func (n *namedSchemaSQLGenerator) Delete(s schema.NamedSchema) ([]Statement, error) {
+	var hazards []MigrationHazard
+	if n.namedSchemasWithObjects[s.Name] {
+		hazards = append(hazards, MigrationHazard{
+			Type: MigrationHazardTypeHasUntrackableDependencies,
+			Message: "The schema is not empty. Its objects are dropped before the schema; however, objects that are " +
+				"not tracked, e.g., those excluded from the diff, are not. The schema can only be dropped if it is " +
+				"empty, i.e., any remaining objects must be dropped with CASCADE.",
+		})
+	}
 	return []Statement{{
 		DDL:         fmt.Sprintf("DROP SCHEMA %s", schema.EscapeIdentifier(s.Name)),
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
+		Hazards:     hazards,
 	}}, nil
 }
 
-func (n *namedSchemaSQLGenerator) Alter(_ namedSchemaDiff) ([]Statement, error) {
-	return nil, nil
+func (n *namedSchemaSQLGenerator) Alter(diff namedSchemaDiff) ([]Statement, error) {
+	return buildAlterOwnerStatements("SCHEMA", schema.EscapeIdentifier(diff.new.Name), diff.old.Owner, diff.new.Owner, n.existingRoles), nil
+}
+
+// buildNamedSchemasWithObjects builds the set of named schemas that contain objects in the schema.
+func buildNamedSchemasWithObjects(s schema.Schema) map[string]bool {
+	namedSchemas := make(map[string]bool)
+	addNamedSchemasOf(namedSchemas, s.Extensions, func(e schema.Extension) string { return e.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.Collations, func(c schema.Collation) string { return c.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.Enums, func(e schema.Enum) string { return e.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.Domains, func(d schema.Domain) string { return d.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.CompositeTypes, func(c schema.CompositeType) string { return c.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.TextSearchDictionaries, func(t schema.TextSearchDictionary) string { return t.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.TextSearchConfigs, func(t schema.TextSearchConfig) string { return t.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.Tables, func(t schema.Table) string { return t.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.ForeignTables, func(f schema.ForeignTable) string { return f.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.Views, func(v schema.View) string { return v.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.MaterializedViews, func(m schema.MaterializedView) string { return m.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.StatisticsObjects, func(so schema.StatisticsObject) string { return so.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.Sequences, func(seq schema.Sequence) string { return seq.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.Functions, func(f schema.Function) string { return f.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.Procedures, func(p schema.Procedure) string { return p.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.Aggregates, func(a schema.Aggregate) string { return a.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.Operators, func(o schema.Operator) string { return o.SchemaName })
+	addNamedSchemasOf(namedSchemas, s.OperatorClasses, func(o schema.OperatorClass) string { return o.SchemaName })
+	return namedSchemas
+}
+
+func addNamedSchemasOf[T any](namedSchemas map[string]bool, objs []T, getNamedSchema func(T) string) {
+	for _, obj := range objs {
+		namedSchemas[getNamedSchema(obj)] = true
+	}
 }
@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestNamedSchemaSQLGenerator(t *testing.T) {
+	t.Run("Drop empty schema", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(
+			schema.Schema{NamedSchemas: []schema.NamedSchema{{Name: "schema_1"}}},
+			schema.Schema{},
+			&planOptions{},
+		)
+		require.NoError(t, err)
+		require.Len(t, stmts, 1)
+		assert.Equal(t, `DROP SCHEMA "schema_1"`, stmts[0].DDL)
+		assert.Empty(t, stmts[0].Hazards)
+	})
+
+	t.Run("Drop non-empty schema", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(
+			schema.Schema{
+				NamedSchemas: []schema.NamedSchema{{Name: "schema_1"}},
+				Enums: []schema.Enum{{
+					SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "schema_1", EscapedName: `"color"`},
+					Labels:              []string{"red"},
+				}},
+			},
+			schema.Schema{},
+			&planOptions{},
+		)
+		require.NoError(t, err)
+		require.Len(t, stmts, 2)
+		// The objects in the schema are dropped before the schema
+		assert.Equal(t, `DROP TYPE "schema_1"."color"`, stmts[0].DDL)
+		assert.Equal(t, `DROP SCHEMA "schema_1"`, stmts[1].DDL)
+		require.Len(t, stmts[1].Hazards, 1)
+		assert.Equal(t, MigrationHazardTypeHasUntrackableDependencies, stmts[1].Hazards[0].Type)
+	})
+
+	t.Run("Create schema with owner", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(
+			schema.Schema{NamedSchemas: []schema.NamedSchema{{Name: "public", Owner: "role_1"}}},
+			schema.Schema{NamedSchemas: []schema.NamedSchema{{Name: "public", Owner: "role_1"}, {Name: "schema_1", Owner: "role_1"}}},
+			&planOptions{},
+		)
+		require.NoError(t, err)
+		var ddl []string
+		for _, stmt := range stmts {
+			ddl = append(ddl, stmt.DDL)
+			// role_1 owns the public schema, so it is known to exist
+			assert.Empty(t, stmt.Hazards)
+		}
+		assert.Equal(t, []string{
+			`CREATE SCHEMA "schema_1"`,
+			`ALTER SCHEMA "schema_1" OWNER TO "role_1"`,
+		}, ddl)
+	})
+
+	t.Run("Transfer schema ownership", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(
+			schema.Schema{NamedSchemas: []schema.NamedSchema{{Name: "schema_1", Owner: "role_1"}}},
+			schema.Schema{NamedSchemas: []schema.NamedSchema{{Name: "schema_1", Owner: "role_2"}}},
+			&planOptions{},
+		)
+		require.NoError(t, err)
+		require.Len(t, stmts, 1)
+		assert.Equal(t, `ALTER SCHEMA "schema_1" OWNER TO "role_2"`, stmts[0].DDL)
+		assert.Equal(t, []MigrationHazard{migrationHazardOwnerRoleUntracked}, stmts[0].Hazards)
+	})
+}
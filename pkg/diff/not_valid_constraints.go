@@ -0,0 +1,67 @@
+package diff
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+const (
+	// checkConstraintValidationRowsPerSecond is a rough estimate of how many rows Postgres can validate a check
+	// constraint against per second. It is only used to estimate how long validating a constraint will take.
+	checkConstraintValidationRowsPerSecond = 1_000_000
+	// foreignKeyValidationRowsPerSecond is a rough estimate of how many rows Postgres can validate a foreign key
+	// against per second. Validating a foreign key is slower than validating a check constraint, since every row
+	// requires a lookup in the referenced table.
+	foreignKeyValidationRowsPerSecond = 100_000
+)
+
+// constraintValidationOptions configures whether check and foreign key constraints on existing tables are added as
+// NOT VALID and then validated, rather than added as valid in one statement.
+//
+// Adding a constraint as NOT VALID only locks the table for a moment, since the existing rows are not scanned. The
+// existing rows are then scanned by VALIDATE CONSTRAINT, which only acquires a SHARE UPDATE EXCLUSIVE lock. This is
+// worth the extra statement for large tables, but for small tables, the constraint can be added in one statement.
+type constraintValidationOptions struct {
+	// addNotValid overrides whether constraints are added as NOT VALID. If nil, constraints are added as NOT VALID on
+	// tables with at least rowThreshold estimated rows.
+	addNotValid  *bool
+	rowThreshold int64
+	// estimatedRowsByTableName is the estimated number of rows in each table of the old schema. Tables without an
+	// estimate are assumed to be large.
+	estimatedRowsByTableName map[string]int64
+}
+
+func (o constraintValidationOptions) shouldAddNotValid(owningTable schema.SchemaQualifiedName) bool {
+	if o.addNotValid != nil {
+		return *o.addNotValid
+	}
+	estimatedRows, ok := o.estimatedRowsByTableName[owningTable.GetName()]
+	if !ok {
+		return true
+	}
+	return estimatedRows >= o.rowThreshold
+}
+
+// validateConstraintStatement builds the statement to validate a constraint that was added as NOT VALID. If the number
+// of rows in the owning table is known, the statement carries a MigrationHazardTypeLongRunning hazard estimating how long
+// the validation will take.
+func (o constraintValidationOptions) validateConstraintStatement(owningTable schema.SchemaQualifiedName, escapedConstraintName string, rowsPerSecond int64) Statement {
+	stmt := validateConstraintStatement(owningTable, escapedConstraintName)
+	if estimatedRows, ok := o.estimatedRowsByTableName[owningTable.GetName()]; ok && estimatedRows > 0 {
+		stmt.Hazards = append(stmt.Hazards, MigrationHazard{
+			Type: MigrationHazardTypeLongRunning,
+			Message: fmt.Sprintf("This will scan the ~%d rows of the owning table, which is estimated to take %s. "+
+				"Reads and writes to the table are not blocked while the constraint is validated.",
+				estimatedRows, estimateValidationDuration(estimatedRows, rowsPerSecond)),
+		})
+	}
+	return stmt
+}
+
+// estimateValidationDuration estimates how long validating a constraint against the rows will take, rounded up to the
+// nearest second
+func estimateValidationDuration(rows, rowsPerSecond int64) time.Duration {
+	return time.Duration((rows+rowsPerSecond-1)/rowsPerSecond) * time.Second
+}
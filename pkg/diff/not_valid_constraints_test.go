@@ -0,0 +1,157 @@
+package diff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestConstraintValidationOptions(t *testing.T) {
+	fooTable := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"`},
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	barTable := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"bar"`},
+		Columns:             []schema.Column{{Name: "foo_id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	barTableWithCheckCon := barTable
+	barTableWithCheckCon.CheckConstraints = []schema.CheckConstraint{{
+		Name:          "positive",
+		KeyColumns:    []string{"foo_id"},
+		Expression:    "(foo_id > 0)",
+		IsValid:       true,
+		IsInheritable: true,
+	}}
+	fkCon := schema.ForeignKeyConstraint{
+		EscapedName:   `"bar_foo_fk"`,
+		OwningTable:   barTable.SchemaQualifiedName,
+		ForeignTable:  fooTable.SchemaQualifiedName,
+		ConstraintDef: "FOREIGN KEY (foo_id) REFERENCES foo(id)",
+		IsValid:       true,
+	}
+	oldSchema := schema.Schema{Tables: []schema.Table{fooTable, barTable}}
+	addNotValid := true
+	doNotAddNotValid := false
+
+	for _, tc := range []struct {
+		name                 string
+		newSchema            schema.Schema
+		constraintValidation constraintValidationOptions
+
+		expectedDDL         []string
+		expectedHazardTypes [][]MigrationHazardType
+		expectedEstimate    time.Duration
+	}{
+		{
+			name:      "Check constraint without row estimates is added as NOT VALID",
+			newSchema: schema.Schema{Tables: []schema.Table{fooTable, barTableWithCheckCon}},
+			expectedDDL: []string{
+				`ALTER TABLE "public"."bar" ADD CONSTRAINT "positive" CHECK((foo_id > 0)) NOT VALID`,
+				`ALTER TABLE "public"."bar" VALIDATE CONSTRAINT "positive"`,
+			},
+			expectedHazardTypes: [][]MigrationHazardType{nil, {MigrationHazardTypeValidateConstraint}},
+		},
+		{
+			name:      "Check constraint on table over threshold is added as NOT VALID with long running validation",
+			newSchema: schema.Schema{Tables: []schema.Table{fooTable, barTableWithCheckCon}},
+			constraintValidation: constraintValidationOptions{
+				rowThreshold:             1000,
+				estimatedRowsByTableName: map[string]int64{barTable.GetName(): 5_000_000},
+			},
+			expectedDDL: []string{
+				`ALTER TABLE "public"."bar" ADD CONSTRAINT "positive" CHECK((foo_id > 0)) NOT VALID`,
+				`ALTER TABLE "public"."bar" VALIDATE CONSTRAINT "positive"`,
+			},
+			expectedHazardTypes: [][]MigrationHazardType{nil, {MigrationHazardTypeValidateConstraint, MigrationHazardTypeLongRunning}},
+			expectedEstimate:    5 * time.Second,
+		},
+		{
+			name:      "Check constraint on table under threshold is added in one statement",
+			newSchema: schema.Schema{Tables: []schema.Table{fooTable, barTableWithCheckCon}},
+			constraintValidation: constraintValidationOptions{
+				rowThreshold:             1000,
+				estimatedRowsByTableName: map[string]int64{barTable.GetName(): 10},
+			},
+			expectedDDL: []string{
+				`ALTER TABLE "public"."bar" ADD CONSTRAINT "positive" CHECK((foo_id > 0))`,
+			},
+			expectedHazardTypes: [][]MigrationHazardType{{MigrationHazardTypeAcquiresAccessExclusiveLock}},
+		},
+		{
+			name:      "Check constraint with NOT VALID forced on table under threshold",
+			newSchema: schema.Schema{Tables: []schema.Table{fooTable, barTableWithCheckCon}},
+			constraintValidation: constraintValidationOptions{
+				addNotValid:              &addNotValid,
+				rowThreshold:             1000,
+				estimatedRowsByTableName: map[string]int64{barTable.GetName(): 10},
+			},
+			expectedDDL: []string{
+				`ALTER TABLE "public"."bar" ADD CONSTRAINT "positive" CHECK((foo_id > 0)) NOT VALID`,
+				`ALTER TABLE "public"."bar" VALIDATE CONSTRAINT "positive"`,
+			},
+			expectedHazardTypes: [][]MigrationHazardType{nil, {MigrationHazardTypeValidateConstraint, MigrationHazardTypeLongRunning}},
+			expectedEstimate:    time.Second,
+		},
+		{
+			name: "Foreign key on table over threshold is added as NOT VALID with long running validation",
+			newSchema: schema.Schema{
+				Tables:                []schema.Table{fooTable, barTable},
+				ForeignKeyConstraints: []schema.ForeignKeyConstraint{fkCon},
+			},
+			constraintValidation: constraintValidationOptions{
+				rowThreshold:             1000,
+				estimatedRowsByTableName: map[string]int64{barTable.GetName(): 5_000_000},
+			},
+			expectedDDL: []string{
+				`ALTER TABLE "public"."bar" ADD CONSTRAINT "bar_foo_fk" FOREIGN KEY (foo_id) REFERENCES foo(id) NOT VALID`,
+				`ALTER TABLE "public"."bar" VALIDATE CONSTRAINT "bar_foo_fk"`,
+			},
+			expectedHazardTypes: [][]MigrationHazardType{nil, {MigrationHazardTypeValidateConstraint, MigrationHazardTypeLongRunning}},
+			expectedEstimate:    50 * time.Second,
+		},
+		{
+			name: "Foreign key with NOT VALID disabled is added in one statement",
+			newSchema: schema.Schema{
+				Tables:                []schema.Table{fooTable, barTable},
+				ForeignKeyConstraints: []schema.ForeignKeyConstraint{fkCon},
+			},
+			constraintValidation: constraintValidationOptions{
+				addNotValid:              &doNotAddNotValid,
+				estimatedRowsByTableName: map[string]int64{barTable.GetName(): 5_000_000},
+			},
+			expectedDDL: []string{
+				`ALTER TABLE "public"."bar" ADD CONSTRAINT "bar_foo_fk" FOREIGN KEY (foo_id) REFERENCES foo(id)`,
+			},
+			expectedHazardTypes: [][]MigrationHazardType{{MigrationHazardTypeAcquiresShareRowExclusiveLock}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			schemaDiff, _, err := buildSchemaDiff(oldSchema, tc.newSchema)
+			require.NoError(t, err)
+			stmts, err := schemaSQLGenerator{constraintValidation: tc.constraintValidation}.Alter(schemaDiff)
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazardTypes [][]MigrationHazardType
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				var stmtHazardTypes []MigrationHazardType
+				for _, hazard := range stmt.Hazards {
+					stmtHazardTypes = append(stmtHazardTypes, hazard.Type)
+					if hazard.Type == MigrationHazardTypeLongRunning {
+						assert.Contains(t, hazard.Message, tc.expectedEstimate.String())
+					}
+				}
+				hazardTypes = append(hazardTypes, stmtHazardTypes)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazardTypes, hazardTypes)
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/pgidentifier"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	// typeModifierRegex matches the type modifier of a type, e.g., the "(255)" of "character varying(255)"
+	typeModifierRegex = regexp.MustCompile(`\(.*\)`)
+
+	// immutableCastTypes are the built-in types whose casts to each other are immutable, i.e., do not depend on
+	// settings like the time zone or DateStyle, so the casts can be used in the expression of a generated column
+	immutableCastTypes = map[string]bool{
+		"smallint":          true,
+		"integer":           true,
+		"bigint":            true,
+		"numeric":           true,
+		"real":              true,
+		"double precision":  true,
+		"text":              true,
+		"character varying": true,
+		"character":         true,
+		"boolean":           true,
+		"uuid":              true,
+		"json":              true,
+		"jsonb":             true,
+	}
+)
+
+// changeColumnTypesOnline returns a copy of the old schema where the columns whose types change are replaced with a
+// shadow column of the new type, along with the statements that perform the replacement:
+//  1. Add the shadow column as a generated column that casts the old column to the new type
+//  2. Rename the old column out of the way
+//  3. Rename the shadow column to the name of the old column
+//  4. Drop the generation expression of the shadow column, such that it is a regular column
+//  5. Drop the old column
+//
+// The shadow column is added at the end of the table, nullable and without a default, so the rest of the plan restores
+// its NOT NULL constraint and default. Columns are only replaced if the cast to the new type is immutable and nothing
+// else references the column, e.g., indexes and views, since dropping the old column would drop them. The other
+// columns are changed via `ALTER COLUMN ... SET DATA TYPE`, with a MigrationHazardTypeImpossibleWithoutDowntime hazard.
+//
+// If ignoreChangesToColOrder is false, only the last column of a table can be replaced, since the shadow column is
+// added at the end of the table.
+func changeColumnTypesOnline(oldSchema, newSchema schema.Schema, ignoreChangesToColOrder bool) (schema.Schema, []Statement, error) {
+	newTablesByName := buildSchemaObjByNameMap(newSchema.Tables)
+
+	var stmts []Statement
+	var tables []schema.Table
+	for _, table := range oldSchema.Tables {
+		newTable, ok := newTablesByName[table.GetName()]
+		if !ok || !canReplaceColumnsOfTable(oldSchema, table) {
+			tables = append(tables, table)
+			continue
+		}
+		newColumnsByName := buildSchemaObjByNameMap(newTable.Columns)
+
+		var replacedColumns []schema.Column
+		var columns []schema.Column
+		for i, column := range table.Columns {
+			newColumn, ok := newColumnsByName[column.Name]
+			if !ok || !canChangeColumnTypeOnline(oldSchema, table, column, newColumn) ||
+				(!ignoreChangesToColOrder && i != len(table.Columns)-1) {
+				columns = append(columns, column)
+				continue
+			}
+			replaceStmts, err := buildReplaceColumnStatements(table.SchemaQualifiedName, column, newColumn)
+			if err != nil {
+				return schema.Schema{}, nil, fmt.Errorf("building statements to replace column %q: %w", column.Name, err)
+			}
+			stmts = append(stmts, replaceStmts...)
+			replacedColumns = append(replacedColumns, schema.Column{
+				Name:       column.Name,
+				Type:       newColumn.Type,
+				Collation:  newColumn.Collation,
+				IsNullable: true,
+				Size:       newColumn.Size,
+			})
+		}
+		if len(replacedColumns) == 0 {
+			tables = append(tables, table)
+			continue
+		}
+		table.Columns = append(columns, replacedColumns...)
+		tables = append(tables, table)
+	}
+
+	oldSchema.Tables = tables
+	return oldSchema, stmts, nil
+}
+
+// canReplaceColumnsOfTable returns whether the columns of the table can be replaced with shadow columns. Columns of
+// partitioned tables, partitions, and tables using inheritance must be the same across the hierarchy, so they are not
+// replaced.
+func canReplaceColumnsOfTable(s schema.Schema, table schema.Table) bool {
+	if table.IsPartitioned() || table.IsPartition() || len(table.InheritsFrom) > 0 {
+		return false
+	}
+	for _, other := range s.Tables {
+		for _, parent := range other.InheritsFrom {
+			if parent == table.SchemaQualifiedName {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// canChangeColumnTypeOnline returns whether the type of the column can be changed via a shadow column
+func canChangeColumnTypeOnline(s schema.Schema, table schema.Table, old, new schema.Column) bool {
+	if strings.EqualFold(old.Type, new.Type) {
+		return false
+	}
+	if old.IsGenerated || new.IsGenerated || old.Identity != nil || new.Identity != nil || old.IsInherited {
+		return false
+	}
+	if old.Name == table.DistributionColumn {
+		// Citus cannot drop the distribution column of a distributed table
+		return false
+	}
+	return isImmutableCast(old.Type, new.Type) && !isColumnReferenced(s, table, old.Name)
+}
+
+// isImmutableCast returns whether the cast from the old type to the new type is known to be immutable. Casts between
+// other types, e.g., to "timestamp with time zone", might depend on settings, so they are assumed to be mutable.
+func isImmutableCast(oldType, newType string) bool {
+	return immutableCastTypes[normalizeCastType(oldType)] && immutableCastTypes[normalizeCastType(newType)]
+}
+
+// normalizeCastType strips the type modifier of the type, e.g., "character varying(255)" -> "character varying"
+func normalizeCastType(typ string) string {
+	return strings.ToLower(strings.TrimSpace(typeModifierRegex.ReplaceAllString(typ, "")))
+}
+
+// isColumnReferenced returns whether any other object might reference the column of the table. The check is
+// conservative: objects whose definitions mention the column's name are assumed to reference it.
+func isColumnReferenced(s schema.Schema, table schema.Table, column string) bool {
+	mentionsColumn := func(def string) bool {
+		return containsIdentifier(def, column) || containsIdentifier(def, schema.EscapeIdentifier(column))
+	}
+	for _, cc := range table.CheckConstraints {
+		if containsString(cc.KeyColumns, column) || mentionsColumn(cc.Expression) {
+			return true
+		}
+	}
+	for _, policy := range table.Policies {
+		if containsString(policy.Columns, column) || mentionsColumn(policy.UsingExpression) || mentionsColumn(policy.CheckExpression) {
+			return true
+		}
+	}
+	for _, idx := range s.Indexes {
+		if idx.OwningTable == table.SchemaQualifiedName && mentionsColumn(string(idx.GetIndexDefStmt)) {
+			return true
+		}
+	}
+	for _, fk := range s.ForeignKeyConstraints {
+		if (fk.OwningTable == table.SchemaQualifiedName || fk.ForeignTable == table.SchemaQualifiedName) && mentionsColumn(fk.ConstraintDef) {
+			return true
+		}
+	}
+	for _, trigger := range s.Triggers {
+		if trigger.OwningTable == table.SchemaQualifiedName && mentionsColumn(string(trigger.GetTriggerDefStmt)) {
+			return true
+		}
+	}
+	for _, stats := range s.StatisticsObjects {
+		if stats.Table == table.SchemaQualifiedName && mentionsColumn(stats.Def) {
+			return true
+		}
+	}
+	for _, view := range s.Views {
+		if contains(view.DependsOnTables, table.SchemaQualifiedName) && mentionsColumn(view.Definition) {
+			return true
+		}
+	}
+	for _, matView := range s.MaterializedViews {
+		if contains(matView.DependsOnTables, table.SchemaQualifiedName) && mentionsColumn(matView.Definition) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+// buildReplaceColumnStatements builds the statements to replace the column with a shadow column of the new type
+func buildReplaceColumnStatements(table schema.SchemaQualifiedName, old, new schema.Column) ([]Statement, error) {
+	uuid, err := pgidentifier.RandomUUID()
+	if err != nil {
+		return nil, fmt.Errorf("generating uuid: %w", err)
+	}
+	shadowColumnName := fmt.Sprintf("%scol_%s", tmpObjNamePrefix, uuid)
+	oldColumnName := fmt.Sprintf("%soldcol_%s", tmpObjNamePrefix, uuid)
+	escapedColumnName := schema.EscapeIdentifier(old.Name)
+
+	shadowColumnDef, err := buildColumnDefinition(schema.Column{
+		Name:                 shadowColumnName,
+		Type:                 new.Type,
+		Collation:            new.Collation,
+		IsNullable:           true,
+		IsGenerated:          true,
+		GenerationExpression: fmt.Sprintf("%s::%s", escapedColumnName, new.Type),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("building shadow column definition: %w", err)
+	}
+
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("%s ADD COLUMN %s", alterTablePrefix(table), shadowColumnDef),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardGeneratedColumnAdded},
+		},
+		{
+			DDL: fmt.Sprintf("-- The values of %s in existing rows were backfilled into %s when it was added, and new "+
+				"values are computed as rows are written", escapedColumnName, schema.EscapeIdentifier(shadowColumnName)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			IsAdvisory:  true,
+		},
+		{
+			DDL:         fmt.Sprintf("%s RENAME COLUMN %s TO %s", alterTablePrefix(table), escapedColumnName, schema.EscapeIdentifier(oldColumnName)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards: []MigrationHazard{{
+				Type: MigrationHazardTypeCorrectness,
+				Message: "Queries that reference the column will fail until the shadow column is renamed to the " +
+					"column's name in the next statement.",
+			}},
+		},
+		{
+			DDL:         fmt.Sprintf("%s RENAME COLUMN %s TO %s", alterTablePrefix(table), schema.EscapeIdentifier(shadowColumnName), escapedColumnName),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards: []MigrationHazard{
+				{
+					Type: MigrationHazardTypeCorrectness,
+					Message: "The column is a generated column until its expression is dropped in the next statement, " +
+						"so writes to the column will fail until then.",
+				},
+				{
+					Type: MigrationHazardTypeColumnOrderChange,
+					Message: "The column is replaced by a column at the end of the table. Queries that rely on column " +
+						"order, e.g., `SELECT *`, will see the new order.",
+				},
+			},
+		},
+		{
+			DDL:         fmt.Sprintf("%s ALTER COLUMN %s DROP EXPRESSION", alterTablePrefix(table), escapedColumnName),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards: []MigrationHazard{{
+				Type: MigrationHazardTypeCorrectness,
+				Message: "The column is nullable and has no default until its NOT NULL constraint and default are " +
+					"restored by later statements.",
+			}},
+		},
+		{
+			DDL:         fmt.Sprintf("%s DROP COLUMN %s", alterTablePrefix(table), schema.EscapeIdentifier(oldColumnName)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards: []MigrationHazard{{
+				Type:    MigrationHazardTypeDeletesData,
+				Message: "Deletes the values of the column with the old type, which were copied to the shadow column",
+			}},
+		},
+		{
+			DDL:         fmt.Sprintf("ANALYZE %s (%s)", table.GetFQEscapedName(), escapedColumnName),
+			Timeout:     statementTimeoutAnalyzeColumn,
+			LockTimeout: lockTimeoutDefault,
+			Hazards: []MigrationHazard{{
+				Type: MigrationHazardTypeImpactsDatabasePerformance,
+				Message: "Running analyze will read rows from the table, putting increased load " +
+					"on the database and consuming database resources. It won't prevent reads/writes to " +
+					"the table, but it could affect performance when executing queries.",
+			}},
+		},
+	}, nil
+}
+
+// migrationHazardColumnTypeChangedInPlace is added to `ALTER COLUMN ... SET DATA TYPE` statements when online column
+// type changes are enabled, since the column could not be replaced with a shadow column
+func migrationHazardColumnTypeChangedInPlace(oldType, newType string) MigrationHazard {
+	reason := "the column is referenced by other objects, e.g., indexes, constraints, or views, which would be dropped " +
+		"along with the old column, is an identity or generated column, or belongs to a table hierarchy"
+	if !isImmutableCast(oldType, newType) {
+		reason = fmt.Sprintf("the cast from %s to %s is not known to be immutable, so it cannot be used in a generated "+
+			"column", oldType, newType)
+	}
+	return MigrationHazard{
+		Type:    MigrationHazardTypeImpossibleWithoutDowntime,
+		Message: fmt.Sprintf("The column's type cannot be changed online via a shadow column, because %s.", reason),
+	}
+}
@@ -0,0 +1,157 @@
+package diff
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+// tmpNameRegex matches the random names of the temporary columns, constraints, and indexes
+var tmpNameRegex = regexp.MustCompile(`(pgschemadiff_tmp(oldcol|col|nn|idx))_[A-Za-z0-9$_]+`)
+
+func TestGenerateMigrationStatements_OnlineColumnTypeChange(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+	buildSchema := func(valType string, idx ...schema.Index) schema.Schema {
+		return schema.Schema{
+			Tables: []schema.Table{{
+				SchemaQualifiedName: foobar,
+				Columns: []schema.Column{
+					{Name: "val", Type: valType, Default: "'x'::" + valType},
+					{Name: "id", Type: "integer"},
+				},
+				ReplicaIdentity: schema.ReplicaIdentityDefault,
+			}},
+			Indexes: idx,
+		}
+	}
+	valIdx := schema.Index{
+		OwningTable:     foobar,
+		Name:            "val_idx",
+		Columns:         []string{"val"},
+		GetIndexDefStmt: "CREATE INDEX val_idx ON public.foobar USING btree (val)",
+	}
+
+	for _, tc := range []struct {
+		name      string
+		oldSchema schema.Schema
+		newSchema schema.Schema
+		opts      planOptions
+
+		expectedDDL         []string
+		expectedHazardTypes []MigrationHazardType
+	}{
+		{
+			name:      "Column is replaced with a shadow column",
+			oldSchema: buildSchema("character varying(50)"),
+			newSchema: buildSchema("text"),
+			opts:      planOptions{onlineColumnTypeChange: true, ignoreChangesToColOrder: true},
+			expectedDDL: []string{
+				`ALTER TABLE "public"."foobar" ADD COLUMN "pgschemadiff_tmpcol" text GENERATED ALWAYS AS ("val"::text) STORED`,
+				`-- The values of "val" in existing rows were backfilled into "pgschemadiff_tmpcol" when it was added, and new values are computed as rows are written`,
+				`ALTER TABLE "public"."foobar" RENAME COLUMN "val" TO "pgschemadiff_tmpoldcol"`,
+				`ALTER TABLE "public"."foobar" RENAME COLUMN "pgschemadiff_tmpcol" TO "val"`,
+				`ALTER TABLE "public"."foobar" ALTER COLUMN "val" DROP EXPRESSION`,
+				`ALTER TABLE "public"."foobar" DROP COLUMN "pgschemadiff_tmpoldcol"`,
+				`ANALYZE "public"."foobar" ("val")`,
+				// The NOT NULL constraint and default of the column are restored
+				`ALTER TABLE "public"."foobar" ADD CONSTRAINT "pgschemadiff_tmpnn" CHECK("val" IS NOT NULL) NOT VALID`,
+				`ALTER TABLE "public"."foobar" VALIDATE CONSTRAINT "pgschemadiff_tmpnn"`,
+				`ALTER TABLE "public"."foobar" ALTER COLUMN "val" SET NOT NULL`,
+				`ALTER TABLE "public"."foobar" ALTER COLUMN "val" SET DEFAULT 'x'::text`,
+				`ALTER TABLE "public"."foobar" DROP CONSTRAINT "pgschemadiff_tmpnn"`,
+				`VACUUM FREEZE "public"."foobar"`,
+				`ANALYZE "public"."foobar"`,
+			},
+			expectedHazardTypes: []MigrationHazardType{
+				MigrationHazardTypeAcquiresAccessExclusiveLock,
+				MigrationHazardTypeCorrectness,
+				MigrationHazardTypeColumnOrderChange,
+				MigrationHazardTypeDeletesData,
+				MigrationHazardTypeImpactsDatabasePerformance,
+				MigrationHazardTypeValidateConstraint,
+			},
+		},
+		{
+			name:      "Column with mutable cast is altered in place",
+			oldSchema: buildSchema("timestamp without time zone"),
+			newSchema: buildSchema("timestamp with time zone"),
+			opts:      planOptions{onlineColumnTypeChange: true, ignoreChangesToColOrder: true},
+			expectedDDL: []string{
+				`ALTER TABLE "public"."foobar" ALTER COLUMN "val" SET DATA TYPE timestamp with time zone using "val"::timestamp with time zone`,
+				`ANALYZE "public"."foobar" ("val")`,
+				`ALTER TABLE "public"."foobar" ALTER COLUMN "val" SET DEFAULT 'x'::timestamp with time zone`,
+				`ANALYZE "public"."foobar"`,
+			},
+			expectedHazardTypes: []MigrationHazardType{
+				MigrationHazardTypeAcquiresAccessExclusiveLock,
+				MigrationHazardTypeImpossibleWithoutDowntime,
+				MigrationHazardTypeImpactsDatabasePerformance,
+			},
+		},
+		{
+			name:      "Indexed column is altered in place",
+			oldSchema: buildSchema("character varying(50)", valIdx),
+			newSchema: buildSchema("text", valIdx),
+			opts:      planOptions{onlineColumnTypeChange: true, ignoreChangesToColOrder: true},
+			expectedDDL: []string{
+				`ALTER TABLE "public"."foobar" ALTER COLUMN "val" SET DATA TYPE text using "val"::text`,
+				`ANALYZE "public"."foobar" ("val")`,
+				`ALTER TABLE "public"."foobar" ALTER COLUMN "val" SET DEFAULT 'x'::text`,
+				`ANALYZE "public"."foobar"`,
+			},
+			expectedHazardTypes: []MigrationHazardType{
+				MigrationHazardTypeAcquiresAccessExclusiveLock,
+				MigrationHazardTypeImpossibleWithoutDowntime,
+				MigrationHazardTypeImpactsDatabasePerformance,
+			},
+		},
+		{
+			name:      "Column that is not last is altered in place if column order is respected",
+			oldSchema: buildSchema("character varying(50)"),
+			newSchema: buildSchema("text"),
+			opts:      planOptions{onlineColumnTypeChange: true},
+			expectedDDL: []string{
+				`ALTER TABLE "public"."foobar" ALTER COLUMN "val" SET DATA TYPE text using "val"::text`,
+				`ANALYZE "public"."foobar" ("val")`,
+				`ALTER TABLE "public"."foobar" ALTER COLUMN "val" SET DEFAULT 'x'::text`,
+				`ANALYZE "public"."foobar"`,
+			},
+			expectedHazardTypes: []MigrationHazardType{
+				MigrationHazardTypeAcquiresAccessExclusiveLock,
+				MigrationHazardTypeImpossibleWithoutDowntime,
+				MigrationHazardTypeImpactsDatabasePerformance,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(tc.oldSchema, tc.newSchema, &tc.opts)
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazardTypes []MigrationHazardType
+			for _, stmt := range stmts {
+				ddl = append(ddl, tmpNameRegex.ReplaceAllString(stmt.DDL, "$1"))
+				for _, hazard := range stmt.Hazards {
+					hazardTypes = append(hazardTypes, hazard.Type)
+				}
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.ElementsMatch(t, tc.expectedHazardTypes, uniqueHazardTypes(hazardTypes))
+		})
+	}
+}
+
+func uniqueHazardTypes(hazardTypes []MigrationHazardType) []MigrationHazardType {
+	seen := make(map[MigrationHazardType]bool)
+	var unique []MigrationHazardType
+	for _, hazardType := range hazardTypes {
+		if !seen[hazardType] {
+			seen[hazardType] = true
+			unique = append(unique, hazardType)
+		}
+	}
+	return unique
+}
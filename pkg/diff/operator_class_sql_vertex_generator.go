@@ -0,0 +1,81 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var migrationHazardOperatorClassDropped = MigrationHazard{
+	Type: MigrationHazardTypeHasUntrackableDependencies,
+	Message: "Operator classes cannot be altered, so changing an operator class drops and re-creates it. Indexes " +
+		"using the operator class are dropped and rebuilt. Objects that depend on its operator family, e.g., " +
+		"other operator classes in the family, are not tracked and will cause the drop to fail.",
+}
+
+type operatorClassSQLVertexGenerator struct{}
+
+func newOperatorClassSqlVertexGenerator() sqlVertexGenerator[schema.OperatorClass, operatorClassDiff] {
+	return legacyToNewSqlVertexGenerator[schema.OperatorClass, operatorClassDiff](&operatorClassSQLVertexGenerator{})
+}
+
+func (o *operatorClassSQLVertexGenerator) Add(operatorClass schema.OperatorClass) ([]Statement, error) {
+	return []Statement{{
+		DDL:         operatorClass.Def,
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}}, nil
+}
+
+func (o *operatorClassSQLVertexGenerator) Delete(operatorClass schema.OperatorClass) ([]Statement, error) {
+	return []Statement{{
+		DDL:         fmt.Sprintf("DROP OPERATOR CLASS %s", operatorClass.GetName()),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardOperatorClassDropped},
+	}}, nil
+}
+
+func (o *operatorClassSQLVertexGenerator) Alter(diff operatorClassDiff) ([]Statement, error) {
+	// Operator classes are always re-created if they have changed, so there should never be anything to alter.
+	if !cmp.Equal(diff.old, diff.new) {
+		return nil, fmt.Errorf("altering operator class to resolve the following diff %s: %w", cmp.Diff(diff.old, diff.new), ErrNotImplemented)
+	}
+	return nil, nil
+}
+
+func (o *operatorClassSQLVertexGenerator) GetSQLVertexId(operatorClass schema.OperatorClass, diffType diffType) sqlVertexId {
+	return buildOperatorClassVertexId(schema.OperatorClassReference{
+		SchemaQualifiedName: operatorClass.SchemaQualifiedName,
+		IndexMethod:         operatorClass.IndexMethod,
+	}, diffType)
+}
+
+func buildOperatorClassVertexId(operatorClass schema.OperatorClassReference, diffType diffType) sqlVertexId {
+	return buildSchemaObjVertexId("operator_class", operatorClass.GetName(), diffType)
+}
+
+func (o *operatorClassSQLVertexGenerator) GetAddAlterDependencies(newOperatorClass, _ schema.OperatorClass) ([]dependency, error) {
+	deps := []dependency{
+		mustRun(o.GetSQLVertexId(newOperatorClass, diffTypeAddAlter)).after(o.GetSQLVertexId(newOperatorClass, diffTypeDelete)),
+	}
+	for _, operator := range newOperatorClass.DependsOnOperators {
+		deps = append(deps, mustRun(o.GetSQLVertexId(newOperatorClass, diffTypeAddAlter)).after(buildOperatorVertexId(operator, diffTypeAddAlter)))
+	}
+	for _, function := range newOperatorClass.DependsOnFunctions {
+		deps = append(deps, mustRun(o.GetSQLVertexId(newOperatorClass, diffTypeAddAlter)).after(buildFunctionVertexId(function, diffTypeAddAlter)))
+	}
+	return deps, nil
+}
+
+func (o *operatorClassSQLVertexGenerator) GetDeleteDependencies(operatorClass schema.OperatorClass) ([]dependency, error) {
+	var deps []dependency
+	for _, operator := range operatorClass.DependsOnOperators {
+		deps = append(deps, mustRun(o.GetSQLVertexId(operatorClass, diffTypeDelete)).before(buildOperatorVertexId(operator, diffTypeDelete)))
+	}
+	for _, function := range operatorClass.DependsOnFunctions {
+		deps = append(deps, mustRun(o.GetSQLVertexId(operatorClass, diffTypeDelete)).before(buildFunctionVertexId(function, diffTypeDelete)))
+	}
+	return deps, nil
+}
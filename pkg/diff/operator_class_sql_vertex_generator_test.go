@@ -0,0 +1,65 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestGenerateMigrationStatements_OperatorClassUsedByIndex(t *testing.T) {
+	table := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}
+	operatorClassName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"abs_int_ops\""}
+	index := schema.Index{
+		Name:            "foo_val_idx",
+		OwningTable:     table,
+		Columns:         []string{"val"},
+		GetIndexDefStmt: "CREATE INDEX foo_val_idx ON public.foo USING hash (val abs_int_ops)",
+		DependsOnOperatorClasses: []schema.OperatorClassReference{
+			{SchemaQualifiedName: operatorClassName, IndexMethod: "hash"},
+		},
+	}
+	buildSchema := func(operatorClassDef string) schema.Schema {
+		return schema.Schema{
+			Tables: []schema.Table{{
+				SchemaQualifiedName: table,
+				Columns:             []schema.Column{{Name: "val", Type: "integer"}},
+				ReplicaIdentity:     schema.ReplicaIdentityDefault,
+			}},
+			Indexes: []schema.Index{index},
+			OperatorClasses: []schema.OperatorClass{{
+				SchemaQualifiedName: operatorClassName,
+				IndexMethod:         "hash",
+				Def:                 operatorClassDef,
+			}},
+		}
+	}
+
+	oldSchema := buildSchema("CREATE OPERATOR CLASS public.abs_int_ops FOR TYPE integer USING hash AS OPERATOR 1 public.|=|(integer, integer), FUNCTION 1 public.abs_hash(integer)")
+	newSchema := buildSchema("CREATE OPERATOR CLASS public.abs_int_ops DEFAULT FOR TYPE integer USING hash AS OPERATOR 1 public.|=|(integer, integer), FUNCTION 1 public.abs_hash(integer)")
+
+	stmts, err := generateMigrationStatements(oldSchema, newSchema, &planOptions{})
+	require.NoError(t, err)
+
+	stmtIdx := func(prefix string) int {
+		for i, stmt := range stmts {
+			if strings.HasPrefix(stmt.DDL, prefix) {
+				return i
+			}
+		}
+		require.Failf(t, "statement not found", "%q not found in %v", prefix, stmts)
+		return -1
+	}
+	dropIndexIdx := stmtIdx("DROP INDEX")
+	dropOperatorClassIdx := stmtIdx("DROP OPERATOR CLASS")
+	createOperatorClassIdx := stmtIdx("CREATE OPERATOR CLASS")
+	createIndexIdx := stmtIdx("CREATE INDEX")
+
+	// The index must be rebuilt around the re-created operator class
+	assert.Less(t, dropIndexIdx, dropOperatorClassIdx)
+	assert.Less(t, dropOperatorClassIdx, createOperatorClassIdx)
+	assert.Less(t, createOperatorClassIdx, createIndexIdx)
+	assert.Contains(t, stmts[dropOperatorClassIdx].Hazards, migrationHazardOperatorClassDropped)
+}
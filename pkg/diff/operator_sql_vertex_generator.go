@@ -0,0 +1,60 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+type operatorSQLVertexGenerator struct{}
+
+func newOperatorSqlVertexGenerator() sqlVertexGenerator[schema.Operator, operatorDiff] {
+	return legacyToNewSqlVertexGenerator[schema.Operator, operatorDiff](&operatorSQLVertexGenerator{})
+}
+
+func (o *operatorSQLVertexGenerator) Add(operator schema.Operator) ([]Statement, error) {
+	return []Statement{{
+		DDL:         operator.Def,
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}}, nil
+}
+
+func (o *operatorSQLVertexGenerator) Delete(operator schema.Operator) ([]Statement, error) {
+	return []Statement{{
+		DDL:         fmt.Sprintf("DROP OPERATOR %s", operator.GetFQEscapedName()),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}}, nil
+}
+
+func (o *operatorSQLVertexGenerator) Alter(diff operatorDiff) ([]Statement, error) {
+	// Operators are always re-created if they have changed, so there should never be anything to alter.
+	if !cmp.Equal(diff.old, diff.new) {
+		return nil, fmt.Errorf("altering operator to resolve the following diff %s: %w", cmp.Diff(diff.old, diff.new), ErrNotImplemented)
+	}
+	return nil, nil
+}
+
+func (o *operatorSQLVertexGenerator) GetSQLVertexId(operator schema.Operator, diffType diffType) sqlVertexId {
+	return buildOperatorVertexId(operator.SchemaQualifiedName, diffType)
+}
+
+func buildOperatorVertexId(name schema.SchemaQualifiedName, diffType diffType) sqlVertexId {
+	return buildSchemaObjVertexId("operator", name.GetFQEscapedName(), diffType)
+}
+
+func (o *operatorSQLVertexGenerator) GetAddAlterDependencies(newOperator, _ schema.Operator) ([]dependency, error) {
+	return []dependency{
+		mustRun(o.GetSQLVertexId(newOperator, diffTypeAddAlter)).after(o.GetSQLVertexId(newOperator, diffTypeDelete)),
+		// The function implementing the operator must exist before the operator is created
+		mustRun(o.GetSQLVertexId(newOperator, diffTypeAddAlter)).after(buildFunctionVertexId(newOperator.Function, diffTypeAddAlter)),
+	}, nil
+}
+
+func (o *operatorSQLVertexGenerator) GetDeleteDependencies(operator schema.Operator) ([]dependency, error) {
+	return []dependency{
+		mustRun(o.GetSQLVertexId(operator, diffTypeDelete)).before(buildFunctionVertexId(operator.Function, diffTypeDelete)),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var migrationHazardOwnerRoleUntracked = MigrationHazard{
+	Type: MigrationHazardTypeHasUntrackableDependencies,
+	Message: "Roles are not tracked. Changing the owner will fail if the role does not exist in the target " +
+		"database.",
+}
+
+// buildExistingRoles builds the set of roles that are known to exist in the database the schema was fetched from,
+// i.e., the roles that own objects in the schema.
+func buildExistingRoles(s schema.Schema) map[string]bool {
+	roles := make(map[string]bool)
+	for _, owner := range s.ObjectOwners {
+		roles[owner] = true
+	}
+	for _, namedSchema := range s.NamedSchemas {
+		roles[namedSchema.Owner] = true
+	}
+	for _, table := range s.Tables {
+		roles[table.Owner] = true
+	}
+	for _, view := range s.Views {
+		roles[view.Owner] = true
+	}
+	for _, sequence := range s.Sequences {
+		roles[sequence.OwnerRole] = true
+	}
+	for _, function := range s.Functions {
+		roles[function.Owner] = true
+	}
+	delete(roles, "")
+	return roles
+}
+
+// buildAlterOwnerStatements builds the statements to change the owner of an object from oldOwner to newOwner, e.g.,
+// `ALTER TABLE "public"."foo" OWNER TO "some_role"`. objectType is the type of the object used in the ALTER statement,
+// e.g., TABLE. No statements are built if the owner is unchanged or the new owner is empty, i.e., owners were not
+// fetched.
+//
+// Roles are not tracked, so the statement has a hazard if the new owner is not in existingRoles.
+func buildAlterOwnerStatements(objectType, escapedObjectName, oldOwner, newOwner string, existingRoles map[string]bool) []Statement {
+	if len(newOwner) == 0 || oldOwner == newOwner {
+		return nil
+	}
+	var hazards []MigrationHazard
+	if !existingRoles[newOwner] {
+		hazards = append(hazards, migrationHazardOwnerRoleUntracked)
+	}
+	return []Statement{{
+		DDL:         fmt.Sprintf("ALTER %s %s OWNER TO %s", objectType, escapedObjectName, schema.EscapeIdentifier(newOwner)),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     hazards,
+	}}
+}
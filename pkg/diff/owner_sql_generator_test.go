@@ -0,0 +1,125 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestGenerateMigrationStatements_Owners(t *testing.T) {
+	fooName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"`}
+	buildSchema := func(owner string) schema.Schema {
+		return schema.Schema{
+			Tables: []schema.Table{{
+				SchemaQualifiedName: fooName,
+				Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+				ReplicaIdentity:     schema.ReplicaIdentityDefault,
+				Owner:               owner,
+			}},
+			Views: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo_view"`},
+				Definition:          " SELECT foo.id\n   FROM foo;",
+				DependsOnTables:     []schema.SchemaQualifiedName{fooName},
+				Owner:               owner,
+			}},
+			Sequences: []schema.Sequence{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo_seq"`},
+				Type:                "bigint",
+				StartValue:          1,
+				Increment:           1,
+				MaxValue:            9223372036854775807,
+				MinValue:            1,
+				CacheSize:           1,
+				OwnerRole:           owner,
+			}},
+			Functions: []schema.Function{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"add"(a integer, b integer)`},
+				FunctionDef:         "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT a + b $function$\n",
+				Language:            "sql",
+				Owner:               owner,
+			}},
+			Privileges: []schema.Privilege{{
+				ObjectType: "FUNCTION",
+				Object:     schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"add"(a integer, b integer)`},
+				Grantee:    schema.PrivilegeGranteePublic,
+				Type:       "EXECUTE",
+			}},
+		}
+	}
+	// The roles are known to exist if they own objects in the current schema
+	currentSchema := buildSchema("role_1")
+	currentSchema.Tables = append(currentSchema.Tables, schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"bar"`},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		Owner:               "role_2",
+	})
+	newSchema := buildSchema("role_2")
+	newSchema.Tables = append(newSchema.Tables, currentSchema.Tables[1])
+
+	expectedDDL := []string{
+		`ALTER TABLE "public"."foo" OWNER TO "role_2"`,
+		`ALTER VIEW "public"."foo_view" OWNER TO "role_2"`,
+		`ALTER SEQUENCE "public"."foo_seq" OWNER TO "role_2"`,
+		`ALTER FUNCTION "public"."add"(a integer, b integer) OWNER TO "role_2"`,
+	}
+
+	t.Run("Transfer ownership to an existing role", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(currentSchema, newSchema, &planOptions{})
+		require.NoError(t, err)
+		var ddl []string
+		for _, stmt := range stmts {
+			ddl = append(ddl, stmt.DDL)
+			assert.Empty(t, stmt.Hazards)
+		}
+		assert.ElementsMatch(t, expectedDDL, ddl)
+	})
+
+	t.Run("Transfer ownership to a role that might not exist", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(buildSchema("role_1"), buildSchema("role_2"), &planOptions{})
+		require.NoError(t, err)
+		var ddl []string
+		for _, stmt := range stmts {
+			ddl = append(ddl, stmt.DDL)
+			assert.Equal(t, []MigrationHazard{migrationHazardOwnerRoleUntracked}, stmt.Hazards)
+		}
+		assert.ElementsMatch(t, expectedDDL, ddl)
+	})
+
+	t.Run("Owners are not fetched", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(buildSchema("role_1"), buildSchema(""), &planOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, stmts)
+	})
+
+	t.Run("Owner is set after the object is created", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(schema.Schema{}, buildSchema("role_1"), &planOptions{})
+		require.NoError(t, err)
+		createdIdx := make(map[string]int)
+		for i, stmt := range stmts {
+			createdIdx[stmt.DDL] = i
+		}
+		for _, tc := range []struct {
+			createPrefix string
+			ownerDDL     string
+		}{
+			{createPrefix: `CREATE TABLE "public"."foo"`, ownerDDL: `ALTER TABLE "public"."foo" OWNER TO "role_1"`},
+			{createPrefix: `CREATE VIEW "public"."foo_view"`, ownerDDL: `ALTER VIEW "public"."foo_view" OWNER TO "role_1"`},
+			{createPrefix: `CREATE SEQUENCE "public"."foo_seq"`, ownerDDL: `ALTER SEQUENCE "public"."foo_seq" OWNER TO "role_1"`},
+			{createPrefix: `CREATE OR REPLACE FUNCTION public.add`, ownerDDL: `ALTER FUNCTION "public"."add"(a integer, b integer) OWNER TO "role_1"`},
+		} {
+			ownerIdx, ok := createdIdx[tc.ownerDDL]
+			require.True(t, ok, "%q not found in %v", tc.ownerDDL, stmts)
+			createIdx := -1
+			for i, stmt := range stmts {
+				if strings.HasPrefix(stmt.DDL, tc.createPrefix) {
+					createIdx = i
+				}
+			}
+			require.NotEqual(t, -1, createIdx, "%q not found in %v", tc.createPrefix, stmts)
+			assert.Less(t, createIdx, ownerIdx)
+		}
+	})
+}
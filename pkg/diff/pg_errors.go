@@ -0,0 +1,54 @@
+package diff
+
+import (
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/lib/pq"
+)
+
+const (
+	pgErrCodeSyntaxError           = "42601"
+	pgErrCodeInsufficientPrivilege = "42501"
+	pgErrCodeLockNotAvailable      = "55P03"
+	pgErrCodeQueryCanceled         = "57014"
+)
+
+// pgError is an error reported by Postgres. It abstracts over the error types of the pgx and pq drivers, since the
+// caller provides the *sql.DB
+type pgError struct {
+	// Code is the Postgres error code (SQLSTATE), e.g., "42601" for a syntax error
+	Code    string
+	Message string
+	Detail  string
+	Hint    string
+}
+
+// getPgError returns the Postgres error wrapped by err. It returns false if err did not come from Postgres, e.g., the
+// connection was closed
+func getPgError(err error) (pgError, bool) {
+	var pgxErr *pgconn.PgError
+	var pqErr *pq.Error
+	if errors.As(err, &pgxErr) {
+		return pgError{
+			Code:    pgxErr.Code,
+			Message: pgxErr.Message,
+			Detail:  pgxErr.Detail,
+			Hint:    pgxErr.Hint,
+		}, true
+	} else if errors.As(err, &pqErr) {
+		return pgError{
+			Code:    string(pqErr.Code),
+			Message: pqErr.Message,
+			Detail:  pqErr.Detail,
+			Hint:    pqErr.Hint,
+		}, true
+	}
+	return pgError{}, false
+}
+
+// isLockTimeoutError returns true if the error is from a statement that exceeded its lock timeout
+func isLockTimeoutError(err error) bool {
+	pgErr, ok := getPgError(err)
+	return ok && pgErr.Code == pgErrCodeLockNotAvailable
+}
@@ -0,0 +1,50 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPgError(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		err           error
+		expectedPgErr pgError
+		expectedOk    bool
+	}{
+		{
+			name:          "pgx error",
+			err:           fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "42601", Message: "syntax error", Detail: "some detail", Hint: "some hint"}),
+			expectedPgErr: pgError{Code: "42601", Message: "syntax error", Detail: "some detail", Hint: "some hint"},
+			expectedOk:    true,
+		},
+		{
+			name:          "pq error",
+			err:           fmt.Errorf("wrapped: %w", &pq.Error{Code: "42601", Message: "syntax error", Detail: "some detail", Hint: "some hint"}),
+			expectedPgErr: pgError{Code: "42601", Message: "syntax error", Detail: "some detail", Hint: "some hint"},
+			expectedOk:    true,
+		},
+		{
+			name: "Not a Postgres error",
+			err:  errors.New("some error"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pgErr, ok := getPgError(tc.err)
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedPgErr, pgErr)
+		})
+	}
+}
+
+func TestIsLockTimeoutError(t *testing.T) {
+	assert.True(t, isLockTimeoutError(fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "55P03"})))
+	assert.True(t, isLockTimeoutError(&pq.Error{Code: "55P03"}))
+	assert.False(t, isLockTimeoutError(&pgconn.PgError{Code: "57014"}))
+	assert.False(t, isLockTimeoutError(errors.New("some error")))
+}
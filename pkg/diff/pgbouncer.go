@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+
+	pg_query "github.com/pganalyze/pg_query_go/v5"
+)
+
+var (
+	// sessionAdvisoryLockFunctionRegex matches calls to the session-level advisory lock functions. The transaction-level
+	// functions, e.g., pg_advisory_xact_lock, are released at the end of the transaction, so they are not matched.
+	sessionAdvisoryLockFunctionRegex = regexp.MustCompile(`(?i)\bpg_(try_)?advisory_(lock|unlock)(_shared|_all)?\s*\(`)
+
+	migrationHazardPgBouncerNoTransaction = MigrationHazard{
+		Type: MigrationHazardTypePgBouncerIncompatible,
+		Message: "This statement cannot be executed within a transaction block, so it relies on the session-level " +
+			"statement_timeout and lock_timeout. In transaction mode, PgBouncer might execute the statement on a " +
+			"different server connection than the one the timeouts were set on.",
+	}
+)
+
+// WithPgBouncerMode configures the plan generation for a database behind PgBouncer in transaction mode, where each
+// transaction might be executed on a different server connection. Statements that rely on session state, e.g.,
+// `SET LOCAL` within an explicit transaction block, session-level advisory locks, prepared statements, and
+// `LISTEN`/`NOTIFY`, are flagged with a MigrationHazardTypePgBouncerIncompatible hazard. So are statements that cannot
+// be executed within a transaction block, since they rely on the session-level timeouts, and statements that contain
+// more than one SQL statement, since they are executed as one multi-statement transaction.
+//
+// The generated statements are each a single SQL statement, so they are executed as single-statement transactions.
+// Statements inserted into the plan via Plan.InsertStatement are also checked.
+func WithPgBouncerMode() PlanOpt {
+	return func(opts *planOptions) {
+		opts.pgBouncerMode = true
+	}
+}
+
+// addPgBouncerHazards adds a MigrationHazardTypePgBouncerIncompatible hazard to the statement for each reason it is
+// incompatible with PgBouncer in transaction mode
+func addPgBouncerHazards(stmt Statement) Statement {
+	var hazards []MigrationHazard
+	if stmt.RequiresNoTransaction {
+		hazards = append(hazards, migrationHazardPgBouncerNoTransaction)
+	}
+	for _, reason := range getPgBouncerIncompatibilities(stmt.DDL) {
+		hazards = append(hazards, MigrationHazard{
+			Type:    MigrationHazardTypePgBouncerIncompatible,
+			Message: fmt.Sprintf("This statement %s, which is not supported by PgBouncer in transaction mode.", reason),
+		})
+	}
+	if len(hazards) > 0 {
+		stmt.Hazards = append(append([]MigrationHazard(nil), stmt.Hazards...), hazards...)
+	}
+	return stmt
+}
+
+// getPgBouncerIncompatibilities returns the reasons the DDL is incompatible with PgBouncer in transaction mode, e.g.,
+// "uses SET LOCAL". DDL that cannot be parsed is only checked for session-level advisory locks.
+func getPgBouncerIncompatibilities(ddl string) []string {
+	var reasons []string
+	if result, err := pg_query.Parse(ddl); err == nil {
+		if len(result.Stmts) > 1 {
+			reasons = append(reasons, "contains multiple SQL statements, which are executed as one multi-statement transaction")
+		}
+		for _, rawStmt := range result.Stmts {
+			if reason, ok := getPgBouncerIncompatibility(rawStmt.Stmt); ok {
+				reasons = append(reasons, reason)
+			}
+		}
+	}
+	if sessionAdvisoryLockFunctionRegex.MatchString(ddl) {
+		reasons = append(reasons, "uses a session-level advisory lock")
+	}
+	return dedupeStrings(reasons)
+}
+
+func getPgBouncerIncompatibility(node *pg_query.Node) (string, bool) {
+	switch n := node.Node.(type) {
+	case *pg_query.Node_TransactionStmt:
+		return "controls the transaction, e.g., via BEGIN or COMMIT", true
+	case *pg_query.Node_VariableSetStmt:
+		if n.VariableSetStmt.IsLocal {
+			return "uses SET LOCAL", true
+		}
+	case *pg_query.Node_PrepareStmt, *pg_query.Node_ExecuteStmt, *pg_query.Node_DeallocateStmt:
+		return "uses a prepared statement", true
+	case *pg_query.Node_ListenStmt, *pg_query.Node_UnlistenStmt, *pg_query.Node_NotifyStmt:
+		return "uses LISTEN/NOTIFY", true
+	}
+	return "", false
+}
+
+func dedupeStrings(vals []string) []string {
+	seen := make(map[string]bool)
+	var deduped []string
+	for _, val := range vals {
+		if seen[val] {
+			continue
+		}
+		seen[val] = true
+		deduped = append(deduped, val)
+	}
+	return deduped
+}
@@ -0,0 +1,114 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestAddPgBouncerHazards(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		stmt                Statement
+		expectedHazardCount int
+	}{
+		{
+			name:                "Plain DDL",
+			stmt:                Statement{DDL: `ALTER TABLE "public"."foobar" ADD COLUMN "bar" text`},
+			expectedHazardCount: 0,
+		},
+		{
+			name:                "SET LOCAL within a transaction block",
+			stmt:                Statement{DDL: "BEGIN; SET LOCAL lock_timeout = '1s'; ALTER TABLE foobar ADD COLUMN bar text; COMMIT"},
+			expectedHazardCount: 3,
+		},
+		{
+			name:                "SET LOCAL",
+			stmt:                Statement{DDL: "SET LOCAL statement_timeout = '1s'"},
+			expectedHazardCount: 1,
+		},
+		{
+			name:                "Session-level SET",
+			stmt:                Statement{DDL: "SET statement_timeout = '1s'"},
+			expectedHazardCount: 0,
+		},
+		{
+			name:                "Session-level advisory lock",
+			stmt:                Statement{DDL: "SELECT pg_advisory_lock(1)"},
+			expectedHazardCount: 1,
+		},
+		{
+			name:                "Transaction-level advisory lock",
+			stmt:                Statement{DDL: "SELECT pg_advisory_xact_lock(1)"},
+			expectedHazardCount: 0,
+		},
+		{
+			name:                "Prepared statement",
+			stmt:                Statement{DDL: "PREPARE foo AS SELECT 1"},
+			expectedHazardCount: 1,
+		},
+		{
+			name:                "LISTEN",
+			stmt:                Statement{DDL: "LISTEN foo"},
+			expectedHazardCount: 1,
+		},
+		{
+			name:                "Requires no transaction",
+			stmt:                Statement{DDL: `CREATE INDEX CONCURRENTLY "foo_idx" ON "public"."foobar" USING btree ("foo")`, RequiresNoTransaction: true},
+			expectedHazardCount: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt := addPgBouncerHazards(tc.stmt)
+			assert.Len(t, stmt.Hazards, tc.expectedHazardCount)
+			for _, hazard := range stmt.Hazards {
+				assert.Equal(t, MigrationHazardTypePgBouncerIncompatible, hazard.Type)
+			}
+		})
+	}
+}
+
+func TestPgBouncerMode(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+	table := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             []schema.Column{{Name: "foo", Type: "text"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	oldSchema := schema.Schema{Tables: []schema.Table{table}}
+	newSchema := schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{{
+		OwningTable:     foobar,
+		Name:            "foo_idx",
+		Columns:         []string{"foo"},
+		Method:          "btree",
+		GetIndexDefStmt: "CREATE INDEX foo_idx ON public.foobar USING btree (foo)",
+	}}}
+
+	t.Run("Statements that require no transaction are flagged", func(t *testing.T) {
+		plan, err := buildPlan(oldSchema, newSchema, &planOptions{pgBouncerMode: true})
+		require.NoError(t, err)
+		require.Len(t, plan.Statements, 1)
+		assert.True(t, plan.Statements[0].RequiresNoTransaction)
+		assert.Contains(t, plan.Statements[0].Hazards, migrationHazardPgBouncerNoTransaction)
+	})
+
+	t.Run("Statements are not flagged without the option", func(t *testing.T) {
+		plan, err := buildPlan(oldSchema, newSchema, &planOptions{})
+		require.NoError(t, err)
+		require.Len(t, plan.Statements, 1)
+		assert.NotContains(t, plan.Statements[0].Hazards, migrationHazardPgBouncerNoTransaction)
+	})
+
+	t.Run("Inserted statements are flagged", func(t *testing.T) {
+		plan, err := buildPlan(oldSchema, newSchema, &planOptions{pgBouncerMode: true})
+		require.NoError(t, err)
+		plan, err = plan.InsertStatement(0, Statement{DDL: "BEGIN; SET LOCAL lock_timeout = '1s'; COMMIT"})
+		require.NoError(t, err)
+		require.NotEmpty(t, plan.Statements[0].Hazards)
+		for _, hazard := range plan.Statements[0].Hazards {
+			assert.Equal(t, MigrationHazardTypePgBouncerIncompatible, hazard.Type)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"time"
 )
 
@@ -22,12 +23,23 @@ const (
 	MigrationHazardTypeIsUserGenerated               MigrationHazardType = "IS_USER_GENERATED"
 	MigrationHazardTypeExtensionVersionUpgrade       MigrationHazardType = "UPGRADING_EXTENSION_VERSION"
 	MigrationHazardTypeAuthzUpdate                   MigrationHazardType = "AUTHZ_UPDATE"
+	MigrationHazardTypeColumnOrderChange             MigrationHazardType = "COLUMN_ORDER_CHANGE"
+	MigrationHazardTypeImpossibleToRollback          MigrationHazardType = "IMPOSSIBLE_TO_ROLLBACK"
+	MigrationHazardTypeLongRunning                   MigrationHazardType = "LONG_RUNNING"
+	MigrationHazardTypeImpossibleWithoutDowntime     MigrationHazardType = "IMPOSSIBLE_WITHOUT_DOWNTIME"
+	MigrationHazardTypePgBouncerIncompatible         MigrationHazardType = "PGBOUNCER_INCOMPATIBLE"
+	MigrationHazardTypeRequiresSuperuser             MigrationHazardType = "REQUIRES_SUPERUSER"
+	MigrationHazardTypeValidateConstraint            MigrationHazardType = "VALIDATE_CONSTRAINT"
 )
 
 // MigrationHazard represents a hazard that a statement poses to a database
 type MigrationHazard struct {
 	Type    MigrationHazardType `json:"type"`
 	Message string              `json:"message"`
+	// Suppressed is true if an operator acknowledged the hazard is safe via Plan.SuppressHazard
+	Suppressed bool `json:"suppressed,omitempty"`
+	// Justification is the operator's reason the suppressed hazard is safe
+	Justification string `json:"justification,omitempty"`
 }
 
 func (p MigrationHazard) String() string {
@@ -46,22 +58,57 @@ type Statement struct {
 	LockTimeout time.Duration
 	// The hazards this statement poses
 	Hazards []MigrationHazard
+	// RequiresNoTransaction is true if the statement cannot be executed within a transaction block, e.g.,
+	// `CREATE INDEX CONCURRENTLY`. If implementing your own plan executor that wraps statements in transactions, be sure
+	// to commit any open transaction before executing this statement and to execute it outside a transaction.
+	RequiresNoTransaction bool
+	// IsAdvisory is true if the statement is only a recommendation to run after the migration, e.g., `ANALYZE`-ing a
+	// table to populate newly created statistics. Advisory statements are not executed when applying a plan. If
+	// implementing your own plan executor, be sure to skip them.
+	IsAdvisory bool
 }
 
 func (s Statement) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
-		DDL         string            `json:"ddl"`
-		Timeout     int64             `json:"timeout_ms"`
-		LockTimeout int64             `json:"lock_timeout_ms"`
-		Hazards     []MigrationHazard `json:"hazards"`
+		DDL                   string            `json:"ddl"`
+		Timeout               int64             `json:"timeout_ms"`
+		LockTimeout           int64             `json:"lock_timeout_ms"`
+		Hazards               []MigrationHazard `json:"hazards"`
+		RequiresNoTransaction bool              `json:"requires_no_transaction"`
+		IsAdvisory            bool              `json:"is_advisory"`
 	}{
-		DDL:         s.DDL,
-		Timeout:     s.Timeout.Milliseconds(),
-		LockTimeout: s.LockTimeout.Milliseconds(),
-		Hazards:     s.Hazards,
+		DDL:                   s.DDL,
+		Timeout:               s.Timeout.Milliseconds(),
+		LockTimeout:           s.LockTimeout.Milliseconds(),
+		Hazards:               s.Hazards,
+		RequiresNoTransaction: s.RequiresNoTransaction,
+		IsAdvisory:            s.IsAdvisory,
 	})
 }
 
+func (s *Statement) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		DDL                   string            `json:"ddl"`
+		Timeout               int64             `json:"timeout_ms"`
+		LockTimeout           int64             `json:"lock_timeout_ms"`
+		Hazards               []MigrationHazard `json:"hazards"`
+		RequiresNoTransaction bool              `json:"requires_no_transaction"`
+		IsAdvisory            bool              `json:"is_advisory"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*s = Statement{
+		DDL:                   aux.DDL,
+		Timeout:               time.Duration(aux.Timeout) * time.Millisecond,
+		LockTimeout:           time.Duration(aux.LockTimeout) * time.Millisecond,
+		Hazards:               aux.Hazards,
+		RequiresNoTransaction: aux.RequiresNoTransaction,
+		IsAdvisory:            aux.IsAdvisory,
+	}
+	return nil
+}
+
 func (s Statement) ToSQL() string {
 	return s.DDL + ";"
 }
@@ -74,6 +121,194 @@ type Plan struct {
 	// plan on running them later, you should verify that the current schema hash matches the current schema hash.
 	// To get the current schema hash, you can use schema.GetPublicSchemaHash(ctx, conn)
 	CurrentSchemaHash string `json:"current_schema_hash"`
+	// Dependencies are the ordering dependencies between the statements, e.g., an index must be built after its table is
+	// created. The statements must still be executed in order; the dependencies are only used to explain the order, e.g.,
+	// via WriteDependencyGraph. If nil, each statement is treated as depending on the statement before it.
+	Dependencies []StatementDependency `json:"dependencies"`
+	// RenameCandidates are the tables that might have been renamed. They are only detected if the plan is generated with
+	// WithDetectRenames. See Plan.ConfirmRename.
+	RenameCandidates []RenameCandidate `json:"rename_candidates,omitempty"`
+	// ColumnRenameCandidates are the columns that might have been renamed. They are only detected if the plan is
+	// generated with WithDetectColumnRenames. See Plan.ConfirmColumnRename.
+	ColumnRenameCandidates []ColumnRenameCandidate `json:"column_rename_candidates,omitempty"`
+	// Hazards are the hazards of the migration that are not caused by any of its statements, e.g., a change in column
+	// order that Postgres cannot apply, so it is ignored. They do not need to be acknowledged, since they are not caused
+	// by executing the plan.
+	Hazards []MigrationHazard `json:"hazards,omitempty"`
+
+	// migrationHooks are run around the execution of the plan by RunWithMigrationHooks. They are not serialized.
+	migrationHooks []MigrationHook
+	// statementHooks are run around the execution of each statement by RunStatementWithHooks. They are not serialized.
+	statementHooks []StatementHook
+	// progressReporter is reported to before the execution of each statement by ReportProgress. It is not serialized.
+	progressReporter ProgressReporter
+	// renameState is used by ConfirmRename and ConfirmColumnRename to re-generate the plan. It is not serialized.
+	renameState *renameState
+	// renderState is used by RenderHuman to describe the changes that the statements alone do not describe, e.g., the
+	// old types of altered columns. It is not serialized.
+	renderState *renderState
+	// pgBouncerMode is true if the plan was generated with WithPgBouncerMode, so statements inserted via InsertStatement
+	// are checked for PgBouncer incompatibilities. It is not serialized.
+	pgBouncerMode bool
+	// requireHazardAcknowledgment is true if the plan must not be executed while it has unsuppressed hazards. It is not
+	// serialized.
+	requireHazardAcknowledgment bool
+}
+
+// StatementDependency is an edge in the serialized plan: the statement at index Statement must run after the statement
+// at index DependsOn
+type StatementDependency struct {
+	Statement int `json:"statement"`
+	DependsOn int `json:"depends_on"`
+}
+
+// PlanSummary contains summary statistics of a plan. It is only included in the serialized plan for consumers of the
+// JSON, e.g., review tooling, and is ignored when deserializing
+type PlanSummary struct {
+	TotalStatements int `json:"total_statements"`
+	// HazardCounts is the number of hazards in the plan by type
+	HazardCounts map[MigrationHazardType]int `json:"hazard_counts"`
+}
+
+type planJSON struct {
+	Statements             []Statement             `json:"statements"`
+	CurrentSchemaHash      string                  `json:"current_schema_hash"`
+	Dependencies           []StatementDependency   `json:"dependencies"`
+	RenameCandidates       []RenameCandidate       `json:"rename_candidates,omitempty"`
+	ColumnRenameCandidates []ColumnRenameCandidate `json:"column_rename_candidates,omitempty"`
+	Hazards                []MigrationHazard       `json:"hazards,omitempty"`
+	Summary                PlanSummary             `json:"summary"`
+}
+
+// MarshalJSON serializes the plan. The output is deterministic for a given plan: statements are serialized in order,
+// and the dependency edges are sorted by statement index.
+func (p Plan) MarshalJSON() ([]byte, error) {
+	dependencies := p.getDependencies()
+	if dependencies == nil {
+		dependencies = []StatementDependency{}
+	}
+	hazardCounts := make(map[MigrationHazardType]int)
+	for _, stmt := range p.Statements {
+		for _, hazard := range stmt.Hazards {
+			hazardCounts[hazard.Type]++
+		}
+	}
+	for _, hazard := range p.Hazards {
+		hazardCounts[hazard.Type]++
+	}
+	statements := p.Statements
+	if statements == nil {
+		statements = []Statement{}
+	}
+	return json.Marshal(planJSON{
+		Statements:             statements,
+		CurrentSchemaHash:      p.CurrentSchemaHash,
+		Dependencies:           dependencies,
+		RenameCandidates:       p.RenameCandidates,
+		ColumnRenameCandidates: p.ColumnRenameCandidates,
+		Hazards:                p.Hazards,
+		Summary: PlanSummary{
+			TotalStatements: len(p.Statements),
+			HazardCounts:    hazardCounts,
+		},
+	})
+}
+
+// UnmarshalJSON deserializes a plan serialized by MarshalJSON. The dependency edges are validated against the order of
+// the statements, since the statements are executed in the order they are serialized.
+func (p *Plan) UnmarshalJSON(data []byte) error {
+	var aux planJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	for _, dep := range aux.Dependencies {
+		if dep.Statement < 0 || dep.Statement >= len(aux.Statements) || dep.DependsOn < 0 || dep.DependsOn >= len(aux.Statements) {
+			return fmt.Errorf("dependency %+v references a statement that does not exist", dep)
+		}
+		if dep.DependsOn >= dep.Statement {
+			return fmt.Errorf("statement %d depends on statement %d, which is not executed before it", dep.Statement, dep.DependsOn)
+		}
+	}
+	var statements []Statement
+	if len(aux.Statements) > 0 {
+		statements = aux.Statements
+	}
+	var dependencies []StatementDependency
+	if len(aux.Dependencies) > 0 {
+		dependencies = sortStatementDependencies(aux.Dependencies)
+	}
+	*p = Plan{
+		Statements:             statements,
+		CurrentSchemaHash:      aux.CurrentSchemaHash,
+		Dependencies:           dependencies,
+		RenameCandidates:       aux.RenameCandidates,
+		ColumnRenameCandidates: aux.ColumnRenameCandidates,
+		Hazards:                aux.Hazards,
+	}
+	return nil
+}
+
+// getDependencies returns the sorted dependencies of the plan. If the plan has no dependencies, e.g., it was not
+// generated, each statement depends on the statement before it
+func (p Plan) getDependencies() []StatementDependency {
+	if p.Dependencies == nil {
+		return buildSequentialDependencies(len(p.Statements))
+	}
+	return sortStatementDependencies(p.Dependencies)
+}
+
+// sortStatementDependencies returns a sorted copy of the dependencies, such that the output is deterministic
+func sortStatementDependencies(deps []StatementDependency) []StatementDependency {
+	if deps == nil {
+		return nil
+	}
+	sorted := append([]StatementDependency(nil), deps...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Statement != sorted[j].Statement {
+			return sorted[i].Statement < sorted[j].Statement
+		}
+		return sorted[i].DependsOn < sorted[j].DependsOn
+	})
+	return sorted
+}
+
+// ExecutableStatements returns the statements of the plan that are executed when applying it, i.e., the statements
+// that are not advisory
+func (p Plan) ExecutableStatements() []Statement {
+	var stmts []Statement
+	for _, stmt := range p.Statements {
+		if !stmt.IsAdvisory {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
+
+// AdvisoryStatements returns the statements of the plan that are only recommendations, e.g., `ANALYZE`-ing a table
+// whose columns changed. They are not executed when applying the plan.
+func (p Plan) AdvisoryStatements() []Statement {
+	var stmts []Statement
+	for _, stmt := range p.Statements {
+		if stmt.IsAdvisory {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
+
+// PlanToJSON serializes the plan, such that it can be stored and executed later via MigrationPlanFromJSON
+func PlanToJSON(plan Plan) ([]byte, error) {
+	return json.Marshal(plan)
+}
+
+// MigrationPlanFromJSON reconstructs a plan serialized by PlanToJSON without re-diffing the schemas. Before executing
+// the plan, be sure to verify the CurrentSchemaHash matches the hash of the database's current schema
+func MigrationPlanFromJSON(data []byte) (Plan, error) {
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return Plan{}, fmt.Errorf("unmarshalling plan: %w", err)
+	}
+	return plan, nil
 }
 
 // ApplyStatementTimeoutModifier applies the given timeout to all statements that match the given regex
@@ -105,11 +340,34 @@ func (p Plan) applyStatementModifier(regex *regexp.Regexp, modifier func(Stateme
 }
 
 // InsertStatement inserts the given statement at the given index. If index is equal to the length of the statements,
-// it will append the statement to the end of the statement in the plan
+// it will append the statement to the end of the statement in the plan. The inserted statement depends on the statement
+// before it, and the statement after it depends on the inserted statement.
 func (p Plan) InsertStatement(index int, statement Statement) (Plan, error) {
 	if index < 0 || index > len(p.Statements) {
 		return Plan{}, fmt.Errorf("index must be >= 0 and <= %d", len(p.Statements))
 	}
+	if p.pgBouncerMode {
+		statement = addPgBouncerHazards(statement)
+	}
+	if p.Dependencies != nil {
+		var deps []StatementDependency
+		shiftIdx := func(idx int) int {
+			if idx >= index {
+				return idx + 1
+			}
+			return idx
+		}
+		for _, dep := range p.Dependencies {
+			deps = append(deps, StatementDependency{Statement: shiftIdx(dep.Statement), DependsOn: shiftIdx(dep.DependsOn)})
+		}
+		if index > 0 {
+			deps = append(deps, StatementDependency{Statement: index, DependsOn: index - 1})
+		}
+		if index < len(p.Statements) {
+			deps = append(deps, StatementDependency{Statement: index + 1, DependsOn: index})
+		}
+		p.Dependencies = sortStatementDependencies(deps)
+	}
 	if index == len(p.Statements) {
 		p.Statements = append(p.Statements, statement)
 		return p, nil
@@ -30,9 +30,71 @@ type (
 		tempDbFactory           tempdb.Factory
 		dataPackNewTables       bool
 		ignoreChangesToColOrder bool
+		ignoreFormattingDiffs   bool
 		logger                  log.Logger
 		validatePlan            bool
 		getSchemaOpts           []schema.GetSchemaOpt
+		// reassignOwnedFrom and reassignOwnedTo are the roles used to build a REASSIGN OWNED statement. If
+		// reassignOwnedFrom is empty, no statement is generated.
+		reassignOwnedFrom string
+		reassignOwnedTo   string
+		// schemaRenames are the named schemas that are renamed, rather than dropped and re-created
+		schemaRenames []namedSchemaRename
+		// schemaMappings are the named schemas in the new schema that are compared with differently named schemas in
+		// the current schema. The oldName is the name in the new schema.
+		schemaMappings []namedSchemaRename
+		// tableRenames are the tables that are renamed, rather than dropped and re-created
+		tableRenames []tableRename
+		// detectRenames detects the tables that might have been renamed. See RenameCandidate.
+		detectRenames bool
+		// columnRenames are the columns that are renamed, rather than dropped and re-added
+		columnRenames []columnRename
+		// detectColumnRenames detects the columns that might have been renamed. See ColumnRenameCandidate.
+		detectColumnRenames bool
+		// repairInvalidIndexes rebuilds invalid indexes via REINDEX CONCURRENTLY rather than re-creating them
+		repairInvalidIndexes bool
+		// nonConcurrentIndexOps builds, drops, and rebuilds indexes without CONCURRENTLY
+		nonConcurrentIndexOps bool
+		// dropIndexesConcurrently overrides whether indexes are dropped with CONCURRENTLY. If nil, indexes are dropped
+		// concurrently unless nonConcurrentIndexOps is set.
+		dropIndexesConcurrently *bool
+		// onlineColumnTypeChange changes the types of columns via shadow columns rather than `SET DATA TYPE`
+		onlineColumnTypeChange bool
+		// migrationHooks are the hooks to run around the execution of the plan
+		migrationHooks []MigrationHook
+		// statementHooks are the hooks to run around the execution of each statement of the plan
+		statementHooks []StatementHook
+		// progressReporter is the reporter that the plan reports its progress to
+		progressReporter ProgressReporter
+		// addConstraintsNotValid overrides whether check and foreign key constraints on existing tables are added as
+		// NOT VALID and then validated. If nil, it is determined by the estimated row count of the table.
+		addConstraintsNotValid *bool
+		// notValidRowThreshold is the estimated row count at which constraints are added as NOT VALID
+		notValidRowThreshold int64
+		// pgBouncerMode flags statements that are incompatible with PgBouncer in transaction mode
+		pgBouncerMode bool
+		// rdsMode flags statements that require superuser, which is not available on Amazon RDS
+		rdsMode bool
+		// requireHazardAcknowledgment makes the Executor refuse to run the plan while it has unsuppressed hazards
+		requireHazardAcknowledgment bool
+		// hazardGenerators generate additional hazards for each statement of the plan
+		hazardGenerators []HazardGenerator
+		// additionalVertexGenerators generate the statements of custom schema objects
+		additionalVertexGenerators []VertexGenerator
+		// idempotentSQL generates statements that are no-ops if they were already applied
+		idempotentSQL bool
+		// doNotEmitAnalyzeAdvisories omits the advisory `ANALYZE` statements for tables whose columns are changed
+		doNotEmitAnalyzeAdvisories bool
+		// doNotEmitVacuumAdvisories omits the advisory `VACUUM FREEZE` statements for tables that are backfilled
+		doNotEmitVacuumAdvisories bool
+		// doNotNormalizeFunctionBodies compares the bodies of functions and procedures exactly, rather than ignoring
+		// differences in formatting. See FunctionNormalizer.
+		doNotNormalizeFunctionBodies bool
+		// lockTimeout overrides the lock timeout of every statement if non-zero
+		lockTimeout time.Duration
+		// estimatedRowsByTableName is the estimated row count of each table in the current schema. It is populated by
+		// Generate if the current schema is fetched from a database.
+		estimatedRowsByTableName map[string]int64
 	}
 
 	PlanOpt func(opts *planOptions)
@@ -60,6 +122,26 @@ func WithRespectColumnOrder() PlanOpt {
 	}
 }
 
+// WithIgnoreFormattingDifferences configures the plan generation to ignore differences in the definitions of
+// functions, procedures, and views that only consist of comments, whitespace, and keyword casing. Only the bodies of
+// SQL and PL/pgSQL functions and procedures are normalized; bodies in other languages, e.g., PL/Python, are compared
+// exactly. This is useful if the new schema's DDL is auto-formatted. Use with caution: normalizing the definitions might hide subtle but
+// significant differences.
+func WithIgnoreFormattingDifferences() PlanOpt {
+	return func(opts *planOptions) {
+		opts.ignoreFormattingDiffs = true
+	}
+}
+
+// WithDoNotNormalizeFunctionBodies configures the plan generation to re-create functions and procedures whose bodies
+// only differ in formatting, e.g., indentation or comments. By default, bodies are normalized before they are compared.
+// See FunctionNormalizer.
+func WithDoNotNormalizeFunctionBodies() PlanOpt {
+	return func(opts *planOptions) {
+		opts.doNotNormalizeFunctionBodies = true
+	}
+}
+
 // WithDoNotValidatePlan disables plan validation, where the migration plan is tested against a temporary database
 // instance.
 func WithDoNotValidatePlan() PlanOpt {
@@ -87,6 +169,193 @@ func WithExcludeSchemas(schemas ...string) PlanOpt {
 	}
 }
 
+// WithIncludeObjects filters the plan to only the objects matching any of the glob patterns, e.g., "public.orders_*".
+// Objects that do not match are treated as if they do not exist in either schema. See schema.WithIncludeObjects.
+func WithIncludeObjects(patterns ...string) PlanOpt {
+	return func(opts *planOptions) {
+		opts.getSchemaOpts = append(opts.getSchemaOpts, schema.WithIncludeObjects(patterns...))
+	}
+}
+
+// WithExcludeObjects filters the objects matching any of the glob patterns out of the plan. See
+// schema.WithExcludeObjects.
+func WithExcludeObjects(patterns ...string) PlanOpt {
+	return func(opts *planOptions) {
+		opts.getSchemaOpts = append(opts.getSchemaOpts, schema.WithExcludeObjects(patterns...))
+	}
+}
+
+// WithOnlySchemas is the equivalent of `pg_dump --schema`. See schema.WithOnlySchemas.
+func WithOnlySchemas(schemas ...string) PlanOpt {
+	return func(opts *planOptions) {
+		opts.getSchemaOpts = append(opts.getSchemaOpts, schema.WithOnlySchemas(schemas...))
+	}
+}
+
+// WithReassignOwned configures the plan generation to prepend a `REASSIGN OWNED BY fromRole TO toRole` statement to the
+// plan if any objects in the current schema are owned by fromRole. This is useful when the role that owns the schema
+// objects is changing.
+func WithReassignOwned(fromRole, toRole string) PlanOpt {
+	return func(opts *planOptions) {
+		opts.reassignOwnedFrom = fromRole
+		opts.reassignOwnedTo = toRole
+		opts.getSchemaOpts = append(opts.getSchemaOpts, schema.WithObjectOwners())
+	}
+}
+
+// WithOwners configures the plan generation to diff the owners of schemas, tables, views, sequences, and functions,
+// i.e., `ALTER ... OWNER TO`. Roles are not tracked, so changing the owner to a role that does not own any objects in
+// the current schema has a hazard. Objects created from DDL are owned by the role that ran it, so DDL schema sources
+// must set the owners explicitly, e.g., `ALTER TABLE foo OWNER TO some_role`. See schema.WithOwners.
+func WithOwners() PlanOpt {
+	return func(opts *planOptions) {
+		opts.getSchemaOpts = append(opts.getSchemaOpts, schema.WithOwners())
+	}
+}
+
+// WithPrivileges configures the plan generation to diff the privileges granted on tables, views, sequences, functions,
+// and procedures, i.e., `GRANT` and `REVOKE`, and the default privileges, i.e., `ALTER DEFAULT PRIVILEGES`. Without it, privileges are left untouched, since privileges that are
+// granted outside the schema source, e.g., by a DBA, would otherwise be revoked. See schema.WithPrivileges.
+func WithPrivileges() PlanOpt {
+	return func(opts *planOptions) {
+		opts.getSchemaOpts = append(opts.getSchemaOpts, schema.WithPrivileges())
+	}
+}
+
+// WithRenamedSchema configures the plan generation to rename the named schema oldName to newName via
+// `ALTER SCHEMA ... RENAME TO ...`, rather than dropping and re-creating the schema and all the objects within it. The
+// objects in the renamed schema are diffed against the objects in the new schema as usual.
+func WithRenamedSchema(oldName, newName string) PlanOpt {
+	return func(opts *planOptions) {
+		opts.schemaRenames = append(opts.schemaRenames, namedSchemaRename{oldName: oldName, newName: newName})
+	}
+}
+
+// WithRenamedTable configures the plan generation to rename the table schemaName.oldName to schemaName.newName via
+// `ALTER TABLE ... RENAME TO ...`, rather than dropping the old table and creating the new table. The renamed table is
+// diffed against the new table as usual. The names are unescaped. If a schema is also renamed, schemaName is the new
+// name of the schema.
+func WithRenamedTable(schemaName, oldName, newName string) PlanOpt {
+	return func(opts *planOptions) {
+		opts.tableRenames = append(opts.tableRenames, tableRename{
+			old: schema.SchemaQualifiedName{SchemaName: schemaName, EscapedName: schema.EscapeIdentifier(oldName)},
+			new: schema.SchemaQualifiedName{SchemaName: schemaName, EscapedName: schema.EscapeIdentifier(newName)},
+		})
+	}
+}
+
+// WithDetectRenames configures the plan generation to detect tables that might have been renamed, i.e., a table that
+// was dropped and a table that was created with the same columns. The candidates are returned via
+// Plan.RenameCandidates, and the plan still drops and re-creates the tables until a candidate is confirmed via
+// Plan.ConfirmRename.
+func WithDetectRenames() PlanOpt {
+	return func(opts *planOptions) {
+		opts.detectRenames = true
+	}
+}
+
+// WithRenamedColumn configures the plan generation to rename the column oldCol of the table schemaName.tableName to
+// newCol via `ALTER TABLE ... RENAME COLUMN ... TO ...`, rather than dropping the old column and adding the new column.
+// The names are unescaped. If the table is also renamed, tableName is the new name of the table.
+func WithRenamedColumn(schemaName, tableName, oldCol, newCol string) PlanOpt {
+	return func(opts *planOptions) {
+		opts.columnRenames = append(opts.columnRenames, columnRename{
+			table: schema.SchemaQualifiedName{SchemaName: schemaName, EscapedName: schema.EscapeIdentifier(tableName)},
+			old:   oldCol,
+			new:   newCol,
+		})
+	}
+}
+
+// WithDetectColumnRenames configures the plan generation to detect columns that might have been renamed, i.e., a
+// column that was dropped from a table and a column with the same type that was added to it. The candidates are
+// returned via Plan.ColumnRenameCandidates, and the plan still drops and re-adds the columns until a candidate is
+// confirmed via Plan.ConfirmColumnRename.
+func WithDetectColumnRenames() PlanOpt {
+	return func(opts *planOptions) {
+		opts.detectColumnRenames = true
+	}
+}
+
+// WithRepairInvalidIndexes configures the plan generation to rebuild invalid indexes, e.g., indexes left behind by a
+// failed `CREATE INDEX CONCURRENTLY`, via `REINDEX INDEX CONCURRENTLY` rather than dropping and re-creating them. Only
+// invalid indexes that are otherwise unchanged in the new schema are rebuilt.
+func WithRepairInvalidIndexes() PlanOpt {
+	return func(opts *planOptions) {
+		opts.repairInvalidIndexes = true
+	}
+}
+
+// WithDoNotUseConcurrentIndexOperations configures the plan generation to build, drop, and rebuild indexes without
+// `CONCURRENTLY`. By default, index operations are done concurrently, so they do not lock out writes but cannot be
+// executed within a transaction block. Non-concurrent index operations lock out writes to the table (and, for
+// drops, all accesses) until they complete, which can take a while for large tables.
+func WithDoNotUseConcurrentIndexOperations() PlanOpt {
+	return func(opts *planOptions) {
+		opts.nonConcurrentIndexOps = true
+	}
+}
+
+// WithDropIndexesConcurrently configures whether indexes are dropped via `DROP INDEX CONCURRENTLY`, overriding
+// WithDoNotUseConcurrentIndexOperations for drops. A concurrent drop does not lock out reads and writes to the table,
+// but the statement cannot be executed within a transaction block, so it has RequiresNoTransaction set. A plain
+// `DROP INDEX` acquires an access exclusive lock on the table.
+//
+// Indexes that back a constraint, e.g., a primary key or unique constraint, are always dropped via
+// `ALTER TABLE ... DROP CONSTRAINT`, and indexes on partitioned tables are never dropped concurrently. By default,
+// indexes are dropped concurrently.
+func WithDropIndexesConcurrently(dropConcurrently bool) PlanOpt {
+	return func(opts *planOptions) {
+		opts.dropIndexesConcurrently = &dropConcurrently
+	}
+}
+
+// WithAddConstraintsNotValid configures whether check and foreign key constraints on existing tables are added as
+// `NOT VALID` and then validated via `VALIDATE CONSTRAINT`, regardless of the size of the table. Adding a constraint as
+// `NOT VALID` only locks the table for a moment, and validating it only takes a `SHARE UPDATE EXCLUSIVE` lock. Adding
+// the constraint as valid in one statement locks the table while all the existing rows are scanned.
+//
+// By default, constraints are added as NOT VALID on tables whose estimated row count is at least the threshold set by
+// WithAddConstraintsNotValidRowThreshold.
+func WithAddConstraintsNotValid(addNotValid bool) PlanOpt {
+	return func(opts *planOptions) {
+		opts.addConstraintsNotValid = &addNotValid
+	}
+}
+
+// WithAddConstraintsNotValidRowThreshold configures the estimated row count, from pg_class.reltuples, at which check
+// and foreign key constraints on existing tables are added as NOT VALID and then validated. Constraints on smaller
+// tables are added in one statement. Tables without an estimate, e.g., tables that have never been analyzed, are
+// assumed to be over the threshold. The default threshold is 0, i.e., constraints are always added as NOT VALID.
+func WithAddConstraintsNotValidRowThreshold(threshold int64) PlanOpt {
+	return func(opts *planOptions) {
+		opts.notValidRowThreshold = threshold
+	}
+}
+
+// WithOnlineColumnTypeChange configures the plan generation to change the types of columns via a shadow column rather
+// than `ALTER COLUMN ... SET DATA TYPE`: a generated column that casts the old column to the new type is added, swapped
+// with the old column via renames, and the old column is dropped. Adding the generated column still rewrites the
+// table, but readers of the column never observe a partially converted table, and the old column's values are kept
+// until the swap succeeds. The replaced column is moved to the end of the table.
+//
+// Columns whose casts are not immutable, or that are referenced by other objects, e.g., indexes, are changed via
+// `SET DATA TYPE` with a MigrationHazardTypeImpossibleWithoutDowntime hazard. Requires Postgres 13 or later.
+func WithOnlineColumnTypeChange() PlanOpt {
+	return func(opts *planOptions) {
+		opts.onlineColumnTypeChange = true
+	}
+}
+
+// WithLockTimeout sets the lock timeout of every statement of the plan, i.e., how long a statement waits to acquire a
+// lock before failing. A low lock timeout prevents a statement that is waiting on a lock from blocking the queries
+// queued behind it. Combine it with WithLockTimeoutRetries to retry the statements that time out.
+func WithLockTimeout(timeout time.Duration) PlanOpt {
+	return func(opts *planOptions) {
+		opts.lockTimeout = timeout
+	}
+}
+
 func WithGetSchemaOpts(getSchemaOpts ...externalschema.GetSchemaOpt) PlanOpt {
 	return func(opts *planOptions) {
 		opts.getSchemaOpts = append(opts.getSchemaOpts, getSchemaOpts...)
@@ -125,6 +394,17 @@ func Generate(
 	targetSchema SchemaSource,
 	opts ...PlanOpt,
 ) (Plan, error) {
+	planOptions := buildPlanOptions(opts)
+
+	currentSchema, newSchema, err := getCurrentAndNewSchemas(ctx, fromSchema, targetSchema, planOptions)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return generateForSchemas(ctx, currentSchema, newSchema, planOptions)
+}
+
+func buildPlanOptions(opts []PlanOpt) *planOptions {
 	planOptions := &planOptions{
 		validatePlan:            true,
 		ignoreChangesToColOrder: true,
@@ -133,14 +413,25 @@ func Generate(
 	for _, opt := range opts {
 		opt(planOptions)
 	}
+	return planOptions
+}
 
+// getCurrentAndNewSchemas gets the schemas from the schema sources. If the current schema is fetched from a database,
+// the estimated row counts of its tables are stored in the plan options.
+func getCurrentAndNewSchemas(ctx context.Context, fromSchema, targetSchema SchemaSource, planOptions *planOptions) (schema.Schema, schema.Schema, error) {
 	currentSchema, err := fromSchema.GetSchema(ctx, schemaSourcePlanDeps{
 		tempDBFactory: planOptions.tempDbFactory,
 		logger:        planOptions.logger,
 		getSchemaOpts: planOptions.getSchemaOpts,
 	})
 	if err != nil {
-		return Plan{}, fmt.Errorf("getting current schema: %w", err)
+		return schema.Schema{}, schema.Schema{}, fmt.Errorf("getting current schema: %w", err)
+	}
+	if estimator, ok := fromSchema.(tableRowEstimator); ok {
+		planOptions.estimatedRowsByTableName, err = estimator.getTableRowEstimates(ctx)
+		if err != nil {
+			return schema.Schema{}, schema.Schema{}, fmt.Errorf("getting table row estimates: %w", err)
+		}
 	}
 	newSchema, err := targetSchema.GetSchema(ctx, schemaSourcePlanDeps{
 		tempDBFactory: planOptions.tempDbFactory,
@@ -148,13 +439,70 @@ func Generate(
 		getSchemaOpts: planOptions.getSchemaOpts,
 	})
 	if err != nil {
-		return Plan{}, fmt.Errorf("getting new schema: %w", err)
+		return schema.Schema{}, schema.Schema{}, fmt.Errorf("getting new schema: %w", err)
+	}
+	newSchema, err = applySchemaMappings(newSchema, planOptions.schemaMappings)
+	if err != nil {
+		return schema.Schema{}, schema.Schema{}, err
+	}
+	return currentSchema, newSchema, nil
+}
+
+// generateForSchemas generates the plan to migrate the current schema to the new schema, validating it unless
+// validation is disabled
+func generateForSchemas(ctx context.Context, currentSchema, newSchema schema.Schema, planOptions *planOptions) (Plan, error) {
+	if planOptions.ignoreChangesToColOrder {
+		warnAboutColumnOrderChanges(currentSchema, newSchema, planOptions.logger)
+	}
+	if planOptions.ignoreFormattingDiffs {
+		planOptions.logger.Warnf("ignoring formatting differences: definitions of functions, procedures, and views are normalized before they are compared")
 	}
 
-	statements, err := generateMigrationStatements(currentSchema, newSchema, planOptions)
+	plan, err := buildPlan(currentSchema, newSchema, planOptions)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	if planOptions.validatePlan {
+		if planOptions.tempDbFactory == nil {
+			return Plan{}, fmt.Errorf("cannot validate plan without a tempDbFactory: %w", errTempDbFactoryRequired)
+		}
+		if err := assertValidPlan(ctx, planOptions.tempDbFactory, currentSchema, newSchema, plan, planOptions); err != nil {
+			return Plan{}, fmt.Errorf("validating migration plan: %w \n%# v", err, pretty.Formatter(plan))
+		}
+	}
+
+	return plan, nil
+}
+
+// buildPlan builds the plan to migrate the current schema to the new schema. The plan is not validated.
+func buildPlan(currentSchema, newSchema schema.Schema, planOptions *planOptions) (Plan, error) {
+	statements, dependencies, err := generateMigrationStatementsWithDependencies(currentSchema, newSchema, planOptions)
 	if err != nil {
 		return Plan{}, fmt.Errorf("generating plan statements: %w", err)
 	}
+	if reassignStatement, ok := buildReassignOwnedStatement(currentSchema, planOptions); ok {
+		statements, dependencies = appendStatementsWithDependencies([]Statement{reassignStatement}, nil, statements, dependencies)
+	}
+	if planOptions.pgBouncerMode {
+		for i := range statements {
+			statements[i] = addPgBouncerHazards(statements[i])
+		}
+	}
+	if planOptions.idempotentSQL {
+		for i := range statements {
+			statements[i] = makeStatementIdempotent(statements[i])
+		}
+	}
+	if planOptions.lockTimeout > 0 {
+		for i := range statements {
+			statements[i].LockTimeout = planOptions.lockTimeout
+		}
+	}
+	statements, err = addGeneratedHazards(statements, planOptions.hazardGenerators)
+	if err != nil {
+		return Plan{}, err
+	}
 
 	hash, err := currentSchema.Hash()
 	if err != nil {
@@ -162,16 +510,36 @@ func Generate(
 	}
 
 	plan := Plan{
-		Statements:        statements,
-		CurrentSchemaHash: hash,
+		Statements:                  statements,
+		CurrentSchemaHash:           hash,
+		Dependencies:                sortStatementDependencies(dependencies),
+		migrationHooks:              planOptions.migrationHooks,
+		statementHooks:              planOptions.statementHooks,
+		progressReporter:            planOptions.progressReporter,
+		pgBouncerMode:               planOptions.pgBouncerMode,
+		requireHazardAcknowledgment: planOptions.requireHazardAcknowledgment,
 	}
 
-	if planOptions.validatePlan {
-		if planOptions.tempDbFactory == nil {
-			return Plan{}, fmt.Errorf("cannot validate plan without a tempDbFactory: %w", errTempDbFactoryRequired)
+	renamedSchema, _, err := applyRenames(currentSchema, planOptions)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan.renderState = newRenderState(renamedSchema)
+	if planOptions.ignoreChangesToColOrder {
+		plan.Hazards = buildColumnOrderChangeHazards(renamedSchema, newSchema)
+	}
+
+	if planOptions.detectRenames || planOptions.detectColumnRenames {
+		if planOptions.detectRenames {
+			plan.RenameCandidates = detectTableRenameCandidates(renamedSchema, newSchema)
 		}
-		if err := assertValidPlan(ctx, planOptions.tempDbFactory, currentSchema, newSchema, plan, planOptions); err != nil {
-			return Plan{}, fmt.Errorf("validating migration plan: %w \n%# v", err, pretty.Formatter(plan))
+		if planOptions.detectColumnRenames {
+			plan.ColumnRenameCandidates = detectColumnRenameCandidates(renamedSchema, newSchema)
+		}
+		plan.renameState = &renameState{
+			currentSchema: currentSchema,
+			newSchema:     newSchema,
+			planOptions:   *planOptions,
 		}
 	}
 
@@ -179,16 +547,48 @@ func Generate(
 }
 
 func generateMigrationStatements(oldSchema, newSchema schema.Schema, planOptions *planOptions) ([]Statement, error) {
+	statements, _, err := generateMigrationStatementsWithDependencies(oldSchema, newSchema, planOptions)
+	return statements, err
+}
+
+// generateMigrationStatementsWithDependencies generates the migration statements and the ordering dependencies between
+// them
+func generateMigrationStatementsWithDependencies(oldSchema, newSchema schema.Schema, planOptions *planOptions) ([]Statement, []StatementDependency, error) {
+	oldSchema, renameStatements, err := applyRenames(oldSchema, planOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !planOptions.doNotNormalizeFunctionBodies {
+		oldSchema = normalizeFunctionBodies(oldSchema, newSchema)
+	}
+	if planOptions.ignoreFormattingDiffs {
+		oldSchema = ignoreFormattingDifferences(oldSchema, newSchema)
+	}
+
+	var reindexStatements []Statement
+	if planOptions.repairInvalidIndexes {
+		oldSchema, reindexStatements = repairInvalidIndexes(oldSchema, newSchema, planOptions.nonConcurrentIndexOps)
+	}
+
+	var columnTypeChangeStatements []Statement
+	if planOptions.onlineColumnTypeChange {
+		oldSchema, columnTypeChangeStatements, err = changeColumnTypesOnline(oldSchema, newSchema, planOptions.ignoreChangesToColOrder)
+		if err != nil {
+			return nil, nil, fmt.Errorf("changing column types online: %w", err)
+		}
+	}
+
 	diff, _, err := buildSchemaDiff(oldSchema, newSchema)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if planOptions.dataPackNewTables {
 		// Instead of enabling ignoreChangesToColOrder by default, force the user to enable ignoreChangesToColOrder.
 		// This ensures the user knows what's going on behind-the-scenes
 		if !planOptions.ignoreChangesToColOrder {
-			return nil, fmt.Errorf("cannot data pack new tables without also ignoring changes to column order")
+			return nil, nil, fmt.Errorf("cannot data pack new tables without also ignoring changes to column order")
 		}
 		diff = dataPackNewTables(diff)
 	}
@@ -196,11 +596,121 @@ func generateMigrationStatements(oldSchema, newSchema schema.Schema, planOptions
 		diff = removeChangesToColumnOrdering(diff)
 	}
 
-	statements, err := diff.resolveToSQL()
+	nonConcurrentIndexDrops := planOptions.nonConcurrentIndexOps
+	if planOptions.dropIndexesConcurrently != nil {
+		nonConcurrentIndexDrops = !*planOptions.dropIndexesConcurrently
+	}
+	statements, dependencies, err := diff.resolveToSQL(planOptions.nonConcurrentIndexOps, nonConcurrentIndexDrops, constraintValidationOptions{
+		addNotValid:              planOptions.addConstraintsNotValid,
+		rowThreshold:             planOptions.notValidRowThreshold,
+		estimatedRowsByTableName: planOptions.estimatedRowsByTableName,
+	}, planOptions.onlineColumnTypeChange, planOptions.rdsMode, planOptions.additionalVertexGenerators)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating migration statements: %w", err)
+	}
+	if planOptions.rdsMode {
+		statements = applyRDSRestrictions(statements)
+	}
+	preDiffStatements := append(append(renameStatements, reindexStatements...), columnTypeChangeStatements...)
+	statements, dependencies = appendStatementsWithDependencies(preDiffStatements, buildSequentialDependencies(len(preDiffStatements)), statements, dependencies)
+	statements, dependencies = appendStatementsWithDependencies(statements, dependencies, buildAdvisoryMaintenanceStatements(statements, planOptions), nil)
+	return statements, dependencies, nil
+}
+
+// applyRenames renames the schemas, then the tables, and then the columns in the old schema. It returns the renamed schema and the
+// statements to rename them, which must run before all other statements, since the other statements reference the
+// renamed objects.
+func applyRenames(oldSchema schema.Schema, planOptions *planOptions) (schema.Schema, []Statement, error) {
+	var renameStatements []Statement
+	oldSchema, schemaRenames, err := renameNamedSchemas(oldSchema, planOptions.schemaRenames)
+	if err != nil {
+		return schema.Schema{}, nil, fmt.Errorf("renaming schemas: %w", err)
+	}
+	for _, rename := range schemaRenames {
+		renameStatements = append(renameStatements, buildRenameNamedSchemaStatement(rename))
+	}
+
+	oldSchema, tableRenames, err := renameTables(oldSchema, planOptions.tableRenames)
+	if err != nil {
+		return schema.Schema{}, nil, fmt.Errorf("renaming tables: %w", err)
+	}
+	for _, rename := range tableRenames {
+		renameStatements = append(renameStatements, buildRenameTableStatement(rename))
+	}
+
+	columnRenamedSchema, columnRenames, err := renameColumns(oldSchema, planOptions.columnRenames)
 	if err != nil {
-		return nil, fmt.Errorf("generating migration statements: %w", err)
+		return schema.Schema{}, nil, fmt.Errorf("renaming columns: %w", err)
+	}
+	for _, rename := range columnRenames {
+		renameStatements = append(renameStatements, buildRenameColumnStatement(oldSchema, rename))
+	}
+	return columnRenamedSchema, renameStatements, nil
+}
+
+// warnAboutColumnOrderChanges logs a warning for every table whose column order changed, since the change will be
+// ignored. The changes are also surfaced as hazards of the plan via buildColumnOrderChangeHazards.
+func warnAboutColumnOrderChanges(currentSchema, newSchema schema.Schema, logger log.Logger) {
+	newTablesByName := buildSchemaObjByNameMap(newSchema.Tables)
+	for _, table := range currentSchema.Tables {
+		newTable, ok := newTablesByName[table.GetName()]
+		if !ok {
+			continue
+		}
+		if hasColumnOrderChanged(table, newTable) {
+			logger.Warnf("the column order of table %s changed. Postgres cannot re-order columns without re-creating the table, so the change will be ignored", table.GetFQEscapedName())
+		}
 	}
-	return statements, nil
+}
+
+// buildColumnOrderChangeHazards builds a MigrationHazardTypeColumnOrderChange hazard for every table whose column order
+// changed. Postgres cannot re-order columns without re-creating the table, so the change is ignored and no statement
+// is generated for it. The hazards belong to the plan rather than a statement, such that they are surfaced even if the
+// table is not otherwise altered.
+func buildColumnOrderChangeHazards(currentSchema, newSchema schema.Schema) []MigrationHazard {
+	newTablesByName := buildSchemaObjByNameMap(newSchema.Tables)
+	var hazards []MigrationHazard
+	for _, table := range currentSchema.Tables {
+		newTable, ok := newTablesByName[table.GetName()]
+		if !ok || !hasColumnOrderChanged(table, newTable) {
+			continue
+		}
+		hazards = append(hazards, MigrationHazard{
+			Type: MigrationHazardTypeColumnOrderChange,
+			Message: fmt.Sprintf("The order of the columns of %s changed. Postgres cannot re-order columns without "+
+				"re-creating the table, so the new order will not be applied. Queries that rely on column order, e.g., "+
+				"`SELECT *`, will see the old order.", table.GetFQEscapedName()),
+		})
+	}
+	return hazards
+}
+
+// buildReassignOwnedStatement builds the REASSIGN OWNED statement configured by WithReassignOwned. It returns false if
+// the option is not set or no objects in the schema are owned by the role being reassigned from.
+func buildReassignOwnedStatement(currentSchema schema.Schema, planOptions *planOptions) (Statement, bool) {
+	if len(planOptions.reassignOwnedFrom) == 0 {
+		return Statement{}, false
+	}
+	var isOwner bool
+	for _, owner := range currentSchema.ObjectOwners {
+		if owner == planOptions.reassignOwnedFrom {
+			isOwner = true
+			break
+		}
+	}
+	if !isOwner {
+		return Statement{}, false
+	}
+
+	return Statement{
+		DDL:         fmt.Sprintf("REASSIGN OWNED BY %s TO %s", schema.EscapeIdentifier(planOptions.reassignOwnedFrom), schema.EscapeIdentifier(planOptions.reassignOwnedTo)),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards: []MigrationHazard{{
+			Type:    MigrationHazardTypeAuthzUpdate,
+			Message: "Reassigning ownership changes the privileges of both roles on the reassigned objects. This affects all objects owned by the role in the current database, not just those in the diffed schemas.",
+		}},
+	}, true
 }
 
 func assertValidPlan(ctx context.Context,
@@ -278,16 +788,26 @@ func schemaFromTempDb(ctx context.Context, db *tempdb.Database, plan *planOption
 }
 
 func assertMigratedSchemaMatchesTarget(migratedSchema, targetSchema schema.Schema, planOptions *planOptions) error {
-	toTargetSchemaStmts, err := generateMigrationStatements(migratedSchema, targetSchema, planOptions)
+	// The renames were already executed by the plan, so they must not be applied to the migrated schema again
+	validationOptions := *planOptions
+	validationOptions.schemaRenames = nil
+	validationOptions.tableRenames = nil
+	validationOptions.columnRenames = nil
+	toTargetSchemaStmts, err := generateMigrationStatements(migratedSchema, targetSchema, &validationOptions)
 	if err != nil {
 		return fmt.Errorf("building schema diff between migrated database and new schema: %w", err)
 	}
 
-	if len(toTargetSchemaStmts) > 0 {
-		var stmtsStrs []string
-		for _, stmt := range toTargetSchemaStmts {
-			stmtsStrs = append(stmtsStrs, stmt.DDL)
+	var stmtsStrs []string
+	for _, stmt := range toTargetSchemaStmts {
+		if stmt.IsAdvisory {
+			// Advisory statements are not executed when the plan is applied, so the differences they resolve are
+			// expected to remain
+			continue
 		}
+		stmtsStrs = append(stmtsStrs, stmt.DDL)
+	}
+	if len(stmtsStrs) > 0 {
 		return fmt.Errorf("validating plan failed. diff detected:\n%s", strings.Join(stmtsStrs, "\n"))
 	}
 
@@ -314,8 +834,11 @@ func executeStatementsIgnoreTimeouts(ctx context.Context, connPool *sql.DB, stat
 	// must be executed within its own transaction block. Postgres will error if you try to set a TRANSACTION-level
 	// timeout for it. SESSION-level statement_timeouts are respected by `ADD INDEX CONCURRENTLY`
 	for _, stmt := range statements {
+		if stmt.IsAdvisory {
+			continue
+		}
 		if _, err := conn.ExecContext(ctx, stmt.ToSQL()); err != nil {
-			return fmt.Errorf("executing migration statement: %s: %w", stmt, err)
+			return fmt.Errorf("executing migration statement: %s: %w", stmt.ToSQL(), err)
 		}
 	}
 	return nil
@@ -33,6 +33,10 @@ func (f fakeSchemaSource) GetSchema(_ context.Context, deps schemaSourcePlanDeps
 	return f.schema, f.err
 }
 
+func (f fakeSchemaSource) Validate() error {
+	return nil
+}
+
 type planGeneratorTestSuite struct {
 	suite.Suite
 
@@ -191,6 +195,57 @@ func (suite *planGeneratorTestSuite) TestGenerate_CannotValidateWithoutTempDbFac
 	suite.ErrorContains(err, "tempDbFactory is required")
 }
 
+func (suite *planGeneratorTestSuite) TestDetectDrift() {
+	expectedDDL := `
+	CREATE TABLE foobar(
+	    id INT PRIMARY KEY,
+	    foo TEXT
+	);
+	CREATE INDEX foo_idx ON foobar(foo);
+	CREATE FUNCTION add(a integer, b integer) RETURNS integer
+	    LANGUAGE SQL
+	    IMMUTABLE
+	    RETURN a + b;
+	`
+	suite.mustApplyDDLToTestDb([]string{expectedDDL})
+
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	tempDbFactory := suite.mustBuildTempDbFactory(context.Background())
+	defer tempDbFactory.Close()
+
+	report, err := DetectDrift(context.Background(), connPool, DDLSchemaSource([]string{expectedDDL}), WithTempDbFactory(tempDbFactory))
+	suite.Require().NoError(err)
+	suite.False(report.HasDrift)
+	suite.Empty(report.Plan.Statements)
+	suite.Empty(report.Items)
+
+	suite.mustApplyDDLToTestDb([]string{`
+	ALTER TABLE foobar ADD COLUMN bar TEXT;
+	DROP INDEX foo_idx;
+	CREATE OR REPLACE FUNCTION add(a integer, b integer) RETURNS integer
+	    LANGUAGE SQL
+	    IMMUTABLE
+	    RETURN a - b;
+	`})
+
+	report, err = DetectDrift(context.Background(), connPool, DDLSchemaSource([]string{expectedDDL}), WithTempDbFactory(tempDbFactory))
+	suite.Require().NoError(err)
+	suite.True(report.HasDrift)
+	suite.Equal([]DriftItem{
+		{ObjectType: "column", Name: `"public"."foobar"."bar"`, Kind: DriftKindAdded},
+		{ObjectType: "index", Name: `"public"."foo_idx"`, Kind: DriftKindRemoved},
+		{ObjectType: "function", Name: `"public"."add"(a integer, b integer)`, Kind: DriftKindModified},
+	}, report.Items)
+
+	// Applying the plan resolves the drift
+	suite.mustApplyMigrationPlan(connPool, report.Plan)
+	report, err = DetectDrift(context.Background(), connPool, DDLSchemaSource([]string{expectedDDL}), WithTempDbFactory(tempDbFactory))
+	suite.Require().NoError(err)
+	suite.False(report.HasDrift)
+}
+
 func TestSimpleMigratorTestSuite(t *testing.T) {
 	suite.Run(t, new(planGeneratorTestSuite))
 }
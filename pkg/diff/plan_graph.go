@@ -0,0 +1,124 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxDependencyGraphLabelLen is the maximum length of the DDL summary in a node's label
+	maxDependencyGraphLabelLen = 60
+
+	defaultDependencyGraphNodeColor = "white"
+)
+
+var (
+	// statementObjTypeRegex extracts the type of object a statement operates on, e.g., "CREATE UNIQUE INDEX ..." -> "INDEX"
+	statementObjTypeRegex = regexp.MustCompile(`(?is)^\s*(?:CREATE|ALTER|DROP|COMMENT\s+ON|REFRESH|REINDEX)\s+` +
+		`(?:OR\s+REPLACE\s+)?(?:UNIQUE\s+)?(?:UNLOGGED\s+)?(?:CONSTRAINT\s+)?` +
+		`(MATERIALIZED\s+VIEW|FOREIGN\s+TABLE|EVENT\s+TRIGGER|TEXT\s+SEARCH|FOREIGN\s+DATA\s+WRAPPER|OPERATOR\s+CLASS|[A-Z]+)\b`)
+
+	// dependencyGraphNodeColorsByObjType is the fill color of a node by the type of object its statement operates on
+	dependencyGraphNodeColorsByObjType = map[string]string{
+		"TABLE":                "lightblue",
+		"FOREIGN TABLE":        "lightblue",
+		"COLUMN":               "lightblue",
+		"ANALYZE":              "lightblue",
+		"INDEX":                "orange",
+		"FUNCTION":             "palegreen",
+		"PROCEDURE":            "palegreen",
+		"TRIGGER":              "palegreen",
+		"EVENT TRIGGER":        "palegreen",
+		"VIEW":                 "plum",
+		"MATERIALIZED VIEW":    "plum",
+		"SEQUENCE":             "khaki",
+		"TYPE":                 "pink",
+		"DOMAIN":               "pink",
+		"COLLATION":            "pink",
+		"POLICY":               "salmon",
+		"GRANT":                "salmon",
+		"REVOKE":               "salmon",
+		"SCHEMA":               "lightgray",
+		"EXTENSION":            "lightgray",
+		"TEXT SEARCH":          "lightgray",
+		"FOREIGN DATA WRAPPER": "lightgray",
+		"SERVER":               "lightgray",
+		"PUBLICATION":          "lightgray",
+		"STATISTICS":           "lightgray",
+		"OPERATOR":             "lightgray",
+		"OPERATOR CLASS":       "lightgray",
+	}
+
+	whitespaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// WriteDependencyGraph writes the plan's dependency graph in DOT format, e.g., to be rendered with Graphviz. Each node
+// is a statement, labeled with a summary of its DDL and its hazards, and colored by the type of object it operates on.
+// Each edge points from a statement to a statement that must run after it. This is only a diagnostic tool and does not
+// affect how the plan is executed.
+func (p Plan) WriteDependencyGraph(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph plan {"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, `node [fontname="Helvetica,Arial,sans-serif" shape=box style=filled]`); err != nil {
+		return err
+	}
+	for i, stmt := range p.Statements {
+		if _, err := fmt.Fprintf(w, "s%d [%s]\n", i, buildDependencyGraphNodeAttrs(i, stmt)); err != nil {
+			return fmt.Errorf("writing node for statement %d: %w", i, err)
+		}
+	}
+	for _, dep := range p.getDependencies() {
+		if dep.Statement < 0 || dep.Statement >= len(p.Statements) || dep.DependsOn < 0 || dep.DependsOn >= len(p.Statements) {
+			return fmt.Errorf("dependency %+v references a statement that does not exist", dep)
+		}
+		if _, err := fmt.Fprintf(w, "s%d -> s%d\n", dep.DependsOn, dep.Statement); err != nil {
+			return fmt.Errorf("writing edge %+v: %w", dep, err)
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+func buildDependencyGraphNodeAttrs(idx int, stmt Statement) string {
+	labelLines := []string{fmt.Sprintf("%d: %s", idx, summarizeDDL(stmt.DDL))}
+	for _, hazard := range stmt.Hazards {
+		labelLines = append(labelLines, hazard.Type)
+	}
+	attrs := []string{
+		fmt.Sprintf("label=%q", strings.Join(labelLines, "\n")),
+		fmt.Sprintf("fillcolor=%q", getDependencyGraphNodeColor(stmt.DDL)),
+	}
+	if len(stmt.Hazards) > 0 {
+		attrs = append(attrs, `color="red"`)
+	}
+	if stmt.IsAdvisory {
+		attrs = append(attrs, `style="filled,dashed"`)
+	}
+	return strings.Join(attrs, " ")
+}
+
+// summarizeDDL collapses the whitespace of the DDL and truncates it
+func summarizeDDL(ddl string) string {
+	summary := strings.TrimSpace(whitespaceRegex.ReplaceAllString(ddl, " "))
+	if runes := []rune(summary); len(runes) > maxDependencyGraphLabelLen {
+		summary = string(runes[:maxDependencyGraphLabelLen-3]) + "..."
+	}
+	return summary
+}
+
+func getDependencyGraphNodeColor(ddl string) string {
+	objType := strings.ToUpper(strings.TrimSpace(ddl))
+	if matches := statementObjTypeRegex.FindStringSubmatch(ddl); len(matches) > 1 {
+		objType = strings.ToUpper(whitespaceRegex.ReplaceAllString(matches[1], " "))
+	} else if fields := strings.Fields(objType); len(fields) > 0 {
+		// Statements like ANALYZE and GRANT are identified by their first keyword
+		objType = fields[0]
+	}
+	if color, ok := dependencyGraphNodeColorsByObjType[objType]; ok {
+		return color
+	}
+	return defaultDependencyGraphNodeColor
+}
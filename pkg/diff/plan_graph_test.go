@@ -0,0 +1,200 @@
+package diff
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	dotNodeRegex = regexp.MustCompile(`^s(\d+) \[label="((?:[^"\\]|\\.)*)" fillcolor="(\w+)"(?: color="red")?(?: style="filled,dashed")?]$`)
+	dotEdgeRegex = regexp.MustCompile(`^s(\d+) -> s(\d+)$`)
+)
+
+type parsedDOTGraph struct {
+	labelsByNode map[int]string
+	colorsByNode map[int]string
+	edges        map[int][]int
+}
+
+// parseDOTGraph parses the subset of the DOT language written by WriteDependencyGraph, failing the test if the
+// graph is malformed
+func parseDOTGraph(t *testing.T, dot string) parsedDOTGraph {
+	lines := strings.Split(strings.TrimSuffix(dot, "\n"), "\n")
+	require.GreaterOrEqual(t, len(lines), 3)
+	require.Equal(t, "digraph plan {", lines[0])
+	require.Equal(t, `node [fontname="Helvetica,Arial,sans-serif" shape=box style=filled]`, lines[1])
+	require.Equal(t, "}", lines[len(lines)-1])
+
+	graph := parsedDOTGraph{
+		labelsByNode: make(map[int]string),
+		colorsByNode: make(map[int]string),
+		edges:        make(map[int][]int),
+	}
+	for _, line := range lines[2 : len(lines)-1] {
+		if matches := dotNodeRegex.FindStringSubmatch(line); matches != nil {
+			node, err := strconv.Atoi(matches[1])
+			require.NoError(t, err)
+			label, err := strconv.Unquote(`"` + matches[2] + `"`)
+			require.NoError(t, err)
+			graph.labelsByNode[node] = label
+			graph.colorsByNode[node] = matches[3]
+		} else if matches := dotEdgeRegex.FindStringSubmatch(line); matches != nil {
+			source, err := strconv.Atoi(matches[1])
+			require.NoError(t, err)
+			target, err := strconv.Atoi(matches[2])
+			require.NoError(t, err)
+			require.Contains(t, graph.labelsByNode, source, "edge references undeclared node: %s", line)
+			require.Contains(t, graph.labelsByNode, target, "edge references undeclared node: %s", line)
+			graph.edges[source] = append(graph.edges[source], target)
+		} else {
+			require.Failf(t, "invalid DOT line", "line: %q", line)
+		}
+	}
+	return graph
+}
+
+func (g parsedDOTGraph) findNode(t *testing.T, labelPrefix string) int {
+	for node, label := range g.labelsByNode {
+		if strings.HasPrefix(strings.SplitN(label, ": ", 2)[1], labelPrefix) {
+			return node
+		}
+	}
+	require.Failf(t, "node not found", "no node with label prefix %q", labelPrefix)
+	return -1
+}
+
+func (g parsedDOTGraph) isReachable(source, target int) bool {
+	visited := make(map[int]bool)
+	toVisit := []int{source}
+	for len(toVisit) > 0 {
+		node := toVisit[0]
+		toVisit = toVisit[1:]
+		if node == target {
+			return true
+		}
+		if visited[node] {
+			continue
+		}
+		visited[node] = true
+		toVisit = append(toVisit, g.edges[node]...)
+	}
+	return false
+}
+
+func TestPlan_WriteDependencyGraph(t *testing.T) {
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: "CREATE TABLE \"public\".\"foo\" (\n\t\"id\" integer\n)", Timeout: 3 * time.Second},
+			{
+				DDL:     "CREATE UNIQUE INDEX CONCURRENTLY foo_id_idx ON public.foo USING btree (id)",
+				Timeout: 20 * time.Minute,
+				Hazards: []MigrationHazard{{Type: MigrationHazardTypeIndexBuild, Message: "some message"}},
+			},
+			{DDL: "CREATE OR REPLACE FUNCTION public.add(a integer, b integer) RETURNS integer LANGUAGE sql AS $$ SELECT a + b $$"},
+			{DDL: "ANALYZE \"public\".\"foo\"", IsAdvisory: true},
+		},
+	}
+
+	buf := bytes.Buffer{}
+	require.NoError(t, plan.WriteDependencyGraph(&buf))
+	assert.Equal(t, `digraph plan {
+node [fontname="Helvetica,Arial,sans-serif" shape=box style=filled]
+s0 [label="0: CREATE TABLE \"public\".\"foo\" ( \"id\" integer )" fillcolor="lightblue"]
+s1 [label="1: CREATE UNIQUE INDEX CONCURRENTLY foo_id_idx ON public.foo...\nINDEX_BUILD" fillcolor="orange" color="red"]
+s2 [label="2: CREATE OR REPLACE FUNCTION public.add(a integer, b intege..." fillcolor="palegreen"]
+s3 [label="3: ANALYZE \"public\".\"foo\"" fillcolor="lightblue" style="filled,dashed"]
+s0 -> s1
+s1 -> s2
+s2 -> s3
+}
+`, buf.String())
+
+	plan.Dependencies = []StatementDependency{{Statement: 4, DependsOn: 0}}
+	assert.ErrorContains(t, plan.WriteDependencyGraph(&bytes.Buffer{}), "does not exist")
+}
+
+func TestPlan_WriteDependencyGraph_GeneratedPlan(t *testing.T) {
+	fooTableName := schema.SchemaQualifiedName{SchemaName: "app", EscapedName: "\"foo\""}
+	barTableName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"bar\""}
+	bazTableName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"baz\""}
+	buildTable := func(name schema.SchemaQualifiedName) schema.Table {
+		return schema.Table{
+			SchemaQualifiedName: name,
+			Columns:             []schema.Column{{Name: "id", Type: "integer", IsNullable: true}},
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		}
+	}
+	oldSchema := schema.Schema{
+		Tables: []schema.Table{buildTable(barTableName), buildTable(bazTableName)},
+		Indexes: []schema.Index{{
+			Name:            "bar_id_idx",
+			OwningTable:     barTableName,
+			Columns:         []string{"id"},
+			GetIndexDefStmt: "CREATE INDEX bar_id_idx ON public.bar USING btree (id)",
+		}},
+	}
+	newSchema := schema.Schema{
+		NamedSchemas: []schema.NamedSchema{{Name: "app"}},
+		Tables:       []schema.Table{buildTable(barTableName), buildTable(fooTableName)},
+		Indexes: []schema.Index{{
+			Name:            "foo_id_idx",
+			OwningTable:     fooTableName,
+			Columns:         []string{"id"},
+			GetIndexDefStmt: "CREATE INDEX foo_id_idx ON app.foo USING btree (id)",
+		}},
+		Views: []schema.View{{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "app", EscapedName: "\"foo_view\""},
+			Definition:          " SELECT foo.id FROM app.foo",
+			DependsOnTables:     []schema.SchemaQualifiedName{fooTableName},
+		}},
+	}
+
+	stmts, deps, err := generateMigrationStatementsWithDependencies(oldSchema, newSchema, &planOptions{})
+	require.NoError(t, err)
+	plan := Plan{Statements: stmts, Dependencies: sortStatementDependencies(deps)}
+
+	buf := bytes.Buffer{}
+	require.NoError(t, plan.WriteDependencyGraph(&buf))
+	// The output should be deterministic
+	secondBuf := bytes.Buffer{}
+	require.NoError(t, plan.WriteDependencyGraph(&secondBuf))
+	assert.Equal(t, buf.String(), secondBuf.String())
+
+	graph := parseDOTGraph(t, buf.String())
+	require.Len(t, graph.labelsByNode, len(stmts))
+	for source, targets := range graph.edges {
+		for _, target := range targets {
+			assert.Less(t, source, target, "statements must only depend on statements that run before them")
+		}
+	}
+
+	createSchemaNode := graph.findNode(t, "CREATE SCHEMA")
+	createTableNode := graph.findNode(t, "CREATE TABLE \"app\".\"foo\"")
+	createIndexNode := graph.findNode(t, "CREATE INDEX CONCURRENTLY foo_id_idx")
+	createViewNode := graph.findNode(t, "CREATE VIEW")
+	dropIndexNode := graph.findNode(t, "DROP INDEX CONCURRENTLY")
+	dropTableNode := graph.findNode(t, "DROP TABLE \"public\".\"baz\"")
+	assert.Equal(t, "lightgray", graph.colorsByNode[createSchemaNode])
+	assert.Equal(t, "lightblue", graph.colorsByNode[createTableNode])
+	assert.Equal(t, "orange", graph.colorsByNode[dropIndexNode])
+	assert.Equal(t, "orange", graph.colorsByNode[createIndexNode])
+	assert.Equal(t, "plum", graph.colorsByNode[createViewNode])
+	assert.Contains(t, graph.labelsByNode[dropTableNode], MigrationHazardTypeDeletesData)
+
+	assert.True(t, graph.isReachable(createSchemaNode, createTableNode))
+	assert.True(t, graph.isReachable(createTableNode, createIndexNode))
+	assert.True(t, graph.isReachable(createTableNode, createViewNode))
+	// Unrelated statements should not depend on each other
+	assert.False(t, graph.isReachable(createIndexNode, createViewNode))
+	assert.False(t, graph.isReachable(createViewNode, createIndexNode))
+	assert.False(t, graph.isReachable(dropIndexNode, dropTableNode))
+	assert.False(t, graph.isReachable(dropTableNode, dropIndexNode))
+}
@@ -325,6 +325,38 @@ func TestPlan_InsertStatement(t *testing.T) {
 				CurrentSchemaHash: "some-hash",
 			},
 		},
+		{
+			name: "insert into plan with dependencies",
+			plan: diff.Plan{
+				Statements: []diff.Statement{
+					{DDL: "statement 1", Timeout: time.Second},
+					{DDL: "statement 2", Timeout: 2 * time.Second},
+					{DDL: "statement 3", Timeout: 3 * time.Second},
+				},
+				CurrentSchemaHash: "some-hash",
+				Dependencies: []diff.StatementDependency{
+					{Statement: 1, DependsOn: 0},
+					{Statement: 2, DependsOn: 0},
+				},
+			},
+			index: 1,
+
+			expectedPlan: diff.Plan{
+				Statements: []diff.Statement{
+					{DDL: "statement 1", Timeout: time.Second},
+					statementToInsert,
+					{DDL: "statement 2", Timeout: 2 * time.Second},
+					{DDL: "statement 3", Timeout: 3 * time.Second},
+				},
+				CurrentSchemaHash: "some-hash",
+				Dependencies: []diff.StatementDependency{
+					{Statement: 1, DependsOn: 0},
+					{Statement: 2, DependsOn: 0},
+					{Statement: 2, DependsOn: 1},
+					{Statement: 3, DependsOn: 0},
+				},
+			},
+		},
 		{
 			name: "errors on negative index",
 			plan: diff.Plan{
@@ -357,3 +389,150 @@ func TestPlan_InsertStatement(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanToJSON(t *testing.T) {
+	plan := diff.Plan{
+		Statements: []diff.Statement{
+			{
+				DDL:         "ALTER TABLE foo DROP COLUMN bar",
+				Timeout:     3 * time.Second,
+				LockTimeout: time.Second,
+				Hazards: []diff.MigrationHazard{
+					{Type: diff.MigrationHazardTypeDeletesData, Message: "Deletes all values in the column"},
+				},
+			},
+			{
+				DDL:                   "CREATE INDEX CONCURRENTLY foo_idx ON foo(id)",
+				Timeout:               20 * time.Minute,
+				LockTimeout:           time.Second,
+				RequiresNoTransaction: true,
+				Hazards: []diff.MigrationHazard{
+					{Type: diff.MigrationHazardTypeIndexBuild, Message: "Might affect database performance"},
+					{Type: diff.MigrationHazardTypeDeletesData, Message: "Some other hazard"},
+				},
+			},
+			{
+				DDL:        "ANALYZE foo",
+				Timeout:    time.Minute,
+				IsAdvisory: true,
+			},
+		},
+		CurrentSchemaHash: "some-hash",
+		Dependencies: []diff.StatementDependency{
+			{Statement: 2, DependsOn: 1},
+			{Statement: 2, DependsOn: 0},
+		},
+	}
+
+	data, err := diff.PlanToJSON(plan)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"statements": [
+			{
+				"ddl": "ALTER TABLE foo DROP COLUMN bar",
+				"timeout_ms": 3000,
+				"lock_timeout_ms": 1000,
+				"hazards": [{"type": "DELETES_DATA", "message": "Deletes all values in the column"}],
+				"requires_no_transaction": false,
+				"is_advisory": false
+			},
+			{
+				"ddl": "CREATE INDEX CONCURRENTLY foo_idx ON foo(id)",
+				"timeout_ms": 1200000,
+				"lock_timeout_ms": 1000,
+				"hazards": [
+					{"type": "INDEX_BUILD", "message": "Might affect database performance"},
+					{"type": "DELETES_DATA", "message": "Some other hazard"}
+				],
+				"requires_no_transaction": true,
+				"is_advisory": false
+			},
+			{
+				"ddl": "ANALYZE foo",
+				"timeout_ms": 60000,
+				"lock_timeout_ms": 0,
+				"hazards": null,
+				"requires_no_transaction": false,
+				"is_advisory": true
+			}
+		],
+		"current_schema_hash": "some-hash",
+		"dependencies": [
+			{"statement": 2, "depends_on": 0},
+			{"statement": 2, "depends_on": 1}
+		],
+		"summary": {
+			"total_statements": 3,
+			"hazard_counts": {"DELETES_DATA": 2, "INDEX_BUILD": 1}
+		}
+	}`, string(data))
+
+	secondData, err := diff.PlanToJSON(plan)
+	require.NoError(t, err)
+	assert.Equal(t, data, secondData, "expected serialization to be deterministic")
+
+	deserializedPlan, err := diff.MigrationPlanFromJSON(data)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, plan.Dependencies, deserializedPlan.Dependencies)
+	deserializedPlan.Dependencies = plan.Dependencies
+	assert.Equal(t, plan, deserializedPlan)
+
+	// Without dependencies, each statement depends on the statement before it
+	plan.Dependencies = nil
+	data, err = diff.PlanToJSON(plan)
+	require.NoError(t, err)
+	deserializedPlan, err = diff.MigrationPlanFromJSON(data)
+	require.NoError(t, err)
+	assert.Equal(t, []diff.StatementDependency{
+		{Statement: 1, DependsOn: 0},
+		{Statement: 2, DependsOn: 1},
+	}, deserializedPlan.Dependencies)
+}
+
+func TestMigrationPlanFromJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		data                string
+		expectedPlan        diff.Plan
+		expectedErrContains string
+	}{
+		{
+			name:         "empty plan",
+			data:         `{"statements": [], "current_schema_hash": "some-hash", "dependencies": [], "summary": {"total_statements": 0, "hazard_counts": {}}}`,
+			expectedPlan: diff.Plan{CurrentSchemaHash: "some-hash"},
+		},
+		{
+			name: "dependencies and summary are optional",
+			data: `{"statements": [{"ddl": "statement 1", "timeout_ms": 1500}], "current_schema_hash": "some-hash"}`,
+			expectedPlan: diff.Plan{
+				Statements:        []diff.Statement{{DDL: "statement 1", Timeout: 1500 * time.Millisecond}},
+				CurrentSchemaHash: "some-hash",
+			},
+		},
+		{
+			name:                "errors on dependency on later statement",
+			data:                `{"statements": [{"ddl": "statement 1"}, {"ddl": "statement 2"}], "dependencies": [{"statement": 0, "depends_on": 1}]}`,
+			expectedErrContains: "not executed before it",
+		},
+		{
+			name:                "errors on dependency on non-existent statement",
+			data:                `{"statements": [{"ddl": "statement 1"}], "dependencies": [{"statement": 1, "depends_on": 0}]}`,
+			expectedErrContains: "does not exist",
+		},
+		{
+			name:                "errors on invalid json",
+			data:                `{"statements": [`,
+			expectedErrContains: "unmarshalling plan",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := diff.MigrationPlanFromJSON([]byte(tc.data))
+			if len(tc.expectedErrContains) > 0 {
+				assert.ErrorContains(t, err, tc.expectedErrContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPlan, plan)
+		})
+	}
+}
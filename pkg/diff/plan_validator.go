@@ -0,0 +1,178 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	// leadingConcurrentlyRegex matches the CONCURRENTLY keyword of statements like `CREATE INDEX CONCURRENTLY`,
+	// `DROP INDEX CONCURRENTLY`, `REINDEX INDEX CONCURRENTLY`, and `REFRESH MATERIALIZED VIEW CONCURRENTLY`
+	leadingConcurrentlyRegex = regexp.MustCompile(`(?is)^(\s*(?:CREATE\s+(?:UNIQUE\s+)?INDEX|DROP\s+INDEX|REINDEX\s+\w+|REFRESH\s+MATERIALIZED\s+VIEW)\s+)CONCURRENTLY\s+`)
+	// trailingConcurrentlyRegex matches the CONCURRENTLY keyword of `ALTER TABLE ... DETACH PARTITION ... CONCURRENTLY`
+	trailingConcurrentlyRegex = regexp.MustCompile(`(?is)\s+CONCURRENTLY\s*$`)
+)
+
+// ValidationError is an error from running a statement of the plan against the database
+type ValidationError struct {
+	// StatementIndex is the position of the statement in the plan
+	StatementIndex int
+	Statement      Statement
+	// Code is the Postgres error code (SQLSTATE), e.g., "42601" for a syntax error. It is empty if the error did not
+	// come from Postgres, e.g., the connection was closed
+	Code    string
+	Message string
+	Detail  string
+	Hint    string
+	// Err is the underlying error
+	Err error
+}
+
+func newValidationError(stmt Statement, err error) ValidationError {
+	validationErr := ValidationError{
+		Statement: stmt,
+		Message:   err.Error(),
+		Err:       err,
+	}
+	if pgErr, ok := getPgError(err); ok {
+		validationErr.Code = pgErr.Code
+		validationErr.Message = pgErr.Message
+		validationErr.Detail = pgErr.Detail
+		validationErr.Hint = pgErr.Hint
+	}
+	return validationErr
+}
+
+func (v ValidationError) Error() string {
+	if len(v.Code) > 0 {
+		return fmt.Sprintf("statement %d: %s: %s (SQLSTATE %s)", v.StatementIndex, v.Statement.ToSQL(), v.Message, v.Code)
+	}
+	return fmt.Sprintf("statement %d: %s: %s", v.StatementIndex, v.Statement.ToSQL(), v.Message)
+}
+
+func (v ValidationError) Unwrap() error {
+	return v.Err
+}
+
+// IsSyntaxError returns true if the statement is not syntactically valid
+func (v ValidationError) IsSyntaxError() bool {
+	return v.Code == pgErrCodeSyntaxError
+}
+
+// IsPermissionError returns true if the user does not have the privileges to run the statement
+func (v ValidationError) IsPermissionError() bool {
+	return v.Code == pgErrCodeInsufficientPrivilege
+}
+
+// IsLockTimeout returns true if the statement could not acquire its locks within its lock timeout
+func (v ValidationError) IsLockTimeout() bool {
+	return v.Code == pgErrCodeLockNotAvailable
+}
+
+// IsStatementTimeout returns true if the statement did not complete within its statement timeout
+func (v ValidationError) IsStatementTimeout() bool {
+	return v.Code == pgErrCodeQueryCanceled
+}
+
+// Validator validates a plan by running its statements against a database in a transaction that is always rolled back.
+// This validates the statements are syntactically and semantically valid for the database without making permanent
+// changes.
+//
+// The statements acquire the same locks they would acquire if the plan were applied, bounded by each statement's lock
+// timeout and statement timeout. The locks are held until the transaction is rolled back. Statements that cannot be run
+// in a transaction, e.g., `CREATE INDEX CONCURRENTLY`, are run without `CONCURRENTLY`, i.e., building the index locks
+// out writes to the table. Be mindful of this when validating against a database that is serving traffic.
+type Validator struct {
+	db *sql.DB
+}
+
+func NewValidator(db *sql.DB) *Validator {
+	return &Validator{db: db}
+}
+
+// Validate runs each statement of the plan and returns an error for each statement that fails. Once a statement fails,
+// the statements that depend on it will likely also fail. Advisory statements are skipped, since they are not executed
+// when the plan is applied.
+//
+// Each statement is run in its own savepoint, which is immediately rolled back if the statement fails. Statements that
+// require no transaction are run without CONCURRENTLY, since they cannot otherwise be run in the transaction. They are
+// still run in the same transaction as the other statements, such that they can see the effects of the statements
+// before them, e.g., an index built on a new column, and vice versa.
+//
+// The returned error is only non-nil if the validation itself could not be run, e.g., a transaction could not be
+// started.
+func (v *Validator) Validate(ctx context.Context, plan Plan) ([]ValidationError, error) {
+	tx, err := v.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var validationErrs []ValidationError
+	for i, stmt := range plan.Statements {
+		if stmt.IsAdvisory {
+			continue
+		}
+		if err := execInSavepoint(ctx, tx, stmt); err != nil {
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				return nil, fmt.Errorf("validating statement %d: %w", i, err)
+			}
+			validationErr.StatementIndex = i
+			validationErrs = append(validationErrs, validationErr)
+		}
+	}
+
+	if err := tx.Rollback(); err != nil {
+		return nil, fmt.Errorf("rolling back transaction: %w", err)
+	}
+	return validationErrs, nil
+}
+
+// execInSavepoint runs the statement with its timeouts in a savepoint, such that the transaction can continue if the
+// statement fails. If the statement fails, a ValidationError is returned.
+func execInSavepoint(ctx context.Context, tx *sql.Tx, stmt Statement) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT validate_statement"); err != nil {
+		return fmt.Errorf("creating savepoint: %w", err)
+	}
+	if err := setLocalTimeout(ctx, tx, "statement_timeout", stmt.Timeout); err != nil {
+		return err
+	}
+	if err := setLocalTimeout(ctx, tx, "lock_timeout", stmt.LockTimeout); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, removeConcurrently(stmt).ToSQL()); err != nil {
+		if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT validate_statement"); rollbackErr != nil {
+			return fmt.Errorf("rolling back to savepoint: %w", rollbackErr)
+		}
+		return newValidationError(stmt, err)
+	}
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT validate_statement"); err != nil {
+		return fmt.Errorf("releasing savepoint: %w", err)
+	}
+	return nil
+}
+
+// setLocalTimeout sets the timeout for the rest of the transaction. A timeout of 0 disables the timeout, i.e., the
+// timeout of a previous statement does not carry over
+func setLocalTimeout(ctx context.Context, tx *sql.Tx, setting string, timeout time.Duration) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL %s = %d", setting, timeout.Milliseconds())); err != nil {
+		return fmt.Errorf("setting %s: %w", setting, err)
+	}
+	return nil
+}
+
+// removeConcurrently removes the CONCURRENTLY keyword from statements that require no transaction, since they cannot be
+// run in a transaction block otherwise
+func removeConcurrently(stmt Statement) Statement {
+	if !stmt.RequiresNoTransaction {
+		return stmt
+	}
+	stmt.DDL = leadingConcurrentlyRegex.ReplaceAllString(stmt.DDL, "$1")
+	stmt.DDL = trailingConcurrentlyRegex.ReplaceAllString(stmt.DDL, "")
+	return stmt
+}
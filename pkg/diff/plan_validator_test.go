@@ -0,0 +1,168 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stripe/pg-schema-diff/internal/pgengine"
+)
+
+func TestRemoveConcurrently(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		stmt        Statement
+		expectedDDL string
+	}{
+		{
+			name:        "create index",
+			stmt:        Statement{DDL: "CREATE UNIQUE INDEX CONCURRENTLY foo_idx ON public.foo USING btree (id)", RequiresNoTransaction: true},
+			expectedDDL: "CREATE UNIQUE INDEX foo_idx ON public.foo USING btree (id)",
+		},
+		{
+			name:        "drop index",
+			stmt:        Statement{DDL: "DROP INDEX CONCURRENTLY \"public\".\"foo_idx\"", RequiresNoTransaction: true},
+			expectedDDL: "DROP INDEX \"public\".\"foo_idx\"",
+		},
+		{
+			name:        "reindex index",
+			stmt:        Statement{DDL: "REINDEX INDEX CONCURRENTLY \"public\".\"foo_idx\"", RequiresNoTransaction: true},
+			expectedDDL: "REINDEX INDEX \"public\".\"foo_idx\"",
+		},
+		{
+			name:        "detach partition",
+			stmt:        Statement{DDL: "ALTER TABLE \"public\".\"foo\" DETACH PARTITION \"public\".\"foo_1\" CONCURRENTLY", RequiresNoTransaction: true},
+			expectedDDL: "ALTER TABLE \"public\".\"foo\" DETACH PARTITION \"public\".\"foo_1\"",
+		},
+		{
+			name:        "statement that can run in a transaction is unchanged",
+			stmt:        Statement{DDL: "REFRESH MATERIALIZED VIEW CONCURRENTLY \"public\".\"foo_mv\""},
+			expectedDDL: "REFRESH MATERIALIZED VIEW CONCURRENTLY \"public\".\"foo_mv\"",
+		},
+		{
+			name:        "identifier containing concurrently is unchanged",
+			stmt:        Statement{DDL: "CREATE INDEX CONCURRENTLY concurrently ON public.foo USING btree (id)", RequiresNoTransaction: true},
+			expectedDDL: "CREATE INDEX concurrently ON public.foo USING btree (id)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedDDL, removeConcurrently(tc.stmt).DDL)
+		})
+	}
+}
+
+func TestNewValidationError(t *testing.T) {
+	stmt := Statement{DDL: "CREATE TABL foo()"}
+	for _, tc := range []struct {
+		name          string
+		err           error
+		expectedCode  string
+		expectedError string
+	}{
+		{
+			name:          "pgx error",
+			err:           fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "42601", Message: "syntax error at or near \"TABL\""}),
+			expectedCode:  "42601",
+			expectedError: "statement 0: CREATE TABL foo();: syntax error at or near \"TABL\" (SQLSTATE 42601)",
+		},
+		{
+			name:          "pq error",
+			err:           &pq.Error{Code: "42601", Message: "syntax error at or near \"TABL\""},
+			expectedCode:  "42601",
+			expectedError: "statement 0: CREATE TABL foo();: syntax error at or near \"TABL\" (SQLSTATE 42601)",
+		},
+		{
+			name:          "non-postgres error",
+			err:           sql.ErrConnDone,
+			expectedError: "statement 0: CREATE TABL foo();: sql: connection is already closed",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			validationErr := newValidationError(stmt, tc.err)
+			assert.Equal(t, tc.expectedCode, validationErr.Code)
+			assert.Equal(t, len(tc.expectedCode) > 0, validationErr.IsSyntaxError())
+			assert.False(t, validationErr.IsPermissionError())
+			assert.EqualError(t, validationErr, tc.expectedError)
+			assert.ErrorIs(t, validationErr, tc.err)
+		})
+	}
+}
+
+func (suite *planGeneratorTestSuite) TestValidator_Validate() {
+	suite.mustApplyDDLToTestDb([]string{`CREATE TABLE foobar(id INT PRIMARY KEY);`})
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: "ALTER TABLE foobar ADD COLUMN val TEXT", Timeout: 3 * time.Second, LockTimeout: time.Second},
+			// Relies on the column added by the previous statement
+			{DDL: "CREATE INDEX CONCURRENTLY foobar_val_idx ON foobar(val)", Timeout: time.Minute, LockTimeout: time.Second, RequiresNoTransaction: true},
+			{DDL: "ALTER TABLE foobar ADD CONSTRAINT foobar_val_key UNIQUE USING INDEX foobar_val_idx", Timeout: 3 * time.Second},
+			{DDL: "CREATE TABL baz()", Timeout: 3 * time.Second},
+			{DDL: "ALTER TABLE missing_table ADD COLUMN val TEXT", Timeout: 3 * time.Second},
+			{DDL: "ANALYZE missing_table", IsAdvisory: true},
+			{DDL: "CREATE TABLE baz(val TEXT)", Timeout: 3 * time.Second},
+		},
+	}
+	validationErrs, err := NewValidator(connPool).Validate(context.Background(), plan)
+	suite.Require().NoError(err)
+	suite.Require().Len(validationErrs, 2)
+	suite.Equal(3, validationErrs[0].StatementIndex)
+	suite.True(validationErrs[0].IsSyntaxError())
+	suite.Equal(4, validationErrs[1].StatementIndex)
+	suite.Equal("42P01", validationErrs[1].Code)
+
+	// The validation should not make any permanent changes
+	_, err = connPool.ExecContext(context.Background(), "SELECT val FROM foobar")
+	suite.Error(err)
+	_, err = connPool.ExecContext(context.Background(), "SELECT * FROM baz")
+	suite.Error(err)
+}
+
+func (suite *planGeneratorTestSuite) TestValidator_ValidateLockTimeout() {
+	suite.mustApplyDDLToTestDb([]string{`CREATE TABLE foobar(id INT PRIMARY KEY);`})
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	lockTx, err := connPool.BeginTx(context.Background(), nil)
+	suite.Require().NoError(err)
+	defer lockTx.Rollback()
+	_, err = lockTx.ExecContext(context.Background(), "LOCK TABLE foobar IN ACCESS EXCLUSIVE MODE")
+	suite.Require().NoError(err)
+
+	validationErrs, err := NewValidator(connPool).Validate(context.Background(), Plan{
+		Statements: []Statement{
+			{DDL: "ALTER TABLE foobar ADD COLUMN val TEXT", Timeout: 3 * time.Second, LockTimeout: 100 * time.Millisecond},
+		},
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(validationErrs, 1)
+	suite.True(validationErrs[0].IsLockTimeout())
+}
+
+func (suite *planGeneratorTestSuite) TestValidator_ValidatePermissionError() {
+	suite.mustApplyDDLToTestDb([]string{
+		`CREATE TABLE foobar(id INT PRIMARY KEY);`,
+		`CREATE ROLE plan_validator_user LOGIN;`,
+	})
+	defer suite.mustApplyDDLToTestDb([]string{`DROP ROLE plan_validator_user;`})
+
+	connPool, err := sql.Open("pgx", suite.db.GetConnOpts().With(pgengine.ConnectionOptionUser, "plan_validator_user").ToDSN())
+	suite.Require().NoError(err)
+	defer connPool.Close()
+
+	validationErrs, err := NewValidator(connPool).Validate(context.Background(), Plan{
+		Statements: []Statement{
+			{DDL: "ALTER TABLE foobar ADD COLUMN val TEXT", Timeout: 3 * time.Second},
+		},
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(validationErrs, 1)
+	suite.True(validationErrs[0].IsPermissionError())
+}
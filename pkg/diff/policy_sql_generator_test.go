@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestPolicySQLVertexGenerator_Alter(t *testing.T) {
+	table := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`},
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	policy := schema.Policy{
+		EscapedName:     `"foobar_policy"`,
+		IsPermissive:    true,
+		AppliesTo:       []string{"PUBLIC"},
+		Cmd:             schema.AllPolicyCmd,
+		UsingExpression: "(id > 0)",
+		CheckExpression: "(id > 0)",
+		Columns:         []string{"id"},
+	}
+
+	for _, tc := range []struct {
+		name                     string
+		alter                    func(p schema.Policy) schema.Policy
+		expectedDDL              []string
+		expectRequiresRecreation bool
+	}{
+		{
+			name:        "No diff",
+			alter:       func(p schema.Policy) schema.Policy { return p },
+			expectedDDL: nil,
+		},
+		{
+			name: "Using and check expressions are altered in one statement",
+			alter: func(p schema.Policy) schema.Policy {
+				p.UsingExpression = "(id > 1)"
+				p.CheckExpression = "(id > 2)"
+				return p
+			},
+			expectedDDL: []string{
+				"ALTER POLICY \"foobar_policy\" ON \"public\".\"foobar\"\n\tUSING ((id > 1))\n\tWITH CHECK ((id > 2))",
+			},
+		},
+		{
+			name: "Roles are altered",
+			alter: func(p schema.Policy) schema.Policy {
+				p.AppliesTo = []string{"role_1", "role_2"}
+				return p
+			},
+			expectedDDL: []string{
+				"ALTER POLICY \"foobar_policy\" ON \"public\".\"foobar\"\n\tTO role_1, role_2",
+			},
+		},
+		{
+			name: "Command change requires re-creation",
+			alter: func(p schema.Policy) schema.Policy {
+				p.Cmd = schema.SelectPolicyCmd
+				return p
+			},
+			expectRequiresRecreation: true,
+		},
+		{
+			name: "Permissive to restrictive requires re-creation",
+			alter: func(p schema.Policy) schema.Policy {
+				p.IsPermissive = false
+				return p
+			},
+			expectRequiresRecreation: true,
+		},
+		{
+			name: "Removing the check expression requires re-creation",
+			alter: func(p schema.Policy) schema.Policy {
+				p.CheckExpression = ""
+				return p
+			},
+			expectRequiresRecreation: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			psg, err := newPolicySQLVertexGenerator(&table, table)
+			require.NoError(t, err)
+			newPolicy := tc.alter(policy)
+
+			diffs, err := buildPolicyDiffs(psg, []schema.Policy{policy}, []schema.Policy{newPolicy})
+			require.NoError(t, err)
+			if tc.expectRequiresRecreation {
+				assert.Equal(t, []schema.Policy{policy}, diffs.deletes)
+				assert.Equal(t, []schema.Policy{newPolicy}, diffs.adds)
+				return
+			}
+			require.Len(t, diffs.alters, 1)
+
+			stmts, err := (&policySQLVertexGenerator{table: table}).Alter(diffs.alters[0])
+			require.NoError(t, err)
+			var ddl []string
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				assert.Equal(t, []MigrationHazard{migrationHazardPolicyAltered}, stmt.Hazards)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+		})
+	}
+}
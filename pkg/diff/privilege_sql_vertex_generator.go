@@ -0,0 +1,164 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	migrationHazardPrivilegeGranted = MigrationHazard{
+		Type:    MigrationHazardTypeAuthzUpdate,
+		Message: "Granting a privilege could allow unauthorized access to data.",
+	}
+	migrationHazardPrivilegeRevoked = MigrationHazard{
+		Type:    MigrationHazardTypeAuthzUpdate,
+		Message: "Revoking a privilege could cause queries to fail if not correctly configured.",
+	}
+	migrationHazardPrivilegeGranteeUntracked = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "Roles are not tracked. Granting the privilege will fail if the role does not exist in the " +
+			"target database.",
+	}
+)
+
+type privilegeSQLVertexGenerator struct{}
+
+func newPrivilegeSqlVertexGenerator() sqlVertexGenerator[schema.Privilege, privilegeDiff] {
+	return legacyToNewSqlVertexGenerator[schema.Privilege, privilegeDiff](&privilegeSQLVertexGenerator{})
+}
+
+func (p *privilegeSQLVertexGenerator) Add(privilege schema.Privilege) ([]Statement, error) {
+	ddl := fmt.Sprintf("GRANT %s ON %s TO %s", privilege.Type, buildPrivilegeObject(privilege), buildPrivilegeGrantee(privilege))
+	if privilege.IsGrantable {
+		ddl += " WITH GRANT OPTION"
+	}
+	hazards := []MigrationHazard{migrationHazardPrivilegeGranted}
+	if privilege.Grantee != schema.PrivilegeGranteePublic {
+		hazards = append(hazards, migrationHazardPrivilegeGranteeUntracked)
+	}
+	return []Statement{{
+		DDL:         ddl,
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     hazards,
+	}}, nil
+}
+
+func (p *privilegeSQLVertexGenerator) Delete(privilege schema.Privilege) ([]Statement, error) {
+	return []Statement{{
+		DDL:         fmt.Sprintf("REVOKE %s ON %s FROM %s", privilege.Type, buildPrivilegeObject(privilege), buildPrivilegeGrantee(privilege)),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardPrivilegeRevoked},
+	}}, nil
+}
+
+func (p *privilegeSQLVertexGenerator) Alter(diff privilegeDiff) ([]Statement, error) {
+	if diff.old.IsGrantable == diff.new.IsGrantable {
+		return nil, nil
+	}
+	if diff.new.IsGrantable {
+		return p.Add(diff.new)
+	}
+	return []Statement{{
+		DDL:         fmt.Sprintf("REVOKE GRANT OPTION FOR %s ON %s FROM %s", diff.new.Type, buildPrivilegeObject(diff.new), buildPrivilegeGrantee(diff.new)),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardPrivilegeRevoked},
+	}}, nil
+}
+
+func buildPrivilegeObject(privilege schema.Privilege) string {
+	name := privilege.Object.GetFQEscapedName()
+	if privilege.ObjectType == "FUNCTION" {
+		name = buildFunctionInputSignature(privilege.Object)
+	}
+	return fmt.Sprintf("%s %s", privilege.ObjectType, name)
+}
+
+func buildPrivilegeGrantee(privilege schema.Privilege) string {
+	if privilege.Grantee == schema.PrivilegeGranteePublic {
+		return privilege.Grantee
+	}
+	return schema.EscapeIdentifier(privilege.Grantee)
+}
+
+func (p *privilegeSQLVertexGenerator) GetSQLVertexId(privilege schema.Privilege, diffType diffType) sqlVertexId {
+	return buildSchemaObjVertexId("privilege", privilege.GetName(), diffType)
+}
+
+func (p *privilegeSQLVertexGenerator) GetAddAlterDependencies(newPrivilege, _ schema.Privilege) ([]dependency, error) {
+	deps := []dependency{
+		mustRun(p.GetSQLVertexId(newPrivilege, diffTypeAddAlter)).after(p.GetSQLVertexId(newPrivilege, diffTypeDelete)),
+	}
+	// The object must be created or altered before privileges are granted on it
+	for _, objectVertexId := range buildPrivilegeObjectVertexIds(newPrivilege) {
+		deps = append(deps, mustRun(p.GetSQLVertexId(newPrivilege, diffTypeAddAlter)).after(objectVertexId))
+	}
+	return deps, nil
+}
+
+func (p *privilegeSQLVertexGenerator) GetDeleteDependencies(privilege schema.Privilege) ([]dependency, error) {
+	var deps []dependency
+	// Privileges are only revoked from objects that still exist after they are migrated (see buildMigratedPrivileges),
+	// which includes the default privileges of newly created routines. Thus, privileges are revoked after the object is
+	// created or altered.
+	for _, objectVertexId := range buildPrivilegeObjectVertexIds(privilege) {
+		deps = append(deps, mustRun(p.GetSQLVertexId(privilege, diffTypeDelete)).after(objectVertexId))
+	}
+	return deps, nil
+}
+
+// buildPrivilegeObjectVertexIds returns the add/alter vertex ids of the object a privilege is granted on. The "TABLE"
+// object type is shared by tables, views, materialized views, and foreign tables, so it depends on all of their
+// vertices. Only one of them will have statements.
+func buildPrivilegeObjectVertexIds(privilege schema.Privilege) []sqlVertexId {
+	switch privilege.ObjectType {
+	case "SEQUENCE":
+		return []sqlVertexId{buildSequenceVertexId(privilege.Object, diffTypeAddAlter)}
+	case "FUNCTION":
+		return []sqlVertexId{buildFunctionVertexId(privilege.Object, diffTypeAddAlter)}
+	case "PROCEDURE":
+		return []sqlVertexId{buildProcedureVertexId(privilege.Object, diffTypeAddAlter)}
+	default:
+		return []sqlVertexId{
+			buildTableVertexId(privilege.Object, diffTypeAddAlter),
+			buildViewVertexId(privilege.Object, diffTypeAddAlter),
+			buildMaterializedViewVertexId(privilege.Object, diffTypeAddAlter),
+			buildForeignTableVertexId(privilege.Object, diffTypeAddAlter),
+		}
+	}
+}
+
+// buildMigratedPrivileges returns the privileges held once the objects themselves are migrated, i.e., before any
+// privileges are granted or revoked. Dropping an object drops its privileges, and creating a function or procedure
+// implicitly grants EXECUTE to PUBLIC. Diffing these privileges against the new privileges yields the privileges to
+// grant and revoke, which avoids revoking privileges on dropped objects and re-grants the privileges of re-created
+// objects.
+func buildMigratedPrivileges(oldPrivileges []schema.Privilege, droppedObjectNames map[string]bool, createdFunctions, createdProcedures []schema.SchemaQualifiedName) []schema.Privilege {
+	var privileges []schema.Privilege
+	for _, privilege := range oldPrivileges {
+		if droppedObjectNames[privilege.Object.GetName()] {
+			continue
+		}
+		privileges = append(privileges, privilege)
+	}
+	for _, routines := range []struct {
+		objectType string
+		names      []schema.SchemaQualifiedName
+	}{
+		{objectType: "FUNCTION", names: createdFunctions},
+		{objectType: "PROCEDURE", names: createdProcedures},
+	} {
+		for _, name := range routines.names {
+			privileges = append(privileges, schema.Privilege{
+				ObjectType: routines.objectType,
+				Object:     name,
+				Grantee:    schema.PrivilegeGranteePublic,
+				Type:       "EXECUTE",
+			})
+		}
+	}
+	return privileges
+}
@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestPrivilegeSQLVertexGenerator(t *testing.T) {
+	table := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}
+	function := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"add\"(a integer, OUT b integer)"}
+
+	for _, tc := range []struct {
+		name            string
+		generate        func(g *privilegeSQLVertexGenerator) ([]Statement, error)
+		expectedDDL     []string
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name: "grant to role",
+			generate: func(g *privilegeSQLVertexGenerator) ([]Statement, error) {
+				return g.Add(schema.Privilege{ObjectType: "TABLE", Object: table, Grantee: "reader", Type: "SELECT", IsGrantable: true})
+			},
+			expectedDDL:     []string{"GRANT SELECT ON TABLE \"public\".\"foo\" TO \"reader\" WITH GRANT OPTION"},
+			expectedHazards: []MigrationHazard{migrationHazardPrivilegeGranted, migrationHazardPrivilegeGranteeUntracked},
+		},
+		{
+			name: "grant to public",
+			generate: func(g *privilegeSQLVertexGenerator) ([]Statement, error) {
+				return g.Add(schema.Privilege{ObjectType: "FUNCTION", Object: function, Grantee: schema.PrivilegeGranteePublic, Type: "EXECUTE"})
+			},
+			expectedDDL:     []string{"GRANT EXECUTE ON FUNCTION \"public\".\"add\"(a integer) TO PUBLIC"},
+			expectedHazards: []MigrationHazard{migrationHazardPrivilegeGranted},
+		},
+		{
+			name: "revoke",
+			generate: func(g *privilegeSQLVertexGenerator) ([]Statement, error) {
+				return g.Delete(schema.Privilege{ObjectType: "TABLE", Object: table, Grantee: "writer", Type: "INSERT"})
+			},
+			expectedDDL:     []string{"REVOKE INSERT ON TABLE \"public\".\"foo\" FROM \"writer\""},
+			expectedHazards: []MigrationHazard{migrationHazardPrivilegeRevoked},
+		},
+		{
+			name: "revoke grant option",
+			generate: func(g *privilegeSQLVertexGenerator) ([]Statement, error) {
+				return g.Alter(privilegeDiff{oldAndNew: oldAndNew[schema.Privilege]{
+					old: schema.Privilege{ObjectType: "TABLE", Object: table, Grantee: "reader", Type: "SELECT", IsGrantable: true},
+					new: schema.Privilege{ObjectType: "TABLE", Object: table, Grantee: "reader", Type: "SELECT"},
+				}})
+			},
+			expectedDDL:     []string{"REVOKE GRANT OPTION FOR SELECT ON TABLE \"public\".\"foo\" FROM \"reader\""},
+			expectedHazards: []MigrationHazard{migrationHazardPrivilegeRevoked},
+		},
+		{
+			name: "no change",
+			generate: func(g *privilegeSQLVertexGenerator) ([]Statement, error) {
+				return g.Alter(privilegeDiff{oldAndNew: oldAndNew[schema.Privilege]{
+					old: schema.Privilege{ObjectType: "TABLE", Object: table, Grantee: "reader", Type: "SELECT"},
+					new: schema.Privilege{ObjectType: "TABLE", Object: table, Grantee: "reader", Type: "SELECT"},
+				}})
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := tc.generate(&privilegeSQLVertexGenerator{})
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+}
+
+func TestBuildMigratedPrivileges(t *testing.T) {
+	keptTable := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"kept\""}
+	droppedTable := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"dropped\""}
+	createdFunction := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"fn\"()"}
+	createdProcedure := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"proc\"()"}
+
+	privileges := buildMigratedPrivileges(
+		[]schema.Privilege{
+			{ObjectType: "TABLE", Object: keptTable, Grantee: "reader", Type: "SELECT"},
+			{ObjectType: "TABLE", Object: droppedTable, Grantee: "reader", Type: "SELECT"},
+		},
+		map[string]bool{droppedTable.GetName(): true},
+		[]schema.SchemaQualifiedName{createdFunction},
+		[]schema.SchemaQualifiedName{createdProcedure},
+	)
+	assert.Equal(t, []schema.Privilege{
+		{ObjectType: "TABLE", Object: keptTable, Grantee: "reader", Type: "SELECT"},
+		{ObjectType: "FUNCTION", Object: createdFunction, Grantee: schema.PrivilegeGranteePublic, Type: "EXECUTE"},
+		{ObjectType: "PROCEDURE", Object: createdProcedure, Grantee: schema.PrivilegeGranteePublic, Type: "EXECUTE"},
+	}, privileges)
+}
+
+func TestGenerateMigrationStatements_Privileges(t *testing.T) {
+	foo := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}
+	bar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"bar\""}
+	columns := []schema.Column{{Name: "id", Type: "integer"}}
+	oldSchema := schema.Schema{
+		Tables: []schema.Table{
+			{SchemaQualifiedName: foo, Columns: columns, ReplicaIdentity: schema.ReplicaIdentityDefault},
+			{SchemaQualifiedName: bar, Columns: columns, ReplicaIdentity: schema.ReplicaIdentityDefault},
+		},
+		Privileges: []schema.Privilege{
+			{ObjectType: "TABLE", Object: foo, Grantee: "reader", Type: "SELECT"},
+			{ObjectType: "TABLE", Object: bar, Grantee: "reader", Type: "SELECT"},
+		},
+	}
+	// foo is re-created as a partitioned table, and bar is dropped
+	newSchema := schema.Schema{
+		Tables: []schema.Table{
+			{SchemaQualifiedName: foo, Columns: columns, ReplicaIdentity: schema.ReplicaIdentityDefault, PartitionKeyDef: "LIST (id)"},
+		},
+		Privileges: []schema.Privilege{
+			{ObjectType: "TABLE", Object: foo, Grantee: "reader", Type: "SELECT"},
+		},
+	}
+
+	stmts, err := generateMigrationStatements(oldSchema, newSchema, &planOptions{})
+	require.NoError(t, err)
+	var ddl []string
+	for _, stmt := range stmts {
+		ddl = append(ddl, stmt.DDL)
+	}
+	createFooIdx := -1
+	grantIdx := -1
+	for i, stmt := range ddl {
+		if strings.HasPrefix(stmt, "CREATE TABLE \"public\".\"foo\"") {
+			createFooIdx = i
+		}
+		if stmt == "GRANT SELECT ON TABLE \"public\".\"foo\" TO \"reader\"" {
+			grantIdx = i
+		}
+		assert.NotContains(t, stmt, "REVOKE")
+	}
+	require.NotEqual(t, -1, createFooIdx, "foo should be re-created: %v", ddl)
+	require.NotEqual(t, -1, grantIdx, "the privileges of foo should be re-granted: %v", ddl)
+	assert.Greater(t, grantIdx, createFooIdx)
+}
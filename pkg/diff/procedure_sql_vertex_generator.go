@@ -39,6 +39,26 @@ func (p procedureSQLVertexGenerator) Add(s schema.Procedure) (partialSQLGraph, e
 		deps = append(deps, mustRun(buildProcedureVertexId(s.SchemaQualifiedName, diffTypeAddAlter)).after(buildSequenceVertexId(seq.SchemaQualifiedName, diffTypeAddAlter)))
 	}
 
+	// Run after the procedures this procedure calls. The tracked dependencies of a procedure can be either
+	// functions or procedures, so we add a dependency on both. A dependency on a vertex that does not exist is a no-op.
+	for _, depFunction := range s.DependsOnFunctions {
+		deps = append(deps,
+			mustRun(buildProcedureVertexId(s.SchemaQualifiedName, diffTypeAddAlter)).after(buildProcedureVertexId(depFunction, diffTypeAddAlter)),
+			mustRun(buildProcedureVertexId(s.SchemaQualifiedName, diffTypeAddAlter)).after(buildFunctionVertexId(depFunction, diffTypeAddAlter)),
+		)
+	}
+
+	var hazards []MigrationHazard
+	if !canProcedureDependenciesBeTracked(s) {
+		hazards = append(hazards, MigrationHazard{
+			Type: MigrationHazardTypeHasUntrackableDependencies,
+			Message: "Dependencies of non-sql procedures are not tracked by Postgres. " +
+				"As a result, we cannot guarantee that this procedure's dependencies are ordered properly relative to " +
+				"this statement. For adds, this means you need to ensure that all objects this procedure depends on " +
+				"are added before this statement.",
+		})
+	}
+
 	return partialSQLGraph{
 		vertices: []sqlVertex{{
 			id:       buildProcedureVertexId(s.SchemaQualifiedName, diffTypeAddAlter),
@@ -47,13 +67,7 @@ func (p procedureSQLVertexGenerator) Add(s schema.Procedure) (partialSQLGraph, e
 				DDL:         s.Def,
 				Timeout:     statementTimeoutDefault,
 				LockTimeout: lockTimeoutDefault,
-				Hazards: []MigrationHazard{{
-					Type: MigrationHazardTypeHasUntrackableDependencies,
-					Message: "Dependencies of procedures are not tracked by Postgres. " +
-						"As a result, we cannot guarantee that this procedure's dependencies are ordered properly relative to " +
-						"this statement. For adds, this means you need to ensure that all objects this function depends on " +
-						"are added before this statement.",
-				}},
+				Hazards:     hazards,
 			}},
 		}},
 		dependencies: deps,
@@ -83,6 +97,25 @@ func (p procedureSQLVertexGenerator) Delete(s schema.Procedure) (partialSQLGraph
 		deps = append(deps, mustRun(buildProcedureVertexId(s.SchemaQualifiedName, diffTypeDelete)).after(buildSequenceVertexId(seq.SchemaQualifiedName, diffTypeAddAlter)))
 	}
 
+	// Run before the functions and procedures this procedure depends on are dropped.
+	for _, depFunction := range s.DependsOnFunctions {
+		deps = append(deps,
+			mustRun(buildProcedureVertexId(s.SchemaQualifiedName, diffTypeDelete)).before(buildProcedureVertexId(depFunction, diffTypeDelete)),
+			mustRun(buildProcedureVertexId(s.SchemaQualifiedName, diffTypeDelete)).before(buildFunctionVertexId(depFunction, diffTypeDelete)),
+		)
+	}
+
+	var hazards []MigrationHazard
+	if !canProcedureDependenciesBeTracked(s) {
+		hazards = append(hazards, MigrationHazard{
+			Type: MigrationHazardTypeHasUntrackableDependencies,
+			Message: "Dependencies of non-sql procedures are not tracked by Postgres. " +
+				"As a result, we cannot guarantee that this procedure's dependencies are ordered properly relative to " +
+				"this statement. For drops, this means you need to ensure that all objects this procedure depends on " +
+				"are dropped after this statement.",
+		})
+	}
+
 	return partialSQLGraph{
 		vertices: []sqlVertex{{
 			id:       buildProcedureVertexId(s.SchemaQualifiedName, diffTypeDelete),
@@ -91,13 +124,7 @@ func (p procedureSQLVertexGenerator) Delete(s schema.Procedure) (partialSQLGraph
 				DDL:         fmt.Sprintf("DROP PROCEDURE %s", s.GetFQEscapedName()),
 				Timeout:     statementTimeoutDefault,
 				LockTimeout: lockTimeoutDefault,
-				Hazards: []MigrationHazard{{
-					Type: MigrationHazardTypeHasUntrackableDependencies,
-					Message: "Dependencies of procedures are not tracked by Postgres. " +
-						"As a result, we cannot guarantee that this procedure's dependencies are ordered properly relative to " +
-						"this statement. For drops, this means you need to ensure that all objects this function depends on " +
-						"are dropped after this statement.",
-				}},
+				Hazards:     hazards,
 			}},
 		}},
 		dependencies: deps,
@@ -112,6 +139,10 @@ func (p procedureSQLVertexGenerator) Alter(d procedureDiff) (partialSQLGraph, er
 	return p.Add(d.new)
 }
 
+func canProcedureDependenciesBeTracked(procedure schema.Procedure) bool {
+	return procedure.Language == "sql"
+}
+
 func buildProcedureVertexId(name schema.SchemaQualifiedName, diffType diffType) sqlVertexId {
 	return buildSchemaObjVertexId("procedure", name.GetFQEscapedName(), diffType)
 }
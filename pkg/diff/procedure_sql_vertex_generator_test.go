@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestProcedureSQLVertexGenerator_Hazards(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		procedure schema.Procedure
+
+		expectedHazardTypes []MigrationHazardType
+	}{
+		{
+			name: "SQL procedure",
+			procedure: schema.Procedure{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"log_message\"(msg text)"},
+				Def:                 "CREATE OR REPLACE PROCEDURE public.log_message(msg text)\n LANGUAGE sql\nBEGIN ATOMIC\n INSERT INTO public.audit_log (message) VALUES (log_message.msg);\nEND\n",
+				Language:            "sql",
+			},
+		},
+		{
+			name: "plpgsql procedure",
+			procedure: schema.Procedure{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"log_message\"(msg text)"},
+				Def:                 "CREATE OR REPLACE PROCEDURE public.log_message(msg text)\n LANGUAGE plpgsql\nAS $procedure$ BEGIN RAISE NOTICE '%', msg; END $procedure$\n",
+				Language:            "plpgsql",
+			},
+			expectedHazardTypes: []MigrationHazardType{MigrationHazardTypeHasUntrackableDependencies},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hazardTypes := func(hazards []MigrationHazard) []MigrationHazardType {
+				var types []MigrationHazardType
+				for _, h := range hazards {
+					types = append(types, h.Type)
+				}
+				return types
+			}
+			generator := newProcedureSqlVertexGenerator(schema.Schema{})
+
+			addGraph, err := generator.Add(tc.procedure)
+			require.NoError(t, err)
+			require.Len(t, addGraph.vertices, 1)
+			require.Len(t, addGraph.vertices[0].statements, 1)
+			assert.Equal(t, tc.procedure.Def, addGraph.vertices[0].statements[0].DDL)
+			assert.Equal(t, tc.expectedHazardTypes, hazardTypes(addGraph.vertices[0].statements[0].Hazards))
+
+			deleteGraph, err := generator.Delete(tc.procedure)
+			require.NoError(t, err)
+			require.Len(t, deleteGraph.vertices, 1)
+			require.Len(t, deleteGraph.vertices[0].statements, 1)
+			assert.Equal(t, "DROP PROCEDURE \"public\".\"log_message\"(msg text)", deleteGraph.vertices[0].statements[0].DDL)
+			assert.Equal(t, tc.expectedHazardTypes, hazardTypes(deleteGraph.vertices[0].statements[0].Hazards))
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_ProcedureDependencies(t *testing.T) {
+	calleeName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"log_message\"(msg text)"}
+	callee := schema.Procedure{
+		SchemaQualifiedName: calleeName,
+		Def:                 "CREATE OR REPLACE PROCEDURE public.log_message(msg text)\n LANGUAGE sql\nBEGIN ATOMIC\n SELECT 1;\nEND\n",
+		Language:            "sql",
+	}
+	// Name the caller such that it sorts before the callee, i.e., the ordering must come from the dependency
+	caller := schema.Procedure{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"a_log_twice\"(msg text)"},
+		Def:                 "CREATE OR REPLACE PROCEDURE public.a_log_twice(msg text)\n LANGUAGE sql\nBEGIN ATOMIC\n CALL public.log_message(a_log_twice.msg);\n CALL public.log_message(a_log_twice.msg);\nEND\n",
+		Language:            "sql",
+		DependsOnFunctions:  []schema.SchemaQualifiedName{calleeName},
+	}
+	withProcedures := schema.Schema{Procedures: []schema.Procedure{caller, callee}}
+
+	stmtIdx := func(t *testing.T, stmts []Statement, prefix string) int {
+		for i, stmt := range stmts {
+			if strings.HasPrefix(stmt.DDL, prefix) {
+				return i
+			}
+		}
+		require.Failf(t, "statement not found", "%q not found in %v", prefix, stmts)
+		return -1
+	}
+
+	t.Run("Add", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(schema.Schema{}, withProcedures, &planOptions{})
+		require.NoError(t, err)
+		// The callee must be created before the caller
+		calleeIdx := stmtIdx(t, stmts, "CREATE OR REPLACE PROCEDURE public.log_message")
+		callerIdx := stmtIdx(t, stmts, "CREATE OR REPLACE PROCEDURE public.a_log_twice")
+		assert.Less(t, calleeIdx, callerIdx)
+		// The dependencies of SQL procedures are tracked, so there are no hazards
+		assert.Empty(t, stmts[calleeIdx].Hazards)
+		assert.Empty(t, stmts[callerIdx].Hazards)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(withProcedures, schema.Schema{}, &planOptions{})
+		require.NoError(t, err)
+		require.Len(t, stmts, 2)
+		// The caller must be dropped before the callee
+		assert.Less(t, stmtIdx(t, stmts, "DROP PROCEDURE \"public\".\"a_log_twice\""), stmtIdx(t, stmts, "DROP PROCEDURE \"public\".\"log_message\""))
+	})
+}
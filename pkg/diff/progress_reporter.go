@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+)
+
+// ProgressReporter reports the progress of a plan's execution, e.g., to give operators feedback during long-running
+// index builds.
+type ProgressReporter interface {
+	// ReportProgress is called before the statement at step (1-indexed) of total is executed. Steps and total include
+	// advisory statements, which are not executed. Use stmt.IsAdvisory to distinguish them.
+	ReportProgress(ctx context.Context, step int, total int, stmt Statement)
+}
+
+// WithProgressReporter configures the plan to report its progress to the reporter. Progress is reported by
+// Plan.ReportProgress.
+func WithProgressReporter(reporter ProgressReporter) PlanOpt {
+	return func(opts *planOptions) {
+		opts.progressReporter = reporter
+	}
+}
+
+// SetProgressReporter sets the reporter that the plan reports its progress to, e.g., after deserializing a plan.
+func (p Plan) SetProgressReporter(reporter ProgressReporter) Plan {
+	p.progressReporter = reporter
+	return p
+}
+
+// ReportProgress reports that the statement at index is about to be executed. It should be called by your plan executor
+// before each statement, in order, including advisory statements. It is a no-op if the plan has no progress reporter.
+func (p Plan) ReportProgress(ctx context.Context, index int) {
+	if p.progressReporter == nil || index < 0 || index >= len(p.Statements) {
+		return
+	}
+	p.progressReporter.ReportProgress(ctx, index+1, len(p.Statements), p.Statements[index])
+}
+
+// LogProgressReporter reports progress as a line per statement written to a logger, e.g.,
+// "[2/3] executing statement: CREATE INDEX ..."
+type LogProgressReporter struct {
+	logger *log.Logger
+}
+
+func NewLogProgressReporter(logger *log.Logger) *LogProgressReporter {
+	return &LogProgressReporter{logger: logger}
+}
+
+func (r *LogProgressReporter) ReportProgress(_ context.Context, step int, total int, stmt Statement) {
+	action := "executing statement"
+	if stmt.IsAdvisory {
+		action = "advisory statement"
+	}
+	r.logger.Printf("[%d/%d] %s: %s", step, total, action, summarizeDDL(stmt.DDL))
+}
+
+// JSONProgressReport is the JSON line written by JSONProgressReporter for each statement
+type JSONProgressReport struct {
+	Step       int    `json:"step"`
+	Total      int    `json:"total"`
+	IsAdvisory bool   `json:"is_advisory"`
+	Summary    string `json:"summary"`
+}
+
+// JSONProgressReporter reports progress as a JSON line per statement written to a writer, e.g., for consumption by a
+// log pipeline. Errors writing to the writer are ignored, such that reporting progress never fails a migration.
+type JSONProgressReporter struct {
+	encoder *json.Encoder
+}
+
+func NewJSONProgressReporter(w io.Writer) *JSONProgressReporter {
+	return &JSONProgressReporter{encoder: json.NewEncoder(w)}
+}
+
+func (r *JSONProgressReporter) ReportProgress(_ context.Context, step int, total int, stmt Statement) {
+	_ = r.encoder.Encode(JSONProgressReport{
+		Step:       step,
+		Total:      total,
+		IsAdvisory: stmt.IsAdvisory,
+		Summary:    summarizeDDL(stmt.DDL),
+	})
+}
@@ -0,0 +1,62 @@
+package diff_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stripe/pg-schema-diff/pkg/diff"
+)
+
+var progressReporterTestPlan = diff.Plan{
+	Statements: []diff.Statement{
+		{DDL: "CREATE TABLE \"public\".\"foo\" (\n\t\"id\" integer NOT NULL\n)"},
+		{DDL: "CREATE INDEX CONCURRENTLY foo_idx ON public.foo USING btree (id)", RequiresNoTransaction: true},
+		{DDL: "ANALYZE \"public\".\"foo\"", IsAdvisory: true},
+	},
+}
+
+func reportPlanProgress(plan diff.Plan) {
+	for i := range plan.Statements {
+		plan.ReportProgress(context.Background(), i)
+	}
+}
+
+func TestLogProgressReporter(t *testing.T) {
+	buf := bytes.Buffer{}
+	reportPlanProgress(progressReporterTestPlan.SetProgressReporter(diff.NewLogProgressReporter(log.New(&buf, "", 0))))
+	assert.Equal(t, []string{
+		`[1/3] executing statement: CREATE TABLE "public"."foo" ( "id" integer NOT NULL )`,
+		`[2/3] executing statement: CREATE INDEX CONCURRENTLY foo_idx ON public.foo USING btr...`,
+		`[3/3] advisory statement: ANALYZE "public"."foo"`,
+	}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
+
+func TestJSONProgressReporter(t *testing.T) {
+	buf := bytes.Buffer{}
+	reportPlanProgress(progressReporterTestPlan.SetProgressReporter(diff.NewJSONProgressReporter(&buf)))
+
+	var reports []diff.JSONProgressReport
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var report diff.JSONProgressReport
+		require.NoError(t, json.Unmarshal([]byte(line), &report))
+		reports = append(reports, report)
+	}
+	assert.Equal(t, []diff.JSONProgressReport{
+		{Step: 1, Total: 3, Summary: `CREATE TABLE "public"."foo" ( "id" integer NOT NULL )`},
+		{Step: 2, Total: 3, Summary: `CREATE INDEX CONCURRENTLY foo_idx ON public.foo USING btr...`},
+		{Step: 3, Total: 3, IsAdvisory: true, Summary: `ANALYZE "public"."foo"`},
+	}, reports)
+}
+
+func TestPlan_ReportProgressWithoutReporter(t *testing.T) {
+	assert.NotPanics(t, func() {
+		reportPlanProgress(progressReporterTestPlan)
+	})
+}
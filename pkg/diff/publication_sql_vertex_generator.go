@@ -0,0 +1,144 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	migrationHazardPublicationSubscribersAffected = MigrationHazard{
+		Type:    MigrationHazardTypeHasUntrackableDependencies,
+		Message: "Subscriptions to this publication will stop receiving changes for the removed tables. Subscriptions cannot be tracked.",
+	}
+	migrationHazardPublicationSubscriptionRefreshRequired = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "Subscriptions to this publication will not receive changes for the added tables until they are " +
+			"refreshed via `ALTER SUBSCRIPTION ... REFRESH PUBLICATION`. Subscriptions cannot be tracked.",
+	}
+)
+
+type publicationSQLVertexGenerator struct{}
+
+func newPublicationSqlVertexGenerator() sqlVertexGenerator[schema.Publication, publicationDiff] {
+	return legacyToNewSqlVertexGenerator[schema.Publication, publicationDiff](&publicationSQLVertexGenerator{})
+}
+
+func (p *publicationSQLVertexGenerator) Add(publication schema.Publication) ([]Statement, error) {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("CREATE PUBLICATION %s", schema.EscapeIdentifier(publication.Name)))
+	if publication.AllTables {
+		sb.WriteString(" FOR ALL TABLES")
+	} else if len(publication.Tables) > 0 {
+		sb.WriteString(fmt.Sprintf(" FOR TABLE %s", buildPublicationTableList(publication.Tables)))
+	}
+	sb.WriteString(fmt.Sprintf(" WITH (%s)", buildPublishParameter(publication.Publish)))
+
+	return []Statement{{
+		DDL:         sb.String(),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}}, nil
+}
+
+func (p *publicationSQLVertexGenerator) Delete(publication schema.Publication) ([]Statement, error) {
+	return []Statement{{
+		DDL:         fmt.Sprintf("DROP PUBLICATION %s", schema.EscapeIdentifier(publication.Name)),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardPublicationSubscribersAffected},
+	}}, nil
+}
+
+func (p *publicationSQLVertexGenerator) Alter(diff publicationDiff) ([]Statement, error) {
+	// Postgres does not support altering a publication to or from `FOR ALL TABLES`, so those publications are
+	// re-created.
+	if diff.old.AllTables != diff.new.AllTables {
+		return nil, fmt.Errorf("altering publication to or from FOR ALL TABLES should be done via re-creation: %w", ErrNotImplemented)
+	}
+	alterPrefix := fmt.Sprintf("ALTER PUBLICATION %s", schema.EscapeIdentifier(diff.new.Name))
+
+	var stmts []Statement
+	// Tables are added and dropped individually rather than replacing the list via SET TABLE, since tables that are
+	// filtered out of the schema are not tracked and should not be removed from the publication.
+	oldTablesByName := buildSchemaObjByNameMap(diff.old.Tables)
+	newTablesByName := buildSchemaObjByNameMap(diff.new.Tables)
+	var addedTables []schema.SchemaQualifiedName
+	for _, table := range diff.new.Tables {
+		if _, ok := oldTablesByName[table.GetName()]; !ok {
+			addedTables = append(addedTables, table)
+		}
+	}
+	var droppedTables []schema.SchemaQualifiedName
+	for _, table := range diff.old.Tables {
+		if _, ok := newTablesByName[table.GetName()]; !ok {
+			droppedTables = append(droppedTables, table)
+		}
+	}
+	if len(addedTables) > 0 {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s ADD TABLE %s", alterPrefix, buildPublicationTableList(addedTables)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardPublicationSubscriptionRefreshRequired},
+		})
+	}
+	if len(droppedTables) > 0 {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s DROP TABLE %s", alterPrefix, buildPublicationTableList(droppedTables)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardPublicationSubscribersAffected},
+		})
+	}
+
+	if buildPublishParameter(diff.old.Publish) != buildPublishParameter(diff.new.Publish) {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s SET (%s)", alterPrefix, buildPublishParameter(diff.new.Publish)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	}
+
+	return stmts, nil
+}
+
+func buildPublicationTableList(tables []schema.SchemaQualifiedName) string {
+	var tableNames []string
+	for _, table := range tables {
+		tableNames = append(tableNames, table.GetFQEscapedName())
+	}
+	return strings.Join(tableNames, ", ")
+}
+
+func buildPublishParameter(publish []string) string {
+	return fmt.Sprintf("publish = '%s'", strings.Join(publish, ", "))
+}
+
+func (p *publicationSQLVertexGenerator) GetSQLVertexId(publication schema.Publication, diffType diffType) sqlVertexId {
+	return buildSchemaObjVertexId("publication", publication.GetName(), diffType)
+}
+
+func (p *publicationSQLVertexGenerator) GetAddAlterDependencies(newPublication, oldPublication schema.Publication) ([]dependency, error) {
+	deps := []dependency{
+		mustRun(p.GetSQLVertexId(newPublication, diffTypeAddAlter)).after(p.GetSQLVertexId(newPublication, diffTypeDelete)),
+	}
+	// The tables must exist before they are added to the publication
+	for _, table := range newPublication.Tables {
+		deps = append(deps, mustRun(p.GetSQLVertexId(newPublication, diffTypeAddAlter)).after(buildTableVertexId(table, diffTypeAddAlter)))
+	}
+	// The tables dropped from the publication must still exist when they are dropped from the publication
+	for _, table := range oldPublication.Tables {
+		deps = append(deps, mustRun(p.GetSQLVertexId(newPublication, diffTypeAddAlter)).before(buildTableVertexId(table, diffTypeDelete)))
+	}
+	return deps, nil
+}
+
+func (p *publicationSQLVertexGenerator) GetDeleteDependencies(publication schema.Publication) ([]dependency, error) {
+	var deps []dependency
+	for _, table := range publication.Tables {
+		deps = append(deps, mustRun(p.GetSQLVertexId(publication, diffTypeDelete)).before(buildTableVertexId(table, diffTypeDelete)))
+	}
+	return deps, nil
+}
@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestPublicationSQLVertexGenerator_Add(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+	fizzbuzz := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"fizzbuzz"`}
+
+	for _, tc := range []struct {
+		name        string
+		publication schema.Publication
+		expectedDDL string
+	}{
+		{
+			name:        "All tables",
+			publication: schema.Publication{Name: "pub", AllTables: true, Publish: []string{"insert", "update"}},
+			expectedDDL: `CREATE PUBLICATION "pub" FOR ALL TABLES WITH (publish = 'insert, update')`,
+		},
+		{
+			name:        "Table list",
+			publication: schema.Publication{Name: "pub", Tables: []schema.SchemaQualifiedName{fizzbuzz, foobar}, Publish: []string{"insert"}},
+			expectedDDL: `CREATE PUBLICATION "pub" FOR TABLE "public"."fizzbuzz", "public"."foobar" WITH (publish = 'insert')`,
+		},
+		{
+			name:        "No tables",
+			publication: schema.Publication{Name: "pub", Publish: []string{"insert"}},
+			expectedDDL: `CREATE PUBLICATION "pub" WITH (publish = 'insert')`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := (&publicationSQLVertexGenerator{}).Add(tc.publication)
+			require.NoError(t, err)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tc.expectedDDL, stmts[0].DDL)
+		})
+	}
+}
+
+func TestPublicationSQLVertexGenerator_Alter(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+	fizzbuzz := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"fizzbuzz"`}
+
+	gen := &publicationSQLVertexGenerator{}
+	stmts, err := gen.Alter(publicationDiff{oldAndNew[schema.Publication]{
+		old: schema.Publication{Name: "pub", Tables: []schema.SchemaQualifiedName{foobar}, Publish: []string{"insert"}},
+		new: schema.Publication{Name: "pub", Tables: []schema.SchemaQualifiedName{fizzbuzz}, Publish: []string{"insert", "delete"}},
+	}})
+	require.NoError(t, err)
+	var ddl []string
+	var hazards [][]MigrationHazard
+	for _, stmt := range stmts {
+		ddl = append(ddl, stmt.DDL)
+		hazards = append(hazards, stmt.Hazards)
+	}
+	assert.Equal(t, []string{
+		`ALTER PUBLICATION "pub" ADD TABLE "public"."fizzbuzz"`,
+		`ALTER PUBLICATION "pub" DROP TABLE "public"."foobar"`,
+		`ALTER PUBLICATION "pub" SET (publish = 'insert, delete')`,
+	}, ddl)
+	assert.Equal(t, [][]MigrationHazard{
+		{migrationHazardPublicationSubscriptionRefreshRequired},
+		{migrationHazardPublicationSubscribersAffected},
+		nil,
+	}, hazards)
+
+	_, err = gen.Alter(publicationDiff{oldAndNew[schema.Publication]{
+		old: schema.Publication{Name: "pub", Tables: []schema.SchemaQualifiedName{foobar}},
+		new: schema.Publication{Name: "pub", AllTables: true},
+	}})
+	assert.ErrorIs(t, err, ErrNotImplemented)
+}
@@ -0,0 +1,136 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	pg_query "github.com/pganalyze/pg_query_go/v5"
+)
+
+var (
+	// trustedExtensions are the extensions that are marked as trusted, i.e., they can be created by non-superusers
+	// with the CREATE privilege on the database. The other extensions might require superuser.
+	trustedExtensions = map[string]bool{
+		"btree_gin":       true,
+		"btree_gist":      true,
+		"citext":          true,
+		"cube":            true,
+		"dict_int":        true,
+		"earthdistance":   true,
+		"fuzzystrmatch":   true,
+		"hstore":          true,
+		"intarray":        true,
+		"isn":             true,
+		"lo":              true,
+		"ltree":           true,
+		"pgcrypto":        true,
+		"pg_trgm":         true,
+		"plpgsql":         true,
+		"seg":             true,
+		"tablefunc":       true,
+		"tcn":             true,
+		"tsm_system_rows": true,
+		"tsm_system_time": true,
+		"unaccent":        true,
+		"uuid-ossp":       true,
+	}
+
+	// untrustedLanguages are the languages that functions can only be created in by superusers
+	untrustedLanguages = map[string]bool{
+		"c":          true,
+		"internal":   true,
+		"plperlu":    true,
+		"plpython3u": true,
+		"pltclu":     true,
+	}
+)
+
+// WithRDSMode configures the plan generation for a database on Amazon RDS, where the migration cannot be run as a
+// superuser. Statements that require superuser, e.g., creating untrusted extensions, foreign data wrappers, and
+// functions in untrusted languages, have a MigrationHazardTypeRequiresSuperuser hazard.
+//
+// Statements on event triggers are commented out and marked as advisory, since a superuser must run them separately.
+// The advisory `ANALYZE` statements, e.g., to populate the statistics of new statistics objects, are not generated.
+func WithRDSMode() PlanOpt {
+	return func(opts *planOptions) {
+		opts.rdsMode = true
+	}
+}
+
+// applyRDSRestrictions adds a MigrationHazardTypeRequiresSuperuser hazard to the statements that require superuser.
+// Statements on event triggers are commented out and marked as advisory.
+func applyRDSRestrictions(stmts []Statement) []Statement {
+	var restrictedStmts []Statement
+	for _, stmt := range stmts {
+		if reason, ok := getSuperuserRequirement(stmt.DDL); ok {
+			stmt.Hazards = append(append([]MigrationHazard(nil), stmt.Hazards...), MigrationHazard{
+				Type:    MigrationHazardTypeRequiresSuperuser,
+				Message: fmt.Sprintf("This statement %s, which requires superuser. Superuser is not available on Amazon RDS.", reason),
+			})
+		}
+		if isEventTriggerStatement(stmt.DDL) {
+			stmt.Hazards = append(append([]MigrationHazard(nil), stmt.Hazards...), MigrationHazard{
+				Type: MigrationHazardTypeRequiresSuperuser,
+				Message: "Event triggers can only be modified by superusers, so the statement is commented out and must " +
+					"be run separately by a superuser, e.g., the RDS master user.",
+			})
+			stmt.DDL = fmt.Sprintf("/*\n  A superuser must run the following statement separately:\n\n  %s\n*/", strings.ReplaceAll(stmt.DDL, "\n", "\n  "))
+			stmt.IsAdvisory = true
+		}
+		restrictedStmts = append(restrictedStmts, stmt)
+	}
+	return restrictedStmts
+}
+
+// getSuperuserRequirement returns the reason the DDL requires superuser, e.g., "creates the untrusted extension
+// postgis". Event triggers are handled separately by isEventTriggerStatement.
+func getSuperuserRequirement(ddl string) (string, bool) {
+	result, err := pg_query.Parse(ddl)
+	if err != nil || len(result.Stmts) != 1 {
+		return "", false
+	}
+	switch n := result.Stmts[0].Stmt.Node.(type) {
+	case *pg_query.Node_CreateExtensionStmt:
+		if !trustedExtensions[n.CreateExtensionStmt.Extname] {
+			return fmt.Sprintf("creates the untrusted extension %s", n.CreateExtensionStmt.Extname), true
+		}
+	case *pg_query.Node_CreateFdwStmt, *pg_query.Node_AlterFdwStmt:
+		return "creates or alters a foreign data wrapper", true
+	case *pg_query.Node_CreatePublicationStmt:
+		if n.CreatePublicationStmt.ForAllTables {
+			return "creates a publication for all tables", true
+		}
+	case *pg_query.Node_CreateFunctionStmt:
+		for _, option := range n.CreateFunctionStmt.Options {
+			defElem := option.GetDefElem()
+			if defElem == nil || defElem.Defname != "language" {
+				continue
+			}
+			if language := defElem.Arg.GetString_().GetSval(); untrustedLanguages[strings.ToLower(language)] {
+				return fmt.Sprintf("creates a function in the untrusted language %s", language), true
+			}
+		}
+	}
+	return "", false
+}
+
+// isEventTriggerStatement returns whether the DDL creates, alters, drops, or comments on an event trigger
+func isEventTriggerStatement(ddl string) bool {
+	result, err := pg_query.Parse(ddl)
+	if err != nil || len(result.Stmts) != 1 {
+		return false
+	}
+	switch n := result.Stmts[0].Stmt.Node.(type) {
+	case *pg_query.Node_CreateEventTrigStmt, *pg_query.Node_AlterEventTrigStmt:
+		return true
+	case *pg_query.Node_DropStmt:
+		return n.DropStmt.RemoveType == pg_query.ObjectType_OBJECT_EVENT_TRIGGER
+	case *pg_query.Node_CommentStmt:
+		return n.CommentStmt.Objtype == pg_query.ObjectType_OBJECT_EVENT_TRIGGER
+	case *pg_query.Node_AlterOwnerStmt:
+		return n.AlterOwnerStmt.ObjectType == pg_query.ObjectType_OBJECT_EVENT_TRIGGER
+	case *pg_query.Node_RenameStmt:
+		return n.RenameStmt.RenameType == pg_query.ObjectType_OBJECT_EVENT_TRIGGER
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestApplyRDSRestrictions(t *testing.T) {
+	for _, tc := range []struct {
+		name                  string
+		ddl                   string
+		expectSuperuserHazard bool
+	}{
+		{
+			name:                  "Untrusted extension",
+			ddl:                   `CREATE EXTENSION "postgis" WITH SCHEMA "public"`,
+			expectSuperuserHazard: true,
+		},
+		{
+			name: "Trusted extension",
+			ddl:  `CREATE EXTENSION "pgcrypto" WITH SCHEMA "public"`,
+		},
+		{
+			name:                  "Foreign data wrapper",
+			ddl:                   `CREATE FOREIGN DATA WRAPPER "some_fdw"`,
+			expectSuperuserHazard: true,
+		},
+		{
+			name:                  "Publication for all tables",
+			ddl:                   `CREATE PUBLICATION "some_pub" FOR ALL TABLES`,
+			expectSuperuserHazard: true,
+		},
+		{
+			name:                  "Function in an untrusted language",
+			ddl:                   `CREATE FUNCTION add(integer, integer) RETURNS integer AS 'some_lib', 'add' LANGUAGE C STRICT`,
+			expectSuperuserHazard: true,
+		},
+		{
+			name: "Function in a trusted language",
+			ddl:  `CREATE FUNCTION add(a integer, b integer) RETURNS integer LANGUAGE sql AS $$ SELECT a + b $$`,
+		},
+		{
+			name: "Table",
+			ddl:  `CREATE TABLE "public"."foobar" ("id" integer)`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts := applyRDSRestrictions([]Statement{{DDL: tc.ddl}})
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tc.ddl, stmts[0].DDL)
+			assert.False(t, stmts[0].IsAdvisory)
+			if tc.expectSuperuserHazard {
+				require.Len(t, stmts[0].Hazards, 1)
+				assert.Equal(t, MigrationHazardTypeRequiresSuperuser, stmts[0].Hazards[0].Type)
+			} else {
+				assert.Empty(t, stmts[0].Hazards)
+			}
+		})
+	}
+}
+
+func TestRDSMode(t *testing.T) {
+	t.Run("Event triggers are commented out", func(t *testing.T) {
+		eventTrigger := schema.EventTrigger{
+			Name:     "log_ddl",
+			Event:    "ddl_command_end",
+			Function: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"log_ddl_command"`},
+			Enabled:  "O",
+		}
+		stmts, err := generateMigrationStatements(
+			schema.Schema{},
+			schema.Schema{EventTriggers: []schema.EventTrigger{eventTrigger}},
+			&planOptions{rdsMode: true},
+		)
+		require.NoError(t, err)
+		require.Len(t, stmts, 1)
+		assert.True(t, stmts[0].IsAdvisory)
+		assert.True(t, strings.HasPrefix(stmts[0].DDL, "/*"))
+		assert.True(t, strings.HasSuffix(stmts[0].DDL, "*/"))
+		assert.Contains(t, stmts[0].DDL, `CREATE EVENT TRIGGER "log_ddl"`)
+		require.Len(t, stmts[0].Hazards, 1)
+		assert.Equal(t, MigrationHazardTypeRequiresSuperuser, stmts[0].Hazards[0].Type)
+
+		stmts, err = generateMigrationStatements(
+			schema.Schema{EventTriggers: []schema.EventTrigger{eventTrigger}},
+			schema.Schema{},
+			&planOptions{rdsMode: true},
+		)
+		require.NoError(t, err)
+		require.Len(t, stmts, 1)
+		assert.True(t, stmts[0].IsAdvisory)
+		assert.Contains(t, stmts[0].DDL, `DROP EVENT TRIGGER IF EXISTS "log_ddl"`)
+	})
+
+	t.Run("Advisory ANALYZE statements are not generated", func(t *testing.T) {
+		table := schema.Table{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"`},
+			Columns:             []schema.Column{{Name: "city", Type: "text"}, {Name: "zip", Type: "text"}},
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		}
+		newSchema := schema.Schema{
+			Tables: []schema.Table{table},
+			StatisticsObjects: []schema.StatisticsObject{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo_city_zip"`},
+				Table:               table.SchemaQualifiedName,
+				Kinds:               []string{"ndistinct"},
+				Columns:             []string{"city", "zip"},
+				StatisticsTarget:    -1,
+				Def:                 "CREATE STATISTICS public.foo_city_zip (ndistinct) ON city, zip FROM public.foo",
+			}},
+		}
+
+		stmts, err := generateMigrationStatements(schema.Schema{Tables: []schema.Table{table}}, newSchema, &planOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"CREATE STATISTICS public.foo_city_zip (ndistinct) ON city, zip FROM public.foo",
+			`ANALYZE "public"."foo"`,
+		}, getDDL(Plan{Statements: stmts}))
+
+		stmts, err = generateMigrationStatements(schema.Schema{Tables: []schema.Table{table}}, newSchema, &planOptions{rdsMode: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"CREATE STATISTICS public.foo_city_zip (ndistinct) ON city, zip FROM public.foo",
+		}, getDDL(Plan{Statements: stmts}))
+	})
+}
@@ -0,0 +1,376 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+const (
+	renderGroupOther = "Other"
+
+	renderColorReset  = "\x1b[0m"
+	renderColorRed    = "\x1b[31m"
+	renderColorGreen  = "\x1b[32m"
+	renderColorYellow = "\x1b[33m"
+)
+
+var (
+	renderDropColumnRegex      = regexp.MustCompile(`^DROP COLUMN (` + identifierPattern + `)$`)
+	renderSetColumnTypeRegex   = regexp.MustCompile(`^ALTER COLUMN (` + identifierPattern + `) SET DATA TYPE (.+?)(?: COLLATE | using |$)`)
+	renderAlterColumnRegex     = regexp.MustCompile(`^ALTER COLUMN (` + identifierPattern + `) (.*)$`)
+	renderAddColumnTypeRegex   = regexp.MustCompile(`^ADD COLUMN ` + identifierPattern + ` (.*)$`)
+	renderTableSubObjVerbRegex = regexp.MustCompile(`^(CREATE|DROP|ALTER|COMMENT ON) `)
+	renderAnalyzeRegex         = regexp.MustCompile(`^ANALYZE (` + qualifiedIdentifierPattern + `)`)
+
+	// renderGroupsByObjType are the groups objects are rendered in by their type. The groups are rendered in order.
+	renderGroupsByObjType = []struct {
+		objType string
+		group   string
+	}{
+		{objType: "SCHEMA", group: "Schemas"},
+		{objType: "EXTENSION", group: "Extensions"},
+		{objType: "COLLATION", group: "Collations"},
+		{objType: "TYPE", group: "Types"},
+		{objType: "DOMAIN", group: "Domains"},
+		{objType: "SEQUENCE", group: "Sequences"},
+		{objType: "TABLE", group: "Tables"},
+		{objType: "FOREIGN TABLE", group: "Foreign tables"},
+		{objType: "INDEX", group: "Indexes"},
+		{objType: "STATISTICS", group: "Statistics"},
+		{objType: "VIEW", group: "Views"},
+		{objType: "MATERIALIZED VIEW", group: "Materialized views"},
+		{objType: "FUNCTION", group: "Functions"},
+		{objType: "PROCEDURE", group: "Procedures"},
+		{objType: "AGGREGATE", group: "Aggregates"},
+		{objType: "EVENT TRIGGER", group: "Event triggers"},
+		{objType: "PUBLICATION", group: "Publications"},
+		{objType: "FOREIGN DATA WRAPPER", group: "Foreign data wrappers"},
+		{objType: "SERVER", group: "Foreign servers"},
+		{objType: "TEXT SEARCH CONFIGURATION", group: "Text search configurations"},
+		{objType: "TEXT SEARCH DICTIONARY", group: "Text search dictionaries"},
+	}
+
+	// renderRedHazardTypes are the hazards that are highlighted in red rather than yellow, since they cannot be undone
+	renderRedHazardTypes = map[MigrationHazardType]bool{
+		MigrationHazardTypeDeletesData:               true,
+		MigrationHazardTypeImpossibleToRollback:      true,
+		MigrationHazardTypeImpossibleWithoutDowntime: true,
+	}
+)
+
+// renderState is used by RenderHuman to describe the changes that the statements alone do not describe
+type renderState struct {
+	// oldColumnTypes are the types of the columns in the current schema, keyed by their normalized qualified names,
+	// e.g., "public"."foobar"."id"
+	oldColumnTypes map[string]string
+	// currentObjects are the functions and procedures in the current schema, keyed like getModifiedObjects, such that
+	// `CREATE OR REPLACE` statements can be rendered as creations or modifications
+	currentObjects map[string]bool
+}
+
+func newRenderState(currentSchema schema.Schema) *renderState {
+	state := &renderState{
+		oldColumnTypes: make(map[string]string),
+		currentObjects: make(map[string]bool),
+	}
+	for _, table := range currentSchema.Tables {
+		for _, col := range table.Columns {
+			state.oldColumnTypes[normalizeQualifiedIdentifier(table.GetFQEscapedName()+"."+schema.EscapeIdentifier(col.Name))] = col.Type
+		}
+	}
+	for _, function := range currentSchema.Functions {
+		state.currentObjects[buildMergeObjectKey("FUNCTION", stripSignature(function.GetFQEscapedName()))] = true
+	}
+	for _, procedure := range currentSchema.Procedures {
+		state.currentObjects[buildMergeObjectKey("PROCEDURE", stripSignature(procedure.GetFQEscapedName()))] = true
+	}
+	return state
+}
+
+func mergeRenderStates(a, b *renderState) *renderState {
+	if a == nil || b == nil {
+		return nil
+	}
+	merged := &renderState{
+		oldColumnTypes: make(map[string]string),
+		currentObjects: make(map[string]bool),
+	}
+	for _, state := range []*renderState{a, b} {
+		for k, v := range state.oldColumnTypes {
+			merged.oldColumnTypes[k] = v
+		}
+		for k, v := range state.currentObjects {
+			merged.currentObjects[k] = v
+		}
+	}
+	return merged
+}
+
+func stripSignature(fqEscapedName string) string {
+	name, _, _ := strings.Cut(fqEscapedName, "(")
+	return name
+}
+
+type (
+	renderedObject struct {
+		name    string
+		created bool
+		dropped bool
+		lines   []renderedLine
+	}
+
+	// renderedLine is a change to an object or a hazard, rendered below the object
+	renderedLine struct {
+		indent int
+		symbol string
+		text   string
+		hazard *MigrationHazard
+	}
+)
+
+// RenderHuman writes a human-readable summary of the plan, resembling the output of `terraform plan`, e.g., to be
+// posted on pull requests in GitOps workflows. Objects are grouped by type, e.g., Tables and Indexes, and prefixed with
+// "+" if they are created, "-" if they are dropped, "-/+" if they are re-created, and "~" if they are modified. The
+// changes to modified objects, e.g., added columns and column type changes, are listed below them, followed by the
+// hazards of the statements. The hazards of the plan itself are listed after the objects. If color is true, the output includes ANSI color codes, and hazards are highlighted in
+// yellow, or red if they cannot be undone.
+//
+// Objects are identified from the DDL of the statements, so statements that do not operate on a single known object,
+// e.g., `GRANT`s, are listed under "Other". Column type changes only include the old type if the plan was generated
+// in this process, since the current schema is not serialized.
+func (p Plan) RenderHuman(w io.Writer, color bool) error {
+	objectsByGroup := make(map[string][]*renderedObject)
+	objectsByKey := make(map[string]*renderedObject)
+	var advisoryStmts []Statement
+	for _, stmt := range p.Statements {
+		if stmt.IsAdvisory {
+			advisoryStmts = append(advisoryStmts, stmt)
+			continue
+		}
+		group, name, symbol, detail := p.describeStatement(stmt)
+		key := group + " " + name
+		obj, ok := objectsByKey[key]
+		if !ok || group == renderGroupOther {
+			obj = &renderedObject{name: name}
+			objectsByKey[key] = obj
+			objectsByGroup[group] = append(objectsByGroup[group], obj)
+		}
+
+		hazardIndent := 6
+		if detail != "" {
+			obj.lines = append(obj.lines, renderedLine{indent: 6, symbol: symbol, text: detail})
+			hazardIndent = 8
+		} else if symbol == "+" {
+			obj.created = true
+		} else if symbol == "-" {
+			obj.dropped = true
+		}
+		for i := range stmt.Hazards {
+			obj.lines = append(obj.lines, renderedLine{indent: hazardIndent, symbol: "!", hazard: &stmt.Hazards[i]})
+		}
+	}
+
+	sb := strings.Builder{}
+	toAdd, toChange, toDestroy := 0, 0, 0
+	var groups []string
+	for _, groupByObjType := range renderGroupsByObjType {
+		groups = append(groups, groupByObjType.group)
+	}
+	for _, group := range append(groups, renderGroupOther) {
+		objects := objectsByGroup[group]
+		if len(objects) == 0 {
+			continue
+		}
+		sb.WriteString(group + ":\n")
+		for _, obj := range objects {
+			symbol := "~"
+			switch {
+			case obj.created && obj.dropped:
+				symbol = "-/+"
+				toAdd++
+				toDestroy++
+			case obj.created:
+				symbol = "+"
+				toAdd++
+			case obj.dropped:
+				symbol = "-"
+				toDestroy++
+			default:
+				toChange++
+			}
+			sb.WriteString(fmt.Sprintf("%s%s %s\n", strings.Repeat(" ", 3-len(symbol)), colorRenderSymbol(symbol, color), obj.name))
+			for _, line := range obj.lines {
+				sb.WriteString(strings.Repeat(" ", line.indent) + renderLine(line, color) + "\n")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(advisoryStmts) > 0 {
+		sb.WriteString("Advisory statements (not executed):\n")
+		for _, stmt := range advisoryStmts {
+			sb.WriteString(fmt.Sprintf("  # %s\n", summarizeDDL(stmt.DDL)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(p.Hazards) > 0 {
+		sb.WriteString("Plan hazards:\n")
+		for i := range p.Hazards {
+			sb.WriteString("  " + renderLine(renderedLine{hazard: &p.Hazards[i]}, color) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if toAdd+toChange+toDestroy == 0 {
+		sb.WriteString("No changes. The schema matches the target schema.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.\n", toAdd, toChange, toDestroy))
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("writing rendered plan: %w", err)
+	}
+	return nil
+}
+
+// describeStatement returns the group and name of the object the statement operates on, the symbol of the change, and
+// a description of the change. The description is empty if the statement creates or drops the object itself.
+func (p Plan) describeStatement(stmt Statement) (group, name, symbol, detail string) {
+	ddl := strings.TrimSpace(stmt.DDL)
+	if match := mergeCreateIndexRegex.FindStringSubmatch(ddl); match != nil {
+		indexName, ok := qualifyIndexName(match[1], match[2])
+		if !ok {
+			indexName = match[1]
+		}
+		return getRenderGroup("INDEX"), normalizeQualifiedIdentifier(indexName), "+", ""
+	}
+	if match := mergeIndexRegex.FindStringSubmatch(ddl); match != nil {
+		if strings.HasPrefix(ddl, "DROP ") {
+			return getRenderGroup("INDEX"), normalizeQualifiedIdentifier(match[1]), "-", ""
+		}
+		return getRenderGroup("INDEX"), normalizeQualifiedIdentifier(match[1]), "~", summarizeDDL(ddl)
+	}
+	if match := rollbackAlterTableRegex.FindStringSubmatch(ddl); match != nil {
+		tableName := normalizeQualifiedIdentifier(match[1])
+		symbol, detail := p.describeAlterTable(tableName, match[2])
+		return getRenderGroup("TABLE"), tableName, symbol, detail
+	}
+	for _, regex := range []*regexp.Regexp{mergeTableSubObjRegex, mergeCommentColumnRegex} {
+		if match := regex.FindStringSubmatch(ddl); match != nil {
+			return getRenderGroup("TABLE"), normalizeQualifiedIdentifier(match[1]), getRenderSubObjSymbol(ddl), summarizeDDL(ddl)
+		}
+	}
+	if match := renderAnalyzeRegex.FindStringSubmatch(ddl); match != nil {
+		return getRenderGroup("TABLE"), normalizeQualifiedIdentifier(match[1]), "~", summarizeDDL(ddl)
+	}
+	if match := mergeObjectRegex.FindStringSubmatch(ddl); match != nil {
+		objType, objName := match[1], normalizeQualifiedIdentifier(match[2])
+		switch {
+		case strings.HasPrefix(ddl, "CREATE OR REPLACE "):
+			if p.renderState == nil || p.renderState.currentObjects[buildMergeObjectKey(objType, objName)] {
+				return getRenderGroup(objType), objName, "~", "definition changed"
+			}
+			return getRenderGroup(objType), objName, "+", ""
+		case strings.HasPrefix(ddl, "CREATE "):
+			return getRenderGroup(objType), objName, "+", ""
+		case strings.HasPrefix(ddl, "DROP "):
+			return getRenderGroup(objType), objName, "-", ""
+		default:
+			return getRenderGroup(objType), objName, "~", summarizeDDL(ddl)
+		}
+	}
+	return renderGroupOther, summarizeDDL(ddl), "~", ""
+}
+
+// describeAlterTable returns the symbol and description of an `ALTER TABLE` statement's action
+func (p Plan) describeAlterTable(tableName, action string) (string, string) {
+	if match := rollbackAddColumnRegex.FindStringSubmatch(action); match != nil {
+		colType := ""
+		if typeMatch := renderAddColumnTypeRegex.FindStringSubmatch(action); typeMatch != nil {
+			colType = " " + summarizeDDL(typeMatch[1])
+		}
+		return "+", fmt.Sprintf("column %s%s", normalizeQualifiedIdentifier(match[1]), colType)
+	}
+	if match := renderDropColumnRegex.FindStringSubmatch(action); match != nil {
+		return "-", fmt.Sprintf("column %s", normalizeQualifiedIdentifier(match[1]))
+	}
+	if match := renderSetColumnTypeRegex.FindStringSubmatch(action); match != nil {
+		colName := normalizeQualifiedIdentifier(match[1])
+		oldType := "(unknown)"
+		if p.renderState != nil {
+			if t, ok := p.renderState.oldColumnTypes[tableName+"."+colName]; ok {
+				oldType = t
+			}
+		}
+		return "~", fmt.Sprintf("column %s: %s -> %s", colName, oldType, match[2])
+	}
+	if match := renderAlterColumnRegex.FindStringSubmatch(action); match != nil {
+		return "~", fmt.Sprintf("column %s: %s", normalizeQualifiedIdentifier(match[1]), summarizeDDL(match[2]))
+	}
+	if match := rollbackAddConstraintRegex.FindStringSubmatch(action); match != nil {
+		return "+", fmt.Sprintf("constraint %s", normalizeQualifiedIdentifier(match[1]))
+	}
+	if match := rollbackDropConstraintRegex.FindStringSubmatch(action); match != nil {
+		return "-", fmt.Sprintf("constraint %s", normalizeQualifiedIdentifier(match[1]))
+	}
+	return "~", summarizeDDL(action)
+}
+
+func getRenderGroup(objType string) string {
+	for _, groupByObjType := range renderGroupsByObjType {
+		if groupByObjType.objType == objType {
+			return groupByObjType.group
+		}
+	}
+	return renderGroupOther
+}
+
+func getRenderSubObjSymbol(ddl string) string {
+	match := renderTableSubObjVerbRegex.FindStringSubmatch(ddl)
+	if match == nil {
+		return "~"
+	}
+	switch match[1] {
+	case "CREATE":
+		return "+"
+	case "DROP":
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func renderLine(line renderedLine, color bool) string {
+	if line.hazard == nil {
+		return fmt.Sprintf("%s %s", colorRenderSymbol(line.symbol, color), line.text)
+	}
+	text := fmt.Sprintf("! %s: %s", line.hazard.Type, strings.Join(strings.Fields(line.hazard.Message), " "))
+	if !color {
+		return text
+	}
+	if renderRedHazardTypes[line.hazard.Type] {
+		return renderColorRed + text + renderColorReset
+	}
+	return renderColorYellow + text + renderColorReset
+}
+
+func colorRenderSymbol(symbol string, color bool) string {
+	if !color {
+		return symbol
+	}
+	switch symbol {
+	case "+":
+		return renderColorGreen + symbol + renderColorReset
+	case "-":
+		return renderColorRed + symbol + renderColorReset
+	case "-/+":
+		return renderColorRed + "-" + renderColorReset + "/" + renderColorGreen + "+" + renderColorReset
+	default:
+		return renderColorYellow + symbol + renderColorReset
+	}
+}
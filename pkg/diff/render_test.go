@@ -0,0 +1,143 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestPlan_RenderHuman(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+	oldTable := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"old_table"`}
+	newTable := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"new_table"`}
+	oldSchema := schema.Schema{
+		Tables: []schema.Table{
+			{
+				SchemaQualifiedName: foobar,
+				Columns: []schema.Column{
+					{Name: "id", Type: "integer"},
+					{Name: "val", Type: "text", IsNullable: true},
+					{Name: "legacy", Type: "text", IsNullable: true},
+				},
+				ReplicaIdentity: schema.ReplicaIdentityDefault,
+			},
+			{
+				SchemaQualifiedName: oldTable,
+				Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+				ReplicaIdentity:     schema.ReplicaIdentityDefault,
+			},
+		},
+		Functions: []schema.Function{{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"add"(a integer, b integer)`},
+			FunctionDef:         "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT a + b $function$\n",
+			Language:            "sql",
+		}},
+	}
+	newSchema := schema.Schema{
+		Tables: []schema.Table{
+			{
+				SchemaQualifiedName: foobar,
+				Columns: []schema.Column{
+					{Name: "id", Type: "bigint"},
+					{Name: "val", Type: "text", IsNullable: true},
+					{Name: "created_at", Type: "timestamp with time zone", Default: "now()"},
+				},
+				ReplicaIdentity: schema.ReplicaIdentityDefault,
+			},
+			{
+				SchemaQualifiedName: newTable,
+				Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+				ReplicaIdentity:     schema.ReplicaIdentityDefault,
+			},
+		},
+		Indexes: []schema.Index{{
+			OwningTable:     foobar,
+			Name:            "foobar_val_idx",
+			Columns:         []string{"val"},
+			Method:          "btree",
+			GetIndexDefStmt: "CREATE INDEX foobar_val_idx ON public.foobar USING btree (val)",
+		}},
+		Views: []schema.View{{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_vals"`},
+			Definition:          " SELECT val FROM foobar",
+			DependsOnTables:     []schema.SchemaQualifiedName{foobar},
+		}},
+		Functions: []schema.Function{
+			{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"add"(a integer, b integer)`},
+				FunctionDef:         "CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT b + a $function$\n",
+				Language:            "sql",
+			},
+			{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"one"()`},
+				FunctionDef:         "CREATE OR REPLACE FUNCTION public.one()\n RETURNS integer\n LANGUAGE sql\nAS $function$ SELECT 1 $function$\n",
+				Language:            "sql",
+			},
+		},
+	}
+	plan, err := buildPlan(oldSchema, newSchema, &planOptions{})
+	require.NoError(t, err)
+
+	sb := strings.Builder{}
+	require.NoError(t, plan.RenderHuman(&sb, false))
+	expected, err := os.ReadFile(filepath.Join("testdata", "render_human.golden"))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), sb.String())
+
+	t.Run("Color", func(t *testing.T) {
+		sb := strings.Builder{}
+		require.NoError(t, plan.RenderHuman(&sb, true))
+		assert.Contains(t, sb.String(), "  \x1b[32m+\x1b[0m \"public\".\"new_table\"\n")
+		assert.Contains(t, sb.String(), "  \x1b[31m-\x1b[0m \"public\".\"old_table\"\n")
+		assert.Contains(t, sb.String(), "\x1b[31m! DELETES_DATA: ")
+		assert.Contains(t, sb.String(), "\x1b[33m! ACQUIRES_ACCESS_EXCLUSIVE_LOCK: ")
+		// Without color, the output only differs by the color codes
+		assert.Equal(t, string(expected), regexp.MustCompile("\x1b\\[[0-9]*m").ReplaceAllString(sb.String(), ""))
+	})
+
+	t.Run("Deserialized plan", func(t *testing.T) {
+		// The old types of altered columns are unknown without the current schema
+		sb := strings.Builder{}
+		require.NoError(t, Plan{Statements: plan.Statements}.RenderHuman(&sb, false))
+		assert.Contains(t, sb.String(), `~ column "id": (unknown) -> bigint`)
+	})
+
+	t.Run("Re-created objects", func(t *testing.T) {
+		sb := strings.Builder{}
+		require.NoError(t, Plan{Statements: []Statement{
+			{DDL: `DROP INDEX CONCURRENTLY "public"."foobar_val_idx"`},
+			{DDL: `CREATE INDEX CONCURRENTLY foobar_val_idx ON public.foobar USING btree (val, id)`},
+			{DDL: `GRANT SELECT ON "public"."foobar" TO "reader"`},
+		}}.RenderHuman(&sb, false))
+		assert.Equal(t, `Indexes:
+-/+ "public"."foobar_val_idx"
+
+Other:
+  ~ GRANT SELECT ON "public"."foobar" TO "reader"
+
+Plan: 1 to add, 1 to change, 1 to destroy.
+`, sb.String())
+	})
+
+	t.Run("Plan hazards", func(t *testing.T) {
+		sb := strings.Builder{}
+		require.NoError(t, Plan{Hazards: []MigrationHazard{{Type: MigrationHazardTypeColumnOrderChange, Message: "The order changed."}}}.RenderHuman(&sb, false))
+		assert.Equal(t, `Plan hazards:
+  ! COLUMN_ORDER_CHANGE: The order changed.
+
+No changes. The schema matches the target schema.
+`, sb.String())
+	})
+
+	t.Run("Empty plan", func(t *testing.T) {
+		sb := strings.Builder{}
+		require.NoError(t, Plan{}.RenderHuman(&sb, true))
+		assert.Equal(t, "No changes. The schema matches the target schema.\n", sb.String())
+	})
+}
@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	migrationHazardReindexConcurrently = MigrationHazard{
+		Type: MigrationHazardTypeImpactsDatabasePerformance,
+		Message: "This might affect database performance. " +
+			"Rebuilding an index concurrently requires a non-trivial amount of CPU and can take a while on large tables, " +
+			"but it does not lock out writes.",
+	}
+	migrationHazardReindex = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresShareLock,
+		Message: "Rebuilding an index without CONCURRENTLY locks out writes to the table until the index is rebuilt. " +
+			"This can take a while on large tables.",
+	}
+)
+
+// repairInvalidIndexes returns a copy of the old schema where the invalid indexes that can be repaired are marked as
+// valid, along with the `REINDEX INDEX CONCURRENTLY` statements that repair them. An invalid index can be repaired if
+// it is valid and otherwise unchanged in the new schema. Without a repair, these indexes are dropped and re-created.
+// If nonConcurrent is true, the indexes are rebuilt via `REINDEX INDEX`, which locks out writes to the table.
+//
+// Indexes on partitioned tables are not repaired, since they are made valid by attaching the index partitions.
+func repairInvalidIndexes(oldSchema, newSchema schema.Schema, nonConcurrent bool) (schema.Schema, []Statement) {
+	oldTablesByName := buildSchemaObjByNameMap(oldSchema.Tables)
+	newIndexesByName := buildSchemaObjByNameMap(newSchema.Indexes)
+
+	var stmts []Statement
+	var repairedIndexes []schema.Index
+	for _, index := range oldSchema.Indexes {
+		if !index.IsInvalid {
+			repairedIndexes = append(repairedIndexes, index)
+			continue
+		}
+		if table, ok := oldTablesByName[index.OwningTable.GetName()]; !ok || table.IsPartitioned() {
+			repairedIndexes = append(repairedIndexes, index)
+			continue
+		}
+
+		repairedIndex := index.DeepCopy()
+		repairedIndex.IsInvalid = false
+		if newIndex, ok := newIndexesByName[index.GetName()]; !ok || !cmp.Equal(repairedIndex, newIndex) {
+			// The index will be dropped or re-created, so there's no point in rebuilding it
+			repairedIndexes = append(repairedIndexes, index)
+			continue
+		}
+
+		repairedIndexes = append(repairedIndexes, repairedIndex)
+		if nonConcurrent {
+			stmts = append(stmts, Statement{
+				DDL:         fmt.Sprintf("REINDEX INDEX %s", index.GetName()),
+				Timeout:     statementTimeoutConcurrentIndexBuild,
+				LockTimeout: lockTimeoutDefault,
+				Hazards:     []MigrationHazard{migrationHazardReindex},
+			})
+			continue
+		}
+		stmts = append(stmts, Statement{
+			DDL:                   fmt.Sprintf("REINDEX INDEX CONCURRENTLY %s", index.GetName()),
+			Timeout:               statementTimeoutConcurrentIndexBuild,
+			LockTimeout:           lockTimeoutDefault,
+			Hazards:               []MigrationHazard{migrationHazardReindexConcurrently},
+			RequiresNoTransaction: true,
+		})
+	}
+
+	oldSchema.Indexes = repairedIndexes
+	return oldSchema, stmts
+}
@@ -0,0 +1,114 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestRepairInvalidIndexes(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	buildSchema := func(table schema.Table, indexes ...schema.Index) schema.Schema {
+		return schema.Schema{Tables: []schema.Table{table}, Indexes: indexes}
+	}
+	table := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             []schema.Column{{Name: "foo", Type: "text"}, {Name: "bar", Type: "text"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	index := schema.Index{
+		OwningTable:     foobar,
+		Name:            "some_idx",
+		Columns:         []string{"foo"},
+		GetIndexDefStmt: "CREATE INDEX some_idx ON public.foobar USING btree (foo)",
+	}
+	invalidIndex := index
+	invalidIndex.IsInvalid = true
+	partitionedTable := table
+	partitionedTable.PartitionKeyDef = "LIST (foo)"
+
+	for _, tc := range []struct {
+		name               string
+		oldSchema          schema.Schema
+		newSchema          schema.Schema
+		expectedOldSchema  schema.Schema
+		expectedStatements []Statement
+	}{
+		{
+			name:              "Invalid index is rebuilt",
+			oldSchema:         buildSchema(table, invalidIndex),
+			newSchema:         buildSchema(table, index),
+			expectedOldSchema: buildSchema(table, index),
+			expectedStatements: []Statement{
+				{
+					DDL:                   "REINDEX INDEX CONCURRENTLY \"public\".\"some_idx\"",
+					Timeout:               statementTimeoutConcurrentIndexBuild,
+					LockTimeout:           lockTimeoutDefault,
+					Hazards:               []MigrationHazard{migrationHazardReindexConcurrently},
+					RequiresNoTransaction: true,
+				},
+			},
+		},
+		{
+			name:      "Invalid index that is changed is not rebuilt",
+			oldSchema: buildSchema(table, invalidIndex),
+			newSchema: buildSchema(table, schema.Index{
+				OwningTable:     foobar,
+				Name:            "some_idx",
+				Columns:         []string{"bar"},
+				GetIndexDefStmt: "CREATE INDEX some_idx ON public.foobar USING btree (bar)",
+			}),
+			expectedOldSchema: buildSchema(table, invalidIndex),
+		},
+		{
+			name:              "Invalid index that is dropped is not rebuilt",
+			oldSchema:         buildSchema(table, invalidIndex),
+			newSchema:         buildSchema(table),
+			expectedOldSchema: buildSchema(table, invalidIndex),
+		},
+		{
+			name:              "Invalid index on partitioned table is not rebuilt",
+			oldSchema:         buildSchema(partitionedTable, invalidIndex),
+			newSchema:         buildSchema(partitionedTable, index),
+			expectedOldSchema: buildSchema(partitionedTable, invalidIndex),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repairedSchema, stmts := repairInvalidIndexes(tc.oldSchema, tc.newSchema, false)
+			assert.Equal(t, tc.expectedOldSchema, repairedSchema)
+			assert.Equal(t, tc.expectedStatements, stmts)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_RepairInvalidIndexes(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	table := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             []schema.Column{{Name: "foo", Type: "text"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	index := schema.Index{
+		OwningTable:     foobar,
+		Name:            "some_idx",
+		Columns:         []string{"foo"},
+		GetIndexDefStmt: "CREATE INDEX some_idx ON public.foobar USING btree (foo)",
+	}
+	invalidIndex := index
+	invalidIndex.IsInvalid = true
+
+	oldSchema := schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{invalidIndex}}
+	newSchema := schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{index}}
+
+	stmts, err := generateMigrationStatements(oldSchema, newSchema, &planOptions{repairInvalidIndexes: true})
+	require.NoError(t, err)
+	var ddl []string
+	for _, stmt := range stmts {
+		ddl = append(ddl, stmt.DDL)
+	}
+	assert.Equal(t, []string{"REINDEX INDEX CONCURRENTLY \"public\".\"some_idx\""}, ddl)
+	// The original schema should not be modified
+	assert.True(t, oldSchema.Indexes[0].IsInvalid)
+}
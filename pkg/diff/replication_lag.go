@@ -0,0 +1,122 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	// replicationLagPollIntervalDefault is how often the Executor checks the replication lag while waiting for it to
+	// drop below the maximum
+	replicationLagPollIntervalDefault = 5 * time.Second
+)
+
+// ReplicationLagChecker reports how far the replicas of the database lag behind it. See WithReplicationLagCheck.
+type ReplicationLagChecker interface {
+	// GetLagSeconds returns the replication lag of the most lagging replica in seconds
+	GetLagSeconds(ctx context.Context) (float64, error)
+}
+
+// ReplicationLagError is returned by the Executor if the replication lag exceeds the maximum before a long-running
+// statement
+type ReplicationLagError struct {
+	LagSeconds    float64
+	MaxLagSeconds float64
+}
+
+func (e ReplicationLagError) Error() string {
+	return fmt.Sprintf("replication lag of %.1fs exceeds the maximum of %.1fs. Wait for the replicas to catch up "+
+		"before running long-running statements", e.LagSeconds, e.MaxLagSeconds)
+}
+
+// WithReplicationLagCheck configures the Executor to check the replication lag around long-running statements, i.e.,
+// statements with a MigrationHazardTypeLongRunning hazard, which can cause replicas to fall behind. Before each of them,
+// the Executor aborts with a ReplicationLagError if the lag exceeds maxLagSeconds. After each of them is committed, the
+// Executor waits until the lag drops below maxLagSeconds before proceeding.
+func WithReplicationLagCheck(checker ReplicationLagChecker, maxLagSeconds float64) ExecutorOpt {
+	return func(e *Executor) {
+		e.replicationLagChecker = checker
+		e.maxReplicationLagSeconds = maxLagSeconds
+	}
+}
+
+// PostgresReplicationLagChecker gets the replication lag of the streaming replicas of the primary from
+// pg_stat_replication. Replicas that have caught up report no lag.
+type PostgresReplicationLagChecker struct {
+	db *sql.DB
+}
+
+func NewPostgresReplicationLagChecker(db *sql.DB) *PostgresReplicationLagChecker {
+	return &PostgresReplicationLagChecker{db: db}
+}
+
+func (c *PostgresReplicationLagChecker) GetLagSeconds(ctx context.Context) (float64, error) {
+	var lagSeconds float64
+	if err := c.db.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(EXTRACT(EPOCH FROM replay_lag)), 0)::FLOAT8 FROM pg_catalog.pg_stat_replication",
+	).Scan(&lagSeconds); err != nil {
+		return 0, fmt.Errorf("querying pg_stat_replication: %w", err)
+	}
+	return lagSeconds, nil
+}
+
+// checkReplicationLag returns a ReplicationLagError if the statement is long-running and the replication lag exceeds
+// the maximum
+func (e *Executor) checkReplicationLag(ctx context.Context, stmt Statement) error {
+	if e.replicationLagChecker == nil || !isLongRunning(stmt) {
+		return nil
+	}
+	lagSeconds, err := e.replicationLagChecker.GetLagSeconds(ctx)
+	if err != nil {
+		return fmt.Errorf("getting replication lag: %w", err)
+	}
+	if lagSeconds > e.maxReplicationLagSeconds {
+		return ReplicationLagError{LagSeconds: lagSeconds, MaxLagSeconds: e.maxReplicationLagSeconds}
+	}
+	return nil
+}
+
+// waitForReplicationLag waits until the replication lag drops below the maximum if any of the committed statements is
+// long-running
+func (e *Executor) waitForReplicationLag(ctx context.Context, plan Plan, committedStmtIdxs []int) error {
+	if e.replicationLagChecker == nil {
+		return nil
+	}
+	hasLongRunningStmt := false
+	for _, idx := range committedStmtIdxs {
+		if !plan.Statements[idx].IsAdvisory && isLongRunning(plan.Statements[idx]) {
+			hasLongRunningStmt = true
+		}
+	}
+	if !hasLongRunningStmt {
+		return nil
+	}
+
+	for {
+		lagSeconds, err := e.replicationLagChecker.GetLagSeconds(ctx)
+		if err != nil {
+			return fmt.Errorf("getting replication lag: %w", err)
+		}
+		if lagSeconds <= e.maxReplicationLagSeconds {
+			return nil
+		}
+		e.logger.Warnf("Replication lag of %.1fs exceeds the maximum of %.1fs. Waiting %s for the replicas to catch up",
+			lagSeconds, e.maxReplicationLagSeconds, e.replicationLagPollInterval)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for replication lag to drop below %.1fs: %w", e.maxReplicationLagSeconds, ctx.Err())
+		case <-time.After(e.replicationLagPollInterval):
+		}
+	}
+}
+
+func isLongRunning(stmt Statement) bool {
+	for _, hazard := range stmt.Hazards {
+		if hazard.Type == MigrationHazardTypeLongRunning {
+			return true
+		}
+	}
+	return false
+}
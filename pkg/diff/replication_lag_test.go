@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReplicationLagChecker returns the lags in order, repeating the last one
+type fakeReplicationLagChecker struct {
+	lagsSeconds []float64
+	calls       int
+}
+
+func (c *fakeReplicationLagChecker) GetLagSeconds(_ context.Context) (float64, error) {
+	idx := c.calls
+	if idx >= len(c.lagsSeconds) {
+		idx = len(c.lagsSeconds) - 1
+	}
+	c.calls++
+	return c.lagsSeconds[idx], nil
+}
+
+var longRunningStatement = Statement{
+	DDL:     `ALTER TABLE "public"."foobar" VALIDATE CONSTRAINT "foobar_val_check"`,
+	Hazards: []MigrationHazard{{Type: MigrationHazardTypeLongRunning, Message: "some message"}},
+}
+
+func TestCheckReplicationLag(t *testing.T) {
+	checker := &fakeReplicationLagChecker{lagsSeconds: []float64{12.5}}
+	executor := NewExecutor(WithReplicationLagCheck(checker, 10))
+
+	err := executor.checkReplicationLag(context.Background(), longRunningStatement)
+	var lagErr ReplicationLagError
+	require.ErrorAs(t, err, &lagErr)
+	assert.Equal(t, ReplicationLagError{LagSeconds: 12.5, MaxLagSeconds: 10}, lagErr)
+	assert.EqualError(t, err, "replication lag of 12.5s exceeds the maximum of 10.0s. Wait for the replicas to catch up before running long-running statements")
+
+	// Statements that are not long-running are not checked
+	assert.NoError(t, executor.checkReplicationLag(context.Background(), Statement{DDL: `ALTER TABLE "public"."foobar" ADD COLUMN "val" text`}))
+	assert.Equal(t, 1, checker.calls)
+
+	checker.lagsSeconds = []float64{3}
+	assert.NoError(t, executor.checkReplicationLag(context.Background(), longRunningStatement))
+}
+
+func TestWaitForReplicationLag(t *testing.T) {
+	plan := Plan{Statements: []Statement{{DDL: `ALTER TABLE "public"."foobar" ADD COLUMN "val" text`}, longRunningStatement}}
+
+	t.Run("Waits until the lag drops below the maximum", func(t *testing.T) {
+		checker := &fakeReplicationLagChecker{lagsSeconds: []float64{30, 15, 5}}
+		logger := &recordingLogger{}
+		executor := NewExecutor(WithReplicationLagCheck(checker, 10), WithExecutorLogger(logger))
+		executor.replicationLagPollInterval = time.Millisecond
+
+		require.NoError(t, executor.waitForReplicationLag(context.Background(), plan, []int{0, 1}))
+		assert.Equal(t, 3, checker.calls)
+		assert.Equal(t, []string{
+			"Replication lag of 30.0s exceeds the maximum of 10.0s. Waiting 1ms for the replicas to catch up",
+			"Replication lag of 15.0s exceeds the maximum of 10.0s. Waiting 1ms for the replicas to catch up",
+		}, logger.warnings)
+	})
+
+	t.Run("Does not wait after statements that are not long-running", func(t *testing.T) {
+		checker := &fakeReplicationLagChecker{lagsSeconds: []float64{30}}
+		executor := NewExecutor(WithReplicationLagCheck(checker, 10))
+		require.NoError(t, executor.waitForReplicationLag(context.Background(), plan, []int{0}))
+		assert.Zero(t, checker.calls)
+	})
+
+	t.Run("Stops waiting when the context is canceled", func(t *testing.T) {
+		checker := &fakeReplicationLagChecker{lagsSeconds: []float64{30}}
+		executor := NewExecutor(WithReplicationLagCheck(checker, 10))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := executor.waitForReplicationLag(ctx, plan, []int{1})
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
+
+func (suite *planGeneratorTestSuite) TestExecutor_ReplicationLagCheck() {
+	suite.mustApplyDDLToTestDb([]string{`CREATE TABLE foobar(id INT PRIMARY KEY, val INT);`})
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: "ALTER TABLE foobar ADD CONSTRAINT foobar_val_check CHECK (val > 0) NOT VALID", Timeout: 3 * time.Second},
+			{
+				DDL:     "ALTER TABLE foobar VALIDATE CONSTRAINT foobar_val_check",
+				Timeout: 3 * time.Second,
+				Hazards: []MigrationHazard{{Type: MigrationHazardTypeLongRunning, Message: "some message"}},
+			},
+		},
+	}
+
+	// The lag exceeds the maximum before the long-running statement, so the plan is aborted
+	err := NewExecutor(WithReplicationLagCheck(&fakeReplicationLagChecker{lagsSeconds: []float64{60}}, 10)).RunInTransaction(context.Background(), plan, connPool)
+	var execErr ExecutionError
+	suite.Require().ErrorAs(err, &execErr)
+	suite.Equal(1, execErr.StatementIndex)
+	suite.ErrorAs(err, &ReplicationLagError{})
+
+	// The lag is under the maximum before the statement, and the executor waits for it to drop under the maximum after
+	checker := &fakeReplicationLagChecker{lagsSeconds: []float64{5, 20, 5}}
+	executor := NewExecutor(WithReplicationLagCheck(checker, 10))
+	executor.replicationLagPollInterval = time.Millisecond
+	suite.Require().NoError(executor.RunInTransaction(context.Background(), plan, connPool))
+	suite.Equal(3, checker.calls)
+
+	lagSeconds, err := NewPostgresReplicationLagChecker(connPool).GetLagSeconds(context.Background())
+	suite.Require().NoError(err)
+	suite.Zero(lagSeconds)
+}
@@ -0,0 +1,429 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+const (
+	// identifierPattern matches a (possibly quoted) identifier
+	identifierPattern = `(?:"(?:[^"]|"")+"|[a-zA-Z_][a-zA-Z0-9_$]*)`
+	// qualifiedIdentifierPattern matches a (possibly schema-qualified) identifier
+	qualifiedIdentifierPattern = identifierPattern + `(?:\.` + identifierPattern + `)?`
+	// ifNotExistsPattern matches the optional guard of idempotent statements (see WithIdempotentSQL), which must not
+	// be captured as the name of the object, since identifierPattern also matches keywords, e.g., IF
+	ifNotExistsPattern = `(?:IF NOT EXISTS )?`
+)
+
+var (
+	rollbackCreateTableRegex   = regexp.MustCompile(`^CREATE TABLE ` + ifNotExistsPattern + `(` + qualifiedIdentifierPattern + `) \(`)
+	rollbackCreateIndexRegex   = regexp.MustCompile(`^CREATE (?:UNIQUE )?INDEX (CONCURRENTLY )?` + ifNotExistsPattern + `(` + identifierPattern + `) ON (ONLY )?(` + qualifiedIdentifierPattern + `) `)
+	rollbackAlterTableRegex    = regexp.MustCompile(`^ALTER TABLE (?:ONLY )?(` + qualifiedIdentifierPattern + `) (.*)$`)
+	rollbackAlterIndexRegex    = regexp.MustCompile(`^ALTER INDEX (` + qualifiedIdentifierPattern + `) (.*)$`)
+	rollbackCreatePolicyRegex  = regexp.MustCompile(`^CREATE POLICY (` + identifierPattern + `) ON (` + qualifiedIdentifierPattern + `)`)
+	rollbackCreateTriggerRegex = regexp.MustCompile(`(?s)^CREATE (OR REPLACE )?TRIGGER (` + identifierPattern + `) .*? ON (` + qualifiedIdentifierPattern + `) `)
+
+	rollbackAddColumnRegex          = regexp.MustCompile(`^ADD COLUMN ` + ifNotExistsPattern + `(` + identifierPattern + `) `)
+	rollbackAddConstraintRegex      = regexp.MustCompile(`^ADD CONSTRAINT (` + identifierPattern + `) `)
+	rollbackDropConstraintRegex     = regexp.MustCompile(`^DROP CONSTRAINT (?:IF EXISTS )?(` + identifierPattern + `)$`)
+	rollbackValidateConstraintRegex = regexp.MustCompile(`^VALIDATE CONSTRAINT ` + identifierPattern + `$`)
+	rollbackAlterColumnNullRegex    = regexp.MustCompile(`^ALTER COLUMN (` + identifierPattern + `) (SET|DROP) NOT NULL$`)
+	rollbackRenameIndexRegex        = regexp.MustCompile(`^RENAME TO (` + identifierPattern + `)$`)
+	rollbackRenameSchemaRegex       = regexp.MustCompile(`^ALTER SCHEMA (` + identifierPattern + `) RENAME TO (` + identifierPattern + `)$`)
+	rollbackAttachPartitionRegex    = regexp.MustCompile(`^ATTACH PARTITION (` + qualifiedIdentifierPattern + `) `)
+
+	// rollbackCreateObjectRegexes maps the regexes of the statements that create an object to the keyword used to drop
+	// the object, e.g., CREATE SCHEMA "foo" is rolled back with DROP SCHEMA "foo"
+	rollbackCreateObjectRegexes = []struct {
+		regex       *regexp.Regexp
+		dropKeyword string
+	}{
+		{regex: buildRollbackCreateObjectRegex("SCHEMA", identifierPattern), dropKeyword: "SCHEMA"},
+		{regex: buildRollbackCreateObjectRegex("EXTENSION", identifierPattern), dropKeyword: "EXTENSION"},
+		{regex: buildRollbackCreateObjectRegex("COLLATION", qualifiedIdentifierPattern), dropKeyword: "COLLATION"},
+		{regex: buildRollbackCreateObjectRegex("SEQUENCE", qualifiedIdentifierPattern), dropKeyword: "SEQUENCE"},
+		{regex: buildRollbackCreateObjectRegex("TYPE", qualifiedIdentifierPattern), dropKeyword: "TYPE"},
+		{regex: buildRollbackCreateObjectRegex("DOMAIN", qualifiedIdentifierPattern), dropKeyword: "DOMAIN"},
+		{regex: buildRollbackCreateObjectRegex("VIEW", qualifiedIdentifierPattern), dropKeyword: "VIEW"},
+		{regex: buildRollbackCreateObjectRegex("MATERIALIZED VIEW", qualifiedIdentifierPattern), dropKeyword: "MATERIALIZED VIEW"},
+		{regex: buildRollbackCreateObjectRegex("STATISTICS", qualifiedIdentifierPattern), dropKeyword: "STATISTICS"},
+		{regex: buildRollbackCreateObjectRegex("FOREIGN DATA WRAPPER", identifierPattern), dropKeyword: "FOREIGN DATA WRAPPER"},
+		{regex: buildRollbackCreateObjectRegex("SERVER", identifierPattern), dropKeyword: "SERVER"},
+		{regex: buildRollbackCreateObjectRegex("FOREIGN TABLE", qualifiedIdentifierPattern), dropKeyword: "FOREIGN TABLE"},
+		{regex: buildRollbackCreateObjectRegex("PUBLICATION", identifierPattern), dropKeyword: "PUBLICATION"},
+		{regex: buildRollbackCreateObjectRegex("EVENT TRIGGER", identifierPattern), dropKeyword: "EVENT TRIGGER"},
+		{regex: buildRollbackCreateObjectRegex("TEXT SEARCH DICTIONARY", qualifiedIdentifierPattern), dropKeyword: "TEXT SEARCH DICTIONARY"},
+		{regex: buildRollbackCreateObjectRegex("TEXT SEARCH CONFIGURATION", qualifiedIdentifierPattern), dropKeyword: "TEXT SEARCH CONFIGURATION"},
+	}
+)
+
+// buildRollbackCreateObjectRegex builds the regex that matches the statement that creates an object of the given type
+// and captures the object's name
+func buildRollbackCreateObjectRegex(objectType, namePattern string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`^CREATE %s %s(%s)(?:\s|$)`, objectType, ifNotExistsPattern, namePattern))
+}
+
+// GenerateRollback generates a plan that reverses the plan, i.e., migrates the database from schema B back to schema A.
+// The statements of the plan are reversed in reverse order. The rollback is derived from the DDL of the statements, so
+// only the statements that create objects, add columns and constraints, and toggle settings can be reversed. A
+// statement that cannot be reversed, e.g., a DROP COLUMN, is represented in the rollback plan by an advisory statement
+// with a MigrationHazardTypeImpossibleToRollback hazard rather than being omitted.
+//
+// Plans generated with WithIdempotentSQL can be reversed as well: the guards and DO blocks that make the statements
+// idempotent are ignored.
+//
+// The CurrentSchemaHash of the rollback plan is empty, since the hash of schema B is not known until the plan is
+// applied.
+func (p Plan) GenerateRollback() (Plan, error) {
+	rg := newRollbackGenerator(p.Statements)
+	var stmts []Statement
+	for i := len(p.Statements) - 1; i >= 0; i-- {
+		stmt := p.Statements[i]
+		if stmt.IsAdvisory {
+			// Advisory statements are not executed, so there is nothing to reverse
+			continue
+		}
+		rollbackStmts, err := rg.rollbackStatement(stmt)
+		if err != nil {
+			return Plan{}, fmt.Errorf("generating rollback of %q: %w", stmt.DDL, err)
+		}
+		stmts = append(stmts, rollbackStmts...)
+	}
+	return Plan{Statements: stmts}, nil
+}
+
+type rollbackGenerator struct {
+	// createdRelationsByName contains the tables and indexes created by the forward plan, keyed by their normalized
+	// name. The rollback drops them, which also reverses any other statement that alters them.
+	createdRelationsByName map[string]bool
+	// transientConstraintsByKey contains the constraints that are added and then dropped by the forward plan, e.g., the
+	// temporary check constraints used to make columns NOT NULL. Neither statement needs to be reversed.
+	transientConstraintsByKey map[string]bool
+}
+
+func newRollbackGenerator(forwardStmts []Statement) *rollbackGenerator {
+	createdRelationsByName := make(map[string]bool)
+	addedConstraintsByKey := make(map[string]bool)
+	transientConstraintsByKey := make(map[string]bool)
+	for _, stmt := range forwardStmts {
+		stmt.DDL = unwrapIdempotentSQL(stmt.DDL)
+		if match := rollbackCreateTableRegex.FindStringSubmatch(stmt.DDL); match != nil {
+			createdRelationsByName[normalizeQualifiedIdentifier(match[1])] = true
+		} else if match := rollbackCreateIndexRegex.FindStringSubmatch(stmt.DDL); match != nil {
+			if indexName, ok := qualifyIndexName(match[2], match[4]); ok {
+				createdRelationsByName[normalizeQualifiedIdentifier(indexName)] = true
+			}
+		} else if match := rollbackAlterTableRegex.FindStringSubmatch(stmt.DDL); match != nil {
+			if addMatch := rollbackAddConstraintRegex.FindStringSubmatch(match[2]); addMatch != nil {
+				addedConstraintsByKey[buildConstraintKey(match[1], addMatch[1])] = true
+			} else if dropMatch := rollbackDropConstraintRegex.FindStringSubmatch(match[2]); dropMatch != nil {
+				if key := buildConstraintKey(match[1], dropMatch[1]); addedConstraintsByKey[key] {
+					transientConstraintsByKey[key] = true
+				}
+			}
+		}
+	}
+	return &rollbackGenerator{
+		createdRelationsByName:    createdRelationsByName,
+		transientConstraintsByKey: transientConstraintsByKey,
+	}
+}
+
+// buildConstraintKey builds the key that identifies a constraint on a table
+func buildConstraintKey(qualifiedTableName, constraintName string) string {
+	return fmt.Sprintf("%s.%s", normalizeQualifiedIdentifier(qualifiedTableName), normalizeQualifiedIdentifier(constraintName))
+}
+
+// rollbackStatement builds the statements that reverse the statement. If the statement cannot be reversed, it returns
+// an advisory statement flagged with a MigrationHazardTypeImpossibleToRollback hazard.
+func (rg *rollbackGenerator) rollbackStatement(stmt Statement) ([]Statement, error) {
+	rollbackStmts, ok, err := rg.buildRollbackStatements(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return rollbackStmts, nil
+	}
+	return []Statement{buildImpossibleToRollbackStatement(stmt)}, nil
+}
+
+// buildRollbackStatements builds the statements that reverse the statement. It returns false if the statement cannot
+// be reversed.
+func (rg *rollbackGenerator) buildRollbackStatements(stmt Statement) ([]Statement, bool, error) {
+	stmt.DDL = unwrapIdempotentSQL(stmt.DDL)
+	if match := rollbackCreateTableRegex.FindStringSubmatch(stmt.DDL); match != nil {
+		return []Statement{{
+			DDL:         fmt.Sprintf("DROP TABLE %s", match[1]),
+			Timeout:     statementTimeoutTableDrop,
+			LockTimeout: lockTimeoutDefault,
+			Hazards: []MigrationHazard{{
+				Type:    MigrationHazardTypeDeletesData,
+				Message: "Deletes all rows in the table (and the table itself)",
+			}},
+		}}, true, nil
+	}
+
+	if match := rollbackCreateIndexRegex.FindStringSubmatch(stmt.DDL); match != nil {
+		if rg.isCreatedRelation(match[4]) {
+			// The index is dropped when its table is dropped
+			return nil, true, nil
+		}
+		indexName, ok := qualifyIndexName(match[2], match[4])
+		if !ok {
+			return nil, false, nil
+		}
+		if len(match[1]) > 0 {
+			return []Statement{{
+				// The index might have been used to add a constraint, in which case it is dropped with the constraint
+				DDL:                   fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s", indexName),
+				Timeout:               statementTimeoutConcurrentIndexDrop,
+				LockTimeout:           lockTimeoutDefault,
+				Hazards:               []MigrationHazard{migrationHazardIndexDroppedQueryPerf},
+				RequiresNoTransaction: true,
+			}}, true, nil
+		}
+		return []Statement{{
+			DDL:         fmt.Sprintf("DROP INDEX IF EXISTS %s", indexName),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardIndexDroppedAcquiresLock, migrationHazardIndexDroppedQueryPerf},
+		}}, true, nil
+	}
+
+	if match := rollbackCreatePolicyRegex.FindStringSubmatch(stmt.DDL); match != nil {
+		if rg.isCreatedRelation(match[2]) {
+			// The policy is dropped when its table is dropped
+			return nil, true, nil
+		}
+		return []Statement{{
+			DDL:         fmt.Sprintf("DROP POLICY %s ON %s", match[1], match[2]),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		}}, true, nil
+	}
+
+	if match := rollbackCreateTriggerRegex.FindStringSubmatch(stmt.DDL); match != nil {
+		if rg.isCreatedRelation(match[3]) {
+			// The trigger is dropped when its table is dropped
+			return nil, true, nil
+		}
+		if len(match[1]) > 0 {
+			// The statement might have replaced an existing trigger, whose definition is not known
+			return nil, false, nil
+		}
+		return []Statement{{
+			DDL:         fmt.Sprintf("DROP TRIGGER %s ON %s", match[2], match[3]),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		}}, true, nil
+	}
+
+	if match := rollbackAlterTableRegex.FindStringSubmatch(stmt.DDL); match != nil {
+		return rg.buildAlterTableRollbackStatements(match[1], match[2])
+	}
+
+	if match := rollbackAlterIndexRegex.FindStringSubmatch(stmt.DDL); match != nil {
+		if rg.isCreatedRelation(match[1]) {
+			// The index is dropped, which reverses any alteration to it
+			return nil, true, nil
+		}
+		if renameMatch := rollbackRenameIndexRegex.FindStringSubmatch(match[2]); renameMatch != nil {
+			oldName, err := parseQualifiedIdentifier(match[1])
+			if err != nil {
+				return nil, false, err
+			}
+			newName := schema.SchemaQualifiedName{SchemaName: oldName.SchemaName, EscapedName: renameMatch[1]}
+			return []Statement{{
+				DDL:         fmt.Sprintf("ALTER INDEX %s RENAME TO %s", newName.GetFQEscapedName(), oldName.EscapedName),
+				Timeout:     statementTimeoutDefault,
+				LockTimeout: lockTimeoutDefault,
+			}}, true, nil
+		}
+		return nil, false, nil
+	}
+
+	if match := rollbackRenameSchemaRegex.FindStringSubmatch(stmt.DDL); match != nil {
+		return []Statement{{
+			DDL:         fmt.Sprintf("ALTER SCHEMA %s RENAME TO %s", match[2], match[1]),
+			Timeout:     stmt.Timeout,
+			LockTimeout: stmt.LockTimeout,
+			Hazards:     stmt.Hazards,
+		}}, true, nil
+	}
+
+	for _, createObject := range rollbackCreateObjectRegexes {
+		if match := createObject.regex.FindStringSubmatch(stmt.DDL); match != nil {
+			return []Statement{{
+				DDL:         fmt.Sprintf("DROP %s %s", createObject.dropKeyword, match[1]),
+				Timeout:     statementTimeoutDefault,
+				LockTimeout: lockTimeoutDefault,
+			}}, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// buildAlterTableRollbackStatements builds the statements that reverse an ALTER TABLE statement. The action is the
+// remainder of the statement after the table name, e.g., ADD COLUMN "foo" integer
+func (rg *rollbackGenerator) buildAlterTableRollbackStatements(tableName, action string) ([]Statement, bool, error) {
+	if rg.isCreatedRelation(tableName) {
+		// The table is dropped, which reverses any alteration to it
+		return nil, true, nil
+	}
+	if match := rollbackAttachPartitionRegex.FindStringSubmatch(action); match != nil && rg.isCreatedRelation(match[1]) {
+		// The partition is detached when it is dropped
+		return nil, true, nil
+	}
+
+	table, err := parseQualifiedIdentifier(tableName)
+	if err != nil {
+		return nil, false, err
+	}
+	prefix := alterTablePrefix(table)
+
+	if match := rollbackAddColumnRegex.FindStringSubmatch(action); match != nil {
+		return []Statement{{
+			DDL:         fmt.Sprintf("%s DROP COLUMN %s", prefix, match[1]),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards: []MigrationHazard{{
+				Type:    MigrationHazardTypeDeletesData,
+				Message: "Deletes all values in the column",
+			}},
+		}}, true, nil
+	}
+	if match := rollbackAddConstraintRegex.FindStringSubmatch(action); match != nil {
+		if rg.transientConstraintsByKey[buildConstraintKey(tableName, match[1])] {
+			return nil, true, nil
+		}
+		return []Statement{{
+			DDL:         dropConstraintDDL(table, match[1]),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		}}, true, nil
+	}
+	if match := rollbackDropConstraintRegex.FindStringSubmatch(action); match != nil && rg.transientConstraintsByKey[buildConstraintKey(tableName, match[1])] {
+		return nil, true, nil
+	}
+	if rollbackValidateConstraintRegex.MatchString(action) {
+		// The constraint is dropped when the statement that added it is reversed
+		return nil, true, nil
+	}
+	if match := rollbackAlterColumnNullRegex.FindStringSubmatch(action); match != nil {
+		if match[2] == "SET" {
+			return []Statement{{
+				DDL:         fmt.Sprintf("%s ALTER COLUMN %s DROP NOT NULL", prefix, match[1]),
+				Timeout:     statementTimeoutDefault,
+				LockTimeout: lockTimeoutDefault,
+			}}, true, nil
+		}
+		return []Statement{{
+			DDL:         fmt.Sprintf("%s ALTER COLUMN %s SET NOT NULL", prefix, match[1]),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards: []MigrationHazard{{
+				Type:    MigrationHazardTypeAcquiresAccessExclusiveLock,
+				Message: "Marking a column as not null requires a full table scan, which will lock out writes",
+			}},
+		}}, true, nil
+	}
+
+	switch action {
+	case "ENABLE ROW LEVEL SECURITY":
+		return []Statement{disableRLSForTable(schema.Table{SchemaQualifiedName: table})}, true, nil
+	case "DISABLE ROW LEVEL SECURITY":
+		return []Statement{enableRLSForTable(schema.Table{SchemaQualifiedName: table})}, true, nil
+	case "FORCE ROW LEVEL SECURITY":
+		return []Statement{unforceRLSForTable(schema.Table{SchemaQualifiedName: table})}, true, nil
+	case "NO FORCE ROW LEVEL SECURITY":
+		return []Statement{forceRLSForTable(schema.Table{SchemaQualifiedName: table})}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+func (rg *rollbackGenerator) isCreatedRelation(qualifiedName string) bool {
+	return rg.createdRelationsByName[normalizeQualifiedIdentifier(qualifiedName)]
+}
+
+// buildImpossibleToRollbackStatement builds the advisory statement that represents a statement that cannot be
+// reversed in a rollback plan. The DDL is the forward statement commented out, so executing it is a no-op.
+func buildImpossibleToRollbackStatement(stmt Statement) Statement {
+	var commentedLines []string
+	for _, line := range strings.Split(stmt.DDL, "\n") {
+		commentedLines = append(commentedLines, "-- "+line)
+	}
+	return Statement{
+		DDL:         strings.Join(commentedLines, "\n"),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards: []MigrationHazard{{
+			Type: MigrationHazardTypeImpossibleToRollback,
+			Message: "This statement of the forward plan cannot be automatically reversed, e.g., because it deletes " +
+				"data or the previous definition of the object is unknown. It must be reversed manually.",
+		}},
+		IsAdvisory: true,
+	}
+}
+
+// qualifyIndexName qualifies the index name with the schema of its table, since indexes are always in the same schema
+// as their table
+func qualifyIndexName(indexName, qualifiedTableName string) (string, bool) {
+	parts := splitQualifiedIdentifier(qualifiedTableName)
+	if len(parts) != 2 {
+		return "", false
+	}
+	return fmt.Sprintf("%s.%s", parts[0], indexName), true
+}
+
+// parseQualifiedIdentifier parses a schema-qualified identifier, e.g., "public"."foo" or public.foo
+func parseQualifiedIdentifier(qualifiedName string) (schema.SchemaQualifiedName, error) {
+	parts := splitQualifiedIdentifier(qualifiedName)
+	if len(parts) != 2 {
+		return schema.SchemaQualifiedName{}, fmt.Errorf("%q is not schema-qualified", qualifiedName)
+	}
+	return schema.SchemaQualifiedName{
+		SchemaName:  unescapeIdentifier(parts[0]),
+		EscapedName: schema.EscapeIdentifier(unescapeIdentifier(parts[1])),
+	}, nil
+}
+
+// normalizeQualifiedIdentifier normalizes a (possibly schema-qualified) identifier, such that the quoted and unquoted
+// forms of the same identifier are equal
+func normalizeQualifiedIdentifier(qualifiedName string) string {
+	var escapedParts []string
+	for _, part := range splitQualifiedIdentifier(qualifiedName) {
+		escapedParts = append(escapedParts, schema.EscapeIdentifier(unescapeIdentifier(part)))
+	}
+	return strings.Join(escapedParts, ".")
+}
+
+// splitQualifiedIdentifier splits a qualified identifier into its (possibly quoted) parts
+func splitQualifiedIdentifier(qualifiedName string) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i, r := range qualifiedName {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == '.' && !inQuotes:
+			parts = append(parts, qualifiedName[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, qualifiedName[start:])
+}
+
+// unescapeIdentifier unescapes a (possibly quoted) identifier. Unquoted identifiers are folded to lower case, like
+// Postgres does.
+func unescapeIdentifier(identifier string) string {
+	if len(identifier) >= 2 && strings.HasPrefix(identifier, "\"") && strings.HasSuffix(identifier, "\"") {
+		return strings.ReplaceAll(identifier[1:len(identifier)-1], "\"\"", "\"")
+	}
+	return strings.ToLower(identifier)
+}
@@ -0,0 +1,313 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestPlan_GenerateRollback(t *testing.T) {
+	for _, tc := range []struct {
+		name                  string
+		forwardDDL            []string
+		expectedRollbackDDL   []string
+		expectedHazardTypes   []MigrationHazardType
+		expectImpossibleIndex []int
+	}{
+		{
+			name:                "create table",
+			forwardDDL:          []string{"CREATE TABLE \"public\".\"foo\" (\n\t\"id\" integer\n)"},
+			expectedRollbackDDL: []string{"DROP TABLE \"public\".\"foo\""},
+			expectedHazardTypes: []MigrationHazardType{MigrationHazardTypeDeletesData},
+		},
+		{
+			name: "alterations of created table are reversed by dropping it",
+			forwardDDL: []string{
+				"CREATE TABLE \"public\".\"foo\" (\n\t\"id\" integer\n)",
+				"ALTER TABLE \"public\".\"foo\" ALTER COLUMN \"id\" SET STORAGE PLAIN",
+				"CREATE INDEX foo_idx ON public.foo USING btree (id)",
+				"ALTER INDEX \"public\".\"foo_idx\" SET TABLESPACE \"fast_ssd\"",
+				"CREATE POLICY \"foo_policy\" ON \"public\".\"foo\" USING (true)",
+				"CREATE OR REPLACE TRIGGER some_trigger BEFORE UPDATE ON public.foo FOR EACH ROW EXECUTE FUNCTION increment_version()",
+			},
+			expectedRollbackDDL: []string{"DROP TABLE \"public\".\"foo\""},
+			expectedHazardTypes: []MigrationHazardType{MigrationHazardTypeDeletesData},
+		},
+		{
+			name: "add column and constraints",
+			forwardDDL: []string{
+				"ALTER TABLE \"public\".\"foo\" ADD COLUMN \"val\" text COLLATE \"pg_catalog\".\"default\"",
+				"ALTER TABLE \"public\".\"foo\" ADD CONSTRAINT \"val_check\" CHECK((length(val) > 0)) NOT VALID",
+				"ALTER TABLE \"public\".\"foo\" VALIDATE CONSTRAINT \"val_check\"",
+			},
+			expectedRollbackDDL: []string{
+				"ALTER TABLE \"public\".\"foo\" DROP CONSTRAINT \"val_check\"",
+				"ALTER TABLE \"public\".\"foo\" DROP COLUMN \"val\"",
+			},
+			expectedHazardTypes: []MigrationHazardType{MigrationHazardTypeDeletesData},
+		},
+		{
+			name: "create index and add constraint using it",
+			forwardDDL: []string{
+				"CREATE UNIQUE INDEX CONCURRENTLY foo_pkey ON public.foo USING btree (id)",
+				"ALTER TABLE \"public\".\"foo\" ADD CONSTRAINT \"foo_pkey\" PRIMARY KEY USING INDEX \"foo_pkey\"",
+			},
+			expectedRollbackDDL: []string{
+				"ALTER TABLE \"public\".\"foo\" DROP CONSTRAINT \"foo_pkey\"",
+				"DROP INDEX CONCURRENTLY IF EXISTS public.foo_pkey",
+			},
+			expectedHazardTypes: []MigrationHazardType{MigrationHazardTypeIndexDropped},
+		},
+		{
+			name: "temporary constraints are not reversed",
+			forwardDDL: []string{
+				"ALTER TABLE \"public\".\"foo\" ADD CONSTRAINT \"pgschemadiff_tmpnn_id\" CHECK(\"id\" IS NOT NULL) NOT VALID",
+				"ALTER TABLE \"public\".\"foo\" VALIDATE CONSTRAINT \"pgschemadiff_tmpnn_id\"",
+				"ALTER TABLE \"public\".\"foo\" ALTER COLUMN \"id\" SET NOT NULL",
+				"ALTER TABLE \"public\".\"foo\" DROP CONSTRAINT \"pgschemadiff_tmpnn_id\"",
+			},
+			expectedRollbackDDL: []string{
+				"ALTER TABLE \"public\".\"foo\" ALTER COLUMN \"id\" DROP NOT NULL",
+			},
+		},
+		{
+			name: "nullability and row level security",
+			forwardDDL: []string{
+				"ALTER TABLE \"public\".\"foo\" ALTER COLUMN \"id\" SET NOT NULL",
+				"ALTER TABLE \"public\".\"foo\" ALTER COLUMN \"val\" DROP NOT NULL",
+				"ALTER TABLE \"public\".\"foo\" ENABLE ROW LEVEL SECURITY",
+				"ALTER TABLE \"public\".\"foo\" FORCE ROW LEVEL SECURITY",
+			},
+			expectedRollbackDDL: []string{
+				"ALTER TABLE \"public\".\"foo\" NO FORCE ROW LEVEL SECURITY",
+				"ALTER TABLE \"public\".\"foo\" DISABLE ROW LEVEL SECURITY",
+				"ALTER TABLE \"public\".\"foo\" ALTER COLUMN \"val\" SET NOT NULL",
+				"ALTER TABLE \"public\".\"foo\" ALTER COLUMN \"id\" DROP NOT NULL",
+			},
+			expectedHazardTypes: []MigrationHazardType{
+				MigrationHazardTypeAuthzUpdate,
+				MigrationHazardTypeAcquiresAccessExclusiveLock,
+			},
+		},
+		{
+			name: "create objects",
+			forwardDDL: []string{
+				"CREATE SCHEMA \"schema_1\"",
+				"CREATE EXTENSION \"pg_trgm\" WITH SCHEMA \"public\"",
+				"CREATE SEQUENCE \"public\".\"foo_seq\"\n\tAS bigint",
+				"CREATE TYPE \"public\".\"color\" AS ENUM ('red', 'blue')",
+				"CREATE DOMAIN \"public\".\"positive_int\" AS integer",
+				"CREATE VIEW \"public\".\"foo_view\" AS SELECT 1",
+				"CREATE MATERIALIZED VIEW \"public\".\"foo_mv\" AS SELECT 1",
+				"CREATE POLICY \"foo_policy\" ON \"public\".\"foo\" USING (true)",
+				"CREATE TRIGGER some_trigger BEFORE UPDATE OF id ON public.foo FOR EACH ROW EXECUTE FUNCTION increment_version()",
+				"CREATE PUBLICATION \"foo_pub\"",
+			},
+			expectedRollbackDDL: []string{
+				"DROP PUBLICATION \"foo_pub\"",
+				"DROP TRIGGER some_trigger ON public.foo",
+				"DROP POLICY \"foo_policy\" ON \"public\".\"foo\"",
+				"DROP MATERIALIZED VIEW \"public\".\"foo_mv\"",
+				"DROP VIEW \"public\".\"foo_view\"",
+				"DROP DOMAIN \"public\".\"positive_int\"",
+				"DROP TYPE \"public\".\"color\"",
+				"DROP SEQUENCE \"public\".\"foo_seq\"",
+				"DROP EXTENSION \"pg_trgm\"",
+				"DROP SCHEMA \"schema_1\"",
+			},
+		},
+		{
+			name: "renames",
+			forwardDDL: []string{
+				"ALTER SCHEMA \"schema_1\" RENAME TO \"schema_2\"",
+				"ALTER INDEX \"public\".\"foo_idx\" RENAME TO \"pgschemadiff_tmpidx_foo_idx\"",
+			},
+			expectedRollbackDDL: []string{
+				"ALTER INDEX \"public\".\"pgschemadiff_tmpidx_foo_idx\" RENAME TO \"foo_idx\"",
+				"ALTER SCHEMA \"schema_2\" RENAME TO \"schema_1\"",
+			},
+		},
+		{
+			name: "idempotent statements",
+			forwardDDL: []string{
+				"CREATE SCHEMA IF NOT EXISTS \"schema_1\"",
+				"CREATE TABLE IF NOT EXISTS \"public\".\"bar\" (\n\t\"id\" integer\n)",
+				"CREATE INDEX IF NOT EXISTS bar_idx ON public.bar USING btree (id)",
+				"ALTER TABLE \"public\".\"foo\" ADD COLUMN IF NOT EXISTS \"val\" text",
+				"CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS foo_val_idx ON public.foo USING btree (val)",
+				"DO $pgschemadiff_idempotent$\nBEGIN\n\tALTER TABLE \"public\".\"foo\" ADD CONSTRAINT \"pgschemadiff_tmpnn_id\" CHECK(\"id\" IS NOT NULL) NOT VALID;\nEXCEPTION WHEN duplicate_table OR duplicate_object OR duplicate_function THEN NULL;\nEND\n$pgschemadiff_idempotent$",
+				"ALTER TABLE \"public\".\"foo\" DROP CONSTRAINT IF EXISTS \"pgschemadiff_tmpnn_id\"",
+				"DO $pgschemadiff_idempotent$\nBEGIN\n\tCREATE TYPE \"public\".\"color\" AS ENUM (\n\t\t'red',\n\t\t'blue'\n\t);\nEXCEPTION WHEN duplicate_table OR duplicate_object OR duplicate_function THEN NULL;\nEND\n$pgschemadiff_idempotent$",
+			},
+			expectedRollbackDDL: []string{
+				"DROP TYPE \"public\".\"color\"",
+				"DROP INDEX CONCURRENTLY IF EXISTS public.foo_val_idx",
+				"ALTER TABLE \"public\".\"foo\" DROP COLUMN \"val\"",
+				"DROP TABLE \"public\".\"bar\"",
+				"DROP SCHEMA \"schema_1\"",
+			},
+			expectedHazardTypes: []MigrationHazardType{MigrationHazardTypeIndexDropped, MigrationHazardTypeDeletesData},
+		},
+		{
+			name: "hazardous forward statements are impossible to roll back",
+			forwardDDL: []string{
+				"ALTER TABLE \"public\".\"foo\" DROP COLUMN \"val\"",
+				"DROP TABLE \"public\".\"bar\"",
+				"CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n RETURNS integer\n LANGUAGE sql\nAS $function$SELECT a + b$function$",
+			},
+			expectedRollbackDDL: []string{
+				"-- CREATE OR REPLACE FUNCTION public.add(a integer, b integer)\n--  RETURNS integer\n--  LANGUAGE sql\n-- AS $function$SELECT a + b$function$",
+				"-- DROP TABLE \"public\".\"bar\"",
+				"-- ALTER TABLE \"public\".\"foo\" DROP COLUMN \"val\"",
+			},
+			expectedHazardTypes:   []MigrationHazardType{MigrationHazardTypeImpossibleToRollback},
+			expectImpossibleIndex: []int{0, 1, 2},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var forwardStmts []Statement
+			for _, ddl := range tc.forwardDDL {
+				forwardStmts = append(forwardStmts, Statement{DDL: ddl, Timeout: statementTimeoutDefault, LockTimeout: lockTimeoutDefault})
+			}
+			rollbackPlan, err := Plan{Statements: forwardStmts, CurrentSchemaHash: "some-hash"}.GenerateRollback()
+			require.NoError(t, err)
+			assert.Empty(t, rollbackPlan.CurrentSchemaHash)
+
+			var ddl []string
+			hazardTypesSet := make(map[MigrationHazardType]bool)
+			var hazardTypes []MigrationHazardType
+			var impossibleIdxs []int
+			for i, stmt := range rollbackPlan.Statements {
+				ddl = append(ddl, stmt.DDL)
+				assert.Greater(t, stmt.Timeout.Nanoseconds(), int64(0))
+				assert.Greater(t, stmt.LockTimeout.Nanoseconds(), int64(0))
+				for _, hazard := range stmt.Hazards {
+					if !hazardTypesSet[hazard.Type] {
+						hazardTypesSet[hazard.Type] = true
+						hazardTypes = append(hazardTypes, hazard.Type)
+					}
+					if hazard.Type == MigrationHazardTypeImpossibleToRollback {
+						assert.True(t, stmt.IsAdvisory)
+						impossibleIdxs = append(impossibleIdxs, i)
+					}
+				}
+			}
+			assert.Equal(t, tc.expectedRollbackDDL, ddl)
+			assert.ElementsMatch(t, tc.expectedHazardTypes, hazardTypes)
+			assert.Equal(t, tc.expectImpossibleIndex, impossibleIdxs)
+		})
+	}
+}
+
+func TestPlan_GenerateRollback_SkipsAdvisoryStatements(t *testing.T) {
+	rollbackPlan, err := Plan{Statements: []Statement{
+		{DDL: "CREATE STATISTICS public.foo_stats ON id, val FROM public.foo", Timeout: statementTimeoutDefault, LockTimeout: lockTimeoutDefault},
+		{DDL: "ANALYZE \"public\".\"foo\"", Timeout: statementTimeoutAnalyzeTable, LockTimeout: lockTimeoutDefault, IsAdvisory: true},
+	}}.GenerateRollback()
+	require.NoError(t, err)
+	require.Len(t, rollbackPlan.Statements, 1)
+	assert.Equal(t, "DROP STATISTICS public.foo_stats", rollbackPlan.Statements[0].DDL)
+}
+
+func TestPlan_GenerateRollback_RoundTrip(t *testing.T) {
+	table := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	newTable := table
+	newTable.Columns = append(newTable.Columns, schema.Column{Name: "val", Type: "text", IsNullable: true})
+	newTable.RLSEnabled = true
+	index := schema.Index{
+		Name:            "foo_val_idx",
+		OwningTable:     table.SchemaQualifiedName,
+		Columns:         []string{"val"},
+		GetIndexDefStmt: "CREATE INDEX foo_val_idx ON public.foo USING btree (val)",
+	}
+
+	forwardStmts, err := generateMigrationStatements(
+		schema.Schema{Tables: []schema.Table{table}},
+		schema.Schema{
+			NamedSchemas: []schema.NamedSchema{{Name: "schema_1"}},
+			Tables:       []schema.Table{newTable},
+			Indexes:      []schema.Index{index},
+		},
+		&planOptions{},
+	)
+	require.NoError(t, err)
+
+	rollbackPlan, err := Plan{Statements: forwardStmts}.GenerateRollback()
+	require.NoError(t, err)
+	var ddl []string
+	for _, stmt := range rollbackPlan.Statements {
+		ddl = append(ddl, stmt.DDL)
+		for _, hazard := range stmt.Hazards {
+			assert.NotEqual(t, MigrationHazardTypeImpossibleToRollback, hazard.Type)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"DROP SCHEMA \"schema_1\"",
+		"ALTER TABLE \"public\".\"foo\" DROP COLUMN \"val\"",
+		"ALTER TABLE \"public\".\"foo\" DISABLE ROW LEVEL SECURITY",
+		"DROP INDEX CONCURRENTLY IF EXISTS public.foo_val_idx",
+	}, ddl)
+}
+
+func TestPlan_GenerateRollback_IdempotentSQL(t *testing.T) {
+	table := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	newTable := table
+	newTable.Columns = append(newTable.Columns, schema.Column{Name: "val", Type: "text", IsNullable: true})
+	newTable.CheckConstraints = []schema.CheckConstraint{{
+		Name:          "val_check",
+		KeyColumns:    []string{"val"},
+		Expression:    "(length(val) > 0)",
+		IsValid:       true,
+		IsInheritable: true,
+	}}
+	barTable := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "schema_1", EscapedName: "\"bar\""},
+		Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+
+	plan, err := buildPlan(
+		schema.Schema{Tables: []schema.Table{table}},
+		schema.Schema{
+			NamedSchemas: []schema.NamedSchema{{Name: "schema_1"}},
+			Tables:       []schema.Table{newTable, barTable},
+			Indexes: []schema.Index{{
+				Name:            "bar_idx",
+				OwningTable:     barTable.SchemaQualifiedName,
+				Columns:         []string{"id"},
+				GetIndexDefStmt: "CREATE INDEX bar_idx ON schema_1.bar USING btree (id)",
+			}},
+		},
+		&planOptions{idempotentSQL: true},
+	)
+	require.NoError(t, err)
+	forwardDDL := getDDL(plan)
+	assert.Contains(t, forwardDDL, "CREATE SCHEMA IF NOT EXISTS \"schema_1\"")
+	assert.Contains(t, forwardDDL, "ALTER TABLE \"public\".\"foo\" ADD COLUMN IF NOT EXISTS \"val\" text")
+
+	rollbackPlan, err := plan.GenerateRollback()
+	require.NoError(t, err)
+	var ddl []string
+	for _, stmt := range rollbackPlan.Statements {
+		ddl = append(ddl, stmt.DDL)
+		for _, hazard := range stmt.Hazards {
+			assert.NotEqual(t, MigrationHazardTypeImpossibleToRollback, hazard.Type)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"DROP SCHEMA \"schema_1\"",
+		"DROP TABLE \"schema_1\".\"bar\"",
+		"ALTER TABLE \"public\".\"foo\" DROP CONSTRAINT \"val_check\"",
+		"ALTER TABLE \"public\".\"foo\" DROP COLUMN \"val\"",
+	}, ddl)
+}
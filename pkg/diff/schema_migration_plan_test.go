@@ -92,16 +92,18 @@ var (
 					LockTimeout: lockTimeoutDefault,
 				},
 				{
-					DDL:         "CREATE INDEX CONCURRENTLY some_idx ON public.foobar USING btree (foo, bar)",
-					Timeout:     statementTimeoutConcurrentIndexBuild,
-					LockTimeout: lockTimeoutDefault,
-					Hazards:     []MigrationHazard{buildIndexBuildHazard()},
+					DDL:                   "CREATE INDEX CONCURRENTLY some_idx ON public.foobar USING btree (foo, bar)",
+					Timeout:               statementTimeoutConcurrentIndexBuild,
+					LockTimeout:           lockTimeoutDefault,
+					Hazards:               []MigrationHazard{buildIndexBuildHazard()},
+					RequiresNoTransaction: true,
 				},
 				{
-					DDL:         "DROP INDEX CONCURRENTLY \"public\".\"pgschemadiff_tmpidx_some_idx_AAECAwQFRgeICQoLDA0ODw\"",
-					Timeout:     statementTimeoutConcurrentIndexDrop,
-					LockTimeout: lockTimeoutDefault,
-					Hazards:     []MigrationHazard{buildIndexDroppedQueryPerfHazard()},
+					DDL:                   "DROP INDEX CONCURRENTLY \"public\".\"pgschemadiff_tmpidx_some_idx_AAECAwQFRgeICQoLDA0ODw\"",
+					Timeout:               statementTimeoutConcurrentIndexDrop,
+					LockTimeout:           lockTimeoutDefault,
+					Hazards:               []MigrationHazard{buildIndexDroppedQueryPerfHazard()},
+					RequiresNoTransaction: true,
 				},
 			},
 		},
@@ -205,6 +207,7 @@ var (
 					Hazards: []MigrationHazard{
 						buildIndexBuildHazard(),
 					},
+					RequiresNoTransaction: true,
 				},
 				{
 					DDL:         "ALTER INDEX \"public\".\"some_idx\" ATTACH PARTITION \"public\".\"foobar_1_some_idx\"",
@@ -218,6 +221,7 @@ var (
 					Hazards: []MigrationHazard{
 						buildIndexDroppedQueryPerfHazard(),
 					},
+					RequiresNoTransaction: true,
 				},
 			},
 		},
@@ -344,6 +348,56 @@ var (
 			},
 			expectedDiffErrContains: "loop detected",
 		},
+		{
+			name: "Exclusion constraint added without building its index concurrently",
+			oldSchema: schema.Schema{
+				Tables: []schema.Table{
+					{
+						SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""},
+						Columns: []schema.Column{
+							{Name: "during", Type: "tsrange", IsNullable: true},
+						},
+						ReplicaIdentity: schema.ReplicaIdentityDefault,
+					},
+				},
+			},
+			newSchema: schema.Schema{
+				Tables: []schema.Table{
+					{
+						SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""},
+						Columns: []schema.Column{
+							{Name: "during", Type: "tsrange", IsNullable: true},
+						},
+						ReplicaIdentity: schema.ReplicaIdentityDefault,
+					},
+				},
+				Indexes: []schema.Index{
+					{
+						OwningTable: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""},
+						Name:        "foobar_during_excl", Columns: []string{"during"},
+						GetIndexDefStmt: "CREATE INDEX foobar_during_excl ON public.foobar USING gist (during)",
+						Constraint: &schema.IndexConstraint{
+							Type:                  schema.ExclusionIndexConstraintType,
+							EscapedConstraintName: "\"foobar_during_excl\"",
+							ConstraintDef:         "EXCLUDE USING gist (during WITH &&)",
+							IsLocal:               true,
+						},
+					},
+				},
+			},
+			expectedStatements: []Statement{
+				{
+					DDL:         "ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"foobar_during_excl\" EXCLUDE USING gist (during WITH &&)",
+					Timeout:     statementTimeoutDefault,
+					LockTimeout: lockTimeoutDefault,
+					Hazards: []MigrationHazard{{
+						Type: MigrationHazardTypeAcquiresAccessExclusiveLock,
+						Message: "This will lock reads and writes to the owning table while the constraint's index is being built. " +
+							"Exclusion constraints cannot be built concurrently.",
+					}},
+				},
+			},
+		},
 	}
 )
 
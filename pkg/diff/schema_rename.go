@@ -0,0 +1,265 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	// simpleIdentifierRegex matches identifiers that Postgres does not quote when it outputs a definition, e.g., via
+	// pg_get_indexdef
+	simpleIdentifierRegex = regexp.MustCompile("^[a-z_][a-z0-9_$]*$")
+
+	schemaQualifiedNameType = reflect.TypeOf(schema.SchemaQualifiedName{})
+	namedSchemaType         = reflect.TypeOf(schema.NamedSchema{})
+	functionType            = reflect.TypeOf(schema.Function{})
+	procedureType           = reflect.TypeOf(schema.Procedure{})
+
+	// renameNamedSchemaFieldNames are the fields that contain names or definitions that might reference a named schema,
+	// e.g., the type of a column or the definition of an index. Other fields, e.g., comments, are not renamed, since they
+	// are free text. Defaults are handled separately: only their regclass literals are renamed.
+	renameNamedSchemaFieldNames = map[string]bool{
+		"Type":                 true,
+		"BaseType":             true,
+		"StateType":            true,
+		"InputTypes":           true,
+		"TableName":            true,
+		"Definition":           true,
+		"Def":                  true,
+		"ConstraintDef":        true,
+		"GetIndexDefStmt":      true,
+		"Expression":           true,
+		"Expressions":          true,
+		"GenerationExpression": true,
+		"CheckExpression":      true,
+		"UsingExpression":      true,
+		"Check":                true,
+		"Predicate":            true,
+		"PartitionKeyDef":      true,
+		"ForValues":            true,
+	}
+)
+
+// namedSchemaRename is a rename of a named schema configured via WithRenamedSchema
+type namedSchemaRename struct {
+	oldName string
+	newName string
+}
+
+// buildRenameNamedSchemaStatement builds the statement to rename a named schema. Postgres automatically updates all
+// objects in the schema, so no other statements are required to move them.
+func buildRenameNamedSchemaStatement(rename namedSchemaRename) Statement {
+	return Statement{
+		DDL:         fmt.Sprintf("ALTER SCHEMA %s RENAME TO %s", schema.EscapeIdentifier(rename.oldName), schema.EscapeIdentifier(rename.newName)),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards: []MigrationHazard{
+			{
+				Type:    MigrationHazardTypeAcquiresAccessExclusiveLock,
+				Message: "Renaming a schema briefly acquires a lock on the schema, which blocks concurrent DDL on the objects in the schema. It should be fast.",
+			},
+			{
+				Type: MigrationHazardTypeHasUntrackableDependencies,
+				Message: "Queries, and the bodies of non-SQL functions, that reference the schema by its old name will " +
+					"fail after the schema is renamed. These references cannot be tracked.",
+			},
+		},
+	}
+}
+
+// renameNamedSchemas returns a copy of the schema where the named schemas are renamed, i.e., the schema that Postgres
+// would report after running `ALTER SCHEMA ... RENAME TO ...`, and the renames that were applied. This allows the
+// objects in a renamed schema to be diffed against the objects in the new schema rather than being dropped and
+// re-created.
+//
+// Renames that were already applied, i.e., the old schema does not exist but the new schema does, are skipped, such
+// that a plan can be generated again after the rename is executed.
+func renameNamedSchemas(s schema.Schema, renames []namedSchemaRename) (schema.Schema, []namedSchemaRename, error) {
+	if len(renames) == 0 {
+		return s, nil, nil
+	}
+
+	namedSchemasByName := buildSchemaObjByNameMap(s.NamedSchemas)
+	var pendingRenames []namedSchemaRename
+	for _, rename := range renames {
+		_, oldExists := namedSchemasByName[rename.oldName]
+		_, newExists := namedSchemasByName[rename.newName]
+		switch {
+		case !oldExists && newExists:
+			continue
+		case !oldExists:
+			return schema.Schema{}, nil, fmt.Errorf("cannot rename schema %q because it does not exist", rename.oldName)
+		case newExists:
+			return schema.Schema{}, nil, fmt.Errorf("cannot rename schema %q to %q because %q already exists", rename.oldName, rename.newName, rename.newName)
+		}
+		pendingRenames = append(pendingRenames, rename)
+	}
+	if len(pendingRenames) == 0 {
+		return s, nil, nil
+	}
+
+	renamed := s.DeepCopy()
+	for _, rename := range pendingRenames {
+		renameNamedSchemaInValue(reflect.ValueOf(&renamed).Elem(), rename)
+	}
+	return renamed, pendingRenames, nil
+}
+
+// renameNamedSchemaInValue recursively renames the named schema in all schema qualified names, names, and definitions
+// contained by the value. Only the fields in renameNamedSchemaFieldNames are renamed. The value must be settable.
+func renameNamedSchemaInValue(v reflect.Value, rename namedSchemaRename) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			renameNamedSchemaInValue(v.Elem(), rename)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			renameNamedSchemaInValue(v.Index(i), rename)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(v.MapIndex(key))
+			renameNamedSchemaInValue(value, rename)
+			v.SetMapIndex(key, value)
+		}
+	case reflect.String:
+		v.SetString(renameNamedSchemaInDefinition(v.String(), rename))
+	case reflect.Struct:
+		switch v.Type() {
+		case schemaQualifiedNameType:
+			if schemaName := v.FieldByName("SchemaName"); schemaName.String() == rename.oldName {
+				schemaName.SetString(rename.newName)
+			}
+			// The escaped name might contain references to the schema, e.g., the argument types of a function
+			renameNamedSchemaInValue(v.FieldByName("EscapedName"), rename)
+			return
+		case namedSchemaType:
+			if name := v.FieldByName("Name"); name.String() == rename.oldName {
+				name.SetString(rename.newName)
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			switch {
+			case (v.Type() == functionType && field.Name == "FunctionDef") || (v.Type() == procedureType && field.Name == "Def"):
+				v.Field(i).SetString(renameNamedSchemaInFunctionDef(v.Field(i).String(), rename))
+			case field.Name == "SchemaName" && field.Type.Kind() == reflect.String:
+				if v.Field(i).String() == rename.oldName {
+					v.Field(i).SetString(rename.newName)
+				}
+			case field.Name == "Default" && field.Type.Kind() == reflect.String:
+				v.Field(i).SetString(renameNamedSchemaInSQL(v.Field(i).String(), rename, false))
+			case isStringOrStrings(field.Type):
+				if renameNamedSchemaFieldNames[field.Name] {
+					renameNamedSchemaInValue(v.Field(i), rename)
+				}
+			default:
+				renameNamedSchemaInValue(v.Field(i), rename)
+			}
+		}
+	}
+}
+
+// renameNamedSchemaInFunctionDef renames the named schema in the function definition, as returned by
+// pg_get_functiondef. String bodies, i.e., `AS $function$...$function$`, are stored verbatim by Postgres, so they are not
+// updated by a schema rename and are left untouched. SQL-standard bodies, i.e., `BEGIN ATOMIC ... END`, are stored as
+// parse trees, so they are updated.
+func renameNamedSchemaInFunctionDef(def string, rename namedSchemaRename) string {
+	bodyIdx := strings.Index(def, "\nAS ")
+	if bodyIdx == -1 {
+		return renameNamedSchemaInDefinition(def, rename)
+	}
+	return renameNamedSchemaInDefinition(def[:bodyIdx], rename) + def[bodyIdx:]
+}
+
+// isStringOrStrings returns whether the type is a string or a pointer to, slice of, or map of strings
+func isStringOrStrings(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String
+}
+
+// renameNamedSchemaInDefinition renames references to the named schema in a definition generated by Postgres, e.g.,
+// `CREATE INDEX foo ON schema_1.bar USING btree (id)`. Postgres only quotes identifiers when necessary. References in
+// string literals, e.g., 'schema_1.bar', are left untouched, unless the literal is cast to regclass, e.g.,
+// nextval('schema_1.bar_id_seq'::regclass), since Postgres stores it as a reference to the object.
+func renameNamedSchemaInDefinition(def string, rename namedSchemaRename) string {
+	return renameNamedSchemaInSQL(def, rename, true)
+}
+
+// renameNamedSchemaInSQL renames references to the named schema in the regclass literals of the SQL and, if renameCode
+// is true, in the SQL outside of string literals
+func renameNamedSchemaInSQL(sql string, rename namedSchemaRename, renameCode bool) string {
+	sb := strings.Builder{}
+	for len(sql) > 0 {
+		literalIdx := strings.IndexByte(sql, '\'')
+		if literalIdx == -1 {
+			literalIdx = len(sql)
+		}
+		if renameCode {
+			sb.WriteString(renameNamedSchemaInCode(sql[:literalIdx], rename))
+		} else {
+			sb.WriteString(sql[:literalIdx])
+		}
+		if literalIdx == len(sql) {
+			break
+		}
+		literalEnd := findClosingQuote(sql, literalIdx)
+		literal := sql[literalIdx:literalEnd]
+		if strings.HasPrefix(sql[literalEnd:], "::regclass") {
+			literal = renameNamedSchemaInCode(literal, rename)
+		}
+		sb.WriteString(literal)
+		sql = sql[literalEnd:]
+	}
+	return sb.String()
+}
+
+// renameNamedSchemaInCode renames references to the named schema in a part of a definition that has no string literals
+func renameNamedSchemaInCode(def string, rename namedSchemaRename) string {
+	oldPrefixes := []string{schema.EscapeIdentifier(rename.oldName) + "."}
+	if simpleIdentifierRegex.MatchString(rename.oldName) {
+		oldPrefixes = append(oldPrefixes, rename.oldName+".")
+	}
+	newPrefix := schema.EscapeIdentifier(rename.newName) + "."
+	if simpleIdentifierRegex.MatchString(rename.newName) {
+		newPrefix = rename.newName + "."
+	}
+
+	for _, oldPrefix := range oldPrefixes {
+		sb := strings.Builder{}
+		for {
+			idx := strings.Index(def, oldPrefix)
+			if idx == -1 {
+				sb.WriteString(def)
+				break
+			}
+			sb.WriteString(def[:idx])
+			// Only replace the prefix if it is not the end of a longer identifier, e.g., "my_schema_1." when renaming
+			// "schema_1"
+			if idx > 0 && isIdentifierPartOrQuote(def[idx-1]) {
+				sb.WriteString(oldPrefix)
+			} else {
+				sb.WriteString(newPrefix)
+			}
+			def = def[idx+len(oldPrefix):]
+		}
+		def = sb.String()
+	}
+	return def
+}
+
+func isIdentifierPartOrQuote(c byte) bool {
+	return c == '"' || c == '.' || isIdentifierPart(c)
+}
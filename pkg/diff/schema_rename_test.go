@@ -0,0 +1,148 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestRenameNamedSchemaInDefinition(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		def      string
+		rename   namedSchemaRename
+		expected string
+	}{
+		{
+			name:     "unquoted names",
+			def:      "CREATE INDEX foobar_idx ON schema_1.foobar USING btree (id)",
+			rename:   namedSchemaRename{oldName: "schema_1", newName: "schema_2"},
+			expected: "CREATE INDEX foobar_idx ON schema_2.foobar USING btree (id)",
+		},
+		{
+			name:     "quoted names",
+			def:      `CREATE INDEX foobar_idx ON "Schema 1".foobar USING btree (id)`,
+			rename:   namedSchemaRename{oldName: "Schema 1", newName: "Schema 2"},
+			expected: `CREATE INDEX foobar_idx ON "Schema 2".foobar USING btree (id)`,
+		},
+		{
+			name:     "unquoted to quoted name",
+			def:      "nextval('schema_1.foobar_id_seq'::regclass)",
+			rename:   namedSchemaRename{oldName: "schema_1", newName: "Schema 2"},
+			expected: `nextval('"Schema 2".foobar_id_seq'::regclass)`,
+		},
+		{
+			name:     "string literals are not renamed",
+			def:      "SELECT 'schema_1.foobar', 'it''s schema_1.foobar' FROM schema_1.foobar",
+			rename:   namedSchemaRename{oldName: "schema_1", newName: "schema_2"},
+			expected: "SELECT 'schema_1.foobar', 'it''s schema_1.foobar' FROM schema_2.foobar",
+		},
+		{
+			name:     "names that end with the schema name are not renamed",
+			def:      "FOREIGN KEY (id) REFERENCES my_schema_1.foobar(id), FOREIGN KEY (id) REFERENCES schema_1.foobar(id)",
+			rename:   namedSchemaRename{oldName: "schema_1", newName: "schema_2"},
+			expected: "FOREIGN KEY (id) REFERENCES my_schema_1.foobar(id), FOREIGN KEY (id) REFERENCES schema_2.foobar(id)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, renameNamedSchemaInDefinition(tc.def, tc.rename))
+		})
+	}
+}
+
+func TestRenameNamedSchemas(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "schema_1", EscapedName: `"foobar"`}
+	comment := "Moved from schema_1.legacy"
+	oldSchema := schema.Schema{
+		NamedSchemas: []schema.NamedSchema{{Name: "public"}, {Name: "schema_1"}},
+		Tables: []schema.Table{{
+			SchemaQualifiedName: foobar,
+			Columns: []schema.Column{
+				{Name: "id", Type: "integer", Default: "nextval('schema_1.foobar_id_seq'::regclass)"},
+				{Name: "color", Type: "schema_1.color", Default: "'schema_1.red'::text", IsNullable: true},
+			},
+			Comment: &comment,
+		}},
+		Indexes: []schema.Index{{
+			Name:            "foobar_pkey",
+			OwningTable:     foobar,
+			GetIndexDefStmt: "CREATE UNIQUE INDEX foobar_pkey ON schema_1.foobar USING btree (id)",
+		}},
+		Functions: []schema.Function{{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "schema_1", EscapedName: `"count_foobar"()`},
+			FunctionDef:         "CREATE OR REPLACE FUNCTION schema_1.count_foobar()\n RETURNS bigint\n LANGUAGE plpgsql\nAS $function$ BEGIN RETURN (SELECT COUNT(*) FROM schema_1.foobar); END $function$\n",
+			Language:            "plpgsql",
+			DependsOnTables:     []schema.SchemaQualifiedName{foobar},
+		}},
+	}
+
+	renamedSchema, appliedRenames, err := renameNamedSchemas(oldSchema, []namedSchemaRename{{oldName: "schema_1", newName: "schema_2"}})
+	require.NoError(t, err)
+	assert.Equal(t, []namedSchemaRename{{oldName: "schema_1", newName: "schema_2"}}, appliedRenames)
+
+	renamedFoobar := schema.SchemaQualifiedName{SchemaName: "schema_2", EscapedName: `"foobar"`}
+	assert.Equal(t, schema.Schema{
+		NamedSchemas: []schema.NamedSchema{{Name: "public"}, {Name: "schema_2"}},
+		Tables: []schema.Table{{
+			SchemaQualifiedName: renamedFoobar,
+			Columns: []schema.Column{
+				// Only the regclass literals of defaults are renamed. Comments are not renamed.
+				{Name: "id", Type: "integer", Default: "nextval('schema_2.foobar_id_seq'::regclass)"},
+				{Name: "color", Type: "schema_2.color", Default: "'schema_1.red'::text", IsNullable: true},
+			},
+			Comment: &comment,
+		}},
+		Indexes: []schema.Index{{
+			Name:            "foobar_pkey",
+			OwningTable:     renamedFoobar,
+			GetIndexDefStmt: "CREATE UNIQUE INDEX foobar_pkey ON schema_2.foobar USING btree (id)",
+		}},
+		Functions: []schema.Function{{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "schema_2", EscapedName: `"count_foobar"()`},
+			// The body of the function is stored verbatim by Postgres, so it is not renamed
+			FunctionDef:     "CREATE OR REPLACE FUNCTION schema_2.count_foobar()\n RETURNS bigint\n LANGUAGE plpgsql\nAS $function$ BEGIN RETURN (SELECT COUNT(*) FROM schema_1.foobar); END $function$\n",
+			Language:        "plpgsql",
+			DependsOnTables: []schema.SchemaQualifiedName{renamedFoobar},
+		}},
+	}, renamedSchema)
+	// The original schema should not be modified
+	assert.Equal(t, "schema_1", oldSchema.Tables[0].SchemaName)
+
+	_, _, err = renameNamedSchemas(oldSchema, []namedSchemaRename{{oldName: "schema_1", newName: "public"}})
+	assert.ErrorContains(t, err, "already exists")
+
+	_, _, err = renameNamedSchemas(oldSchema, []namedSchemaRename{{oldName: "schema_3", newName: "schema_4"}})
+	assert.ErrorContains(t, err, "does not exist")
+
+	t.Run("Already applied renames are skipped", func(t *testing.T) {
+		skippedSchema, appliedRenames, err := renameNamedSchemas(renamedSchema, []namedSchemaRename{{oldName: "schema_1", newName: "schema_2"}})
+		require.NoError(t, err)
+		assert.Empty(t, appliedRenames)
+		assert.Equal(t, renamedSchema, skippedSchema)
+
+		_, stmts, err := applyRenames(renamedSchema, &planOptions{schemaRenames: []namedSchemaRename{{oldName: "schema_1", newName: "schema_2"}}})
+		require.NoError(t, err)
+		assert.Empty(t, stmts)
+	})
+}
+
+func TestAssertMigratedSchemaMatchesTarget_Renames(t *testing.T) {
+	// The migrated schema already has the renames applied, so validating the plan must not apply them again
+	migratedSchema := schema.Schema{
+		NamedSchemas: []schema.NamedSchema{{Name: "public"}, {Name: "schema_2"}},
+		Tables: []schema.Table{{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "schema_2", EscapedName: `"bar"`},
+			Columns:             []schema.Column{{Name: "new_id", Type: "integer"}},
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		}},
+	}
+	assert.NoError(t, assertMigratedSchemaMatchesTarget(migratedSchema, migratedSchema, &planOptions{
+		schemaRenames: []namedSchemaRename{{oldName: "schema_1", newName: "schema_2"}},
+		tableRenames: []tableRename{{
+			old: schema.SchemaQualifiedName{SchemaName: "schema_1", EscapedName: `"foo"`},
+			new: schema.SchemaQualifiedName{SchemaName: "schema_1", EscapedName: `"bar"`},
+		}},
+	}))
+}
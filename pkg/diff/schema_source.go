@@ -2,11 +2,14 @@ package diff
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	pg_query "github.com/pganalyze/pg_query_go/v5"
+	"github.com/pganalyze/pg_query_go/v5/parser"
 	"github.com/stripe/pg-schema-diff/internal/schema"
 	"github.com/stripe/pg-schema-diff/pkg/log"
 	"github.com/stripe/pg-schema-diff/pkg/sqldb"
@@ -21,6 +24,9 @@ type schemaSourcePlanDeps struct {
 
 type SchemaSource interface {
 	GetSchema(ctx context.Context, deps schemaSourcePlanDeps) (schema.Schema, error)
+	// Validate returns an error if the schema is known to be invalid, e.g., its DDL declares an event trigger as
+	// DEFERRABLE. It does not require a database, so it only catches a subset of the errors returned by GetSchema.
+	Validate() error
 }
 
 type (
@@ -100,6 +106,9 @@ func DDLSchemaSource(stmts []string) SchemaSource {
 }
 
 func (s *ddlSchemaSource) GetSchema(ctx context.Context, deps schemaSourcePlanDeps) (schema.Schema, error) {
+	if err := s.Validate(); err != nil {
+		return schema.Schema{}, err
+	}
 	if deps.tempDBFactory == nil {
 		return schema.Schema{}, errTempDbFactoryRequired
 	}
@@ -127,6 +136,20 @@ func (s *ddlSchemaSource) GetSchema(ctx context.Context, deps schemaSourcePlanDe
 	return schema.GetSchema(ctx, tempDb.ConnPool, append(deps.getSchemaOpts, tempDb.ExcludeMetadataOptions...)...)
 }
 
+func (s *ddlSchemaSource) Validate() error {
+	for _, ddlStmt := range s.ddl {
+		if err := validateDDLStatement(ddlStmt); err != nil {
+			return fmt.Errorf("validating DDL: %w", err)
+		}
+	}
+	return nil
+}
+
+// tableRowEstimator is implemented by schema sources that can estimate the number of rows in each table
+type tableRowEstimator interface {
+	getTableRowEstimates(ctx context.Context) (map[string]int64, error)
+}
+
 type dbSchemaSource struct {
 	queryable sqldb.Queryable
 }
@@ -140,3 +163,86 @@ func DBSchemaSource(queryable sqldb.Queryable) SchemaSource {
 func (s *dbSchemaSource) GetSchema(ctx context.Context, deps schemaSourcePlanDeps) (schema.Schema, error) {
 	return schema.GetSchema(ctx, s.queryable, deps.getSchemaOpts...)
 }
+
+func (s *dbSchemaSource) Validate() error {
+	return nil
+}
+
+func (s *dbSchemaSource) getTableRowEstimates(ctx context.Context) (map[string]int64, error) {
+	return schema.GetTableRowEstimates(ctx, s.queryable)
+}
+
+type schemaSchemaSource struct {
+	schema schema.Schema
+}
+
+// SchemaSchemaSource returns a SchemaSource that returns the provided schema, e.g., a schema parsed from a pg_dump via
+// schema.ParseDump. The schema is returned as is: the schema filters of the plan, e.g., WithIncludeSchemas, are not
+// applied to it.
+func SchemaSchemaSource(s schema.Schema) SchemaSource {
+	return &schemaSchemaSource{schema: s}
+}
+
+func (s *schemaSchemaSource) GetSchema(_ context.Context, _ schemaSourcePlanDeps) (schema.Schema, error) {
+	return s.schema.DeepCopy(), nil
+}
+
+func (s *schemaSchemaSource) Validate() error {
+	return nil
+}
+
+// validateDDLStatement catches mistakes in the DDL that Postgres would otherwise reject with a cryptic syntax error.
+// Errors that cannot be explained are left to Postgres, since the parser might not support the newest syntax.
+func validateDDLStatement(ddlStmt ddlStatement) error {
+	_, err := pg_query.Parse(ddlStmt.stmt)
+	var parseErr *parser.Error
+	runes := []rune(ddlStmt.stmt)
+	if err == nil || !errors.As(err, &parseErr) || parseErr.Cursorpos <= 0 || parseErr.Cursorpos > len(runes) {
+		return nil
+	}
+	// The cursor position is the 1-based index of the character, not the byte, at which parsing failed
+	errOffset := len(string(runes[:parseErr.Cursorpos-1]))
+	scanResult, err := pg_query.Scan(ddlStmt.stmt)
+	if err != nil {
+		return nil
+	}
+
+	// Find the statement that failed to parse and the token parsing failed at
+	var stmtTokens []*pg_query.ScanToken
+	for _, token := range scanResult.Tokens {
+		switch token.Token {
+		case pg_query.Token_SQL_COMMENT, pg_query.Token_C_COMMENT:
+			continue
+		case pg_query.Token_ASCII_59:
+			stmtTokens = nil
+			continue
+		}
+		stmtTokens = append(stmtTokens, token)
+		if int(token.Start) != errOffset {
+			continue
+		}
+		if token.Token == pg_query.Token_DEFERRABLE && len(stmtTokens) > 3 &&
+			stmtTokens[0].Token == pg_query.Token_CREATE &&
+			stmtTokens[1].Token == pg_query.Token_EVENT &&
+			stmtTokens[2].Token == pg_query.Token_TRIGGER {
+			triggerName := ddlStmt.stmt[stmtTokens[3].Start:stmtTokens[3].End]
+			if strings.HasPrefix(triggerName, `"`) {
+				triggerName = strings.ReplaceAll(strings.Trim(triggerName, `"`), `""`, `"`)
+			} else {
+				triggerName = strings.ToLower(triggerName)
+			}
+			return fmt.Errorf("event triggers do not support DEFERRABLE; found in trigger '%s'%s", triggerName, buildDDLLocation(ddlStmt, int(stmtTokens[0].Start)))
+		}
+		break
+	}
+	return nil
+}
+
+// buildDDLLocation builds a description of where the character at the given offset is in the DDL statement.
+func buildDDLLocation(ddlStmt ddlStatement, offset int) string {
+	line := strings.Count(ddlStmt.stmt[:offset], "\n") + 1
+	if ddlStmt.file != "" {
+		return fmt.Sprintf(" (from %s:%d)", ddlStmt.file, line)
+	}
+	return fmt.Sprintf(" (line %d)", line)
+}
@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestValidateDDLStatement(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		ddlStmt          ddlStatement
+		expectedErrorMsg string
+	}{
+		{
+			name: "Valid event trigger",
+			ddlStmt: ddlStatement{
+				stmt: "CREATE EVENT TRIGGER log_ddl ON ddl_command_end EXECUTE FUNCTION log_ddl_command();",
+			},
+		},
+		{
+			name: "Deferrable constraint trigger",
+			ddlStmt: ddlStatement{
+				stmt: "CREATE CONSTRAINT TRIGGER check_foobar AFTER INSERT ON foobar DEFERRABLE INITIALLY DEFERRED FOR EACH ROW EXECUTE FUNCTION check_foobar();",
+			},
+		},
+		{
+			name: "Deferrable event trigger",
+			ddlStmt: ddlStatement{
+				stmt: "CREATE TABLE foobar();\nCREATE EVENT TRIGGER log_ddl ON ddl_command_end\n    DEFERRABLE INITIALLY DEFERRED\n    EXECUTE FUNCTION log_ddl_command();",
+			},
+			expectedErrorMsg: "event triggers do not support DEFERRABLE; found in trigger 'log_ddl' (line 2)",
+		},
+		{
+			name: "Deferrable event trigger with quoted name from file",
+			ddlStmt: ddlStatement{
+				stmt: `create event trigger "Log DDL" on ddl_command_end deferrable execute function log_ddl_command();`,
+				file: "schema/event_triggers.sql",
+			},
+			expectedErrorMsg: "event triggers do not support DEFERRABLE; found in trigger 'Log DDL' (from schema/event_triggers.sql:1)",
+		},
+		{
+			name: "Deferrable event trigger after a comment and a multi-byte character",
+			ddlStmt: ddlStatement{
+				stmt: "COMMENT ON SCHEMA public IS 'schéma';\n-- Log DDL commands\nCREATE EVENT TRIGGER Log_DDL ON ddl_command_end DEFERRABLE EXECUTE FUNCTION log_ddl_command();",
+			},
+			expectedErrorMsg: "event triggers do not support DEFERRABLE; found in trigger 'log_ddl' (line 3)",
+		},
+		{
+			name: "Other syntax errors are left to Postgres",
+			ddlStmt: ddlStatement{
+				stmt: "CREATE TABL foobar();",
+			},
+		},
+		{
+			name: "Deferrable in a later statement is not attributed to the event trigger",
+			ddlStmt: ddlStatement{
+				stmt: "CREATE EVENT TRIGGER log_ddl ON ddl_command_end EXECUTE FUNCTION log_ddl_command();\nCREATE TABLE foobar(id INT UNIQUE DEFERRABLE);",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateDDLStatement(tc.ddlStmt)
+			if len(tc.expectedErrorMsg) > 0 {
+				assert.EqualError(t, err, tc.expectedErrorMsg)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDDLSchemaSource_Validate(t *testing.T) {
+	assert.NoError(t, DDLSchemaSource([]string{
+		"CREATE EVENT TRIGGER log_ddl ON ddl_command_end EXECUTE FUNCTION log_ddl_command();",
+	}).Validate())
+	assert.EqualError(t, DDLSchemaSource([]string{
+		"CREATE TABLE foobar();",
+		"CREATE EVENT TRIGGER log_ddl ON ddl_command_end DEFERRABLE EXECUTE FUNCTION log_ddl_command();",
+	}).Validate(), "validating DDL: event triggers do not support DEFERRABLE; found in trigger 'log_ddl' (line 1)")
+}
+
+func TestSchemaSchemaSource(t *testing.T) {
+	currentSchema, err := schema.ParseDump(strings.NewReader(`
+CREATE TABLE public.foobar (
+    id integer NOT NULL
+);
+`))
+	require.NoError(t, err)
+	newSchema, err := schema.ParseDump(strings.NewReader(`
+CREATE TABLE public.foobar (
+    id integer NOT NULL,
+    val text
+);
+`))
+	require.NoError(t, err)
+
+	plan, err := Generate(context.Background(), SchemaSchemaSource(currentSchema), SchemaSchemaSource(newSchema), WithDoNotValidatePlan())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "public"."foobar" ADD COLUMN "val" text COLLATE "pg_catalog"."default"`,
+		`ANALYZE "public"."foobar"`,
+	}, getDDL(plan))
+}
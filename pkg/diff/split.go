@@ -0,0 +1,115 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	// tmpObjNameRegex matches the names of the temporary objects created by the plan, e.g., the temporary name of an
+	// index that is being replaced
+	tmpObjNameRegex = regexp.MustCompile(tmpObjNamePrefix + `[a-zA-Z0-9_$]*`)
+)
+
+// InvalidSplitError is returned by Plan.SplitAt if the plan cannot be split at the index
+type InvalidSplitError struct {
+	Index int
+	// NearestValidIndex is the nearest index the plan can be split at, or -1 if the plan cannot be split
+	NearestValidIndex int
+	Reason            string
+}
+
+func (e InvalidSplitError) Error() string {
+	if e.NearestValidIndex < 0 {
+		return fmt.Sprintf("cannot split plan at statement %d: %s. The plan cannot be split", e.Index, e.Reason)
+	}
+	return fmt.Sprintf("cannot split plan at statement %d: %s. The nearest valid split point is statement %d", e.Index, e.Reason, e.NearestValidIndex)
+}
+
+// SplitAt splits the plan into two plans, where the first plan contains the statements before stmtIndex and the second
+// plan contains the statements from stmtIndex onward, e.g., to run a large migration over multiple maintenance
+// windows. The second plan must be executed after the first plan.
+//
+// Statements only depend on earlier statements, so dependencies that cross the split point are satisfied by running the
+// first plan before the second plan. However, the plan cannot be split between statements that share a temporary
+// object, e.g., an index is renamed to a temporary name, re-created, and then the temporary index is dropped. If the
+// plan cannot be split at stmtIndex, an InvalidSplitError containing the nearest valid split point is returned.
+//
+// The second plan's CurrentSchemaHash is empty, since the schema after the first plan is applied is unknown. Rename
+// candidates cannot be confirmed on the split plans.
+func (p Plan) SplitAt(stmtIndex int) (Plan, Plan, error) {
+	if stmtIndex <= 0 || stmtIndex >= len(p.Statements) {
+		return Plan{}, Plan{}, fmt.Errorf("index must be > 0 and < %d", len(p.Statements))
+	}
+	if reason, ok := p.canSplitAt(stmtIndex); !ok {
+		return Plan{}, Plan{}, InvalidSplitError{
+			Index:             stmtIndex,
+			NearestValidIndex: p.nearestValidSplitIndex(stmtIndex),
+			Reason:            reason,
+		}
+	}
+
+	first := p
+	first.Statements = append([]Statement(nil), p.Statements[:stmtIndex]...)
+	second := p
+	second.Statements = append([]Statement(nil), p.Statements[stmtIndex:]...)
+	second.CurrentSchemaHash = ""
+	// The hazards of the plan itself are only reported once
+	second.Hazards = nil
+	if p.Dependencies != nil {
+		first.Dependencies = []StatementDependency{}
+		second.Dependencies = []StatementDependency{}
+		for _, dep := range p.Dependencies {
+			if dep.Statement < stmtIndex && dep.DependsOn < stmtIndex {
+				first.Dependencies = append(first.Dependencies, dep)
+			} else if dep.Statement >= stmtIndex && dep.DependsOn >= stmtIndex {
+				second.Dependencies = append(second.Dependencies, StatementDependency{
+					Statement: dep.Statement - stmtIndex,
+					DependsOn: dep.DependsOn - stmtIndex,
+				})
+			}
+		}
+	}
+	for _, plan := range []*Plan{&first, &second} {
+		plan.RenameCandidates = nil
+		plan.ColumnRenameCandidates = nil
+		plan.renameState = nil
+	}
+	// The second plan runs against the schema produced by the first plan, so the current schema no longer describes it
+	second.renderState = nil
+	return first, second, nil
+}
+
+// canSplitAt returns whether the plan can be split at the index and, if not, the reason
+func (p Plan) canSplitAt(stmtIndex int) (string, bool) {
+	firstIdxByTmpObj := make(map[string]int)
+	for i, stmt := range p.Statements {
+		for _, tmpObj := range tmpObjNameRegex.FindAllString(stmt.DDL, -1) {
+			firstIdx, ok := firstIdxByTmpObj[tmpObj]
+			if !ok {
+				firstIdxByTmpObj[tmpObj] = i
+				continue
+			}
+			if firstIdx < stmtIndex && i >= stmtIndex {
+				return fmt.Sprintf("statements %d and %d share the temporary object %s", firstIdx, i, tmpObj), false
+			}
+		}
+	}
+	return "", true
+}
+
+// nearestValidSplitIndex returns the valid split index nearest to the index, preferring the earlier index if two are
+// equally near. It returns -1 if the plan cannot be split.
+func (p Plan) nearestValidSplitIndex(stmtIndex int) int {
+	for distance := 1; distance < len(p.Statements); distance++ {
+		for _, idx := range []int{stmtIndex - distance, stmtIndex + distance} {
+			if idx <= 0 || idx >= len(p.Statements) {
+				continue
+			}
+			if _, ok := p.canSplitAt(idx); ok {
+				return idx
+			}
+		}
+	}
+	return -1
+}
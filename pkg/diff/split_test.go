@@ -0,0 +1,190 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestSplitAt(t *testing.T) {
+	plan := Plan{
+		Statements: []Statement{
+			{DDL: `ALTER TABLE "public"."foobar" ADD COLUMN "bar" text`},
+			{DDL: `ALTER INDEX "public"."foo_idx" RENAME TO "pgschemadiff_tmpidx_foo_idx_abc"`},
+			{DDL: `CREATE INDEX CONCURRENTLY foo_idx ON public.foobar USING btree (foo, bar)`},
+			{DDL: `DROP INDEX CONCURRENTLY "public"."pgschemadiff_tmpidx_foo_idx_abc"`},
+			{DDL: `ALTER TABLE "public"."foobar" DROP COLUMN "baz"`},
+		},
+		CurrentSchemaHash: "some-hash",
+		Dependencies: []StatementDependency{
+			{Statement: 2, DependsOn: 0},
+			{Statement: 2, DependsOn: 1},
+			{Statement: 3, DependsOn: 2},
+			{Statement: 4, DependsOn: 3},
+		},
+		RenameCandidates: []RenameCandidate{{}},
+	}
+
+	first, second, err := plan.SplitAt(1)
+	require.NoError(t, err)
+	assert.Equal(t, Plan{
+		Statements:        plan.Statements[:1],
+		CurrentSchemaHash: "some-hash",
+		Dependencies:      []StatementDependency{},
+	}, first)
+	assert.Equal(t, Plan{
+		Statements: plan.Statements[1:],
+		Dependencies: []StatementDependency{
+			{Statement: 1, DependsOn: 0},
+			{Statement: 2, DependsOn: 1},
+			{Statement: 3, DependsOn: 2},
+		},
+	}, second)
+
+	first, second, err = plan.SplitAt(4)
+	require.NoError(t, err)
+	assert.Len(t, first.Statements, 4)
+	assert.Equal(t, []StatementDependency{{Statement: 2, DependsOn: 0}, {Statement: 2, DependsOn: 1}, {Statement: 3, DependsOn: 2}}, first.Dependencies)
+	assert.Len(t, second.Statements, 1)
+	assert.Empty(t, second.Dependencies)
+
+	t.Run("Temporary objects cannot be split", func(t *testing.T) {
+		_, _, err := plan.SplitAt(3)
+		var splitErr InvalidSplitError
+		require.ErrorAs(t, err, &splitErr)
+		assert.Equal(t, 4, splitErr.NearestValidIndex)
+		assert.ErrorContains(t, err, "statements 1 and 3 share the temporary object pgschemadiff_tmpidx_foo_idx_abc")
+
+		_, _, err = plan.SplitAt(2)
+		require.ErrorAs(t, err, &splitErr)
+		// Both are equally near, so the earlier one is preferred
+		assert.Equal(t, 1, splitErr.NearestValidIndex)
+	})
+
+	t.Run("Generated plans that share a temporary object are refused", func(t *testing.T) {
+		foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+		table := schema.Table{
+			SchemaQualifiedName: foobar,
+			Columns: []schema.Column{
+				{Name: "foo", Type: "text", IsNullable: true},
+				{Name: "bar", Type: "text", IsNullable: true},
+			},
+			ReplicaIdentity: schema.ReplicaIdentityDefault,
+		}
+		oldSchema := schema.Schema{
+			Tables: []schema.Table{table},
+			Indexes: []schema.Index{{
+				OwningTable:     foobar,
+				Name:            "foobar_idx",
+				Columns:         []string{"foo"},
+				GetIndexDefStmt: "CREATE INDEX foobar_idx ON public.foobar USING btree (foo)",
+			}},
+		}
+		newSchema := schema.Schema{
+			Tables: []schema.Table{table},
+			Indexes: []schema.Index{{
+				OwningTable:     foobar,
+				Name:            "foobar_idx",
+				Columns:         []string{"foo", "bar"},
+				GetIndexDefStmt: "CREATE INDEX foobar_idx ON public.foobar USING btree (foo, bar)",
+			}},
+		}
+		// The index is renamed to a temporary name, re-created, and then the temporary index is dropped
+		plan, err := buildPlan(oldSchema, newSchema, &planOptions{})
+		require.NoError(t, err)
+		require.Len(t, plan.Statements, 3)
+
+		for _, stmtIndex := range []int{1, 2} {
+			_, _, err := plan.SplitAt(stmtIndex)
+			var splitErr InvalidSplitError
+			require.ErrorAs(t, err, &splitErr)
+			assert.Equal(t, -1, splitErr.NearestValidIndex)
+			assert.ErrorContains(t, err, "share the temporary object pgschemadiff_tmpidx_foobar_idx_")
+		}
+	})
+
+	t.Run("Dependencies across the split point do not prevent a split", func(t *testing.T) {
+		// The second plan runs after the first plan, so statements in the second plan can depend on statements in the
+		// first plan
+		plan := Plan{
+			Statements:   []Statement{{DDL: "SELECT 1"}, {DDL: "SELECT 2"}, {DDL: "SELECT 3"}},
+			Dependencies: []StatementDependency{{Statement: 1, DependsOn: 0}, {Statement: 2, DependsOn: 1}},
+		}
+		first, second, err := plan.SplitAt(1)
+		require.NoError(t, err)
+		assert.Empty(t, first.Dependencies)
+		assert.Equal(t, []StatementDependency{{Statement: 1, DependsOn: 0}}, second.Dependencies)
+	})
+
+	t.Run("Plans that cannot be split", func(t *testing.T) {
+		_, _, err := plan.SplitAt(0)
+		assert.ErrorContains(t, err, "index must be > 0 and < 5")
+
+		plan := Plan{Statements: plan.Statements[1:4]}
+		_, _, err = plan.SplitAt(1)
+		var splitErr InvalidSplitError
+		require.ErrorAs(t, err, &splitErr)
+		assert.Equal(t, -1, splitErr.NearestValidIndex)
+	})
+
+	t.Run("Plans without dependencies", func(t *testing.T) {
+		plan := Plan{Statements: []Statement{{DDL: "SELECT 1"}, {DDL: "SELECT 2"}}}
+		first, second, err := plan.SplitAt(1)
+		require.NoError(t, err)
+		assert.Nil(t, first.Dependencies)
+		assert.Nil(t, second.Dependencies)
+	})
+}
+
+func (suite *planGeneratorTestSuite) TestSplitAt_SameEndStateAsOriginalPlan() {
+	initialDDL := `
+		CREATE TABLE foobar(id INT PRIMARY KEY, foo TEXT, bar TEXT);
+		CREATE INDEX foobar_foo_idx ON foobar(foo);
+	`
+	newDDL := `
+		CREATE TABLE foobar(id INT PRIMARY KEY, foo TEXT, bar TEXT NOT NULL, baz INT);
+		CREATE INDEX foobar_foo_idx ON foobar(foo, bar);
+		CREATE TABLE other(id INT PRIMARY KEY, foobar_id INT REFERENCES foobar(id));
+	`
+	suite.mustApplyDDLToTestDb([]string{initialDDL})
+	splitDB := suite.mustGetTestDBPool()
+	defer splitDB.Close()
+
+	originalTestDB, err := suite.pgEngine.CreateDatabase()
+	suite.Require().NoError(err)
+	defer originalTestDB.DropDB()
+	originalDB, err := sql.Open("pgx", originalTestDB.GetDSN())
+	suite.Require().NoError(err)
+	defer originalDB.Close()
+	_, err = originalDB.Exec(initialDDL)
+	suite.Require().NoError(err)
+
+	tempDbFactory := suite.mustBuildTempDbFactory(context.Background())
+	defer tempDbFactory.Close()
+	plan, err := Generate(context.Background(), DBSchemaSource(splitDB), DDLSchemaSource([]string{newDDL}), WithTempDbFactory(tempDbFactory))
+	suite.Require().NoError(err)
+	suite.Require().Greater(len(plan.Statements), 2)
+
+	first, second, err := plan.SplitAt(len(plan.Statements) / 2)
+	var splitErr InvalidSplitError
+	if errors.As(err, &splitErr) {
+		suite.Require().GreaterOrEqual(splitErr.NearestValidIndex, 0)
+		first, second, err = plan.SplitAt(splitErr.NearestValidIndex)
+	}
+	suite.Require().NoError(err)
+	suite.mustApplyMigrationPlan(splitDB, first)
+	suite.mustApplyMigrationPlan(splitDB, second)
+
+	suite.mustApplyMigrationPlan(originalDB, plan)
+
+	splitSchema, err := schema.GetSchema(context.Background(), splitDB)
+	suite.Require().NoError(err)
+	originalSchema, err := schema.GetSchema(context.Background(), originalDB)
+	suite.Require().NoError(err)
+	suite.Equal(originalSchema, splitSchema)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,6 +32,22 @@ const (
 	statementTimeoutTableDrop = 20 * time.Minute
 	// statementTimeoutAnalyzeColumn is the statement timeout for analyzing the column of a table
 	statementTimeoutAnalyzeColumn = 20 * time.Minute
+	// statementTimeoutAnalyzeTable is the statement timeout for analyzing an entire table
+	statementTimeoutAnalyzeTable = 20 * time.Minute
+	// statementTimeoutVacuumTable is the statement timeout for vacuuming an entire table
+	statementTimeoutVacuumTable = 20 * time.Minute
+	// statementTimeoutMaterializedViewBuild is the statement timeout for populating materialized views and building
+	// their indexes. It may take a while to run the materialized view's query
+	statementTimeoutMaterializedViewBuild = 20 * time.Minute
+	// statementTimeoutDetachPartitionConcurrently is the statement timeout for concurrently detaching a partition. It
+	// waits for all transactions that might be using the partition to complete, so give it a long timeout
+	statementTimeoutDetachPartitionConcurrently = 20 * time.Minute
+	// statementTimeoutAttachPartition is the statement timeout for re-attaching a partition with new bounds. The
+	// partition is scanned to validate its new bounds, which may take a while on large partitions
+	statementTimeoutAttachPartition = 20 * time.Minute
+	// statementTimeoutSetTablespace is the statement timeout for moving a table or index to a different tablespace. All
+	// of its data is copied, which may take a while on large tables
+	statementTimeoutSetTablespace = 20 * time.Minute
 
 	tmpObjNamePrefix = "pgschemadiff_tmp"
 )
@@ -45,6 +62,28 @@ var (
 		Message: "Dropping this index means queries that use this index might perform worse because " +
 			"they will no longer will be able to leverage it.",
 	}
+	migrationHazardPrimaryKeyDropped = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "Dropping the only primary key of this table might break logical replication, which relies on the " +
+			"primary key to identify rows. Replication slots and subscriptions cannot be tracked.",
+	}
+	migrationHazardIndexBuildConcurrently = MigrationHazard{
+		Type: MigrationHazardTypeIndexBuild,
+		Message: "This might affect database performance. " +
+			"Concurrent index builds require a non-trivial amount of CPU, potentially affecting database performance. " +
+			"They also can take a while but do not lock out writes.",
+	}
+	migrationHazardIndexBuildNonConcurrently = MigrationHazard{
+		Type: MigrationHazardTypeIndexBuild,
+		Message: "This might affect database performance. " +
+			"Index builds require a non-trivial amount of CPU, potentially affecting database performance. " +
+			"They also can take a while.",
+	}
+	migrationHazardIndexBuildAcquiresShareLock = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresShareLock,
+		Message: "Non-concurrent index builds lock out writes to the table until the index is built. This can take a " +
+			"while on large tables.",
+	}
 	migrationHazardIndexDroppedAcquiresLock = MigrationHazard{
 		Type:    MigrationHazardTypeAcquiresAccessExclusiveLock,
 		Message: "Index drops will lock out all accesses to the table. They should be fast",
@@ -53,6 +92,71 @@ var (
 		Type:    MigrationHazardTypeHasUntrackableDependencies,
 		Message: "This sequence has no owner, so it cannot be tracked. It may be in use by a table or function.",
 	}
+	migrationHazardSequenceIncrementOrCacheChanged = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "Changing the increment or cache size of a sequence changes the values returned by nextval for " +
+			"in-flight sessions. Code that relies on the spacing of the values cannot be tracked and might break.",
+	}
+	migrationHazardSequenceStartValueChanged = MigrationHazard{
+		Type: MigrationHazardTypeCorrectness,
+		Message: "Changing the start value of a sequence does not change its current value. If the sequence must be " +
+			"resynced, run setval or ALTER SEQUENCE ... RESTART after the migration.",
+	}
+	migrationHazardTriggerFunctionDependenciesUntrackable = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "Dependencies, i.e. the tables and columns used in the function body, of non-sql trigger functions " +
+			"cannot be tracked. As a result, the trigger might fail when it fires if the objects its function " +
+			"references are altered or dropped.",
+	}
+	migrationHazardGeneratedColumnAdded = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresAccessExclusiveLock,
+		Message: "Adding a stored generated column rewrites the table to compute the column's values. This locks out " +
+			"reads and writes to the table until the rewrite completes, which can take a while on large tables.",
+	}
+	migrationHazardPartitionDroppedAcquiresLock = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresAccessExclusiveLock,
+		Message: "The partitioned table has a default partition, so the partition cannot be detached concurrently " +
+			"before it is dropped. Dropping the partition will lock out all accesses to the partitioned table. It should be fast.",
+	}
+	migrationHazardPartitionAttachScansDefaultPartition = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresAccessExclusiveLock,
+		Message: "The partitioned table has a default partition, which is scanned to validate that none of its rows " +
+			"belong in the attached partition. This locks out all accesses to the default partition until the scan completes.",
+	}
+	migrationHazardHashPartitionBoundsChanged = MigrationHazard{
+		Type: MigrationHazardTypeCorrectness,
+		Message: "Changing the modulus or remainder of a hash partition does not move rows between partitions. " +
+			"Re-attaching the partition fails if any of its rows do not satisfy the new bounds, so the table usually " +
+			"must be re-partitioned by copying its rows into a new partitioned table instead.",
+	}
+	migrationHazardPartitionDetached = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresAccessExclusiveLock,
+		Message: "Detaching the partition locks out all accesses to the partitioned table. It should be fast. Until the " +
+			"partition is re-attached, its rows are not visible through the partitioned table, and rows inserted for its " +
+			"bounds are routed to the default partition or rejected.",
+	}
+	migrationHazardPartitionAttachValidatesBounds = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresAccessExclusiveLock,
+		Message: "Attaching the partition scans it to validate that its rows satisfy the new bounds, which locks out all " +
+			"accesses to the partition until the scan completes. If the partitioned table has a default partition, " +
+			"the default partition is also scanned.",
+	}
+	migrationHazardColumnAddedWithVolatileDefault = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresAccessExclusiveLock,
+		Message: "Adding a column with a volatile default, e.g., nextval() or random(), rewrites the table to evaluate " +
+			"the default for every existing row. This locks out reads and writes to the table until the rewrite " +
+			"completes, which can take a while on large tables.",
+	}
+	migrationHazardColumnIdentityAltered = MigrationHazard{
+		Type:    MigrationHazardTypeAcquiresAccessExclusiveLock,
+		Message: "Altering the identity of a column will lock out all accesses to the table. It should be fast",
+	}
+	migrationHazardColumnIdentityAdded = MigrationHazard{
+		Type: MigrationHazardTypeCorrectness,
+		Message: "The identity's sequence starts at its start value regardless of the values already in the column. " +
+			"Inserts that use the identity might conflict with existing values until the sequence is advanced past " +
+			"them, e.g., via ALTER TABLE ... ALTER COLUMN ... RESTART WITH.",
+	}
 	migrationHazardExtensionDroppedCannotTrackDependencies = MigrationHazard{
 		Type:    MigrationHazardTypeHasUntrackableDependencies,
 		Message: "This extension may be in use by tables, indexes, functions, triggers, etc. This statement will be ran last, so this may be OK.",
@@ -61,6 +165,18 @@ var (
 		Type:    MigrationHazardTypeExtensionVersionUpgrade,
 		Message: "This extension's version is being upgraded. Be sure the newer version is backwards compatible with your use case.",
 	}
+	migrationHazardValidateConstraint = MigrationHazard{
+		Type: MigrationHazardTypeValidateConstraint,
+		Message: "This will scan all rows of the owning table to validate the constraint. Validating only acquires a " +
+			"SHARE UPDATE EXCLUSIVE lock, so reads and writes are not blocked, but other schema changes and vacuums of " +
+			"the table are blocked until the validation completes. If any existing row violates the constraint, the " +
+			"validation fails.",
+	}
+	migrationHazardExtensionAlteredVersionDowngraded = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "This extension's version is being downgraded. Objects that depend on functionality added in the newer " +
+			"version, e.g., functions, types, and operators, are not tracked and might break.",
+	}
 )
 
 type oldAndNew[S any] struct {
@@ -85,10 +201,42 @@ type (
 		oldAndNew[schema.Enum]
 	}
 
+	domainDiff struct {
+		oldAndNew[schema.Domain]
+	}
+
+	compositeTypeDiff struct {
+		oldAndNew[schema.CompositeType]
+	}
+
+	textSearchDictionaryDiff struct {
+		oldAndNew[schema.TextSearchDictionary]
+	}
+
+	textSearchConfigDiff struct {
+		oldAndNew[schema.TextSearchConfig]
+	}
+
 	extensionDiff struct {
 		oldAndNew[schema.Extension]
 	}
 
+	collationDiff struct {
+		oldAndNew[schema.Collation]
+	}
+
+	foreignDataWrapperDiff struct {
+		oldAndNew[schema.ForeignDataWrapper]
+	}
+
+	foreignServerDiff struct {
+		oldAndNew[schema.ForeignServer]
+	}
+
+	foreignTableDiff struct {
+		oldAndNew[schema.ForeignTable]
+	}
+
 	columnDiff struct {
 		oldAndNew[schema.Column]
 		oldOrdering int
@@ -105,15 +253,23 @@ type (
 		checkConstraintDiff listDiff[schema.CheckConstraint, checkConstraintDiff]
 		policiesDiff        listDiff[schema.Policy, policyDiff]
 	}
-	
+
 	viewDiff struct {
 		oldAndNew[schema.View]
 	}
 
+	materializedViewDiff struct {
+		oldAndNew[schema.MaterializedView]
+	}
+
 	indexDiff struct {
 		oldAndNew[schema.Index]
 	}
 
+	statisticsObjectDiff struct {
+		oldAndNew[schema.StatisticsObject]
+	}
+
 	foreignKeyConstraintDiff struct {
 		oldAndNew[schema.ForeignKeyConstraint]
 	}
@@ -130,33 +286,80 @@ type (
 		oldAndNew[schema.Procedure]
 	}
 
+	aggregateDiff struct {
+		oldAndNew[schema.Aggregate]
+	}
+
 	triggerDiff struct {
 		oldAndNew[schema.Trigger]
 	}
-	
+
 	eventTriggerDiff struct {
 		oldAndNew[schema.EventTrigger]
 	}
+
+	operatorDiff struct {
+		oldAndNew[schema.Operator]
+	}
+
+	operatorClassDiff struct {
+		oldAndNew[schema.OperatorClass]
+	}
+
+	publicationDiff struct {
+		oldAndNew[schema.Publication]
+	}
+
+	privilegeDiff struct {
+		oldAndNew[schema.Privilege]
+	}
+
+	defaultPrivilegeDiff struct {
+		oldAndNew[schema.DefaultPrivilege]
+	}
 )
 
 type schemaDiff struct {
 	oldAndNew[schema.Schema]
 	namedSchemaDiffs          listDiff[schema.NamedSchema, namedSchemaDiff]
 	extensionDiffs            listDiff[schema.Extension, extensionDiff]
+	collationDiffs            listDiff[schema.Collation, collationDiff]
+	foreignDataWrapperDiffs   listDiff[schema.ForeignDataWrapper, foreignDataWrapperDiff]
+	foreignServerDiffs        listDiff[schema.ForeignServer, foreignServerDiff]
 	enumDiffs                 listDiff[schema.Enum, enumDiff]
+	domainDiffs               listDiff[schema.Domain, domainDiff]
+	compositeTypeDiffs        listDiff[schema.CompositeType, compositeTypeDiff]
+	textSearchDictionaryDiffs listDiff[schema.TextSearchDictionary, textSearchDictionaryDiff]
+	textSearchConfigDiffs     listDiff[schema.TextSearchConfig, textSearchConfigDiff]
 	tableDiffs                listDiff[schema.Table, tableDiff]
+	foreignTableDiffs         listDiff[schema.ForeignTable, foreignTableDiff]
 	viewDiffs                 listDiff[schema.View, viewDiff]
+	materializedViewDiffs     listDiff[schema.MaterializedView, materializedViewDiff]
 	indexDiffs                listDiff[schema.Index, indexDiff]
+	statisticsObjectDiffs     listDiff[schema.StatisticsObject, statisticsObjectDiff]
 	foreignKeyConstraintDiffs listDiff[schema.ForeignKeyConstraint, foreignKeyConstraintDiff]
 	sequenceDiffs             listDiff[schema.Sequence, sequenceDiff]
 	functionDiffs             listDiff[schema.Function, functionDiff]
 	proceduresDiffs           listDiff[schema.Procedure, procedureDiff]
+	aggregateDiffs            listDiff[schema.Aggregate, aggregateDiff]
 	triggerDiffs              listDiff[schema.Trigger, triggerDiff]
 	eventTriggerDiffs         listDiff[schema.EventTrigger, eventTriggerDiff]
+	operatorDiffs             listDiff[schema.Operator, operatorDiff]
+	operatorClassDiffs        listDiff[schema.OperatorClass, operatorClassDiff]
+	publicationDiffs          listDiff[schema.Publication, publicationDiff]
+	privilegeDiffs            listDiff[schema.Privilege, privilegeDiff]
+	defaultPrivilegeDiffs     listDiff[schema.DefaultPrivilege, defaultPrivilegeDiff]
 }
 
-func (sd schemaDiff) resolveToSQL() ([]Statement, error) {
-	return schemaSQLGenerator{}.Alter(sd)
+func (sd schemaDiff) resolveToSQL(nonConcurrentIndexOps, nonConcurrentIndexDrops bool, constraintValidation constraintValidationOptions, onlineColumnTypeChange, omitAdvisoryAnalyze bool, additionalVertexGenerators []VertexGenerator) ([]Statement, []StatementDependency, error) {
+	return schemaSQLGenerator{
+		nonConcurrentIndexOps:      nonConcurrentIndexOps,
+		nonConcurrentIndexDrops:    nonConcurrentIndexDrops,
+		constraintValidation:       constraintValidation,
+		onlineColumnTypeChange:     onlineColumnTypeChange,
+		omitAdvisoryAnalyze:        omitAdvisoryAnalyze,
+		additionalVertexGenerators: additionalVertexGenerators,
+	}.alterWithDependencies(sd)
 }
 
 // The procedure for DIFFING schemas and GENERATING/RESOLVING the SQL required to migrate the old schema to the new schema is
@@ -224,6 +427,42 @@ func buildSchemaDiff(old, new schema.Schema) (schemaDiff, bool, error) {
 		return schemaDiff{}, false, fmt.Errorf("diffing extensions: %w", err)
 	}
 
+	collationDiffs, err := diffLists(old.Collations, new.Collations, func(old, new schema.Collation, _, _ int) (collationDiff, bool, error) {
+		return collationDiff{
+			oldAndNew[schema.Collation]{
+				old: old,
+				new: new,
+			},
+		}, false, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing collations: %w", err)
+	}
+
+	foreignDataWrapperDiffs, err := diffLists(old.ForeignDataWrappers, new.ForeignDataWrappers, func(old, new schema.ForeignDataWrapper, _, _ int) (foreignDataWrapperDiff, bool, error) {
+		return foreignDataWrapperDiff{
+			oldAndNew[schema.ForeignDataWrapper]{
+				old: old,
+				new: new,
+			},
+		}, false, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing foreign data wrappers: %w", err)
+	}
+
+	foreignServerDiffs, err := diffLists(old.ForeignServers, new.ForeignServers, func(old, new schema.ForeignServer, _, _ int) (foreignServerDiff, bool, error) {
+		return foreignServerDiff{
+			oldAndNew[schema.ForeignServer]{
+				old: old,
+				new: new,
+			},
+		}, false, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing foreign servers: %w", err)
+	}
+
 	enumDiffs, err := diffLists(old.Enums, new.Enums, func(old, new schema.Enum, _, _ int) (enumDiff, bool, error) {
 		return enumDiff{
 			oldAndNew[schema.Enum]{
@@ -236,11 +475,64 @@ func buildSchemaDiff(old, new schema.Schema) (schemaDiff, bool, error) {
 		return schemaDiff{}, false, fmt.Errorf("diffing enums: %w", err)
 	}
 
+	domainDiffs, err := diffLists(old.Domains, new.Domains, func(old, new schema.Domain, _, _ int) (domainDiff, bool, error) {
+		return domainDiff{
+			oldAndNew[schema.Domain]{
+				old: old,
+				new: new,
+			},
+		}, false, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing domains: %w", err)
+	}
+
+	compositeTypeDiffs, err := diffLists(old.CompositeTypes, new.CompositeTypes, func(old, new schema.CompositeType, _, _ int) (compositeTypeDiff, bool, error) {
+		return compositeTypeDiff{
+			oldAndNew[schema.CompositeType]{
+				old: old,
+				new: new,
+			},
+		}, false, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing composite types: %w", err)
+	}
+
+	textSearchDictionaryDiffs, err := diffLists(old.TextSearchDictionaries, new.TextSearchDictionaries, func(old, new schema.TextSearchDictionary, _, _ int) (textSearchDictionaryDiff, bool, error) {
+		return textSearchDictionaryDiff{
+			oldAndNew[schema.TextSearchDictionary]{
+				old: old,
+				new: new,
+			},
+		}, false, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing text search dictionaries: %w", err)
+	}
+
+	textSearchConfigDiffs, err := diffLists(old.TextSearchConfigs, new.TextSearchConfigs, func(old, new schema.TextSearchConfig, _, _ int) (textSearchConfigDiff, bool, error) {
+		return textSearchConfigDiff{
+			oldAndNew[schema.TextSearchConfig]{
+				old: old,
+				new: new,
+			},
+		}, false, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing text search configs: %w", err)
+	}
+
 	tableDiffs, err := diffLists(old.Tables, new.Tables, buildTableDiff)
 	if err != nil {
 		return schemaDiff{}, false, fmt.Errorf("diffing tables: %w", err)
 	}
-	
+
+	foreignTableDiffs, err := diffLists(old.ForeignTables, new.ForeignTables, buildForeignTableDiff)
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing foreign tables: %w", err)
+	}
+
 	viewDiffs, err := diffLists(old.Views, new.Views, func(old, new schema.View, _, _ int) (viewDiff, bool, error) {
 		return viewDiff{
 			oldAndNew[schema.View]{
@@ -253,6 +545,49 @@ func buildSchemaDiff(old, new schema.Schema) (schemaDiff, bool, error) {
 		return schemaDiff{}, false, fmt.Errorf("diffing views: %w", err)
 	}
 
+	operatorDiffs, err := diffLists(old.Operators, new.Operators, func(old, new schema.Operator, _, _ int) (operatorDiff, bool, error) {
+		// Operators cannot be meaningfully altered, so they must be re-created if they have changed
+		return operatorDiff{
+			oldAndNew[schema.Operator]{
+				old: old,
+				new: new,
+			},
+		}, !cmp.Equal(old, new), nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing operators: %w", err)
+	}
+
+	deletedOperatorsByName := buildSchemaObjByNameMap(operatorDiffs.deletes)
+	operatorClassDiffs, err := diffLists(old.OperatorClasses, new.OperatorClasses, func(old, new schema.OperatorClass, _, _ int) (operatorClassDiff, bool, error) {
+		// Operator classes cannot be meaningfully altered, so they must be re-created if they have changed. An
+		// operator class must also be re-created if any of its operators are re-created, since the operators
+		// cannot be dropped while they are members of the operator class.
+		requiresRecreation := !cmp.Equal(old, new)
+		for _, operator := range old.DependsOnOperators {
+			if _, isDeleted := deletedOperatorsByName[operator.GetName()]; isDeleted {
+				requiresRecreation = true
+			}
+		}
+		return operatorClassDiff{
+			oldAndNew[schema.OperatorClass]{
+				old: old,
+				new: new,
+			},
+		}, requiresRecreation, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing operator classes: %w", err)
+	}
+
+	deletedOperatorClassesByName := buildSchemaObjByNameMap(operatorClassDiffs.deletes)
+	materializedViewDiffs, err := diffLists(old.MaterializedViews, new.MaterializedViews, func(old, new schema.MaterializedView, _, _ int) (materializedViewDiff, bool, error) {
+		return buildMaterializedViewDiff(deletedOperatorClassesByName, old, new)
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing materialized views: %w", err)
+	}
+
 	newSchemaTablesByName := buildSchemaObjByNameMap(new.Tables)
 	addedTablesByName := buildSchemaObjByNameMap(tableDiffs.adds)
 	indexesDiff, err := diffLists(old.Indexes, new.Indexes, func(oldIndex, newIndex schema.Index, _, _ int) (indexDiff, bool, error) {
@@ -261,13 +596,37 @@ func buildSchemaDiff(old, new schema.Schema) (schemaDiff, bool, error) {
 			addedTablesByName:      addedTablesByName,
 			oldSchemaIndexesByName: buildSchemaObjByNameMap(old.Indexes),
 			newSchemaIndexesByName: buildSchemaObjByNameMap(new.Indexes),
+
+			deletedOperatorClassesByName: deletedOperatorClassesByName,
 		}, oldIndex, newIndex)
 	})
 	if err != nil {
 		return schemaDiff{}, false, fmt.Errorf("diffing indexes: %w", err)
 	}
 
-	fsg := newForeignKeyConstraintSQLVertexGenerator(oldAndNew[schema.Schema]{old: old, new: new}, tableDiffs)
+	statisticsObjectDiffs, err := diffLists(old.StatisticsObjects, new.StatisticsObjects, func(old, new schema.StatisticsObject, _, _ int) (statisticsObjectDiff, bool, error) {
+		if _, isOnNewTable := addedTablesByName[new.Table.GetName()]; isOnNewTable {
+			// The statistics object must be re-created if its table is re-created
+			return statisticsObjectDiff{}, true, nil
+		}
+		// Only the statistics target of a statistics object can be altered
+		oldCopy := old
+		oldCopy.StatisticsTarget = new.StatisticsTarget
+		if !cmp.Equal(oldCopy, new) {
+			return statisticsObjectDiff{}, true, nil
+		}
+		return statisticsObjectDiff{
+			oldAndNew[schema.StatisticsObject]{
+				old: old,
+				new: new,
+			},
+		}, false, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing statistics objects: %w", err)
+	}
+
+	fsg := newForeignKeyConstraintSQLVertexGenerator(oldAndNew[schema.Schema]{old: old, new: new}, tableDiffs, constraintValidationOptions{})
 	foreignKeyConstraintDiffs, err := diffLists(old.ForeignKeyConstraints, new.ForeignKeyConstraints, func(old, new schema.ForeignKeyConstraint, _, _ int) (foreignKeyConstraintDiff, bool, error) {
 		return buildForeignKeyConstraintDiff(fsg, addedTablesByName, old, new)
 	})
@@ -297,12 +656,14 @@ func buildSchemaDiff(old, new schema.Schema) (schemaDiff, bool, error) {
 	}
 
 	functionDiffs, err := diffLists(old.Functions, new.Functions, func(old, new schema.Function, _, _ int) (functionDiff, bool, error) {
+		// Postgres does not allow CREATE OR REPLACE to change whether a function is a window function, so the
+		// function must be re-created
 		return functionDiff{
 			oldAndNew[schema.Function]{
 				old: old,
 				new: new,
 			},
-		}, false, nil
+		}, old.IsWindow != new.IsWindow, nil
 	})
 	if err != nil {
 		return schemaDiff{}, false, fmt.Errorf("diffing functions: %w", err)
@@ -320,6 +681,19 @@ func buildSchemaDiff(old, new schema.Schema) (schemaDiff, bool, error) {
 		return schemaDiff{}, false, fmt.Errorf("diffing procedures: %w", err)
 	}
 
+	aggregateDiffs, err := diffLists(old.Aggregates, new.Aggregates, func(old, new schema.Aggregate, _, _ int) (aggregateDiff, bool, error) {
+		// Aggregates cannot be replaced, so they must be re-created if they have changed
+		return aggregateDiff{
+			oldAndNew[schema.Aggregate]{
+				old: old,
+				new: new,
+			},
+		}, !cmp.Equal(old, new), nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing aggregates: %w", err)
+	}
+
 	triggerDiffs, err := diffLists(old.Triggers, new.Triggers, func(old, new schema.Trigger, _, _ int) (triggerDiff, bool, error) {
 		if _, isOnNewTable := addedTablesByName[new.OwningTable.GetName()]; isOnNewTable {
 			// If the table is new, then it must be re-created (this occurs if the base table has been
@@ -349,6 +723,83 @@ func buildSchemaDiff(old, new schema.Schema) (schemaDiff, bool, error) {
 		return schemaDiff{}, false, fmt.Errorf("diffing event triggers: %w", err)
 	}
 
+	deletedTablesByName := buildSchemaObjByNameMap(tableDiffs.deletes)
+	publicationDiffs, err := diffLists(old.Publications, new.Publications, func(old, new schema.Publication, _, _ int) (publicationDiff, bool, error) {
+		// Postgres does not support altering a publication to or from FOR ALL TABLES. A publication must also be
+		// re-created if any of its tables are re-created, since re-creating a table removes it from the publication.
+		requiresRecreation := old.AllTables != new.AllTables
+		for _, table := range new.Tables {
+			if _, isDeleted := deletedTablesByName[table.GetName()]; isDeleted {
+				requiresRecreation = true
+			}
+		}
+		return publicationDiff{
+			oldAndNew[schema.Publication]{
+				old: old,
+				new: new,
+			},
+		}, requiresRecreation, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing publications: %w", err)
+	}
+
+	// Dropping an object drops its privileges. Views are re-created when they are altered, so their privileges are
+	// also dropped
+	droppedObjectNames := make(map[string]bool)
+	for _, names := range [][]string{
+		getObjectNames(tableDiffs.deletes),
+		getObjectNames(foreignTableDiffs.deletes),
+		getObjectNames(viewDiffs.deletes),
+		getObjectNames(materializedViewDiffs.deletes),
+		getObjectNames(sequencesDiffs.deletes),
+		getObjectNames(functionDiffs.deletes),
+		getObjectNames(procedureDiffs.deletes),
+	} {
+		for _, name := range names {
+			droppedObjectNames[name] = true
+		}
+	}
+	for _, d := range viewDiffs.alters {
+		if !cmp.Equal(d.old, d.new) {
+			droppedObjectNames[d.new.GetName()] = true
+		}
+	}
+	var createdFunctions []schema.SchemaQualifiedName
+	for _, function := range functionDiffs.adds {
+		createdFunctions = append(createdFunctions, function.SchemaQualifiedName)
+	}
+	var createdProcedures []schema.SchemaQualifiedName
+	for _, procedure := range procedureDiffs.adds {
+		createdProcedures = append(createdProcedures, procedure.SchemaQualifiedName)
+	}
+	privilegeDiffs, err := diffLists(
+		buildMigratedPrivileges(old.Privileges, droppedObjectNames, createdFunctions, createdProcedures),
+		new.Privileges,
+		func(old, new schema.Privilege, _, _ int) (privilegeDiff, bool, error) {
+			return privilegeDiff{
+				oldAndNew[schema.Privilege]{
+					old: old,
+					new: new,
+				},
+			}, false, nil
+		})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing privileges: %w", err)
+	}
+
+	defaultPrivilegeDiffs, err := diffLists(old.DefaultPrivileges, new.DefaultPrivileges, func(old, new schema.DefaultPrivilege, _, _ int) (defaultPrivilegeDiff, bool, error) {
+		return defaultPrivilegeDiff{
+			oldAndNew[schema.DefaultPrivilege]{
+				old: old,
+				new: new,
+			},
+		}, false, nil
+	})
+	if err != nil {
+		return schemaDiff{}, false, fmt.Errorf("diffing default privileges: %w", err)
+	}
+
 	return schemaDiff{
 		oldAndNew: oldAndNew[schema.Schema]{
 			old: old,
@@ -356,16 +807,32 @@ func buildSchemaDiff(old, new schema.Schema) (schemaDiff, bool, error) {
 		},
 		namedSchemaDiffs:          schemaDiffs,
 		extensionDiffs:            extensionDiffs,
+		collationDiffs:            collationDiffs,
+		foreignDataWrapperDiffs:   foreignDataWrapperDiffs,
+		foreignServerDiffs:        foreignServerDiffs,
 		enumDiffs:                 enumDiffs,
+		domainDiffs:               domainDiffs,
+		compositeTypeDiffs:        compositeTypeDiffs,
+		textSearchDictionaryDiffs: textSearchDictionaryDiffs,
+		textSearchConfigDiffs:     textSearchConfigDiffs,
 		tableDiffs:                tableDiffs,
+		foreignTableDiffs:         foreignTableDiffs,
 		viewDiffs:                 viewDiffs,
+		materializedViewDiffs:     materializedViewDiffs,
 		indexDiffs:                indexesDiff,
+		statisticsObjectDiffs:     statisticsObjectDiffs,
 		foreignKeyConstraintDiffs: foreignKeyConstraintDiffs,
 		sequenceDiffs:             sequencesDiffs,
 		functionDiffs:             functionDiffs,
 		proceduresDiffs:           procedureDiffs,
+		aggregateDiffs:            aggregateDiffs,
 		triggerDiffs:              triggerDiffs,
 		eventTriggerDiffs:         eventTriggerDiffs,
+		operatorDiffs:             operatorDiffs,
+		operatorClassDiffs:        operatorClassDiffs,
+		publicationDiffs:          publicationDiffs,
+		privilegeDiffs:            privilegeDiffs,
+		defaultPrivilegeDiffs:     defaultPrivilegeDiffs,
 	}, false, nil
 }
 
@@ -398,11 +865,14 @@ func buildTableDiff(oldTable, newTable schema.Table, _, _ int) (diff tableDiff,
 		oldTable.Columns,
 		newTable.Columns,
 		func(old, new schema.Column, oldIndex, newIndex int) (columnDiff, bool, error) {
+			// Postgres does not support altering the generation expression of a stored generated column or converting
+			// a column to or from a generated column, so the column must be re-created
+			requiresRecreation := old.IsGenerated != new.IsGenerated || old.GenerationExpression != new.GenerationExpression
 			return columnDiff{
 				oldAndNew:   oldAndNew[schema.Column]{old: old, new: new},
 				oldOrdering: oldIndex,
 				newOrdering: newIndex,
-			}, false, nil
+			}, requiresRecreation, nil
 		},
 	)
 	if err != nil {
@@ -450,6 +920,31 @@ func buildTableDiff(oldTable, newTable schema.Table, _, _ int) (diff tableDiff,
 	}, false, nil
 }
 
+// hasColumnOrderChanged returns true if the relative order of the columns present in both the old and new table
+// changed. Added and dropped columns are not considered a change in order.
+func hasColumnOrderChanged(oldTable, newTable schema.Table) bool {
+	newColumnsByName := buildSchemaObjByNameMap(newTable.Columns)
+	var sharedOldColumnNames []string
+	for _, column := range oldTable.Columns {
+		if _, ok := newColumnsByName[column.GetName()]; ok {
+			sharedOldColumnNames = append(sharedOldColumnNames, column.GetName())
+		}
+	}
+
+	oldColumnsByName := buildSchemaObjByNameMap(oldTable.Columns)
+	i := 0
+	for _, column := range newTable.Columns {
+		if _, ok := oldColumnsByName[column.GetName()]; !ok {
+			continue
+		}
+		if sharedOldColumnNames[i] != column.GetName() {
+			return true
+		}
+		i++
+	}
+	return false
+}
+
 type indexDiffConfig struct {
 	newSchemaTablesByName map[string]schema.Table
 	addedTablesByName     map[string]schema.Table
@@ -458,6 +953,9 @@ type indexDiffConfig struct {
 	oldSchemaIndexesByName map[string]schema.Index
 	newSchemaIndexesByName map[string]schema.Index
 
+	// deletedOperatorClassesByName contains the operator classes that are dropped, including those that are re-created
+	deletedOperatorClassesByName map[string]schema.OperatorClass
+
 	// seenIndexByName is used to prevent infinite recursion when diffing indexes
 	seenIndexesByName map[string]bool
 }
@@ -480,6 +978,14 @@ func buildIndexDiff(deps indexDiffConfig, old, new schema.Index) (diff indexDiff
 		return indexDiff{}, true, nil
 	}
 
+	for _, operatorClass := range new.DependsOnOperatorClasses {
+		if _, isDeleted := deps.deletedOperatorClassesByName[operatorClass.GetName()]; isDeleted {
+			// An operator class cannot be dropped while an index uses it, so the index must be re-created if its
+			// operator class is re-created
+			return indexDiff{}, true, nil
+		}
+	}
+
 	if old.ParentIdx == nil {
 		// If the old index didn't belong to a partitioned index (and the new index does), we can resolve the parent
 		// index name diff if the index now belongs to a partitioned index by attaching the index.
@@ -515,9 +1021,10 @@ func buildIndexDiff(deps indexDiffConfig, old, new schema.Index) (diff indexDiff
 	}
 
 	if !isOnPartitionedTable {
-		if old.Constraint == nil && new.Constraint != nil {
+		if old.Constraint == nil && new.Constraint != nil && !new.IsExclusionConstraint() {
 			// Attach the constraint using the existing index. This cannot be done if the index is on a partitioned table.
-			// In the case of an index being on a partitioned table, it must be re-created
+			// In the case of an index being on a partitioned table, it must be re-created. Exclusion constraints cannot be
+			// attached using an existing index, so they must always be re-created.
 			updatedOld.Constraint = new.Constraint
 		}
 		if old.Constraint != nil && new.Constraint != nil && old.Constraint.IsLocal && !new.Constraint.IsLocal {
@@ -532,6 +1039,22 @@ func buildIndexDiff(deps indexDiffConfig, old, new schema.Index) (diff indexDiff
 		updatedOld.IsInvalid = new.IsInvalid
 	}
 
+	// The index can be moved to its new tablespace without re-creating it
+	updatedOld.Tablespace = new.Tablespace
+	// The comment on the index can be changed without re-creating it
+	updatedOld.Comment = new.Comment
+	if canAlterBrinParameters(old, new) {
+		// The BRIN storage parameters can be changed without re-creating the index
+		updatedOld.PagesPerRange = new.PagesPerRange
+		updatedOld.Autosummarize = new.Autosummarize
+		updatedOld.GetIndexDefStmt = new.GetIndexDefStmt
+	}
+	if canAlterGINParameters(old, new) {
+		// The GIN storage parameters can be changed without re-creating the index
+		updatedOld.GINParameters = new.GINParameters
+		updatedOld.GetIndexDefStmt = new.GetIndexDefStmt
+	}
+
 	recreateIndex := !cmp.Equal(updatedOld, new)
 	return indexDiff{
 		oldAndNew: oldAndNew[schema.Index]{
@@ -540,17 +1063,43 @@ func buildIndexDiff(deps indexDiffConfig, old, new schema.Index) (diff indexDiff
 	}, recreateIndex, nil
 }
 
-type schemaSQLGenerator struct{}
+type schemaSQLGenerator struct {
+	// nonConcurrentIndexOps is true if indexes should be built without CONCURRENTLY
+	nonConcurrentIndexOps bool
+	// nonConcurrentIndexDrops is true if indexes should be dropped without CONCURRENTLY
+	nonConcurrentIndexDrops bool
+	// constraintValidation configures whether constraints on existing tables are added as NOT VALID
+	constraintValidation constraintValidationOptions
+	// onlineColumnTypeChange is true if the types of columns are changed via shadow columns where possible. See
+	// changeColumnTypesOnline.
+	onlineColumnTypeChange bool
+	// omitAdvisoryAnalyze is true if the advisory `ANALYZE` statements, e.g., to populate the statistics of new
+	// statistics objects, are not generated
+	omitAdvisoryAnalyze bool
+	// additionalVertexGenerators generate the vertices of custom schema objects, which are sorted along with the
+	// built-in vertices
+	additionalVertexGenerators []VertexGenerator
+}
+
+func (s schemaSQLGenerator) Alter(diff schemaDiff) ([]Statement, error) {
+	stmts, _, err := s.alterWithDependencies(diff)
+	return stmts, err
+}
 
-func (schemaSQLGenerator) Alter(diff schemaDiff) ([]Statement, error) {
+// alterWithDependencies generates the statements to migrate the schema and the ordering dependencies between them
+func (s schemaSQLGenerator) alterWithDependencies(diff schemaDiff) ([]Statement, []StatementDependency, error) {
 	tablesInNewSchemaByName := buildSchemaObjByNameMap(diff.new.Tables)
 	deletedTablesByName := buildSchemaObjByNameMap(diff.tableDiffs.deletes)
 	addedTablesByName := buildSchemaObjByNameMap(diff.tableDiffs.adds)
 	functionsInNewSchemaByName := buildSchemaObjByNameMap(diff.new.Functions)
+	existingRoles := buildExistingRoles(diff.old)
 
-	namedSchemaStatements, err := diff.namedSchemaDiffs.resolveToSQLGroupedByEffect(&namedSchemaSQLGenerator{})
+	namedSchemaStatements, err := diff.namedSchemaDiffs.resolveToSQLGroupedByEffect(&namedSchemaSQLGenerator{
+		namedSchemasWithObjects: buildNamedSchemasWithObjects(diff.old),
+		existingRoles:           existingRoles,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("resolving named schema sql statements: %w", err)
+		return nil, nil, fmt.Errorf("resolving named schema sql statements: %w", err)
 	}
 
 	var partialGraph partialSQLGraph
@@ -559,44 +1108,100 @@ func (schemaSQLGenerator) Alter(diff schemaDiff) ([]Statement, error) {
 		deletedTablesByName:     deletedTablesByName,
 		tablesInNewSchemaByName: tablesInNewSchemaByName,
 		tableDiffsByName:        buildDiffByNameMap[schema.Table, tableDiff](diff.tableDiffs.alters),
+
+		hasDefaultPartitionByTableName: buildHasDefaultPartitionByTableNameMap(diff.old.Tables),
+		constraintValidation:           s.constraintValidation,
+		onlineColumnTypeChange:         s.onlineColumnTypeChange,
+		existingRoles:                  existingRoles,
 	}), diff.tableDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving table diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving table diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, tablePartialGraph)
 
+	foreignTablesPartialGraph, err := generatePartialGraph(newForeignTableSqlVertexGenerator(), diff.foreignTableDiffs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving foreign table diff: %w", err)
+	}
+	partialGraph = concatPartialGraphs(partialGraph, foreignTablesPartialGraph)
+
 	// Add view handling
 	viewGenerator := legacyToNewSqlVertexGenerator[schema.View, viewDiff](&viewSQLVertexGenerator{
 		tablesInNewSchemaByName: tablesInNewSchemaByName,
-		viewsInNewSchemaByName: buildSchemaObjByNameMap(diff.new.Views),
+		viewsInNewSchemaByName:  buildSchemaObjByNameMap(diff.new.Views),
+		existingRoles:           existingRoles,
 	})
 	viewsPartialGraph, err := generatePartialGraph(viewGenerator, diff.viewDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving view diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving view diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, viewsPartialGraph)
 
+	materializedViewsPartialGraph, err := generatePartialGraph(legacyToNewSqlVertexGenerator[schema.MaterializedView, materializedViewDiff](&materializedViewSQLVertexGenerator{
+		nonConcurrentIndexOps:   s.nonConcurrentIndexOps,
+		nonConcurrentIndexDrops: s.nonConcurrentIndexDrops,
+	}), diff.materializedViewDiffs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving materialized view diff: %w", err)
+	}
+	partialGraph = concatPartialGraphs(partialGraph, materializedViewsPartialGraph)
+
 	extensionStatements, err := diff.extensionDiffs.resolveToSQLGroupedByEffect(&extensionSQLGenerator{})
 	if err != nil {
-		return nil, fmt.Errorf("resolving extension diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving extension diff: %w", err)
+	}
+
+	collationStatements, err := diff.collationDiffs.resolveToSQLGroupedByEffect(&collationSQLGenerator{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving collation diff: %w", err)
+	}
+
+	foreignDataWrapperStatements, err := diff.foreignDataWrapperDiffs.resolveToSQLGroupedByEffect(&foreignDataWrapperSQLGenerator{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving foreign data wrapper diff: %w", err)
+	}
+
+	foreignServerStatements, err := diff.foreignServerDiffs.resolveToSQLGroupedByEffect(&foreignServerSQLGenerator{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving foreign server diff: %w", err)
 	}
 
 	enumStatements, err := diff.enumDiffs.resolveToSQLGroupedByEffect(&enumSQLGenerator{})
 	if err != nil {
-		return nil, fmt.Errorf("resolving enum diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving enum diff: %w", err)
+	}
+
+	domainStatements, err := diff.domainDiffs.resolveToSQLGroupedByEffect(&domainSQLGenerator{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving domain diff: %w", err)
+	}
+
+	compositeTypeStatements, err := diff.compositeTypeDiffs.resolveToSQLGroupedByEffect(&compositeTypeSQLGenerator{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving composite type diff: %w", err)
 	}
 
-	attachPartitionGenerator := newAttachPartitionSQLVertexGenerator(diff.new.Indexes, diff.tableDiffs.adds)
+	textSearchDictionaryStatements, err := diff.textSearchDictionaryDiffs.resolveToSQLGroupedByEffect(&textSearchDictionarySQLGenerator{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving text search dictionary diff: %w", err)
+	}
+
+	textSearchConfigStatements, err := diff.textSearchConfigDiffs.resolveToSQLGroupedByEffect(&textSearchConfigSQLGenerator{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving text search config diff: %w", err)
+	}
+
+	attachPartitionGenerator := newAttachPartitionSQLVertexGenerator(diff.old.Tables, diff.new.Indexes, diff.tableDiffs)
 	attachPartitionsPartialGraph, err := generatePartialGraph(legacyToNewSqlVertexGenerator[schema.Table, tableDiff](attachPartitionGenerator), diff.tableDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving attach partition diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving attach partition diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, attachPartitionsPartialGraph)
 
 	renameConflictingIndexesGenerator := newRenameConflictingIndexSQLVertexGenerator(buildSchemaObjByNameMap(diff.old.Indexes))
 	renameConflictingIndexesPartialGraph, err := generatePartialGraph(legacyToNewSqlVertexGenerator[schema.Index, indexDiff](renameConflictingIndexesGenerator), diff.indexDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving renaming conflicting indexes diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving renaming conflicting indexes diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, renameConflictingIndexesPartialGraph)
 
@@ -605,51 +1210,62 @@ func (schemaSQLGenerator) Alter(diff schemaDiff) ([]Statement, error) {
 		addedTablesByName:        addedTablesByName,
 		tablesInNewSchemaByName:  tablesInNewSchemaByName,
 		indexesInNewSchemaByName: buildSchemaObjByNameMap(diff.new.Indexes),
+		nonConcurrentIndexOps:    s.nonConcurrentIndexOps,
+		nonConcurrentIndexDrops:  s.nonConcurrentIndexDrops,
+
+		distributionColumnsByTableName: buildDistributionColumnsByTableName(diff.old.Tables),
 
 		renameSQLVertexGenerator:          renameConflictingIndexesGenerator,
 		attachPartitionSQLVertexGenerator: attachPartitionGenerator,
 	})
 	indexesPartialGraph, err := generatePartialGraph(indexGenerator, diff.indexDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving index diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving index diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, indexesPartialGraph)
 
-	foreignKeyGenerator := newForeignKeyConstraintSQLVertexGenerator(diff.oldAndNew, diff.tableDiffs)
+	statisticsObjectsPartialGraph, err := generatePartialGraph(newStatisticsObjectSqlVertexGenerator(s.omitAdvisoryAnalyze), diff.statisticsObjectDiffs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving statistics object diff: %w", err)
+	}
+	partialGraph = concatPartialGraphs(partialGraph, statisticsObjectsPartialGraph)
+
+	foreignKeyGenerator := newForeignKeyConstraintSQLVertexGenerator(diff.oldAndNew, diff.tableDiffs, s.constraintValidation)
 	fkConsPartialGraph, err := generatePartialGraph(foreignKeyGenerator, diff.foreignKeyConstraintDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving foreign key constraint diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving foreign key constraint diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, fkConsPartialGraph)
 
 	sequenceGenerator := legacyToNewSqlVertexGenerator[schema.Sequence, sequenceDiff](&sequenceSQLVertexGenerator{
 		deletedTablesByName: deletedTablesByName,
 		tableDiffsByName:    buildDiffByNameMap[schema.Table, tableDiff](diff.tableDiffs.alters),
+		existingRoles:       existingRoles,
 	})
 	sequencesPartialGraph, err := generatePartialGraph(sequenceGenerator, diff.sequenceDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving sequence diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving sequence diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, sequencesPartialGraph)
 
 	sequenceOwnershipGenerator := legacyToNewSqlVertexGenerator[schema.Sequence, sequenceDiff](&sequenceOwnershipSQLVertexGenerator{})
 	sequenceOwnershipsPartialGraph, err := generatePartialGraph(sequenceOwnershipGenerator, diff.sequenceDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving sequence ownership diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving sequence ownership diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, sequenceOwnershipsPartialGraph)
 
-	functionGenerator := newFunctionSqlVertexGenerator(functionsInNewSchemaByName, diff.tableDiffs.alters)
+	functionGenerator := newFunctionSqlVertexGenerator(functionsInNewSchemaByName, diff.tableDiffs.alters, diff.functionDiffs.deletes, existingRoles)
 	functionsPartialGraph, err := generatePartialGraph(functionGenerator, diff.functionDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving function diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving function diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, functionsPartialGraph)
 
 	procedureGenerator := newProcedureSqlVertexGenerator(diff.new)
 	proceduresPartialGraph, err := generatePartialGraph(procedureGenerator, diff.proceduresDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving procedure diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving procedure diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, proceduresPartialGraph)
 
@@ -658,25 +1274,67 @@ func (schemaSQLGenerator) Alter(diff schemaDiff) ([]Statement, error) {
 	})
 	triggersPartialGraph, err := generatePartialGraph(triggerGenerator, diff.triggerDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving trigger diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving trigger diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, triggersPartialGraph)
 
 	eventTriggerGenerator := newEventTriggerSQLVertexGenerator(diff.old.EventTriggers, diff.new.EventTriggers)
 	eventTriggersPartialGraph, err := generatePartialGraph(legacyToNewSqlVertexGenerator[schema.EventTrigger, eventTriggerDiff](eventTriggerGenerator), diff.eventTriggerDiffs)
 	if err != nil {
-		return nil, fmt.Errorf("resolving event trigger diff: %w", err)
+		return nil, nil, fmt.Errorf("resolving event trigger diff: %w", err)
 	}
 	partialGraph = concatPartialGraphs(partialGraph, eventTriggersPartialGraph)
 
+	aggregatesPartialGraph, err := generatePartialGraph(newAggregateSqlVertexGenerator(), diff.aggregateDiffs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving aggregate diff: %w", err)
+	}
+	partialGraph = concatPartialGraphs(partialGraph, aggregatesPartialGraph)
+
+	operatorsPartialGraph, err := generatePartialGraph(newOperatorSqlVertexGenerator(), diff.operatorDiffs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving operator diff: %w", err)
+	}
+	partialGraph = concatPartialGraphs(partialGraph, operatorsPartialGraph)
+
+	operatorClassesPartialGraph, err := generatePartialGraph(newOperatorClassSqlVertexGenerator(), diff.operatorClassDiffs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving operator class diff: %w", err)
+	}
+	partialGraph = concatPartialGraphs(partialGraph, operatorClassesPartialGraph)
+
+	publicationsPartialGraph, err := generatePartialGraph(newPublicationSqlVertexGenerator(), diff.publicationDiffs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving publication diff: %w", err)
+	}
+	partialGraph = concatPartialGraphs(partialGraph, publicationsPartialGraph)
+
+	privilegesPartialGraph, err := generatePartialGraph(newPrivilegeSqlVertexGenerator(), diff.privilegeDiffs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving privilege diff: %w", err)
+	}
+	partialGraph = concatPartialGraphs(partialGraph, privilegesPartialGraph)
+
+	defaultPrivilegesPartialGraph, err := generatePartialGraph(newDefaultPrivilegeSqlVertexGenerator(), diff.defaultPrivilegeDiffs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving default privilege diff: %w", err)
+	}
+	partialGraph = concatPartialGraphs(partialGraph, defaultPrivilegesPartialGraph)
+
+	additionalPartialGraph, err := generateAdditionalPartialGraph(s.additionalVertexGenerators, diff.old, diff.new)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving additional vertex generators: %w", err)
+	}
+	partialGraph = concatPartialGraphs(partialGraph, additionalPartialGraph)
+
 	sqlGraph, err := graphFromPartials(partialGraph)
 	if err != nil {
-		return nil, fmt.Errorf("converting to graph: %w", err)
+		return nil, nil, fmt.Errorf("converting to graph: %w", err)
 	}
 
-	graphStatements, err := sqlGraph.toOrderedStatements()
+	graphStatements, graphDependencies, err := sqlGraph.toOrderedStatementsWithDependencies()
 	if err != nil {
-		return nil, fmt.Errorf("getting ordered statements: %w", err)
+		return nil, nil, fmt.Errorf("getting ordered statements: %w", err)
 	}
 
 	// We migrate schemas and extensions first and disable them last since their dependencies may span across
@@ -686,13 +1344,48 @@ func (schemaSQLGenerator) Alter(diff schemaDiff) ([]Statement, error) {
 	statements = append(statements, namedSchemaStatements.Alters...)
 	statements = append(statements, extensionStatements.Adds...)
 	statements = append(statements, extensionStatements.Alters...)
+	// Collations can be used by domains, composite types, columns, and indexes, so they are migrated before all of
+	// them and dropped after all of them
+	statements = append(statements, collationStatements.Adds...)
+	statements = append(statements, collationStatements.Alters...)
+	// Foreign-data wrappers can use handlers from extensions, and foreign tables use servers, which in turn use
+	// foreign-data wrappers. They are migrated in that order before the graph and dropped in the reverse order
+	statements = append(statements, foreignDataWrapperStatements.Adds...)
+	statements = append(statements, foreignDataWrapperStatements.Alters...)
+	statements = append(statements, foreignServerStatements.Adds...)
+	statements = append(statements, foreignServerStatements.Alters...)
 	statements = append(statements, enumStatements.Adds...)
 	statements = append(statements, enumStatements.Alters...)
-	statements = append(statements, graphStatements...)
-	statements = append(statements, enumStatements.Deletes...)
-	statements = append(statements, extensionStatements.Deletes...)
-	statements = append(statements, namedSchemaStatements.Deletes...)
-	return statements, nil
+	// Domains can be based on enums, and composite types can use both enums and domains, so they are migrated in that
+	// order and dropped in the reverse order
+	statements = append(statements, domainStatements.Adds...)
+	statements = append(statements, domainStatements.Alters...)
+	statements = append(statements, compositeTypeStatements.Adds...)
+	statements = append(statements, compositeTypeStatements.Alters...)
+	// Text search dictionaries can use templates from extensions, and columns, indexes, and functions can use text
+	// search configurations, which in turn use dictionaries. Dictionaries are migrated before configurations, which
+	// are migrated before the graph, and they are dropped in the reverse order
+	statements = append(statements, textSearchDictionaryStatements.Adds...)
+	statements = append(statements, textSearchDictionaryStatements.Alters...)
+	statements = append(statements, textSearchConfigStatements.Adds...)
+	statements = append(statements, textSearchConfigStatements.Alters...)
+	dependencies := buildSequentialDependencies(len(statements))
+	statements, dependencies = appendStatementsWithDependencies(statements, dependencies, graphStatements, graphDependencies)
+
+	var postGraphStatements []Statement
+	postGraphStatements = append(postGraphStatements, textSearchConfigStatements.Deletes...)
+	postGraphStatements = append(postGraphStatements, textSearchDictionaryStatements.Deletes...)
+	postGraphStatements = append(postGraphStatements, compositeTypeStatements.Deletes...)
+	postGraphStatements = append(postGraphStatements, domainStatements.Deletes...)
+	postGraphStatements = append(postGraphStatements, enumStatements.Deletes...)
+	postGraphStatements = append(postGraphStatements, foreignServerStatements.Deletes...)
+	postGraphStatements = append(postGraphStatements, foreignDataWrapperStatements.Deletes...)
+	postGraphStatements = append(postGraphStatements, collationStatements.Deletes...)
+	postGraphStatements = append(postGraphStatements, extensionStatements.Deletes...)
+	postGraphStatements = append(postGraphStatements, namedSchemaStatements.Deletes...)
+	postGraphStatements = append(postGraphStatements, buildRefreshMaterializedViewStatements(diff.materializedViewDiffs)...)
+	statements, dependencies = appendStatementsWithDependencies(statements, dependencies, postGraphStatements, buildSequentialDependencies(len(postGraphStatements)))
+	return statements, dependencies, nil
 }
 
 func buildIndexesByTableNameMap(indexes []schema.Index) map[string][]schema.Index {
@@ -757,6 +1450,14 @@ func buildSchemaObjByNameMap[S schema.Object](s []S) map[string]S {
 	})
 }
 
+func getObjectNames[S schema.Object](objs []S) []string {
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.GetName())
+	}
+	return names
+}
+
 func buildDiffByNameMap[S schema.Object, D diff[S]](d []D) map[string]D {
 	return buildMap(d, func(d D) string {
 		return d.GetNew().GetName()
@@ -775,6 +1476,15 @@ type tableSQLVertexGenerator struct {
 	deletedTablesByName     map[string]schema.Table
 	tablesInNewSchemaByName map[string]schema.Table
 	tableDiffsByName        map[string]tableDiff
+	// hasDefaultPartitionByTableName is a map of partitioned table name to whether it has a default partition in the
+	// old schema. Partitions of a table with a default partition cannot be detached concurrently
+	hasDefaultPartitionByTableName map[string]bool
+	// constraintValidation configures whether check constraints on existing tables are added as NOT VALID
+	constraintValidation constraintValidationOptions
+	// onlineColumnTypeChange is true if the types of columns are changed via shadow columns where possible
+	onlineColumnTypeChange bool
+	// existingRoles is the set of roles known to exist in the target database. See buildExistingRoles.
+	existingRoles map[string]bool
 }
 
 func (t *tableSQLVertexGenerator) Add(table schema.Table) ([]Statement, error) {
@@ -797,6 +1507,10 @@ func (t *tableSQLVertexGenerator) Add(table schema.Table) ([]Statement, error) {
 
 	var columnDefs []string
 	for _, column := range table.Columns {
+		if column.IsInherited {
+			// Inherited columns are created by the INHERITS clause
+			continue
+		}
 		columnDef, err := buildColumnDefinition(column)
 		if err != nil {
 			return nil, fmt.Errorf("building column definition: %w", err)
@@ -808,14 +1522,43 @@ func (t *tableSQLVertexGenerator) Add(table schema.Table) ([]Statement, error) {
 		table.GetFQEscapedName(),
 		strings.Join(columnDefs, ",\n"),
 	))
+	if len(table.InheritsFrom) > 0 {
+		createTableSb.WriteString(buildInheritsClause(table))
+	}
 	if table.IsPartitioned() {
 		createTableSb.WriteString(fmt.Sprintf(" PARTITION BY %s", table.PartitionKeyDef))
 	}
-	stmts = append(stmts, Statement{
-		DDL:         createTableSb.String(),
-		Timeout:     statementTimeoutDefault,
-		LockTimeout: lockTimeoutDefault,
-	})
+	if len(table.StorageParameters) > 0 {
+		createTableSb.WriteString(fmt.Sprintf(" WITH (%s)", buildStorageParameterList(table.StorageParameters)))
+	}
+	createTableSb.WriteString(buildTablespaceClause(table.Tablespace))
+	createTableStmt := Statement{
+		DDL:         createTableSb.String(),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}
+	if t.hasDivergedInheritedColumns(table, table.InheritsFrom) {
+		createTableStmt.Hazards = append(createTableStmt.Hazards, migrationHazardInheritedColumnsDiverged)
+	}
+	stmts = append(stmts, createTableStmt)
+
+	inheritedColumnsStmts, err := t.buildInheritedColumnsStatements(table)
+	if err != nil {
+		return nil, fmt.Errorf("building inherited columns statements: %w", err)
+	}
+	// Remove hazards from statements since the table is brand new
+	stmts = append(stmts, stripMigrationHazards(inheritedColumnsStmts...)...)
+
+	for _, column := range table.Columns {
+		if len(column.StorageType) == 0 || column.IsInherited {
+			continue
+		}
+		setStorageStmt, err := alterColumnStorageStatement(table.SchemaQualifiedName, column.Name, column.StorageType)
+		if err != nil {
+			return nil, fmt.Errorf("building set storage statement for column %s: %w", column.Name, err)
+		}
+		stmts = append(stmts, setStorageStmt)
+	}
 
 	csg := checkConstraintSQLVertexGenerator{
 		tableName:  table.SchemaQualifiedName,
@@ -861,35 +1604,65 @@ func (t *tableSQLVertexGenerator) Add(table schema.Table) ([]Statement, error) {
 		stmts = append(stmts, stripMigrationHazards(forceRLSForTable(table))...)
 	}
 
+	stmts = append(stmts, buildCommentStatements("TABLE", table.GetFQEscapedName(), nil, table.Comment)...)
+	for _, column := range table.Columns {
+		stmts = append(stmts, buildColumnCommentStatements(table.SchemaQualifiedName, nil, column)...)
+	}
+	stmts = append(stmts, buildAlterOwnerStatements("TABLE", table.GetFQEscapedName(), "", table.Owner, t.existingRoles)...)
+
 	return stmts, nil
 }
 
 func (t *tableSQLVertexGenerator) Delete(table schema.Table) ([]Statement, error) {
-	if table.IsPartition() {
-		// Don't support dropping partitions without dropping the base table. This would be easy to implement, but we
-		// would need to add tests for it.
-		//
-		// The base table might be recreated, so check if its deleted rather than just checking if it does not exist in
-		// the new schema
-		if _, baseTableDropped := t.deletedTablesByName[table.ParentTable.GetName()]; !baseTableDropped {
-			return nil, fmt.Errorf("deleting partitions without dropping parent table: %w", ErrNotImplemented)
-		}
+	dropTableStmt := Statement{
+		DDL:         fmt.Sprintf("DROP TABLE %s", table.GetFQEscapedName()),
+		Timeout:     statementTimeoutTableDrop,
+		LockTimeout: lockTimeoutDefault,
+		Hazards: []MigrationHazard{{
+			Type:    MigrationHazardTypeDeletesData,
+			Message: "Deletes all rows in the table (and the table itself)",
+		}},
+	}
+	if len(table.DistributionColumn) > 0 {
+		dropTableStmt.Hazards = append(dropTableStmt.Hazards, migrationHazardCitusDistributedTableDropped)
+	}
+	if !table.IsPartition() {
+		return []Statement{dropTableStmt}, nil
+	}
+
+	// The base table might be recreated, so check if its deleted rather than just checking if it does not exist in
+	// the new schema
+	if _, baseTableDropped := t.deletedTablesByName[table.ParentTable.GetName()]; baseTableDropped {
 		// It will be dropped when the parent table is dropped
 		return nil, nil
 	}
+	if t.hasDefaultPartitionByTableName[table.ParentTable.GetName()] {
+		dropTableStmt.Hazards = append(dropTableStmt.Hazards, migrationHazardPartitionDroppedAcquiresLock)
+		return []Statement{dropTableStmt}, nil
+	}
+	// Dropping a partition locks out all accesses to the partitioned table, so detach it concurrently first
 	return []Statement{
 		{
-			DDL:         fmt.Sprintf("DROP TABLE %s", table.GetFQEscapedName()),
-			Timeout:     statementTimeoutTableDrop,
-			LockTimeout: lockTimeoutDefault,
-			Hazards: []MigrationHazard{{
-				Type:    MigrationHazardTypeDeletesData,
-				Message: "Deletes all rows in the table (and the table itself)",
-			}},
+			DDL:                   fmt.Sprintf("%s DETACH PARTITION %s CONCURRENTLY", alterTablePrefix(*table.ParentTable), table.GetFQEscapedName()),
+			Timeout:               statementTimeoutDetachPartitionConcurrently,
+			LockTimeout:           lockTimeoutDefault,
+			RequiresNoTransaction: true,
 		},
+		dropTableStmt,
 	}, nil
 }
 
+// buildHasDefaultPartitionByTableNameMap builds a map of partitioned table name to whether it has a default partition
+func buildHasDefaultPartitionByTableNameMap(tables []schema.Table) map[string]bool {
+	hasDefaultPartitionByTableName := make(map[string]bool)
+	for _, table := range tables {
+		if table.IsDefaultPartition() {
+			hasDefaultPartitionByTableName[table.ParentTable.GetName()] = true
+		}
+	}
+	return hasDefaultPartitionByTableName
+}
+
 func (t *tableSQLVertexGenerator) Alter(diff tableDiff) ([]Statement, error) {
 	if diff.old.IsPartition() != diff.new.IsPartition() {
 		return nil, fmt.Errorf("changing a partition to no longer be a partition (or vice versa): %w", ErrNotImplemented)
@@ -920,6 +1693,12 @@ func (t *tableSQLVertexGenerator) Alter(diff tableDiff) ([]Statement, error) {
 		stmts = append(stmts, alterBaseTableStmts...)
 	}
 
+	stmts = append(stmts, alterStorageParametersStatements(alterTablePrefix(diff.new.SchemaQualifiedName), diff.old.StorageParameters, diff.new.StorageParameters)...)
+
+	if diff.old.Tablespace != diff.new.Tablespace {
+		stmts = append(stmts, buildSetTablespaceStatement(alterTablePrefix(diff.new.SchemaQualifiedName), diff.new.Tablespace, diff.new.IsPartitioned()))
+	}
+
 	if diff.old.ReplicaIdentity != diff.new.ReplicaIdentity {
 		alterReplicaIdentityStmt, err := alterReplicaIdentityStatement(diff.new.SchemaQualifiedName, diff.new.ReplicaIdentity)
 		if err != nil {
@@ -937,6 +1716,9 @@ func (t *tableSQLVertexGenerator) Alter(diff tableDiff) ([]Statement, error) {
 		stmts = append(stmts, forceRLSForTable(diff.new))
 	}
 
+	stmts = append(stmts, buildCommentStatements("TABLE", diff.new.GetFQEscapedName(), diff.old.Comment, diff.new.Comment)...)
+	stmts = append(stmts, buildAlterOwnerStatements("TABLE", diff.new.GetFQEscapedName(), diff.old.Owner, diff.new.Owner, t.existingRoles)...)
+
 	return stmts, nil
 }
 
@@ -945,6 +1727,12 @@ func (t *tableSQLVertexGenerator) alterBaseTable(diff tableDiff) ([]Statement, e
 		return nil, fmt.Errorf("changing partition key def: %w", ErrNotImplemented)
 	}
 
+	diff, err := t.applyInheritedColumnChanges(diff)
+	if err != nil {
+		return nil, fmt.Errorf("applying inherited column changes: %w", err)
+	}
+	noInheritStmts, inheritStmts := t.buildAlterInheritanceStatements(diff)
+
 	var tempCCs []schema.CheckConstraint
 	for _, colDiff := range getDangerousNotNullAlters(diff.columnsDiff.alters, diff.new.CheckConstraints, diff.old.CheckConstraints) {
 		tempCC, err := buildTempNotNullConstraint(colDiff)
@@ -957,7 +1745,7 @@ func (t *tableSQLVertexGenerator) alterBaseTable(diff tableDiff) ([]Statement, e
 
 	var partialGraph partialSQLGraph
 
-	columnGenerator := newColumnSQLVertexGenerator(diff.new.SchemaQualifiedName)
+	columnGenerator := newColumnSQLVertexGenerator(diff.new.SchemaQualifiedName, diff.old.DistributionColumn, t.onlineColumnTypeChange)
 	columnsPartialGraph, err := generatePartialGraph(columnGenerator, diff.columnsDiff)
 	if err != nil {
 		return nil, fmt.Errorf("resolving index diff: %w", err)
@@ -971,6 +1759,7 @@ func (t *tableSQLVertexGenerator) alterBaseTable(diff tableDiff) ([]Statement, e
 		addedColumnsByName:     buildSchemaObjByNameMap(diff.columnsDiff.adds),
 		deletedColumnsByName:   buildSchemaObjByNameMap(diff.columnsDiff.deletes),
 		isNewTable:             false,
+		constraintValidation:   t.constraintValidation,
 	})
 	checkConsPartialGraph, err := generatePartialGraph(checkConGenerator, diff.checkConstraintDiff)
 	if err != nil {
@@ -1011,18 +1800,17 @@ func (t *tableSQLVertexGenerator) alterBaseTable(diff tableDiff) ([]Statement, e
 	}
 
 	var stmts []Statement
+	stmts = append(stmts, noInheritStmts...)
 	stmts = append(stmts, graphStmts...)
 	// Drop the temporary check constraints that were added to make changing columns to "NOT NULL" not require an
 	// extended table lock
 	stmts = append(stmts, dropTempCCs...)
+	stmts = append(stmts, inheritStmts...)
 
 	return stmts, nil
 }
 
 func (t *tableSQLVertexGenerator) alterPartition(diff tableDiff) ([]Statement, error) {
-	if diff.old.ForValues != diff.new.ForValues {
-		return nil, fmt.Errorf("altering partition FOR VALUES: %w", ErrNotImplemented)
-	}
 	if !diff.checkConstraintDiff.isEmpty() {
 		return nil, fmt.Errorf("check constraints on partitions: %w", ErrNotImplemented)
 	}
@@ -1038,6 +1826,11 @@ func (t *tableSQLVertexGenerator) alterPartition(diff tableDiff) ([]Statement, e
 	}
 
 	var stmts []Statement
+	if isPartitionBoundsChanged(diff.old, diff.new) {
+		hasDefaultPartition := t.hasDefaultPartitionByTableName[diff.new.ParentTable.GetName()] && !diff.new.IsDefaultPartition()
+		stmts = append(stmts, buildAlterPartitionBoundsStatements(diff.new, hasDefaultPartition)...)
+	}
+
 	// ColumnsDiff should only have nullability changes. Partitioned tables
 	// aren't concerned about old/new columns added
 	for _, colDiff := range diff.columnsDiff.alters {
@@ -1077,9 +1870,48 @@ func (t *tableSQLVertexGenerator) alterPartition(diff tableDiff) ([]Statement, e
 		}
 	}
 
+	// Comments are not shared with the parent table, so each partition's column comments are set individually
+	for _, colDiff := range diff.columnsDiff.alters {
+		oldColumn := colDiff.old
+		stmts = append(stmts, buildColumnCommentStatements(diff.new.SchemaQualifiedName, &oldColumn, colDiff.new)...)
+	}
+
 	return stmts, nil
 }
 
+// buildAlterPartitionBoundsStatements builds the statements to change the bounds of a partition. The bounds of a
+// partition cannot be altered, so the partition is detached and re-attached with its new bounds. Unlike re-creating
+// the partition, this preserves its data and indexes: the indexes are re-attached to the partitioned indexes when the
+// partition is re-attached.
+//
+// The partition is not detached concurrently, since a concurrent detach leaves the old bounds behind as a check
+// constraint on the partition and is not allowed if the partitioned table has a default partition.
+func buildAlterPartitionBoundsStatements(partition schema.Table, hasDefaultPartition bool) []Statement {
+	attachHazards := []MigrationHazard{migrationHazardPartitionAttachValidatesBounds}
+	if hasDefaultPartition {
+		attachHazards = append(attachHazards, migrationHazardPartitionAttachScansDefaultPartition)
+	}
+	if partition.IsHashPartition() {
+		attachHazards = append(attachHazards, migrationHazardHashPartitionBoundsChanged)
+	}
+
+	parentPrefix := alterTablePrefix(*partition.ParentTable)
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("%s DETACH PARTITION %s", parentPrefix, partition.GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardPartitionDetached},
+		},
+		{
+			DDL:         fmt.Sprintf("%s ATTACH PARTITION %s %s", parentPrefix, partition.GetFQEscapedName(), partition.ForValues),
+			Timeout:     statementTimeoutAttachPartition,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     attachHazards,
+		},
+	}
+}
+
 func alterReplicaIdentityStatement(table schema.SchemaQualifiedName, identity schema.ReplicaIdentity) (Statement, error) {
 	alterType, err := replicaIdentityAlterType(identity)
 	if err != nil {
@@ -1096,6 +1928,58 @@ func alterReplicaIdentityStatement(table schema.SchemaQualifiedName, identity sc
 	}, nil
 }
 
+// alterStorageParametersStatements builds the statements to set the new and changed storage parameters and reset the
+// removed storage parameters. The alter prefix is the statement that the SET and RESET clauses are appended to, e.g.,
+// "ALTER TABLE foo"
+func alterStorageParametersStatements(alterPrefix string, oldParams, newParams map[string]string) []Statement {
+	setParams := make(map[string]string)
+	for key, value := range newParams {
+		if oldValue, ok := oldParams[key]; !ok || oldValue != value {
+			setParams[key] = value
+		}
+	}
+	var resetKeys []string
+	for key := range oldParams {
+		if _, ok := newParams[key]; !ok {
+			resetKeys = append(resetKeys, key)
+		}
+	}
+	sort.Strings(resetKeys)
+
+	var stmts []Statement
+	if len(setParams) > 0 {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s SET (%s)", alterPrefix, buildStorageParameterList(setParams)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	}
+	if len(resetKeys) > 0 {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s RESET (%s)", alterPrefix, strings.Join(resetKeys, ", ")),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	}
+	return stmts
+}
+
+// buildStorageParameterList builds a deterministically ordered list of storage parameters, e.g.,
+// "fillfactor=70, toast.autovacuum_enabled=false"
+func buildStorageParameterList(params map[string]string) string {
+	var keys []string
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var paramDefs []string
+	for _, key := range keys {
+		paramDefs = append(paramDefs, fmt.Sprintf("%s=%s", key, params[key]))
+	}
+	return strings.Join(paramDefs, ", ")
+}
+
 func replicaIdentityAlterType(identity schema.ReplicaIdentity) (string, error) {
 	switch identity {
 	case schema.ReplicaIdentityDefault:
@@ -1119,7 +2003,7 @@ func buildTableVertexId(name schema.SchemaQualifiedName, diffType diffType) sqlV
 	return buildSchemaObjVertexId("table", name.GetFQEscapedName(), diffType)
 }
 
-func (t *tableSQLVertexGenerator) GetAddAlterDependencies(table, _ schema.Table) ([]dependency, error) {
+func (t *tableSQLVertexGenerator) GetAddAlterDependencies(table, old schema.Table) ([]dependency, error) {
 	deps := []dependency{
 		mustRun(t.GetSQLVertexId(table, diffTypeAddAlter)).after(t.GetSQLVertexId(table, diffTypeDelete)),
 	}
@@ -1129,9 +2013,38 @@ func (t *tableSQLVertexGenerator) GetAddAlterDependencies(table, _ schema.Table)
 			mustRun(t.GetSQLVertexId(table, diffTypeAddAlter)).after(buildTableVertexId(*table.ParentTable, diffTypeAddAlter)),
 		)
 	}
+	// Children are altered after their parents, since altering the columns of a parent also alters its children's.
+	// They must stop inheriting from their old parents before the old parents are altered or dropped.
+	newParentsByName := buildSchemaObjByNameMap(table.InheritsFrom)
+	for _, parent := range table.InheritsFrom {
+		deps = append(deps, mustRun(t.GetSQLVertexId(table, diffTypeAddAlter)).after(buildTableVertexId(parent, diffTypeAddAlter)))
+	}
+	for _, parent := range old.InheritsFrom {
+		if _, ok := newParentsByName[parent.GetName()]; ok {
+			continue
+		}
+		deps = append(deps,
+			mustRun(t.GetSQLVertexId(table, diffTypeAddAlter)).before(buildTableVertexId(parent, diffTypeDelete)),
+			mustRun(t.GetSQLVertexId(table, diffTypeAddAlter)).before(buildTableVertexId(parent, diffTypeAddAlter)),
+		)
+	}
+	if isPartitionBoundsChanged(old, table) {
+		// The new bounds of the partition might overlap with the bounds of a dropped partition, so the partition must
+		// be re-attached after its dropped siblings are dropped
+		for _, deletedTable := range t.deletedTablesByName {
+			if deletedTable.ParentTable != nil && deletedTable.ParentTable.GetName() == table.ParentTable.GetName() {
+				deps = append(deps, mustRun(t.GetSQLVertexId(table, diffTypeAddAlter)).after(t.GetSQLVertexId(deletedTable, diffTypeDelete)))
+			}
+		}
+	}
 	return deps, nil
 }
 
+// isPartitionBoundsChanged returns true if the table is an existing partition whose bounds changed
+func isPartitionBoundsChanged(old, new schema.Table) bool {
+	return old.IsPartition() && new.IsPartition() && old.ForValues != new.ForValues
+}
+
 func getDangerousNotNullAlters(alteredCols []columnDiff, newSchemaCCs []schema.CheckConstraint, oldSchemaCCs []schema.CheckConstraint) []columnDiff {
 	var ccs []schema.CheckConstraint
 	ccs = append(ccs, newSchemaCCs...)
@@ -1197,15 +2110,28 @@ func (t *tableSQLVertexGenerator) GetDeleteDependencies(table schema.Table) ([]d
 			mustRun(t.GetSQLVertexId(table, diffTypeDelete)).after(buildTableVertexId(*table.ParentTable, diffTypeDelete)),
 		)
 	}
+	// Unlike partitions, children are not dropped with their parents, so they must be dropped first
+	for _, parent := range table.InheritsFrom {
+		deps = append(deps, mustRun(t.GetSQLVertexId(table, diffTypeDelete)).before(buildTableVertexId(parent, diffTypeDelete)))
+	}
 	return deps, nil
 }
 
 type columnSQLVertexGenerator struct {
 	tableName schema.SchemaQualifiedName
-}
-
-func newColumnSQLVertexGenerator(tableName schema.SchemaQualifiedName) sqlVertexGenerator[schema.Column, columnDiff] {
-	return legacyToNewSqlVertexGenerator[schema.Column, columnDiff](&columnSQLVertexGenerator{tableName: tableName})
+	// distributionColumn is the Citus distribution column of the table. It is empty if the table is not distributed.
+	distributionColumn string
+	// onlineColumnTypeChange is true if the types of columns are changed via shadow columns where possible. The type
+	// changes that remain could not be made online.
+	onlineColumnTypeChange bool
+}
+
+func newColumnSQLVertexGenerator(tableName schema.SchemaQualifiedName, distributionColumn string, onlineColumnTypeChange bool) sqlVertexGenerator[schema.Column, columnDiff] {
+	return legacyToNewSqlVertexGenerator[schema.Column, columnDiff](&columnSQLVertexGenerator{
+		tableName:              tableName,
+		distributionColumn:     distributionColumn,
+		onlineColumnTypeChange: onlineColumnTypeChange,
+	})
 }
 
 func (csg *columnSQLVertexGenerator) Add(column schema.Column) ([]Statement, error) {
@@ -1213,27 +2139,63 @@ func (csg *columnSQLVertexGenerator) Add(column schema.Column) ([]Statement, err
 	if err != nil {
 		return nil, fmt.Errorf("building column definition: %w", err)
 	}
-	return []Statement{{
+	addColumnStmt := Statement{
 		DDL:         fmt.Sprintf("%s ADD COLUMN %s", alterTablePrefix(csg.tableName), columnDef),
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
-	}}, nil
+	}
+	if column.IsGenerated {
+		addColumnStmt.Hazards = append(addColumnStmt.Hazards, migrationHazardGeneratedColumnAdded)
+	}
+	if column.IsDefaultVolatile {
+		addColumnStmt.Hazards = append(addColumnStmt.Hazards, migrationHazardColumnAddedWithVolatileDefault)
+	}
+	stmts := []Statement{addColumnStmt}
+	if len(column.StorageType) > 0 {
+		setStorageStmt, err := alterColumnStorageStatement(csg.tableName, column.Name, column.StorageType)
+		if err != nil {
+			return nil, fmt.Errorf("building set storage statement: %w", err)
+		}
+		stmts = append(stmts, setStorageStmt)
+	}
+	stmts = append(stmts, buildColumnCommentStatements(csg.tableName, nil, column)...)
+	return stmts, nil
+}
+
+// buildColumnCommentStatements builds the statements to change the comment on the column. oldColumn is nil if the
+// column is new.
+func buildColumnCommentStatements(table schema.SchemaQualifiedName, oldColumn *schema.Column, newColumn schema.Column) []Statement {
+	var oldComment *string
+	if oldColumn != nil {
+		oldComment = oldColumn.Comment
+	}
+	escapedColumnName := fmt.Sprintf("%s.%s", table.GetFQEscapedName(), schema.EscapeIdentifier(newColumn.Name))
+	return buildCommentStatements("COLUMN", escapedColumnName, oldComment, newColumn.Comment)
 }
 
 func (csg *columnSQLVertexGenerator) Delete(column schema.Column) ([]Statement, error) {
+	hazards := []MigrationHazard{
+		{
+			Type:    MigrationHazardTypeDeletesData,
+			Message: "Deletes all values in the column",
+		},
+	}
+	if csg.isDistributionColumn(column) {
+		hazards = append(hazards, migrationHazardCitusDistributionColumnChanged(column.Name))
+	}
 	return []Statement{{
 		DDL:         fmt.Sprintf("%s DROP COLUMN %s", alterTablePrefix(csg.tableName), schema.EscapeIdentifier(column.Name)),
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
-		Hazards: []MigrationHazard{
-			{
-				Type:    MigrationHazardTypeDeletesData,
-				Message: "Deletes all values in the column",
-			},
-		},
+		Hazards:     hazards,
 	}}, nil
 }
 
+// isDistributionColumn returns whether the column is the Citus distribution column of the table
+func (csg *columnSQLVertexGenerator) isDistributionColumn(column schema.Column) bool {
+	return len(csg.distributionColumn) > 0 && column.Name == csg.distributionColumn
+}
+
 func (csg *columnSQLVertexGenerator) Alter(diff columnDiff) ([]Statement, error) {
 	if diff.oldOrdering != diff.newOrdering {
 		return nil, fmt.Errorf("old=%d; new=%d: %w", diff.oldOrdering, diff.newOrdering, ErrColumnOrderingChanged)
@@ -1285,16 +2247,24 @@ func (csg *columnSQLVertexGenerator) Alter(diff columnDiff) ([]Statement, error)
 		})
 	}
 
-	if !strings.EqualFold(oldColumn.Type, newColumn.Type) ||
-		!strings.EqualFold(oldColumn.Collation.GetFQEscapedName(), newColumn.Collation.GetFQEscapedName()) {
+	isTypeTransformed := !strings.EqualFold(oldColumn.Type, newColumn.Type) ||
+		!strings.EqualFold(oldColumn.Collation.GetFQEscapedName(), newColumn.Collation.GetFQEscapedName())
+	if isTypeTransformed {
+		typeTransformationStmt := csg.generateTypeTransformationStatement(
+			diff.new,
+			oldColumn.Type,
+			newColumn.Type,
+			newColumn.Collation,
+		)
+		if csg.onlineColumnTypeChange && !strings.EqualFold(oldColumn.Type, newColumn.Type) {
+			typeTransformationStmt.Hazards = append(typeTransformationStmt.Hazards, migrationHazardColumnTypeChangedInPlace(oldColumn.Type, newColumn.Type))
+		}
+		if csg.isDistributionColumn(oldColumn) {
+			typeTransformationStmt.Hazards = append(typeTransformationStmt.Hazards, migrationHazardCitusDistributionColumnChanged(oldColumn.Name))
+		}
 		stmts = append(stmts,
 			[]Statement{
-				csg.generateTypeTransformationStatement(
-					diff.new,
-					oldColumn.Type,
-					newColumn.Type,
-					newColumn.Collation,
-				),
+				typeTransformationStmt,
 				// When "SET TYPE" is used to alter a column, that column's statistics are removed, which could
 				// affect query plans. In order to mitigate the effect on queries, re-generate the statistics for the
 				// column before continuing with the migration.
@@ -1324,9 +2294,82 @@ func (csg *columnSQLVertexGenerator) Alter(diff columnDiff) ([]Statement, error)
 		})
 	}
 
+	setStorageStmts, err := csg.buildSetStorageStatements(oldColumn, newColumn, isTypeTransformed)
+	if err != nil {
+		return nil, fmt.Errorf("building set storage statements: %w", err)
+	}
+	stmts = append(stmts, setStorageStmts...)
+
+	stmts = append(stmts, buildColumnCommentStatements(csg.tableName, &oldColumn, newColumn)...)
+
 	return stmts, nil
 }
 
+func (csg *columnSQLVertexGenerator) buildSetStorageStatements(old, new schema.Column, isTypeTransformed bool) ([]Statement, error) {
+	oldStorageType := old.StorageType
+	if isTypeTransformed {
+		// Changing the type of a column resets its storage strategy to the default storage strategy of the new type
+		oldStorageType = ""
+	}
+	if oldStorageType == new.StorageType {
+		return nil, nil
+	}
+
+	newStorageType := new.StorageType
+	if len(newStorageType) == 0 {
+		// The column is being reverted to the default storage strategy of its type
+		newStorageType = old.DefaultStorageType
+	}
+	stmt, err := alterColumnStorageStatement(csg.tableName, new.Name, newStorageType)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(oldStorageType) == 0 {
+		oldStorageType = new.DefaultStorageType
+	}
+	if isCompressibleStorageType(oldStorageType) && !isCompressibleStorageType(newStorageType) {
+		stmt.Hazards = append(stmt.Hazards, MigrationHazard{
+			Type: MigrationHazardTypeImpactsDatabasePerformance,
+			Message: "This storage strategy disables compression of the column's values, which can increase the size " +
+				"of the table. Only values written after the migration will be stored uncompressed.",
+		})
+	}
+	return []Statement{stmt}, nil
+}
+
+func alterColumnStorageStatement(table schema.SchemaQualifiedName, column string, storageType schema.ColumnStorageType) (Statement, error) {
+	storageTypeSQL, err := columnStorageTypeToSQL(storageType)
+	if err != nil {
+		return Statement{}, fmt.Errorf("getting storage type: %w", err)
+	}
+	return Statement{
+		DDL:         fmt.Sprintf("%s ALTER COLUMN %s SET STORAGE %s", alterTablePrefix(table), schema.EscapeIdentifier(column), storageTypeSQL),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}, nil
+}
+
+func columnStorageTypeToSQL(storageType schema.ColumnStorageType) (string, error) {
+	switch storageType {
+	case schema.ColumnStorageTypePlain:
+		return "PLAIN", nil
+	case schema.ColumnStorageTypeExternal:
+		return "EXTERNAL", nil
+	case schema.ColumnStorageTypeExtended:
+		return "EXTENDED", nil
+	case schema.ColumnStorageTypeMain:
+		return "MAIN", nil
+	default:
+		return "", fmt.Errorf("unknown storage type %q", storageType)
+	}
+}
+
+// isCompressibleStorageType returns whether values stored with the storage type can be compressed
+func isCompressibleStorageType(storageType schema.ColumnStorageType) bool {
+	return storageType == schema.ColumnStorageTypeExtended || storageType == schema.ColumnStorageTypeMain
+}
+
 func (csg *columnSQLVertexGenerator) generateTypeTransformationStatement(
 	col schema.Column,
 	oldType string,
@@ -1393,6 +2436,7 @@ func (csg *columnSQLVertexGenerator) buildUpdateIdentityStatements(old, new sche
 			DDL:         fmt.Sprintf("%s DROP IDENTITY", csg.alterColumnPrefix(old)),
 			Timeout:     statementTimeoutDefault,
 			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardColumnIdentityAltered},
 		}}, nil
 	}
 
@@ -1407,6 +2451,7 @@ func (csg *columnSQLVertexGenerator) buildUpdateIdentityStatements(old, new sche
 			DDL:         fmt.Sprintf("%s ADD %s", csg.alterColumnPrefix(new), def),
 			Timeout:     statementTimeoutDefault,
 			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardColumnIdentityAltered, migrationHazardColumnIdentityAdded},
 		}}, nil
 	}
 
@@ -1446,6 +2491,7 @@ func (csg *columnSQLVertexGenerator) buildUpdateIdentityStatements(old, new sche
 		DDL:         fmt.Sprintf("%s\n%s", csg.alterColumnPrefix(new), strings.Join(modifications, "\n")),
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardColumnIdentityAltered},
 	}}, nil
 }
 
@@ -1585,6 +2631,13 @@ type indexSQLVertexGenerator struct {
 	// indexesInNewSchemaByName is a map of index name to the index
 	// This is used to identify the parent index is a primary key
 	indexesInNewSchemaByName map[string]schema.Index
+	// distributionColumnsByTableName is a map of table name to the Citus distribution column of the table in the old
+	// schema. It is used to identify primary keys that cannot be added to distributed tables.
+	distributionColumnsByTableName map[string]string
+	// nonConcurrentIndexOps is true if indexes should be built without CONCURRENTLY
+	nonConcurrentIndexOps bool
+	// nonConcurrentIndexDrops is true if indexes should be dropped without CONCURRENTLY
+	nonConcurrentIndexDrops bool
 
 	// renameSQLVertexGenerator is used to find renames
 	renameSQLVertexGenerator *renameConflictingIndexSQLVertexGenerator
@@ -1601,6 +2654,11 @@ func (isg *indexSQLVertexGenerator) Add(index schema.Index) ([]Statement, error)
 	if _, isNewTable := isg.addedTablesByName[index.OwningTable.GetName()]; isNewTable {
 		stmts = stripMigrationHazards(stmts...)
 	}
+	if distributionColumn, ok := isg.distributionColumnsByTableName[index.OwningTable.GetName()]; ok &&
+		index.IsPk() && !containsString(index.Columns, distributionColumn) && len(stmts) > 0 {
+		stmts[len(stmts)-1].Hazards = append(stmts[len(stmts)-1].Hazards, migrationHazardCitusPrimaryKeyExcludesDistributionColumn(distributionColumn))
+	}
+	stmts = append(stmts, buildCommentStatements("INDEX", index.GetSchemaQualifiedName().GetFQEscapedName(), nil, index.Comment)...)
 	return stmts, nil
 }
 
@@ -1608,6 +2666,9 @@ func (isg *indexSQLVertexGenerator) addIdxStmtsWithHazards(index schema.Index) (
 	if index.IsInvalid {
 		return nil, fmt.Errorf("can't create an invalid index: %w", ErrNotImplemented)
 	}
+	if index.IsExclusionConstraint() {
+		return isg.addExclusionConstraintStmts(index)
+	}
 
 	var stmts []Statement
 	var createIdxStmtHazards []MigrationHazard
@@ -1623,33 +2684,41 @@ func (isg *indexSQLVertexGenerator) addIdxStmtsWithHazards(index schema.Index) (
 			// If the table is the base table of a partitioned table, the constraint should "ONLY" be added to the base
 			//table. We can then concurrently build all of the partitioned indexes and attach them.
 			// Without "ONLY", all the partitioned indexes will be automatically built
+			constraintDef := index.Constraint.ConstraintDef
+			if len(index.Tablespace) > 0 {
+				constraintDef += fmt.Sprintf(" USING INDEX%s", buildTablespaceClause(index.Tablespace))
+			}
 			return []Statement{{
-				DDL:         fmt.Sprintf("ALTER TABLE ONLY %s ADD CONSTRAINT %s %s", index.OwningTable.GetFQEscapedName(), index.Constraint.EscapedConstraintName, index.Constraint.ConstraintDef),
+				DDL:         fmt.Sprintf("ALTER TABLE ONLY %s ADD CONSTRAINT %s %s", index.OwningTable.GetFQEscapedName(), index.Constraint.EscapedConstraintName, constraintDef),
 				Timeout:     statementTimeoutDefault,
 				LockTimeout: lockTimeoutDefault,
 			}}, nil
 		}
-	} else if !isOnPartitionedTable {
+	} else if isg.nonConcurrentIndexOps {
+		createIdxStmtHazards = append(createIdxStmtHazards, migrationHazardIndexBuildNonConcurrently, migrationHazardIndexBuildAcquiresShareLock)
+		createIdxStmtTimeout = statementTimeoutConcurrentIndexBuild
+	} else {
 		// Only indexes on non-partitioned tables can be created concurrently
 		concurrentCreateIdxStmt, err := index.GetIndexDefStmt.ToCreateIndexConcurrently()
 		if err != nil {
 			return nil, fmt.Errorf("modifying index def statement to concurrently: %w", err)
 		}
 		createIdxStmt = concurrentCreateIdxStmt
-		createIdxStmtHazards = append(createIdxStmtHazards, MigrationHazard{
-			Type: MigrationHazardTypeIndexBuild,
-			Message: "This might affect database performance. " +
-				"Concurrent index builds require a non-trivial amount of CPU, potentially affecting database performance. " +
-				"They also can take a while but do not lock out writes.",
-		})
+		createIdxStmtHazards = append(createIdxStmtHazards, migrationHazardIndexBuildConcurrently)
 		createIdxStmtTimeout = statementTimeoutConcurrentIndexBuild
 	}
 
+	isConcurrent := createIdxStmt != string(index.GetIndexDefStmt)
+	createIdxStmt, err := addTablespaceToCreateIndexStmt(createIdxStmt, index)
+	if err != nil {
+		return nil, fmt.Errorf("adding tablespace to create index statement: %w", err)
+	}
 	stmts = append(stmts, Statement{
-		DDL:         createIdxStmt,
-		Timeout:     createIdxStmtTimeout,
-		LockTimeout: lockTimeoutDefault,
-		Hazards:     createIdxStmtHazards,
+		DDL:                   createIdxStmt,
+		Timeout:               createIdxStmtTimeout,
+		LockTimeout:           lockTimeoutDefault,
+		Hazards:               createIdxStmtHazards,
+		RequiresNoTransaction: isConcurrent,
 	})
 
 	if index.Constraint != nil {
@@ -1698,6 +2767,14 @@ func (isg *indexSQLVertexGenerator) Delete(index schema.Index) ([]Statement, err
 			escapedConstraintName = rename.EscapedName
 		}
 
+		hazards := []MigrationHazard{
+			migrationHazardIndexDroppedAcquiresLock,
+			migrationHazardIndexDroppedQueryPerf,
+		}
+		if index.IsPk() && !isg.tableHasPkInNewSchema(index.OwningTable) {
+			hazards = append(hazards, migrationHazardPrimaryKeyDropped)
+		}
+
 		// Dropping the constraint will automatically drop the index. There is no way to drop
 		// the constraint without dropping the index
 		return []Statement{
@@ -1706,10 +2783,7 @@ func (isg *indexSQLVertexGenerator) Delete(index schema.Index) ([]Statement, err
 
 				Timeout:     statementTimeoutDefault,
 				LockTimeout: lockTimeoutDefault,
-				Hazards: []MigrationHazard{
-					migrationHazardIndexDroppedAcquiresLock,
-					migrationHazardIndexDroppedQueryPerf,
-				},
+				Hazards:     hazards,
 			},
 		}, nil
 	}
@@ -1719,7 +2793,7 @@ func (isg *indexSQLVertexGenerator) Delete(index schema.Index) ([]Statement, err
 	dropIndexStmtTimeout := statementTimeoutConcurrentIndexDrop
 	if isOnPartitionedTable, err := isg.isOnPartitionedTable(index); err != nil {
 		return nil, err
-	} else if isOnPartitionedTable {
+	} else if isOnPartitionedTable || isg.nonConcurrentIndexDrops {
 		// Currently, postgres has no good way of dropping an index partition concurrently
 		concurrentlyModifier = ""
 		dropIndexStmtTimeout = statementTimeoutDefault
@@ -1735,10 +2809,11 @@ func (isg *indexSQLVertexGenerator) Delete(index schema.Index) ([]Statement, err
 	}
 
 	return []Statement{{
-		DDL:         fmt.Sprintf("DROP INDEX %s%s", concurrentlyModifier, indexName.GetFQEscapedName()),
-		Timeout:     dropIndexStmtTimeout,
-		LockTimeout: lockTimeoutDefault,
-		Hazards:     append(dropIndexStmtHazards, migrationHazardIndexDroppedQueryPerf),
+		DDL:                   fmt.Sprintf("DROP INDEX %s%s", concurrentlyModifier, indexName.GetFQEscapedName()),
+		Timeout:               dropIndexStmtTimeout,
+		LockTimeout:           lockTimeoutDefault,
+		Hazards:               append(dropIndexStmtHazards, migrationHazardIndexDroppedQueryPerf),
+		RequiresNoTransaction: len(concurrentlyModifier) > 0,
 	}}, nil
 }
 
@@ -1766,6 +2841,32 @@ func (isg *indexSQLVertexGenerator) Alter(diff indexDiff) ([]Statement, error) {
 		diff.old.ParentIdx = diff.new.ParentIdx
 	}
 
+	if diff.old.Tablespace != diff.new.Tablespace {
+		isOnPartitionedTable, err := isg.isOnPartitionedTable(diff.new)
+		if err != nil {
+			return nil, err
+		}
+		alterIndexPrefix := fmt.Sprintf("ALTER INDEX %s", diff.new.GetSchemaQualifiedName().GetFQEscapedName())
+		stmts = append(stmts, buildSetTablespaceStatement(alterIndexPrefix, diff.new.Tablespace, isOnPartitionedTable))
+		diff.old.Tablespace = diff.new.Tablespace
+	}
+
+	stmts = append(stmts, buildCommentStatements("INDEX", diff.new.GetSchemaQualifiedName().GetFQEscapedName(), diff.old.Comment, diff.new.Comment)...)
+	diff.old.Comment = diff.new.Comment
+
+	if canAlterBrinParameters(diff.old, diff.new) {
+		stmts = append(stmts, buildAlterBrinParametersStatements(diff.old, diff.new)...)
+		diff.old.PagesPerRange = diff.new.PagesPerRange
+		diff.old.Autosummarize = diff.new.Autosummarize
+		diff.old.GetIndexDefStmt = diff.new.GetIndexDefStmt
+	}
+
+	if canAlterGINParameters(diff.old, diff.new) {
+		stmts = append(stmts, buildAlterGINParametersStatements(diff.old, diff.new)...)
+		diff.old.GINParameters = diff.new.GINParameters
+		diff.old.GetIndexDefStmt = diff.new.GetIndexDefStmt
+	}
+
 	if !cmp.Equal(diff.old, diff.new) {
 		return nil, fmt.Errorf("index diff could not be resolved %s", cmp.Diff(diff.old, diff.new))
 	}
@@ -1777,6 +2878,17 @@ func (isg *indexSQLVertexGenerator) isOnPartitionedTable(index schema.Index) (bo
 	return isOnPartitionedTable(isg.tablesInNewSchemaByName, index)
 }
 
+// tableHasPkInNewSchema returns true if the table has a primary key in the new schema, e.g., the primary key is being
+// replaced by a different one
+func (isg *indexSQLVertexGenerator) tableHasPkInNewSchema(table schema.SchemaQualifiedName) bool {
+	for _, index := range isg.indexesInNewSchemaByName {
+		if index.OwningTable.GetName() == table.GetName() && index.IsPk() {
+			return true
+		}
+	}
+	return false
+}
+
 // Returns true if the table the index belongs too is partitioned. If the table is a partition of a
 // partitioned table, this will always return false
 func isOnPartitionedTable(tablesInNewSchemaByName map[string]schema.Table, index schema.Index) (bool, error) {
@@ -1787,16 +2899,46 @@ func isOnPartitionedTable(tablesInNewSchemaByName map[string]schema.Table, index
 	}
 }
 
+// addExclusionConstraintStmts builds the statements to add an exclusion constraint. Unlike primary key and unique
+// constraints, an exclusion constraint cannot be added using an index that was built concurrently, so its index is built
+// while the owning table is locked.
+func (isg *indexSQLVertexGenerator) addExclusionConstraintStmts(index schema.Index) ([]Statement, error) {
+	if isOnPartitionedTable, err := isg.isOnPartitionedTable(index); err != nil {
+		return nil, err
+	} else if isOnPartitionedTable || index.ParentIdx != nil {
+		return nil, fmt.Errorf("adding exclusion constraints on partitioned tables: %w", ErrNotImplemented)
+	}
+	if len(index.Tablespace) > 0 {
+		return nil, fmt.Errorf("adding exclusion constraints in a non-default tablespace: %w", ErrNotImplemented)
+	}
+
+	return []Statement{{
+		DDL:         fmt.Sprintf("%s %s", addConstraintPrefix(index.OwningTable, index.Constraint.EscapedConstraintName), index.Constraint.ConstraintDef),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards: []MigrationHazard{{
+			Type: MigrationHazardTypeAcquiresAccessExclusiveLock,
+			Message: "This will lock reads and writes to the owning table while the constraint's index is being built. " +
+				"Exclusion constraints cannot be built concurrently.",
+		}},
+	}}, nil
+}
+
 func (isg *indexSQLVertexGenerator) addIndexConstraint(index schema.Index) (Statement, error) {
 	sqlConstraintType, err := constraintTypeAsSQL(index.Constraint.Type)
 	if err != nil {
 		return Statement{}, fmt.Errorf("getting constraint type as SQL: %w", err)
 	}
+	ddl := fmt.Sprintf("%s %s USING INDEX %s",
+		addConstraintPrefix(index.OwningTable, index.Constraint.EscapedConstraintName),
+		sqlConstraintType,
+		index.GetSchemaQualifiedName().EscapedName)
+	if index.Constraint.Deferrable {
+		// The deferrability is not part of the index, so it must be specified when the constraint is attached
+		ddl = fmt.Sprintf("%s %s", ddl, deferrabilityClause(index.Constraint.Deferrable, index.Constraint.InitiallyDeferred))
+	}
 	return Statement{
-		DDL: fmt.Sprintf("%s %s USING INDEX %s",
-			addConstraintPrefix(index.OwningTable, index.Constraint.EscapedConstraintName),
-			sqlConstraintType,
-			index.GetSchemaQualifiedName().EscapedName),
+		DDL:         ddl,
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
 	}, nil
@@ -1804,10 +2946,13 @@ func (isg *indexSQLVertexGenerator) addIndexConstraint(index schema.Index) (Stat
 
 func constraintTypeAsSQL(constraintType schema.IndexConstraintType) (string, error) {
 	switch constraintType {
-	case "p":
+	case schema.PkIndexConstraintType:
 		return "PRIMARY KEY", nil
-	case "u":
+	case schema.UniqueIndexConstraintType:
 		return "UNIQUE", nil
+	case schema.ExclusionIndexConstraintType:
+		// Exclusion constraints cannot be added using an existing index
+		return "", fmt.Errorf("adding an exclusion constraint using an existing index: %w", ErrNotImplemented)
 	default:
 		return "", fmt.Errorf("unknown/unsupported index constraint type: %s", constraintType)
 	}
@@ -1842,6 +2987,11 @@ func (isg *indexSQLVertexGenerator) GetAddAlterDependencies(index, _ schema.Inde
 			mustRun(isg.GetSQLVertexId(index, diffTypeAddAlter)).after(buildIndexVertexId(*index.ParentIdx, diffTypeAddAlter)))
 	}
 
+	for _, operatorClass := range index.DependsOnOperatorClasses {
+		dependencies = append(dependencies,
+			mustRun(isg.GetSQLVertexId(index, diffTypeAddAlter)).after(buildOperatorClassVertexId(operatorClass, diffTypeAddAlter)))
+	}
+
 	return dependencies, nil
 }
 
@@ -1858,6 +3008,10 @@ func (isg *indexSQLVertexGenerator) GetDeleteDependencies(index schema.Index) ([
 		dependencies = append(dependencies,
 			mustRun(isg.GetSQLVertexId(index, diffTypeDelete)).after(buildIndexVertexId(*index.ParentIdx, diffTypeDelete)))
 	}
+	for _, operatorClass := range index.DependsOnOperatorClasses {
+		dependencies = append(dependencies,
+			mustRun(isg.GetSQLVertexId(index, diffTypeDelete)).before(buildOperatorClassVertexId(operatorClass, diffTypeDelete)))
+	}
 	dependencies = append(dependencies, isg.addDepsOnTableAddAlterIfNecessary(index)...)
 
 	return dependencies, nil
@@ -1893,7 +3047,8 @@ func (isg *indexSQLVertexGenerator) addDepsOnTableAddAlterIfNecessary(index sche
 	}
 
 	parentTableColumnsByName := buildSchemaObjByNameMap(parentTable.Columns)
-	for _, idxColumn := range index.Columns {
+	idxColumns := append(append([]string(nil), index.Columns...), index.IncludedColumns...)
+	for _, idxColumn := range idxColumns {
 		// We need to force the index drop to come before the statements to drop columns. Otherwise, the columns
 		// drops will force the index to drop non-concurrently
 		if _, columnStillPresent := parentTableColumnsByName[idxColumn]; !columnStillPresent {
@@ -1912,6 +3067,7 @@ type checkConstraintSQLVertexGenerator struct {
 	addedColumnsByName     map[string]schema.Column
 	deletedColumnsByName   map[string]schema.Column
 	isNewTable             bool
+	constraintValidation   constraintValidationOptions
 }
 
 func (csg *checkConstraintSQLVertexGenerator) Add(con schema.CheckConstraint) ([]Statement, error) {
@@ -1922,7 +3078,7 @@ func (csg *checkConstraintSQLVertexGenerator) Add(con schema.CheckConstraint) ([
 	}
 
 	var stmts []Statement
-	if !con.IsValid || csg.isNewTable {
+	if !con.IsValid || csg.isNewTable || !csg.constraintValidation.shouldAddNotValid(csg.tableName) {
 		stmts = append(stmts, csg.createCheckConstraintStatement(con))
 	} else {
 		// If the check constraint is not on a new table and is marked as valid, we should:
@@ -1930,7 +3086,7 @@ func (csg *checkConstraintSQLVertexGenerator) Add(con schema.CheckConstraint) ([
 		// 2. Validate the constraint
 		con.IsValid = false
 		stmts = append(stmts, csg.createCheckConstraintStatement(con))
-		stmts = append(stmts, validateConstraintStatement(csg.tableName, schema.EscapeIdentifier(con.Name)))
+		stmts = append(stmts, csg.constraintValidation.validateConstraintStatement(csg.tableName, schema.EscapeIdentifier(con.Name), checkConstraintValidationRowsPerSecond))
 	}
 
 	return stmts, nil
@@ -1982,7 +3138,7 @@ func (csg *checkConstraintSQLVertexGenerator) Alter(diff checkConstraintDiff) ([
 
 	var stmts []Statement
 	if !diff.old.IsValid && diff.new.IsValid {
-		stmts = append(stmts, validateConstraintStatement(csg.tableName, schema.EscapeIdentifier(diff.new.Name)))
+		stmts = append(stmts, csg.constraintValidation.validateConstraintStatement(csg.tableName, schema.EscapeIdentifier(diff.new.Name), checkConstraintValidationRowsPerSecond))
 		oldCopy.IsValid = diff.new.IsValid
 	}
 
@@ -2095,6 +3251,12 @@ func getTargetColumns(targetColumnNames []string, columnsByName map[string]schem
 type attachPartitionSQLVertexGenerator struct {
 	indexesInNewSchemaByTableName map[string][]schema.Index
 	addedTablesByName             map[string]schema.Table
+	// partitionsWithChangedBoundsByParentName is a map of partitioned table name to its existing partitions whose
+	// bounds changed. These partitions are detached and re-attached with their new bounds
+	partitionsWithChangedBoundsByParentName map[string][]schema.Table
+	// hasDefaultPartitionByTableName is a map of partitioned table name to whether it has a default partition in the
+	// old schema
+	hasDefaultPartitionByTableName map[string]bool
 
 	// isPartitionAttachedAfterIdxBuildsByTableName is a map of table name to whether or not the table partition will be
 	// attached after its indexes are built. This is useful for determining when indexes need to be attached
@@ -2103,10 +3265,20 @@ type attachPartitionSQLVertexGenerator struct {
 	sqlVertexGenerator[schema.Table, tableDiff]
 }
 
-func newAttachPartitionSQLVertexGenerator(newSchemaIndexes []schema.Index, addedTables []schema.Table) *attachPartitionSQLVertexGenerator {
+func newAttachPartitionSQLVertexGenerator(oldSchemaTables []schema.Table, newSchemaIndexes []schema.Index, tableDiffs listDiff[schema.Table, tableDiff]) *attachPartitionSQLVertexGenerator {
+	partitionsWithChangedBoundsByParentName := make(map[string][]schema.Table)
+	for _, diff := range tableDiffs.alters {
+		if isPartitionBoundsChanged(diff.old, diff.new) {
+			parentName := diff.new.ParentTable.GetName()
+			partitionsWithChangedBoundsByParentName[parentName] = append(partitionsWithChangedBoundsByParentName[parentName], diff.new)
+		}
+	}
+
 	asg := &attachPartitionSQLVertexGenerator{
-		indexesInNewSchemaByTableName: buildIndexesByTableNameMap(newSchemaIndexes),
-		addedTablesByName:             buildSchemaObjByNameMap(addedTables),
+		indexesInNewSchemaByTableName:           buildIndexesByTableNameMap(newSchemaIndexes),
+		addedTablesByName:                       buildSchemaObjByNameMap(tableDiffs.adds),
+		partitionsWithChangedBoundsByParentName: partitionsWithChangedBoundsByParentName,
+		hasDefaultPartitionByTableName:          buildHasDefaultPartitionByTableNameMap(oldSchemaTables),
 
 		isPartitionAttachedAfterIdxBuildsByTableName: make(map[string]bool),
 	}
@@ -2115,15 +3287,21 @@ func newAttachPartitionSQLVertexGenerator(newSchemaIndexes []schema.Index, added
 	return asg
 }
 
-func (*attachPartitionSQLVertexGenerator) Add(table schema.Table) ([]Statement, error) {
+func (a *attachPartitionSQLVertexGenerator) Add(table schema.Table) ([]Statement, error) {
 	if table.ParentTable == nil {
 		return nil, nil
 	}
 
+	var hazards []MigrationHazard
+	if a.hasDefaultPartitionByTableName[table.ParentTable.GetName()] && !table.IsDefaultPartition() {
+		// The default partition must be scanned for rows that belong in the new partition
+		hazards = append(hazards, migrationHazardPartitionAttachScansDefaultPartition)
+	}
 	return []Statement{{
 		DDL:         fmt.Sprintf("%s ATTACH PARTITION %s %s", alterTablePrefix(*table.ParentTable), table.SchemaQualifiedName.GetFQEscapedName(), table.ForValues),
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
+		Hazards:     hazards,
 	}}, nil
 }
 
@@ -2148,6 +3326,11 @@ func (a *attachPartitionSQLVertexGenerator) GetAddAlterDependencies(table, old s
 	deps := []dependency{
 		mustRun(a.GetSQLVertexId(table, diffTypeAddAlter)).after(buildTableVertexId(table.SchemaQualifiedName, diffTypeAddAlter)),
 	}
+	for _, partition := range a.partitionsWithChangedBoundsByParentName[table.ParentTable.GetName()] {
+		// The bounds of the new partition might overlap with the old bounds of its sibling, e.g., when a partition is
+		// split, so the new partition must be attached after its sibling is re-attached with its new bounds
+		deps = append(deps, mustRun(a.GetSQLVertexId(table, diffTypeAddAlter)).after(buildTableVertexId(partition.SchemaQualifiedName, diffTypeAddAlter)))
+	}
 
 	if _, baseTableIsNew := a.addedTablesByName[table.ParentTable.GetName()]; baseTableIsNew {
 		// If the base table is new, we should force the partition to be attached before we build any non-local indexes.
@@ -2221,9 +3404,11 @@ type foreignKeyConstraintSQLVertexGenerator struct {
 	// childrenInNewSchemaByPartitionedIndexName gives all child indexes (across all levels) for a given
 	// partitioned index in the new schema.
 	childrenInNewSchemaByPartitionedIndexName map[string][]schema.Index
+	// constraintValidation configures whether foreign keys are added as NOT VALID
+	constraintValidation constraintValidationOptions
 }
 
-func newForeignKeyConstraintSQLVertexGenerator(oldAndNewSchema oldAndNew[schema.Schema], tableDiffs listDiff[schema.Table, tableDiff]) sqlVertexGenerator[schema.ForeignKeyConstraint, foreignKeyConstraintDiff] {
+func newForeignKeyConstraintSQLVertexGenerator(oldAndNewSchema oldAndNew[schema.Schema], tableDiffs listDiff[schema.Table, tableDiff], constraintValidation constraintValidationOptions) sqlVertexGenerator[schema.ForeignKeyConstraint, foreignKeyConstraintDiff] {
 	return legacyToNewSqlVertexGenerator[schema.ForeignKeyConstraint, foreignKeyConstraintDiff](&foreignKeyConstraintSQLVertexGenerator{
 		newSchemaTablesByName:                     buildSchemaObjByNameMap(oldAndNewSchema.new.Tables),
 		addedTablesByName:                         buildSchemaObjByNameMap(tableDiffs.adds),
@@ -2231,11 +3416,12 @@ func newForeignKeyConstraintSQLVertexGenerator(oldAndNewSchema oldAndNew[schema.
 		childrenInOldSchemaByPartitionedIndexName: buildChildrenByPartitionedIndexNameMap(oldAndNewSchema.old.Indexes),
 		indexesInNewSchemaByTableName:             buildIndexesByTableNameMap(oldAndNewSchema.new.Indexes),
 		childrenInNewSchemaByPartitionedIndexName: buildChildrenByPartitionedIndexNameMap(oldAndNewSchema.new.Indexes),
+		constraintValidation:                      constraintValidation,
 	})
 }
 
 func (f *foreignKeyConstraintSQLVertexGenerator) Add(con schema.ForeignKeyConstraint) ([]Statement, error) {
-	if con.IsValid {
+	if con.IsValid && f.constraintValidation.shouldAddNotValid(con.OwningTable) {
 		table, ok := f.newSchemaTablesByName[con.OwningTable.GetName()]
 		if !ok {
 			return nil, fmt.Errorf("could not find table with name %s", con.OwningTable.GetName())
@@ -2273,7 +3459,7 @@ func (f *foreignKeyConstraintSQLVertexGenerator) addAsInvalidThenValidateStateme
 			Timeout:     statementTimeoutDefault,
 			LockTimeout: lockTimeoutDefault,
 		},
-		validateConstraintStatement(con.OwningTable, con.EscapedName),
+		f.constraintValidation.validateConstraintStatement(con.OwningTable, con.EscapedName, foreignKeyValidationRowsPerSecond),
 	}
 }
 
@@ -2299,7 +3485,24 @@ func (f *foreignKeyConstraintSQLVertexGenerator) Alter(diff foreignKeyConstraint
 			return nil, fmt.Errorf("expected the old constraint def to be suffixed with NOT VALID: %q", diff.old.ConstraintDef)
 		}
 		diff.old.ConstraintDef = strings.TrimSuffix(diff.old.ConstraintDef, " NOT VALID")
-		stmts = append(stmts, validateConstraintStatement(diff.new.OwningTable, diff.new.EscapedName))
+		stmts = append(stmts, f.constraintValidation.validateConstraintStatement(diff.new.OwningTable, diff.new.EscapedName, foreignKeyValidationRowsPerSecond))
+	}
+	if diff.old.Deferrable != diff.new.Deferrable || diff.old.InitiallyDeferred != diff.new.InitiallyDeferred {
+		// Similar to above, strip the deferrability clauses from both constraint defs. If the defs are then equal,
+		// only the deferrability has changed, and the constraint can be altered in place
+		oldDefWithoutDeferrability := trimDeferrabilityClause(diff.old.ConstraintDef, diff.old.Deferrable, diff.old.InitiallyDeferred)
+		newDefWithoutDeferrability := trimDeferrabilityClause(diff.new.ConstraintDef, diff.new.Deferrable, diff.new.InitiallyDeferred)
+		if oldDefWithoutDeferrability == newDefWithoutDeferrability {
+			diff.old.Deferrable = diff.new.Deferrable
+			diff.old.InitiallyDeferred = diff.new.InitiallyDeferred
+			diff.old.ConstraintDef = diff.new.ConstraintDef
+			stmts = append(stmts, Statement{
+				DDL: fmt.Sprintf("%s ALTER CONSTRAINT %s %s", alterTablePrefix(diff.new.OwningTable), diff.new.EscapedName,
+					deferrabilityClause(diff.new.Deferrable, diff.new.InitiallyDeferred)),
+				Timeout:     statementTimeoutDefault,
+				LockTimeout: lockTimeoutDefault,
+			})
+		}
 	}
 	if !cmp.Equal(diff.old, diff.new) {
 		return nil, fmt.Errorf("altering foreign key constraint to resolve the following diff %s: %w", cmp.Diff(diff.old, diff.new), ErrNotImplemented)
@@ -2308,6 +3511,36 @@ func (f *foreignKeyConstraintSQLVertexGenerator) Alter(diff foreignKeyConstraint
 	return stmts, nil
 }
 
+// deferrabilityClause returns the clause that sets the deferrability of a constraint, e.g.,
+// "DEFERRABLE INITIALLY DEFERRED"
+func deferrabilityClause(deferrable, initiallyDeferred bool) string {
+	if !deferrable {
+		return "NOT DEFERRABLE"
+	}
+	if initiallyDeferred {
+		return "DEFERRABLE INITIALLY DEFERRED"
+	}
+	return "DEFERRABLE INITIALLY IMMEDIATE"
+}
+
+// trimDeferrabilityClause removes the deferrability clause from a constraint def returned by pg_get_constraintdef.
+// Postgres only includes the clause if the constraint is deferrable, and it always precedes the "NOT VALID" suffix.
+// The def is returned as is if the expected clause is not found.
+func trimDeferrabilityClause(constraintDef string, deferrable, initiallyDeferred bool) string {
+	if !deferrable {
+		return constraintDef
+	}
+	clause := " DEFERRABLE"
+	if initiallyDeferred {
+		clause += " INITIALLY DEFERRED"
+	}
+	const notValidSuffix = " NOT VALID"
+	if strings.HasSuffix(constraintDef, clause+notValidSuffix) {
+		return strings.TrimSuffix(constraintDef, clause+notValidSuffix) + notValidSuffix
+	}
+	return strings.TrimSuffix(constraintDef, clause)
+}
+
 func (*foreignKeyConstraintSQLVertexGenerator) GetSQLVertexId(con schema.ForeignKeyConstraint, diffType diffType) sqlVertexId {
 	return buildSchemaObjVertexId("fkconstraint", con.GetName(), diffType)
 }
@@ -2354,12 +3587,17 @@ func (f *foreignKeyConstraintSQLVertexGenerator) GetDeleteDependencies(con schem
 type sequenceSQLVertexGenerator struct {
 	deletedTablesByName map[string]schema.Table
 	tableDiffsByName    map[string]tableDiff
+	// existingRoles is the set of roles known to exist in the target database. See buildExistingRoles.
+	existingRoles map[string]bool
 }
 
 func (s *sequenceSQLVertexGenerator) Add(seq schema.Sequence) ([]Statement, error) {
-	return []Statement{
+	// The owner is set before the sequence is owned by a column (see sequenceOwnershipSQLVertexGenerator), since
+	// Postgres requires a sequence to have the same owner as the table that owns it
+	stmts := []Statement{
 		s.buildAddAlterSequenceStatement(seq, false),
-	}, nil
+	}
+	return append(stmts, buildAlterOwnerStatements("SEQUENCE", seq.GetFQEscapedName(), "", seq.OwnerRole, s.existingRoles)...), nil
 }
 
 func (s *sequenceSQLVertexGenerator) Delete(seq schema.Sequence) ([]Statement, error) {
@@ -2382,6 +3620,13 @@ func (s *sequenceSQLVertexGenerator) Delete(seq schema.Sequence) ([]Statement, e
 
 func (s *sequenceSQLVertexGenerator) Alter(diff sequenceDiff) ([]Statement, error) {
 	var stmts []Statement
+	// The owner of a sequence that is owned by a column cannot be changed directly. It changes with the owner of the
+	// table instead
+	if diff.old.Owner == nil {
+		stmts = append(stmts, buildAlterOwnerStatements("SEQUENCE", diff.new.GetFQEscapedName(), diff.old.OwnerRole, diff.new.OwnerRole, s.existingRoles)...)
+	}
+	diff.old.OwnerRole = diff.new.OwnerRole
+
 	// Ownership changes handled by the sequenceOwnershipSQLVertexGenerator
 	diff.old.Owner = diff.new.Owner
 
@@ -2394,7 +3639,14 @@ func (s *sequenceSQLVertexGenerator) Alter(diff sequenceDiff) ([]Statement, erro
 		diff.old.StartValue != diff.new.StartValue ||
 		diff.old.CacheSize != diff.new.CacheSize ||
 		diff.old.Cycle != diff.new.Cycle {
-		stmts = append(stmts, s.buildAddAlterSequenceStatement(diff.new, true))
+		stmt := s.buildAddAlterSequenceStatement(diff.new, true)
+		if diff.old.Increment != diff.new.Increment || diff.old.CacheSize != diff.new.CacheSize {
+			stmt.Hazards = append(stmt.Hazards, migrationHazardSequenceIncrementOrCacheChanged)
+		}
+		if diff.old.StartValue != diff.new.StartValue {
+			stmt.Hazards = append(stmt.Hazards, migrationHazardSequenceStartValueChanged)
+		}
+		stmts = append(stmts, stmt)
 
 		// Diffs handled by alter statement
 		diff.old.Type = diff.new.Type
@@ -2602,6 +3854,10 @@ func (e *extensionSQLGenerator) Alter(diff extensionDiff) ([]Statement, error) {
 		} else {
 			// We optimistically assume an update path from the old to new version exists. When we
 			// validate the plan later, any issues will be caught and an error will be thrown.
+			hazard := migrationHazardExtensionAlteredVersionUpgraded
+			if isExtensionVersionDowngrade(diff.old.Version, diff.new.Version) {
+				hazard = migrationHazardExtensionAlteredVersionDowngraded
+			}
 			statements = append(statements, Statement{
 				DDL: fmt.Sprintf(
 					"ALTER EXTENSION %s UPDATE TO %s",
@@ -2610,13 +3866,49 @@ func (e *extensionSQLGenerator) Alter(diff extensionDiff) ([]Statement, error) {
 				),
 				Timeout:     statementTimeoutDefault,
 				LockTimeout: lockTimeoutDefault,
-				Hazards:     []MigrationHazard{migrationHazardExtensionAlteredVersionUpgraded},
+				Hazards:     []MigrationHazard{hazard},
 			})
 		}
 	}
 	return statements, nil
 }
 
+// isExtensionVersionDowngrade returns true if the new version is lower than the old version. Extension versions are
+// arbitrary strings, so only versions made up of dot-separated numbers, e.g., "1.5" or "3.4.0", are compared. Any other
+// version change is assumed to be an upgrade.
+func isExtensionVersionDowngrade(oldVersion, newVersion string) bool {
+	oldParts, oldOk := parseExtensionVersion(oldVersion)
+	newParts, newOk := parseExtensionVersion(newVersion)
+	if !oldOk || !newOk {
+		return false
+	}
+	for i := 0; i < len(oldParts) || i < len(newParts); i++ {
+		var oldPart, newPart int
+		if i < len(oldParts) {
+			oldPart = oldParts[i]
+		}
+		if i < len(newParts) {
+			newPart = newParts[i]
+		}
+		if oldPart != newPart {
+			return newPart < oldPart
+		}
+	}
+	return false
+}
+
+func parseExtensionVersion(version string) ([]int, bool) {
+	var parts []int
+	for _, rawPart := range strings.Split(version, ".") {
+		part, err := strconv.Atoi(rawPart)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, part)
+	}
+	return parts, true
+}
+
 type triggerSQLVertexGenerator struct {
 	// functionsInNewSchemaByName is a map of function new to functions in the new schema.
 	// These functions are not necessarily new
@@ -2628,6 +3920,7 @@ func (t *triggerSQLVertexGenerator) Add(trigger schema.Trigger) ([]Statement, er
 		DDL:         string(trigger.GetTriggerDefStmt),
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
+		Hazards:     t.getTriggerFunctionHazards(trigger),
 	}}, nil
 }
 
@@ -2652,9 +3945,21 @@ func (t *triggerSQLVertexGenerator) Alter(diff triggerDiff) ([]Statement, error)
 		DDL:         createOrReplaceStmt,
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
+		Hazards:     t.getTriggerFunctionHazards(diff.new),
 	}}, nil
 }
 
+// getTriggerFunctionHazards returns the hazards of (re)creating a trigger that executes a non-sql function. The
+// dependencies of non-sql functions, e.g., the columns and tables the function body references, cannot be tracked,
+// so the trigger might start failing at runtime if those objects are altered or dropped.
+func (t *triggerSQLVertexGenerator) getTriggerFunctionHazards(trigger schema.Trigger) []MigrationHazard {
+	function, ok := t.functionsInNewSchemaByName[trigger.Function.GetName()]
+	if !ok || canFunctionDependenciesBeTracked(function) {
+		return nil
+	}
+	return []MigrationHazard{migrationHazardTriggerFunctionDependenciesUntrackable}
+}
+
 func (t *triggerSQLVertexGenerator) GetSQLVertexId(trigger schema.Trigger, diffType diffType) sqlVertexId {
 	return buildSchemaObjVertexId("trigger", trigger.GetName(), diffType)
 }
@@ -2704,11 +4009,15 @@ func dropConstraintDDL(table schema.SchemaQualifiedName, escapedConstraintName s
 	return fmt.Sprintf("%s DROP CONSTRAINT %s", alterTablePrefix(table), escapedConstraintName)
 }
 
+// validateConstraintStatement validates a constraint that was added as NOT VALID. Validating a constraint only acquires a
+// SHARE UPDATE EXCLUSIVE lock on the owning table, so it does not block reads or writes while the existing rows are
+// scanned. This is why constraints on existing tables are added as NOT VALID and then validated.
 func validateConstraintStatement(owningTable schema.SchemaQualifiedName, escapedConstraintName string) Statement {
 	return Statement{
 		DDL:         fmt.Sprintf("%s VALIDATE CONSTRAINT %s", alterTablePrefix(owningTable), escapedConstraintName),
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardValidateConstraint},
 	}
 }
 
@@ -2725,6 +4034,9 @@ func buildColumnDefinition(column schema.Column) (string, error) {
 	if !column.IsNullable {
 		sb.WriteString(" NOT NULL")
 	}
+	if column.IsGenerated {
+		sb.WriteString(fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", column.GenerationExpression))
+	}
 	if len(column.Default) > 0 {
 		sb.WriteString(fmt.Sprintf(" DEFAULT %s", column.Default))
 	}
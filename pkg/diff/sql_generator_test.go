@@ -1,9 +1,14 @@
 package diff
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
 )
 
 func TestIsNotNullCCRegex(t *testing.T) {
@@ -24,3 +29,986 @@ func TestIsNotNullCCRegex(t *testing.T) {
 		})
 	}
 }
+
+// TestSchemaDiff_ObjectTypes ensures a SQL generator is registered, i.e., the schema diff has a list diff, for every object
+// type that is diffed. The acceptance tests rely on this to check that every diffed object type is exercised.
+func TestSchemaDiff_ObjectTypes(t *testing.T) {
+	diffedObjectTypes := make(map[reflect.Type]bool)
+	schemaDiffType := reflect.TypeOf(schemaDiff{})
+	for i := 0; i < schemaDiffType.NumField(); i++ {
+		if adds, ok := schemaDiffType.Field(i).Type.FieldByName("adds"); ok {
+			diffedObjectTypes[adds.Type.Elem()] = true
+		}
+	}
+
+	var objectTypesWithoutGenerator []string
+	schemaType := reflect.TypeOf(schema.Schema{})
+	for i := 0; i < schemaType.NumField(); i++ {
+		field := schemaType.Field(i)
+		if field.Tag.Get("hash") == "ignore" {
+			continue
+		}
+		if !diffedObjectTypes[field.Type.Elem()] {
+			objectTypesWithoutGenerator = append(objectTypesWithoutGenerator, field.Name)
+		}
+	}
+	assert.Empty(t, objectTypesWithoutGenerator)
+}
+
+func TestHasColumnOrderChanged(t *testing.T) {
+	buildTable := func(columnNames ...string) schema.Table {
+		var columns []schema.Column
+		for _, name := range columnNames {
+			columns = append(columns, schema.Column{Name: name, Type: "integer"})
+		}
+		return schema.Table{Columns: columns}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		old      schema.Table
+		new      schema.Table
+		expected bool
+	}{
+		{name: "Same order", old: buildTable("a", "b", "c"), new: buildTable("a", "b", "c"), expected: false},
+		{name: "Swapped columns", old: buildTable("a", "b", "c"), new: buildTable("b", "a", "c"), expected: true},
+		{name: "Column added in the middle", old: buildTable("a", "c"), new: buildTable("a", "b", "c"), expected: false},
+		{name: "Column dropped", old: buildTable("a", "b", "c"), new: buildTable("a", "c"), expected: false},
+		{name: "Column added and columns swapped", old: buildTable("a", "c"), new: buildTable("c", "b", "a"), expected: true},
+		{name: "No shared columns", old: buildTable("a"), new: buildTable("b"), expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, hasColumnOrderChanged(tc.old, tc.new))
+		})
+	}
+}
+
+func TestBuildPlan_ColumnOrderChange(t *testing.T) {
+	buildSchema := func(columnNames ...string) schema.Schema {
+		var columns []schema.Column
+		for _, name := range columnNames {
+			columns = append(columns, schema.Column{Name: name, Type: "integer"})
+		}
+		return schema.Schema{Tables: []schema.Table{{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`},
+			Columns:             columns,
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		}}}
+	}
+
+	// The change in order cannot be applied, so it is surfaced as a hazard of the plan even though no statement is
+	// generated for the table
+	plan, err := buildPlan(buildSchema("a", "b"), buildSchema("b", "a"), &planOptions{ignoreChangesToColOrder: true})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Statements)
+	require.Len(t, plan.Hazards, 1)
+	assert.Equal(t, MigrationHazardTypeColumnOrderChange, plan.Hazards[0].Type)
+	assert.Contains(t, plan.Hazards[0].Message, `"public"."foobar"`)
+
+	plan, err = buildPlan(buildSchema("a", "b"), buildSchema("a", "b", "c"), &planOptions{ignoreChangesToColOrder: true})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Hazards)
+}
+
+func TestSequenceSQLVertexGenerator_AlterHazards(t *testing.T) {
+	seq := schema.Sequence{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar_seq\""},
+		Type:                "integer",
+		StartValue:          1,
+		Increment:           1,
+		MaxValue:            100,
+		MinValue:            1,
+		CacheSize:           1,
+	}
+	for _, tc := range []struct {
+		name            string
+		alter           func(seq *schema.Sequence)
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name:  "max value changed",
+			alter: func(seq *schema.Sequence) { seq.MaxValue = 200 },
+		},
+		{
+			name:            "increment changed",
+			alter:           func(seq *schema.Sequence) { seq.Increment = 2 },
+			expectedHazards: []MigrationHazard{migrationHazardSequenceIncrementOrCacheChanged},
+		},
+		{
+			name:            "cache changed",
+			alter:           func(seq *schema.Sequence) { seq.CacheSize = 10 },
+			expectedHazards: []MigrationHazard{migrationHazardSequenceIncrementOrCacheChanged},
+		},
+		{
+			name: "start value and increment changed",
+			alter: func(seq *schema.Sequence) {
+				seq.StartValue = 10
+				seq.Increment = 2
+			},
+			expectedHazards: []MigrationHazard{
+				migrationHazardSequenceIncrementOrCacheChanged,
+				migrationHazardSequenceStartValueChanged,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			newSeq := seq
+			tc.alter(&newSeq)
+			stmts, err := (&sequenceSQLVertexGenerator{}).Alter(sequenceDiff{oldAndNew: oldAndNew[schema.Sequence]{
+				old: seq,
+				new: newSeq,
+			}})
+			assert.NoError(t, err)
+			assert.Len(t, stmts, 1)
+			assert.Equal(t, tc.expectedHazards, stmts[0].Hazards)
+		})
+	}
+}
+
+func TestTriggerSQLVertexGenerator_Hazards(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	function := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"increment_version\"()"}
+	trigger := schema.Trigger{
+		EscapedName:       "\"some_trigger\"",
+		OwningTable:       foobar,
+		Function:          function,
+		GetTriggerDefStmt: "CREATE TRIGGER some_trigger BEFORE UPDATE ON public.foobar FOR EACH ROW EXECUTE FUNCTION increment_version()",
+	}
+	for _, tc := range []struct {
+		name            string
+		language        string
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name:            "plpgsql function",
+			language:        "plpgsql",
+			expectedHazards: []MigrationHazard{migrationHazardTriggerFunctionDependenciesUntrackable},
+		},
+		{
+			name:     "sql function",
+			language: "sql",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			generator := &triggerSQLVertexGenerator{
+				functionsInNewSchemaByName: map[string]schema.Function{
+					function.GetName(): {SchemaQualifiedName: function, Language: tc.language},
+				},
+			}
+
+			stmts, err := generator.Add(trigger)
+			require.NoError(t, err)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tc.expectedHazards, stmts[0].Hazards)
+
+			newTrigger := trigger
+			newTrigger.GetTriggerDefStmt = "CREATE TRIGGER some_trigger BEFORE UPDATE ON public.foobar FOR EACH ROW WHEN ((new.id > 0)) EXECUTE FUNCTION increment_version()"
+			stmts, err = generator.Alter(triggerDiff{oldAndNew: oldAndNew[schema.Trigger]{old: trigger, new: newTrigger}})
+			require.NoError(t, err)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tc.expectedHazards, stmts[0].Hazards)
+
+			stmts, err = generator.Delete(trigger)
+			require.NoError(t, err)
+			require.Len(t, stmts, 1)
+			assert.Empty(t, stmts[0].Hazards)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_NonConcurrentIndexOperations(t *testing.T) {
+	dropConcurrently := true
+	doNotDropConcurrently := false
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	table := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             []schema.Column{{Name: "foo", Type: "text"}, {Name: "bar", Type: "text"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	oldSchema := schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{{
+		OwningTable:     foobar,
+		Name:            "old_idx",
+		Columns:         []string{"foo"},
+		GetIndexDefStmt: "CREATE INDEX old_idx ON public.foobar USING btree (foo)",
+	}}}
+	newSchema := schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{{
+		OwningTable:     foobar,
+		Name:            "new_idx",
+		Columns:         []string{"bar"},
+		GetIndexDefStmt: "CREATE INDEX new_idx ON public.foobar USING btree (bar)",
+	}}}
+
+	for _, tc := range []struct {
+		name               string
+		opts               planOptions
+		expectedStatements []Statement
+	}{
+		{
+			name: "Concurrent index operations",
+			expectedStatements: []Statement{
+				{
+					DDL:                   "CREATE INDEX CONCURRENTLY new_idx ON public.foobar USING btree (bar)",
+					Timeout:               statementTimeoutConcurrentIndexBuild,
+					LockTimeout:           lockTimeoutDefault,
+					Hazards:               []MigrationHazard{migrationHazardIndexBuildConcurrently},
+					RequiresNoTransaction: true,
+				},
+				{
+					DDL:                   "DROP INDEX CONCURRENTLY \"public\".\"old_idx\"",
+					Timeout:               statementTimeoutConcurrentIndexDrop,
+					LockTimeout:           lockTimeoutDefault,
+					Hazards:               []MigrationHazard{migrationHazardIndexDroppedQueryPerf},
+					RequiresNoTransaction: true,
+				},
+			},
+		},
+		{
+			name: "Non-concurrent index operations",
+			opts: planOptions{nonConcurrentIndexOps: true},
+			expectedStatements: []Statement{
+				{
+					DDL:         "CREATE INDEX new_idx ON public.foobar USING btree (bar)",
+					Timeout:     statementTimeoutConcurrentIndexBuild,
+					LockTimeout: lockTimeoutDefault,
+					Hazards:     []MigrationHazard{migrationHazardIndexBuildNonConcurrently, migrationHazardIndexBuildAcquiresShareLock},
+				},
+				{
+					DDL:         "DROP INDEX \"public\".\"old_idx\"",
+					Timeout:     statementTimeoutDefault,
+					LockTimeout: lockTimeoutDefault,
+					Hazards:     []MigrationHazard{migrationHazardIndexDroppedAcquiresLock, migrationHazardIndexDroppedQueryPerf},
+				},
+			},
+		},
+		{
+			name: "Non-concurrent index drops",
+			opts: planOptions{dropIndexesConcurrently: &doNotDropConcurrently},
+			expectedStatements: []Statement{
+				{
+					DDL:                   "CREATE INDEX CONCURRENTLY new_idx ON public.foobar USING btree (bar)",
+					Timeout:               statementTimeoutConcurrentIndexBuild,
+					LockTimeout:           lockTimeoutDefault,
+					Hazards:               []MigrationHazard{migrationHazardIndexBuildConcurrently},
+					RequiresNoTransaction: true,
+				},
+				{
+					DDL:         "DROP INDEX \"public\".\"old_idx\"",
+					Timeout:     statementTimeoutDefault,
+					LockTimeout: lockTimeoutDefault,
+					Hazards:     []MigrationHazard{migrationHazardIndexDroppedAcquiresLock, migrationHazardIndexDroppedQueryPerf},
+				},
+			},
+		},
+		{
+			name: "Concurrent index drops with non-concurrent index operations",
+			opts: planOptions{nonConcurrentIndexOps: true, dropIndexesConcurrently: &dropConcurrently},
+			expectedStatements: []Statement{
+				{
+					DDL:         "CREATE INDEX new_idx ON public.foobar USING btree (bar)",
+					Timeout:     statementTimeoutConcurrentIndexBuild,
+					LockTimeout: lockTimeoutDefault,
+					Hazards:     []MigrationHazard{migrationHazardIndexBuildNonConcurrently, migrationHazardIndexBuildAcquiresShareLock},
+				},
+				{
+					DDL:                   "DROP INDEX CONCURRENTLY \"public\".\"old_idx\"",
+					Timeout:               statementTimeoutConcurrentIndexDrop,
+					LockTimeout:           lockTimeoutDefault,
+					Hazards:               []MigrationHazard{migrationHazardIndexDroppedQueryPerf},
+					RequiresNoTransaction: true,
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(oldSchema, newSchema, &tc.opts)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedStatements, stmts)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_IndexMethodChange(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	table := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             []schema.Column{{Name: "foo", Type: "text"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	oldSchema := schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{{
+		OwningTable:     foobar,
+		Name:            "foo_idx",
+		Columns:         []string{"foo"},
+		Method:          "btree",
+		GetIndexDefStmt: "CREATE INDEX foo_idx ON public.foobar USING btree (foo)",
+	}}}
+	newSchema := schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{{
+		OwningTable:     foobar,
+		Name:            "foo_idx",
+		Columns:         []string{"foo"},
+		Method:          "hash",
+		GetIndexDefStmt: "CREATE INDEX foo_idx ON public.foobar USING hash (foo)",
+	}}}
+
+	stmts, err := generateMigrationStatements(oldSchema, newSchema, &planOptions{})
+	require.NoError(t, err)
+	// The index is re-created using the new method, i.e., the old index is renamed, the new index is built, and the
+	// old index is dropped
+	require.Len(t, stmts, 3)
+	assert.True(t, strings.HasPrefix(stmts[0].DDL, "ALTER INDEX \"public\".\"foo_idx\" RENAME TO"), stmts[0].DDL)
+	assert.Equal(t, "CREATE INDEX CONCURRENTLY foo_idx ON public.foobar USING hash (foo)", stmts[1].DDL)
+	assert.Equal(t, []MigrationHazard{migrationHazardIndexBuildConcurrently}, stmts[1].Hazards)
+	assert.True(t, strings.HasPrefix(stmts[2].DDL, "DROP INDEX CONCURRENTLY"), stmts[2].DDL)
+	assert.Equal(t, []MigrationHazard{migrationHazardIndexDroppedQueryPerf}, stmts[2].Hazards)
+}
+
+func TestGenerateMigrationStatements_DropIndexesConcurrentlyWithConstraints(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	table := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             []schema.Column{{Name: "foo", Type: "text"}, {Name: "bar", Type: "text"}},
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+	}
+	oldSchema := schema.Schema{Tables: []schema.Table{table}, Indexes: []schema.Index{
+		{
+			OwningTable:     foobar,
+			Name:            "foo_key",
+			Columns:         []string{"foo"},
+			IsUnique:        true,
+			GetIndexDefStmt: "CREATE UNIQUE INDEX foo_key ON public.foobar USING btree (foo)",
+			Constraint: &schema.IndexConstraint{
+				Type:                  schema.UniqueIndexConstraintType,
+				EscapedConstraintName: "\"foo_key\"",
+				ConstraintDef:         "UNIQUE (foo)",
+				IsLocal:               true,
+			},
+		},
+		{
+			OwningTable:     foobar,
+			Name:            "bar_idx",
+			Columns:         []string{"bar"},
+			IsUnique:        true,
+			GetIndexDefStmt: "CREATE UNIQUE INDEX bar_idx ON public.foobar USING btree (bar)",
+		},
+	}}
+	newSchema := schema.Schema{Tables: []schema.Table{table}}
+
+	for _, dropConcurrently := range []bool{true, false} {
+		t.Run(fmt.Sprintf("dropIndexesConcurrently=%t", dropConcurrently), func(t *testing.T) {
+			stmts, err := generateMigrationStatements(oldSchema, newSchema, &planOptions{dropIndexesConcurrently: &dropConcurrently})
+			require.NoError(t, err)
+
+			requiresNoTransactionByDDL := make(map[string]bool)
+			for _, stmt := range stmts {
+				requiresNoTransactionByDDL[stmt.DDL] = stmt.RequiresNoTransaction
+			}
+			// The index backing the unique constraint is always dropped via the constraint
+			expectedDropBarIdx := "DROP INDEX \"public\".\"bar_idx\""
+			if dropConcurrently {
+				expectedDropBarIdx = "DROP INDEX CONCURRENTLY \"public\".\"bar_idx\""
+			}
+			assert.Equal(t, map[string]bool{
+				"ALTER TABLE \"public\".\"foobar\" DROP CONSTRAINT \"foo_key\"": false,
+				expectedDropBarIdx: dropConcurrently,
+			}, requiresNoTransactionByDDL)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_ConstraintDeferrability(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	fizz := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"fizz\""}
+	tables := []schema.Table{
+		{
+			SchemaQualifiedName: foobar,
+			Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		},
+		{
+			SchemaQualifiedName: fizz,
+			Columns:             []schema.Column{{Name: "foobar_id", Type: "integer"}},
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		},
+	}
+	buildFkSchema := func(constraintDef string, deferrable, initiallyDeferred bool) schema.Schema {
+		return schema.Schema{
+			Tables: tables,
+			Indexes: []schema.Index{{
+				OwningTable:     foobar,
+				Name:            "foobar_pkey",
+				Columns:         []string{"id"},
+				IsUnique:        true,
+				GetIndexDefStmt: "CREATE UNIQUE INDEX foobar_pkey ON public.foobar USING btree (id)",
+				Constraint: &schema.IndexConstraint{
+					Type:                  schema.PkIndexConstraintType,
+					EscapedConstraintName: "\"foobar_pkey\"",
+					ConstraintDef:         "PRIMARY KEY (id)",
+					IsLocal:               true,
+				},
+			}},
+			ForeignKeyConstraints: []schema.ForeignKeyConstraint{{
+				EscapedName:       "\"fizz_foobar_id_fkey\"",
+				OwningTable:       fizz,
+				ForeignTable:      foobar,
+				ConstraintDef:     constraintDef,
+				IsValid:           true,
+				Deferrable:        deferrable,
+				InitiallyDeferred: initiallyDeferred,
+			}},
+		}
+	}
+	buildUniqueSchema := func(constraintDef string, deferrable, initiallyDeferred bool) schema.Schema {
+		return schema.Schema{
+			Tables: tables,
+			Indexes: []schema.Index{{
+				OwningTable:     foobar,
+				Name:            "foobar_id_key",
+				Columns:         []string{"id"},
+				IsUnique:        true,
+				GetIndexDefStmt: "CREATE UNIQUE INDEX foobar_id_key ON public.foobar USING btree (id)",
+				Constraint: &schema.IndexConstraint{
+					Type:                  schema.UniqueIndexConstraintType,
+					EscapedConstraintName: "\"foobar_id_key\"",
+					ConstraintDef:         constraintDef,
+					IsLocal:               true,
+					Deferrable:            deferrable,
+					InitiallyDeferred:     initiallyDeferred,
+				},
+			}},
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		oldSchema schema.Schema
+		newSchema schema.Schema
+
+		expectedDDL []string
+	}{
+		{
+			name:      "Foreign key made deferrable",
+			oldSchema: buildFkSchema("FOREIGN KEY (foobar_id) REFERENCES foobar(id)", false, false),
+			newSchema: buildFkSchema("FOREIGN KEY (foobar_id) REFERENCES foobar(id) DEFERRABLE", true, false),
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"fizz\" ALTER CONSTRAINT \"fizz_foobar_id_fkey\" DEFERRABLE INITIALLY IMMEDIATE",
+			},
+		},
+		{
+			name:      "Foreign key made initially deferred",
+			oldSchema: buildFkSchema("FOREIGN KEY (foobar_id) REFERENCES foobar(id) DEFERRABLE", true, false),
+			newSchema: buildFkSchema("FOREIGN KEY (foobar_id) REFERENCES foobar(id) DEFERRABLE INITIALLY DEFERRED", true, true),
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"fizz\" ALTER CONSTRAINT \"fizz_foobar_id_fkey\" DEFERRABLE INITIALLY DEFERRED",
+			},
+		},
+		{
+			name:      "Foreign key made not deferrable",
+			oldSchema: buildFkSchema("FOREIGN KEY (foobar_id) REFERENCES foobar(id) DEFERRABLE INITIALLY DEFERRED", true, true),
+			newSchema: buildFkSchema("FOREIGN KEY (foobar_id) REFERENCES foobar(id)", false, false),
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"fizz\" ALTER CONSTRAINT \"fizz_foobar_id_fkey\" NOT DEFERRABLE",
+			},
+		},
+		{
+			name:      "Foreign key with deferrability and other changes is re-created",
+			oldSchema: buildFkSchema("FOREIGN KEY (foobar_id) REFERENCES foobar(id)", false, false),
+			newSchema: buildFkSchema("FOREIGN KEY (foobar_id) REFERENCES foobar(id) ON DELETE CASCADE DEFERRABLE", true, false),
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"fizz\" DROP CONSTRAINT \"fizz_foobar_id_fkey\"",
+				"ALTER TABLE \"public\".\"fizz\" ADD CONSTRAINT \"fizz_foobar_id_fkey\" FOREIGN KEY (foobar_id) REFERENCES foobar(id) ON DELETE CASCADE DEFERRABLE NOT VALID",
+				"ALTER TABLE \"public\".\"fizz\" VALIDATE CONSTRAINT \"fizz_foobar_id_fkey\"",
+			},
+		},
+		{
+			name:      "Unique constraint made deferrable",
+			oldSchema: buildUniqueSchema("UNIQUE (id)", false, false),
+			newSchema: buildUniqueSchema("UNIQUE (id) DEFERRABLE INITIALLY DEFERRED", true, true),
+			expectedDDL: []string{
+				"ALTER INDEX \"public\".\"foobar_id_key\" RENAME TO \"pgschemadiff_tmpidx\"",
+				"CREATE UNIQUE INDEX CONCURRENTLY foobar_id_key ON public.foobar USING btree (id)",
+				"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"foobar_id_key\" UNIQUE USING INDEX \"foobar_id_key\" DEFERRABLE INITIALLY DEFERRED",
+				"ALTER TABLE \"public\".\"foobar\" DROP CONSTRAINT \"pgschemadiff_tmpidx\"",
+			},
+		},
+		{
+			// Only the deferrability of foreign keys can be altered, so unique constraints are always re-created
+			name:      "Unique constraint made not deferrable",
+			oldSchema: buildUniqueSchema("UNIQUE (id) DEFERRABLE", true, false),
+			newSchema: buildUniqueSchema("UNIQUE (id)", false, false),
+			expectedDDL: []string{
+				"ALTER INDEX \"public\".\"foobar_id_key\" RENAME TO \"pgschemadiff_tmpidx\"",
+				"CREATE UNIQUE INDEX CONCURRENTLY foobar_id_key ON public.foobar USING btree (id)",
+				"ALTER TABLE \"public\".\"foobar\" ADD CONSTRAINT \"foobar_id_key\" UNIQUE USING INDEX \"foobar_id_key\"",
+				"ALTER TABLE \"public\".\"foobar\" DROP CONSTRAINT \"pgschemadiff_tmpidx\"",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(tc.oldSchema, tc.newSchema, &planOptions{})
+			require.NoError(t, err)
+
+			var ddl []string
+			for _, stmt := range stmts {
+				ddl = append(ddl, tmpNameRegex.ReplaceAllString(stmt.DDL, "$1"))
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+		})
+	}
+}
+
+func TestAlterStorageParametersStatements(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		oldParams   map[string]string
+		newParams   map[string]string
+		expectedDDL []string
+	}{
+		{
+			name:      "No changes",
+			oldParams: map[string]string{"fillfactor": "70"},
+			newParams: map[string]string{"fillfactor": "70"},
+		},
+		{
+			name:        "Set new and changed parameters",
+			oldParams:   map[string]string{"fillfactor": "70", "autovacuum_enabled": "false"},
+			newParams:   map[string]string{"fillfactor": "80", "autovacuum_enabled": "false", "toast.autovacuum_enabled": "false"},
+			expectedDDL: []string{"ALTER TABLE \"public\".\"foobar\" SET (fillfactor=80, toast.autovacuum_enabled=false)"},
+		},
+		{
+			name:        "Reset removed parameters",
+			oldParams:   map[string]string{"fillfactor": "70", "toast.autovacuum_enabled": "false"},
+			newParams:   nil,
+			expectedDDL: []string{"ALTER TABLE \"public\".\"foobar\" RESET (fillfactor, toast.autovacuum_enabled)"},
+		},
+		{
+			name:      "Set and reset parameters",
+			oldParams: map[string]string{"autovacuum_vacuum_scale_factor": "0.2"},
+			newParams: map[string]string{"fillfactor": "90"},
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"foobar\" SET (fillfactor=90)",
+				"ALTER TABLE \"public\".\"foobar\" RESET (autovacuum_vacuum_scale_factor)",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts := alterStorageParametersStatements(alterTablePrefix(schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}), tc.oldParams, tc.newParams)
+			var ddl []string
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				// Storage parameter changes are low-impact, so they don't have any hazards
+				assert.Empty(t, stmt.Hazards)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_GeneratedColumns(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	price := schema.Column{Name: "price", Type: "numeric"}
+	buildSchema := func(columns ...schema.Column) schema.Schema {
+		return schema.Schema{Tables: []schema.Table{{
+			SchemaQualifiedName: foobar,
+			Columns:             append([]schema.Column{price}, columns...),
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		}}}
+	}
+	regularColumn := schema.Column{Name: "total", Type: "numeric", IsNullable: true}
+	generatedColumn := schema.Column{Name: "total", Type: "numeric", IsNullable: true, IsGenerated: true, GenerationExpression: "(price * 1.1)"}
+	changedGeneratedColumn := generatedColumn
+	changedGeneratedColumn.GenerationExpression = "(price * 1.2)"
+
+	for _, tc := range []struct {
+		name        string
+		oldSchema   schema.Schema
+		newSchema   schema.Schema
+		expectedDDL []string
+	}{
+		{
+			name:      "Add generated column",
+			oldSchema: buildSchema(),
+			newSchema: buildSchema(generatedColumn),
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"foobar\" ADD COLUMN \"total\" numeric GENERATED ALWAYS AS ((price * 1.1)) STORED",
+				"VACUUM FREEZE \"public\".\"foobar\"",
+				"ANALYZE \"public\".\"foobar\"",
+			},
+		},
+		{
+			name:      "Change generation expression",
+			oldSchema: buildSchema(generatedColumn),
+			newSchema: buildSchema(changedGeneratedColumn),
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"foobar\" DROP COLUMN \"total\"",
+				"ALTER TABLE \"public\".\"foobar\" ADD COLUMN \"total\" numeric GENERATED ALWAYS AS ((price * 1.2)) STORED",
+				"VACUUM FREEZE \"public\".\"foobar\"",
+				"ANALYZE \"public\".\"foobar\"",
+			},
+		},
+		{
+			name:      "Regular column to generated column",
+			oldSchema: buildSchema(regularColumn),
+			newSchema: buildSchema(generatedColumn),
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"foobar\" DROP COLUMN \"total\"",
+				"ALTER TABLE \"public\".\"foobar\" ADD COLUMN \"total\" numeric GENERATED ALWAYS AS ((price * 1.1)) STORED",
+				"VACUUM FREEZE \"public\".\"foobar\"",
+				"ANALYZE \"public\".\"foobar\"",
+			},
+		},
+		{
+			name:      "Generated column to regular column",
+			oldSchema: buildSchema(generatedColumn),
+			newSchema: buildSchema(regularColumn),
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"foobar\" DROP COLUMN \"total\"",
+				"ALTER TABLE \"public\".\"foobar\" ADD COLUMN \"total\" numeric",
+				"ANALYZE \"public\".\"foobar\"",
+			},
+		},
+		{
+			name:      "Unchanged generated column",
+			oldSchema: buildSchema(generatedColumn),
+			newSchema: buildSchema(generatedColumn),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(tc.oldSchema, tc.newSchema, &planOptions{})
+			require.NoError(t, err)
+			var ddl []string
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+		})
+	}
+}
+
+func TestColumnSQLVertexGenerator_IdentityStatements(t *testing.T) {
+	csg := &columnSQLVertexGenerator{tableName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}}
+	column := schema.Column{Name: "id", Type: "bigint"}
+	identity := &schema.ColumnIdentity{
+		Type:       schema.ColumnIdentityTypeAlways,
+		MinValue:   1,
+		MaxValue:   100,
+		StartValue: 1,
+		Increment:  1,
+		CacheSize:  1,
+	}
+	identityColumn := column
+	identityColumn.Identity = identity
+	byDefaultIdentity := *identity
+	byDefaultIdentity.Type = schema.ColumnIdentityTypeByDefault
+	byDefaultIdentityColumn := column
+	byDefaultIdentityColumn.Identity = &byDefaultIdentity
+
+	for _, tc := range []struct {
+		name               string
+		old                schema.Column
+		new                schema.Column
+		expectedStatements []Statement
+	}{
+		{
+			name: "Add identity",
+			old:  column,
+			new:  identityColumn,
+			expectedStatements: []Statement{{
+				DDL:         "ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"id\" ADD GENERATED ALWAYS AS IDENTITY (INCREMENT BY 1 MINVALUE 1 MAXVALUE 100 START WITH 1 CACHE 1 NO CYCLE)",
+				Timeout:     statementTimeoutDefault,
+				LockTimeout: lockTimeoutDefault,
+				Hazards:     []MigrationHazard{migrationHazardColumnIdentityAltered, migrationHazardColumnIdentityAdded},
+			}},
+		},
+		{
+			name: "Drop identity",
+			old:  identityColumn,
+			new:  column,
+			expectedStatements: []Statement{{
+				DDL:         "ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"id\" DROP IDENTITY",
+				Timeout:     statementTimeoutDefault,
+				LockTimeout: lockTimeoutDefault,
+				Hazards:     []MigrationHazard{migrationHazardColumnIdentityAltered},
+			}},
+		},
+		{
+			name: "Switch identity to by default",
+			old:  identityColumn,
+			new:  byDefaultIdentityColumn,
+			expectedStatements: []Statement{{
+				DDL:         "ALTER TABLE \"public\".\"foobar\" ALTER COLUMN \"id\"\n\tSET GENERATED BY DEFAULT",
+				Timeout:     statementTimeoutDefault,
+				LockTimeout: lockTimeoutDefault,
+				Hazards:     []MigrationHazard{migrationHazardColumnIdentityAltered},
+			}},
+		},
+		{
+			name: "Unchanged identity",
+			old:  identityColumn,
+			new:  identityColumn,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := csg.buildUpdateIdentityStatements(tc.old, tc.new)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatements, stmts)
+		})
+	}
+}
+
+func TestColumnSQLVertexGenerator_AddHazards(t *testing.T) {
+	csg := &columnSQLVertexGenerator{tableName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}}
+	for _, tc := range []struct {
+		name            string
+		column          schema.Column
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name:   "Constant default",
+			column: schema.Column{Name: "version", Type: "integer", Default: "1"},
+		},
+		{
+			name:   "Stable default",
+			column: schema.Column{Name: "created_at", Type: "timestamp with time zone", Default: "now()"},
+		},
+		{
+			name:            "Volatile default",
+			column:          schema.Column{Name: "id", Type: "integer", Default: "nextval('foobar_id_seq'::regclass)", IsDefaultVolatile: true},
+			expectedHazards: []MigrationHazard{migrationHazardColumnAddedWithVolatileDefault},
+		},
+		{
+			name:            "Generated column",
+			column:          schema.Column{Name: "total", Type: "numeric", IsGenerated: true, GenerationExpression: "(price * 1.1)"},
+			expectedHazards: []MigrationHazard{migrationHazardGeneratedColumnAdded},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := csg.Add(tc.column)
+			require.NoError(t, err)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tc.expectedHazards, stmts[0].Hazards)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_RangePartitionBounds(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	columns := []schema.Column{{Name: "id", Type: "integer"}}
+	parent := schema.Table{
+		SchemaQualifiedName: foobar,
+		Columns:             columns,
+		ReplicaIdentity:     schema.ReplicaIdentityDefault,
+		PartitionKeyDef:     "RANGE (id)",
+	}
+	buildPartition := func(name, forValues string) schema.Table {
+		return schema.Table{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: schema.EscapeIdentifier(name)},
+			Columns:             columns,
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+			ParentTable:         &foobar,
+			ForValues:           forValues,
+		}
+	}
+	buildSchema := func(partitions ...schema.Table) schema.Schema {
+		return schema.Schema{Tables: append([]schema.Table{parent}, partitions...)}
+	}
+	indexOfDDL := func(t *testing.T, stmts []Statement, ddl string) int {
+		for i, stmt := range stmts {
+			if stmt.DDL == ddl {
+				return i
+			}
+		}
+		require.Failf(t, "statement not found", "%q not found in %v", ddl, stmts)
+		return -1
+	}
+
+	t.Run("Alter partition bounds", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(
+			buildSchema(buildPartition("foobar_1", "FOR VALUES FROM (0) TO (100)")),
+			buildSchema(buildPartition("foobar_1", "FOR VALUES FROM (0) TO (200)")),
+			&planOptions{},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []Statement{
+			{
+				DDL:         "ALTER TABLE \"public\".\"foobar\" DETACH PARTITION \"public\".\"foobar_1\"",
+				Timeout:     statementTimeoutDefault,
+				LockTimeout: lockTimeoutDefault,
+				Hazards:     []MigrationHazard{migrationHazardPartitionDetached},
+			},
+			{
+				DDL:         "ALTER TABLE \"public\".\"foobar\" ATTACH PARTITION \"public\".\"foobar_1\" FOR VALUES FROM (0) TO (200)",
+				Timeout:     statementTimeoutAttachPartition,
+				LockTimeout: lockTimeoutDefault,
+				Hazards:     []MigrationHazard{migrationHazardPartitionAttachValidatesBounds},
+			},
+		}, stmts)
+	})
+
+	t.Run("Split partition", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(
+			buildSchema(buildPartition("foobar_1", "FOR VALUES FROM (0) TO (100)")),
+			buildSchema(buildPartition("foobar_1", "FOR VALUES FROM (0) TO (50)"), buildPartition("foobar_2", "FOR VALUES FROM (50) TO (100)")),
+			&planOptions{},
+		)
+		require.NoError(t, err)
+		assert.Less(t,
+			indexOfDDL(t, stmts, "ALTER TABLE \"public\".\"foobar\" ATTACH PARTITION \"public\".\"foobar_1\" FOR VALUES FROM (0) TO (50)"),
+			indexOfDDL(t, stmts, "ALTER TABLE \"public\".\"foobar\" ATTACH PARTITION \"public\".\"foobar_2\" FOR VALUES FROM (50) TO (100)"),
+		)
+	})
+
+	t.Run("Merge partitions", func(t *testing.T) {
+		stmts, err := generateMigrationStatements(
+			buildSchema(buildPartition("foobar_1", "FOR VALUES FROM (0) TO (50)"), buildPartition("foobar_2", "FOR VALUES FROM (50) TO (100)")),
+			buildSchema(buildPartition("foobar_1", "FOR VALUES FROM (0) TO (100)")),
+			&planOptions{},
+		)
+		require.NoError(t, err)
+		assert.Less(t,
+			indexOfDDL(t, stmts, "DROP TABLE \"public\".\"foobar_2\""),
+			indexOfDDL(t, stmts, "ALTER TABLE \"public\".\"foobar\" ATTACH PARTITION \"public\".\"foobar_1\" FOR VALUES FROM (0) TO (100)"),
+		)
+	})
+}
+
+func TestGenerateMigrationStatements_PartitionAttachHazards(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foobar\""}
+	columns := []schema.Column{{Name: "id", Type: "integer"}}
+	buildSchema := func(partitionKeyDef string, partitions map[string]string) schema.Schema {
+		tables := []schema.Table{{
+			SchemaQualifiedName: foobar,
+			Columns:             columns,
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+			PartitionKeyDef:     partitionKeyDef,
+		}}
+		for name, forValues := range partitions {
+			tables = append(tables, schema.Table{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: schema.EscapeIdentifier(name)},
+				Columns:             columns,
+				ReplicaIdentity:     schema.ReplicaIdentityDefault,
+				ParentTable:         &foobar,
+				ForValues:           forValues,
+			})
+		}
+		return schema.Schema{Tables: tables}
+	}
+
+	for _, tc := range []struct {
+		name            string
+		oldSchema       schema.Schema
+		newSchema       schema.Schema
+		expectedHazards map[string][]MigrationHazard
+	}{
+		{
+			name:      "Attach partition without a default partition",
+			oldSchema: buildSchema("LIST (id)", map[string]string{"foobar_1": "FOR VALUES IN (1)"}),
+			newSchema: buildSchema("LIST (id)", map[string]string{"foobar_1": "FOR VALUES IN (1)", "foobar_2": "FOR VALUES IN (2)"}),
+			expectedHazards: map[string][]MigrationHazard{
+				"ALTER TABLE \"public\".\"foobar\" ATTACH PARTITION \"public\".\"foobar_2\" FOR VALUES IN (2)": nil,
+			},
+		},
+		{
+			name:      "Attach partition with a default partition",
+			oldSchema: buildSchema("LIST (id)", map[string]string{"foobar_default": "DEFAULT"}),
+			newSchema: buildSchema("LIST (id)", map[string]string{"foobar_default": "DEFAULT", "foobar_null": "FOR VALUES IN (NULL)"}),
+			expectedHazards: map[string][]MigrationHazard{
+				"ALTER TABLE \"public\".\"foobar\" ATTACH PARTITION \"public\".\"foobar_null\" FOR VALUES IN (NULL)": {migrationHazardPartitionAttachScansDefaultPartition},
+			},
+		},
+		{
+			name:      "Alter list partition bounds with a default partition",
+			oldSchema: buildSchema("LIST (id)", map[string]string{"foobar_1": "FOR VALUES IN (1)", "foobar_default": "DEFAULT"}),
+			newSchema: buildSchema("LIST (id)", map[string]string{"foobar_1": "FOR VALUES IN (1, 2)", "foobar_default": "DEFAULT"}),
+			expectedHazards: map[string][]MigrationHazard{
+				"ALTER TABLE \"public\".\"foobar\" DETACH PARTITION \"public\".\"foobar_1\"":                      {migrationHazardPartitionDetached},
+				"ALTER TABLE \"public\".\"foobar\" ATTACH PARTITION \"public\".\"foobar_1\" FOR VALUES IN (1, 2)": {migrationHazardPartitionAttachValidatesBounds, migrationHazardPartitionAttachScansDefaultPartition},
+			},
+		},
+		{
+			name:      "Alter hash partition bounds",
+			oldSchema: buildSchema("HASH (id)", map[string]string{"foobar_0": "FOR VALUES WITH (modulus 2, remainder 0)"}),
+			newSchema: buildSchema("HASH (id)", map[string]string{"foobar_0": "FOR VALUES WITH (modulus 4, remainder 0)"}),
+			expectedHazards: map[string][]MigrationHazard{
+				"ALTER TABLE \"public\".\"foobar\" DETACH PARTITION \"public\".\"foobar_0\"":                                          {migrationHazardPartitionDetached},
+				"ALTER TABLE \"public\".\"foobar\" ATTACH PARTITION \"public\".\"foobar_0\" FOR VALUES WITH (modulus 4, remainder 0)": {migrationHazardPartitionAttachValidatesBounds, migrationHazardHashPartitionBoundsChanged},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(tc.oldSchema, tc.newSchema, &planOptions{})
+			require.NoError(t, err)
+			hazardsByDDL := make(map[string][]MigrationHazard)
+			for _, stmt := range stmts {
+				if strings.Contains(stmt.DDL, "PARTITION") {
+					hazardsByDDL[stmt.DDL] = stmt.Hazards
+				}
+			}
+			assert.Equal(t, tc.expectedHazards, hazardsByDDL)
+		})
+	}
+}
+
+func TestExtensionSQLGenerator(t *testing.T) {
+	buildExtension := func(name, schemaName, version string) schema.Extension {
+		return schema.Extension{
+			SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: schemaName, EscapedName: schema.EscapeIdentifier(name)},
+			Version:             version,
+		}
+	}
+
+	stmts, err := (&extensionSQLGenerator{}).Add(buildExtension("uuid-ossp", "public", "1.1"))
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "CREATE EXTENSION \"uuid-ossp\" WITH SCHEMA \"public\" VERSION \"1.1\"", stmts[0].DDL)
+
+	for _, tc := range []struct {
+		name           string
+		old            schema.Extension
+		new            schema.Extension
+		expectedDDL    string
+		expectedHazard MigrationHazard
+	}{
+		{
+			name:           "Upgrade to latest version",
+			old:            buildExtension("pg_trgm", "public", "1.5"),
+			new:            buildExtension("pg_trgm", "public", ""),
+			expectedDDL:    "ALTER EXTENSION \"pg_trgm\" UPDATE",
+			expectedHazard: migrationHazardExtensionAlteredVersionUpgraded,
+		},
+		{
+			name:           "Upgrade to explicit version",
+			old:            buildExtension("pg_trgm", "public", "1.5"),
+			new:            buildExtension("pg_trgm", "public", "1.6"),
+			expectedDDL:    "ALTER EXTENSION \"pg_trgm\" UPDATE TO \"1.6\"",
+			expectedHazard: migrationHazardExtensionAlteredVersionUpgraded,
+		},
+		{
+			name:           "Downgrade",
+			old:            buildExtension("uuid-ossp", "public", "1.1"),
+			new:            buildExtension("uuid-ossp", "public", "1.0"),
+			expectedDDL:    "ALTER EXTENSION \"uuid-ossp\" UPDATE TO \"1.0\"",
+			expectedHazard: migrationHazardExtensionAlteredVersionDowngraded,
+		},
+		{
+			name:           "Downgrade with differing number of version parts",
+			old:            buildExtension("postgis", "public", "3.4"),
+			new:            buildExtension("postgis", "public", "3.3.2"),
+			expectedDDL:    "ALTER EXTENSION \"postgis\" UPDATE TO \"3.3.2\"",
+			expectedHazard: migrationHazardExtensionAlteredVersionDowngraded,
+		},
+		{
+			name:           "Non-numeric versions are assumed to be upgrades",
+			old:            buildExtension("postgis", "public", "3.4.0"),
+			new:            buildExtension("postgis", "public", "3.3.0dev"),
+			expectedDDL:    "ALTER EXTENSION \"postgis\" UPDATE TO \"3.3.0dev\"",
+			expectedHazard: migrationHazardExtensionAlteredVersionUpgraded,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := (&extensionSQLGenerator{}).Alter(extensionDiff{oldAndNew[schema.Extension]{old: tc.old, new: tc.new}})
+			require.NoError(t, err)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tc.expectedDDL, stmts[0].DDL)
+			assert.Equal(t, []MigrationHazard{tc.expectedHazard}, stmts[0].Hazards)
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package diff
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/stripe/pg-schema-diff/internal/graph"
 )
@@ -102,6 +105,23 @@ func (d dependencyBuilder) after(id sqlVertexId) dependency {
 	}
 }
 
+// CyclicDependencyError is returned when the dependencies between the SQL vertices form a cycle, i.e., there is no order
+// in which the statements can be run. This indicates the dependencies between the schema objects are incorrectly
+// modeled.
+type CyclicDependencyError struct {
+	// Cycle contains the ids of the vertices in the cycle. Each vertex must run before the next vertex, and the last
+	// vertex must run before the first vertex
+	Cycle []sqlVertexId
+}
+
+func (e *CyclicDependencyError) Error() string {
+	var edges []string
+	for i, id := range e.Cycle {
+		edges = append(edges, fmt.Sprintf("%s must run before %s", id, e.Cycle[(i+1)%len(e.Cycle)]))
+	}
+	return fmt.Sprintf("cyclic dependency detected between %d vertices: %s", len(e.Cycle), strings.Join(edges, "; "))
+}
+
 // sqlGraph represents a dependency web of SQL statements
 type sqlGraph struct {
 	*graph.Graph[sqlVertex]
@@ -114,15 +134,114 @@ func newSqlGraph() *sqlGraph {
 }
 
 func (s *sqlGraph) toOrderedStatements() ([]Statement, error) {
+	stmts, _, err := s.toOrderedStatementsWithDependencies()
+	return stmts, err
+}
+
+// toOrderedStatementsWithDependencies returns the ordered statements and the dependencies between them. The statements
+// of a vertex each depend on the statement before them, and the first statement of a vertex depends on the last statement
+// of each vertex it must run after. Vertices without statements are collapsed, i.e., the vertices that must run after them
+// depend on the statements they themselves depend on.
+func (s *sqlGraph) toOrderedStatementsWithDependencies() ([]Statement, []StatementDependency, error) {
 	vertices, err := s.TopologicallySortWithPriority(graph.IsLowerPriorityFromGetPriority(func(v sqlVertex) int {
 		return v.GetPriority()
 	}))
 	if err != nil {
-		return nil, fmt.Errorf("topologically sorting graph: %w", err)
+		var cycleErr *graph.CycleError
+		if errors.As(err, &cycleErr) {
+			return nil, nil, s.buildCyclicDependencyError(cycleErr)
+		}
+		return nil, nil, fmt.Errorf("topologically sorting graph: %w", err)
 	}
+
+	// The vertices are topologically sorted, so a vertex's predecessors are always visited before the vertex
+	predecessorIdsById := make(map[string][]string)
+	// lastStmtIdxsById is the set of statements that must complete for a vertex to be complete
+	lastStmtIdxsById := make(map[string][]int)
 	var stmts []Statement
+	var deps []StatementDependency
 	for _, v := range vertices {
-		stmts = append(stmts, v.statements...)
+		var predecessorLastStmtIdxs []int
+		for _, predecessorId := range predecessorIdsById[v.GetId()] {
+			predecessorLastStmtIdxs = append(predecessorLastStmtIdxs, lastStmtIdxsById[predecessorId]...)
+		}
+		predecessorLastStmtIdxs = uniqueSortedInts(predecessorLastStmtIdxs)
+
+		if len(v.statements) == 0 {
+			lastStmtIdxsById[v.GetId()] = predecessorLastStmtIdxs
+		} else {
+			firstStmtIdx := len(stmts)
+			for _, idx := range predecessorLastStmtIdxs {
+				deps = append(deps, StatementDependency{Statement: firstStmtIdx, DependsOn: idx})
+			}
+			for i := 1; i < len(v.statements); i++ {
+				deps = append(deps, StatementDependency{Statement: firstStmtIdx + i, DependsOn: firstStmtIdx + i - 1})
+			}
+			stmts = append(stmts, v.statements...)
+			lastStmtIdxsById[v.GetId()] = []int{len(stmts) - 1}
+		}
+
+		for _, targetId := range s.GetAdjacentVertexIds(v.GetId()) {
+			predecessorIdsById[targetId] = append(predecessorIdsById[targetId], v.GetId())
+		}
+	}
+	return stmts, deps, nil
+}
+
+func (s *sqlGraph) buildCyclicDependencyError(cycleErr *graph.CycleError) *CyclicDependencyError {
+	var cycle []sqlVertexId
+	for _, id := range cycleErr.Cycle {
+		cycle = append(cycle, s.GetVertex(id).id)
+	}
+	return &CyclicDependencyError{Cycle: cycle}
+}
+
+func uniqueSortedInts(vals []int) []int {
+	sort.Ints(vals)
+	var unique []int
+	for i, val := range vals {
+		if i == 0 || val != vals[i-1] {
+			unique = append(unique, val)
+		}
+	}
+	return unique
+}
+
+// buildSequentialDependencies builds the dependencies for statements that must run one after another
+func buildSequentialDependencies(stmtCount int) []StatementDependency {
+	var deps []StatementDependency
+	for i := 1; i < stmtCount; i++ {
+		deps = append(deps, StatementDependency{Statement: i, DependsOn: i - 1})
+	}
+	return deps
+}
+
+// appendStatementsWithDependencies appends newStmts, which must run after all the statements in stmts. newDeps are the
+// dependencies between the new statements, indexed from the first new statement. The new statements without any
+// dependencies depend on the statements in stmts that no other statement depends on.
+func appendStatementsWithDependencies(stmts []Statement, deps []StatementDependency, newStmts []Statement, newDeps []StatementDependency) ([]Statement, []StatementDependency) {
+	hasDependents := make(map[int]bool)
+	for _, dep := range deps {
+		hasDependents[dep.DependsOn] = true
+	}
+	hasDependencies := make(map[int]bool)
+	for _, dep := range newDeps {
+		hasDependencies[dep.Statement] = true
+	}
+
+	offset := len(stmts)
+	for i := range newStmts {
+		if hasDependencies[i] {
+			continue
+		}
+		for j := range stmts {
+			if !hasDependents[j] {
+				deps = append(deps, StatementDependency{Statement: offset + i, DependsOn: j})
+			}
+		}
+	}
+	for _, dep := range newDeps {
+		deps = append(deps, StatementDependency{Statement: offset + dep.Statement, DependsOn: offset + dep.DependsOn})
 	}
-	return stmts, nil
+	return append(stmts, newStmts...), deps
 }
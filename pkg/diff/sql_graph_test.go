@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+// cyclicSQLVertexGenerator generates a vertex for each added table that depends on the vertex of the next added
+// table, such that the dependencies of the added tables form a cycle
+type cyclicSQLVertexGenerator struct {
+	tableNames []string
+}
+
+func (c cyclicSQLVertexGenerator) Add(t schema.Table) (partialSQLGraph, error) {
+	id := buildTableVertexId(t.SchemaQualifiedName, diffTypeAddAlter)
+	var nextTableName string
+	for i, name := range c.tableNames {
+		if name == t.GetName() {
+			nextTableName = c.tableNames[(i+1)%len(c.tableNames)]
+		}
+	}
+	return partialSQLGraph{
+		vertices: []sqlVertex{{
+			id:         id,
+			priority:   sqlPrioritySooner,
+			statements: []Statement{{DDL: "CREATE TABLE " + t.GetFQEscapedName() + "()"}},
+		}},
+		dependencies: []dependency{
+			mustRun(id).after(buildSchemaObjVertexId("table", nextTableName, diffTypeAddAlter)),
+		},
+	}, nil
+}
+
+func (c cyclicSQLVertexGenerator) Delete(schema.Table) (partialSQLGraph, error) {
+	return partialSQLGraph{}, nil
+}
+
+func (c cyclicSQLVertexGenerator) Alter(tableDiff) (partialSQLGraph, error) {
+	return partialSQLGraph{}, nil
+}
+
+func TestSQLGraph_ToOrderedStatements_CyclicDependency(t *testing.T) {
+	var tables []schema.Table
+	var tableNames []string
+	for _, name := range []string{"\"foo\"", "\"bar\"", "\"baz\""} {
+		table := schema.Table{SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: name}}
+		tables = append(tables, table)
+		tableNames = append(tableNames, table.GetName())
+	}
+
+	partialGraph, err := generatePartialGraph[schema.Table, tableDiff](cyclicSQLVertexGenerator{tableNames: tableNames}, listDiff[schema.Table, tableDiff]{
+		adds: tables,
+	})
+	require.NoError(t, err)
+	graph, err := graphFromPartials(partialGraph)
+	require.NoError(t, err)
+
+	var stmts []Statement
+	require.NotPanics(t, func() {
+		stmts, err = graph.toOrderedStatements()
+	})
+	assert.Nil(t, stmts)
+	var cyclicDependencyErr *CyclicDependencyError
+	require.ErrorAs(t, err, &cyclicDependencyErr)
+	assert.Equal(t, []sqlVertexId{
+		buildTableVertexId(tables[1].SchemaQualifiedName, diffTypeAddAlter),
+		buildTableVertexId(tables[0].SchemaQualifiedName, diffTypeAddAlter),
+		buildTableVertexId(tables[2].SchemaQualifiedName, diffTypeAddAlter),
+	}, cyclicDependencyErr.Cycle)
+	assert.EqualError(t, err, `cyclic dependency detected between 3 vertices: `+
+		`table:"public"."bar":ADDALTER must run before table:"public"."foo":ADDALTER; `+
+		`table:"public"."foo":ADDALTER must run before table:"public"."baz":ADDALTER; `+
+		`table:"public"."baz":ADDALTER must run before table:"public"."bar":ADDALTER`)
+}
@@ -0,0 +1,93 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+type statisticsObjectSQLVertexGenerator struct {
+	// omitAnalyze is true if the advisory `ANALYZE` statement is not generated for new statistics objects
+	omitAnalyze bool
+}
+
+func newStatisticsObjectSqlVertexGenerator(omitAnalyze bool) sqlVertexGenerator[schema.StatisticsObject, statisticsObjectDiff] {
+	return legacyToNewSqlVertexGenerator[schema.StatisticsObject, statisticsObjectDiff](&statisticsObjectSQLVertexGenerator{
+		omitAnalyze: omitAnalyze,
+	})
+}
+
+func (s *statisticsObjectSQLVertexGenerator) Add(statisticsObject schema.StatisticsObject) ([]Statement, error) {
+	stmts := []Statement{{
+		DDL:         statisticsObject.Def,
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}}
+	if statisticsObject.StatisticsTarget != -1 {
+		stmts = append(stmts, buildSetStatisticsTargetStatement(statisticsObject))
+	}
+	if s.omitAnalyze {
+		return stmts, nil
+	}
+	// The statistics are only populated once the table is analyzed. Analyzing a large table can take a while, so it is
+	// left to the user to run it after the migration
+	stmts = append(stmts, Statement{
+		DDL:         fmt.Sprintf("ANALYZE %s", statisticsObject.Table.GetFQEscapedName()),
+		Timeout:     statementTimeoutAnalyzeTable,
+		LockTimeout: lockTimeoutDefault,
+		IsAdvisory:  true,
+	})
+	return stmts, nil
+}
+
+func (s *statisticsObjectSQLVertexGenerator) Delete(statisticsObject schema.StatisticsObject) ([]Statement, error) {
+	return []Statement{{
+		DDL:         fmt.Sprintf("DROP STATISTICS %s", statisticsObject.GetFQEscapedName()),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}}, nil
+}
+
+func (s *statisticsObjectSQLVertexGenerator) Alter(diff statisticsObjectDiff) ([]Statement, error) {
+	// The kinds and columns of a statistics object cannot be altered, so statistics objects are re-created if anything
+	// other than their statistics target has changed
+	oldCopy := diff.old
+	oldCopy.StatisticsTarget = diff.new.StatisticsTarget
+	if !cmp.Equal(oldCopy, diff.new) {
+		return nil, fmt.Errorf("altering statistics object to resolve the following diff %s: %w", cmp.Diff(oldCopy, diff.new), ErrNotImplemented)
+	}
+
+	if diff.old.StatisticsTarget == diff.new.StatisticsTarget {
+		return nil, nil
+	}
+	return []Statement{buildSetStatisticsTargetStatement(diff.new)}, nil
+}
+
+func buildSetStatisticsTargetStatement(statisticsObject schema.StatisticsObject) Statement {
+	return Statement{
+		DDL:         fmt.Sprintf("ALTER STATISTICS %s SET STATISTICS %d", statisticsObject.GetFQEscapedName(), statisticsObject.StatisticsTarget),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+	}
+}
+
+func (s *statisticsObjectSQLVertexGenerator) GetSQLVertexId(statisticsObject schema.StatisticsObject, diffType diffType) sqlVertexId {
+	return buildSchemaObjVertexId("statistics_object", statisticsObject.GetName(), diffType)
+}
+
+func (s *statisticsObjectSQLVertexGenerator) GetAddAlterDependencies(newStatisticsObject, _ schema.StatisticsObject) ([]dependency, error) {
+	return []dependency{
+		mustRun(s.GetSQLVertexId(newStatisticsObject, diffTypeAddAlter)).after(s.GetSQLVertexId(newStatisticsObject, diffTypeDelete)),
+		mustRun(s.GetSQLVertexId(newStatisticsObject, diffTypeAddAlter)).after(buildTableVertexId(newStatisticsObject.Table, diffTypeAddAlter)),
+	}, nil
+}
+
+func (s *statisticsObjectSQLVertexGenerator) GetDeleteDependencies(statisticsObject schema.StatisticsObject) ([]dependency, error) {
+	// Dropping a column used by a statistics object implicitly drops the statistics object, so it must be dropped
+	// before the table is altered
+	return []dependency{
+		mustRun(s.GetSQLVertexId(statisticsObject, diffTypeDelete)).before(buildTableVertexId(statisticsObject.Table, diffTypeAddAlter)),
+		mustRun(s.GetSQLVertexId(statisticsObject, diffTypeDelete)).before(buildTableVertexId(statisticsObject.Table, diffTypeDelete)),
+	}, nil
+}
@@ -0,0 +1,138 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestStatisticsObjectSQLVertexGenerator(t *testing.T) {
+	statisticsObject := schema.StatisticsObject{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_city_zip\""},
+		Table:               schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""},
+		Kinds:               []string{"ndistinct"},
+		Columns:             []string{"city", "zip"},
+		StatisticsTarget:    -1,
+		Def:                 "CREATE STATISTICS public.foo_city_zip (ndistinct) ON city, zip FROM public.foo",
+	}
+	withStatisticsTarget := func(target int) schema.StatisticsObject {
+		s := statisticsObject
+		s.StatisticsTarget = target
+		return s
+	}
+
+	for _, tc := range []struct {
+		name        string
+		generate    func(g *statisticsObjectSQLVertexGenerator) ([]Statement, error)
+		expectedDDL []string
+		// expectedAdvisory is whether each statement is advisory
+		expectedAdvisory []bool
+	}{
+		{
+			name: "create",
+			generate: func(g *statisticsObjectSQLVertexGenerator) ([]Statement, error) {
+				return g.Add(statisticsObject)
+			},
+			expectedDDL: []string{
+				"CREATE STATISTICS public.foo_city_zip (ndistinct) ON city, zip FROM public.foo",
+				"ANALYZE \"public\".\"foo\"",
+			},
+			expectedAdvisory: []bool{false, true},
+		},
+		{
+			name: "create with statistics target",
+			generate: func(g *statisticsObjectSQLVertexGenerator) ([]Statement, error) {
+				return g.Add(withStatisticsTarget(500))
+			},
+			expectedDDL: []string{
+				"CREATE STATISTICS public.foo_city_zip (ndistinct) ON city, zip FROM public.foo",
+				"ALTER STATISTICS \"public\".\"foo_city_zip\" SET STATISTICS 500",
+				"ANALYZE \"public\".\"foo\"",
+			},
+			expectedAdvisory: []bool{false, false, true},
+		},
+		{
+			name: "drop",
+			generate: func(g *statisticsObjectSQLVertexGenerator) ([]Statement, error) {
+				return g.Delete(statisticsObject)
+			},
+			expectedDDL:      []string{"DROP STATISTICS \"public\".\"foo_city_zip\""},
+			expectedAdvisory: []bool{false},
+		},
+		{
+			name: "reset statistics target",
+			generate: func(g *statisticsObjectSQLVertexGenerator) ([]Statement, error) {
+				return g.Alter(statisticsObjectDiff{oldAndNew: oldAndNew[schema.StatisticsObject]{
+					old: withStatisticsTarget(500),
+					new: statisticsObject,
+				}})
+			},
+			expectedDDL:      []string{"ALTER STATISTICS \"public\".\"foo_city_zip\" SET STATISTICS -1"},
+			expectedAdvisory: []bool{false},
+		},
+		{
+			name: "no change",
+			generate: func(g *statisticsObjectSQLVertexGenerator) ([]Statement, error) {
+				return g.Alter(statisticsObjectDiff{oldAndNew: oldAndNew[schema.StatisticsObject]{
+					old: statisticsObject,
+					new: statisticsObject,
+				}})
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := tc.generate(&statisticsObjectSQLVertexGenerator{})
+			require.NoError(t, err)
+
+			var ddl []string
+			var advisory []bool
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				advisory = append(advisory, stmt.IsAdvisory)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedAdvisory, advisory)
+		})
+	}
+}
+
+func TestGenerateMigrationStatements_StatisticsObjectKindsChanged(t *testing.T) {
+	table := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}
+	buildSchema := func(kinds []string, def string) schema.Schema {
+		return schema.Schema{
+			Tables: []schema.Table{{
+				SchemaQualifiedName: table,
+				Columns:             []schema.Column{{Name: "city", Type: "text"}, {Name: "zip", Type: "text"}},
+				ReplicaIdentity:     schema.ReplicaIdentityDefault,
+			}},
+			StatisticsObjects: []schema.StatisticsObject{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo_city_zip\""},
+				Table:               table,
+				Kinds:               kinds,
+				Columns:             []string{"city", "zip"},
+				StatisticsTarget:    -1,
+				Def:                 def,
+			}},
+		}
+	}
+
+	stmts, err := generateMigrationStatements(
+		buildSchema([]string{"ndistinct"}, "CREATE STATISTICS public.foo_city_zip (ndistinct) ON city, zip FROM public.foo"),
+		buildSchema([]string{"ndistinct", "mcv"}, "CREATE STATISTICS public.foo_city_zip (ndistinct, mcv) ON city, zip FROM public.foo"),
+		&planOptions{},
+	)
+	require.NoError(t, err)
+	var ddl []string
+	for _, stmt := range stmts {
+		ddl = append(ddl, stmt.DDL)
+	}
+	// The statistics object is re-created, since its kinds cannot be altered
+	assert.Equal(t, []string{
+		"DROP STATISTICS \"public\".\"foo_city_zip\"",
+		"CREATE STATISTICS public.foo_city_zip (ndistinct, mcv) ON city, zip FROM public.foo",
+		"ANALYZE \"public\".\"foo\"",
+	}, ddl)
+	assert.True(t, stmts[len(stmts)-1].IsAdvisory)
+}
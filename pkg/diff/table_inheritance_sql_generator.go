@@ -0,0 +1,245 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var migrationHazardInheritedColumnsDiverged = MigrationHazard{
+	Type: MigrationHazardTypeCorrectness,
+	Message: "The definitions of some columns inherited from the parent table, e.g., their defaults or nullability, " +
+		"differ from the child table's. Altering these columns on the parent table also alters them on the child " +
+		"table, which can overwrite the child table's definitions.",
+}
+
+// buildInheritsClause builds the INHERITS clause of a CREATE TABLE statement for a table using (non-partition)
+// inheritance
+func buildInheritsClause(table schema.Table) string {
+	var parentNames []string
+	for _, parent := range table.InheritsFrom {
+		parentNames = append(parentNames, parent.GetFQEscapedName())
+	}
+	return fmt.Sprintf(" INHERITS (%s)", strings.Join(parentNames, ", "))
+}
+
+// buildInheritedColumnsStatements builds the statements to apply the child's definitions of the columns it only
+// inherits from its parents, e.g., defaults, since the inherited columns are created using the parent's definitions.
+func (t *tableSQLVertexGenerator) buildInheritedColumnsStatements(table schema.Table) ([]Statement, error) {
+	columnGenerator := &columnSQLVertexGenerator{tableName: table.SchemaQualifiedName}
+	var stmts []Statement
+	for _, column := range table.Columns {
+		if !column.IsInherited {
+			continue
+		}
+		parentColumn, ok := t.getParentColumn(table, column.Name)
+		if !ok {
+			return nil, fmt.Errorf("could not find parent of inherited column %q", column.Name)
+		}
+		// Identities are not inherited
+		parentColumn.Identity = nil
+		alterColumnStmts, err := columnGenerator.Alter(columnDiff{oldAndNew: oldAndNew[schema.Column]{old: parentColumn, new: column}})
+		if err != nil {
+			return nil, fmt.Errorf("altering inherited column %q: %w", column.Name, err)
+		}
+		stmts = append(stmts, alterColumnStmts...)
+	}
+	return stmts, nil
+}
+
+// getParentColumn gets the column with the given name from the first parent of the table (in the new schema) that
+// has it
+func (t *tableSQLVertexGenerator) getParentColumn(table schema.Table, columnName string) (schema.Column, bool) {
+	for _, parentName := range table.InheritsFrom {
+		parent := t.tablesInNewSchemaByName[parentName.GetName()]
+		for _, column := range parent.Columns {
+			if column.Name == columnName {
+				return column, true
+			}
+		}
+	}
+	return schema.Column{}, false
+}
+
+// hasDivergedInheritedColumns returns true if the definition of any column the table inherits from the given parents
+// differs from the parent's definition
+func (t *tableSQLVertexGenerator) hasDivergedInheritedColumns(table schema.Table, parentNames []schema.SchemaQualifiedName) bool {
+	for _, parentName := range parentNames {
+		parentColumnsByName := buildSchemaObjByNameMap(t.tablesInNewSchemaByName[parentName.GetName()].Columns)
+		for _, column := range table.Columns {
+			parentColumn, ok := parentColumnsByName[column.Name]
+			if !ok {
+				continue
+			}
+			if column.Default != parentColumn.Default ||
+				column.IsNullable != parentColumn.IsNullable ||
+				column.GenerationExpression != parentColumn.GenerationExpression {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// buildAlterInheritanceStatements builds the statements to remove the parents the table no longer inherits from and
+// the statements to add the parents it newly inherits from. The parents must be removed before the table's columns are
+// altered, since inherited columns cannot be dropped, and added after, since the table must have all the columns of
+// its new parents.
+func (t *tableSQLVertexGenerator) buildAlterInheritanceStatements(diff tableDiff) (noInheritStmts []Statement, inheritStmts []Statement) {
+	newParentsByName := buildSchemaObjByNameMap(diff.new.InheritsFrom)
+	for _, parent := range diff.old.InheritsFrom {
+		if _, ok := newParentsByName[parent.GetName()]; ok {
+			continue
+		}
+		noInheritStmts = append(noInheritStmts, Statement{
+			DDL:         fmt.Sprintf("%s NO INHERIT %s", alterTablePrefix(diff.new.SchemaQualifiedName), parent.GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	}
+
+	oldParentsByName := buildSchemaObjByNameMap(diff.old.InheritsFrom)
+	for _, parent := range diff.new.InheritsFrom {
+		if _, ok := oldParentsByName[parent.GetName()]; ok {
+			continue
+		}
+		stmt := Statement{
+			DDL:         fmt.Sprintf("%s INHERIT %s", alterTablePrefix(diff.new.SchemaQualifiedName), parent.GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		}
+		if t.hasDivergedInheritedColumns(diff.new, []schema.SchemaQualifiedName{parent}) {
+			stmt.Hazards = append(stmt.Hazards, migrationHazardInheritedColumnsDiverged)
+		}
+		inheritStmts = append(inheritStmts, stmt)
+	}
+	return noInheritStmts, inheritStmts
+}
+
+// applyInheritedColumnChanges updates the table's columns diff to account for the changes to the columns of the
+// parents it keeps inheriting from. The parents are altered before the table, and altering the columns of a parent
+// also alters them on its children, e.g., adding a column to a parent also adds it to its children.
+func (t *tableSQLVertexGenerator) applyInheritedColumnChanges(diff tableDiff) (tableDiff, error) {
+	oldParentsByName := buildSchemaObjByNameMap(diff.old.InheritsFrom)
+	var keptParentDiffs []tableDiff
+	for _, parent := range diff.new.InheritsFrom {
+		if _, ok := oldParentsByName[parent.GetName()]; !ok {
+			continue
+		}
+		if _, ok := t.deletedTablesByName[parent.GetName()]; ok {
+			return tableDiff{}, fmt.Errorf("re-creating %s, which %s inherits from: %w", parent.GetName(), diff.new.GetName(), ErrNotImplemented)
+		}
+		if parentDiff, ok := t.tableDiffsByName[parent.GetName()]; ok {
+			keptParentDiffs = append(keptParentDiffs, parentDiff)
+		}
+	}
+	if len(keptParentDiffs) == 0 {
+		return diff, nil
+	}
+
+	addedParentColumnsByName := make(map[string]schema.Column)
+	alteredParentColumnsByName := make(map[string]columnDiff)
+	for _, parentDiff := range keptParentDiffs {
+		for _, column := range parentDiff.columnsDiff.adds {
+			addedParentColumnsByName[column.Name] = column
+		}
+		for _, colDiff := range parentDiff.columnsDiff.alters {
+			alteredParentColumnsByName[colDiff.new.Name] = colDiff
+		}
+	}
+
+	var columnsDiff listDiff[schema.Column, columnDiff]
+	for _, column := range diff.columnsDiff.adds {
+		parentColumn, ok := addedParentColumnsByName[column.Name]
+		if !ok {
+			columnsDiff.adds = append(columnsDiff.adds, column)
+			continue
+		}
+		// The column was already added by the parent, so only the child's definition of it must be applied
+		parentColumn.Identity = nil
+		columnsDiff.alters = append(columnsDiff.alters, columnDiff{
+			oldAndNew: oldAndNew[schema.Column]{old: parentColumn, new: column},
+		})
+	}
+	for _, colDiff := range diff.columnsDiff.alters {
+		if colDiff.old.IsInherited && t.isColumnDroppedByParents(diff, colDiff.old.Name) {
+			// The column was dropped by the parents, so it must be re-added to the child
+			columnsDiff.adds = append(columnsDiff.adds, colDiff.new)
+			continue
+		}
+		if parentColDiff, ok := alteredParentColumnsByName[colDiff.new.Name]; ok {
+			colDiff.old = applyParentColumnChanges(colDiff.old, parentColDiff)
+		}
+		columnsDiff.alters = append(columnsDiff.alters, colDiff)
+	}
+	for _, column := range diff.columnsDiff.deletes {
+		if column.IsInherited && t.isColumnDroppedByParents(diff, column.Name) {
+			// The column was already dropped by the parents
+			continue
+		}
+		columnsDiff.deletes = append(columnsDiff.deletes, column)
+	}
+	diff.columnsDiff = columnsDiff
+	return diff, nil
+}
+
+// isColumnDroppedByParents returns true if dropping the column from the parents the table keeps inheriting from also
+// drops it from the table, i.e., all the kept parents that define the column drop it. The parents the table no longer
+// inherits from are removed before the kept parents are altered, so they do not keep the column. It assumes the column
+// is not defined locally by the table.
+func (t *tableSQLVertexGenerator) isColumnDroppedByParents(diff tableDiff, columnName string) bool {
+	newParentsByName := buildSchemaObjByNameMap(diff.new.InheritsFrom)
+	isDropped := false
+	for _, parent := range diff.old.InheritsFrom {
+		oldParent, ok := t.getOldTable(parent)
+		if !ok {
+			continue
+		}
+		if _, ok := buildSchemaObjByNameMap(oldParent.Columns)[columnName]; !ok {
+			continue
+		}
+		if _, isKept := newParentsByName[parent.GetName()]; !isKept {
+			continue
+		}
+		parentDiff, ok := t.tableDiffsByName[parent.GetName()]
+		if !ok {
+			return false
+		}
+		if _, isDeleted := buildSchemaObjByNameMap(parentDiff.columnsDiff.deletes)[columnName]; !isDeleted {
+			return false
+		}
+		isDropped = true
+	}
+	return isDropped
+}
+
+// getOldTable gets the table from the old schema
+func (t *tableSQLVertexGenerator) getOldTable(name schema.SchemaQualifiedName) (schema.Table, bool) {
+	if tableDiff, ok := t.tableDiffsByName[name.GetName()]; ok {
+		return tableDiff.old, true
+	}
+	table, ok := t.deletedTablesByName[name.GetName()]
+	return table, ok
+}
+
+// applyParentColumnChanges applies the changes made to a parent's column to the child's column, since altering the
+// parent's column also alters the child's column
+func applyParentColumnChanges(column schema.Column, parentColDiff columnDiff) schema.Column {
+	parentOld, parentNew := parentColDiff.old, parentColDiff.new
+	if parentOld.Type != parentNew.Type || parentOld.Collation != parentNew.Collation {
+		column.Type = parentNew.Type
+		column.Collation = parentNew.Collation
+	}
+	if parentOld.Default != parentNew.Default {
+		column.Default = parentNew.Default
+	}
+	if parentOld.IsNullable != parentNew.IsNullable {
+		column.IsNullable = parentNew.IsNullable
+	}
+	if parentOld.StorageType != parentNew.StorageType {
+		column.StorageType = parentNew.StorageType
+		column.DefaultStorageType = parentNew.DefaultStorageType
+	}
+	return column
+}
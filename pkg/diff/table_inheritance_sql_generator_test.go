@@ -0,0 +1,189 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestGenerateMigrationStatements_TableInheritance(t *testing.T) {
+	parentName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"parent\""}
+	otherParentName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"other_parent\""}
+	childName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"child\""}
+	buildTable := func(name schema.SchemaQualifiedName, inheritsFrom []schema.SchemaQualifiedName, columns ...schema.Column) schema.Table {
+		return schema.Table{
+			SchemaQualifiedName: name,
+			Columns:             columns,
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+			InheritsFrom:        inheritsFrom,
+		}
+	}
+	id := schema.Column{Name: "id", Type: "integer", IsNullable: true}
+	val := schema.Column{Name: "val", Type: "text", IsNullable: true}
+	inherited := func(column schema.Column) schema.Column {
+		column.IsInherited = true
+		return column
+	}
+	withDefault := func(column schema.Column, def string) schema.Column {
+		column.Default = def
+		return column
+	}
+
+	for _, tc := range []struct {
+		name            string
+		old             []schema.Table
+		new             []schema.Table
+		expectedDDL     []string
+		expectedHazards []MigrationHazard
+		expectedErrIs   error
+	}{
+		{
+			name: "create child with parent",
+			new: []schema.Table{
+				buildTable(parentName, nil, id, val),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, inherited(id), inherited(withDefault(val, "'child'::text"))),
+			},
+			expectedDDL: []string{
+				"CREATE TABLE \"public\".\"parent\" (\n\t\"id\" integer,\n\t\"val\" text\n)",
+				"CREATE TABLE \"public\".\"child\" (\n\n) INHERITS (\"public\".\"parent\")",
+				"ALTER TABLE \"public\".\"child\" ALTER COLUMN \"val\" SET DEFAULT 'child'::text",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardInheritedColumnsDiverged},
+		},
+		{
+			name: "add inheritance",
+			old: []schema.Table{
+				buildTable(parentName, nil, id, val),
+				buildTable(childName, nil, id),
+			},
+			new: []schema.Table{
+				buildTable(parentName, nil, id, val),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, id, val),
+			},
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"child\" ADD COLUMN \"val\" text",
+				"ALTER TABLE \"public\".\"child\" INHERIT \"public\".\"parent\"",
+				"ANALYZE \"public\".\"child\"",
+			},
+		},
+		{
+			name: "remove inheritance and drop inherited column",
+			old: []schema.Table{
+				buildTable(parentName, nil, id, val),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, inherited(id), inherited(val)),
+			},
+			new: []schema.Table{
+				buildTable(parentName, nil, id, val),
+				buildTable(childName, nil, id),
+			},
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"child\" NO INHERIT \"public\".\"parent\"",
+				"ALTER TABLE \"public\".\"child\" DROP COLUMN \"val\"",
+				"ANALYZE \"public\".\"child\"",
+			},
+			expectedHazards: []MigrationHazard{{Type: MigrationHazardTypeDeletesData, Message: "Deletes all values in the column"}},
+		},
+		{
+			name: "change parents",
+			old: []schema.Table{
+				buildTable(parentName, nil, id),
+				buildTable(otherParentName, nil, id, val),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, inherited(id)),
+			},
+			new: []schema.Table{
+				buildTable(parentName, nil, id),
+				buildTable(otherParentName, nil, id, val),
+				buildTable(childName, []schema.SchemaQualifiedName{otherParentName}, id, val),
+			},
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"child\" NO INHERIT \"public\".\"parent\"",
+				"ALTER TABLE \"public\".\"child\" ADD COLUMN \"val\" text",
+				"ALTER TABLE \"public\".\"child\" INHERIT \"public\".\"other_parent\"",
+				"ANALYZE \"public\".\"child\"",
+			},
+		},
+		{
+			name: "columns added to and dropped from parent are propagated",
+			old: []schema.Table{
+				buildTable(parentName, nil, id),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, inherited(id)),
+			},
+			new: []schema.Table{
+				buildTable(parentName, nil, val),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, inherited(withDefault(val, "'child'::text"))),
+			},
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"parent\" ADD COLUMN \"val\" text",
+				"ALTER TABLE \"public\".\"parent\" DROP COLUMN \"id\"",
+				"ALTER TABLE \"public\".\"child\" ALTER COLUMN \"val\" SET DEFAULT 'child'::text",
+				"ANALYZE \"public\".\"parent\"",
+			},
+			expectedHazards: []MigrationHazard{{Type: MigrationHazardTypeDeletesData, Message: "Deletes all values in the column"}},
+		},
+		{
+			name: "parent column defaults are propagated",
+			old: []schema.Table{
+				buildTable(parentName, nil, id),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, inherited(id)),
+			},
+			new: []schema.Table{
+				buildTable(parentName, nil, withDefault(id, "1")),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, inherited(withDefault(id, "1"))),
+			},
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"parent\" ALTER COLUMN \"id\" SET DEFAULT 1",
+			},
+		},
+		{
+			name: "drop parent and child",
+			old: []schema.Table{
+				buildTable(parentName, nil, id),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, inherited(id)),
+			},
+			expectedDDL: []string{
+				"DROP TABLE \"public\".\"child\"",
+				"DROP TABLE \"public\".\"parent\"",
+			},
+			expectedHazards: []MigrationHazard{
+				{Type: MigrationHazardTypeDeletesData, Message: "Deletes all rows in the table (and the table itself)"},
+				{Type: MigrationHazardTypeDeletesData, Message: "Deletes all rows in the table (and the table itself)"},
+			},
+		},
+		{
+			name: "re-create parent of kept child",
+			old: []schema.Table{
+				buildTable(parentName, nil, id),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, inherited(id)),
+			},
+			new: []schema.Table{
+				func() schema.Table {
+					parent := buildTable(parentName, nil, id)
+					parent.PartitionKeyDef = "LIST (id)"
+					return parent
+				}(),
+				buildTable(childName, []schema.SchemaQualifiedName{parentName}, inherited(id)),
+			},
+			expectedErrIs: ErrNotImplemented,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(schema.Schema{Tables: tc.old}, schema.Schema{Tables: tc.new}, &planOptions{})
+			if tc.expectedErrIs != nil {
+				require.ErrorIs(t, err, tc.expectedErrIs)
+				return
+			}
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+// RenameCandidate is a table in the current schema that might have been renamed to a table in the new schema: the old
+// table does not exist in the new schema, the new table does not exist in the current schema, and both tables have the
+// same columns. Candidates are only detected if the plan is generated with WithDetectRenames. Confirm a candidate via
+// Plan.ConfirmRename to rename the table rather than dropping and re-creating it.
+type RenameCandidate struct {
+	Old schema.SchemaQualifiedName `json:"old"`
+	New schema.SchemaQualifiedName `json:"new"`
+}
+
+// tableRename is a rename of a table configured via WithRenamedTable or Plan.ConfirmRename
+type tableRename struct {
+	old schema.SchemaQualifiedName
+	new schema.SchemaQualifiedName
+}
+
+// renameState is the state required to re-generate a plan once a rename candidate is confirmed
+type renameState struct {
+	currentSchema schema.Schema
+	newSchema     schema.Schema
+	planOptions   planOptions
+}
+
+// ConfirmRename confirms the rename candidate, returning a new plan that renames the old table via
+// `ALTER TABLE ... RENAME TO ...` rather than dropping the old table and creating the new table. The objects that depend
+// on the table, e.g., indexes, foreign keys, and views, are diffed against the renamed table as usual.
+//
+// The plan must be returned by Generate with WithDetectRenames; deserialized plans cannot be modified. Unlike Generate,
+// ConfirmRename does not validate the new plan. To validate it, generate the plan again with WithRenamedTable.
+func (p Plan) ConfirmRename(old, new schema.SchemaQualifiedName) (Plan, error) {
+	if p.renameState == nil {
+		return Plan{}, fmt.Errorf("plan was not generated with rename detection enabled")
+	}
+	var isCandidate bool
+	for _, candidate := range p.RenameCandidates {
+		if candidate.Old == old && candidate.New == new {
+			isCandidate = true
+			break
+		}
+	}
+	if !isCandidate {
+		return Plan{}, fmt.Errorf("%s -> %s is not a rename candidate", old.GetFQEscapedName(), new.GetFQEscapedName())
+	}
+
+	options := p.renameState.planOptions
+	options.tableRenames = append(append([]tableRename(nil), options.tableRenames...), tableRename{old: old, new: new})
+	return buildPlan(p.renameState.currentSchema, p.renameState.newSchema, &options)
+}
+
+// detectTableRenameCandidates finds the tables that were deleted from the old schema that might have been renamed to the
+// tables added to the new schema. Tables can only be renamed within the same named schema.
+func detectTableRenameCandidates(oldSchema, newSchema schema.Schema) []RenameCandidate {
+	oldTablesByName := buildSchemaObjByNameMap(oldSchema.Tables)
+	newTablesByName := buildSchemaObjByNameMap(newSchema.Tables)
+
+	var candidates []RenameCandidate
+	for _, oldTable := range oldSchema.Tables {
+		if _, ok := newTablesByName[oldTable.GetName()]; ok || oldTable.IsPartition() {
+			continue
+		}
+		for _, newTable := range newSchema.Tables {
+			if _, ok := oldTablesByName[newTable.GetName()]; ok || newTable.IsPartition() {
+				continue
+			}
+			if oldTable.SchemaName != newTable.SchemaName || !hasIdenticalColumns(oldTable, newTable) {
+				continue
+			}
+			candidates = append(candidates, RenameCandidate{Old: oldTable.SchemaQualifiedName, New: newTable.SchemaQualifiedName})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Old != candidates[j].Old {
+			return candidates[i].Old.GetName() < candidates[j].Old.GetName()
+		}
+		return candidates[i].New.GetName() < candidates[j].New.GetName()
+	})
+	return candidates
+}
+
+// hasIdenticalColumns returns true if the tables have the same set of columns, ignoring column order and defaults. The
+// defaults are ignored because they often reference objects named after the table, e.g., the table's sequence.
+func hasIdenticalColumns(a, b schema.Table) bool {
+	if len(a.Columns) != len(b.Columns) {
+		return false
+	}
+	bColumnsByName := buildSchemaObjByNameMap(b.Columns)
+	for _, aCol := range a.Columns {
+		bCol, ok := bColumnsByName[aCol.Name]
+		if !ok || aCol.Type != bCol.Type || aCol.Collation != bCol.Collation || aCol.IsNullable != bCol.IsNullable {
+			return false
+		}
+	}
+	return true
+}
+
+// buildRenameTableStatement builds the statement to rename a table. Postgres automatically updates the objects that
+// reference the table by OID, e.g., indexes, foreign keys, and views.
+func buildRenameTableStatement(rename tableRename) Statement {
+	return Statement{
+		DDL:         fmt.Sprintf("%s RENAME TO %s", alterTablePrefix(rename.old), rename.new.EscapedName),
+		Timeout:     statementTimeoutDefault,
+		LockTimeout: lockTimeoutDefault,
+		Hazards: []MigrationHazard{
+			{
+				Type:    MigrationHazardTypeAcquiresAccessExclusiveLock,
+				Message: "Renaming a table briefly acquires an access exclusive lock on the table. It should be fast.",
+			},
+			{
+				Type: MigrationHazardTypeHasUntrackableDependencies,
+				Message: "Queries, and the bodies of functions, that reference the table by its old name will fail after " +
+					"the table is renamed. These references cannot be tracked.",
+			},
+		},
+	}
+}
+
+// renameTables returns a copy of the schema where the tables are renamed, i.e., the schema that Postgres would report
+// after running `ALTER TABLE ... RENAME TO ...`, and the renames that were applied. This allows the renamed tables to
+// be diffed against the tables in the new schema rather than being dropped and re-created.
+//
+// Renames that were already applied, i.e., the old table does not exist but the new table does, are skipped, such that
+// a plan can be generated again after the rename is executed.
+func renameTables(s schema.Schema, renames []tableRename) (schema.Schema, []tableRename, error) {
+	if len(renames) == 0 {
+		return s, nil, nil
+	}
+
+	tablesByName := buildSchemaObjByNameMap(s.Tables)
+	var pendingRenames []tableRename
+	for _, rename := range renames {
+		if rename.old.SchemaName != rename.new.SchemaName {
+			return schema.Schema{}, nil, fmt.Errorf("cannot rename table %s to %s because tables cannot be renamed across schemas", rename.old.GetFQEscapedName(), rename.new.GetFQEscapedName())
+		}
+		_, oldExists := tablesByName[rename.old.GetName()]
+		_, newExists := tablesByName[rename.new.GetName()]
+		switch {
+		case !oldExists && newExists:
+			continue
+		case !oldExists:
+			return schema.Schema{}, nil, fmt.Errorf("cannot rename table %s because it does not exist", rename.old.GetFQEscapedName())
+		case newExists:
+			return schema.Schema{}, nil, fmt.Errorf("cannot rename table %s to %s because %s already exists", rename.old.GetFQEscapedName(), rename.new.GetFQEscapedName(), rename.new.GetFQEscapedName())
+		}
+		pendingRenames = append(pendingRenames, rename)
+	}
+	if len(pendingRenames) == 0 {
+		return s, nil, nil
+	}
+
+	renamed := s.DeepCopy()
+	for _, rename := range pendingRenames {
+		renameTableInValue(reflect.ValueOf(&renamed).Elem(), rename)
+		if rename.old.SchemaName == "public" {
+			renameUnqualifiedTableReferences(&renamed, rename)
+		}
+	}
+	return renamed, pendingRenames, nil
+}
+
+// renameUnqualifiedTableReferences renames the unqualified references to the table in the definitions that might
+// contain them. Postgres does not qualify references to tables on the search path, which includes the public schema by
+// default. If a reference is renamed by mistake, e.g., a column with the same name as the table, the definition will
+// differ from the new definition, and the object will be re-created rather than left untouched.
+func renameUnqualifiedTableReferences(s *schema.Schema, rename tableRename) {
+	oldRefs := identifierForms(rename.old.EscapedName)
+	newRef := simplestIdentifierForm(rename.new.EscapedName)
+	for i, fk := range s.ForeignKeyConstraints {
+		if fk.ForeignTable == rename.new {
+			s.ForeignKeyConstraints[i].ConstraintDef = replaceIdentifierReferences(fk.ConstraintDef, prefixEach("REFERENCES ", oldRefs), "REFERENCES "+newRef)
+		}
+	}
+	for i, v := range s.Views {
+		s.Views[i].Definition = replaceIdentifierReferences(v.Definition, oldRefs, newRef)
+	}
+	for i, mv := range s.MaterializedViews {
+		s.MaterializedViews[i].Definition = replaceIdentifierReferences(mv.Definition, oldRefs, newRef)
+	}
+	for i, f := range s.Functions {
+		s.Functions[i].FunctionDef = replaceIdentifierReferencesOutsideBody(f.FunctionDef, oldRefs, newRef)
+	}
+	for i, p := range s.Procedures {
+		s.Procedures[i].Def = replaceIdentifierReferencesOutsideBody(p.Def, oldRefs, newRef)
+	}
+}
+
+// replaceIdentifierReferencesOutsideBody replaces the references in a function definition, as returned by
+// pg_get_functiondef. String bodies, i.e., `AS $function$...$function$`, are stored verbatim by Postgres, so they are not
+// updated by a rename and are left untouched.
+func replaceIdentifierReferencesOutsideBody(def string, oldRefs []string, newRef string) string {
+	if bodyIdx := strings.Index(def, "\nAS "); bodyIdx != -1 {
+		return replaceIdentifierReferences(def[:bodyIdx], oldRefs, newRef) + def[bodyIdx:]
+	}
+	return replaceIdentifierReferences(def, oldRefs, newRef)
+}
+
+// renameTableInValue recursively renames the table in all schema qualified names and definitions contained by the
+// value. The value must be settable.
+func renameTableInValue(v reflect.Value, rename tableRename) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			renameTableInValue(v.Elem(), rename)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			renameTableInValue(v.Index(i), rename)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(v.MapIndex(key))
+			renameTableInValue(value, rename)
+			v.SetMapIndex(key, value)
+		}
+	case reflect.String:
+		v.SetString(renameTableInDefinition(v.String(), rename))
+	case reflect.Struct:
+		if v.Type() == schemaQualifiedNameType {
+			if v.Interface().(schema.SchemaQualifiedName) == rename.old {
+				v.Set(reflect.ValueOf(rename.new))
+				return
+			}
+			// The escaped name might contain references to the table, e.g., the argument types of a function
+			renameTableInValue(v.FieldByName("EscapedName"), rename)
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if (v.Type() == functionType && field.Name == "FunctionDef") || (v.Type() == procedureType && field.Name == "Def") {
+				oldRefs, newRef := qualifiedTableReferences(rename)
+				v.Field(i).SetString(replaceIdentifierReferencesOutsideBody(v.Field(i).String(), oldRefs, newRef))
+				continue
+			}
+			renameTableInValue(v.Field(i), rename)
+		}
+	}
+}
+
+// renameTableInDefinition renames the schema-qualified references to the table in a definition generated by Postgres,
+// e.g., `CREATE INDEX foo ON schema_1.bar USING btree (id)`
+func renameTableInDefinition(def string, rename tableRename) string {
+	oldRefs, newRef := qualifiedTableReferences(rename)
+	return replaceIdentifierReferences(def, oldRefs, newRef)
+}
+
+// qualifiedTableReferences returns the forms a schema-qualified reference to the old table might take in a definition
+// generated by Postgres and the form of a reference to the new table. Postgres only quotes identifiers when necessary.
+func qualifiedTableReferences(rename tableRename) ([]string, string) {
+	var oldRefs []string
+	for _, schemaForm := range identifierForms(schema.EscapeIdentifier(rename.old.SchemaName)) {
+		oldRefs = append(oldRefs, prefixEach(schemaForm+".", identifierForms(rename.old.EscapedName))...)
+	}
+	newRef := simplestIdentifierForm(schema.EscapeIdentifier(rename.new.SchemaName)) + "." + simplestIdentifierForm(rename.new.EscapedName)
+	return oldRefs, newRef
+}
+
+// replaceIdentifierReferences replaces the references in the definition that are not part of a longer identifier
+func replaceIdentifierReferences(def string, oldRefs []string, newRef string) string {
+	for _, oldRef := range oldRefs {
+		sb := strings.Builder{}
+		for {
+			idx := strings.Index(def, oldRef)
+			if idx == -1 {
+				sb.WriteString(def)
+				break
+			}
+			sb.WriteString(def[:idx])
+			end := idx + len(oldRef)
+			if (idx > 0 && isIdentifierPartOrQuote(def[idx-1])) || (end < len(def) && (def[end] == '"' || isIdentifierPart(def[end]))) {
+				sb.WriteString(oldRef)
+			} else {
+				sb.WriteString(newRef)
+			}
+			def = def[end:]
+		}
+		def = sb.String()
+	}
+	return def
+}
+
+// identifierForms returns the forms the escaped identifier might take in a definition generated by Postgres: the
+// escaped identifier and, if quoting is not necessary, the bare identifier
+func identifierForms(escapedIdentifier string) []string {
+	forms := []string{escapedIdentifier}
+	if bare := unescapeIdentifier(escapedIdentifier); simpleIdentifierRegex.MatchString(bare) {
+		forms = append(forms, bare)
+	}
+	return forms
+}
+
+// simplestIdentifierForm returns the form Postgres would use for the escaped identifier in a definition
+func simplestIdentifierForm(escapedIdentifier string) string {
+	if bare := unescapeIdentifier(escapedIdentifier); simpleIdentifierRegex.MatchString(bare) {
+		return bare
+	}
+	return escapedIdentifier
+}
+
+func prefixEach(prefix string, vals []string) []string {
+	var prefixed []string
+	for _, val := range vals {
+		prefixed = append(prefixed, prefix+val)
+	}
+	return prefixed
+}
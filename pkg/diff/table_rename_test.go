@@ -0,0 +1,159 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestDetectTableRenameCandidates(t *testing.T) {
+	foo := schema.Table{
+		SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"`},
+		Columns: []schema.Column{
+			{Name: "id", Type: "integer"},
+			{Name: "val", Type: "text", IsNullable: true, Collation: defaultCollation},
+		},
+		ReplicaIdentity: schema.ReplicaIdentityDefault,
+	}
+	bar := foo
+	bar.SchemaQualifiedName = schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"bar"`}
+	// Column order and defaults are ignored
+	bar.Columns = []schema.Column{foo.Columns[1], foo.Columns[0]}
+	bar.Columns[0].Default = "'some default'::text"
+	barInOtherSchema := foo
+	barInOtherSchema.SchemaQualifiedName = schema.SchemaQualifiedName{SchemaName: "other", EscapedName: `"bar"`}
+	barWithOtherColumns := foo
+	barWithOtherColumns.SchemaQualifiedName = bar.SchemaQualifiedName
+	barWithOtherColumns.Columns = []schema.Column{{Name: "id", Type: "bigint"}, foo.Columns[1]}
+
+	for _, tc := range []struct {
+		name               string
+		oldSchema          schema.Schema
+		newSchema          schema.Schema
+		expectedCandidates []RenameCandidate
+	}{
+		{
+			name:               "Table with identical columns",
+			oldSchema:          schema.Schema{Tables: []schema.Table{foo}},
+			newSchema:          schema.Schema{Tables: []schema.Table{bar}},
+			expectedCandidates: []RenameCandidate{{Old: foo.SchemaQualifiedName, New: bar.SchemaQualifiedName}},
+		},
+		{
+			name:      "Table with different columns",
+			oldSchema: schema.Schema{Tables: []schema.Table{foo}},
+			newSchema: schema.Schema{Tables: []schema.Table{barWithOtherColumns}},
+		},
+		{
+			name:      "Table in different schema",
+			oldSchema: schema.Schema{Tables: []schema.Table{foo}},
+			newSchema: schema.Schema{Tables: []schema.Table{barInOtherSchema}},
+		},
+		{
+			name:      "Table still exists",
+			oldSchema: schema.Schema{Tables: []schema.Table{foo}},
+			newSchema: schema.Schema{Tables: []schema.Table{foo, bar}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedCandidates, detectTableRenameCandidates(tc.oldSchema, tc.newSchema))
+		})
+	}
+}
+
+func TestPlan_ConfirmRename(t *testing.T) {
+	fooName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo"`}
+	barName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"bar"`}
+	bazName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"baz"`}
+	buildSchema := func(tableName schema.SchemaQualifiedName, unqualifiedTableName string) schema.Schema {
+		return schema.Schema{
+			Tables: []schema.Table{
+				{
+					SchemaQualifiedName: tableName,
+					Columns:             []schema.Column{{Name: "id", Type: "integer"}},
+					ReplicaIdentity:     schema.ReplicaIdentityDefault,
+				},
+				{
+					SchemaQualifiedName: bazName,
+					Columns:             []schema.Column{{Name: "foo_id", Type: "integer", IsNullable: true}},
+					ReplicaIdentity:     schema.ReplicaIdentityDefault,
+				},
+			},
+			Indexes: []schema.Index{{
+				Name:            "foo_id_idx",
+				OwningTable:     tableName,
+				Columns:         []string{"id"},
+				IsUnique:        true,
+				GetIndexDefStmt: schema.GetIndexDefStatement("CREATE UNIQUE INDEX foo_id_idx ON public." + unqualifiedTableName + " USING btree (id)"),
+			}},
+			ForeignKeyConstraints: []schema.ForeignKeyConstraint{{
+				EscapedName:   `"baz_foo_fk"`,
+				OwningTable:   bazName,
+				ForeignTable:  tableName,
+				ConstraintDef: "FOREIGN KEY (foo_id) REFERENCES " + unqualifiedTableName + "(id)",
+				IsValid:       true,
+			}},
+			Views: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foo_view"`},
+				Definition:          " SELECT id\n   FROM " + unqualifiedTableName + ";",
+				DependsOnTables:     []schema.SchemaQualifiedName{tableName},
+			}},
+			Functions: []schema.Function{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"count_foo"()`},
+				FunctionDef:         "CREATE OR REPLACE FUNCTION public.count_foo()\n RETURNS bigint\n LANGUAGE sql\nAS $function$SELECT count(*) FROM public." + unqualifiedTableName + "$function$\n",
+				Language:            "sql",
+			}},
+		}
+	}
+	oldSchema := buildSchema(fooName, "foo")
+	newSchema := buildSchema(barName, "bar")
+
+	plan, err := buildPlan(oldSchema, newSchema, &planOptions{detectRenames: true})
+	require.NoError(t, err)
+	assert.Equal(t, []RenameCandidate{{Old: fooName, New: barName}}, plan.RenameCandidates)
+	assert.Contains(t, getDDL(plan), `DROP TABLE "public"."foo"`)
+
+	_, err = plan.ConfirmRename(barName, fooName)
+	assert.ErrorContains(t, err, "not a rename candidate")
+	_, err = Plan{}.ConfirmRename(fooName, barName)
+	assert.ErrorContains(t, err, "rename detection")
+
+	renamedPlan, err := plan.ConfirmRename(fooName, barName)
+	require.NoError(t, err)
+	assert.Empty(t, renamedPlan.RenameCandidates)
+	ddl := getDDL(renamedPlan)
+	require.NotEmpty(t, ddl)
+	assert.Equal(t, `ALTER TABLE "public"."foo" RENAME TO "bar"`, ddl[0])
+	for _, stmt := range ddl {
+		assert.NotContains(t, stmt, "DROP TABLE")
+		assert.NotContains(t, stmt, "CREATE TABLE")
+		// The index, foreign key, and view reference the table by OID, so they are not re-created
+		assert.NotContains(t, stmt, "foo_id_idx")
+		assert.NotContains(t, stmt, "baz_foo_fk")
+		assert.NotContains(t, stmt, "foo_view")
+	}
+	// The function body references the table by name, so it is re-created
+	assert.Contains(t, ddl, "CREATE OR REPLACE FUNCTION public.count_foo()\n RETURNS bigint\n LANGUAGE sql\nAS $function$SELECT count(*) FROM public.bar$function$\n")
+
+	t.Run("Already applied renames are skipped", func(t *testing.T) {
+		// Planning again after the rename was executed, or validating the plan, must not rename the table again
+		plan, err := buildPlan(newSchema, newSchema, &planOptions{tableRenames: []tableRename{{old: fooName, new: barName}}})
+		require.NoError(t, err)
+		assert.Empty(t, plan.Statements)
+
+		_, err = buildPlan(newSchema, newSchema, &planOptions{tableRenames: []tableRename{{
+			old: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"qux"`},
+			new: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"quux"`},
+		}}})
+		assert.ErrorContains(t, err, "does not exist")
+	})
+}
+
+func getDDL(plan Plan) []string {
+	var ddl []string
+	for _, stmt := range plan.Statements {
+		ddl = append(ddl, stmt.DDL)
+	}
+	return ddl
+}
@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+const (
+	// defaultTablespace is the tablespace that objects are moved to when they no longer have a tablespace, i.e., they
+	// are moved back to the database's default tablespace
+	defaultTablespace = "pg_default"
+)
+
+var (
+	migrationHazardTablespaceChangedAcquiresLock = MigrationHazard{
+		Type: MigrationHazardTypeAcquiresAccessExclusiveLock,
+		Message: "Moving to a different tablespace copies all of the data, which locks out all reads and writes " +
+			"until the copy completes",
+	}
+	migrationHazardTablespaceUntracked = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "Tablespaces are not tracked by pg-schema-diff. The tablespace must already exist and be usable by " +
+			"the migrating role.",
+	}
+)
+
+// buildTablespaceClause builds the TABLESPACE clause of a CREATE statement. It is empty if the object is in the
+// database's default tablespace.
+func buildTablespaceClause(tablespace string) string {
+	if len(tablespace) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" TABLESPACE %s", schema.EscapeIdentifier(tablespace))
+}
+
+// buildSetTablespaceStatement builds the statement to move an object to a different tablespace. The alter prefix is the
+// statement that the SET TABLESPACE clause is appended to, e.g., "ALTER TABLE foo". Moving a partitioned table or index
+// does not move any data; it only changes the tablespace of the partitions created afterward.
+func buildSetTablespaceStatement(alterPrefix, tablespace string, isPartitioned bool) Statement {
+	if len(tablespace) == 0 {
+		tablespace = defaultTablespace
+	}
+	stmt := Statement{
+		DDL:         fmt.Sprintf("%s SET TABLESPACE %s", alterPrefix, schema.EscapeIdentifier(tablespace)),
+		Timeout:     statementTimeoutSetTablespace,
+		LockTimeout: lockTimeoutDefault,
+		Hazards:     []MigrationHazard{migrationHazardTablespaceChangedAcquiresLock, migrationHazardTablespaceUntracked},
+	}
+	if isPartitioned {
+		stmt.Timeout = statementTimeoutDefault
+		stmt.Hazards = []MigrationHazard{migrationHazardTablespaceUntracked}
+	}
+	return stmt
+}
+
+// addTablespaceToCreateIndexStmt adds the index's TABLESPACE clause to its CREATE INDEX statement, since
+// pg_get_indexdef omits it. The clause must precede the WHERE clause of a partial index.
+func addTablespaceToCreateIndexStmt(createIdxStmt string, index schema.Index) (string, error) {
+	tablespaceClause := buildTablespaceClause(index.Tablespace)
+	if len(tablespaceClause) == 0 {
+		return createIdxStmt, nil
+	}
+	if len(index.Predicate) == 0 {
+		return createIdxStmt + tablespaceClause, nil
+	}
+
+	whereClause := fmt.Sprintf(" WHERE %s", index.Predicate)
+	if !strings.HasSuffix(createIdxStmt, whereClause) {
+		return "", fmt.Errorf("%q does not end with the index's predicate %q", createIdxStmt, index.Predicate)
+	}
+	return strings.TrimSuffix(createIdxStmt, whereClause) + tablespaceClause + whereClause, nil
+}
@@ -0,0 +1,141 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestGenerateMigrationStatements_Tablespaces(t *testing.T) {
+	tableName := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"foo\""}
+	buildTable := func(tablespace string) schema.Table {
+		return schema.Table{
+			SchemaQualifiedName: tableName,
+			Columns:             []schema.Column{{Name: "id", Type: "integer", IsNullable: true}},
+			ReplicaIdentity:     schema.ReplicaIdentityDefault,
+			Tablespace:          tablespace,
+		}
+	}
+	buildIndex := func(tablespace, predicate string) schema.Index {
+		defStmt := "CREATE INDEX foo_id_idx ON public.foo USING btree (id)"
+		if len(predicate) > 0 {
+			defStmt += " WHERE " + predicate
+		}
+		return schema.Index{
+			Name:            "foo_id_idx",
+			OwningTable:     tableName,
+			Columns:         []string{"id"},
+			Predicate:       predicate,
+			GetIndexDefStmt: schema.GetIndexDefStatement(defStmt),
+			Tablespace:      tablespace,
+		}
+	}
+
+	for _, tc := range []struct {
+		name            string
+		old             schema.Schema
+		new             schema.Schema
+		expectedDDL     []string
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name: "create table in tablespace",
+			new:  schema.Schema{Tables: []schema.Table{buildTable("fast_ssd")}},
+			expectedDDL: []string{
+				"CREATE TABLE \"public\".\"foo\" (\n\t\"id\" integer\n) TABLESPACE \"fast_ssd\"",
+			},
+		},
+		{
+			name: "move table to tablespace",
+			old:  schema.Schema{Tables: []schema.Table{buildTable("")}},
+			new:  schema.Schema{Tables: []schema.Table{buildTable("fast_ssd")}},
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"foo\" SET TABLESPACE \"fast_ssd\"",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardTablespaceChangedAcquiresLock, migrationHazardTablespaceUntracked},
+		},
+		{
+			name: "move partitioned table back to default tablespace",
+			old: schema.Schema{Tables: []schema.Table{func() schema.Table {
+				table := buildTable("fast_ssd")
+				table.PartitionKeyDef = "LIST (id)"
+				return table
+			}()}},
+			new: schema.Schema{Tables: []schema.Table{func() schema.Table {
+				table := buildTable("")
+				table.PartitionKeyDef = "LIST (id)"
+				return table
+			}()}},
+			expectedDDL: []string{
+				"ALTER TABLE \"public\".\"foo\" SET TABLESPACE \"pg_default\"",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardTablespaceUntracked},
+		},
+		{
+			name: "create partial index in tablespace",
+			old:  schema.Schema{Tables: []schema.Table{buildTable("")}},
+			new: schema.Schema{
+				Tables:  []schema.Table{buildTable("")},
+				Indexes: []schema.Index{buildIndex("fast_ssd", "(id > 0)")},
+			},
+			expectedDDL: []string{
+				"CREATE INDEX CONCURRENTLY foo_id_idx ON public.foo USING btree (id) TABLESPACE \"fast_ssd\" WHERE (id > 0)",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardIndexBuildConcurrently},
+		},
+		{
+			name: "move index to tablespace",
+			old: schema.Schema{
+				Tables:  []schema.Table{buildTable("")},
+				Indexes: []schema.Index{buildIndex("", "")},
+			},
+			new: schema.Schema{
+				Tables:  []schema.Table{buildTable("")},
+				Indexes: []schema.Index{buildIndex("fast_ssd", "")},
+			},
+			expectedDDL: []string{
+				"ALTER INDEX \"public\".\"foo_id_idx\" SET TABLESPACE \"fast_ssd\"",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardTablespaceChangedAcquiresLock, migrationHazardTablespaceUntracked},
+		},
+		{
+			name: "move index back to default tablespace",
+			old: schema.Schema{
+				Tables:  []schema.Table{buildTable("")},
+				Indexes: []schema.Index{buildIndex("fast_ssd", "")},
+			},
+			new: schema.Schema{
+				Tables:  []schema.Table{buildTable("")},
+				Indexes: []schema.Index{buildIndex("", "")},
+			},
+			expectedDDL: []string{
+				"ALTER INDEX \"public\".\"foo_id_idx\" SET TABLESPACE \"pg_default\"",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardTablespaceChangedAcquiresLock, migrationHazardTablespaceUntracked},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := generateMigrationStatements(tc.old, tc.new, &planOptions{})
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+}
+
+func TestAddTablespaceToCreateIndexStmt_PredicateMismatch(t *testing.T) {
+	_, err := addTablespaceToCreateIndexStmt("CREATE INDEX foo_id_idx ON public.foo USING btree (id) WHERE (id > 0)", schema.Index{
+		Predicate:  "(id > 1)",
+		Tablespace: "fast_ssd",
+	})
+	assert.Error(t, err)
+}
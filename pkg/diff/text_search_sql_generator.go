@@ -0,0 +1,286 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	migrationHazardTextSearchDictionaryRecreated = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "The template of the text search dictionary changed, so the dictionary must be dropped and re-created. " +
+			"This will fail if any text search configurations still use the dictionary.",
+	}
+	migrationHazardTextSearchDictionaryOptionsChanged = MigrationHazard{
+		Type: MigrationHazardTypeCorrectness,
+		Message: "Changing the options of a text search dictionary changes how text is normalized. Existing tsvector " +
+			"values, e.g., stored in columns or indexes, are not recomputed and might not match new queries.",
+	}
+	migrationHazardTextSearchConfigRecreated = MigrationHazard{
+		Type: MigrationHazardTypeHasUntrackableDependencies,
+		Message: "The parser of the text search configuration changed, so the configuration must be dropped and " +
+			"re-created. This will fail if any columns or indexes still use the configuration. Functions that " +
+			"reference the configuration are not tracked and might break.",
+	}
+	migrationHazardTextSearchConfigDeleted = MigrationHazard{
+		Type:    MigrationHazardTypeHasUntrackableDependencies,
+		Message: "Functions that reference the text search configuration are not tracked and might break.",
+	}
+	migrationHazardTextSearchConfigMappingChanged = MigrationHazard{
+		Type: MigrationHazardTypeCorrectness,
+		Message: "Changing the mappings of a text search configuration changes how text is parsed into tsvectors. " +
+			"Existing tsvector values, e.g., stored in columns or indexes, are not recomputed and might not match new " +
+			"queries.",
+	}
+)
+
+// textSearchDictionarySQLGenerator is a SQL generator for text search dictionaries. Dictionaries are added before and
+// dropped after the text search configurations that use them.
+type textSearchDictionarySQLGenerator struct{}
+
+func (t *textSearchDictionarySQLGenerator) Add(dictionary schema.TextSearchDictionary) ([]Statement, error) {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("CREATE TEXT SEARCH DICTIONARY %s (TEMPLATE = %s", dictionary.GetFQEscapedName(), dictionary.Template.GetFQEscapedName()))
+	if len(dictionary.Options) > 0 {
+		sb.WriteString(fmt.Sprintf(", %s", dictionary.Options))
+	}
+	sb.WriteString(")")
+	return []Statement{
+		{
+			DDL:         sb.String(),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (t *textSearchDictionarySQLGenerator) Delete(dictionary schema.TextSearchDictionary) ([]Statement, error) {
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("DROP TEXT SEARCH DICTIONARY %s", dictionary.GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}, nil
+}
+
+func (t *textSearchDictionarySQLGenerator) Alter(diff textSearchDictionaryDiff) ([]Statement, error) {
+	if diff.old.Template != diff.new.Template {
+		// The template of a dictionary cannot be altered. Similar to domains, the dictionary must be re-created in the
+		// alter statement, since the normal delete -> add ordering would drop the dictionary after all other objects
+		// are migrated.
+		deletes, err := t.Delete(diff.old)
+		if err != nil {
+			return nil, fmt.Errorf("generating delete statements: %w", err)
+		}
+		adds, err := t.Add(diff.new)
+		if err != nil {
+			return nil, fmt.Errorf("generating add statements: %w", err)
+		}
+		stmts := append(deletes, adds...)
+		stmts[0].Hazards = append(stmts[0].Hazards, migrationHazardTextSearchDictionaryRecreated)
+		return stmts, nil
+	}
+
+	oldOptionsByKey := buildTextSearchDictionaryOptionsByKeyMap(diff.old.Options)
+	newOptionsByKey := buildTextSearchDictionaryOptionsByKeyMap(diff.new.Options)
+	var optionDefs []string
+	for _, option := range splitTextSearchDictionaryOptions(diff.new.Options) {
+		key := getTextSearchDictionaryOptionKey(option)
+		if oldOptionsByKey[key] != option {
+			optionDefs = append(optionDefs, option)
+		}
+	}
+	// An option without a value resets it to the template's default
+	for _, option := range splitTextSearchDictionaryOptions(diff.old.Options) {
+		key := getTextSearchDictionaryOptionKey(option)
+		if _, ok := newOptionsByKey[key]; !ok {
+			optionDefs = append(optionDefs, key)
+		}
+	}
+	if len(optionDefs) == 0 {
+		return nil, nil
+	}
+
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("ALTER TEXT SEARCH DICTIONARY %s (%s)", diff.new.GetFQEscapedName(), strings.Join(optionDefs, ", ")),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardTextSearchDictionaryOptionsChanged},
+		},
+	}, nil
+}
+
+// splitTextSearchDictionaryOptions splits the options of a dictionary, e.g.,
+// "language = 'english', stopwords = 'english'", into the individual option definitions. Commas within quoted values
+// are not treated as separators.
+func splitTextSearchDictionaryOptions(options string) []string {
+	var defs []string
+	inQuotes := false
+	start := 0
+	for i, c := range options {
+		switch {
+		case c == '\'':
+			inQuotes = !inQuotes
+		case c == ',' && !inQuotes:
+			defs = append(defs, strings.TrimSpace(options[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(options[start:]); len(last) > 0 {
+		defs = append(defs, last)
+	}
+	return defs
+}
+
+func getTextSearchDictionaryOptionKey(option string) string {
+	key, _, _ := strings.Cut(option, "=")
+	return strings.TrimSpace(key)
+}
+
+func buildTextSearchDictionaryOptionsByKeyMap(options string) map[string]string {
+	optionsByKey := make(map[string]string)
+	for _, option := range splitTextSearchDictionaryOptions(options) {
+		optionsByKey[getTextSearchDictionaryOptionKey(option)] = option
+	}
+	return optionsByKey
+}
+
+// textSearchConfigSQLGenerator is a SQL generator for text search configurations. Configurations are added before and
+// dropped after all objects that might use them, e.g., columns, indexes, and functions.
+type textSearchConfigSQLGenerator struct{}
+
+func (t *textSearchConfigSQLGenerator) Add(config schema.TextSearchConfig) ([]Statement, error) {
+	stmts := []Statement{
+		{
+			DDL:         fmt.Sprintf("CREATE TEXT SEARCH CONFIGURATION %s (PARSER = %s)", config.GetFQEscapedName(), config.Parser.GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		},
+	}
+	// A configuration created with only a parser has no mappings, so all of its mappings are added
+	for _, group := range groupTextSearchConfigMappingsByDictionaries(config.Mappings) {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s ADD MAPPING FOR %s WITH %s", alterTextSearchConfigPrefix(config), strings.Join(group.tokenTypes, ", "), buildTextSearchDictionaryList(group.dictionaries)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+		})
+	}
+	return stmts, nil
+}
+
+func (t *textSearchConfigSQLGenerator) Delete(config schema.TextSearchConfig) ([]Statement, error) {
+	return []Statement{
+		{
+			DDL:         fmt.Sprintf("DROP TEXT SEARCH CONFIGURATION %s", config.GetFQEscapedName()),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardTextSearchConfigDeleted},
+		},
+	}, nil
+}
+
+func (t *textSearchConfigSQLGenerator) Alter(diff textSearchConfigDiff) ([]Statement, error) {
+	if diff.old.Parser != diff.new.Parser {
+		// The parser of a configuration cannot be altered, so the configuration must be re-created. Similar to domains,
+		// this must be done in the alter statement, since the normal delete -> add ordering would drop the
+		// configuration after all other objects are migrated.
+		deletes, err := t.Delete(diff.old)
+		if err != nil {
+			return nil, fmt.Errorf("generating delete statements: %w", err)
+		}
+		adds, err := t.Add(diff.new)
+		if err != nil {
+			return nil, fmt.Errorf("generating add statements: %w", err)
+		}
+		stmts := append(deletes, adds...)
+		stmts[0].Hazards = append(stmts[0].Hazards, migrationHazardTextSearchConfigRecreated)
+		return stmts, nil
+	}
+
+	oldMappingsByTokenType := buildSchemaObjByNameMap(diff.old.Mappings)
+	newMappingsByTokenType := buildSchemaObjByNameMap(diff.new.Mappings)
+
+	var droppedTokenTypes []string
+	for _, mapping := range diff.old.Mappings {
+		if _, ok := newMappingsByTokenType[mapping.TokenType]; !ok {
+			droppedTokenTypes = append(droppedTokenTypes, mapping.TokenType)
+		}
+	}
+	var alteredMappings []schema.TextSearchConfigMapping
+	var addedMappings []schema.TextSearchConfigMapping
+	for _, mapping := range diff.new.Mappings {
+		oldMapping, ok := oldMappingsByTokenType[mapping.TokenType]
+		if !ok {
+			addedMappings = append(addedMappings, mapping)
+		} else if !cmp.Equal(oldMapping, mapping) {
+			alteredMappings = append(alteredMappings, mapping)
+		}
+	}
+
+	var stmts []Statement
+	if len(droppedTokenTypes) > 0 {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s DROP MAPPING FOR %s", alterTextSearchConfigPrefix(diff.new), strings.Join(droppedTokenTypes, ", ")),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardTextSearchConfigMappingChanged},
+		})
+	}
+	for _, group := range groupTextSearchConfigMappingsByDictionaries(alteredMappings) {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s ALTER MAPPING FOR %s WITH %s", alterTextSearchConfigPrefix(diff.new), strings.Join(group.tokenTypes, ", "), buildTextSearchDictionaryList(group.dictionaries)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardTextSearchConfigMappingChanged},
+		})
+	}
+	for _, group := range groupTextSearchConfigMappingsByDictionaries(addedMappings) {
+		stmts = append(stmts, Statement{
+			DDL:         fmt.Sprintf("%s ADD MAPPING FOR %s WITH %s", alterTextSearchConfigPrefix(diff.new), strings.Join(group.tokenTypes, ", "), buildTextSearchDictionaryList(group.dictionaries)),
+			Timeout:     statementTimeoutDefault,
+			LockTimeout: lockTimeoutDefault,
+			Hazards:     []MigrationHazard{migrationHazardTextSearchConfigMappingChanged},
+		})
+	}
+	return stmts, nil
+}
+
+type textSearchConfigMappingGroup struct {
+	tokenTypes   []string
+	dictionaries []schema.SchemaQualifiedName
+}
+
+// groupTextSearchConfigMappingsByDictionaries groups the token types that map to the same dictionaries, such that
+// they can be mapped in a single statement. The groups are ordered by their first token type.
+func groupTextSearchConfigMappingsByDictionaries(mappings []schema.TextSearchConfigMapping) []textSearchConfigMappingGroup {
+	var groups []textSearchConfigMappingGroup
+	groupIdxByDictionaries := make(map[string]int)
+	for _, mapping := range mappings {
+		dictionaryList := buildTextSearchDictionaryList(mapping.Dictionaries)
+		idx, ok := groupIdxByDictionaries[dictionaryList]
+		if !ok {
+			idx = len(groups)
+			groupIdxByDictionaries[dictionaryList] = idx
+			groups = append(groups, textSearchConfigMappingGroup{dictionaries: mapping.Dictionaries})
+		}
+		groups[idx].tokenTypes = append(groups[idx].tokenTypes, mapping.TokenType)
+	}
+	return groups
+}
+
+func buildTextSearchDictionaryList(dictionaries []schema.SchemaQualifiedName) string {
+	var dictionaryNames []string
+	for _, dictionary := range dictionaries {
+		dictionaryNames = append(dictionaryNames, dictionary.GetFQEscapedName())
+	}
+	return strings.Join(dictionaryNames, ", ")
+}
+
+func alterTextSearchConfigPrefix(config schema.TextSearchConfig) string {
+	return fmt.Sprintf("ALTER TEXT SEARCH CONFIGURATION %s", config.GetFQEscapedName())
+}
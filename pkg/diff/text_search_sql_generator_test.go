@@ -0,0 +1,183 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+var (
+	textSearchDictionaryName = schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"english_stem_nostop\""}
+	snowballTemplate         = schema.SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"snowball\""}
+	simpleDictionary         = schema.SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"simple\""}
+	textSearchConfigName     = schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"my_english\""}
+	defaultParser            = schema.SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"default\""}
+)
+
+func TestTextSearchDictionarySQLGenerator_Add(t *testing.T) {
+	stmts, err := (&textSearchDictionarySQLGenerator{}).Add(schema.TextSearchDictionary{
+		SchemaQualifiedName: textSearchDictionaryName,
+		Template:            snowballTemplate,
+		Options:             "language = 'english'",
+	})
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "CREATE TEXT SEARCH DICTIONARY \"public\".\"english_stem_nostop\" (TEMPLATE = \"pg_catalog\".\"snowball\", language = 'english')", stmts[0].DDL)
+}
+
+func TestTextSearchDictionarySQLGenerator_Alter(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		old             schema.TextSearchDictionary
+		new             schema.TextSearchDictionary
+		expectedDDL     []string
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name: "no change",
+			old:  schema.TextSearchDictionary{SchemaQualifiedName: textSearchDictionaryName, Template: snowballTemplate, Options: "language = 'english'"},
+			new:  schema.TextSearchDictionary{SchemaQualifiedName: textSearchDictionaryName, Template: snowballTemplate, Options: "language = 'english'"},
+		},
+		{
+			name: "add, change, and remove options",
+			old: schema.TextSearchDictionary{
+				SchemaQualifiedName: textSearchDictionaryName,
+				Template:            snowballTemplate,
+				Options:             "language = 'english', stopwords = 'english'",
+			},
+			new: schema.TextSearchDictionary{
+				SchemaQualifiedName: textSearchDictionaryName,
+				Template:            snowballTemplate,
+				Options:             "language = 'russian', accept = 'a,b'",
+			},
+			expectedDDL: []string{
+				"ALTER TEXT SEARCH DICTIONARY \"public\".\"english_stem_nostop\" (language = 'russian', accept = 'a,b', stopwords)",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardTextSearchDictionaryOptionsChanged},
+		},
+		{
+			name: "change template",
+			old:  schema.TextSearchDictionary{SchemaQualifiedName: textSearchDictionaryName, Template: snowballTemplate, Options: "language = 'english'"},
+			new: schema.TextSearchDictionary{
+				SchemaQualifiedName: textSearchDictionaryName,
+				Template:            schema.SchemaQualifiedName{SchemaName: "pg_catalog", EscapedName: "\"simple\""},
+			},
+			expectedDDL: []string{
+				"DROP TEXT SEARCH DICTIONARY \"public\".\"english_stem_nostop\"",
+				"CREATE TEXT SEARCH DICTIONARY \"public\".\"english_stem_nostop\" (TEMPLATE = \"pg_catalog\".\"simple\")",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardTextSearchDictionaryRecreated},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := (&textSearchDictionarySQLGenerator{}).Alter(textSearchDictionaryDiff{oldAndNew: oldAndNew[schema.TextSearchDictionary]{
+				old: tc.old,
+				new: tc.new,
+			}})
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+}
+
+func TestTextSearchConfigSQLGenerator_Add(t *testing.T) {
+	stmts, err := (&textSearchConfigSQLGenerator{}).Add(schema.TextSearchConfig{
+		SchemaQualifiedName: textSearchConfigName,
+		Parser:              defaultParser,
+		Mappings: []schema.TextSearchConfigMapping{
+			{TokenType: "asciiword", Dictionaries: []schema.SchemaQualifiedName{textSearchDictionaryName, simpleDictionary}},
+			{TokenType: "word", Dictionaries: []schema.SchemaQualifiedName{simpleDictionary}},
+			{TokenType: "asciihword", Dictionaries: []schema.SchemaQualifiedName{textSearchDictionaryName, simpleDictionary}},
+		},
+	})
+	require.NoError(t, err)
+	var ddl []string
+	for _, stmt := range stmts {
+		ddl = append(ddl, stmt.DDL)
+	}
+	assert.Equal(t, []string{
+		"CREATE TEXT SEARCH CONFIGURATION \"public\".\"my_english\" (PARSER = \"pg_catalog\".\"default\")",
+		"ALTER TEXT SEARCH CONFIGURATION \"public\".\"my_english\" ADD MAPPING FOR asciiword, asciihword WITH \"public\".\"english_stem_nostop\", \"pg_catalog\".\"simple\"",
+		"ALTER TEXT SEARCH CONFIGURATION \"public\".\"my_english\" ADD MAPPING FOR word WITH \"pg_catalog\".\"simple\"",
+	}, ddl)
+}
+
+func TestTextSearchConfigSQLGenerator_Alter(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		old             schema.TextSearchConfig
+		new             schema.TextSearchConfig
+		expectedDDL     []string
+		expectedHazards []MigrationHazard
+	}{
+		{
+			name: "drop, alter, and add mappings",
+			old: schema.TextSearchConfig{
+				SchemaQualifiedName: textSearchConfigName,
+				Parser:              defaultParser,
+				Mappings: []schema.TextSearchConfigMapping{
+					{TokenType: "asciiword", Dictionaries: []schema.SchemaQualifiedName{simpleDictionary}},
+					{TokenType: "email", Dictionaries: []schema.SchemaQualifiedName{simpleDictionary}},
+				},
+			},
+			new: schema.TextSearchConfig{
+				SchemaQualifiedName: textSearchConfigName,
+				Parser:              defaultParser,
+				Mappings: []schema.TextSearchConfigMapping{
+					{TokenType: "asciiword", Dictionaries: []schema.SchemaQualifiedName{textSearchDictionaryName, simpleDictionary}},
+					{TokenType: "word", Dictionaries: []schema.SchemaQualifiedName{simpleDictionary}},
+				},
+			},
+			expectedDDL: []string{
+				"ALTER TEXT SEARCH CONFIGURATION \"public\".\"my_english\" DROP MAPPING FOR email",
+				"ALTER TEXT SEARCH CONFIGURATION \"public\".\"my_english\" ALTER MAPPING FOR asciiword WITH \"public\".\"english_stem_nostop\", \"pg_catalog\".\"simple\"",
+				"ALTER TEXT SEARCH CONFIGURATION \"public\".\"my_english\" ADD MAPPING FOR word WITH \"pg_catalog\".\"simple\"",
+			},
+			expectedHazards: []MigrationHazard{
+				migrationHazardTextSearchConfigMappingChanged,
+				migrationHazardTextSearchConfigMappingChanged,
+				migrationHazardTextSearchConfigMappingChanged,
+			},
+		},
+		{
+			name: "change parser",
+			old:  schema.TextSearchConfig{SchemaQualifiedName: textSearchConfigName, Parser: defaultParser},
+			new: schema.TextSearchConfig{
+				SchemaQualifiedName: textSearchConfigName,
+				Parser:              schema.SchemaQualifiedName{SchemaName: "public", EscapedName: "\"my_parser\""},
+			},
+			expectedDDL: []string{
+				"DROP TEXT SEARCH CONFIGURATION \"public\".\"my_english\"",
+				"CREATE TEXT SEARCH CONFIGURATION \"public\".\"my_english\" (PARSER = \"public\".\"my_parser\")",
+			},
+			expectedHazards: []MigrationHazard{migrationHazardTextSearchConfigDeleted, migrationHazardTextSearchConfigRecreated},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmts, err := (&textSearchConfigSQLGenerator{}).Alter(textSearchConfigDiff{oldAndNew: oldAndNew[schema.TextSearchConfig]{
+				old: tc.old,
+				new: tc.new,
+			}})
+			require.NoError(t, err)
+
+			var ddl []string
+			var hazards []MigrationHazard
+			for _, stmt := range stmts {
+				ddl = append(ddl, stmt.DDL)
+				hazards = append(hazards, stmt.Hazards...)
+			}
+			assert.Equal(t, tc.expectedDDL, ddl)
+			assert.Equal(t, tc.expectedHazards, hazards)
+		})
+	}
+}
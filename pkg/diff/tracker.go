@@ -0,0 +1,169 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stripe/pg-schema-diff/internal/schema"
+	"github.com/stripe/pg-schema-diff/pkg/sqldb"
+)
+
+const (
+	defaultTrackingTableSchema = "public"
+	defaultTrackingTableName   = "schema_migrations"
+
+	trackingTableVersionFormat = "20060102150405"
+)
+
+type (
+	// MigrationRecord is a migration recorded in the tracking table
+	MigrationRecord struct {
+		// Version identifies the migration. It defaults to the UTC time the migration was recorded at, e.g.,
+		// "20240102150405"
+		Version   string
+		AppliedAt time.Time
+		Duration  time.Duration
+		// StatementsCount is the number of statements in the migration's plan
+		StatementsCount int
+		// Fingerprint is the fingerprint of the schema after the migration, e.g., from schema.GetSchemaFingerprint
+		Fingerprint string
+		AppliedBy   string
+	}
+
+	trackerOptions struct {
+		tableName schema.SchemaQualifiedName
+	}
+
+	TrackerOpt func(*trackerOptions)
+
+	recordMigrationOptions struct {
+		version   string
+		duration  time.Duration
+		queryable sqldb.Queryable
+	}
+
+	RecordMigrationOpt func(*recordMigrationOptions)
+)
+
+// WithTrackingTable configures the tracker to record migrations in schemaName.tableName rather than
+// public.schema_migrations
+func WithTrackingTable(schemaName, tableName string) TrackerOpt {
+	return func(opts *trackerOptions) {
+		opts.tableName = schema.SchemaQualifiedName{SchemaName: schemaName, EscapedName: schema.EscapeIdentifier(tableName)}
+	}
+}
+
+// WithMigrationVersion sets the version of the recorded migration
+func WithMigrationVersion(version string) RecordMigrationOpt {
+	return func(opts *recordMigrationOptions) {
+		opts.version = version
+	}
+}
+
+// WithMigrationDuration sets how long the recorded migration took to execute
+func WithMigrationDuration(duration time.Duration) RecordMigrationOpt {
+	return func(opts *recordMigrationOptions) {
+		opts.duration = duration
+	}
+}
+
+// WithRecordingQueryable records the migration via the queryable rather than the tracker's queryable, e.g., the
+// transaction the plan's last statement is executed in, such that the migration is only recorded if the last statement
+// is committed.
+func WithRecordingQueryable(queryable sqldb.Queryable) RecordMigrationOpt {
+	return func(opts *recordMigrationOptions) {
+		opts.queryable = queryable
+	}
+}
+
+// Tracker records the migrations applied to a database in a tracking table. The tracking table is marked with a
+// comment that excludes it from the schema, so it never appears in schema diffs. It is the source of truth for the
+// schema's fingerprint: schema.FingerprintTable reads the fingerprint recorded with the latest migration.
+type Tracker struct {
+	queryable sqldb.Queryable
+	tableName schema.SchemaQualifiedName
+}
+
+// NewTracker creates a Tracker. By default, migrations are recorded in public.schema_migrations.
+func NewTracker(queryable sqldb.Queryable, opts ...TrackerOpt) *Tracker {
+	options := trackerOptions{
+		tableName: schema.SchemaQualifiedName{
+			SchemaName:  defaultTrackingTableSchema,
+			EscapedName: schema.EscapeIdentifier(defaultTrackingTableName),
+		},
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &Tracker{
+		queryable: queryable,
+		tableName: options.tableName,
+	}
+}
+
+// EnsureTable creates the tracking table if it does not exist. It is idempotent.
+func (t *Tracker) EnsureTable(ctx context.Context) error {
+	if _, err := t.queryable.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version TEXT NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	duration_ms BIGINT NOT NULL,
+	statements_count INT NOT NULL,
+	fingerprint TEXT NOT NULL,
+	applied_by TEXT NOT NULL
+)`, t.tableName.GetFQEscapedName())); err != nil {
+		return fmt.Errorf("creating tracking table: %w", err)
+	}
+	if _, err := t.queryable.ExecContext(ctx, fmt.Sprintf("COMMENT ON TABLE %s IS '%s'", t.tableName.GetFQEscapedName(), schema.TrackingTableComment)); err != nil {
+		return fmt.Errorf("marking tracking table: %w", err)
+	}
+	return nil
+}
+
+// RecordMigration records that the plan was applied. The tracking table must already exist, i.e., EnsureTable must be
+// called before the migration. To record the migration transactionally with the plan's last statement, pass the
+// statement's transaction via WithRecordingQueryable.
+func (t *Tracker) RecordMigration(ctx context.Context, plan Plan, fingerprint, appliedBy string, opts ...RecordMigrationOpt) error {
+	options := recordMigrationOptions{
+		version:   time.Now().UTC().Format(trackingTableVersionFormat),
+		queryable: t.queryable,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if _, err := options.queryable.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO %s (version, duration_ms, statements_count, fingerprint, applied_by) VALUES ($1, $2, $3, $4, $5)", t.tableName.GetFQEscapedName()),
+		options.version, options.duration.Milliseconds(), len(plan.Statements), fingerprint, appliedBy,
+	); err != nil {
+		return fmt.Errorf("inserting migration record: %w", err)
+	}
+	return nil
+}
+
+// GetHistory gets the recorded migrations, from oldest to newest
+func (t *Tracker) GetHistory(ctx context.Context) ([]MigrationRecord, error) {
+	rows, err := t.queryable.QueryContext(ctx, fmt.Sprintf(
+		"SELECT version, applied_at, duration_ms, statements_count, fingerprint, applied_by FROM %s ORDER BY applied_at, version",
+		t.tableName.GetFQEscapedName(),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("querying migration history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []MigrationRecord
+	for rows.Next() {
+		var record MigrationRecord
+		var durationMs int64
+		if err := rows.Scan(&record.Version, &record.AppliedAt, &durationMs, &record.StatementsCount, &record.Fingerprint, &record.AppliedBy); err != nil {
+			return nil, fmt.Errorf("scanning migration record: %w", err)
+		}
+		record.Duration = time.Duration(durationMs) * time.Millisecond
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating migration history: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,68 @@
+package diff
+
+import (
+	"context"
+	"time"
+)
+
+func (suite *planGeneratorTestSuite) TestTracker() {
+	suite.mustApplyDDLToTestDb([]string{`CREATE TABLE foobar(id INT PRIMARY KEY);`})
+	connPool := suite.mustGetTestDBPool()
+	defer connPool.Close()
+
+	tempDbFactory := suite.mustBuildTempDbFactory(context.Background())
+	defer tempDbFactory.Close()
+
+	tracker := NewTracker(connPool, WithTrackingTable("public", "migration_history"))
+	suite.Require().NoError(tracker.EnsureTable(context.Background()))
+	// EnsureTable is idempotent
+	suite.Require().NoError(tracker.EnsureTable(context.Background()))
+
+	history, err := tracker.GetHistory(context.Background())
+	suite.Require().NoError(err)
+	suite.Empty(history)
+
+	// The tracking table should not appear in the diff
+	plan, err := Generate(context.Background(), DBSchemaSource(connPool), DDLSchemaSource([]string{
+		`CREATE TABLE foobar(id INT PRIMARY KEY, val TEXT);`,
+	}), WithTempDbFactory(tempDbFactory))
+	suite.Require().NoError(err)
+	for _, stmt := range plan.Statements {
+		suite.NotContains(stmt.DDL, "migration_history")
+	}
+
+	// Record the migration transactionally with the plan's last statement
+	tx, err := connPool.BeginTx(context.Background(), nil)
+	suite.Require().NoError(err)
+	defer tx.Rollback()
+	for _, stmt := range plan.Statements {
+		_, err := tx.ExecContext(context.Background(), stmt.ToSQL())
+		suite.Require().NoError(err)
+	}
+	suite.Require().NoError(tracker.RecordMigration(context.Background(), plan, "some-fingerprint", "some-user",
+		WithMigrationVersion("v1"),
+		WithMigrationDuration(1500*time.Millisecond),
+		WithRecordingQueryable(tx),
+	))
+
+	// The migration is not visible until the transaction is committed
+	history, err = tracker.GetHistory(context.Background())
+	suite.Require().NoError(err)
+	suite.Empty(history)
+	suite.Require().NoError(tx.Commit())
+
+	suite.Require().NoError(tracker.RecordMigration(context.Background(), Plan{}, "other-fingerprint", "other-user"))
+
+	history, err = tracker.GetHistory(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Len(history, 2)
+	suite.Equal("v1", history[0].Version)
+	suite.Equal(1500*time.Millisecond, history[0].Duration)
+	suite.Equal(len(plan.Statements), history[0].StatementsCount)
+	suite.Equal("some-fingerprint", history[0].Fingerprint)
+	suite.Equal("some-user", history[0].AppliedBy)
+	suite.False(history[0].AppliedAt.IsZero())
+	suite.Len(history[1].Version, len(trackingTableVersionFormat))
+	suite.Equal(0, history[1].StatementsCount)
+	suite.Equal("other-user", history[1].AppliedBy)
+}
@@ -1,24 +1,31 @@
 package diff
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/google/go-cmp/cmp"
+	pg_query "github.com/pganalyze/pg_query_go/v5"
 	"github.com/stripe/pg-schema-diff/internal/schema"
 )
 
 type viewSQLVertexGenerator struct {
 	tablesInNewSchemaByName map[string]schema.Table
 	viewsInNewSchemaByName  map[string]schema.View
+	// existingRoles is the set of roles known to exist in the target database. See buildExistingRoles.
+	existingRoles map[string]bool
 }
 
 func (v *viewSQLVertexGenerator) Add(view schema.View) ([]Statement, error) {
 	stmt := fmt.Sprintf("CREATE VIEW %s AS %s", view.GetFQEscapedName(), view.Definition)
-	return []Statement{{
+	stmts := []Statement{{
 		DDL:         stmt,
 		Timeout:     statementTimeoutDefault,
 		LockTimeout: lockTimeoutDefault,
-	}}, nil
+	}}
+	stmts = append(stmts, buildCommentStatements("VIEW", view.GetFQEscapedName(), nil, view.Comment)...)
+	return append(stmts, buildAlterOwnerStatements("VIEW", view.GetFQEscapedName(), "", view.Owner, v.existingRoles)...), nil
 }
 
 func (v *viewSQLVertexGenerator) Delete(view schema.View) ([]Statement, error) {
@@ -37,6 +44,15 @@ func (v *viewSQLVertexGenerator) Alter(diff viewDiff) ([]Statement, error) {
 	if cmp.Equal(diff.old, diff.new) {
 		return nil, nil
 	}
+
+	// The comment and owner of a view can be changed without re-creating it
+	oldWithNewMetadata := diff.old
+	oldWithNewMetadata.Comment = diff.new.Comment
+	oldWithNewMetadata.Owner = diff.new.Owner
+	if cmp.Equal(oldWithNewMetadata, diff.new) {
+		stmts := buildCommentStatements("VIEW", diff.new.GetFQEscapedName(), diff.old.Comment, diff.new.Comment)
+		return append(stmts, buildAlterOwnerStatements("VIEW", diff.new.GetFQEscapedName(), diff.old.Owner, diff.new.Owner, v.existingRoles)...), nil
+	}
 	
 	// Views cannot be altered directly, they must be dropped and recreated
 	var stmts []Statement
@@ -76,6 +92,13 @@ func (v *viewSQLVertexGenerator) GetAddAlterDependencies(newView, oldView schema
 		))
 	}
 	
+	// A view depends on all foreign tables it references
+	for _, depForeignTable := range newView.DependsOnForeignTables {
+		deps = append(deps, mustRun(v.GetSQLVertexId(newView, diffTypeAddAlter)).after(
+			buildForeignTableVertexId(depForeignTable, diffTypeAddAlter),
+		))
+	}
+	
 	// A view depends on all other views it references
 	for _, depView := range newView.DependsOnViews {
 		// Skip self-references (shouldn't happen but be safe)
@@ -96,6 +119,14 @@ func (v *viewSQLVertexGenerator) GetAddAlterDependencies(newView, oldView schema
 			}
 		}
 		
+		for _, depForeignTable := range oldView.DependsOnForeignTables {
+			if !contains(newView.DependsOnForeignTables, depForeignTable) {
+				deps = append(deps, mustRun(v.GetSQLVertexId(newView, diffTypeAddAlter)).before(
+					buildForeignTableVertexId(depForeignTable, diffTypeDelete),
+				))
+			}
+		}
+		
 		for _, depView := range oldView.DependsOnViews {
 			if !contains(newView.DependsOnViews, depView) {
 				deps = append(deps, mustRun(v.GetSQLVertexId(newView, diffTypeAddAlter)).before(
@@ -118,6 +149,15 @@ func (v *viewSQLVertexGenerator) GetDeleteDependencies(view schema.View) ([]depe
 		))
 	}
 	
+	// When deleting a view, it must be deleted before any foreign tables it depends on are dropped or altered, since
+	// altering them might involve dropping columns
+	for _, depForeignTable := range view.DependsOnForeignTables {
+		deps = append(deps,
+			mustRun(v.GetSQLVertexId(view, diffTypeDelete)).before(buildForeignTableVertexId(depForeignTable, diffTypeDelete)),
+			mustRun(v.GetSQLVertexId(view, diffTypeDelete)).before(buildForeignTableVertexId(depForeignTable, diffTypeAddAlter)),
+		)
+	}
+	
 	// When deleting a view, it must be deleted before any views it depends on
 	for _, depView := range view.DependsOnViews {
 		// Skip self-references (shouldn't happen but be safe)
@@ -138,4 +178,269 @@ func contains(names []schema.SchemaQualifiedName, name schema.SchemaQualifiedNam
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// TableColumnChange describes a column of a table being renamed from OldColumnName to NewColumnName.
+type TableColumnChange struct {
+	Table         schema.SchemaQualifiedName
+	OldColumnName string
+	NewColumnName string
+}
+
+// viewRelation is a table referenced in the FROM clause of a SELECT in a view definition
+type viewRelation struct {
+	schemaName string
+	name       string
+	alias      string
+}
+
+// viewDefinitionEdit replaces the text between start and end of a view definition
+type viewDefinitionEdit struct {
+	start, end int
+	text       string
+}
+
+// repairViewsAfterColumnRename returns the views that reference the renamed column with their definitions updated the
+// way Postgres updates them when the column is renamed: references to the column are renamed, and output columns named
+// after the column keep their name via an alias, e.g., `SELECT val FROM foobar` becomes
+// `SELECT new_val AS val FROM foobar`. Views that do not depend on the table, or whose definitions do not reference the
+// column, are not returned.
+//
+// References are resolved using the parsed definition: a reference is only renamed if it is qualified by the table's
+// name or alias, or if it is unqualified and the table is the only relation in the FROM clause. Columns with the same
+// name in other tables, aliases, and string literals are left untouched. Views whose definitions cannot be parsed are
+// not repaired.
+func repairViewsAfterColumnRename(views []schema.View, tableChange TableColumnChange) []schema.View {
+	var repairedViews []schema.View
+	for _, view := range views {
+		if !contains(view.DependsOnTables, tableChange.Table) {
+			continue
+		}
+		newDefinition, err := renameColumnInViewDefinition(view.Definition, tableChange)
+		if err != nil || newDefinition == view.Definition {
+			continue
+		}
+		view.Definition = newDefinition
+		repairedViews = append(repairedViews, view)
+	}
+	return repairedViews
+}
+
+func renameColumnInViewDefinition(definition string, tableChange TableColumnChange) (string, error) {
+	parseTreeJSON, err := pg_query.ParseToJSON(definition)
+	if err != nil {
+		return "", fmt.Errorf("parsing view definition: %w", err)
+	}
+	var parseTree struct {
+		Stmts []struct {
+			Stmt map[string]any `json:"stmt"`
+		} `json:"stmts"`
+	}
+	if err := json.Unmarshal([]byte(parseTreeJSON), &parseTree); err != nil {
+		return "", fmt.Errorf("unmarshalling parse tree: %w", err)
+	}
+	if len(parseTree.Stmts) != 1 {
+		return "", fmt.Errorf("expected a single statement but found %d", len(parseTree.Stmts))
+	}
+
+	// The output columns of the view are the targets of the top-level SELECT or, for set operations, e.g., UNION, of
+	// its left-most SELECT
+	outputTargets := make(map[any]bool)
+	selectStmt, _ := parseTree.Stmts[0].Stmt["SelectStmt"].(map[string]any)
+	for selectStmt != nil {
+		if larg, ok := selectStmt["larg"].(map[string]any); ok {
+			selectStmt = larg
+			continue
+		}
+		for _, target := range asSlice(selectStmt["targetList"]) {
+			if resTarget, ok := asMap(target)["ResTarget"].(map[string]any); ok {
+				outputTargets[getJSONLocation(resTarget)] = resTarget["name"] == nil
+			}
+		}
+		break
+	}
+
+	r := viewColumnRenamer{
+		definition:    definition,
+		tableChange:   tableChange,
+		tableName:     unescapeIdentifier(tableChange.Table.EscapedName),
+		outputTargets: outputTargets,
+	}
+	r.walk(parseTree.Stmts[0].Stmt, nil, nil)
+	if len(r.edits) == 0 {
+		return definition, nil
+	}
+
+	sort.Slice(r.edits, func(i, j int) bool {
+		return r.edits[i].start > r.edits[j].start
+	})
+	for _, edit := range r.edits {
+		definition = definition[:edit.start] + edit.text + definition[edit.end:]
+	}
+	return definition, nil
+}
+
+type viewColumnRenamer struct {
+	definition  string
+	tableChange TableColumnChange
+	tableName   string
+	// outputTargets are the locations of the targets that define the view's output columns, mapped to whether the
+	// target has no alias
+	outputTargets map[any]bool
+	edits         []viewDefinitionEdit
+}
+
+// walk walks the parse tree. innerRelations are the relations of the innermost SELECT, which unqualified column
+// references resolve to, and outerRelations are the relations of the enclosing SELECTs, which qualified column
+// references might reference.
+func (r *viewColumnRenamer) walk(node any, innerRelations, outerRelations []viewRelation) {
+	switch n := node.(type) {
+	case []any:
+		for _, child := range n {
+			r.walk(child, innerRelations, outerRelations)
+		}
+	case map[string]any:
+		if selectStmt, ok := n["SelectStmt"].(map[string]any); ok {
+			outerRelations = append(append([]viewRelation(nil), outerRelations...), innerRelations...)
+			innerRelations = nil
+			for _, from := range asSlice(selectStmt["fromClause"]) {
+				innerRelations = append(innerRelations, getViewRelations(from)...)
+			}
+			r.walk(selectStmt, innerRelations, outerRelations)
+			return
+		}
+		if resTarget, ok := n["ResTarget"].(map[string]any); ok {
+			if columnRef, ok := asMap(resTarget["val"])["ColumnRef"].(map[string]any); ok && r.outputTargets[getJSONLocation(resTarget)] {
+				if r.renameColumnRef(columnRef, innerRelations, outerRelations) {
+					// Keep the name of the output column
+					r.edits[len(r.edits)-1].text += " AS " + simplestIdentifierForm(schema.EscapeIdentifier(r.tableChange.OldColumnName))
+				}
+				return
+			}
+		}
+		if columnRef, ok := n["ColumnRef"].(map[string]any); ok {
+			r.renameColumnRef(columnRef, innerRelations, outerRelations)
+			return
+		}
+		for _, child := range n {
+			r.walk(child, innerRelations, outerRelations)
+		}
+	}
+}
+
+// renameColumnRef adds an edit renaming the column reference if it references the renamed column
+func (r *viewColumnRenamer) renameColumnRef(columnRef map[string]any, innerRelations, outerRelations []viewRelation) bool {
+	var fields []string
+	for _, field := range asSlice(columnRef["fields"]) {
+		str, ok := asMap(field)["String"].(map[string]any)
+		if !ok {
+			// e.g., `foobar.*`
+			return false
+		}
+		sval, _ := str["sval"].(string)
+		fields = append(fields, sval)
+	}
+	if len(fields) == 0 || fields[len(fields)-1] != r.tableChange.OldColumnName {
+		return false
+	}
+
+	var isReference bool
+	switch len(fields) {
+	case 1:
+		isReference = len(innerRelations) == 1 && r.isTable(innerRelations[0])
+	case 2:
+		for _, rel := range append(append([]viewRelation(nil), innerRelations...), outerRelations...) {
+			if (rel.alias == fields[0] || (rel.alias == "" && rel.name == fields[0])) && r.isTable(rel) {
+				isReference = true
+			}
+		}
+	case 3:
+		isReference = fields[0] == r.tableChange.Table.SchemaName && fields[1] == r.tableName
+	}
+	if !isReference {
+		return false
+	}
+
+	location, ok := getJSONLocation(columnRef).(float64)
+	if !ok {
+		return false
+	}
+	spans := scanIdentifierChain(r.definition, int(location))
+	if len(spans) != len(fields) {
+		return false
+	}
+	lastSpan := spans[len(spans)-1]
+	r.edits = append(r.edits, viewDefinitionEdit{
+		start: lastSpan[0],
+		end:   lastSpan[1],
+		text:  simplestIdentifierForm(schema.EscapeIdentifier(r.tableChange.NewColumnName)),
+	})
+	return true
+}
+
+func (r *viewColumnRenamer) isTable(rel viewRelation) bool {
+	return rel.name == r.tableName && (rel.schemaName == "" || rel.schemaName == r.tableChange.Table.SchemaName)
+}
+
+// getViewRelations returns the tables referenced by an item of a FROM clause, including the tables of joins
+func getViewRelations(from any) []viewRelation {
+	fromMap := asMap(from)
+	if rangeVar, ok := fromMap["RangeVar"].(map[string]any); ok {
+		rel := viewRelation{}
+		rel.schemaName, _ = rangeVar["schemaname"].(string)
+		rel.name, _ = rangeVar["relname"].(string)
+		rel.alias, _ = asMap(rangeVar["alias"])["aliasname"].(string)
+		return []viewRelation{rel}
+	}
+	if joinExpr, ok := fromMap["JoinExpr"].(map[string]any); ok {
+		return append(getViewRelations(joinExpr["larg"]), getViewRelations(joinExpr["rarg"])...)
+	}
+	return nil
+}
+
+// scanIdentifierChain returns the spans of the identifiers of a dotted chain of identifiers, e.g., foobar."val",
+// starting at the index
+func scanIdentifierChain(sql string, start int) [][2]int {
+	var spans [][2]int
+	i := start
+	for i < len(sql) {
+		end := i
+		switch {
+		case sql[i] == '"':
+			end = findClosingQuote(sql, i)
+		case isIdentifierStart(sql[i]):
+			for end = i + 1; end < len(sql) && isIdentifierPart(sql[end]); end++ {
+			}
+		default:
+			return spans
+		}
+		spans = append(spans, [2]int{i, end})
+		if end >= len(sql) || sql[end] != '.' {
+			return spans
+		}
+		i = end + 1
+	}
+	return spans
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func getJSONLocation(node map[string]any) any {
+	return node["location"]
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9') || c == '$'
+}
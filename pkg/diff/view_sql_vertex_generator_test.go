@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stripe/pg-schema-diff/internal/schema"
+)
+
+func TestRepairViewsAfterColumnRename(t *testing.T) {
+	foobar := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar"`}
+	fizzbuzz := schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"fizzbuzz"`}
+
+	for _, tc := range []struct {
+		name     string
+		views    []schema.View
+		expected []schema.View
+	}{
+		{
+			name: "Unquoted and qualified references",
+			views: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_view"`},
+				Definition:          " SELECT foobar.id,\n    val\n   FROM foobar\n  WHERE (foobar.val > 0);",
+				DependsOnTables:     []schema.SchemaQualifiedName{foobar},
+			}},
+			expected: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_view"`},
+				Definition:          " SELECT foobar.id,\n    new_val AS val\n   FROM foobar\n  WHERE (foobar.new_val > 0);",
+				DependsOnTables:     []schema.SchemaQualifiedName{foobar},
+			}},
+		},
+		{
+			name: "Quoted references, string literals and partial matches keep the output names",
+			views: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_view"`},
+				Definition:          ` SELECT foobar."val", foobar.val_2, 'val' AS label FROM foobar;`,
+				DependsOnTables:     []schema.SchemaQualifiedName{foobar},
+			}},
+			expected: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_view"`},
+				Definition:          ` SELECT foobar.new_val AS val, foobar.val_2, 'val' AS label FROM foobar;`,
+				DependsOnTables:     []schema.SchemaQualifiedName{foobar},
+			}},
+		},
+		{
+			name: "Aliases and columns of other tables",
+			views: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_view"`},
+				Definition:          " SELECT f.val AS foobar_val,\n    fizzbuzz.val\n   FROM (foobar f\n     JOIN fizzbuzz ON ((f.id = fizzbuzz.id)))\n  WHERE (f.val <> fizzbuzz.val);",
+				DependsOnTables:     []schema.SchemaQualifiedName{foobar, fizzbuzz},
+			}},
+			expected: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_view"`},
+				Definition:          " SELECT f.new_val AS foobar_val,\n    fizzbuzz.val\n   FROM (foobar f\n     JOIN fizzbuzz ON ((f.id = fizzbuzz.id)))\n  WHERE (f.new_val <> fizzbuzz.val);",
+				DependsOnTables:     []schema.SchemaQualifiedName{foobar, fizzbuzz},
+			}},
+		},
+		{
+			name: "Output columns named after the column are not renamed",
+			views: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_view"`},
+				Definition:          " SELECT (foobar.id + 1) AS val\n   FROM foobar\n  ORDER BY foobar.val;",
+				DependsOnTables:     []schema.SchemaQualifiedName{foobar},
+			}},
+			expected: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_view"`},
+				Definition:          " SELECT (foobar.id + 1) AS val\n   FROM foobar\n  ORDER BY foobar.new_val;",
+				DependsOnTables:     []schema.SchemaQualifiedName{foobar},
+			}},
+		},
+		{
+			name: "Views that do not depend on the table are not repaired",
+			views: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"fizzbuzz_view"`},
+				Definition:          ` SELECT fizzbuzz.val FROM fizzbuzz;`,
+				DependsOnTables:     []schema.SchemaQualifiedName{fizzbuzz},
+			}},
+			expected: nil,
+		},
+		{
+			name: "Views that do not reference the column are not repaired",
+			views: []schema.View{{
+				SchemaQualifiedName: schema.SchemaQualifiedName{SchemaName: "public", EscapedName: `"foobar_view"`},
+				Definition:          ` SELECT foobar.id FROM foobar;`,
+				DependsOnTables:     []schema.SchemaQualifiedName{foobar},
+			}},
+			expected: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, repairViewsAfterColumnRename(tc.views, TableColumnChange{
+				Table:         foobar,
+				OldColumnName: "val",
+				NewColumnName: "new_val",
+			}))
+		})
+	}
+}
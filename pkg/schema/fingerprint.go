@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	internalschema "github.com/stripe/pg-schema-diff/internal/schema"
+	"github.com/stripe/pg-schema-diff/pkg/sqldb"
+)
+
+// GetSchemaFingerprint gets the fingerprint of the target schema: a stable SHA-256 hash of the normalized schema.
+// Unlike GetSchemaHash, the fingerprint is stable across versions of pg-schema-diff's dependencies, so it can be
+// persisted, e.g., with each migration recorded by diff.Tracker, to detect schema drift without computing a full diff.
+func GetSchemaFingerprint(ctx context.Context, queryable sqldb.Queryable, opts ...GetSchemaOpt) (string, error) {
+	schema, err := internalschema.GetSchema(ctx, queryable, opts...)
+	if err != nil {
+		return "", fmt.Errorf("getting schema: %w", err)
+	}
+	fingerprint, err := internalschema.Fingerprint(schema)
+	if err != nil {
+		return "", fmt.Errorf("fingerprinting schema: %w", err)
+	}
+	return fingerprint, nil
+}
+
+// FingerprintTable reads the fingerprints recorded in a migration tracking table, i.e., the table diff.Tracker records
+// migrations in. Record the fingerprint of the schema with each migration via diff.Tracker's RecordMigration, e.g., from
+// GetSchemaFingerprint. At startup, compare the live schema against the latest recorded fingerprint via MatchesLatest: if
+// it matches, the database has not drifted since it was last migrated, and the diff can be skipped.
+//
+// FingerprintTable only reads, so it can be used by read-only roles and on hot-standby replicas.
+type FingerprintTable struct {
+	queryable   sqldb.Queryable
+	escapedName string
+}
+
+// NewFingerprintTable creates a FingerprintTable backed by the migration tracking table schemaName.tableName, e.g.,
+// public.schema_migrations
+func NewFingerprintTable(queryable sqldb.Queryable, schemaName, tableName string) *FingerprintTable {
+	return &FingerprintTable{
+		queryable: queryable,
+		escapedName: internalschema.SchemaQualifiedName{
+			SchemaName:  schemaName,
+			EscapedName: internalschema.EscapeIdentifier(tableName),
+		}.GetFQEscapedName(),
+	}
+}
+
+// LatestFingerprint gets the fingerprint recorded with the most recent migration. It returns false if no fingerprint has
+// been recorded, including if the tracking table does not exist or the most recent migration was recorded without a
+// fingerprint.
+func (f *FingerprintTable) LatestFingerprint(ctx context.Context) (string, bool, error) {
+	var exists bool
+	if err := f.queryable.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", f.escapedName).Scan(&exists); err != nil {
+		return "", false, fmt.Errorf("checking if tracking table exists: %w", err)
+	}
+	if !exists {
+		return "", false, nil
+	}
+	var fingerprint string
+	if err := f.queryable.QueryRowContext(ctx, fmt.Sprintf("SELECT fingerprint FROM %s ORDER BY applied_at DESC, version DESC LIMIT 1", f.escapedName)).Scan(&fingerprint); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("getting latest fingerprint: %w", err)
+	}
+	if len(fingerprint) == 0 {
+		return "", false, nil
+	}
+	return fingerprint, true, nil
+}
+
+// MatchesLatest returns true if the fingerprint of the live schema matches the most recently recorded fingerprint,
+// i.e., the schema has not drifted since it was last migrated. The opts must match the opts the fingerprint was recorded
+// with. It returns false if no fingerprint has been recorded.
+func (f *FingerprintTable) MatchesLatest(ctx context.Context, opts ...GetSchemaOpt) (bool, error) {
+	latestFingerprint, ok, err := f.LatestFingerprint(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, nil
+	}
+	fingerprint, err := GetSchemaFingerprint(ctx, f.queryable, opts...)
+	if err != nil {
+		return false, err
+	}
+	return fingerprint == latestFingerprint, nil
+}
@@ -3,6 +3,7 @@ package schema
 import (
 	"context"
 	"fmt"
+	"io"
 
 	internalschema "github.com/stripe/pg-schema-diff/internal/schema"
 	"github.com/stripe/pg-schema-diff/pkg/sqldb"
@@ -10,15 +11,35 @@ import (
 
 type GetSchemaOpt = internalschema.GetSchemaOpt
 
+// SchemaQualifiedName is the name of a schema object, e.g., a table, qualified by its schema. The EscapedName is quoted,
+// e.g., `"foobar"`. It is used to identify objects in the migration plan, e.g., diff.RenameCandidate.
+type SchemaQualifiedName = internalschema.SchemaQualifiedName
+
+// Schema is the schema of a database. It is exposed to extend the diff engine, e.g., via
+// diff.WithAdditionalVertexGenerators. Its API is subject to change.
+type Schema = internalschema.Schema
+
 var (
-	WithIncludeSchemas = internalschema.WithIncludeSchemas
-	WithExcludeSchemas = internalschema.WithExcludeSchemas
+	WithIncludeSchemas           = internalschema.WithIncludeSchemas
+	WithExcludeSchemas           = internalschema.WithExcludeSchemas
+	WithOnlySchemas              = internalschema.WithOnlySchemas
+	WithObjectOwners             = internalschema.WithObjectOwners
+	WithOwners                   = internalschema.WithOwners
+	WithPrivileges               = internalschema.WithPrivileges
+	WithCitusDistributionColumns = internalschema.WithCitusDistributionColumns
+	WithIncludeObjects           = internalschema.WithIncludeObjects
+	WithExcludeObjects           = internalschema.WithExcludeObjects
 )
 
+// ParseDump parses the output of `pg_dump --schema-only` into a Schema, such that a diff can be computed without a
+// connection to the dumped database, e.g., via diff.SchemaSchemaSource. Only a subset of objects is supported; an error
+// is returned if the dump contains any other statement. See the internal schema.ParseDump for the supported objects.
+func ParseDump(r io.Reader) (Schema, error) {
+	return internalschema.ParseDump(r)
+}
+
 // GetSchemaHash hash gets the hash of the target schema. It can be used to compare against the hash in the migration
 // plan to determine if the plan is still valid.
-//
-// We do not expose the Schema struct yet because it is subject to change, and we do not want folks depending on its API.
 func GetSchemaHash(ctx context.Context, queryable sqldb.Queryable, opts ...GetSchemaOpt) (string, error) {
 	schema, err := internalschema.GetSchema(ctx, queryable, opts...)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/stripe/pg-schema-diff/internal/pgengine"
 	internalschema "github.com/stripe/pg-schema-diff/internal/schema"
+	"github.com/stripe/pg-schema-diff/pkg/diff"
 	"github.com/stripe/pg-schema-diff/pkg/schema"
 )
 
@@ -104,6 +105,71 @@ func (suite *schemaTestSuite) TestGetPublicSchemaHash() {
 	suite.Equal(expectedHash, hash)
 }
 
+func (suite *schemaTestSuite) TestFingerprintTable() {
+	db, err := suite.pgEngine.CreateDatabase()
+	suite.Require().NoError(err)
+	defer db.DropDB()
+
+	connPool, err := sql.Open("pgx", db.GetDSN())
+	suite.Require().NoError(err)
+	defer connPool.Close()
+
+	_, err = connPool.ExecContext(context.Background(), `
+		CREATE SCHEMA migrations;
+		CREATE TABLE foo(id INT PRIMARY KEY);
+	`)
+	suite.Require().NoError(err)
+
+	opts := []schema.GetSchemaOpt{schema.WithExcludeSchemas("migrations")}
+	tracker := diff.NewTracker(connPool, diff.WithTrackingTable("migrations", "schema_migrations"))
+	fingerprintTable := schema.NewFingerprintTable(connPool, "migrations", "schema_migrations")
+
+	matches, err := fingerprintTable.MatchesLatest(context.Background(), opts...)
+	suite.Require().NoError(err)
+	suite.False(matches, "no fingerprint has been recorded")
+	// Reading the latest fingerprint does not create the tracking table
+	var tableExists bool
+	suite.Require().NoError(connPool.QueryRowContext(context.Background(), "SELECT to_regclass('migrations.schema_migrations') IS NOT NULL").Scan(&tableExists))
+	suite.False(tableExists)
+
+	suite.Require().NoError(tracker.EnsureTable(context.Background()))
+	matches, err = fingerprintTable.MatchesLatest(context.Background(), opts...)
+	suite.Require().NoError(err)
+	suite.False(matches, "no fingerprint has been recorded")
+
+	fingerprint, err := schema.GetSchemaFingerprint(context.Background(), connPool, opts...)
+	suite.Require().NoError(err)
+	suite.Require().NoError(tracker.RecordMigration(context.Background(), diff.Plan{}, fingerprint, "some-user", diff.WithMigrationVersion("v1")))
+	matches, err = fingerprintTable.MatchesLatest(context.Background(), opts...)
+	suite.Require().NoError(err)
+	suite.True(matches)
+
+	// Drift the schema
+	_, err = connPool.ExecContext(context.Background(), `ALTER TABLE foo ADD COLUMN bar TEXT`)
+	suite.Require().NoError(err)
+	matches, err = fingerprintTable.MatchesLatest(context.Background(), opts...)
+	suite.Require().NoError(err)
+	suite.False(matches)
+
+	newFingerprint, err := schema.GetSchemaFingerprint(context.Background(), connPool, opts...)
+	suite.Require().NoError(err)
+	suite.NotEqual(fingerprint, newFingerprint)
+	suite.Require().NoError(tracker.RecordMigration(context.Background(), diff.Plan{}, newFingerprint, "some-user", diff.WithMigrationVersion("v2")))
+	latestFingerprint, ok, err := fingerprintTable.LatestFingerprint(context.Background())
+	suite.Require().NoError(err)
+	suite.True(ok)
+	suite.Equal(newFingerprint, latestFingerprint)
+	matches, err = fingerprintTable.MatchesLatest(context.Background(), opts...)
+	suite.Require().NoError(err)
+	suite.True(matches)
+
+	// The latest fingerprint is the one recorded with the latest migration
+	history, err := tracker.GetHistory(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Len(history, 2)
+	suite.Equal(latestFingerprint, history[1].Fingerprint)
+}
+
 func TestSchemaTestSuite(t *testing.T) {
 	suite.Run(t, new(schemaTestSuite))
 }